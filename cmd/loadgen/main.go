@@ -0,0 +1,232 @@
+// Command loadgen drives load against a running instance and reports
+// latency percentiles per operation, so performance changes are
+// measurable. Results can be saved as JSON and compared with a baseline:
+//
+//	loadgen -url http://localhost:8080 -email alice@example.com -password secret -out base.json
+//	loadgen -url http://localhost:8080 -email alice@example.com -password secret -baseline base.json
+//
+// With -baseline, loadgen exits non-zero when any operation's p99 regresses
+// by more than -max-regression.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/pkg/client"
+)
+
+// Operations exercised by each worker iteration.
+const (
+	opLogin  = "login"
+	opMe     = "token_validate"
+	opCreate = "review_create"
+	opGet    = "review_get"
+	opUpdate = "review_update"
+	opList   = "review_list"
+)
+
+var operations = []string{opLogin, opMe, opCreate, opGet, opUpdate, opList}
+
+func main() {
+	var (
+		baseURL       = flag.String("url", "http://localhost:8080", "server base URL")
+		email         = flag.String("email", os.Getenv("AUREA_EMAIL"), "login email (a reviewer or admin)")
+		password      = flag.String("password", os.Getenv("AUREA_PASSWORD"), "login password")
+		workers       = flag.Int("workers", 8, "concurrent workers")
+		duration      = flag.Duration("duration", 30*time.Second, "how long to generate load")
+		loginEvery    = flag.Int("login-every", 20, "log in again every N iterations per worker")
+		out           = flag.String("out", "", "write results as JSON to this file")
+		baseline      = flag.String("baseline", "", "compare with results previously written with -out")
+		maxRegression = flag.Float64("max-regression", 0.10, "allowed p99 increase over the baseline, as a fraction")
+	)
+	flag.Parse()
+	if *email == "" || *password == "" {
+		log.Fatal("-email and -password (or AUREA_EMAIL and AUREA_PASSWORD) are required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *duration)
+	defer cancel()
+
+	rec := newRecorder()
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			run(ctx, rec, *baseURL, *email, *password, worker, *loginEvery)
+		}(i)
+	}
+	wg.Wait()
+
+	results := rec.results(time.Since(start))
+	printResults(results)
+	if *out != "" {
+		if err := writeResults(*out, results); err != nil {
+			log.Fatalf("Failed to write results: %v", err)
+		}
+	}
+	if *baseline != "" {
+		regressed, err := compare(*baseline, results, *maxRegression)
+		if err != nil {
+			log.Fatalf("Failed to compare with baseline: %v", err)
+		}
+		if regressed {
+			os.Exit(1)
+		}
+	}
+}
+
+// run loops through the scenario until ctx is done: log in, validate the
+// token, then create, read, update and list reviews.
+func run(ctx context.Context, rec *recorder, baseURL, email, password string, worker, loginEvery int) {
+	c := client.New(baseURL, client.WithMaxRetries(0))
+	for i := 0; ctx.Err() == nil; i++ {
+		if i%loginEvery == 0 {
+			if !rec.time(ctx, opLogin, func() error { return c.Login(ctx, email, password) }) {
+				time.Sleep(time.Second)
+				continue
+			}
+		}
+		rec.time(ctx, opMe, func() error { _, err := c.Me(ctx); return err })
+
+		var review *client.Review
+		ok := rec.time(ctx, opCreate, func() error {
+			var err error
+			review, err = c.CreateReview(ctx, client.ReviewInput{
+				Title:   fmt.Sprintf("loadgen %d-%d", worker, i),
+				Content: "Generated by loadgen.",
+			})
+			return err
+		})
+		if ok {
+			rec.time(ctx, opGet, func() error { _, err := c.GetReview(ctx, review.ID); return err })
+			rec.time(ctx, opUpdate, func() error {
+				_, err := c.UpdateReview(ctx, review.ID, client.ReviewInput{Title: review.Title, Content: fmt.Sprintf("Updated %d.", rand.Int())})
+				return err
+			})
+		}
+		rec.time(ctx, opList, func() error { _, err := c.ListReviews(ctx, client.ListOptions{PageSize: 20}); return err })
+	}
+}
+
+// recorder collects latencies per operation.
+type recorder struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+}
+
+func newRecorder() *recorder {
+	return &recorder{latencies: make(map[string][]time.Duration), errors: make(map[string]int)}
+}
+
+// time runs fn and records its latency, or an error. Calls cut short by the
+// end of the run are not counted.
+func (r *recorder) time(ctx context.Context, op string, fn func() error) bool {
+	start := time.Now()
+	err := fn()
+	elapsed := time.Since(start)
+	if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.errors[op]++
+		return false
+	}
+	r.latencies[op] = append(r.latencies[op], elapsed)
+	return true
+}
+
+// Result summarizes one operation.
+type Result struct {
+	Operation  string  `json:"operation"`
+	Requests   int     `json:"requests"`
+	Errors     int     `json:"errors"`
+	PerSecond  float64 `json:"per_second"`
+	P50Millis  float64 `json:"p50_ms"`
+	P90Millis  float64 `json:"p90_ms"`
+	P99Millis  float64 `json:"p99_ms"`
+	MaxMillis  float64 `json:"max_ms"`
+	MeanMillis float64 `json:"mean_ms"`
+}
+
+func (r *recorder) results(elapsed time.Duration) []Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Result
+	for _, op := range operations {
+		samples := r.latencies[op]
+		res := Result{Operation: op, Requests: len(samples), Errors: r.errors[op]}
+		if len(samples) > 0 {
+			s := summarize(samples)
+			res.PerSecond = float64(len(samples)) / elapsed.Seconds()
+			res.P50Millis, res.P90Millis, res.P99Millis = millis(s.p50), millis(s.p90), millis(s.p99)
+			res.MaxMillis, res.MeanMillis = millis(s.max), millis(s.mean)
+		}
+		out = append(out, res)
+	}
+	return out
+}
+
+func printResults(results []Result) {
+	fmt.Printf("%-16s %9s %7s %9s %9s %9s %9s %9s\n", "operation", "requests", "errors", "req/s", "p50 ms", "p90 ms", "p99 ms", "max ms")
+	for _, r := range results {
+		fmt.Printf("%-16s %9d %7d %9.1f %9.2f %9.2f %9.2f %9.2f\n",
+			r.Operation, r.Requests, r.Errors, r.PerSecond, r.P50Millis, r.P90Millis, r.P99Millis, r.MaxMillis)
+	}
+}
+
+func writeResults(path string, results []Result) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// compare reports each operation whose p99 exceeds the baseline's by more
+// than maxRegression, and whether any did.
+func compare(path string, results []Result, maxRegression float64) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	var base []Result
+	if err := json.Unmarshal(data, &base); err != nil {
+		return false, err
+	}
+	before := make(map[string]Result)
+	for _, r := range base {
+		before[r.Operation] = r
+	}
+
+	regressed := false
+	for _, r := range results {
+		b, ok := before[r.Operation]
+		if !ok || b.P99Millis == 0 || r.Requests == 0 {
+			continue
+		}
+		change := (r.P99Millis - b.P99Millis) / b.P99Millis
+		status := "ok"
+		if change > maxRegression {
+			status, regressed = "REGRESSED", true
+		}
+		fmt.Printf("%-16s p99 %8.2f ms -> %8.2f ms (%+6.1f%%) %s\n", r.Operation, b.P99Millis, r.P99Millis, change*100, status)
+	}
+	return regressed, nil
+}
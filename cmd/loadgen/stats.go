@@ -0,0 +1,43 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// summary holds latency percentiles of a sample.
+type summary struct {
+	p50, p90, p99, max, mean time.Duration
+}
+
+func summarize(samples []time.Duration) summary {
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	return summary{
+		p50:  percentile(sorted, 0.50),
+		p90:  percentile(sorted, 0.90),
+		p99:  percentile(sorted, 0.99),
+		max:  sorted[len(sorted)-1],
+		mean: total / time.Duration(len(sorted)),
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.999999) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
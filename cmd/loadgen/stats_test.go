@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	var hundred []time.Duration
+	for i := 1; i <= 100; i++ {
+		hundred = append(hundred, time.Duration(i)*time.Millisecond)
+	}
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{"single sample", []time.Duration{time.Second}, 0.99, time.Second},
+		{"p50 of 100", hundred, 0.50, 50 * time.Millisecond},
+		{"p99 of 100", hundred, 0.99, 99 * time.Millisecond},
+		{"p100 of 100", hundred, 1, 100 * time.Millisecond},
+		{"p0", hundred, 0, time.Millisecond},
+		{"p90 of 3 rounds up", []time.Duration{1, 2, 3}, 0.90, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.sorted, tt.p); got != tt.want {
+				t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeUnsorted(t *testing.T) {
+	s := summarize([]time.Duration{30, 10, 20})
+	if s.p50 != 20 || s.max != 30 || s.mean != 20 {
+		t.Errorf("summarize = %+v", s)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/operator"
+	"github.com/andres20980/aurea-orchestrator/pkg/client"
+)
+
+func main() {
+	// Load configuration from environment
+	baseURL := os.Getenv("AUREA_URL")
+	if baseURL == "" {
+		baseURL = "http://aurea-api.aurea-orchestrator.svc:8080"
+	}
+	interval := 30 * time.Second
+	if v := os.Getenv("RECONCILE_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("Invalid RECONCILE_INTERVAL: %v", err)
+		}
+		interval = d
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The controller acts as an instance operator to manage organizations
+	api := client.New(baseURL, client.WithToken(os.Getenv("AUREA_TOKEN")))
+	if email := os.Getenv("AUREA_EMAIL"); email != "" {
+		if err := api.Login(ctx, email, os.Getenv("AUREA_PASSWORD")); err != nil {
+			log.Fatalf("Failed to log in to %s: %v", baseURL, err)
+		}
+	}
+
+	kube, err := operator.InCluster()
+	if err != nil {
+		log.Fatalf("Failed to configure Kubernetes client: %v", err)
+	}
+
+	log.Printf("Operator reconciling AureaOrg and AureaWebhook resources every %s", interval)
+	operator.New(kube, api, baseURL, interval).Run(ctx)
+}
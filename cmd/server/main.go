@@ -1,18 +1,91 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"expvar"
+	"flag"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/andres20980/aurea-orchestrator/internal/accesslog"
+	"github.com/andres20980/aurea-orchestrator/internal/accessreview"
+	"github.com/andres20980/aurea-orchestrator/internal/ai"
+	"github.com/andres20980/aurea-orchestrator/internal/assign"
 	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/biexport"
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+	"github.com/andres20980/aurea-orchestrator/internal/compliance"
+	"github.com/andres20980/aurea-orchestrator/internal/deadletter"
+	"github.com/andres20980/aurea-orchestrator/internal/debugsrv"
+	"github.com/andres20980/aurea-orchestrator/internal/egress"
+	"github.com/andres20980/aurea-orchestrator/internal/emailapproval"
+	"github.com/andres20980/aurea-orchestrator/internal/envelope"
+	"github.com/andres20980/aurea-orchestrator/internal/escalation"
 	"github.com/andres20980/aurea-orchestrator/internal/handlers"
+	"github.com/andres20980/aurea-orchestrator/internal/httpclient"
+	"github.com/andres20980/aurea-orchestrator/internal/integrations/jira"
+	"github.com/andres20980/aurea-orchestrator/internal/llm"
+	"github.com/andres20980/aurea-orchestrator/internal/logging"
+	"github.com/andres20980/aurea-orchestrator/internal/mail"
+	"github.com/andres20980/aurea-orchestrator/internal/metrics"
 	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/operations"
+	"github.com/andres20980/aurea-orchestrator/internal/orgdeletion"
+	"github.com/andres20980/aurea-orchestrator/internal/plugins"
+	"github.com/andres20980/aurea-orchestrator/internal/push"
+	"github.com/andres20980/aurea-orchestrator/internal/replication"
+	"github.com/andres20980/aurea-orchestrator/internal/retention"
+	"github.com/andres20980/aurea-orchestrator/internal/scan"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/seed"
+	"github.com/andres20980/aurea-orchestrator/internal/sharelink"
+	"github.com/andres20980/aurea-orchestrator/internal/signing"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/templates"
+	"github.com/andres20980/aurea-orchestrator/internal/tokenbind"
+	"github.com/andres20980/aurea-orchestrator/internal/ui"
+	"github.com/andres20980/aurea-orchestrator/internal/webhooks"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
 	"github.com/gorilla/mux"
 )
 
 func main() {
+	// "server seed --profile demo" starts the server preloaded with sample
+	// organizations, users and reviews
+	var seedProfile, seedPassword string
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		fs := flag.NewFlagSet("seed", flag.ExitOnError)
+		fs.StringVar(&seedProfile, "profile", "demo", "seed profile: "+strings.Join(seed.Profiles(), ", "))
+		fs.StringVar(&seedPassword, "password", "aurea-demo", "password of every seeded user")
+		fs.Parse(os.Args[2:])
+	}
+
+	// Application logs go to stderr at LOG_LEVEL (default info) in
+	// LOG_FORMAT (text or json); operators can change the level and enable
+	// targeted debug logging at /api/admin/logging
+	logLevel := slog.LevelInfo
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		var err error
+		if logLevel, err = logging.ParseLevel(v); err != nil {
+			log.Fatalf("Invalid LOG_LEVEL %q", v)
+		}
+	}
+	logControl, err := logging.Setup(os.Stderr, os.Getenv("LOG_FORMAT"), logLevel)
+	if err != nil {
+		log.Fatalf("Invalid logging configuration: %v", err)
+	}
+
 	// Load configuration from environment
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
@@ -29,33 +102,882 @@ func main() {
 		log.Fatalf("Invalid TOKEN_TTL format: %v", err)
 	}
 
+	// Approval proofs are signed with APPROVAL_SIGNING_KEY (base64 Ed25519 seed)
+	var signer *signing.Signer
+	if seed := os.Getenv("APPROVAL_SIGNING_KEY"); seed != "" {
+		signer, err = signing.NewSigner(seed)
+	} else {
+		log.Println("APPROVAL_SIGNING_KEY not set; using an ephemeral approval signing key")
+		signer, err = signing.NewEphemeralSigner()
+	}
+	if err != nil {
+		log.Fatalf("Invalid APPROVAL_SIGNING_KEY: %v", err)
+	}
+
 	// Initialize services
 	authService := auth.NewService(jwtSecret, ttl)
-	
+	dataStore := store.NewMemory()
+	// Review content and comments are encrypted at rest when
+	// ENCRYPTION_MASTER_KEYS is set ("id:base64key,..."; the first is active)
+	if masterKeys := os.Getenv("ENCRYPTION_MASTER_KEYS"); masterKeys != "" {
+		keys, err := envelope.ParseLocalKeys(masterKeys)
+		if err != nil {
+			log.Fatalf("Invalid ENCRYPTION_MASTER_KEYS: %v", err)
+		}
+		dataStore.SetFieldCipher(envelope.New(keys))
+	}
+	if seedProfile != "" {
+		accounts, err := seed.Load(dataStore, seedProfile, seedPassword)
+		if err != nil {
+			log.Fatalf("Failed to seed: %v", err)
+		}
+		for _, a := range accounts {
+			log.Printf("Seeded %s (%s, org %s) with password %q", a.Email, a.Role, a.OrgID, seedPassword)
+		}
+	}
+	mailer := mail.LogMailer{}
+	assigner := assign.NewEngine(dataStore)
+
+	baseURL := os.Getenv("BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	// Outbound calls honor HTTPS_PROXY and the operator's egress policy.
+	// Internal addresses (loopback, link-local, private) are refused at
+	// dial time unless EGRESS_ALLOW_NETWORKS lists them, e.g. for a local
+	// model server or an internal Jira
+	egressPolicy, err := egress.FromEnv()
+	if err != nil {
+		log.Fatalf("Invalid egress configuration: %v", err)
+	}
+	outbound := egressPolicy.Client()
+	// URLs org admins enter reach internal networks only where the
+	// destination's own variable (comma-separated CIDRs) allows:
+	// EGRESS_ALLOW_NETWORKS opens networks to the instance's integrations,
+	// not to URLs org admins enter
+	orgPolicy := func(env string) egress.Policy {
+		policy := egressPolicy
+		if policy.AllowNetworks, err = egress.ParseNetworks(os.Getenv(env)); err != nil {
+			log.Fatalf("Invalid %s: %v", env, err)
+		}
+		return policy
+	}
+	// PagerDuty and Opsgenie deduplicate on the incident key, so POSTs are safe to retry.
+	pagingHTTP := httpclient.New("paging", outbound, httpclient.Options{RetryUnsafe: true}).HTTP()
+	// Organizations' Jira sites, webhook endpoints and BI destinations are
+	// allowed internal networks by JIRA_ALLOW_NETWORKS,
+	// WEBHOOK_ALLOW_NETWORKS and BI_EXPORT_ALLOW_NETWORKS
+	orgJiraHTTP := httpclient.New("jira-org", orgPolicy("JIRA_ALLOW_NETWORKS").Client(), httpclient.Options{}).HTTP()
+	webhookHTTP := httpclient.New("webhooks", orgPolicy("WEBHOOK_ALLOW_NETWORKS").Client(), httpclient.Options{}).HTTP()
+	// A day's BI export is written to a fixed key or posted with its date, so retries are safe.
+	biHTTP := httpclient.New("bi-export", orgPolicy("BI_EXPORT_ALLOW_NETWORKS").Client(), httpclient.Options{RetryUnsafe: true}).HTTP()
+
+	// Jira status sync is enabled when JIRA_BASE_URL is set
+	var jiraClient *jira.Client
+	if jiraURL := os.Getenv("JIRA_BASE_URL"); jiraURL != "" {
+		jiraHTTP := httpclient.New("jira", outbound, httpclient.Options{}).HTTP()
+		jiraClient = jira.NewClient(jiraURL, os.Getenv("JIRA_EMAIL"), os.Getenv("JIRA_API_TOKEN"), jiraHTTP)
+	}
+
+	// Events go through a journaled outbox so none are lost across restarts
+	outboxJournal := os.Getenv("OUTBOX_JOURNAL")
+	if outboxJournal == "" {
+		outboxJournal = "data/outbox.jsonl"
+	}
+	if err := dataStore.OpenOutboxJournal(outboxJournal); err != nil {
+		log.Fatalf("Failed to open outbox journal: %v", err)
+	}
+	// Push notifications go to devices registered at /api/me/devices through
+	// FCM (FCM_CREDENTIALS_FILE, a service account key) and/or APNs
+	// (APNS_KEY_FILE, APNS_KEY_ID, APNS_TEAM_ID, APNS_TOPIC; APNS_SANDBOX=true
+	// for development builds)
+	pushHTTP := httpclient.New("push", outbound, httpclient.Options{}).HTTP()
+	pushSenders := map[string]push.Sender{}
+	if path := os.Getenv("FCM_CREDENTIALS_FILE"); path != "" {
+		credentials, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Failed to read FCM credentials: %v", err)
+		}
+		if pushSenders[models.PlatformFCM], err = push.NewFCM(credentials, pushHTTP); err != nil {
+			log.Fatalf("Invalid FCM credentials: %v", err)
+		}
+	}
+	if path := os.Getenv("APNS_KEY_FILE"); path != "" {
+		p8, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Failed to read APNs key: %v", err)
+		}
+		pushSenders[models.PlatformAPNs], err = push.NewAPNs(p8, os.Getenv("APNS_KEY_ID"), os.Getenv("APNS_TEAM_ID"), os.Getenv("APNS_TOPIC"), os.Getenv("APNS_SANDBOX") == "true", pushHTTP)
+		if err != nil {
+			log.Fatalf("Invalid APNs configuration: %v", err)
+		}
+	}
+	pushGateway := push.NewGateway(dataStore, pushSenders)
+	dispatcher := notify.NewDispatcher(dataStore, notify.NewEmailChannel(dataStore, mailer, baseURL), pushGateway)
+	service := aurea.NewWithStore(dataStore, dispatcher)
+
+	// Compiled-in plugins are listed in PLUGINS_ENABLED; operators can
+	// toggle them at /api/admin/plugins
+	pluginTimeout := plugins.DefaultTimeout
+	if v := os.Getenv("PLUGIN_TIMEOUT"); v != "" {
+		if pluginTimeout, err = time.ParseDuration(v); err != nil {
+			log.Fatalf("Invalid PLUGIN_TIMEOUT: %v", err)
+		}
+	}
+	pluginManager := plugins.NewManager(dataStore, dispatcher, strings.Split(os.Getenv("PLUGINS_ENABLED"), ","), pluginTimeout)
+	service.OnBeforeCreate(pluginManager.BeforeCreateReview)
+	service.UseApprovalSigner(signer)
+	reviews := service.Reviews()
+	jiraConnector := jira.NewConnector(dataStore, dispatcher, orgJiraHTTP)
+
+	// Webhook deliveries are kept for WEBHOOK_DELIVERY_RETENTION (default
+	// 7 days) so consumers can redeliver them after an outage
+	deliveryRetention := webhooks.DefaultRetention
+	if v := os.Getenv("WEBHOOK_DELIVERY_RETENTION"); v != "" {
+		if deliveryRetention, err = time.ParseDuration(v); err != nil {
+			log.Fatalf("Invalid WEBHOOK_DELIVERY_RETENTION: %v", err)
+		}
+	}
+	deliverer := webhooks.NewDeliverer(dataStore, dispatcher, webhookHTTP, baseURL, deliveryRetention)
+
+	// Failed webhook deliveries, emails and pushes are dead-lettered for
+	// org admins and operators to retry or purge
+	deadLetters := deadletter.New(dataStore)
+	deliverer.UseDeadLetters(deadLetters)
+	dispatcher.UseDeadLetters(deadLetters)
+
+	// Cross-region replication: the primary serves its change log and
+	// read-only replicas tail it
+	instanceID := os.Getenv("INSTANCE_ID")
+	if instanceID == "" {
+		instanceID, _ = os.Hostname()
+	}
+	replicationMode := os.Getenv("REPLICATION_MODE")
+	replicationToken := os.Getenv("REPLICATION_TOKEN")
+	replicationStatus := func() replication.Status {
+		return replication.Status{InstanceID: instanceID, Conflicts: []replication.Conflict{}}
+	}
+	var primary *replication.Primary
+	switch replicationMode {
+	case "":
+	case replication.ModePrimary:
+		primary = replication.NewPrimary(dataStore, instanceID)
+		go primary.Run(context.Background())
+		replicationStatus = primary.Status
+	case replication.ModeReplica:
+		replica := replication.NewReplica(dataStore, instanceID, os.Getenv("REPLICATION_PRIMARY_URL"), replicationToken)
+		go replica.Run(context.Background())
+		replicationStatus = replica.Status
+	default:
+		log.Fatalf("Invalid REPLICATION_MODE %q", replicationMode)
+	}
+
+	// Page the on-call for critical reviews that breach their SLA, and push
+	// each day's review events to organizations' BI destinations. Replicas
+	// leave these jobs to the primary so nothing is done twice.
+	biExporter := biexport.New(dataStore, dispatcher, biHTTP, time.Hour)
+	if replicationMode != replication.ModeReplica {
+		escalator := escalation.New(dataStore, dispatcher, pagingHTTP, time.Minute)
+		go escalator.Run(context.Background())
+		go biExporter.Run(context.Background())
+		// Close access review campaigns past their deadline, downgrading
+		// members nobody re-certified
+		go accessreview.New(dataStore, 10*time.Minute).Run(context.Background())
+		// Notify or reassign reviews that stall, per organization rules
+		go escalation.NewRules(dataStore, assigner, time.Minute).Run(context.Background())
+	}
+
+	// All listeners are registered; start relaying outbox events
+	go dispatcher.Run(context.Background())
+
+	blobDir := os.Getenv("BLOB_DIR")
+	if blobDir == "" {
+		blobDir = "data/blobs"
+	}
+	blobs, err := blob.NewFS(blobDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize blob storage: %v", err)
+	}
+
+	// Attachments are scanned for malware by the ClamAV daemon at
+	// CLAMAV_ADDR (host:port or a Unix socket path) or the scanning API at
+	// SCAN_API_URL (authenticated with SCAN_API_TOKEN). Without either they
+	// are stored unscanned.
+	var scanner scan.Scanner
+	switch {
+	case os.Getenv("CLAMAV_ADDR") != "":
+		scanner = scan.NewClamAV(os.Getenv("CLAMAV_ADDR"))
+	case os.Getenv("SCAN_API_URL") != "":
+		scanHTTP := httpclient.New("attachment-scan", outbound, httpclient.Options{}).HTTP()
+		scanner = scan.NewAPI(scanHTTP, os.Getenv("SCAN_API_URL"), os.Getenv("SCAN_API_TOKEN"))
+	}
+	attachmentScans := scan.NewService(dataStore, blobs, scanner)
+	if replicationMode != replication.ModeReplica {
+		go attachmentScans.Run(context.Background())
+	}
+
+	// Long requests answered with 202 run as operations; finished ones
+	// are kept for a day
+	operationRunner := operations.NewRunner(dataStore, blobs)
+	complianceReports := compliance.New(dataStore, blobs, time.Hour)
+	if replicationMode != replication.ModeReplica {
+		// Generate the evidence bundle of each quarter once it ends
+		go complianceReports.Run(context.Background())
+		// Apply organizations' retention policies
+		go retention.New(dataStore, time.Hour).Run(context.Background())
+		// Delete organizations once their deletion grace period ends
+		go orgdeletion.New(dataStore, blobs, time.Hour).Run(context.Background())
+	}
+	go operationRunner.Run(context.Background())
+
+	// AI features use the providers each organization configures, then
+	// the instance's: those with an API key set in OPENAI_API_KEY or
+	// ANTHROPIC_API_KEY, and a self-hosted server with an OpenAI-compatible
+	// API (vLLM, Ollama at http://host:11434/v1) at LOCAL_LLM_BASE_URL
+	// serving LOCAL_LLM_MODEL. *_BASE_URL and *_MODEL override their
+	// endpoint and default model. LLM_PROVIDERS lists those to use in
+	// failover order (default openai,anthropic,local); unhealthy providers
+	// are tried last.
+	// Model calls are slow: raise their EGRESS_TIMEOUTS, e.g.
+	// api.openai.com=2m
+	llmHTTP := httpclient.New("llm", outbound, httpclient.Options{}).HTTP()
+	instanceLLMs := make(map[string]llm.Provider)
+	if key := os.Getenv("OPENAI_API_KEY"); key != "" {
+		instanceLLMs["openai"] = llm.NewOpenAI(llmHTTP, os.Getenv("OPENAI_BASE_URL"), key, os.Getenv("OPENAI_MODEL"))
+	}
+	if key := os.Getenv("ANTHROPIC_API_KEY"); key != "" {
+		instanceLLMs["anthropic"] = llm.NewAnthropic(llmHTTP, os.Getenv("ANTHROPIC_BASE_URL"), key, os.Getenv("ANTHROPIC_MODEL"))
+	}
+	if baseURL := os.Getenv("LOCAL_LLM_BASE_URL"); baseURL != "" {
+		model := os.Getenv("LOCAL_LLM_MODEL")
+		if model == "" {
+			log.Fatal("LOCAL_LLM_BASE_URL needs LOCAL_LLM_MODEL")
+		}
+		instanceLLMs["local"] = llm.NewLocal(llmHTTP, baseURL, os.Getenv("LOCAL_LLM_API_KEY"), model)
+	}
+	llmOrder := os.Getenv("LLM_PROVIDERS")
+	if llmOrder == "" {
+		llmOrder = "openai,anthropic,local"
+	}
+	llmProviders := &llm.Registry{}
+	for _, name := range strings.Split(llmOrder, ",") {
+		name = strings.TrimSpace(name)
+		if p, ok := instanceLLMs[name]; ok {
+			llmProviders.Add(p)
+			delete(instanceLLMs, name)
+		}
+	}
+	// Organizations' own providers are allowed internal networks by
+	// LLM_ALLOW_NETWORKS
+	orgLLMPolicy := orgPolicy("LLM_ALLOW_NETWORKS")
+	orgLLMHTTP := httpclient.New("llm-org", orgLLMPolicy.Client(), httpclient.Options{}).HTTP()
+	aiProviders := ai.NewProviders(dataStore, orgLLMHTTP, llmProviders)
+	go aiProviders.Run(context.Background(), time.Minute)
+	// New reviews get AI suggestions of reviewers and labels
+	suggester := ai.NewSuggester(dataStore, dispatcher, operationRunner, aiProviders, assigner)
+
+	// pprof, expvar and profile snapshots are served on DEBUG_ADDR (keep it
+	// private, e.g. 127.0.0.1:6060) to holders of DEBUG_TOKEN
+	if debugAddr := os.Getenv("DEBUG_ADDR"); debugAddr != "" {
+		debugToken := os.Getenv("DEBUG_TOKEN")
+		if debugToken == "" {
+			log.Fatal("DEBUG_TOKEN is required when DEBUG_ADDR is set")
+		}
+		go func() {
+			log.Printf("Debug server listening on %s", debugAddr)
+			if err := http.ListenAndServe(debugAddr, debugsrv.Handler(debugToken, blobs)); err != nil {
+				log.Printf("Debug server stopped: %v", err)
+			}
+		}()
+	}
+
+	// Data residency: REGION names this deployment and REGIONS lists every
+	// regional deployment ("eu=https://eu.example.com,us=..."). Requests for
+	// an organization pinned to another region are forwarded there.
+	localRegion := os.Getenv("REGION")
+	regionURLs, err := middleware.ParseRegions(os.Getenv("REGIONS"))
+	if err != nil {
+		log.Fatalf("Invalid REGIONS: %v", err)
+	}
+	var regionNames []string
+	for name := range regionURLs {
+		regionNames = append(regionNames, name)
+	}
+	if localRegion != "" && regionURLs[localRegion] == nil {
+		regionNames = append(regionNames, localRegion)
+	}
+
+	// Flag logins from new devices or impossible locations. Behind proxies
+	// listed in TRUSTED_PROXIES (comma-separated CIDRs; TRUST_PROXY=true
+	// trusts loopback and private networks) the client IP is the last
+	// X-Forwarded-For hop they did not add, and Cloudflare geolocation
+	// headers are read; LOGIN_REVERIFY=true holds suspicious sessions until
+	// the user confirms an emailed code.
+	trustedProxies, err := security.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	if trustedProxies == nil && os.Getenv("TRUST_PROXY") == "true" {
+		trustedProxies = security.PrivateProxies
+	}
+	var locator security.Locator
+	if trustedProxies != nil {
+		locator = security.CloudflareLocator
+	}
+	loginMonitor := security.NewDetector(dataStore, mailer, locator, trustedProxies, os.Getenv("LOGIN_REVERIFY") == "true")
+
+	// After LOGIN_CHALLENGE_AFTER failed logins from an IP within 15 minutes,
+	// require a CAPTCHA_PROVIDER (hcaptcha or turnstile) challenge
+	challengeAfter := 5
+	if v := os.Getenv("LOGIN_CHALLENGE_AFTER"); v != "" {
+		if challengeAfter, err = strconv.Atoi(v); err != nil || challengeAfter < 1 {
+			log.Fatalf("Invalid LOGIN_CHALLENGE_AFTER %q", v)
+		}
+	}
+	var challenger security.Challenger
+	if provider := os.Getenv("CAPTCHA_PROVIDER"); provider != "" {
+		captchaHTTP := httpclient.New("captcha", outbound, httpclient.Options{}).HTTP()
+		if challenger, err = security.ChallengerFor(provider, os.Getenv("CAPTCHA_SECRET"), captchaHTTP); err != nil {
+			log.Fatalf("Invalid CAPTCHA_PROVIDER: %v", err)
+		}
+	}
+	// Public intake forms accept 5 submissions per IP per form each hour
+	intakeLimiter := security.NewRateLimiter(5, time.Hour)
+	// Sign-ups by verified email domain: 10 per IP each hour
+	signupLimiter := security.NewRateLimiter(10, time.Hour)
+	// Organizations whose password policy sets check_breached refuse
+	// passwords found by Have I Been Pwned's k-anonymity range API
+	breaches := security.NewPwnedPasswords(httpclient.New("pwned-passwords", outbound, httpclient.Options{}).HTTP())
+	// Templates are imported from bundles at a URL or in the catalog at
+	// TEMPLATE_CATALOG_URL; signatures must come from TEMPLATE_TRUSTED_KEYS.
+	// Bundle URLs come from org admins, so bundles and the catalog reach
+	// internal networks only where TEMPLATE_ALLOW_NETWORKS allows
+	templateImporter, err := templates.NewImporter(
+		httpclient.New("templates", orgPolicy("TEMPLATE_ALLOW_NETWORKS").Client(), httpclient.Options{}).HTTP(),
+		os.Getenv("TEMPLATE_CATALOG_URL"),
+		strings.Split(os.Getenv("TEMPLATE_TRUSTED_KEYS"), ","))
+	if err != nil {
+		log.Fatalf("Invalid TEMPLATE_TRUSTED_KEYS: %v", err)
+	}
+	// Password reset links are signed and single-use; each email address
+	// may ask for 3 per hour and each client IP for 10. They are mailed in
+	// the background so the answer takes as long whether or not the
+	// account exists
+	linkTokens := security.NewTokenSigner(jwtSecret)
+	forgotLimiter := security.NewRateLimiter(3, time.Hour)
+	forgotIPLimiter := security.NewRateLimiter(10, time.Hour)
+	// New accounts and changed email addresses are confirmed by emailed
+	// links valid for EMAIL_VERIFICATION_TTL (default 48h)
+	verificationTTL := 48 * time.Hour
+	if v := os.Getenv("EMAIL_VERIFICATION_TTL"); v != "" {
+		if verificationTTL, err = time.ParseDuration(v); err != nil || verificationTTL <= 0 {
+			log.Fatalf("Invalid EMAIL_VERIFICATION_TTL %q", v)
+		}
+	}
+	emailVerifier := &handlers.EmailVerifier{
+		Store:   dataStore,
+		Mailer:  mailer,
+		Signer:  linkTokens,
+		Issuer:  authService,
+		Limiter: security.NewRateLimiter(3, time.Hour),
+		BaseURL: baseURL,
+		TTL:     verificationTTL,
+		Proxies: trustedProxies,
+	}
+	loginGuard := security.NewLoginGuard(challenger, os.Getenv("CAPTCHA_SITE_KEY"), challengeAfter, 15*time.Minute)
+
+	// Instance operators are identified by role or by the OPERATOR_EMAILS allowlist
+	operators := strings.Split(os.Getenv("OPERATOR_EMAILS"), ",")
+
+	// Kill switches disable a capability during an incident, instance-wide
+	// or per org. KILL_SWITCHES engages some at startup, e.g.
+	// "webhooks,intake:org-1"; the admin APIs engage and lift them at runtime
+	for _, entry := range strings.Split(os.Getenv("KILL_SWITCHES"), ",") {
+		capability, orgID, _ := strings.Cut(strings.TrimSpace(entry), ":")
+		if capability == "" {
+			continue
+		}
+		if !slices.Contains(models.Capabilities, capability) {
+			log.Fatalf("Invalid KILL_SWITCHES: unknown capability %q", capability)
+		}
+		dataStore.SetKillSwitch(models.KillSwitch{Capability: capability, OrgID: orgID, Reason: "disabled by configuration", DisabledBy: "config", DisabledAt: time.Now()})
+	}
+	killSwitch := func(capability string) func(http.HandlerFunc) http.HandlerFunc {
+		return middleware.Capability(dataStore, capability, nil)
+	}
+	intakeSwitch := middleware.Capability(dataStore, models.CapabilityIntake, func(r *http.Request) string {
+		if form, err := dataStore.FindIntakeForm(middleware.HashAPIKey(mux.Vars(r)["token"])); err == nil {
+			return form.OrgID
+		}
+		return ""
+	})
+
+	// Share links give people without an account time-limited read access
+	// to one review; tokens are signed with the JWT secret
+	shareLinks := sharelink.NewSigner(jwtSecret)
+
+	// Organizations can bind login tokens to the client's TLS certificate
+	// or DPoP key; DPoP proofs are checked against replay
+	dpopProofs := tokenbind.NewVerifier()
+
 	// Setup router
 	r := mux.NewRouter()
 
 	// Public endpoints
-	r.HandleFunc("/login", handlers.Login(authService)).Methods("POST")
+	r.HandleFunc("/healthz", handlers.Health).Methods("GET")
+	// Login wrappers, innermost first: bind the token to the client, hold
+	// it back for a forced password change or an unverified email, start
+	// the session, then run anomaly detection and brute-force protection
+	login := handlers.BindLogin(dataStore, dpopProofs, jwtSecret)(handlers.Login(dataStore, authService))
+	login = handlers.RequirePasswordChange(dataStore, jwtSecret)(login)
+	login = emailVerifier.RequireVerified(jwtSecret)(login)
+	login = handlers.TrackSession(dataStore, jwtSecret, trustedProxies)(login)
+	login = handlers.ObserveLogin(loginMonitor, dataStore, jwtSecret)(login)
+	r.HandleFunc("/login", handlers.GuardLogin(loginGuard, trustedProxies)(login)).Methods("POST")
+	r.HandleFunc("/login/verify", handlers.VerifyLogin(loginMonitor)).Methods("POST")
+	r.HandleFunc("/login/change-password", handlers.CompletePasswordChange(dataStore, breaches)).Methods("POST")
+	r.HandleFunc("/auth/forgot-password", handlers.ForgotPassword(dataStore, mail.Async(mailer), linkTokens, forgotLimiter, forgotIPLimiter, trustedProxies, baseURL)).Methods("POST")
+	r.HandleFunc("/auth/reset-password", handlers.ResetPassword(dataStore, linkTokens, breaches)).Methods("POST")
+	r.HandleFunc("/signup", handlers.Signup(dataStore, emailVerifier, signupLimiter, breaches, trustedProxies)).Methods("POST")
+	r.HandleFunc("/auth/verify-email", emailVerifier.Verify).Methods("POST")
+	r.HandleFunc("/auth/resend-verification", emailVerifier.Resend).Methods("POST")
+	r.HandleFunc("/intake/{token}", intakeSwitch(handlers.GetIntakeForm(dataStore))).Methods("GET")
+	r.HandleFunc("/intake/{token}", intakeSwitch(handlers.SubmitIntakeForm(dataStore, intakeLimiter, challenger, trustedProxies))).Methods("POST")
+	r.HandleFunc("/oauth/token", handlers.OAuthToken(dataStore)).Methods("POST")
+	r.HandleFunc("/oauth/introspect", handlers.OAuthIntrospect(dataStore)).Methods("POST")
+	r.HandleFunc("/approval-key", handlers.GetApprovalKey(signer)).Methods("GET")
+	r.HandleFunc("/avatars/{name}", handlers.ServeAvatar(blobs)).Methods("GET")
+	r.HandleFunc("/logos/{name}", handlers.ServeLogo(blobs)).Methods("GET")
+	r.HandleFunc("/shared/{token}", handlers.SharedReview(dataStore, blobs, shareLinks, trustedProxies)).Methods("GET")
+	r.HandleFunc("/schemas", handlers.ListWebhookSchemas(baseURL)).Methods("GET")
+	r.HandleFunc("/schemas/{event}/{version}.json", handlers.GetWebhookSchema(baseURL)).Methods("GET")
+	r.HandleFunc("/webhooks/jira/{orgId}", handlers.JiraWebhook(dataStore, jiraConnector)).Methods("POST")
+	if primary != nil {
+		r.HandleFunc("/replication/stream", primary.StreamHandler(replicationToken)).Methods("GET")
+	}
 
 	// Protected endpoints
 	api := r.PathPrefix("/api").Subrouter()
+	api.Use(middleware.ServiceCertAuth(dataStore, authService))
+	api.Use(middleware.SignedRequestAuth(dataStore, authService, jwtSecret))
+	api.Use(middleware.APIKeyAuth(dataStore, authService))
+	api.Use(middleware.OAuthAuth(dataStore, authService))
 	api.Use(middleware.JWTAuth(jwtSecret))
+	api.Use(middleware.TokenBinding(dataStore, dpopProofs))
+	api.Use(middleware.Sessions(dataStore, trustedProxies))
+	api.Use(accesslog.Identify)
+	api.Use(logging.Identify)
+	// AUTHZ_TRACE=true lets admins send X-Authz-Trace to see why a request
+	// was allowed or denied
+	if os.Getenv("AUTHZ_TRACE") == "true" {
+		api.Use(middleware.AuthzTrace(operators))
+	}
+	requireRole := func(roles ...string) func(http.HandlerFunc) http.HandlerFunc {
+		return middleware.Traced("role "+strings.Join(roles, "|"), middleware.RequireRole(roles...))
+	}
+	// ?fields=title,status trims JSON responses to the fields named. It
+	// comes before Locale so handlers still find the response language on
+	// their writer
+	api.Use(middleware.SparseFields)
+	api.Use(middleware.Locale(dataStore))
+	// Org admins can break glass for emergency elevated access within
+	// their own organization, once another admin or an operator approves,
+	// for at most BREAK_GLASS_MAX (default 1h)
+	breakGlassMax := time.Hour
+	if v := os.Getenv("BREAK_GLASS_MAX"); v != "" {
+		if breakGlassMax, err = time.ParseDuration(v); err != nil || breakGlassMax <= 0 {
+			log.Fatalf("Invalid BREAK_GLASS_MAX %q", v)
+		}
+	}
+	api.Use(middleware.BreakGlass(dataStore))
+	// Org admins can restrict API access to CIDR ranges; refused requests
+	// are recorded as security events and operators are exempt
+	restrictNetwork := middleware.RestrictNetwork(dataStore, operators, trustedProxies)
+	api.Use(restrictNetwork)
+	// An elevated admin can approve their own organization's reviews past
+	// its approval requirements
+	approveReview := requireRole("admin")(handlers.UnlessElevated(dataStore, handlers.RequireResolvedThreads(dataStore), handlers.RequireApprovalGates(dataStore), handlers.RequireRiskApprovals(dataStore))(handlers.ApproveReview(reviews)))
+
+	// Requests for orgs pinned to another region are forwarded there;
+	// deactivated users, suspended orgs, maintenance and replicas refuse
+	// them or their writes
+	orgChecks := []mux.MiddlewareFunc{
+		middleware.Residency(dataStore, localRegion, regionURLs),
+		middleware.RejectInactive(dataStore),
+		middleware.ReadOnly(dataStore, operators),
+	}
+	if replicationMode == replication.ModeReplica {
+		orgChecks = append(orgChecks, middleware.ReplicaReadOnly())
+	}
+
+	// Approvers can act on approval-request emails through signed links
+	// valid for EMAIL_APPROVAL_TTL (default 24h), or by replying when
+	// INBOUND_EMAIL_DOMAIN routes replies to /inbound/email, authenticated
+	// with INBOUND_EMAIL_SECRET. These routes are outside /api, so they
+	// run the API's network and org checks as the approver
+	emailApprovalTTL := 24 * time.Hour
+	if v := os.Getenv("EMAIL_APPROVAL_TTL"); v != "" {
+		if emailApprovalTTL, err = time.ParseDuration(v); err != nil {
+			log.Fatalf("Invalid EMAIL_APPROVAL_TTL %q", v)
+		}
+	}
+	emailApprovals := &handlers.EmailApprovals{
+		Store:       dataStore,
+		Mailer:      mailer,
+		Links:       emailapproval.NewLinks(jwtSecret),
+		Issuer:      authService,
+		Reviews:     reviews,
+		Approve:     middleware.JWTAuth(jwtSecret)(approveReview),
+		Checks:      middleware.Chain(append([]mux.MiddlewareFunc{middleware.JWTAuth(jwtSecret), restrictNetwork}, orgChecks...)...),
+		BaseURL:     baseURL,
+		ReplyDomain: os.Getenv("INBOUND_EMAIL_DOMAIN"),
+		TTL:         emailApprovalTTL,
+	}
+	r.HandleFunc("/email-approval/{token}", killSwitch(models.CapabilityEmailApproval)(emailApprovals.ConfirmPage)).Methods("GET")
+	r.HandleFunc("/email-approval/{token}", killSwitch(models.CapabilityEmailApproval)(emailApprovals.Confirm)).Methods("POST")
+	r.HandleFunc("/inbound/email", killSwitch(models.CapabilityEmailApproval)(emailApprovals.InboundReply(os.Getenv("INBOUND_EMAIL_SECRET")))).Methods("POST")
+	api.Use(orgChecks...)
+	// Guests (external collaborators) only reach the reviews shared with
+	// them, with their own limit of GUEST_RATE_LIMIT requests per minute
+	// (default 60)
+	guestRate := 60
+	if v := os.Getenv("GUEST_RATE_LIMIT"); v != "" {
+		if guestRate, err = strconv.Atoi(v); err != nil || guestRate < 1 {
+			log.Fatalf("Invalid GUEST_RATE_LIMIT %q", v)
+		}
+	}
+	api.Use(middleware.RestrictGuests(security.NewRateLimiter(guestRate, time.Minute),
+		"GET /api/me",
+		"GET /api/me/profile",
+		"PUT /api/me/profile",
+		"PUT /api/me/password",
+		"GET /api/me/sessions",
+		"DELETE /api/me/sessions/{sessionId}",
+		"GET /api/me/notifications",
+		"GET /api/shared-reviews",
+		"GET /api/shared-reviews/{id}",
+		"GET /api/reviews/{id}/comments",
+		"POST /api/reviews/{id}/comments",
+	))
 
 	// User endpoints
-	api.HandleFunc("/me", handlers.GetCurrentUser).Methods("GET")
+	api.HandleFunc("/operations", handlers.ListOperations(dataStore)).Methods("GET")
+	api.HandleFunc("/operations/{id}", handlers.GetOperation(dataStore)).Methods("GET")
+	api.HandleFunc("/operations/{id}/cancel", handlers.CancelOperation(dataStore, operationRunner)).Methods("POST")
+	api.HandleFunc("/operations/{id}/result", handlers.DownloadOperationResult(dataStore, operationRunner)).Methods("GET", "HEAD")
+	api.HandleFunc("/me", handlers.GetCurrentUser(dataStore)).Methods("GET")
+	api.HandleFunc("/me/profile", handlers.GetProfile(dataStore)).Methods("GET")
+	api.HandleFunc("/me/password", handlers.ChangePassword(dataStore, breaches)).Methods("PUT")
+	api.HandleFunc("/me/email", emailVerifier.ChangeEmail).Methods("PUT")
+	api.HandleFunc("/me/email/resend", emailVerifier.ResendEmailChange).Methods("POST")
+	api.HandleFunc("/me/expertise", handlers.GetMyExpertise(dataStore)).Methods("GET")
+	api.HandleFunc("/me/expertise", handlers.SetMyExpertise(dataStore)).Methods("PUT")
+	api.HandleFunc("/me/onboarding", handlers.GetOnboarding(dataStore)).Methods("GET")
+	api.HandleFunc("/me/onboarding", handlers.UpdateOnboarding(dataStore)).Methods("PUT")
+	api.HandleFunc("/me/sessions", handlers.ListMySessions(dataStore)).Methods("GET")
+	api.HandleFunc("/me/sessions/{sessionId}", handlers.RevokeMySession(dataStore)).Methods("DELETE")
+	api.HandleFunc("/me/profile", handlers.UpdateProfile(dataStore)).Methods("PUT")
+	api.HandleFunc("/me/avatar", handlers.UploadAvatar(dataStore, blobs, baseURL)).Methods("PUT")
+	api.HandleFunc("/me/out-of-office", handlers.GetOutOfOffice(dataStore)).Methods("GET")
+	api.HandleFunc("/me/out-of-office", handlers.SetOutOfOffice(dataStore)).Methods("PUT")
+	api.HandleFunc("/me/out-of-office", handlers.ClearOutOfOffice(dataStore)).Methods("DELETE")
+	api.HandleFunc("/me/subscriptions", handlers.ListSubscriptions(dataStore)).Methods("GET")
+	api.HandleFunc("/me/subscriptions", handlers.Subscribe(dataStore)).Methods("POST")
+	api.HandleFunc("/me/subscriptions/{subId}", handlers.Unsubscribe(dataStore)).Methods("DELETE")
+	api.HandleFunc("/me/notifications", handlers.ListNotifications(dataStore)).Methods("GET")
+	api.HandleFunc("/me/break-glass", handlers.GetBreakGlass(dataStore)).Methods("GET")
+	api.HandleFunc("/me/break-glass", requireRole("admin")(handlers.RequestBreakGlass(dataStore, mailer, operators, breakGlassMax))).Methods("POST")
+	api.HandleFunc("/me/break-glass", handlers.EndBreakGlass(dataStore)).Methods("DELETE")
+	api.HandleFunc("/me/role-requests", handlers.ListMyRoleRequests(dataStore)).Methods("GET")
+	api.HandleFunc("/me/role-requests", handlers.RequestRole(dataStore)).Methods("POST")
+	api.HandleFunc("/me/role-requests/{requestId}", handlers.CancelRoleRequest(dataStore)).Methods("DELETE")
+	api.HandleFunc("/me/devices", handlers.ListDevices(dataStore)).Methods("GET")
+	api.HandleFunc("/me/devices", handlers.RegisterDevice(dataStore, pushGateway)).Methods("POST")
+	api.HandleFunc("/me/devices/{deviceId}", handlers.UpdateDevice(dataStore)).Methods("PUT")
+	api.HandleFunc("/me/devices/{deviceId}", handlers.UnregisterDevice(dataStore)).Methods("DELETE")
+
+	api.HandleFunc("/users/{id}/stats", handlers.GetUserStats(dataStore)).Methods("GET")
+
+	// OAuth consent, called by the consent screen on the user's behalf
+	api.HandleFunc("/oauth/authorize", handlers.GetOAuthConsent(dataStore)).Methods("GET")
+	api.HandleFunc("/oauth/authorize", handlers.AuthorizeOAuthClient(dataStore)).Methods("POST")
 
 	// Organization endpoints
-	api.HandleFunc("/orgs/{id}/members", handlers.GetOrgMembers).Methods("GET")
-	api.HandleFunc("/orgs/{id}/members", middleware.RequireRole("admin")(handlers.AddOrgMember)).Methods("POST")
-	api.HandleFunc("/orgs/{id}/members/{userId}", middleware.RequireRole("admin")(handlers.RemoveOrgMember)).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/members", handlers.GetOrgMembers(dataStore)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/members", requireRole("admin")(handlers.AddOrgMember(dataStore, mailer, baseURL))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/members/import", requireRole("admin")(handlers.ImportMembers(dataStore, mailer, baseURL, operationRunner))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/members/{userId}", requireRole("admin")(handlers.RemoveOrgMember(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/members/{userId}/password-reset", requireRole("admin")(handlers.ForcePasswordReset(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/members/{userId}/expertise", handlers.GetMemberExpertise(dataStore)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/members/{userId}/expertise", requireRole("admin")(handlers.SetMemberExpertise(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/members/{userId}/manager", requireRole("admin")(handlers.SetMemberManager(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/members/{userId}/role", requireRole("admin")(handlers.SetMemberRole(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/access-reviews", requireRole("admin")(handlers.ListAccessCampaigns(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/access-reviews", requireRole("admin")(handlers.CreateAccessCampaign(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/access-reviews/{campaignId}", requireRole("admin")(handlers.GetAccessCampaign(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/access-reviews/{campaignId}/members/{userId}/certify", requireRole("admin")(handlers.CertifyAccess(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/access-reviews/{campaignId}/members/{userId}/change", requireRole("admin")(handlers.ChangeAccess(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/token-binding", requireRole("admin")(handlers.GetTokenBinding(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/token-binding", requireRole("admin")(handlers.SetTokenBinding(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/request-signing", requireRole("admin")(handlers.GetRequestSigning(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/request-signing", requireRole("admin")(handlers.SetRequestSigning(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/network-policy", requireRole("admin")(handlers.GetNetworkPolicy(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/network-policy", requireRole("admin")(handlers.SetNetworkPolicy(dataStore, trustedProxies))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/security-events", requireRole("admin")(handlers.ListOrgSecurityEvents(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/break-glass", requireRole("admin")(handlers.ListOrgBreakGlass(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/break-glass/{grantId}/approve", requireRole("admin")(handlers.ApproveBreakGlass(dataStore, mailer, operators))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/role-requests", requireRole("admin")(handlers.ListRoleRequests(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/role-requests/{requestId}/approve", requireRole("admin")(handlers.ApproveRoleRequest(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/role-requests/{requestId}/reject", requireRole("admin")(handlers.RejectRoleRequest(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/members/{userId}/deactivate", requireRole("admin")(handlers.DeactivateMember(dataStore, assigner))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/members/{userId}/deactivate", requireRole("admin")(handlers.ReactivateMember(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/calendar", handlers.GetOrgCalendar(dataStore)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/calendar", requireRole("admin")(handlers.UpdateOrgCalendar(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/branding", handlers.GetOrgBranding(dataStore)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/branding", requireRole("admin")(handlers.UpdateOrgBranding(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/branding/logo", requireRole("admin")(handlers.UploadOrgLogo(dataStore, blobs, baseURL))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/branding/logo", requireRole("admin")(handlers.DeleteOrgLogo(dataStore, blobs))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/integrations/jira", requireRole("admin")(handlers.GetJiraConfig(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/integrations/jira", requireRole("admin")(handlers.UpdateJiraConfig(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/secret-policy", requireRole("admin")(handlers.GetSecretPolicy(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/secret-policy", requireRole("admin")(handlers.SetSecretPolicy(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/moderation", requireRole("admin")(handlers.GetModerationPolicy(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/moderation", requireRole("admin")(handlers.SetModerationPolicy(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/escalation-rules", requireRole("admin")(handlers.ListEscalationRules(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/escalation-rules", requireRole("admin")(handlers.CreateEscalationRule(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/escalation-rules/{ruleId}", requireRole("admin")(handlers.GetEscalationRule(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/escalation-rules/{ruleId}", requireRole("admin")(handlers.UpdateEscalationRule(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/escalation-rules/{ruleId}", requireRole("admin")(handlers.DeleteEscalationRule(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/integrations/escalation", requireRole("admin")(handlers.GetEscalationConfig(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/integrations/escalation", requireRole("admin")(handlers.UpdateEscalationConfig(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/integrations/bi-export", requireRole("admin")(handlers.GetBIExportConfig(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/integrations/bi-export", requireRole("admin")(handlers.UpdateBIExportConfig(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/integrations/bi-export/run", requireRole("admin")(handlers.RunBIExport(dataStore, biExporter))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/roles", requireRole("admin")(handlers.ListRoles(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/roles", requireRole("admin")(handlers.CreateRole(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/roles/{roleId}", requireRole("admin")(handlers.GetRole(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/roles/{roleId}", requireRole("admin")(handlers.UpdateRole(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/roles/{roleId}", requireRole("admin")(handlers.DeleteRole(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/intake-forms", requireRole("admin")(handlers.ListIntakeForms(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/intake-forms", requireRole("admin")(handlers.CreateIntakeForm(dataStore, baseURL))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/intake-forms/{formId}", requireRole("admin")(handlers.DisableIntakeForm(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/intake-submissions", requireRole("admin")(handlers.ListIntakeSubmissions(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/intake-submissions/{submissionId}/accept", requireRole("admin")(handlers.AcceptIntakeSubmission(dataStore, reviews))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/intake-submissions/{submissionId}/reject", requireRole("admin")(handlers.RejectIntakeSubmission(dataStore))).Methods("POST")
+	api.HandleFunc("/templates/catalog", handlers.ListTemplateCatalog(templateImporter)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/templates", handlers.ListTemplates(dataStore)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/templates/import", requireRole("admin")(handlers.ImportTemplates(dataStore, templateImporter))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/templates/{kind}/{name}", handlers.GetTemplate(dataStore)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/templates/{kind}/{name}", requireRole("admin")(handlers.DeleteTemplate(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/templates/{kind}/{name}/versions", handlers.ListTemplateVersions(dataStore)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/fields", handlers.ListCustomFields(dataStore)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/fields", requireRole("admin")(handlers.CreateCustomField(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/fields/{fieldId}", requireRole("admin")(handlers.UpdateCustomField(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/fields/{fieldId}", requireRole("admin")(handlers.DeleteCustomField(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/oauth-clients", requireRole("admin")(handlers.ListOAuthClients(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/oauth-clients", requireRole("admin")(handlers.CreateOAuthClient(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/oauth-clients/{clientId}", requireRole("admin")(handlers.DeleteOAuthClient(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/webhooks", requireRole("admin")(handlers.ListWebhooks(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/webhooks", requireRole("admin")(handlers.CreateWebhook(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/webhooks/{webhookId}", requireRole("admin")(handlers.GetWebhook(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/webhooks/{webhookId}", requireRole("admin")(handlers.UpdateWebhook(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/webhooks/{webhookId}", requireRole("admin")(handlers.DeleteWebhook(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/webhook-deliveries", requireRole("admin")(handlers.ListWebhookDeliveries(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/webhook-deliveries/redeliver", requireRole("admin")(handlers.RedeliverWebhooks(deliverer))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/webhook-deliveries/{deliveryId}", requireRole("admin")(handlers.GetWebhookDelivery(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/webhook-deliveries/{deliveryId}/redeliver", requireRole("admin")(handlers.RedeliverWebhook(deliverer))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/domains", requireRole("admin")(handlers.ListOrgDomains(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/domains", requireRole("admin")(handlers.AddOrgDomain(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/domains/{domain}", requireRole("admin")(handlers.UpdateOrgDomain(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/domains/{domain}", requireRole("admin")(handlers.DeleteOrgDomain(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/hierarchy", requireRole("admin")(handlers.GetOrgHierarchy(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/tree/reviews", requireRole("admin")(handlers.ListTreeReviews(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/tree/reviews/{reviewId}", requireRole("admin")(handlers.GetTreeReview(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/rollup", requireRole("admin")(handlers.GetOrgRollup(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/domains/{domain}/verify", requireRole("admin")(handlers.VerifyOrgDomain(dataStore, net.DefaultResolver.LookupTXT))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/export", requireRole("admin")(killSwitch(models.CapabilityExports)(handlers.ExportOrg(dataStore)))).Methods("GET", "HEAD")
+	api.HandleFunc("/orgs/{id}/export", requireRole("admin")(killSwitch(models.CapabilityExports)(handlers.StartOrgExport(dataStore, operationRunner)))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/audit/export", requireRole("admin")(killSwitch(models.CapabilityExports)(handlers.ExportAuditLog(dataStore)))).Methods("GET", "HEAD")
+	api.HandleFunc("/orgs/{id}/ai/usage", requireRole("admin")(handlers.GetLLMUsage(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/ai/providers", requireRole("admin")(handlers.ListLLMProviders(dataStore, aiProviders))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/ai/providers/{name}", requireRole("admin")(handlers.SetLLMProvider(dataStore, orgLLMPolicy))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/ai/providers/{name}", requireRole("admin")(handlers.DeleteLLMProvider(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/ai/providers/{name}/check", requireRole("admin")(handlers.CheckLLMProvider(aiProviders))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/ai/budget", requireRole("admin")(handlers.GetAIBudget(dataStore, aiProviders))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/ai/budget", requireRole("admin")(handlers.SetAIBudget(dataStore, aiProviders))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/ai/redaction", requireRole("admin")(handlers.GetAIRedactionPolicy(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/ai/redaction", requireRole("admin")(handlers.SetAIRedactionPolicy(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/ai/redaction/test", requireRole("admin")(handlers.TestAIRedaction(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/ai/redactions", requireRole("admin")(handlers.ListAIRedactions(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/ai/prompts", requireRole("admin")(handlers.ListPrompts(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/ai/prompts/{feature}", requireRole("admin")(handlers.GetPrompt(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/ai/prompts/{feature}", requireRole("admin")(handlers.SetPrompt(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/ai/prompts/{feature}/versions", requireRole("admin")(handlers.ListPromptVersions(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/ai/prompts/{feature}/rollback", requireRole("admin")(handlers.RollbackPrompt(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/ai/prompts/{feature}/test", requireRole("admin")(killSwitch(models.CapabilityAI)(handlers.TestPrompt(dataStore, operationRunner, aiProviders, suggester)))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/ai/suggestion-weights", requireRole("admin")(handlers.GetSuggestionWeights(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/ai/suggestion-weights", requireRole("admin")(handlers.ResetSuggestionWeights(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/kill-switches", requireRole("admin")(handlers.ListOrgKillSwitches(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/kill-switches/{capability}", requireRole("admin")(handlers.SetOrgKillSwitch(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/kill-switches/{capability}", requireRole("admin")(handlers.DeleteOrgKillSwitch(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/dead-letters", requireRole("admin")(handlers.ListDeadLetters(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/dead-letters", requireRole("admin")(handlers.PurgeDeadLetters(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/dead-letters/{letterId}", requireRole("admin")(handlers.GetDeadLetter(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/dead-letters/{letterId}", requireRole("admin")(handlers.DeleteDeadLetter(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/dead-letters/{letterId}/retry", requireRole("admin")(handlers.RetryDeadLetter(deadLetters))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/api-keys", requireRole("admin")(handlers.ListAPIKeys(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/api-keys", requireRole("admin")(handlers.CreateAPIKey(dataStore, jwtSecret))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/api-keys/{keyId}", requireRole("admin")(handlers.GetAPIKey(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/api-keys/{keyId}", requireRole("admin")(handlers.UpdateAPIKey(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/api-keys/{keyId}", requireRole("admin")(handlers.DeleteAPIKey(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/policy", handlers.GetOrgPolicy(service.Orgs())).Methods("GET")
+	api.HandleFunc("/orgs/{id}/policy", requireRole("admin")(handlers.UpdateOrgPolicy(service.Orgs()))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/risk-policy", requireRole("admin")(handlers.GetRiskPolicy(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/risk-policy", requireRole("admin")(handlers.SetRiskPolicy(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/approval-gates", requireRole("admin")(handlers.ListApprovalGates(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/approval-gates", requireRole("admin")(handlers.CreateApprovalGate(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/approval-gates/fields", requireRole("admin")(handlers.ListApprovalGateFields)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/approval-gates/evaluate", requireRole("admin")(handlers.EvaluateApprovalGates(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/approval-gates/{gateId}", requireRole("admin")(handlers.GetApprovalGate(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/approval-gates/{gateId}", requireRole("admin")(handlers.UpdateApprovalGate(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/approval-gates/{gateId}", requireRole("admin")(handlers.DeleteApprovalGate(dataStore))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/compliance/reports", requireRole("admin")(handlers.ListComplianceReports(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/compliance/reports", requireRole("admin")(handlers.GenerateComplianceReport(complianceReports))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/compliance/reports/{quarter}", requireRole("admin")(handlers.GetComplianceReport(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/compliance/reports/{quarter}/bundle", requireRole("admin")(killSwitch(models.CapabilityExports)(handlers.DownloadComplianceReport(dataStore, blobs)))).Methods("GET", "HEAD")
+	api.HandleFunc("/orgs/{id}", requireRole("admin")(handlers.RequestOrgDeletion(dataStore, blobs, mailer))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/deletion", requireRole("admin")(handlers.GetOrgDeletion(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/deletion", requireRole("admin")(handlers.CancelOrgDeletion(dataStore, blobs, mailer))).Methods("DELETE")
+	api.HandleFunc("/orgs/{id}/deletion/export", requireRole("admin")(handlers.DownloadOrgDeletionExport(dataStore, blobs))).Methods("GET", "HEAD")
+	api.HandleFunc("/orgs/{id}/retention", requireRole("admin")(handlers.GetRetentionPolicy(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/retention", requireRole("admin")(handlers.SetRetentionPolicy(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/retention/preview", requireRole("admin")(handlers.PreviewRetention(dataStore))).Methods("GET", "POST")
+	api.HandleFunc("/orgs/{id}/retention/runs", requireRole("admin")(handlers.ListRetentionRuns(dataStore))).Methods("GET")
+	api.HandleFunc("/orgs/{id}/teams", handlers.ListTeams(dataStore)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/teams", requireRole("admin")(handlers.CreateTeam(dataStore))).Methods("POST")
+	api.HandleFunc("/orgs/{id}/teams/{teamId}", handlers.GetTeam(dataStore)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/teams/{teamId}", requireRole("admin")(handlers.UpdateTeam(dataStore))).Methods("PUT")
+	api.HandleFunc("/orgs/{id}/teams/{teamId}", requireRole("admin")(handlers.DeleteTeam(dataStore))).Methods("DELETE")
 
 	// Review endpoints with RBAC
-	api.HandleFunc("/reviews", handlers.ListReviews).Methods("GET")
-	api.HandleFunc("/reviews", middleware.RequireRole("reviewer", "admin")(handlers.CreateReview)).Methods("POST")
-	api.HandleFunc("/reviews/{id}", handlers.GetReview).Methods("GET")
-	api.HandleFunc("/reviews/{id}", middleware.RequireRole("reviewer", "admin")(handlers.UpdateReview)).Methods("PUT")
-	api.HandleFunc("/reviews/{id}/approve", middleware.RequireRole("admin")(handlers.ApproveReview)).Methods("POST")
+	renderMarkdown := handlers.RenderMarkdown(dataStore, baseURL)
+	addRisk := handlers.AddRisk(dataStore)
+	api.HandleFunc("/reviews", addRisk(renderMarkdown(handlers.ListReviews(reviews)))).Methods("GET")
+	api.HandleFunc("/markdown/preview", handlers.PreviewMarkdown(dataStore, baseURL)).Methods("POST")
+	bannedTerms := handlers.FilterBannedTerms(dataStore)
+	detectSecrets := handlers.DetectSecrets(dataStore)
+	api.HandleFunc("/reviews", requireRole("reviewer", "admin")(bannedTerms(detectSecrets(handlers.ApplyCustomFields(dataStore)(handlers.CreateReview(reviews)))))).Methods("POST")
+	followMerged := handlers.FollowMerged(dataStore)
+	api.HandleFunc("/reviews/{id}", followMerged(addRisk(renderMarkdown(handlers.GetReview(reviews))))).Methods("GET")
+	api.HandleFunc("/reviews/{id}", requireRole("reviewer", "admin")(handlers.RequireEditable(dataStore)(bannedTerms(detectSecrets(handlers.ApplyCustomFields(dataStore)(handlers.UpdateReview(reviews))))))).Methods("PUT")
+	api.HandleFunc("/reviews/{id}/approve", approveReview).Methods("POST")
+	api.HandleFunc("/reviews/{id}/approval-requests", requireRole("reviewer", "admin")(emailApprovals.RequestApproval)).Methods("POST")
+	api.HandleFunc("/reviews/{id}/reopen", requireRole("admin")(handlers.ReopenReview(reviews))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/assignee", requireRole("admin")(handlers.PublishOnSuccess(dispatcher, notify.ReviewAssigned)(handlers.AssignReviewer(dataStore)))).Methods("PUT")
+	api.HandleFunc("/reviews/{id}/auto-assign", requireRole("admin")(handlers.PublishOnSuccess(dispatcher, notify.ReviewAssigned)(handlers.AutoAssignReview(dataStore, assigner)))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/suggested-reviewers", handlers.SuggestReviewers(dataStore, assigner)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/priority", requireRole("reviewer", "admin")(handlers.SetReviewPriority(reviews))).Methods("PUT")
+	api.HandleFunc("/reviews/{id}/labels", requireRole("reviewer", "admin")(handlers.RequireEditable(dataStore)(handlers.SetReviewLabels(reviews)))).Methods("PUT")
+	api.HandleFunc("/reviews/{id}/duplicates", handlers.FindDuplicates(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/merge", requireRole("reviewer", "admin")(handlers.MergeReview(dataStore))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/comments", followMerged(renderMarkdown(handlers.ListComments(reviews)))).Methods("GET")
+	api.HandleFunc("/reviews/{id}/comments", bannedTerms(handlers.AddComment(reviews))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/comments/{commentId}/resolve", handlers.ResolveComment(reviews)).Methods("POST")
+	api.HandleFunc("/reviews/{id}/comments/{commentId}/resolve", handlers.UnresolveComment(reviews)).Methods("DELETE")
+	api.HandleFunc("/reviews/{id}/attachments", handlers.ListAttachments(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/attachments", requireRole("reviewer", "admin")(handlers.UploadAttachment(dataStore, blobs, attachmentScans))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/attachments/bundle", killSwitch(models.CapabilityExports)(handlers.ExportAttachments(dataStore, blobs))).Methods("GET", "HEAD")
+	api.HandleFunc("/reviews/{id}/attachments/{attachmentId}", handlers.GetAttachment(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/attachments/{attachmentId}", requireRole("reviewer", "admin")(handlers.DeleteAttachment(dataStore, blobs))).Methods("DELETE")
+	api.HandleFunc("/reviews/{id}/attachments/{attachmentId}/content", handlers.DownloadAttachment(dataStore, blobs)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/attachments/{attachmentId}/scan", requireRole("admin")(handlers.RescanAttachment(dataStore, attachmentScans))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/secret-findings", handlers.ListSecretFindings(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/redactions", handlers.ListRedactions(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/redactions", requireRole("admin")(handlers.RedactContent(dataStore))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/escalations", handlers.GetEscalationHistory(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/sla", handlers.GetReviewSLA(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/links", handlers.ListReviewLinks(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/links", requireRole("reviewer", "admin")(handlers.AddReviewLink(dataStore, jiraClient))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/links/{linkId}", requireRole("reviewer", "admin")(handlers.RemoveReviewLink(dataStore))).Methods("DELETE")
+	api.HandleFunc("/reviews/{id}/links/{linkId}/sync", handlers.SyncReviewLink(dataStore, jiraClient)).Methods("POST")
+	api.HandleFunc("/external-links", handlers.FindLinkedReviews(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/fields", handlers.GetReviewFields(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/risk", handlers.GetReviewRisk(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/fields", requireRole("reviewer", "admin")(handlers.RequireEditable(dataStore)(handlers.SetReviewFields(dataStore)))).Methods("PUT")
+	api.HandleFunc("/reviews/{id}/export", killSwitch(models.CapabilityExports)(handlers.ExportReview(dataStore, blobs))).Methods("GET")
+	api.HandleFunc("/reviews/{id}/summarize", requireRole("reviewer", "admin")(killSwitch(models.CapabilityAI)(handlers.SummarizeReview(dataStore, operationRunner, aiProviders)))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/summary", handlers.GetReviewSummary(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/suggestions", handlers.ListSuggestions(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/suggestions", requireRole("reviewer", "admin")(killSwitch(models.CapabilityAI)(handlers.RequestSuggestions(dataStore, suggester, aiProviders)))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/suggestions/{suggestionId}/accept", requireRole("reviewer", "admin")(handlers.RequireEditable(dataStore)(handlers.DecideSuggestion(dataStore, reviews, models.SuggestionAccepted)))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/suggestions/{suggestionId}/reject", requireRole("reviewer", "admin")(handlers.DecideSuggestion(dataStore, reviews, models.SuggestionRejected))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/share-links", requireRole("reviewer", "admin")(handlers.ListShareLinks(dataStore))).Methods("GET")
+	api.HandleFunc("/reviews/{id}/share-links", requireRole("reviewer", "admin")(handlers.CreateShareLink(dataStore, shareLinks, baseURL))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/share-links/{linkId}", requireRole("reviewer", "admin")(handlers.RevokeShareLink(dataStore))).Methods("DELETE")
+	api.HandleFunc("/reviews/{id}/share-links/{linkId}/accesses", requireRole("reviewer", "admin")(handlers.ListShareLinkAccesses(dataStore))).Methods("GET")
+	api.HandleFunc("/reviews/{id}/grants", requireRole("reviewer", "admin")(handlers.ListReviewGrants(dataStore))).Methods("GET")
+	api.HandleFunc("/reviews/{id}/grants/{orgId}", requireRole("reviewer", "admin")(handlers.SetReviewGrant(dataStore))).Methods("PUT")
+	api.HandleFunc("/reviews/{id}/grants/{orgId}", requireRole("reviewer", "admin")(handlers.DeleteReviewGrant(dataStore))).Methods("DELETE")
+	api.HandleFunc("/reviews/{id}/guests", requireRole("reviewer", "admin")(handlers.ListGuestGrants(dataStore))).Methods("GET")
+	api.HandleFunc("/reviews/{id}/guests/{userId}", requireRole("reviewer", "admin")(handlers.SetGuestGrant(dataStore))).Methods("PUT")
+	api.HandleFunc("/reviews/{id}/guests/{userId}", requireRole("reviewer", "admin")(handlers.DeleteGuestGrant(dataStore))).Methods("DELETE")
+	api.HandleFunc("/shared-reviews", handlers.ListSharedReviews(dataStore)).Methods("GET")
+	api.HandleFunc("/shared-reviews/{id}", handlers.GetSharedReview(dataStore)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/approval-proof", handlers.GetApprovalProof(dataStore)).Methods("GET")
+
+	// Instance operator endpoints (cross-org, distinct from org-scoped admin)
+	requireOperator := middleware.Traced("operator", middleware.RequireOperator(operators))
+	api.HandleFunc("/admin/orgs", requireOperator(handlers.AdminListOrgs(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/orgs/{id}/suspend", requireOperator(handlers.AdminSuspendOrg(dataStore))).Methods("POST")
+	api.HandleFunc("/admin/orgs/{id}/suspend", requireOperator(handlers.AdminUnsuspendOrg(dataStore))).Methods("DELETE")
+	api.HandleFunc("/admin/orgs/{id}/network-policy", requireOperator(handlers.AdminGetNetworkPolicy(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/orgs/{id}/network-policy", requireOperator(handlers.AdminDeleteNetworkPolicy(dataStore))).Methods("DELETE")
+	api.HandleFunc("/manage/orgs", requireOperator(handlers.ManageListOrgs(dataStore))).Methods("GET")
+	api.HandleFunc("/manage/orgs", requireOperator(handlers.ManageCreateOrg(dataStore, regionNames))).Methods("POST")
+	api.HandleFunc("/manage/orgs/{id}", requireOperator(handlers.ManageGetOrg(dataStore))).Methods("GET")
+	api.HandleFunc("/manage/orgs/{id}", requireOperator(handlers.ManageUpdateOrg(dataStore))).Methods("PUT")
+	api.HandleFunc("/manage/orgs/{id}", requireOperator(handlers.ManageDeleteOrg(dataStore))).Methods("DELETE")
+	api.HandleFunc("/manage/orgs/{id}/parent", requireOperator(handlers.ManageSetOrgParent(dataStore))).Methods("PUT")
+	api.HandleFunc("/admin/security-events", requireOperator(handlers.AdminListSecurityEvents(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/plugins", requireOperator(handlers.AdminListPlugins(pluginManager))).Methods("GET")
+	api.HandleFunc("/admin/plugins/{name}", requireOperator(handlers.AdminSetPlugin(pluginManager))).Methods("PUT")
+	api.HandleFunc("/admin/break-glass", requireOperator(handlers.AdminListBreakGlass(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/break-glass/{grantId}/approve", requireOperator(handlers.AdminApproveBreakGlass(dataStore, mailer, operators))).Methods("POST")
+	api.HandleFunc("/admin/legal-holds", requireOperator(handlers.AdminListLegalHolds(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/legal-holds", requireOperator(handlers.AdminPlaceLegalHold(dataStore))).Methods("POST")
+	api.HandleFunc("/admin/legal-holds/{holdId}", requireOperator(handlers.AdminGetLegalHold(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/legal-holds/{holdId}/release", requireOperator(handlers.AdminReleaseLegalHold(dataStore))).Methods("POST")
+	api.HandleFunc("/admin/stats", requireOperator(handlers.AdminStats(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/service-identities", requireOperator(handlers.AdminListServiceIdentities(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/service-identities", requireOperator(handlers.AdminCreateServiceIdentity(dataStore))).Methods("POST")
+	api.HandleFunc("/admin/service-identities/{serviceId}", requireOperator(handlers.AdminGetServiceIdentity(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/service-identities/{serviceId}", requireOperator(handlers.AdminUpdateServiceIdentity(dataStore))).Methods("PUT")
+	api.HandleFunc("/admin/service-identities/{serviceId}", requireOperator(handlers.AdminDeleteServiceIdentity(dataStore))).Methods("DELETE")
+	api.HandleFunc("/admin/kill-switches", requireOperator(handlers.AdminListKillSwitches(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/kill-switches/{capability}", requireOperator(handlers.AdminSetKillSwitch(dataStore))).Methods("PUT")
+	api.HandleFunc("/admin/kill-switches/{capability}", requireOperator(handlers.AdminDeleteKillSwitch(dataStore))).Methods("DELETE")
+	api.HandleFunc("/admin/dead-letters", requireOperator(handlers.AdminListDeadLetters(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/dead-letters", requireOperator(handlers.AdminPurgeDeadLetters(dataStore))).Methods("DELETE")
+	api.HandleFunc("/admin/dead-letters/{letterId}", requireOperator(handlers.AdminGetDeadLetter(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/dead-letters/{letterId}", requireOperator(handlers.AdminDeleteDeadLetter(dataStore))).Methods("DELETE")
+	api.HandleFunc("/admin/dead-letters/{letterId}/retry", requireOperator(handlers.AdminRetryDeadLetter(deadLetters))).Methods("POST")
+	api.HandleFunc("/admin/integrations", requireOperator(handlers.AdminIntegrationStats())).Methods("GET")
+	api.HandleFunc("/admin/replication", requireOperator(handlers.AdminReplicationStatus(replicationStatus))).Methods("GET")
+	api.HandleFunc("/admin/encryption/rotate", requireOperator(handlers.AdminRotateEncryption(dataStore))).Methods("POST")
+	api.HandleFunc("/admin/settings", requireOperator(handlers.AdminGetSettings(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/settings", requireOperator(handlers.AdminUpdateSettings(dataStore))).Methods("PUT")
+	api.HandleFunc("/admin/maintenance", requireOperator(handlers.AdminGetMaintenance(dataStore))).Methods("GET")
+	api.HandleFunc("/admin/maintenance", requireOperator(handlers.AdminSetMaintenance(dataStore))).Methods("PUT")
+	api.HandleFunc("/admin/logging", requireOperator(handlers.AdminGetLogging(logControl))).Methods("GET")
+	api.HandleFunc("/admin/logging/level", requireOperator(handlers.AdminSetLogLevel(logControl))).Methods("PUT")
+	api.HandleFunc("/admin/logging/overrides", requireOperator(handlers.AdminAddLogOverride(logControl))).Methods("POST")
+	api.HandleFunc("/admin/logging/overrides/{overrideId}", requireOperator(handlers.AdminRemoveLogOverride(logControl))).Methods("DELETE")
+
+	// Plugin routes
+	api.PathPrefix("/plugins/").Handler(pluginManager)
+
+	// Embedded web UI; registered last so every route above takes precedence
+	r.PathPrefix("/").Handler(ui.Handler()).Methods("GET", "HEAD")
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -63,8 +985,187 @@ func main() {
 		port = "8080"
 	}
 
+	// Metrics are pushed every METRICS_PUSH_INTERVAL (default 15s) to a
+	// StatsD daemon at METRICS_STATSD_ADDR (METRICS_STATSD_FORMAT=dogstatsd
+	// for tags, as the Datadog agent expects) and/or a Prometheus
+	// remote-write endpoint at METRICS_REMOTE_WRITE_URL, authenticated with
+	// METRICS_REMOTE_WRITE_TOKEN or METRICS_REMOTE_WRITE_USER/_PASSWORD.
+	// METRICS_LABELS ("env=prod,team=x") are added to every series.
+	var metricSinks []metrics.Sink
+	if addr := os.Getenv("METRICS_STATSD_ADDR"); addr != "" {
+		format := os.Getenv("METRICS_STATSD_FORMAT")
+		if format != "" && format != "statsd" && format != "dogstatsd" {
+			log.Fatalf("Invalid METRICS_STATSD_FORMAT %q", format)
+		}
+		statsd, err := metrics.NewStatsD(addr, "aurea", format == "dogstatsd")
+		if err != nil {
+			log.Fatalf("Invalid METRICS_STATSD_ADDR: %v", err)
+		}
+		metricSinks = append(metricSinks, statsd)
+	}
+	if url := os.Getenv("METRICS_REMOTE_WRITE_URL"); url != "" {
+		metricsHTTP := httpclient.New("metrics", outbound, httpclient.Options{}).HTTP()
+		metricSinks = append(metricSinks, metrics.NewRemoteWrite(url, metricsHTTP, "aurea",
+			os.Getenv("METRICS_REMOTE_WRITE_TOKEN"), os.Getenv("METRICS_REMOTE_WRITE_USER"), os.Getenv("METRICS_REMOTE_WRITE_PASSWORD")))
+	}
+	var pusher *metrics.Pusher
+	requestMetrics := metrics.NewRequests()
+	if len(metricSinks) > 0 {
+		interval := 15 * time.Second
+		if v := os.Getenv("METRICS_PUSH_INTERVAL"); v != "" {
+			if interval, err = time.ParseDuration(v); err != nil || interval <= 0 {
+				log.Fatalf("Invalid METRICS_PUSH_INTERVAL %q", v)
+			}
+		}
+		labels := metrics.ParseLabels(os.Getenv("METRICS_LABELS"))
+		labels["instance"] = instanceID
+		pusher = metrics.NewPusher(interval, labels, metricSinks...)
+		pusher.Add(requestMetrics.Samples)
+		pusher.Add(func() []metrics.Sample {
+			stats := dataStore.Stats()
+			out := []metrics.Sample{
+				{Name: "organizations", Value: float64(stats.Organizations)},
+				{Name: "users", Value: float64(stats.Users)},
+			}
+			for status, n := range stats.ReviewsByStatus {
+				out = append(out, metrics.Sample{Name: "reviews", Labels: map[string]string{"status": status}, Value: float64(n)})
+			}
+			return out
+		})
+	}
+
+	// ACCESS_LOG (stdout, file:<path>, syslog or syslog:udp://host:514)
+	// enables the access log. ACCESS_LOG_FIELDS selects fields,
+	// ACCESS_LOG_SAMPLE logs a fraction of non-error requests,
+	// ACCESS_LOG_REDACT=false keeps credentials and emails, and files rotate
+	// at ACCESS_LOG_MAX_MB keeping ACCESS_LOG_MAX_FILES
+	handler := logging.Middleware(r)
+	// LOAD_SHED=true answers 503 once concurrency exceeds an adaptive limit
+	// that shrinks when requests take longer than SHED_TARGET_LATENCY
+	// (default 500ms) or goroutines exceed SHED_MAX_GOROUTINES. Exports,
+	// bundles, downloads and ranged resumes of them are limited separately
+	// to SHED_STREAM_LIMIT at a time (default 20)
+	if os.Getenv("LOAD_SHED") == "true" {
+		streams := func(r *http.Request) bool {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead || !strings.HasPrefix(r.URL.Path, "/api/") {
+				return false
+			}
+			if r.Header.Get("Range") != "" {
+				return true
+			}
+			for _, suffix := range []string{"/export", "/bundle", "/content", "/result"} {
+				if strings.HasSuffix(r.URL.Path, suffix) {
+					return true
+				}
+			}
+			return false
+		}
+		shedOpts := middleware.ShedOptions{
+			Exempt:  []string{"/healthz", "/api/admin/", "/api/manage/", "/replication/"},
+			Streams: streams,
+		}
+		if v := os.Getenv("SHED_TARGET_LATENCY"); v != "" {
+			if shedOpts.TargetLatency, err = time.ParseDuration(v); err != nil {
+				log.Fatalf("Invalid SHED_TARGET_LATENCY %q", v)
+			}
+		}
+		if v := os.Getenv("SHED_MAX_GOROUTINES"); v != "" {
+			if shedOpts.MaxGoroutines, err = strconv.Atoi(v); err != nil {
+				log.Fatalf("Invalid SHED_MAX_GOROUTINES %q", v)
+			}
+		}
+		if v := os.Getenv("SHED_STREAM_LIMIT"); v != "" {
+			if shedOpts.StreamLimit, err = strconv.Atoi(v); err != nil || shedOpts.StreamLimit < 1 {
+				log.Fatalf("Invalid SHED_STREAM_LIMIT %q", v)
+			}
+		}
+		shedder := middleware.NewLoadShedder(shedOpts)
+		expvar.Publish("load_shedding", expvar.Func(func() interface{} { return shedder.Stats() }))
+		if pusher != nil {
+			pusher.Add(func() []metrics.Sample {
+				st := shedder.Stats()
+				return []metrics.Sample{
+					{Name: "load_shed_limit", Value: float64(st.Limit)},
+					{Name: "load_shed_in_flight", Value: float64(st.InFlight)},
+					{Name: "load_shed_streams", Value: float64(st.Streams)},
+					{Name: "load_shed_rejected_total", Value: float64(st.Shed), Kind: metrics.Counter},
+				}
+			})
+		}
+		handler = shedder.Handler(handler)
+	}
+	if sink := os.Getenv("ACCESS_LOG"); sink != "" {
+		cfg := accesslog.Config{
+			Redact:     os.Getenv("ACCESS_LOG_REDACT") != "false",
+			SampleRate: 1,
+			Proxies:    trustedProxies,
+		}
+		if v := os.Getenv("ACCESS_LOG_FIELDS"); v != "" {
+			cfg.Fields = strings.Split(v, ",")
+		}
+		if v := os.Getenv("ACCESS_LOG_SAMPLE"); v != "" {
+			if cfg.SampleRate, err = strconv.ParseFloat(v, 64); err != nil {
+				log.Fatalf("Invalid ACCESS_LOG_SAMPLE %q", v)
+			}
+		}
+		maxMB, maxFiles := 100, 5
+		if v := os.Getenv("ACCESS_LOG_MAX_MB"); v != "" {
+			if maxMB, err = strconv.Atoi(v); err != nil {
+				log.Fatalf("Invalid ACCESS_LOG_MAX_MB %q", v)
+			}
+		}
+		if v := os.Getenv("ACCESS_LOG_MAX_FILES"); v != "" {
+			if maxFiles, err = strconv.Atoi(v); err != nil {
+				log.Fatalf("Invalid ACCESS_LOG_MAX_FILES %q", v)
+			}
+		}
+		out, err := accesslog.OpenSink(sink, int64(maxMB)<<20, maxFiles)
+		if err != nil {
+			log.Fatalf("Failed to open access log: %v", err)
+		}
+		defer out.Close()
+		accessLog, err := accesslog.New(out, cfg)
+		if err != nil {
+			log.Fatalf("Invalid access log configuration: %v", err)
+		}
+		handler = accessLog.Handler(handler)
+	}
+	if pusher != nil {
+		handler = requestMetrics.Middleware(handler)
+		go pusher.Run(context.Background())
+	}
+
+	// MTLS_ADDR (e.g. :8443) serves the same API to internal services that
+	// authenticate with a client certificate signed by MTLS_CLIENT_CA; the
+	// listener's own certificate is MTLS_CERT_FILE and MTLS_KEY_FILE
+	if addr := os.Getenv("MTLS_ADDR"); addr != "" {
+		caPEM, err := os.ReadFile(os.Getenv("MTLS_CLIENT_CA"))
+		if err != nil {
+			log.Fatalf("Failed to read MTLS_CLIENT_CA: %v", err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caPEM) {
+			log.Fatalf("MTLS_CLIENT_CA contains no certificates")
+		}
+		mtlsServer := &http.Server{
+			Addr:    addr,
+			Handler: handler,
+			TLSConfig: &tls.Config{
+				ClientAuth: tls.RequireAndVerifyClientCert,
+				ClientCAs:  clientCAs,
+				MinVersion: tls.VersionTLS12,
+			},
+		}
+		go func() {
+			log.Printf("mTLS listener starting on %s", addr)
+			if err := mtlsServer.ListenAndServeTLS(os.Getenv("MTLS_CERT_FILE"), os.Getenv("MTLS_KEY_FILE")); err != nil {
+				log.Fatalf("mTLS listener failed: %v", err)
+			}
+		}()
+	}
+
 	log.Printf("Server starting on port %s", port)
-	if err := http.ListenAndServe(":"+port, r); err != nil {
+	if err := http.ListenAndServe(":"+port, handler); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
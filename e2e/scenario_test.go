@@ -0,0 +1,372 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/webhooks"
+	"github.com/andres20980/aurea-orchestrator/pkg/client"
+)
+
+// flowEvents are the events the review flow must produce, in order.
+var flowEvents = []string{"review.created", "comment.added", "review.approved"}
+
+// deliveryWait bounds how long side effects may take to arrive. Events are
+// relayed from the outbox asynchronously.
+const deliveryWait = 15 * time.Second
+
+// scenario runs one complete flow: an admin registers a webhook, invites a
+// member, then creates, comments on and approves a review.
+type scenario struct {
+	baseURL          string
+	email            string
+	password         string
+	reviewerEmail    string
+	reviewerPassword string
+
+	admin    *client.Client
+	reviewer *client.Client
+	me       *client.User
+	secret   string
+	hooks    *receiver
+	review   *client.Review
+}
+
+// run executes the steps in order as subtests, stopping at the first
+// failure whose result later steps depend on.
+func (s *scenario) run(ctx context.Context, t *testing.T) {
+	s.hooks = newReceiver()
+	t.Cleanup(s.hooks.Close)
+	t.Cleanup(func() { s.cleanup(t) })
+
+	steps := []struct {
+		name     string
+		fn       func(context.Context) error
+		required bool
+	}{
+		{"login", s.login, true},
+		{"register webhook", s.registerWebhook, true},
+		{"subscribe reviewer", s.subscribeReviewer, false},
+		{"invite member", s.inviteMember, false},
+		{"create review", s.createReview, true},
+		{"comment", s.comment, false},
+		{"approve", s.approve, false},
+		{"webhooks delivered", s.checkWebhooks, false},
+		{"notifications delivered", s.checkNotifications, false},
+	}
+	for _, step := range steps {
+		passed := t.Run(step.name, func(t *testing.T) {
+			switch err := step.fn(ctx); {
+			case err == errSkipped:
+				t.Skip("no reviewer account given")
+			case err != nil:
+				t.Fatal(err)
+			}
+		})
+		if !passed && step.required {
+			t.FailNow()
+		}
+	}
+}
+
+// errSkipped marks a step whose optional inputs were not given.
+var errSkipped = errors.New("skipped")
+
+func (s *scenario) login(ctx context.Context) error {
+	s.admin = client.New(s.baseURL)
+	if err := s.admin.Login(ctx, s.email, s.password); err != nil {
+		return err
+	}
+	me, err := s.admin.Me(ctx)
+	if err != nil {
+		return err
+	}
+	if me.Role != "admin" {
+		return fmt.Errorf("%s has role %q, want admin", me.Email, me.Role)
+	}
+	s.me = me
+
+	if s.reviewerEmail != "" {
+		s.reviewer = client.New(s.baseURL)
+		if err := s.reviewer.Login(ctx, s.reviewerEmail, s.reviewerPassword); err != nil {
+			return fmt.Errorf("reviewer: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *scenario) registerWebhook(ctx context.Context) error {
+	s.secret = randomHex(16)
+	hook, err := s.admin.CreateWebhook(ctx, s.me.OrgID, client.WebhookInput{
+		ExternalID: "e2e-" + randomHex(4),
+		URL:        s.hooks.URL,
+		Events:     flowEvents,
+		Secret:     s.secret,
+	})
+	if err != nil {
+		return err
+	}
+	s.hooks.id = hook.ID
+	return nil
+}
+
+// subscribeReviewer makes the reviewer watch the admin's reviews, so the
+// flow below must reach their inbox.
+func (s *scenario) subscribeReviewer(ctx context.Context) error {
+	if s.reviewer == nil {
+		return errSkipped
+	}
+	return call(ctx, s.baseURL, s.reviewer.Token(), http.MethodPost, "/api/me/subscriptions",
+		map[string]string{"author_id": s.me.ID}, nil)
+}
+
+// inviteMember adds a new member through the bulk import, which sends the
+// invite email.
+func (s *scenario) inviteMember(ctx context.Context) error {
+	var report struct {
+		Created int `json:"created"`
+		Rows    []struct {
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		} `json:"rows"`
+	}
+	rows := []map[string]string{{
+		"email": "e2e-" + randomHex(4) + "@example.invalid",
+		"name":  "E2E Invitee",
+		"role":  "reviewer",
+	}}
+	if err := call(ctx, s.baseURL, s.admin.Token(), http.MethodPost, "/api/orgs/"+s.me.OrgID+"/members/import", rows, &report); err != nil {
+		return err
+	}
+	if report.Created != 1 {
+		return fmt.Errorf("import created %d members: %+v", report.Created, report.Rows)
+	}
+	return nil
+}
+
+func (s *scenario) createReview(ctx context.Context) error {
+	review, err := s.admin.CreateReview(ctx, client.ReviewInput{
+		Title:   "E2E review " + randomHex(4),
+		Content: "Created by the end-to-end scenario.",
+	})
+	if err != nil {
+		return err
+	}
+	s.review = review
+	return nil
+}
+
+func (s *scenario) comment(ctx context.Context) error {
+	_, err := s.admin.AddComment(ctx, s.review.ID, "Looks good.")
+	return err
+}
+
+func (s *scenario) approve(ctx context.Context) error {
+	if err := s.admin.ApproveReview(ctx, s.review.ID); err != nil {
+		return err
+	}
+	review, err := s.admin.GetReview(ctx, s.review.ID)
+	if err != nil {
+		return err
+	}
+	if review.Status != "approved" {
+		return fmt.Errorf("status is %q after approval", review.Status)
+	}
+	return nil
+}
+
+// checkWebhooks waits for one signed delivery per flow event for the review.
+func (s *scenario) checkWebhooks(ctx context.Context) error {
+	got, err := s.hooks.wait(ctx, s.review.ID, len(flowEvents))
+	if err != nil {
+		return err
+	}
+	for _, want := range flowEvents {
+		d, ok := got[want]
+		if !ok {
+			return fmt.Errorf("no %s delivery", want)
+		}
+		if sig := d.header.Get("X-Aurea-Signature"); sig != webhooks.Sign(s.secret, d.body) {
+			return fmt.Errorf("%s delivery has signature %q, which does not match the body", want, sig)
+		}
+		if id := d.header.Get("X-Aurea-Webhook"); id != s.hooks.id {
+			return fmt.Errorf("%s delivery names webhook %q, want %q", want, id, s.hooks.id)
+		}
+	}
+	return nil
+}
+
+// checkNotifications polls the reviewer's inbox for every flow event.
+func (s *scenario) checkNotifications(ctx context.Context) error {
+	if s.reviewer == nil {
+		return errSkipped
+	}
+	deadline := time.Now().Add(deliveryWait)
+	for {
+		var inbox []struct {
+			Type     string `json:"type"`
+			ReviewID string `json:"review_id"`
+			ActorID  string `json:"actor_id"`
+		}
+		if err := call(ctx, s.baseURL, s.reviewer.Token(), http.MethodGet, "/api/me/notifications", nil, &inbox); err != nil {
+			return err
+		}
+		seen := map[string]bool{}
+		for _, n := range inbox {
+			if n.ReviewID == s.review.ID && n.ActorID == s.me.ID {
+				seen[n.Type] = true
+			}
+		}
+		var missing []string
+		for _, want := range flowEvents {
+			if !seen[want] {
+				missing = append(missing, want)
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("missing notifications: %s", strings.Join(missing, ", "))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// cleanup removes the webhook so repeated runs against a shared instance
+// do not accumulate dead endpoints.
+func (s *scenario) cleanup(t *testing.T) {
+	if s.hooks.id == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.admin.DeleteWebhook(ctx, s.me.OrgID, s.hooks.id); err != nil {
+		t.Errorf("Failed to delete webhook %s: %v", s.hooks.id, err)
+	}
+}
+
+// delivery is one webhook request as received.
+type delivery struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver is a local webhook endpoint that records deliveries by event.
+type receiver struct {
+	*httptest.Server
+	id string
+
+	mu       sync.Mutex
+	byReview map[string]map[string]delivery
+	arrived  chan struct{}
+}
+
+func newReceiver() *receiver {
+	rc := &receiver{byReview: map[string]map[string]delivery{}, arrived: make(chan struct{}, 1)}
+	rc.Server = httptest.NewServer(http.HandlerFunc(rc.serve))
+	return rc
+}
+
+func (rc *receiver) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var payload struct {
+		Event    string `json:"event"`
+		ReviewID string `json:"review_id"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	rc.mu.Lock()
+	if rc.byReview[payload.ReviewID] == nil {
+		rc.byReview[payload.ReviewID] = map[string]delivery{}
+	}
+	rc.byReview[payload.ReviewID][payload.Event] = delivery{header: r.Header.Clone(), body: body}
+	rc.mu.Unlock()
+	select {
+	case rc.arrived <- struct{}{}:
+	default:
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// wait returns the deliveries for reviewID once n distinct events have
+// arrived, or an error after deliveryWait.
+func (rc *receiver) wait(ctx context.Context, reviewID string, n int) (map[string]delivery, error) {
+	timer := time.NewTimer(deliveryWait)
+	defer timer.Stop()
+	for {
+		rc.mu.Lock()
+		got := make(map[string]delivery, len(rc.byReview[reviewID]))
+		for event, d := range rc.byReview[reviewID] {
+			got[event] = d
+		}
+		rc.mu.Unlock()
+		if len(got) >= n {
+			return got, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return got, fmt.Errorf("received %d of %d webhook deliveries", len(got), n)
+		case <-rc.arrived:
+		}
+	}
+}
+
+// call sends an authenticated JSON request for endpoints the SDK does not
+// cover.
+func call(ctx context.Context, baseURL, token, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &client.APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
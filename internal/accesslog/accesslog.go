@@ -0,0 +1,290 @@
+// Package accesslog writes one JSON line per HTTP request, separately from
+// the application log. Fields are selectable, secrets and email addresses
+// are redacted by default, and successful requests can be sampled.
+package accesslog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+)
+
+// Fields that can be logged.
+const (
+	FieldTime      = "time"
+	FieldMethod    = "method"
+	FieldPath      = "path"
+	FieldQuery     = "query"
+	FieldStatus    = "status"
+	FieldBytes     = "bytes"
+	FieldDuration  = "duration_ms"
+	FieldRemoteIP  = "remote_ip"
+	FieldUserAgent = "user_agent"
+	FieldReferer   = "referer"
+	FieldUserID    = "user_id"
+	FieldOrgID     = "org_id"
+)
+
+// AllFields lists every loggable field.
+var AllFields = []string{
+	FieldTime, FieldMethod, FieldPath, FieldQuery, FieldStatus, FieldBytes, FieldDuration,
+	FieldRemoteIP, FieldUserAgent, FieldReferer, FieldUserID, FieldOrgID,
+}
+
+// DefaultFields are logged when no fields are configured.
+var DefaultFields = []string{
+	FieldTime, FieldMethod, FieldPath, FieldStatus, FieldBytes, FieldDuration, FieldUserID, FieldOrgID,
+}
+
+// Config configures an access logger.
+type Config struct {
+	// Fields to log, from AllFields. Empty means DefaultFields.
+	Fields []string
+	// Redact replaces credentials in paths and query strings and masks
+	// email addresses.
+	Redact bool
+	// SampleRate is the fraction of requests logged, in (0, 1]. Server
+	// errors are always logged.
+	SampleRate float64
+	// Proxies are believed about the remote IP in X-Forwarded-For.
+	Proxies security.TrustedProxies
+}
+
+// Logger writes access log lines to a sink.
+type Logger struct {
+	cfg    Config
+	fields map[string]bool
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// New creates a logger writing to out.
+func New(out io.Writer, cfg Config) (*Logger, error) {
+	if len(cfg.Fields) == 0 {
+		cfg.Fields = DefaultFields
+	}
+	fields := make(map[string]bool)
+	for _, f := range cfg.Fields {
+		known := false
+		for _, k := range AllFields {
+			known = known || k == f
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown access log field %q", f)
+		}
+		fields[f] = true
+	}
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		return nil, errors.New("access log sample rate must be in (0, 1]")
+	}
+	return &Logger{cfg: cfg, fields: fields, out: out}, nil
+}
+
+type identityKey struct{}
+
+// identity is filled in by Identify once the request is authenticated,
+// which happens inside the handler the logger wraps.
+type identity struct {
+	userID, orgID string
+}
+
+// Handler logs each request served by next.
+func (l *Logger) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := &identity{}
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), identityKey{}, id)))
+		if rec.status < 500 && l.cfg.SampleRate < 1 && rand.Float64() >= l.cfg.SampleRate {
+			return
+		}
+		l.write(l.entry(r, rec, id, start))
+	})
+}
+
+// Identify records the authenticated user and org of the request for the
+// access log. Mount it after JWTAuth.
+func Identify(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := r.Context().Value(identityKey{}).(*identity); ok {
+			if claims, ok := middleware.GetClaims(r.Context()); ok {
+				id.userID, id.orgID = claims.UserID, claims.OrgID
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *Logger) entry(r *http.Request, rec *recorder, id *identity, start time.Time) map[string]interface{} {
+	e := make(map[string]interface{}, len(l.fields))
+	set := func(field string, value interface{}) {
+		if l.fields[field] {
+			e[field] = value
+		}
+	}
+	set(FieldTime, start.UTC().Format(time.RFC3339Nano))
+	set(FieldMethod, r.Method)
+	set(FieldPath, l.path(r.URL.Path))
+	if r.URL.RawQuery != "" {
+		set(FieldQuery, l.query(r.URL.Query()))
+	}
+	set(FieldStatus, rec.status)
+	set(FieldBytes, rec.bytes)
+	set(FieldDuration, float64(time.Since(start).Microseconds())/1000)
+	set(FieldRemoteIP, security.ClientIP(r, l.cfg.Proxies))
+	if ua := r.UserAgent(); ua != "" {
+		set(FieldUserAgent, ua)
+	}
+	if ref := r.Referer(); ref != "" {
+		set(FieldReferer, l.referer(ref))
+	}
+	if id.userID != "" {
+		set(FieldUserID, id.userID)
+		set(FieldOrgID, id.orgID)
+	}
+	return e
+}
+
+func (l *Logger) write(e map[string]interface{}) {
+	var line bytes.Buffer
+	enc := json.NewEncoder(&line)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(e); err != nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out.Write(line.Bytes())
+}
+
+var (
+	// credentialSegment matches path segments that carry a credential:
+	// long opaque tokens and prefixed API or OAuth keys.
+	credentialSegment = regexp.MustCompile(`^(?:[A-Za-z0-9_.~-]{32,}|(?:aurea|aot|art|aoc)_.+)$`)
+	emailPattern      = regexp.MustCompile(`([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+	// sensitiveParams are query parameters whose values are credentials.
+	sensitiveParams = []string{"token", "key", "secret", "password", "code", "assertion", "signature", "sig", "state", "code_verifier"}
+)
+
+const redacted = "[REDACTED]"
+
+func (l *Logger) path(p string) string {
+	if !l.cfg.Redact {
+		return p
+	}
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		if credentialSegment.MatchString(s) {
+			segments[i] = redacted
+		} else {
+			segments[i] = maskEmails(s)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func (l *Logger) query(q url.Values) string {
+	if !l.cfg.Redact {
+		return q.Encode()
+	}
+	for name, values := range q {
+		lower := strings.ToLower(name)
+		sensitive := false
+		for _, s := range sensitiveParams {
+			sensitive = sensitive || lower == s || strings.HasSuffix(lower, "_"+s)
+		}
+		for i, v := range values {
+			if sensitive {
+				values[i] = redacted
+			} else {
+				values[i] = maskEmails(v)
+			}
+		}
+	}
+	// Encode would escape the brackets of the placeholder.
+	return strings.ReplaceAll(q.Encode(), url.QueryEscape(redacted), redacted)
+}
+
+func (l *Logger) referer(ref string) string {
+	if !l.cfg.Redact {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return redacted
+	}
+	u.Path = l.path(u.Path)
+	u.RawPath = ""
+	u.RawQuery = l.query(u.Query())
+	u.User = nil
+	u.Fragment = ""
+	return u.String()
+}
+
+// maskEmails keeps the first character and domain of email addresses,
+// e.g. a***@example.com.
+func maskEmails(s string) string {
+	if !strings.Contains(s, "@") {
+		return s
+	}
+	return emailPattern.ReplaceAllString(s, "$1***@$2")
+}
+
+// recorder captures the status and size of a response.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = code, true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Flush supports streaming responses such as the replication stream.
+func (r *recorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack supports connection upgrades.
+func (r *recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("accesslog: response does not support hijacking")
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns the underlying ResponseWriter.
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
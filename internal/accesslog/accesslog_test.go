@@ -0,0 +1,194 @@
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		target  string
+		header  map[string]string
+		remote  string
+		claims  *auth.Claims
+		status  int
+		want    map[string]interface{}
+		omitted []string
+	}{
+		{
+			name:   "default fields",
+			target: "/api/reviews/r1",
+			claims: &auth.Claims{UserID: "u1", OrgID: "acme"},
+			status: http.StatusCreated,
+			want:   map[string]interface{}{"method": "GET", "path": "/api/reviews/r1", "status": 201.0, "bytes": 2.0, "user_id": "u1", "org_id": "acme"},
+			// Not among the default fields.
+			omitted: []string{"query", "remote_ip", "user_agent"},
+		},
+		{
+			name:    "anonymous request",
+			target:  "/healthz",
+			want:    map[string]interface{}{"path": "/healthz"},
+			omitted: []string{"user_id", "org_id"},
+		},
+		{
+			name:   "credentials in the path",
+			cfg:    Config{Redact: true},
+			target: "/api/share/0123456789abcdef0123456789abcdef/aurea_secretkey/alice@acme.test",
+			want:   map[string]interface{}{"path": "/api/share/[REDACTED]/[REDACTED]/a***@acme.test"},
+		},
+		{
+			name:   "credentials in the query",
+			cfg:    Config{Redact: true, Fields: []string{FieldQuery}},
+			target: "/oauth/callback?code=abc&state=xyz&access_token=t&email=alice@acme.test&page=2",
+			want:   map[string]interface{}{"query": "access_token=[REDACTED]&code=[REDACTED]&email=a%2A%2A%2A%40acme.test&page=2&state=[REDACTED]"},
+		},
+		{
+			name:   "unredacted",
+			cfg:    Config{Fields: []string{FieldPath, FieldQuery}},
+			target: "/api/users/alice@acme.test?token=t",
+			want:   map[string]interface{}{"path": "/api/users/alice@acme.test", "query": "token=t"},
+		},
+		{
+			name:   "referer",
+			cfg:    Config{Redact: true, Fields: []string{FieldReferer, FieldUserAgent}},
+			target: "/api/reviews",
+			header: map[string]string{"Referer": "https://bob:pw@aurea.example/reset-password?token=t#frag", "User-Agent": "curl/8"},
+			want:   map[string]interface{}{"referer": "https://aurea.example/reset-password?token=[REDACTED]", "user_agent": "curl/8"},
+		},
+		{
+			name:   "client behind a trusted proxy",
+			cfg:    Config{Fields: []string{FieldRemoteIP}, Proxies: security.TrustedProxies{netip.MustParsePrefix("10.0.0.0/8")}},
+			target: "/api/reviews",
+			remote: "10.0.0.2:4000",
+			header: map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7"},
+			want:   map[string]interface{}{"remote_ip": "203.0.113.7"},
+		},
+		{
+			name:   "forwarded header without a proxy",
+			cfg:    Config{Fields: []string{FieldRemoteIP}},
+			target: "/api/reviews",
+			remote: "198.51.100.9:4000",
+			header: map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			want:   map[string]interface{}{"remote_ip": "198.51.100.9"},
+		},
+		{
+			name:   "server error while sampling",
+			cfg:    Config{SampleRate: 1e-12},
+			target: "/api/reviews",
+			status: http.StatusInternalServerError,
+			want:   map[string]interface{}{"status": 500.0},
+		},
+		{
+			name:   "success while sampling",
+			cfg:    Config{SampleRate: 1e-12},
+			target: "/api/reviews",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.cfg.SampleRate == 0 {
+				tt.cfg.SampleRate = 1
+			}
+			var out bytes.Buffer
+			l, err := New(&out, tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			app := Identify(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				w.Write([]byte("ok"))
+			}))
+			h := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.claims != nil {
+					r = r.WithContext(middleware.WithClaims(r.Context(), tt.claims))
+				}
+				app.ServeHTTP(w, r)
+			}))
+			req := httptest.NewRequest("GET", tt.target, nil)
+			if tt.remote != "" {
+				req.RemoteAddr = tt.remote
+			}
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.want == nil {
+				if out.Len() != 0 {
+					t.Errorf("logged %s", out.String())
+				}
+				return
+			}
+			var entry map[string]interface{}
+			if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+				t.Fatalf("line %q: %v", out.String(), err)
+			}
+			for field, want := range tt.want {
+				if entry[field] != want {
+					t.Errorf("%s = %v, want %v", field, entry[field], want)
+				}
+			}
+			for _, field := range tt.omitted {
+				if v, ok := entry[field]; ok {
+					t.Errorf("%s = %v, want it left out", field, v)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"unknown field", Config{Fields: []string{"password"}, SampleRate: 1}},
+		{"no sampling", Config{SampleRate: 0}},
+		{"sample rate above one", Config{SampleRate: 1.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(&bytes.Buffer{}, tt.cfg); err == nil {
+				t.Error("New accepted the config")
+			}
+		})
+	}
+}
+
+func TestRotating(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	r, err := OpenRotating(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"first 0\n", "second 0\n", "third 00\n", "fourth 0\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	matches, _ := filepath.Glob(path + "*")
+	if len(matches) != 3 {
+		t.Errorf("files = %v, want the current one and 2 rotated", matches)
+	}
+	if current, _ := os.ReadFile(path); string(current) != "fourth 0\n" {
+		t.Errorf("current file = %q", current)
+	}
+}
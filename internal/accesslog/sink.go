@@ -0,0 +1,116 @@
+package accesslog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// OpenSink opens the destination named by spec:
+//
+//	stdout                      standard output
+//	file:/var/log/access.log    a file, rotated at maxSize bytes keeping keep old files
+//	syslog                      the local syslog daemon
+//	syslog:udp://host:514       a remote syslog server
+func OpenSink(spec string, maxSize int64, keep int) (io.WriteCloser, error) {
+	switch {
+	case spec == "stdout":
+		return nopCloser{os.Stdout}, nil
+	case strings.HasPrefix(spec, "file:"):
+		return OpenRotating(strings.TrimPrefix(spec, "file:"), maxSize, keep)
+	case spec == "syslog":
+		return openSyslog("", "")
+	case strings.HasPrefix(spec, "syslog:"):
+		network, addr, ok := strings.Cut(strings.TrimPrefix(spec, "syslog:"), "://")
+		if !ok {
+			return nil, fmt.Errorf("syslog sink must look like syslog:udp://host:514")
+		}
+		return openSyslog(network, addr)
+	}
+	return nil, fmt.Errorf("unknown access log sink %q", spec)
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// Rotating is a file that is rotated once it exceeds a size: access.log
+// becomes access.log.1, access.log.1 becomes access.log.2 and so on, and
+// the oldest beyond keep is removed.
+type Rotating struct {
+	path    string
+	maxSize int64
+	keep    int
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// OpenRotating opens (appending to) a rotating file.
+func OpenRotating(path string, maxSize int64, keep int) (*Rotating, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	r := &Rotating{path: path, maxSize: maxSize, keep: keep}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Rotating) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if p would take the file past its limit.
+func (r *Rotating) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *Rotating) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	os.Remove(fmt.Sprintf("%s.%d", r.path, r.keep))
+	for i := r.keep - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if r.keep > 0 {
+		if err := os.Rename(r.path, r.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+// Close closes the file.
+func (r *Rotating) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
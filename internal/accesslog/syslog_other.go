@@ -0,0 +1,12 @@
+//go:build windows || plan9
+
+package accesslog
+
+import (
+	"errors"
+	"io"
+)
+
+func openSyslog(network, addr string) (io.WriteCloser, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,191 @@
+// Package accessreview runs recertification campaigns: an organization's
+// admins confirm, before a deadline, that each member still needs their
+// role. Members nobody certified are downgraded when the deadline passes.
+package accessreview
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// ReminderWindow is how long before the deadline admins start getting a
+// daily reminder about uncertified members.
+const ReminderWindow = 3 * 24 * time.Hour
+
+// NotifyReminder is the notification type of campaign reminders.
+const NotifyReminder = "access_review.reminder"
+
+var ranks = map[string]int{"guest": 0, "member": 1, "reviewer": 2, "admin": 3}
+
+// Outranks reports whether role a grants more access than role b.
+func Outranks(a, b string) bool {
+	return ranks[a] > ranks[b]
+}
+
+// Start opens a campaign covering every member of the organization whose
+// role outranks the campaign's fallback role.
+func Start(st *store.Memory, c models.AccessCampaign) (models.AccessCampaign, []models.AccessItem) {
+	var items []models.AccessItem
+	for _, u := range st.ListOrgUsers(c.OrgID) {
+		if Outranks(u.Role, c.FallbackRole) {
+			items = append(items, models.AccessItem{UserID: u.ID, Role: u.Role})
+		}
+	}
+	c = st.AddAccessCampaign(c, items)
+	st.AppendAudit(&models.AuditEvent{
+		OrgID:      c.OrgID,
+		ActorID:    c.CreatedBy,
+		Action:     "access_review.started",
+		TargetType: "access_review",
+		TargetID:   c.ID,
+		Metadata:   map[string]string{"deadline": c.Deadline.Format(time.RFC3339), "members": fmt.Sprint(len(items))},
+	})
+	return c, st.ListAccessItems(c.ID)
+}
+
+// Progress counts a campaign's items by decision.
+func Progress(st *store.Memory, campaignID string) models.AccessProgress {
+	var p models.AccessProgress
+	for _, item := range st.ListAccessItems(campaignID) {
+		p.Add(item)
+	}
+	return p
+}
+
+// Runner enforces campaign deadlines and sends reminders.
+type Runner struct {
+	st       *store.Memory
+	interval time.Duration
+}
+
+// New creates a runner that checks campaigns every interval.
+func New(st *store.Memory, interval time.Duration) *Runner {
+	return &Runner{st: st, interval: interval}
+}
+
+// Run checks open campaigns every interval until ctx is cancelled.
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.Check(now)
+		}
+	}
+}
+
+// Check closes the campaigns whose deadline has passed at now, downgrading
+// uncertified members, and reminds admins of campaigns about to close.
+func (r *Runner) Check(now time.Time) {
+	for _, c := range r.st.ListAccessCampaigns("") {
+		switch {
+		case !now.Before(c.Deadline):
+			r.expire(c, now)
+		case c.Deadline.Sub(now) <= ReminderWindow && (c.LastReminder == nil || now.Sub(*c.LastReminder) >= 24*time.Hour):
+			r.remind(c, now)
+		}
+	}
+}
+
+func (r *Runner) expire(c models.AccessCampaign, now time.Time) {
+	for _, item := range r.st.ListAccessItems(c.ID) {
+		if item.Decision != models.AccessPending {
+			continue
+		}
+		decided := models.AccessItem{DecidedBy: "system", DecidedAt: &now}
+		user, err := r.st.GetUser(item.UserID)
+		switch {
+		case err != nil || user.OrgID != c.OrgID:
+			decided.Decision, decided.Reason = models.AccessChanged, "member left the organization"
+		case user.Role != item.Role:
+			decided.Decision, decided.NewRole, decided.Reason = models.AccessChanged, user.Role, "role changed outside the campaign"
+		case user.Role == "admin" && r.lastAdmin(c.OrgID, user.ID):
+			// Never leave an organization without an admin.
+			decided.Decision, decided.Reason = models.AccessCertified, "kept as the organization's only admin"
+		default:
+			// Tokens carry the role they were issued with, so the member's
+			// sessions end with the downgrade.
+			decided.Decision, decided.NewRole, decided.Reason = models.AccessDowngraded, c.FallbackRole, "not re-certified before the deadline"
+			downgrade := func(tx *store.Tx) error {
+				if _, err := tx.SetUserRole(c.OrgID, user.ID, c.FallbackRole); err != nil {
+					return err
+				}
+				tx.EndUserSessions(user.ID, "role downgraded", now)
+				tx.AppendAudit(&models.AuditEvent{
+					OrgID:      c.OrgID,
+					ActorID:    "system",
+					Action:     "role.downgraded",
+					TargetType: "user",
+					TargetID:   user.ID,
+					Reason:     fmt.Sprintf("not re-certified in access review %q", c.Name),
+					Metadata:   map[string]string{"campaign_id": c.ID, "from": item.Role, "to": c.FallbackRole},
+				})
+				_, err := tx.DecideAccessItem(c.ID, item.UserID, decided)
+				return err
+			}
+			if err := r.st.Update(downgrade); err != nil {
+				log.Printf("Cannot downgrade %s in access review %s: %v", user.ID, c.ID, err)
+			}
+			continue
+		}
+		r.st.DecideAccessItem(c.ID, item.UserID, decided)
+	}
+	r.st.CompleteAccessCampaign(c.ID, now)
+	r.st.AppendAudit(&models.AuditEvent{
+		OrgID:      c.OrgID,
+		ActorID:    "system",
+		Action:     "access_review.completed",
+		TargetType: "access_review",
+		TargetID:   c.ID,
+	})
+
+	if c.RepeatDays > 0 {
+		next := models.AccessCampaign{
+			OrgID:        c.OrgID,
+			Name:         c.Name,
+			FallbackRole: c.FallbackRole,
+			Deadline:     c.Deadline.AddDate(0, 0, c.RepeatDays),
+			RepeatDays:   c.RepeatDays,
+			CreatedBy:    "system",
+			CreatedAt:    now,
+		}
+		Start(r.st, next)
+	}
+}
+
+// lastAdmin reports whether userID is the organization's only admin.
+func (r *Runner) lastAdmin(orgID, userID string) bool {
+	for _, u := range r.st.ListOrgUsers(orgID) {
+		if u.Role == "admin" && u.ID != userID {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Runner) remind(c models.AccessCampaign, now time.Time) {
+	if Progress(r.st, c.ID).Pending == 0 {
+		return
+	}
+	for _, u := range r.st.ListOrgUsers(c.OrgID) {
+		if u.Role != "admin" {
+			continue
+		}
+		r.st.AddNotification(&models.Notification{
+			UserID:    u.ID,
+			OrgID:     c.OrgID,
+			Type:      NotifyReminder,
+			ActorID:   "system",
+			CreatedAt: now,
+		})
+	}
+	r.st.MarkAccessReminder(c.ID, now)
+}
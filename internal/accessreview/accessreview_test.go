@@ -0,0 +1,67 @@
+package accessreview
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/tokenbind"
+)
+
+func TestExpireEndsSessions(t *testing.T) {
+	st := store.NewMemory()
+	tokens := auth.NewService("secret", 24*time.Hour)
+	now := time.Now()
+	login := func(t *testing.T, email string) (*models.User, string) {
+		t.Helper()
+		u := &models.User{Email: email, Role: "admin", OrgID: "acme", CreatedAt: now}
+		if err := st.CreateUser(u); err != nil {
+			t.Fatal(err)
+		}
+		token, err := tokens.GenerateToken(u.ID, u.Email, u.OrgID, u.Role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		st.AddSession(models.Session{UserID: u.ID, OrgID: "acme", TokenHash: tokenbind.TokenHash(token), CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(24 * time.Hour)})
+		return u, token
+	}
+	alice, aliceToken := login(t, "alice@acme.test")
+	bob, bobToken := login(t, "bob@acme.test")
+
+	c, _ := Start(st, models.AccessCampaign{OrgID: "acme", Name: "Q3", FallbackRole: "member", Deadline: now.Add(time.Hour), CreatedBy: alice.ID, CreatedAt: now})
+	if _, err := st.DecideAccessItem(c.ID, alice.ID, models.AccessItem{Decision: models.AccessCertified, DecidedBy: bob.ID, DecidedAt: &now}); err != nil {
+		t.Fatal(err)
+	}
+	New(st, time.Minute).Check(now.Add(2 * time.Hour))
+
+	api := middleware.JWTAuth("secret")(middleware.Sessions(st, nil)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+	tests := []struct {
+		name  string
+		user  *models.User
+		token string
+		role  string
+		want  int
+	}{
+		{"certified", alice, aliceToken, "admin", http.StatusOK},
+		{"downgraded", bob, bobToken, "member", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if u, _ := st.GetUser(tt.user.ID); u.Role != tt.role {
+				t.Errorf("role = %q, want %q", u.Role, tt.role)
+			}
+			req := httptest.NewRequest("GET", "/api/me", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			api.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status with the old token = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
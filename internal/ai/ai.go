@@ -0,0 +1,66 @@
+// Package ai implements the review features backed by language models:
+// it builds their prompts from reviews and reads the models' answers.
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// maxReviewChars bounds how much of a review and its comments a prompt
+// quotes, keeping requests within the models' context windows.
+const maxReviewChars = 24000
+
+// reviewText renders a review and its discussion for a prompt, cutting
+// the oldest comments first, then the content, when it is too long.
+func reviewText(review *models.Review, comments []*models.Comment) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Title: %s\nStatus: %s\n\n", review.Title, review.Status)
+	fmt.Fprintf(&b, "Content:\n%s\n", reviewContent(review))
+	if discussion := discussionText(comments, maxReviewChars-b.Len()); discussion != "" {
+		b.WriteString("\nComments, oldest first:\n")
+		b.WriteString(discussion)
+	}
+	return b.String()
+}
+
+// reviewContent returns the content of a review, cut to half the prompt
+// budget.
+func reviewContent(review *models.Review) string {
+	if len(review.Content) > maxReviewChars/2 {
+		return review.Content[:maxReviewChars/2] + "\n[truncated]"
+	}
+	return review.Content
+}
+
+// discussionText renders comments one per line, oldest first, leaving out
+// the oldest ones beyond size bytes.
+func discussionText(comments []*models.Comment, size int) string {
+	var lines []string
+	for i := len(comments) - 1; i >= 0; i-- {
+		line := fmt.Sprintf("- %s: %s\n", comments[i].AuthorID, comments[i].Body)
+		if len(line) > size {
+			lines = append(lines, "- [earlier comments omitted]\n")
+			break
+		}
+		size -= len(line)
+		lines = append(lines, line)
+	}
+	slices.Reverse(lines)
+	return strings.Join(lines, "")
+}
+
+// decodeJSON reads the JSON object in a model's answer, which may be
+// wrapped in a Markdown code fence or surrounded by prose.
+func decodeJSON(text string, v interface{}) error {
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return errors.New("answer has no JSON object")
+	}
+	return json.Unmarshal([]byte(text[start:end+1]), v)
+}
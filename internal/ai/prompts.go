@@ -0,0 +1,149 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// maxPromptSize bounds each template of a custom prompt.
+const maxPromptSize = 16 << 10
+
+// PromptVariable documents a variable a feature's prompt can use.
+type PromptVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// promptFeature is what a feature's prompts are built from.
+type promptFeature struct {
+	defaults  models.PromptTemplate
+	format    string
+	variables []PromptVariable
+}
+
+var promptFeatures = map[string]promptFeature{
+	models.AIFeatureSummary: {
+		defaults: models.PromptTemplate{
+			Feature: models.AIFeatureSummary,
+			System: `You summarize code and document reviews for busy reviewers.
+The summary is at most five sentences on what the review changes and where the discussion stands.
+Risks lists at most five short highlights of what deserves close attention, such as security, data loss, breaking changes or open disagreements.`,
+			Prompt: `{{.review}}`,
+		},
+		format: `Answer with a JSON object only: {"summary": "...", "risks": ["...", ...]}. Use an empty risks list when there are none.`,
+		variables: []PromptVariable{
+			{Name: "review", Description: "The title, status, content and comments of the review, shortened to fit"},
+			{Name: "title", Description: "The review title"},
+			{Name: "status", Description: "The review status"},
+			{Name: "content", Description: "The review content, shortened to fit"},
+			{Name: "comments", Description: "The comments, oldest first, one per line; the oldest are left out when too long"},
+		},
+	},
+	models.AIFeatureSuggestions: {
+		defaults: models.PromptTemplate{
+			Feature: models.AIFeatureSuggestions,
+			System: `You help route code and document reviews.
+Given a review, the candidate reviewers and the labels in use, pick the reviewers best placed to review it and the labels that fit it.`,
+			Prompt: `{{.review}}
+{{- if .labels}}
+
+Current labels: {{.labels}}
+{{- end}}
+
+Candidate reviewers:
+{{.reviewers}}
+
+{{if .vocabulary -}}
+Labels in use: {{.vocabulary}}
+{{- else -}}
+No labels are in use yet; propose short lowercase ones.
+{{- end}}`,
+		},
+		format: `Answer with a JSON object only:
+{"reviewers": [{"user_id": "...", "reason": "...", "confidence": 0.0}], "labels": [{"label": "...", "reason": "...", "confidence": 0.0}]}
+Only use user IDs from the candidate list and, when labels are listed, only those labels. Suggest at most three of each; confidence is from 0 to 1; reasons are one short sentence.`,
+		variables: []PromptVariable{
+			{Name: "review", Description: "The title, status and content of the review, shortened to fit"},
+			{Name: "title", Description: "The review title"},
+			{Name: "labels", Description: "The review's current labels, comma separated; empty when it has none"},
+			{Name: "reviewers", Description: "The candidate reviewers with their user ID, name, expertise and open reviews, one per line"},
+			{Name: "vocabulary", Description: "The labels the organization uses, comma separated; empty when it has none yet"},
+		},
+	},
+}
+
+// DefaultPrompt returns the built-in prompt of a feature.
+func DefaultPrompt(feature string) (models.PromptTemplate, bool) {
+	f, ok := promptFeatures[feature]
+	return f.defaults, ok
+}
+
+// PromptVariables returns the variables a feature's prompt can use.
+func PromptVariables(feature string) []PromptVariable {
+	return promptFeatures[feature].variables
+}
+
+// ActivePrompt returns the prompt an organization uses for a feature: its
+// own version in use, or the built-in default.
+func ActivePrompt(st *store.Memory, orgID, feature string) models.PromptTemplate {
+	if t, ok := st.ActivePrompt(orgID, feature); ok {
+		return t
+	}
+	t, _ := DefaultPrompt(feature)
+	return t
+}
+
+// ValidatePrompt checks that a custom prompt parses and renders with the
+// feature's variables.
+func ValidatePrompt(t models.PromptTemplate) error {
+	f, ok := promptFeatures[t.Feature]
+	if !ok {
+		return fmt.Errorf("unknown AI feature %q", t.Feature)
+	}
+	if strings.TrimSpace(t.System) == "" || strings.TrimSpace(t.Prompt) == "" {
+		return errors.New("system and prompt are required")
+	}
+	if len(t.System) > maxPromptSize || len(t.Prompt) > maxPromptSize {
+		return fmt.Errorf("system and prompt must be at most %d bytes each", maxPromptSize)
+	}
+	sample := make(map[string]string, len(f.variables))
+	for _, v := range f.variables {
+		sample[v.Name] = ""
+	}
+	_, err := renderPrompt(t, sample)
+	return err
+}
+
+// RenderedPrompt is what a feature sends a model.
+type RenderedPrompt struct {
+	System string `json:"system"`
+	Prompt string `json:"prompt"`
+}
+
+// renderPrompt fills a prompt's templates with vars and appends the
+// answer format of its feature.
+func renderPrompt(t models.PromptTemplate, vars map[string]string) (RenderedPrompt, error) {
+	var out RenderedPrompt
+	for _, part := range []struct {
+		name string
+		text string
+		dst  *string
+	}{{"system", t.System, &out.System}, {"prompt", t.Prompt, &out.Prompt}} {
+		tmpl, err := template.New(part.name).Option("missingkey=error").Parse(part.text)
+		if err != nil {
+			return RenderedPrompt{}, err
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, vars); err != nil {
+			return RenderedPrompt{}, err
+		}
+		*part.dst = b.String()
+	}
+	out.System = strings.TrimSpace(out.System) + "\n" + promptFeatures[t.Feature].format
+	return out, nil
+}
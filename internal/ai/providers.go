@@ -0,0 +1,336 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/llm"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// healthTimeout bounds a provider health check.
+const healthTimeout = 10 * time.Second
+
+// ErrBudgetExceeded is returned when an organization has spent its
+// monthly AI budget.
+var ErrBudgetExceeded = errors.New("ai: monthly budget exceeded")
+
+// Choice is the provider picked for a request and what it charges.
+type Choice struct {
+	Provider llm.Provider
+	// prices are the configured prices of the providers the request may
+	// fail over to, by name; the known price of the model that answered
+	// is used for the others.
+	prices map[string]llm.Price
+}
+
+// Providers resolves the language model providers of an organization's
+// requests, keeps track of their health, accounts for their cost and
+// enforces budgets.
+type Providers struct {
+	st       *store.Memory
+	client   *http.Client
+	instance *llm.Registry
+
+	mu     sync.Mutex
+	health map[string]ProviderHealth
+}
+
+// NewProviders creates a resolver that prefers the providers each
+// organization configured, calling them through client, and falls back
+// to the instance's.
+func NewProviders(st *store.Memory, client *http.Client, instance *llm.Registry) *Providers {
+	return &Providers{st: st, client: client, instance: instance, health: make(map[string]ProviderHealth)}
+}
+
+// candidate is a provider a request may go to.
+type candidate struct {
+	// key identifies the provider's health: the organization's ID, or
+	// "instance", and its name.
+	key      string
+	provider llm.Provider
+	price    llm.Price
+}
+
+// candidates returns the organization's providers called name, or all of
+// them when name is empty, in order of preference: its own by priority,
+// then the instance's.
+func (ps *Providers) candidates(orgID, name string) ([]candidate, error) {
+	var out []candidate
+	for _, cfg := range ps.st.ListLLMProviders(orgID) {
+		if cfg.Disabled || (name != "" && cfg.Name != name) {
+			continue
+		}
+		p, err := llm.FromConfig(ps.client, cfg)
+		if err != nil {
+			if name != "" {
+				return nil, err
+			}
+			// Configurations are checked when saved
+			continue
+		}
+		out = append(out, candidate{
+			key:      orgID + "/" + cfg.Name,
+			provider: p,
+			price:    llm.Price{Input: cfg.InputPrice, Output: cfg.OutputPrice},
+		})
+	}
+	for _, n := range ps.instance.Names() {
+		if name != "" && (n != name || len(out) > 0) {
+			continue
+		}
+		p, _ := ps.instance.Get(n)
+		out = append(out, candidate{key: "instance/" + n, provider: p})
+	}
+	if len(out) == 0 {
+		return nil, llm.ErrNoProvider
+	}
+	return out, nil
+}
+
+// For returns the organization's provider called name or, when name is
+// empty, one that tries its providers in order of preference, healthy
+// ones first, failing over to the next when one fails. Prompts are
+// redacted as the organization's policy asks.
+func (ps *Providers) For(orgID, name string) (Choice, error) {
+	candidates, err := ps.candidates(orgID, name)
+	if err != nil {
+		return Choice{}, err
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return ps.healthy(candidates[i].key) && !ps.healthy(candidates[j].key)
+	})
+
+	policy := ps.st.GetAIRedactionPolicy(orgID)
+	var redactor *Redactor
+	if policy.Enabled {
+		if redactor, err = NewRedactor(policy, ps.st.GetSecretPolicy(orgID)); err != nil {
+			return Choice{}, err
+		}
+	}
+	c := Choice{prices: make(map[string]llm.Price)}
+	for i := range candidates {
+		p := candidates[i].provider
+		if redactor != nil && (!llm.SelfHosted(p) || policy.SelfHosted) {
+			candidates[i].provider = redactingProvider{Provider: p, redactor: redactor, st: ps.st, orgID: orgID}
+		}
+		if _, ok := c.prices[p.Name()]; !ok {
+			c.prices[p.Name()] = candidates[i].price
+		}
+	}
+	if len(candidates) == 1 {
+		c.Provider = candidates[0].provider
+	} else {
+		c.Provider = failover{ps: ps, candidates: candidates}
+	}
+	return c, nil
+}
+
+// Names returns the providers available to an organization, in order of
+// preference.
+func (ps *Providers) Names(orgID string) []string {
+	candidates, _ := ps.candidates(orgID, "")
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = c.provider.Name()
+	}
+	return names
+}
+
+// Provider health states.
+const (
+	HealthUnknown   = "unknown"
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+)
+
+// ProviderHealth is what is known of whether a provider can serve
+// requests, from health checks and the requests sent to it. Why a
+// provider failed is only logged: the error may describe the network the
+// provider sits on, or echo what it answered.
+type ProviderHealth struct {
+	Provider  string    `json:"provider"`
+	Status    string    `json:"status"`
+	LatencyMS int64     `json:"latency_ms,omitempty"`
+	CheckedAt time.Time `json:"checked_at,omitempty"`
+}
+
+func (ps *Providers) healthy(key string) bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return ps.health[key].Status != HealthUnhealthy
+}
+
+func (ps *Providers) observe(key, name string, latency time.Duration, err error) {
+	h := ProviderHealth{Provider: name, Status: HealthHealthy, LatencyMS: latency.Milliseconds(), CheckedAt: time.Now()}
+	if err != nil {
+		h.Status = HealthUnhealthy
+		slog.Warn("llm provider unhealthy", "provider", key, "error", err)
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.health[key] = h
+}
+
+// Health returns the health of the providers available to an
+// organization, in order of preference.
+func (ps *Providers) Health(orgID string) []ProviderHealth {
+	candidates, _ := ps.candidates(orgID, "")
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	out := make([]ProviderHealth, len(candidates))
+	for i, c := range candidates {
+		h, ok := ps.health[c.key]
+		if !ok {
+			h = ProviderHealth{Provider: c.provider.Name(), Status: HealthUnknown}
+		}
+		out[i] = h
+	}
+	return out
+}
+
+// Check checks the health of the organization's provider called name now.
+func (ps *Providers) Check(ctx context.Context, orgID, name string) (ProviderHealth, error) {
+	candidates, err := ps.candidates(orgID, name)
+	if err != nil {
+		return ProviderHealth{}, err
+	}
+	ps.check(ctx, candidates[0])
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	h, ok := ps.health[candidates[0].key]
+	if !ok {
+		h = ProviderHealth{Provider: name, Status: HealthUnknown}
+	}
+	return h, nil
+}
+
+func (ps *Providers) check(ctx context.Context, c candidate) {
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+	start := time.Now()
+	err := llm.CheckHealth(ctx, c.provider)
+	if errors.Is(err, llm.ErrNoHealthCheck) {
+		return
+	}
+	ps.observe(c.key, c.provider.Name(), time.Since(start), err)
+}
+
+// Run checks the health of every configured provider each interval until
+// ctx is done.
+func (ps *Providers) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, n := range ps.instance.Names() {
+			p, _ := ps.instance.Get(n)
+			ps.check(ctx, candidate{key: "instance/" + n, provider: p})
+		}
+		for _, cfg := range ps.st.AllLLMProviders() {
+			if p, err := llm.FromConfig(ps.client, cfg); err == nil && !cfg.Disabled {
+				ps.check(ctx, candidate{key: cfg.OrgID + "/" + cfg.Name, provider: p})
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// failover sends a request to its candidates in turn until one answers.
+// Requests naming a model only go to the first, as the others may not
+// serve it.
+type failover struct {
+	ps         *Providers
+	candidates []candidate
+}
+
+// Name implements llm.Provider, naming the preferred provider.
+func (f failover) Name() string {
+	return f.candidates[0].provider.Name()
+}
+
+// Complete implements llm.Provider.
+func (f failover) Complete(ctx context.Context, req llm.Request) (llm.Response, error) {
+	var resp llm.Response
+	var err error
+	for i, c := range f.candidates {
+		if i > 0 && (req.Model != "" || ctx.Err() != nil) {
+			break
+		}
+		start := time.Now()
+		resp, err = c.provider.Complete(ctx, req)
+		resp.Provider = c.provider.Name()
+		if ctx.Err() == nil {
+			f.ps.observe(c.key, c.provider.Name(), time.Since(start), err)
+		}
+		if err == nil || resp.InputTokens > 0 {
+			break
+		}
+	}
+	return resp, err
+}
+
+// answeredBy names the provider that gave resp when p was asked.
+func answeredBy(p llm.Provider, resp llm.Response) string {
+	if resp.Provider != "" {
+		return resp.Provider
+	}
+	return p.Name()
+}
+
+// MonthStart returns the start of the calendar month (UTC) budgets count t in.
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// MonthCost returns what the organization's requests cost in the month of t.
+func (ps *Providers) MonthCost(orgID string, t time.Time) float64 {
+	from := MonthStart(t)
+	var cost float64
+	for _, u := range ps.st.ListLLMUsage(orgID, from, from.AddDate(0, 1, 0)) {
+		cost += u.CostUSD
+	}
+	return cost
+}
+
+// CheckBudget returns ErrBudgetExceeded once the organization has spent
+// its budget for the month.
+func (ps *Providers) CheckBudget(orgID string) error {
+	b, ok := ps.st.GetAIBudget(orgID)
+	if !ok {
+		return nil
+	}
+	if ps.MonthCost(orgID, time.Now()) >= b.MonthlyUSD {
+		return ErrBudgetExceeded
+	}
+	return nil
+}
+
+// Record accounts for the tokens and cost of a request made with c,
+// adding them to u. Requests that failed before using tokens are not
+// recorded.
+func (ps *Providers) Record(u models.LLMUsage, c Choice, resp llm.Response) {
+	if resp.InputTokens == 0 && resp.OutputTokens == 0 {
+		return
+	}
+	u.Provider = answeredBy(c.Provider, resp)
+	price := c.prices[u.Provider]
+	if price == (llm.Price{}) {
+		price, _ = llm.PriceOf(resp.Model)
+	}
+	u.Model = resp.Model
+	u.InputTokens = resp.InputTokens
+	u.OutputTokens = resp.OutputTokens
+	u.CostUSD = price.Cost(resp)
+	ps.st.RecordLLMUsage(u)
+}
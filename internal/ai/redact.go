@@ -0,0 +1,268 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/llm"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/secrets"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Redacted value kinds besides the PII kinds.
+const (
+	redactSecret = "secret"
+	redactCustom = "custom"
+)
+
+// piiDetector recognizes one kind of personal data. Valid, when set,
+// weeds out matches that only look like it, such as numbers failing a
+// checksum.
+type piiDetector struct {
+	re    *regexp.Regexp
+	valid func(string) bool
+}
+
+var piiDetectors = map[string]piiDetector{
+	models.PIIEmail: {re: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)},
+	models.PIIPhone: {re: regexp.MustCompile(`\+\d{8,15}\b|(?:\+\d{1,3}[ .-]?)?(?:\(\d{1,4}\)[ .-]?)?\b(?:\d{3}[ .-]\d{3}[ .-]\d{3,4}|\d{3}[ .-]\d{4})\b`)},
+	models.PIICreditCard: {
+		re:    regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		valid: luhn,
+	},
+	models.PIIIBAN: {
+		re:    regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`),
+		valid: ibanChecksum,
+	},
+	models.PIISSN: {re: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+	models.PIIIPAddress: {
+		re:    regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`),
+		valid: func(s string) bool { return net.ParseIP(s) != nil },
+	},
+}
+
+// luhn reports whether the digits of s pass the Luhn check of card numbers.
+func luhn(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			continue
+		}
+		d := int(s[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// ibanChecksum reports whether s passes the ISO 13616 mod-97 check.
+func ibanChecksum(s string) bool {
+	s = strings.ReplaceAll(s, " ", "")
+	var digits strings.Builder
+	for _, c := range s[4:] + s[:4] {
+		if c >= 'A' && c <= 'Z' {
+			fmt.Fprintf(&digits, "%d", c-'A'+10)
+		} else {
+			digits.WriteRune(c)
+		}
+	}
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// Redactor replaces the values an organization's policy covers with
+// placeholders.
+type Redactor struct {
+	secrets *secrets.Detector
+	custom  *secrets.Detector
+	pii     []string
+}
+
+// NewRedactor creates a redactor for an organization's AI redaction
+// policy, recognizing secrets with its secret detection policy.
+func NewRedactor(policy models.AIRedactionPolicy, secretPolicy models.SecretPolicy) (*Redactor, error) {
+	r := &Redactor{pii: policy.PII}
+	if policy.Secrets {
+		d, err := secrets.New(secretPolicy)
+		if err != nil {
+			return nil, err
+		}
+		r.secrets = d
+	}
+	if len(policy.Rules) > 0 {
+		builtin := make([]string, len(secrets.BuiltinRules))
+		for i, rule := range secrets.BuiltinRules {
+			builtin[i] = rule.ID
+		}
+		d, err := secrets.New(models.SecretPolicy{Rules: policy.Rules, DisabledRules: builtin})
+		if err != nil {
+			return nil, err
+		}
+		r.custom = d
+	}
+	return r, nil
+}
+
+// Start begins redacting the texts of one request.
+func (r *Redactor) Start() *Redaction {
+	return &Redaction{r: r, byValue: make(map[string]int), kinds: make(map[string]int)}
+}
+
+// redactedValue is a value a placeholder stands for.
+type redactedValue struct {
+	entry models.AIRedactionEntry
+	// restore is what the placeholder becomes in answers.
+	restore string
+}
+
+// Redaction is the map from placeholders to the values redacted from one
+// request's texts, so answers can be re-anchored. The same value gets the
+// same placeholder in every text.
+type Redaction struct {
+	r       *Redactor
+	values  []redactedValue
+	byValue map[string]int
+	kinds   map[string]int
+}
+
+type span struct {
+	start, end int
+	kind, rule string
+}
+
+// Redact returns text with the values the policy covers replaced by
+// placeholders such as [EMAIL_1].
+func (x *Redaction) Redact(text string) string {
+	var spans []span
+	if x.r.secrets != nil {
+		for _, m := range x.r.secrets.Matches(text) {
+			spans = append(spans, span{m.Start, m.End, redactSecret, m.RuleID})
+		}
+	}
+	if x.r.custom != nil {
+		for _, m := range x.r.custom.Matches(text) {
+			spans = append(spans, span{m.Start, m.End, redactCustom, m.RuleID})
+		}
+	}
+	for _, kind := range x.r.pii {
+		d, ok := piiDetectors[kind]
+		if !ok {
+			continue
+		}
+		for _, loc := range d.re.FindAllStringIndex(text, -1) {
+			if d.valid == nil || d.valid(text[loc[0]:loc[1]]) {
+				spans = append(spans, span{loc[0], loc[1], kind, ""})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return text
+	}
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end > spans[j].end
+	})
+
+	var b strings.Builder
+	last := 0
+	for _, s := range spans {
+		if s.start < last {
+			continue
+		}
+		b.WriteString(text[last:s.start])
+		b.WriteString(x.placeholder(text[s.start:s.end], s.kind, s.rule))
+		last = s.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func (x *Redaction) placeholder(value, kind, rule string) string {
+	key := kind + "\x00" + value
+	if i, ok := x.byValue[key]; ok {
+		x.values[i].entry.Count++
+		return x.values[i].entry.Placeholder
+	}
+	x.kinds[kind]++
+	v := redactedValue{
+		entry: models.AIRedactionEntry{
+			Placeholder: fmt.Sprintf("[%s_%d]", strings.ToUpper(kind), x.kinds[kind]),
+			Kind:        kind,
+			RuleID:      rule,
+			Preview:     secrets.Mask(value),
+			Fingerprint: secrets.Fingerprint(value),
+			Count:       1,
+		},
+		restore: value,
+	}
+	if kind == redactSecret {
+		v.restore = v.entry.Preview
+	}
+	x.byValue[key] = len(x.values)
+	x.values = append(x.values, v)
+	return v.entry.Placeholder
+}
+
+// Restore puts the redacted values back in place of the placeholders in an
+// answer, escaped for a JSON string when asJSON is set. Secrets come back
+// masked.
+func (x *Redaction) Restore(text string, asJSON bool) string {
+	if len(x.values) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, 2*len(x.values))
+	for _, v := range x.values {
+		restore := v.restore
+		if asJSON {
+			quoted, _ := json.Marshal(restore)
+			restore = string(quoted[1 : len(quoted)-1])
+		}
+		pairs = append(pairs, v.entry.Placeholder, restore)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// Entries returns what was redacted, without the values.
+func (x *Redaction) Entries() []models.AIRedactionEntry {
+	entries := make([]models.AIRedactionEntry, len(x.values))
+	for i, v := range x.values {
+		entries[i] = v.entry
+	}
+	return entries
+}
+
+// redactingProvider redacts the prompts sent to a provider and records
+// what it redacted.
+type redactingProvider struct {
+	llm.Provider
+	redactor *Redactor
+	st       *store.Memory
+	orgID    string
+}
+
+// Complete implements llm.Provider.
+func (p redactingProvider) Complete(ctx context.Context, req llm.Request) (llm.Response, error) {
+	x := p.redactor.Start()
+	req.System = x.Redact(req.System)
+	req.Prompt = x.Redact(req.Prompt)
+	resp, err := p.Provider.Complete(ctx, req)
+	resp.Text = x.Restore(resp.Text, req.JSON)
+	if entries := x.Entries(); len(entries) > 0 {
+		p.st.RecordAIRedaction(models.AIRedaction{OrgID: p.orgID, Provider: p.Name(), Entries: entries})
+	}
+	return resp, err
+}
@@ -0,0 +1,163 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/llm"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+const (
+	// suggestMaxTokens bounds the length of a suggestions answer.
+	suggestMaxTokens = 600
+	// maxSuggestions is how many reviewers, and how many labels, are kept.
+	maxSuggestions = 3
+	// minScore drops suggestions the model or the feedback barely supports.
+	minScore = 0.2
+	// maxLabelLength bounds labels the model invents when the organization
+	// has none yet.
+	maxLabelLength = 40
+)
+
+// ReviewerOption is a reviewer the model may suggest.
+type ReviewerOption struct {
+	UserID string
+	Name   string
+	// Skills are the reviewer's expertise areas.
+	Skills []string
+	// Open counts the unapproved reviews assigned to the reviewer.
+	Open int
+}
+
+// SuggestInput is what reviewer and label suggestions are made from.
+type SuggestInput struct {
+	Review    *models.Review
+	Labels    []string
+	Reviewers []ReviewerOption
+	// Vocabulary lists the labels the organization uses. When empty, the
+	// model may propose new ones.
+	Vocabulary []string
+	Feedback   []models.SuggestionFeedback
+}
+
+// Weight turns the feedback on suggestions of one value into the factor
+// their confidence is multiplied by: the share of them accepted, smoothed
+// so values without feedback weigh 1, doubled so the range is 0 to 2.
+func Weight(fb models.SuggestionFeedback) float64 {
+	return 2 * float64(fb.Accepted+1) / float64(fb.Accepted+fb.Rejected+2)
+}
+
+// SuggestPrompt renders the suggestions prompt t for in.
+func SuggestPrompt(t models.PromptTemplate, in SuggestInput) (RenderedPrompt, error) {
+	var reviewers strings.Builder
+	for _, r := range in.Reviewers {
+		skills := strings.Join(r.Skills, ", ")
+		if skills == "" {
+			skills = "none recorded"
+		}
+		fmt.Fprintf(&reviewers, "- user_id %s, %s; expertise: %s; open reviews: %d\n", r.UserID, r.Name, skills, r.Open)
+	}
+	return renderPrompt(t, map[string]string{
+		"review":     strings.TrimSpace(reviewText(in.Review, nil)),
+		"title":      in.Review.Title,
+		"labels":     strings.Join(in.Labels, ", "),
+		"reviewers":  strings.TrimSuffix(reviewers.String(), "\n"),
+		"vocabulary": strings.Join(in.Vocabulary, ", "),
+	})
+}
+
+// Suggest asks p for reviewers and labels for a review, using the prompt
+// t. Suggestions the input does not allow are dropped, the rest are scored with the
+// organization's feedback and the best kept. The response is returned
+// even when the answer cannot be read, so its tokens can be accounted for.
+func Suggest(ctx context.Context, p llm.Provider, model string, t models.PromptTemplate, in SuggestInput) ([]models.Suggestion, llm.Response, error) {
+	prompt, err := SuggestPrompt(t, in)
+	if err != nil {
+		return nil, llm.Response{}, fmt.Errorf("prompt version %d: %w", t.Version, err)
+	}
+	resp, err := p.Complete(ctx, llm.Request{
+		Model:     model,
+		System:    prompt.System,
+		Prompt:    prompt.Prompt,
+		MaxTokens: suggestMaxTokens,
+		JSON:      true,
+	})
+	if err != nil {
+		return nil, resp, fmt.Errorf("%s: %w", answeredBy(p, resp), err)
+	}
+	var answer struct {
+		Reviewers []struct {
+			UserID     string  `json:"user_id"`
+			Reason     string  `json:"reason"`
+			Confidence float64 `json:"confidence"`
+		} `json:"reviewers"`
+		Labels []struct {
+			Label      string  `json:"label"`
+			Reason     string  `json:"reason"`
+			Confidence float64 `json:"confidence"`
+		} `json:"labels"`
+	}
+	if err := decodeJSON(resp.Text, &answer); err != nil {
+		return nil, resp, fmt.Errorf("%s gave an answer that is not a list of suggestions", answeredBy(p, resp))
+	}
+
+	weights := make(map[string]float64)
+	for _, fb := range in.Feedback {
+		weights[fb.Kind+"/"+fb.Value] = Weight(fb)
+	}
+	now := time.Now()
+	var reviewers, labels []models.Suggestion
+	add := func(list []models.Suggestion, kind, value, reason string, confidence float64) []models.Suggestion {
+		if slices.ContainsFunc(list, func(s models.Suggestion) bool { return s.Value == value }) {
+			return list
+		}
+		confidence = min(max(confidence, 0), 1)
+		weight, ok := weights[kind+"/"+value]
+		if !ok {
+			weight = 1
+		}
+		score := confidence * weight
+		if score < minScore {
+			return list
+		}
+		return append(list, models.Suggestion{
+			OrgID:      in.Review.OrgID,
+			Kind:       kind,
+			Value:      value,
+			Reason:     strings.TrimSpace(reason),
+			Confidence: confidence,
+			Score:      score,
+			Provider:   answeredBy(p, resp),
+			Model:      resp.Model,
+			CreatedAt:  now,
+		})
+	}
+	for _, r := range answer.Reviewers {
+		if slices.ContainsFunc(in.Reviewers, func(o ReviewerOption) bool { return o.UserID == r.UserID }) {
+			reviewers = add(reviewers, models.SuggestReviewer, r.UserID, r.Reason, r.Confidence)
+		}
+	}
+	for _, l := range answer.Labels {
+		label := strings.TrimSpace(l.Label)
+		known := slices.Contains(in.Vocabulary, label)
+		invented := len(in.Vocabulary) == 0 && label != "" && len(label) <= maxLabelLength
+		if (known || invented) && !slices.Contains(in.Labels, label) {
+			labels = add(labels, models.SuggestLabel, label, l.Reason, l.Confidence)
+		}
+	}
+	return append(best(reviewers), best(labels)...), resp, nil
+}
+
+// best keeps the maxSuggestions highest scored suggestions.
+func best(list []models.Suggestion) []models.Suggestion {
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Score > list[j].Score })
+	if len(list) > maxSuggestions {
+		list = list[:maxSuggestions]
+	}
+	return list
+}
@@ -0,0 +1,100 @@
+package ai
+
+import (
+	"context"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/assign"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/operations"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// maxReviewerOptions bounds how many candidate reviewers a prompt lists,
+// best matches first.
+const maxReviewerOptions = 15
+
+// Suggester proposes reviewers and labels for new reviews, and for others
+// on request, as operations.
+type Suggester struct {
+	st        *store.Memory
+	runner    *operations.Runner
+	providers *Providers
+	engine    *assign.Engine
+}
+
+// NewSuggester creates a suggester that draws candidate reviewers from
+// engine and subscribes it to review events so new reviews get
+// suggestions.
+func NewSuggester(st *store.Memory, d *notify.Dispatcher, runner *operations.Runner, providers *Providers, engine *assign.Engine) *Suggester {
+	s := &Suggester{st: st, runner: runner, providers: providers, engine: engine}
+	d.Listen(s.handleEvent)
+	return s
+}
+
+func (s *Suggester) handleEvent(e notify.Event) {
+	if e.Type != notify.ReviewCreated {
+		return
+	}
+	review, err := s.st.GetReview(e.ReviewID)
+	if err != nil {
+		return
+	}
+	if _, disabled := s.st.KillSwitch(models.CapabilityAI, review.OrgID); disabled {
+		return
+	}
+	if s.providers.CheckBudget(review.OrgID) != nil {
+		return
+	}
+	c, err := s.providers.For(review.OrgID, "")
+	if err != nil {
+		return
+	}
+	s.Start(review, e.ActorID, c, "")
+}
+
+// Start queues suggestions for a review from c, replacing its pending
+// ones, on behalf of userID.
+func (s *Suggester) Start(review *models.Review, userID string, c Choice, model string) models.Operation {
+	usage := models.LLMUsage{
+		OrgID:    review.OrgID,
+		Feature:  models.AIFeatureSuggestions,
+		ReviewID: review.ID,
+		UserID:   userID,
+	}
+	return s.runner.Start(models.Operation{
+		OrgID:     review.OrgID,
+		Kind:      "review.suggest",
+		CreatedBy: userID,
+	}, func(ctx context.Context, t *operations.Task) (interface{}, error) {
+		current, err := s.st.GetReview(review.ID)
+		if err != nil {
+			return nil, err
+		}
+		prompt := ActivePrompt(s.st, review.OrgID, models.AIFeatureSuggestions)
+		suggestions, resp, err := Suggest(ctx, c.Provider, model, prompt, s.Input(current))
+		s.providers.Record(usage, c, resp)
+		if err != nil {
+			return nil, err
+		}
+		return s.st.ReplaceSuggestions(review.ID, suggestions), nil
+	})
+}
+
+// Input gathers what suggestions for a review are made from.
+func (s *Suggester) Input(review *models.Review) SuggestInput {
+	in := SuggestInput{
+		Review:     review,
+		Labels:     s.st.GetLabels(review.ID),
+		Vocabulary: s.st.OrgLabels(review.OrgID),
+		Feedback:   s.st.SuggestionFeedback(review.OrgID),
+	}
+	for _, c := range s.engine.Suggest(review, time.Now()) {
+		if len(in.Reviewers) == maxReviewerOptions {
+			break
+		}
+		in.Reviewers = append(in.Reviewers, ReviewerOption{UserID: c.User.ID, Name: c.User.Name, Skills: s.st.GetExpertise(c.User.ID), Open: c.Open})
+	}
+	return in
+}
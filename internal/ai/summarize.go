@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/llm"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// summaryMaxTokens bounds the length of a summary answer.
+const summaryMaxTokens = 800
+
+// SummaryPrompt renders the summary prompt t for a review.
+func SummaryPrompt(t models.PromptTemplate, review *models.Review, comments []*models.Comment) (RenderedPrompt, error) {
+	return renderPrompt(t, map[string]string{
+		"review":   strings.TrimSpace(reviewText(review, comments)),
+		"title":    review.Title,
+		"status":   review.Status,
+		"content":  reviewContent(review),
+		"comments": discussionText(comments, maxReviewChars/2),
+	})
+}
+
+// Summarize asks p for a summary of a review with its risk highlights,
+// using the prompt t. The response is returned even when the answer
+// cannot be read, so the tokens it used can be accounted for.
+func Summarize(ctx context.Context, p llm.Provider, model string, t models.PromptTemplate, review *models.Review, comments []*models.Comment) (models.ReviewSummary, llm.Response, error) {
+	prompt, err := SummaryPrompt(t, review, comments)
+	if err != nil {
+		return models.ReviewSummary{}, llm.Response{}, fmt.Errorf("prompt version %d: %w", t.Version, err)
+	}
+	resp, err := p.Complete(ctx, llm.Request{
+		Model:     model,
+		System:    prompt.System,
+		Prompt:    prompt.Prompt,
+		MaxTokens: summaryMaxTokens,
+		JSON:      true,
+	})
+	if err != nil {
+		return models.ReviewSummary{}, resp, fmt.Errorf("%s: %w", answeredBy(p, resp), err)
+	}
+	var answer struct {
+		Summary string   `json:"summary"`
+		Risks   []string `json:"risks"`
+	}
+	if err := decodeJSON(resp.Text, &answer); err != nil || strings.TrimSpace(answer.Summary) == "" {
+		return models.ReviewSummary{}, resp, fmt.Errorf("%s gave an answer that is not a summary", answeredBy(p, resp))
+	}
+	risks := []string{}
+	for _, r := range answer.Risks {
+		if r = strings.TrimSpace(r); r != "" {
+			risks = append(risks, r)
+		}
+	}
+	return models.ReviewSummary{
+		ReviewID:    review.ID,
+		Summary:     strings.TrimSpace(answer.Summary),
+		Risks:       risks,
+		Provider:    answeredBy(p, resp),
+		Model:       resp.Model,
+		GeneratedAt: time.Now(),
+	}, resp, nil
+}
@@ -0,0 +1,110 @@
+// Package assign picks reviewers for reviews.
+package assign
+
+import (
+	"sync"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// Candidate is a reviewer eligible for a review, with the signals
+// strategies weigh.
+type Candidate struct {
+	User *models.User
+	// Open counts the unapproved reviews currently assigned to the user.
+	Open int
+	// Skills lists the user's expertise areas that match the review's
+	// labels.
+	Skills []string
+	// Expertise scores how well the user's past approvals match the
+	// review's labels; higher is better.
+	Expertise int
+}
+
+// betterMatch reports whether a should be preferred over b: more
+// matching expertise areas, then more matching approvals, then fewer open
+// assignments.
+func betterMatch(a, b Candidate) bool {
+	if len(a.Skills) != len(b.Skills) {
+		return len(a.Skills) > len(b.Skills)
+	}
+	if a.Expertise != b.Expertise {
+		return a.Expertise > b.Expertise
+	}
+	return a.Open < b.Open
+}
+
+// Strategy chooses a reviewer for a review from the eligible candidates.
+type Strategy interface {
+	Pick(review *models.Review, candidates []Candidate) (*models.User, bool)
+}
+
+// RoundRobin cycles through candidates independently for each organization.
+type RoundRobin struct {
+	mu   sync.Mutex
+	next map[string]int
+}
+
+// NewRoundRobin creates a round-robin strategy.
+func NewRoundRobin() *RoundRobin {
+	return &RoundRobin{next: make(map[string]int)}
+}
+
+// Pick returns the next candidate in rotation, skipping the review's author.
+func (rr *RoundRobin) Pick(review *models.Review, candidates []Candidate) (*models.User, bool) {
+	eligible := excludeAuthor(review, candidates)
+	if len(eligible) == 0 {
+		return nil, false
+	}
+
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	i := rr.next[review.OrgID] % len(eligible)
+	rr.next[review.OrgID] = i + 1
+	return eligible[i].User, true
+}
+
+// LeastLoaded picks the candidate with the fewest open assignments.
+type LeastLoaded struct{}
+
+// Pick returns the least loaded candidate other than the review's author,
+// breaking ties by candidate order.
+func (LeastLoaded) Pick(review *models.Review, candidates []Candidate) (*models.User, bool) {
+	return best(review, candidates, func(a, b Candidate) bool { return a.Open < b.Open })
+}
+
+// ByExpertise picks the candidate whose expertise areas and past
+// approvals best match the review's labels, preferring the less loaded
+// among equals. Without any match it behaves like LeastLoaded.
+type ByExpertise struct{}
+
+// Pick returns the most experienced candidate other than the review's
+// author.
+func (ByExpertise) Pick(review *models.Review, candidates []Candidate) (*models.User, bool) {
+	return best(review, candidates, betterMatch)
+}
+
+// best returns the first eligible candidate no other is better than.
+func best(review *models.Review, candidates []Candidate, better func(a, b Candidate) bool) (*models.User, bool) {
+	eligible := excludeAuthor(review, candidates)
+	if len(eligible) == 0 {
+		return nil, false
+	}
+	pick := eligible[0]
+	for _, c := range eligible[1:] {
+		if better(c, pick) {
+			pick = c
+		}
+	}
+	return pick.User, true
+}
+
+func excludeAuthor(review *models.Review, candidates []Candidate) []Candidate {
+	eligible := make([]Candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.User.ID != review.AuthorID {
+			eligible = append(eligible, c)
+		}
+	}
+	return eligible
+}
@@ -0,0 +1,132 @@
+package assign
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Engine assigns reviews with the strategy each organization selects in
+// its policy, round-robin by default.
+type Engine struct {
+	st         *store.Memory
+	strategies map[string]Strategy
+}
+
+// NewEngine creates an engine with the built-in strategies.
+func NewEngine(st *store.Memory) *Engine {
+	return &Engine{st: st, strategies: map[string]Strategy{
+		models.AssignRoundRobin:  NewRoundRobin(),
+		models.AssignLeastLoaded: LeastLoaded{},
+		models.AssignExpertise:   ByExpertise{},
+	}}
+}
+
+// Candidates returns the reviewers of the review's organization available
+// at t, that is neither deactivated nor out of office, with their open
+// assignments and how their expertise matches the review's labels.
+func (e *Engine) Candidates(review *models.Review, t time.Time) []Candidate {
+	users := e.st.ListAvailableReviewers(review.OrgID, t)
+	labels := e.st.GetLabels(review.ID)
+	open := e.st.OpenAssignmentCounts(review.OrgID)
+	expertise := e.st.LabelExperience(review.OrgID, labels)
+	candidates := make([]Candidate, len(users))
+	for i, u := range users {
+		candidates[i] = Candidate{
+			User:      u,
+			Open:      open[u.ID],
+			Skills:    matchSkills(e.st.GetExpertise(u.ID), labels),
+			Expertise: expertise[u.ID],
+		}
+	}
+	return candidates
+}
+
+// Suggest ranks the candidates for a review, the author excluded: best
+// expertise match first, then least loaded.
+func (e *Engine) Suggest(review *models.Review, t time.Time) []Candidate {
+	candidates := excludeAuthor(review, e.Candidates(review, t))
+	sort.SliceStable(candidates, func(i, j int) bool { return betterMatch(candidates[i], candidates[j]) })
+	return candidates
+}
+
+// matchSkills returns the expertise areas that match one of labels,
+// ignoring case.
+func matchSkills(areas, labels []string) []string {
+	var matched []string
+	for _, area := range areas {
+		for _, l := range labels {
+			if strings.EqualFold(area, l) {
+				matched = append(matched, area)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// experts keeps the candidates, other than the review's author, tagged
+// with an expertise area matching the review's labels.
+func experts(review *models.Review, candidates []Candidate) []Candidate {
+	var out []Candidate
+	for _, c := range excludeAuthor(review, candidates) {
+		if len(c.Skills) > 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Pick chooses a reviewer with the organization's strategy and returns
+// the strategy used.
+func (e *Engine) Pick(review *models.Review, t time.Time) (*models.User, string, bool) {
+	name := e.strategyOf(review.OrgID)
+	u, ok := e.pick(name, review, "", t)
+	return u, name, ok
+}
+
+// Reassign chooses a reviewer other than currentID with the
+// organization's strategy.
+func (e *Engine) Reassign(review *models.Review, currentID string, t time.Time) (*models.User, bool) {
+	return e.pick(e.strategyOf(review.OrgID), review, currentID, t)
+}
+
+// PickWith chooses a reviewer with the named strategy.
+func (e *Engine) PickWith(name string, review *models.Review, t time.Time) (*models.User, bool) {
+	return e.pick(name, review, "", t)
+}
+
+func (e *Engine) strategyOf(orgID string) string {
+	name := e.st.GetPolicy(orgID).AssignmentStrategy
+	if e.strategies[name] == nil {
+		name = models.AssignRoundRobin
+	}
+	return name
+}
+
+// pick runs the named strategy over the candidates other than excludeID.
+// When some are tagged with expertise matching the review's labels, the
+// strategy chooses among them only.
+func (e *Engine) pick(name string, review *models.Review, excludeID string, t time.Time) (*models.User, bool) {
+	s, ok := e.strategies[name]
+	if !ok {
+		return nil, false
+	}
+	candidates := e.Candidates(review, t)
+	if excludeID != "" {
+		kept := candidates[:0]
+		for _, c := range candidates {
+			if c.User.ID != excludeID {
+				kept = append(kept, c)
+			}
+		}
+		candidates = kept
+	}
+	if matched := experts(review, candidates); len(matched) > 0 {
+		candidates = matched
+	}
+	return s.Pick(review, candidates)
+}
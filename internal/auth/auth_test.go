@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestParseToken(t *testing.T) {
+	s := NewService("secret", time.Hour)
+	valid, err := s.GenerateToken("u1", "u1@acme.test", "acme", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, _ := NewService("secret", -time.Minute).GenerateToken("u1", "", "acme", "admin")
+	otherKey, _ := NewService("other", time.Hour).GenerateToken("u1", "", "acme", "admin")
+	none, _ := jwt.NewWithClaims(jwt.SigningMethodNone, &Claims{
+		UserID:           "u1",
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	noExpiry, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{UserID: "u1"}).SignedString([]byte("secret"))
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"valid", valid, true},
+		{"expired", expired, false},
+		{"signed with another key", otherKey, false},
+		{"alg none", none, false},
+		{"no expiry", noExpiry, false},
+		{"garbage", "not.a.token", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := s.ValidateToken(tt.token)
+			if !tt.ok {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("err = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.UserID != "u1" || claims.OrgID != "acme" || claims.Role != "admin" || claims.Email != "u1@acme.test" {
+				t.Errorf("claims = %+v", claims)
+			}
+		})
+	}
+}
+
+func TestGenerateTokenIsUnique(t *testing.T) {
+	s := NewService("secret", time.Hour)
+	a, _ := s.GenerateToken("u1", "", "acme", "admin")
+	b, _ := s.GenerateToken("u1", "", "acme", "admin")
+	if a == b {
+		t.Error("two tokens issued in the same second are equal")
+	}
+}
+
+func BenchmarkGenerateToken(b *testing.B) {
+	s := NewService("secret", time.Hour)
+	for i := 0; i < b.N; i++ {
+		if _, err := s.GenerateToken("u1", "u1@acme.test", "acme", "admin"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateToken(b *testing.B) {
+	s := NewService("secret", time.Hour)
+	token, err := s.GenerateToken("u1", "u1@acme.test", "acme", "admin")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.ValidateToken(token); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Package biexport pushes each organization's review lifecycle events to a
+// data warehouse once a day, as NDJSON files delivered to an HTTPS endpoint
+// or an S3 bucket.
+package biexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/webhooks"
+)
+
+const (
+	dayLayout = "2006-01-02"
+	// maxCatchUp bounds how many missed days one run exports per
+	// organization, e.g. after an outage.
+	maxCatchUp = 7
+)
+
+// Exporter records lifecycle events as they are published and exports
+// each complete UTC day once.
+type Exporter struct {
+	st       *store.Memory
+	http     *http.Client
+	interval time.Duration
+}
+
+// New creates an exporter that delivers through client and subscribes it
+// to review events.
+func New(st *store.Memory, d *notify.Dispatcher, client *http.Client, interval time.Duration) *Exporter {
+	e := &Exporter{st: st, http: client, interval: interval}
+	d.Listen(e.record)
+	return e
+}
+
+func (e *Exporter) record(ev notify.Event) {
+	review, err := e.st.GetReview(ev.ReviewID)
+	if err != nil {
+		return
+	}
+	e.st.AppendLifecycle(&models.LifecycleEvent{
+		Event:     ev.Type,
+		OrgID:     review.OrgID,
+		ReviewID:  review.ID,
+		ActorID:   ev.ActorID,
+		Status:    review.Status,
+		Priority:  e.st.GetPriority(review.ID),
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// Run exports due days every interval until ctx is cancelled.
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.ExportDue(ctx, now)
+		}
+	}
+}
+
+// ExportDue exports, for every enabled organization, each complete day
+// since its last successful export. A failed day is retried on the next
+// run; later days wait so files arrive in order.
+func (e *Exporter) ExportDue(ctx context.Context, now time.Time) {
+	yesterday := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	for _, cfg := range e.st.ListBIExportConfigs() {
+		day := yesterday
+		if last, err := time.Parse(dayLayout, cfg.LastDay); err == nil {
+			day = last.AddDate(0, 0, 1)
+			if earliest := yesterday.AddDate(0, 0, 1-maxCatchUp); day.Before(earliest) {
+				day = earliest
+			}
+		}
+		for ; !day.After(yesterday); day = day.AddDate(0, 0, 1) {
+			if err := e.Export(ctx, cfg, day); err != nil {
+				log.Printf("BI export of %s for org %s failed: %v", day.Format(dayLayout), cfg.OrgID, err)
+				break
+			}
+			cfg, _ = e.st.GetBIExportConfig(cfg.OrgID)
+		}
+	}
+}
+
+// Export delivers one UTC day of an organization's events and records the
+// outcome on its configuration. Days without events are delivered as
+// empty files so consumers can tell "no activity" from "not delivered".
+func (e *Exporter) Export(ctx context.Context, cfg models.BIExportConfig, day time.Time) error {
+	day = day.UTC().Truncate(24 * time.Hour)
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, ev := range e.st.ListLifecycle(cfg.OrgID, day, day.AddDate(0, 0, 1)) {
+		enc.Encode(ev)
+	}
+
+	err := e.deliver(ctx, cfg, day, body.Bytes())
+
+	current, ok := e.st.GetBIExportConfig(cfg.OrgID)
+	if !ok {
+		return err
+	}
+	current.LastRunAt = time.Now()
+	current.LastError = ""
+	if err != nil {
+		current.LastError = err.Error()
+	} else if day.Format(dayLayout) > current.LastDay {
+		current.LastDay = day.Format(dayLayout)
+	}
+	e.st.SetBIExportConfig(current)
+	return err
+}
+
+func (e *Exporter) deliver(ctx context.Context, cfg models.BIExportConfig, day time.Time, body []byte) error {
+	if k, disabled := e.st.KillSwitch(models.CapabilityBIExport, cfg.OrgID); disabled {
+		return k
+	}
+	switch cfg.Destination {
+	case models.BIDestinationHTTPS:
+		return e.post(ctx, cfg, day, body)
+	case models.BIDestinationS3:
+		return e.putS3(ctx, cfg, ObjectKey(cfg, day), body)
+	}
+	return fmt.Errorf("unknown destination %q", cfg.Destination)
+}
+
+// post sends the day's file to the configured endpoint, signed like
+// webhook payloads.
+func (e *Exporter) post(ctx context.Context, cfg models.BIExportConfig, day time.Time, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("X-Aurea-Org", cfg.OrgID)
+	req.Header.Set("X-Aurea-Export-Date", day.Format(dayLayout))
+	if cfg.Secret != "" {
+		req.Header.Set("X-Aurea-Signature", webhooks.Sign(cfg.Secret, body))
+	}
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// ObjectKey is where a day's file is written in S3, partitioned by
+// organization and date in the layout most warehouse loaders recognize.
+func ObjectKey(cfg models.BIExportConfig, day time.Time) string {
+	key := fmt.Sprintf("org=%s/date=%s/review-events.ndjson", cfg.OrgID, day.UTC().Format(dayLayout))
+	if prefix := strings.Trim(cfg.Prefix, "/"); prefix != "" {
+		key = prefix + "/" + key
+	}
+	return key
+}
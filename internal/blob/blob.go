@@ -0,0 +1,105 @@
+// Package blob stores binary objects such as avatars and attachments.
+package blob
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned when an object does not exist.
+var ErrNotFound = errors.New("blob not found")
+
+// Store persists binary objects by key.
+type Store interface {
+	Put(key, contentType string, r io.Reader) error
+	Get(key string) (io.ReadCloser, string, error)
+	Delete(key string) error
+}
+
+// FS stores objects as files below a root directory. The content type of
+// each object is kept in a sidecar file.
+type FS struct {
+	root string
+}
+
+// NewFS creates a filesystem-backed store rooted at dir, creating it if needed.
+func NewFS(dir string) (*FS, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create blob dir: %w", err)
+	}
+	return &FS{root: dir}, nil
+}
+
+// Put writes an object, replacing any existing object with the same key.
+func (s *FS) Put(key, contentType string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".type", []byte(contentType), 0o640); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens an object and returns its content type.
+func (s *FS) Get(key string) (io.ReadCloser, string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, "", err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	contentType, _ := os.ReadFile(path + ".type")
+	if len(contentType) == 0 {
+		contentType = []byte("application/octet-stream")
+	}
+	return f, string(contentType), nil
+}
+
+// Delete removes an object. Deleting a missing object is not an error.
+func (s *FS) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	os.Remove(path + ".type")
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path maps a key to a file below root, rejecting keys that would escape it.
+func (s *FS) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if key == "" || strings.HasSuffix(clean, ".type") || clean == "/" {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.root, clean), nil
+}
@@ -0,0 +1,187 @@
+// Package calendar computes deadlines in organization working time.
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// maxScanDays bounds calendar walks so a calendar with almost no working
+// time cannot loop for ever.
+const maxScanDays = 3 * 366
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Default returns the calendar used by organizations that have not set
+// one: Monday to Friday, 09:00-17:00 UTC, no holidays.
+func Default(orgID string) models.WorkCalendar {
+	return models.WorkCalendar{
+		OrgID:    orgID,
+		Timezone: "UTC",
+		WorkDays: []string{"mon", "tue", "wed", "thu", "fri"},
+		DayStart: "09:00",
+		DayEnd:   "17:00",
+		Holidays: []string{},
+	}
+}
+
+// Calendar is a validated, ready-to-use work calendar.
+type Calendar struct {
+	loc      *time.Location
+	days     map[time.Weekday]bool
+	start    int // minutes after midnight
+	end      int
+	holidays map[string]bool
+}
+
+// New validates wc and prepares it for deadline computations.
+func New(wc models.WorkCalendar) (*Calendar, error) {
+	loc, err := time.LoadLocation(wc.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", wc.Timezone)
+	}
+
+	c := &Calendar{loc: loc, days: make(map[time.Weekday]bool), holidays: make(map[string]bool)}
+	for _, d := range wc.WorkDays {
+		wd, ok := weekdays[strings.ToLower(d)]
+		if !ok {
+			return nil, fmt.Errorf("invalid work day %q", d)
+		}
+		c.days[wd] = true
+	}
+	if len(c.days) == 0 {
+		return nil, errors.New("at least one work day is required")
+	}
+
+	if c.start, err = parseClock(wc.DayStart); err != nil {
+		return nil, err
+	}
+	if c.end, err = parseClock(wc.DayEnd); err != nil {
+		return nil, err
+	}
+	if c.end <= c.start {
+		return nil, errors.New("day_end must be after day_start")
+	}
+
+	for _, h := range wc.Holidays {
+		if _, err := time.ParseInLocation("2006-01-02", h, loc); err != nil {
+			return nil, fmt.Errorf("invalid holiday %q: use YYYY-MM-DD", h)
+		}
+		c.holidays[h] = true
+	}
+	return c, nil
+}
+
+// Location returns the calendar's time zone.
+func (c *Calendar) Location() *time.Location {
+	return c.loc
+}
+
+// AddWorkingTime returns the instant reached after d of working time has
+// elapsed from t.
+func (c *Calendar) AddWorkingTime(t time.Time, d time.Duration) (time.Time, error) {
+	cursor := t.In(c.loc)
+	remaining := d
+	for i := 0; i < maxScanDays; i++ {
+		if opens, closes, ok := c.window(cursor); ok {
+			if cursor.Before(opens) {
+				cursor = opens
+			}
+			if cursor.Before(closes) {
+				avail := closes.Sub(cursor)
+				if remaining <= avail {
+					return cursor.Add(remaining), nil
+				}
+				remaining -= avail
+			}
+		}
+		cursor = nextMidnight(cursor)
+	}
+	return time.Time{}, errors.New("calendar has too little working time to reach the deadline")
+}
+
+// WorkingTimeBetween returns the working time elapsed between from and to.
+func (c *Calendar) WorkingTimeBetween(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+	cursor := from.In(c.loc)
+	var total time.Duration
+	for i := 0; i < maxScanDays && cursor.Before(to); i++ {
+		if opens, closes, ok := c.window(cursor); ok {
+			start, end := maxTime(cursor, opens), minTime(to, closes)
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+		cursor = nextMidnight(cursor)
+	}
+	return total
+}
+
+// SLA reports a review's progress against a target of targetHours working
+// hours from its creation.
+func (c *Calendar) SLA(review *models.Review, targetHours int, now time.Time) (models.ReviewSLA, error) {
+	target := time.Duration(targetHours) * time.Hour
+	due, err := c.AddWorkingTime(review.CreatedAt, target)
+	if err != nil {
+		return models.ReviewSLA{}, err
+	}
+	elapsed := c.WorkingTimeBetween(review.CreatedAt, now)
+	remaining := target - elapsed
+	if remaining < 0 {
+		remaining = 0
+	}
+	return models.ReviewSLA{
+		ReviewID:       review.ID,
+		TargetHours:    targetHours,
+		DueAt:          due.In(c.loc),
+		ElapsedHours:   elapsed.Hours(),
+		RemainingHours: remaining.Hours(),
+		Breached:       now.After(due),
+	}, nil
+}
+
+// window returns the working hours of the day containing t.
+func (c *Calendar) window(t time.Time) (time.Time, time.Time, bool) {
+	if !c.days[t.Weekday()] || c.holidays[t.Format("2006-01-02")] {
+		return time.Time{}, time.Time{}, false
+	}
+	// Build from wall-clock minutes so DST transitions keep the local hours.
+	opens := time.Date(t.Year(), t.Month(), t.Day(), 0, c.start, 0, 0, c.loc)
+	closes := time.Date(t.Year(), t.Month(), t.Day(), 0, c.end, 0, 0, c.loc)
+	return opens, closes, true
+}
+
+func nextMidnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q: use HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,77 @@
+// Package deadletter keeps background jobs that failed (webhook
+// deliveries, notification emails and pushes) so organization admins and
+// operators can inspect, retry or purge them.
+package deadletter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Retrier runs a dead-lettered job again.
+type Retrier func(ctx context.Context, l models.DeadLetter) error
+
+// Queue records failed jobs and retries them with the retrier registered
+// for their kind.
+type Queue struct {
+	st *store.Memory
+
+	mu       sync.RWMutex
+	retriers map[string]Retrier
+}
+
+// New creates a queue backed by the store.
+func New(st *store.Memory) *Queue {
+	return &Queue{st: st, retriers: make(map[string]Retrier)}
+}
+
+// Handle registers the retrier of a kind. It must be called before the
+// server starts.
+func (q *Queue) Handle(kind string, r Retrier) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.retriers[kind] = r
+}
+
+// Add records a failed attempt of a job.
+func (q *Queue) Add(l models.DeadLetter) {
+	now := time.Now()
+	l.CreatedAt, l.FailedAt = now, now
+	if l.Attempts == 0 {
+		l.Attempts = 1
+	}
+	q.st.PutDeadLetter(l)
+}
+
+// Resolve drops the dead letter of a job that has succeeded since.
+func (q *Queue) Resolve(kind, reference string) {
+	q.st.ResolveDeadLetter(kind, reference)
+}
+
+// Retry runs a dead letter of the organization (any organization when
+// orgID is empty) again. On success the letter is removed; on failure it
+// is kept with the new error.
+func (q *Queue) Retry(ctx context.Context, orgID, id string) (models.DeadLetter, error) {
+	l, err := q.st.GetDeadLetter(orgID, id)
+	if err != nil {
+		return l, err
+	}
+	q.mu.RLock()
+	retry, ok := q.retriers[l.Kind]
+	q.mu.RUnlock()
+	if !ok {
+		return l, fmt.Errorf("no retrier for %s jobs", l.Kind)
+	}
+	if err := retry(ctx, l); err != nil {
+		l.Error, l.FailedAt = err.Error(), time.Now()
+		l.Attempts = 1
+		return q.st.PutDeadLetter(l), err
+	}
+	q.st.ResolveDeadLetter(l.Kind, l.Reference)
+	return l, nil
+}
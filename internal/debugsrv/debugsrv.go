@@ -0,0 +1,126 @@
+// Package debugsrv serves profiling and runtime introspection endpoints
+// for operators. It is meant for its own listener, never the public API.
+package debugsrv
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	rpprof "runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+)
+
+// snapshotPrefix is where snapshots are kept in blob storage.
+const snapshotPrefix = "debug-snapshots/"
+
+// snapshotProfiles are the profiles that can be captured to storage.
+var snapshotProfiles = map[string]bool{
+	"goroutine": true, "heap": true, "allocs": true, "block": true, "mutex": true, "threadcreate": true,
+}
+
+func init() {
+	expvar.Publish("goroutines", expvar.Func(func() interface{} { return runtime.NumGoroutine() }))
+}
+
+// Handler returns the debug endpoints, all requiring "Authorization:
+// Bearer <token>":
+//
+//	/debug/pprof/...           net/http/pprof
+//	/debug/vars                expvar
+//	POST /debug/snapshots      capture ?profile= (default goroutine) to blob storage
+//	GET /debug/snapshots/{name} download a captured snapshot
+//
+// The pprof handlers are mounted explicitly; nothing is served from
+// http.DefaultServeMux.
+func Handler(token string, blobs blob.Store) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/snapshots", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		capture(w, r, blobs)
+	})
+	mux.HandleFunc("/debug/snapshots/", func(w http.ResponseWriter, r *http.Request) {
+		download(w, r, blobs)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := []byte(r.Header.Get("Authorization"))
+		if token == "" || subtle.ConstantTimeCompare(given, []byte("Bearer "+token)) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// capture writes a profile to blob storage so it survives the process and
+// can be analysed offline with go tool pprof.
+func capture(w http.ResponseWriter, r *http.Request, blobs blob.Store) {
+	name := r.URL.Query().Get("profile")
+	if name == "" {
+		name = "goroutine"
+	}
+	if !snapshotProfiles[name] {
+		http.Error(w, fmt.Sprintf("unknown profile %q", name), http.StatusBadRequest)
+		return
+	}
+	if name == "heap" {
+		runtime.GC()
+	}
+
+	key := fmt.Sprintf("%s%s-%s.pb.gz", snapshotPrefix, time.Now().UTC().Format("20060102T150405.000Z"), name)
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(rpprof.Lookup(name).WriteTo(pw, 0))
+	}()
+	if err := blobs.Put(key, "application/octet-stream", pr); err != nil {
+		pr.CloseWithError(err)
+		http.Error(w, "Failed to store snapshot: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{
+		"profile": name,
+		"name":    strings.TrimPrefix(key, snapshotPrefix),
+	})
+}
+
+func download(w http.ResponseWriter, r *http.Request, blobs blob.Store) {
+	name := strings.TrimPrefix(r.URL.Path, "/debug/snapshots/")
+	if name == "" || strings.Contains(name, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	body, contentType, err := blobs.Get(snapshotPrefix + name)
+	if errors.Is(err, blob.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to read snapshot", http.StatusInternalServerError)
+		return
+	}
+	defer body.Close()
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	io.Copy(w, body)
+}
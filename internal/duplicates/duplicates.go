@@ -0,0 +1,87 @@
+// Package duplicates finds reviews of an organization that likely
+// duplicate one another, by the similarity of their titles and content.
+package duplicates
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// DefaultThreshold is the lowest score reported as a likely duplicate.
+const DefaultThreshold = 0.5
+
+// Title similarity weighs more than content, which is often boilerplate.
+const (
+	titleWeight   = 0.6
+	contentWeight = 0.4
+)
+
+// Match is a review similar to the one searched for.
+type Match struct {
+	ReviewID string  `json:"review_id"`
+	Title    string  `json:"title"`
+	Status   string  `json:"status"`
+	Score    float64 `json:"score"`
+}
+
+// Find scores review against candidates and returns those scoring at
+// least threshold, most similar first, at most limit of them.
+func Find(review *models.Review, candidates []*models.Review, threshold float64, limit int) []Match {
+	matches := []Match{}
+	for _, c := range candidates {
+		if c.ID == review.ID {
+			continue
+		}
+		score := Score(review, c)
+		if score >= threshold {
+			matches = append(matches, Match{ReviewID: c.ID, Title: c.Title, Status: c.Status, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Score combines the word overlap of two reviews' titles and contents
+// into a similarity between 0 and 1. When either content is empty only
+// the titles count.
+func Score(a, b *models.Review) float64 {
+	title := jaccard(words(a.Title), words(b.Title))
+	contentA, contentB := words(a.Content), words(b.Content)
+	if len(contentA) == 0 || len(contentB) == 0 {
+		return title
+	}
+	return titleWeight*title + contentWeight*jaccard(contentA, contentB)
+}
+
+// words returns the distinct lowercase words of s, ignoring those shorter
+// than three letters.
+func words(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) >= 3 {
+			set[w] = true
+		}
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,324 @@
+// Package egress controls outbound HTTP traffic: proxying, destination
+// allow and deny lists, per-destination timeouts and circuit breaking.
+//
+// Host names are checked before a request is sent, and the address each
+// connection actually dials is checked after name resolution, so a public
+// name cannot point a connection at a loopback, link-local or private
+// address unless the operator allows that network.
+package egress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/httpclient"
+)
+
+var (
+	// ErrDenied is returned for requests to destinations the policy blocks.
+	ErrDenied = errors.New("egress: destination not allowed")
+	// ErrCircuitOpen is returned while a destination's breaker is open.
+	ErrCircuitOpen = errors.New("egress: circuit open")
+)
+
+// internal lists the ranges that are denied by default on top of the
+// loopback, link-local, private, unspecified and multicast addresses the
+// standard library classifies: shared address space (RFC 6598), benchmark
+// networks (RFC 2544) and IPv4 "this network".
+var internal = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+}
+
+const (
+	defaultTimeout   = 10 * time.Second
+	breakerThreshold = 5
+	breakerCooldown  = 30 * time.Second
+)
+
+// Policy describes which destinations may be reached and how.
+type Policy struct {
+	// Allow lists permitted hosts. Empty allows every host not denied.
+	// Entries may use a leading "*." to match subdomains.
+	Allow []string
+	// Deny lists hosts that are always blocked, even if allowed.
+	Deny []string
+	// AllowNetworks lists internal networks that may be dialed. Loopback,
+	// link-local, private and other internal addresses are refused
+	// otherwise, whatever name resolved to them.
+	AllowNetworks []netip.Prefix
+	// Timeouts overrides the request timeout for specific hosts.
+	Timeouts map[string]time.Duration
+	// DefaultTimeout applies to hosts without an override.
+	DefaultTimeout time.Duration
+}
+
+// FromEnv reads the policy from EGRESS_ALLOW, EGRESS_DENY,
+// EGRESS_ALLOW_NETWORKS (comma-separated CIDRs, e.g. 10.0.0.0/8) and
+// EGRESS_TIMEOUTS (comma-separated host=duration pairs).
+func FromEnv() (Policy, error) {
+	p := Policy{
+		Allow:          splitList(os.Getenv("EGRESS_ALLOW")),
+		Deny:           splitList(os.Getenv("EGRESS_DENY")),
+		Timeouts:       map[string]time.Duration{},
+		DefaultTimeout: defaultTimeout,
+	}
+	networks, err := ParseNetworks(os.Getenv("EGRESS_ALLOW_NETWORKS"))
+	if err != nil {
+		return Policy{}, err
+	}
+	p.AllowNetworks = networks
+	for _, entry := range splitList(os.Getenv("EGRESS_TIMEOUTS")) {
+		host, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return Policy{}, fmt.Errorf("egress timeout %q is not host=duration", entry)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return Policy{}, fmt.Errorf("egress timeout for %s: %w", host, err)
+		}
+		if host == "*" {
+			p.DefaultTimeout = d
+			continue
+		}
+		p.Timeouts[strings.ToLower(host)] = d
+	}
+	return p, nil
+}
+
+// ParseNetworks parses a comma-separated list of CIDRs.
+func ParseNetworks(s string) ([]netip.Prefix, error) {
+	var out []netip.Prefix
+	for _, entry := range splitList(s) {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("egress network %q: %w", entry, err)
+		}
+		out = append(out, prefix.Masked())
+	}
+	return out, nil
+}
+
+// CheckHost returns ErrDenied if the policy refuses host or an address it
+// resolves to now. Connections are still checked when dialed, since the
+// name may resolve differently by then.
+func (p Policy) CheckHost(ctx context.Context, host string) error {
+	if !p.Permits(host) {
+		return fmt.Errorf("%w: %s", ErrDenied, host)
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !p.PermitsAddr(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrDenied, host, addr)
+		}
+	}
+	return nil
+}
+
+// Permits reports whether requests to host are allowed. A host that is
+// an IP address must also pass PermitsAddr.
+func (p Policy) Permits(host string) bool {
+	host = strings.ToLower(host)
+	if matchAny(p.Deny, host) {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !p.PermitsAddr(addr) {
+		return false
+	}
+	return len(p.Allow) == 0 || matchAny(p.Allow, host)
+}
+
+// PermitsAddr reports whether connections to addr are allowed: internal
+// addresses only when AllowNetworks covers them.
+func (p Policy) PermitsAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, n := range p.AllowNetworks {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+	return !isInternal(addr)
+}
+
+func isInternal(addr netip.Addr) bool {
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return true
+	}
+	for _, n := range internal {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dialer returns a dialer that refuses, once the name is resolved, to
+// connect to an address the policy does not permit.
+func (p Policy) Dialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrDenied, address)
+			}
+			if !p.PermitsAddr(ap.Addr()) {
+				return fmt.Errorf("%w: %s", ErrDenied, ap.Addr())
+			}
+			return nil
+		},
+	}
+}
+
+// TimeoutFor returns the request timeout for host.
+func (p Policy) TimeoutFor(host string) time.Duration {
+	if d, ok := p.Timeouts[strings.ToLower(host)]; ok {
+		return d
+	}
+	if p.DefaultTimeout > 0 {
+		return p.DefaultTimeout
+	}
+	return defaultTimeout
+}
+
+// Client returns an HTTP client that routes through HTTPS_PROXY/HTTP_PROXY
+// and enforces the policy on every request, including redirects, and on
+// every connection it dials. The configured proxy itself may be internal;
+// requests sent through it are resolved by the proxy, so only their host
+// names and IP literals are checked here.
+func (p Policy) Client() *http.Client {
+	guarded := p.Dialer()
+	direct := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	var proxies sync.Map
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.Proxy = func(req *http.Request) (*url.URL, error) {
+		u, err := http.ProxyFromEnvironment(req)
+		if u != nil {
+			proxies.Store(proxyAddr(u), true)
+		}
+		return u, err
+	}
+	base.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if _, ok := proxies.Load(addr); ok {
+			return direct.DialContext(ctx, network, addr)
+		}
+		return guarded.DialContext(ctx, network, addr)
+	}
+	return &http.Client{Transport: &Transport{Policy: p, Base: base}}
+}
+
+// proxyAddr returns the host:port the transport dials for proxy u.
+func proxyAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// Transport enforces a Policy around another RoundTripper. Only the host
+// names are checked unless Base dials through Policy.Dialer, as the
+// transport of Client does.
+type Transport struct {
+	Policy Policy
+	Base   http.RoundTripper
+
+	mu       sync.Mutex
+	breakers map[string]*httpclient.Breaker
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	if !t.Policy.Permits(host) {
+		return nil, fmt.Errorf("%w: %s", ErrDenied, host)
+	}
+	b := t.breaker(host)
+	if !b.Allow(time.Now()) {
+		return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.Policy.TimeoutFor(host))
+	resp, err := t.Base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		b.Record(false, time.Now())
+		return nil, err
+	}
+	b.Record(resp.StatusCode < 500, time.Now())
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+func (t *Transport) breaker(host string) *httpclient.Breaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.breakers == nil {
+		t.breakers = make(map[string]*httpclient.Breaker)
+	}
+	b, ok := t.breakers[host]
+	if !ok {
+		b = httpclient.NewBreaker(breakerThreshold, breakerCooldown)
+		t.breakers[host] = b
+	}
+	return b
+}
+
+// cancelBody releases the per-request timeout once the body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelBody) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+func matchAny(patterns []string, host string) bool {
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		if p == host {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(p, "*"); ok && strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,135 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestPermitsAddr(t *testing.T) {
+	p := Policy{AllowNetworks: []netip.Prefix{netip.MustParsePrefix("10.20.0.0/16")}}
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"10.0.0.5", false},
+		{"172.16.3.4", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::ffff:127.0.0.1", false},
+		{"10.20.3.4", true},
+		{"::ffff:10.20.3.4", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := p.PermitsAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("PermitsAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermits(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		host   string
+		want   bool
+	}{
+		{"no lists", Policy{}, "hooks.example.com", true},
+		{"allowed subdomain", Policy{Allow: []string{"*.example.com"}}, "hooks.example.com", true},
+		{"not allowed", Policy{Allow: []string{"*.example.com"}}, "example.org", false},
+		{"denied wins", Policy{Allow: []string{"*.example.com"}, Deny: []string{"hooks.example.com"}}, "hooks.example.com", false},
+		{"metadata literal", Policy{}, "169.254.169.254", false},
+		{"allowed network literal", Policy{AllowNetworks: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}}, "127.0.0.1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Permits(tt.host); got != tt.want {
+				t.Errorf("Permits(%s) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientChecksResolvedAddress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	// "localhost" passes the host name checks; only the resolved address
+	// gives it away.
+	target := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+
+	tests := []struct {
+		name   string
+		policy Policy
+		denied bool
+	}{
+		{"default", Policy{}, true},
+		{"host allowlisted", Policy{Allow: []string{"localhost"}}, true},
+		{"network allowlisted", Policy{AllowNetworks: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8"), netip.MustParsePrefix("::1/128")}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.policy.Client().Get(target)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if denied := errors.Is(err, ErrDenied); denied != tt.denied {
+				t.Errorf("denied = %v, want %v (err %v)", denied, tt.denied, err)
+			}
+			if !tt.denied && err != nil {
+				t.Errorf("Get: %v", err)
+			}
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("EGRESS_ALLOW_NETWORKS", "10.1.2.3/16, 127.0.0.1/32")
+	p, err := FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.AllowNetworks) != 2 || p.AllowNetworks[0] != netip.MustParsePrefix("10.1.0.0/16") {
+		t.Errorf("AllowNetworks = %v", p.AllowNetworks)
+	}
+
+	t.Setenv("EGRESS_ALLOW_NETWORKS", "intranet")
+	if _, err := FromEnv(); err == nil {
+		t.Error("FromEnv accepted a network that is not a CIDR")
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	p := Policy{AllowNetworks: []netip.Prefix{netip.MustParsePrefix("10.20.0.0/16")}}
+	tests := []struct {
+		host   string
+		denied bool
+	}{
+		{"93.184.216.34", false},
+		{"10.20.3.4", false},
+		{"10.0.0.5", true},
+		{"169.254.169.254", true},
+		{"localhost", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			err := p.CheckHost(context.Background(), tt.host)
+			if denied := errors.Is(err, ErrDenied); denied != tt.denied {
+				t.Errorf("CheckHost(%s) = %v, want denied %v", tt.host, err, tt.denied)
+			}
+		})
+	}
+}
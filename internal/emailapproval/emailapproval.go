@@ -0,0 +1,112 @@
+// Package emailapproval signs the one-click approve/reject links in
+// approval-request emails and interprets replies to those emails.
+package emailapproval
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Actions an approval email can carry out.
+const (
+	Approve = "approve"
+	Reject  = "reject"
+)
+
+// ErrInvalidLink is returned for links that were not issued by this server
+// or were altered.
+var ErrInvalidLink = errors.New("invalid approval link")
+
+// Links signs and verifies approval link tokens. A token names one
+// approval request and one action, so a link can only do what it says.
+type Links struct {
+	secret []byte
+}
+
+// NewLinks creates a signer keyed by secret.
+func NewLinks(secret string) *Links {
+	return &Links{secret: []byte(secret)}
+}
+
+// Sign returns the link token for an action on an approval request.
+func (l *Links) Sign(requestID, action string) string {
+	return requestID + "." + action + "." + l.mac(requestID, action)
+}
+
+// Verify checks a link token and returns the request ID and action.
+func (l *Links) Verify(token string) (requestID, action string, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || (parts[1] != Approve && parts[1] != Reject) {
+		return "", "", ErrInvalidLink
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(l.mac(parts[0], parts[1]))) {
+		return "", "", ErrInvalidLink
+	}
+	return parts[0], parts[1], nil
+}
+
+func (l *Links) mac(requestID, action string) string {
+	h := hmac.New(sha256.New, l.secret)
+	h.Write([]byte("email-approval\x00" + requestID + "\x00" + action))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ReplyAddress returns the reply-to address that routes replies to an
+// approval request back to the inbound webhook, e.g.
+// approvals+<id>@inbound.example.com.
+func ReplyAddress(domain, requestID string) string {
+	return "approvals+" + requestID + "@" + domain
+}
+
+// RequestFromAddress extracts the approval request ID from a reply-to
+// address, or returns "" if the address is not one.
+func RequestFromAddress(addr string) string {
+	if i := strings.LastIndex(addr, "<"); i >= 0 {
+		addr = strings.TrimSuffix(addr[i+1:], ">")
+	}
+	local, _, ok := strings.Cut(strings.TrimSpace(addr), "@")
+	if !ok {
+		return ""
+	}
+	id, ok := strings.CutPrefix(local, "approvals+")
+	if !ok {
+		return ""
+	}
+	return id
+}
+
+// ParseReply reads the decision from the first non-blank line of a reply:
+// "approve"/"approved"/"lgtm" approve, and "reject"/"rejected" reject. The
+// rest of the reply, up to the quoted original message, is returned as
+// the note. An unrecognized reply returns an empty action.
+func ParseReply(text string) (action, note string) {
+	var lines []string
+	s := bufio.NewScanner(strings.NewReader(text))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, ">") || (strings.HasPrefix(line, "On ") && strings.HasSuffix(line, "wrote:")) {
+			break
+		}
+		if action == "" {
+			if line == "" {
+				continue
+			}
+			word, rest, _ := strings.Cut(line, " ")
+			switch strings.ToLower(strings.Trim(word, ".,!:")) {
+			case "approve", "approved", "lgtm":
+				action = Approve
+			case "reject", "rejected":
+				action = Reject
+			default:
+				return "", ""
+			}
+			line = strings.TrimSpace(rest)
+		}
+		lines = append(lines, line)
+	}
+	return action, strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,193 @@
+// Package envelope implements envelope encryption for sensitive fields:
+// each value is sealed with a fresh AES-256-GCM data key, and the data key
+// is wrapped by a master key held by a KeyWrapper (a KMS or local keys).
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks sealed values so plaintext written before encryption was
+// enabled is still readable.
+const prefix = "enc:v1:"
+
+// ErrMalformed is returned for sealed values that cannot be parsed.
+var ErrMalformed = errors.New("envelope: malformed ciphertext")
+
+// KeyWrapper wraps and unwraps data keys with master keys.
+type KeyWrapper interface {
+	// ActiveKeyID is the master key used to wrap new data keys.
+	ActiveKeyID() string
+	Wrap(dataKey []byte) (keyID string, wrapped []byte, err error)
+	Unwrap(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// Cipher seals and opens field values.
+type Cipher struct {
+	keys KeyWrapper
+}
+
+// New creates a cipher that wraps data keys with keys.
+func New(keys KeyWrapper) *Cipher {
+	return &Cipher{keys: keys}
+}
+
+// Encrypt seals plaintext under a new data key. Empty values stay empty.
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	sealed, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	keyID, wrapped, err := c.keys.Wrap(dataKey)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawStdEncoding
+	return prefix + keyID + ":" + enc.EncodeToString(wrapped) + ":" + enc.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a sealed value. Values without the envelope prefix are
+// returned unchanged.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	keyID, wrapped, sealed, ok, err := parse(value)
+	if !ok {
+		return value, err
+	}
+	dataKey, err := c.keys.Unwrap(keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKey, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Stale reports whether value is plaintext or sealed under a master key
+// other than the active one, and so should be re-encrypted on rotation.
+func (c *Cipher) Stale(value string) bool {
+	if value == "" {
+		return false
+	}
+	keyID, _, _, ok, _ := parse(value)
+	return !ok || keyID != c.keys.ActiveKeyID()
+}
+
+func parse(value string) (keyID string, wrapped, sealed []byte, ok bool, err error) {
+	rest, found := strings.CutPrefix(value, prefix)
+	if !found {
+		return "", nil, nil, false, nil
+	}
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 {
+		return "", nil, nil, false, ErrMalformed
+	}
+	enc := base64.RawStdEncoding
+	if wrapped, err = enc.DecodeString(parts[1]); err != nil {
+		return "", nil, nil, false, ErrMalformed
+	}
+	if sealed, err = enc.DecodeString(parts[2]); err != nil {
+		return "", nil, nil, false, ErrMalformed
+	}
+	return parts[0], wrapped, sealed, true, nil
+}
+
+// seal encrypts with AES-GCM and prepends the nonce.
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// LocalKeys holds master keys in process memory. The first key is active;
+// the rest are kept to unwrap data keys sealed before a rotation.
+type LocalKeys struct {
+	active string
+	keys   map[string][]byte
+}
+
+// ParseLocalKeys parses "id:base64key,id:base64key". Each key must decode
+// to 32 bytes.
+func ParseLocalKeys(spec string) (*LocalKeys, error) {
+	lk := &LocalKeys{keys: make(map[string][]byte)}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("envelope: key %q is not id:base64", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("envelope: key %q must be 32 bytes of base64", id)
+		}
+		if lk.active == "" {
+			lk.active = id
+		}
+		lk.keys[id] = key
+	}
+	if lk.active == "" {
+		return nil, errors.New("envelope: no master keys configured")
+	}
+	return lk, nil
+}
+
+// ActiveKeyID implements KeyWrapper.
+func (lk *LocalKeys) ActiveKeyID() string { return lk.active }
+
+// Wrap implements KeyWrapper.
+func (lk *LocalKeys) Wrap(dataKey []byte) (string, []byte, error) {
+	wrapped, err := seal(lk.keys[lk.active], dataKey)
+	return lk.active, wrapped, err
+}
+
+// Unwrap implements KeyWrapper.
+func (lk *LocalKeys) Unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := lk.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("envelope: unknown master key %q", keyID)
+	}
+	return open(key, wrapped)
+}
@@ -0,0 +1,155 @@
+// Package escalation pages the on-call when critical reviews breach their
+// SLA, and applies organizations' escalation rules to reviews that stall.
+package escalation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/calendar"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Escalator periodically checks open critical reviews against their SLA
+// and pages each organization's configured provider once per review.
+type Escalator struct {
+	st       *store.Memory
+	http     *http.Client
+	interval time.Duration
+}
+
+// New creates an escalator that pages through client and subscribes it to
+// review events so pages are resolved when the review is approved.
+func New(st *store.Memory, d *notify.Dispatcher, client *http.Client, interval time.Duration) *Escalator {
+	e := &Escalator{st: st, http: client, interval: interval}
+	d.Listen(e.handleEvent)
+	return e
+}
+
+// Run checks for overdue reviews every interval until ctx is cancelled.
+func (e *Escalator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.Check(ctx, now)
+		}
+	}
+}
+
+// Check pages for every critical review whose SLA has been breached at now.
+func (e *Escalator) Check(ctx context.Context, now time.Time) {
+	for _, review := range e.st.ListOpenReviews() {
+		if e.st.GetPriority(review.ID) != models.PriorityCritical {
+			continue
+		}
+		if _, paged := e.st.GetEscalation(review.ID); paged {
+			continue
+		}
+		cfg, ok := e.st.GetEscalationConfig(review.OrgID)
+		if !ok || !cfg.Enabled {
+			continue
+		}
+		breached, err := e.breached(review, now)
+		if err != nil {
+			log.Printf("Cannot evaluate SLA for review %s: %v", review.ID, err)
+			continue
+		}
+		if !breached {
+			continue
+		}
+		if err := e.page(ctx, cfg, review, now); err != nil {
+			log.Printf("Failed to page %s for review %s: %v", cfg.Provider, review.ID, err)
+		}
+	}
+}
+
+func (e *Escalator) breached(review *models.Review, now time.Time) (bool, error) {
+	target := e.st.GetPolicy(review.OrgID).ReviewSLAHours
+	if target <= 0 {
+		return false, nil
+	}
+	wc, ok := e.st.GetCalendar(review.OrgID)
+	if !ok {
+		wc = calendar.Default(review.OrgID)
+	}
+	cal, err := calendar.New(wc)
+	if err != nil {
+		return false, err
+	}
+	sla, err := cal.SLA(review, target, now)
+	if err != nil {
+		return false, err
+	}
+	return sla.Breached, nil
+}
+
+func (e *Escalator) page(ctx context.Context, cfg models.EscalationConfig, review *models.Review, now time.Time) error {
+	pager, err := PagerFor(cfg.Provider, e.http)
+	if err != nil {
+		return err
+	}
+	esc := &models.Escalation{
+		ReviewID: review.ID,
+		OrgID:    review.OrgID,
+		Provider: cfg.Provider,
+		DedupKey: dedupKey(review.ID),
+		PagedAt:  now,
+	}
+	// Record first so concurrent checks cannot double-page.
+	if !e.st.RecordEscalation(esc) {
+		return nil
+	}
+
+	err = pager.Trigger(ctx, cfg.RoutingKey, Incident{
+		DedupKey: esc.DedupKey,
+		Summary:  fmt.Sprintf("Critical review %q breached its SLA", review.Title),
+		Details: map[string]string{
+			"review_id": review.ID,
+			"org_id":    review.OrgID,
+			"status":    review.Status,
+		},
+	})
+	if err != nil {
+		// Forget the record so the next check retries.
+		e.st.ForgetEscalation(review.ID)
+	}
+	return err
+}
+
+func (e *Escalator) handleEvent(ev notify.Event) {
+	if ev.Type != notify.ReviewApproved {
+		return
+	}
+	esc, ok := e.st.GetEscalation(ev.ReviewID)
+	if !ok {
+		return
+	}
+	cfg, ok := e.st.GetEscalationConfig(esc.OrgID)
+	if !ok {
+		return
+	}
+	go func() {
+		pager, err := PagerFor(esc.Provider, e.http)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+			err = pager.Resolve(ctx, cfg.RoutingKey, esc.DedupKey)
+		}
+		if err != nil {
+			log.Printf("Failed to resolve page for review %s: %v", ev.ReviewID, err)
+		}
+	}()
+}
+
+func dedupKey(reviewID string) string {
+	return "aurea-review-" + reviewID
+}
@@ -0,0 +1,133 @@
+package escalation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+const (
+	pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+	opsgenieAlertsURL  = "https://api.opsgenie.com/v2/alerts"
+)
+
+// Incident describes a page about an overdue review.
+type Incident struct {
+	DedupKey string
+	Summary  string
+	Details  map[string]string
+}
+
+// Pager opens and closes incidents with an on-call provider.
+type Pager interface {
+	Trigger(ctx context.Context, routingKey string, inc Incident) error
+	Resolve(ctx context.Context, routingKey, dedupKey string) error
+}
+
+// PagerFor returns the pager for a provider name.
+func PagerFor(provider string, client *http.Client) (Pager, error) {
+	switch provider {
+	case models.PagerPagerDuty:
+		return &PagerDuty{URL: pagerDutyEventsURL, HTTP: client}, nil
+	case models.PagerOpsgenie:
+		return &Opsgenie{URL: opsgenieAlertsURL, HTTP: client}, nil
+	}
+	return nil, fmt.Errorf("unknown paging provider %q", provider)
+}
+
+// PagerDuty sends events through the PagerDuty Events API v2. The dedup key
+// makes repeated triggers for the same review collapse into one incident.
+type PagerDuty struct {
+	URL  string
+	HTTP *http.Client
+}
+
+// Trigger opens (or updates) the incident for inc.DedupKey.
+func (p *PagerDuty) Trigger(ctx context.Context, routingKey string, inc Incident) error {
+	return p.send(ctx, map[string]interface{}{
+		"routing_key":  routingKey,
+		"event_action": "trigger",
+		"dedup_key":    inc.DedupKey,
+		"payload": map[string]interface{}{
+			"summary":        inc.Summary,
+			"source":         "aurea-orchestrator",
+			"severity":       "critical",
+			"custom_details": inc.Details,
+		},
+	})
+}
+
+// Resolve closes the incident for dedupKey.
+func (p *PagerDuty) Resolve(ctx context.Context, routingKey, dedupKey string) error {
+	return p.send(ctx, map[string]interface{}{
+		"routing_key":  routingKey,
+		"event_action": "resolve",
+		"dedup_key":    dedupKey,
+	})
+}
+
+func (p *PagerDuty) send(ctx context.Context, event map[string]interface{}) error {
+	return postJSON(ctx, p.HTTP, p.URL, nil, event)
+}
+
+// Opsgenie creates alerts through the Opsgenie Alert API. The alias acts as
+// the deduplication key.
+type Opsgenie struct {
+	URL  string
+	HTTP *http.Client
+}
+
+// Trigger creates the alert for inc.DedupKey.
+func (o *Opsgenie) Trigger(ctx context.Context, apiKey string, inc Incident) error {
+	return postJSON(ctx, o.HTTP, o.URL, authHeader(apiKey), map[string]interface{}{
+		"message":  inc.Summary,
+		"alias":    inc.DedupKey,
+		"priority": "P1",
+		"source":   "aurea-orchestrator",
+		"details":  inc.Details,
+	})
+}
+
+// Resolve closes the alert with the given alias.
+func (o *Opsgenie) Resolve(ctx context.Context, apiKey, dedupKey string) error {
+	target := o.URL + "/" + url.PathEscape(dedupKey) + "/close?identifierType=alias"
+	return postJSON(ctx, o.HTTP, target, authHeader(apiKey), map[string]string{"source": "aurea-orchestrator"})
+}
+
+func authHeader(apiKey string) http.Header {
+	return http.Header{"Authorization": []string{"GenieKey " + apiKey}}
+}
+
+func postJSON(ctx context.Context, client *http.Client, target string, header http.Header, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("paging provider returned status %d", resp.StatusCode)
+	}
+	return nil
+}
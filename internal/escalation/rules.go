@@ -0,0 +1,162 @@
+package escalation
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/assign"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/risk"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// NotifyStalled is the notification type sent by notify rules.
+const NotifyStalled = "review.stalled"
+
+// Rules applies organizations' escalation rules to their stalled reviews.
+type Rules struct {
+	st       *store.Memory
+	assigner *assign.Engine
+	interval time.Duration
+}
+
+// NewRules creates a runner that checks reviews every interval and
+// reassigns with assigner.
+func NewRules(st *store.Memory, assigner *assign.Engine, interval time.Duration) *Rules {
+	return &Rules{st: st, assigner: assigner, interval: interval}
+}
+
+// Run checks for stalled reviews every interval until ctx is cancelled.
+func (r *Rules) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.Check(now)
+		}
+	}
+}
+
+// Check applies, to every open review, the enabled rules of its
+// organization whose delay has passed at now and that have not fired
+// since the review's last activity.
+func (r *Rules) Check(now time.Time) {
+	byOrg := make(map[string][]models.EscalationRule)
+	for _, rule := range r.st.ListEscalationRules("") {
+		if rule.Enabled {
+			byOrg[rule.OrgID] = append(byOrg[rule.OrgID], rule)
+		}
+	}
+	if len(byOrg) == 0 {
+		return
+	}
+	for _, review := range r.st.ListOpenReviews() {
+		rules := byOrg[review.OrgID]
+		if len(rules) == 0 {
+			continue
+		}
+		last, err := r.st.LastActivity(review.ID)
+		if err != nil {
+			continue
+		}
+		history := r.st.EscalationHistory(review.ID)
+		var level string
+		for _, rule := range rules {
+			if rule.Priority != "" && rule.Priority != r.st.GetPriority(review.ID) {
+				continue
+			}
+			if rule.Risk != "" {
+				if level == "" {
+					level = risk.Review(r.st, review).Level
+				}
+				if rule.Risk != level {
+					continue
+				}
+			}
+			if now.Sub(last) < time.Duration(rule.AfterHours)*time.Hour || firedSince(history, rule.ID, last) {
+				continue
+			}
+			step := r.apply(rule, review, now)
+			r.st.AddEscalationStep(step)
+			if rule.Action == models.EscalateReassign && step.TargetID != "" {
+				// The new assignment is activity; later rules wait for the
+				// review to stall again.
+				break
+			}
+		}
+	}
+}
+
+// firedSince reports whether the rule has fired on the review since t.
+func firedSince(history []models.EscalationStep, ruleID string, t time.Time) bool {
+	for _, s := range history {
+		if s.RuleID == ruleID && !s.At.Before(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rules) apply(rule models.EscalationRule, review *models.Review, now time.Time) models.EscalationStep {
+	step := models.EscalationStep{ReviewID: review.ID, RuleID: rule.ID, RuleName: rule.Name, Action: rule.Action, At: now}
+	a, assigned := r.st.GetAssignment(review.ID)
+	if assigned {
+		step.FromID = a.ReviewerID
+	}
+	switch rule.Action {
+	case models.EscalateNotifyAssignee:
+		if !assigned {
+			step.Note = "review has no assignee"
+			return step
+		}
+		step.TargetID = a.ReviewerID
+		r.notify(review, step.TargetID, NotifyStalled, now)
+	case models.EscalateNotifyManager:
+		if !assigned {
+			step.Note = "review has no assignee"
+			return step
+		}
+		if manager, ok := r.st.GetManager(a.ReviewerID); ok {
+			step.TargetID = manager
+			r.notify(review, manager, NotifyStalled, now)
+			return step
+		}
+		step.Note = "assignee has no manager; notified the organization's admins"
+		for _, u := range r.st.ListOrgUsers(review.OrgID) {
+			if u.Role == "admin" {
+				r.notify(review, u.ID, NotifyStalled, now)
+			}
+		}
+	case models.EscalateReassign:
+		next, ok := r.assigner.Reassign(review, step.FromID, now)
+		if !ok {
+			step.Note = "no other reviewer available"
+			return step
+		}
+		err := r.st.Assign(&models.Assignment{ReviewID: review.ID, ReviewerID: next.ID, AssignedBy: "system", AssignedAt: now})
+		if err != nil {
+			log.Printf("Cannot reassign stalled review %s: %v", review.ID, err)
+			step.Note = "reassignment failed"
+			return step
+		}
+		step.TargetID = next.ID
+		r.notify(review, next.ID, notify.ReviewAssigned, now)
+	}
+	return step
+}
+
+func (r *Rules) notify(review *models.Review, userID, kind string, now time.Time) {
+	r.st.AddNotification(&models.Notification{
+		UserID:    userID,
+		OrgID:     review.OrgID,
+		Type:      kind,
+		ReviewID:  review.ID,
+		ActorID:   "system",
+		CreatedAt: now,
+	})
+}
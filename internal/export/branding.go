@@ -0,0 +1,122 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+
+	// Logo formats the PDF header can embed.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+const (
+	bandHeight = 30
+	bandBottom = 792 - bandHeight
+	logoHeight = 22
+	// logoMaxPx bounds the embedded logo height in pixels; wide logos may
+	// be up to six times as wide.
+	logoMaxPx = 4 * logoHeight
+)
+
+// rgb is a color with components in [0, 1].
+type rgb struct{ r, g, b float64 }
+
+var neutralBand = rgb{0.14, 0.16, 0.18}
+
+// parseColor parses "#rrggbb", falling back to a neutral header color.
+func parseColor(s string) rgb {
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 32)
+	if err != nil || len(s) != 7 {
+		return neutralBand
+	}
+	return rgb{float64(v>>16&0xff) / 255, float64(v>>8&0xff) / 255, float64(v&0xff) / 255}
+}
+
+func (c rgb) dark() bool {
+	return 0.299*c.r+0.587*c.g+0.114*c.b < 0.6
+}
+
+// brandHeader draws the colored band with the organization name and logo.
+func brandHeader(name string, band rgb, logo *pdfImage) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "q %.3f %.3f %.3f rg 0 %d 612 %d re f Q\n", band.r, band.g, band.b, bandBottom, bandHeight)
+	if name != "" {
+		text := "1 1 1"
+		if !band.dark() {
+			text = "0 0 0"
+		}
+		fmt.Fprintf(&b, "q %s rg BT /F2 14 Tf %d %d Td (%s) Tj ET Q\n", text, pdfMarginLeft, bandBottom+10, escapePDF(asciiOnly(name)))
+	}
+	if logo != nil {
+		width := logoHeight * float64(logo.width) / float64(logo.height)
+		fmt.Fprintf(&b, "q %.2f 0 0 %d %.2f %d cm /Im1 Do Q\n", width, logoHeight, 612-pdfMarginLeft-width, bandBottom+(bandHeight-logoHeight)/2)
+	}
+	return b.String()
+}
+
+// pdfImage is an RGB image XObject.
+type pdfImage struct {
+	width, height int
+	data          []byte // zlib-compressed RGB samples
+}
+
+func (img *pdfImage) object() string {
+	return fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream",
+		img.width, img.height, len(img.data), img.data)
+}
+
+// decodeLogo converts a logo to a PDF image, downscaling large images and
+// blending transparency onto the band color. It returns nil for missing
+// or undecodable logos, which are simply left out.
+func decodeLogo(data []byte, band rgb) *pdfImage {
+	if len(data) == 0 {
+		return nil
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return nil
+	}
+	step := 1
+	for w/step > logoMaxPx*6 || h/step > logoMaxPx {
+		step++
+	}
+	out := &pdfImage{width: w / step, height: h / step}
+	if out.width == 0 {
+		out.width = 1
+	}
+	if out.height == 0 {
+		out.height = 1
+	}
+
+	var raw bytes.Buffer
+	bg := [3]float64{band.r * 0xffff, band.g * 0xffff, band.b * 0xffff}
+	for y := 0; y < out.height; y++ {
+		for x := 0; x < out.width; x++ {
+			r, g, b, a := src.At(bounds.Min.X+x*step, bounds.Min.Y+y*step).RGBA()
+			// Colors are alpha-premultiplied, so blending adds the
+			// background's share.
+			alpha := float64(a) / 0xffff
+			for i, c := range [3]uint32{r, g, b} {
+				v := float64(c) + bg[i]*(1-alpha)
+				raw.WriteByte(byte(v / 0xffff * 255))
+			}
+		}
+	}
+
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write(raw.Bytes())
+	zw.Close()
+	out.data = compressed.Bytes()
+	return out
+}
@@ -0,0 +1,150 @@
+// Package export renders reviews into archivable documents.
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// Format identifies an export document format.
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatPDF      Format = "pdf"
+)
+
+// ParseFormat validates a format query parameter. An empty value selects Markdown.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case "", FormatMarkdown:
+		return FormatMarkdown, nil
+	case FormatPDF:
+		return FormatPDF, nil
+	}
+	return "", fmt.Errorf("unsupported export format %q", s)
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	if f == FormatPDF {
+		return "application/pdf"
+	}
+	return "text/markdown; charset=utf-8"
+}
+
+// ReviewDocument gathers everything that goes into a review export.
+type ReviewDocument struct {
+	Review    *models.Review
+	Checklist []*models.ChecklistItem
+	Comments  []*models.Comment
+	Approval  *models.Approval
+	// CustomFields are the organization-defined fields set on the review.
+	CustomFields []Field
+	// Lang is the language of headings and labels; empty means English.
+	Lang string
+	// Branding, when set, adds the organization's header to PDF exports.
+	Branding *Branding
+}
+
+// Branding is an organization's look as applied to exported documents.
+type Branding struct {
+	Name string
+	// Color is the header color as "#rrggbb"; empty uses a neutral gray.
+	Color string
+	// Logo is a PNG, JPEG or GIF image; other formats are left out.
+	Logo []byte
+}
+
+// Field is a custom field value, formatted for display.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Render produces the document in the requested format.
+func Render(doc ReviewDocument, f Format) []byte {
+	md := Markdown(doc)
+	if f == FormatPDF {
+		return renderPDF(strings.Split(md, "\n"), doc.Branding)
+	}
+	return []byte(md)
+}
+
+// Markdown renders the document as Markdown.
+func Markdown(doc ReviewDocument) string {
+	var b strings.Builder
+	r := doc.Review
+	t := func(key string, args ...interface{}) string { return i18n.T(doc.Lang, key, args...) }
+
+	fmt.Fprintf(&b, "# %s\n\n", r.Title)
+	fmt.Fprintf(&b, "- %s: %s\n", t("Review ID"), r.ID)
+	fmt.Fprintf(&b, "- %s: %s\n", t("Organization"), r.OrgID)
+	fmt.Fprintf(&b, "- %s: %s\n", t("Author"), r.AuthorID)
+	fmt.Fprintf(&b, "- %s: %s\n", t("Status"), r.Status)
+	fmt.Fprintf(&b, "- %s: %s\n", t("Created"), formatTime(r.CreatedAt))
+	fmt.Fprintf(&b, "- %s: %s\n\n", t("Updated"), formatTime(r.UpdatedAt))
+
+	if len(doc.CustomFields) > 0 {
+		fmt.Fprintf(&b, "## %s\n\n", t("Custom fields"))
+		for _, f := range doc.CustomFields {
+			fmt.Fprintf(&b, "- %s: %s\n", f.Name, f.Value)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "## %s\n\n", t("Content"))
+	b.WriteString(r.Content)
+	b.WriteString("\n\n")
+
+	fmt.Fprintf(&b, "## %s\n\n", t("Checklist"))
+	if len(doc.Checklist) == 0 {
+		fmt.Fprintf(&b, "_%s_\n", t("No checklist items."))
+	}
+	for _, item := range doc.Checklist {
+		mark := " "
+		if item.Checked {
+			mark = "x"
+		}
+		fmt.Fprintf(&b, "- [%s] %s", mark, item.Text)
+		if item.Checked && item.CheckedAt != nil {
+			fmt.Fprintf(&b, " (%s, %s)", item.CheckedBy, formatTime(*item.CheckedAt))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "## %s\n\n", t("Comments"))
+	if len(doc.Comments) == 0 {
+		fmt.Fprintf(&b, "_%s_\n", t("No comments."))
+	}
+	for _, c := range doc.Comments {
+		fmt.Fprintf(&b, "**%s** — %s\n\n", c.AuthorID, formatTime(c.CreatedAt))
+		for _, line := range strings.Split(c.Body, "\n") {
+			fmt.Fprintf(&b, "> %s\n", line)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "## %s\n\n", t("Approval"))
+	if doc.Approval == nil {
+		fmt.Fprintf(&b, "_%s_\n", t("Not approved."))
+	} else {
+		b.WriteString(t("Approved by %s on %s.", doc.Approval.ApprovedBy, formatTime(doc.Approval.ApprovedAt)))
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "\n---\n%s\n", t("Exported %s", formatTime(time.Now())))
+	return b.String()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,133 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	pdfLinesPerPage = 60
+	pdfWrapColumn   = 95
+	pdfFontSize     = 10
+	pdfLeading      = 12
+	pdfMarginLeft   = 50
+	pdfMarginTop    = 792 - 50
+)
+
+// PDF renders plain text lines as a minimal multi-page PDF document using
+// the built-in Courier font. Long lines are wrapped and characters outside
+// printable ASCII are replaced, since standard fonts have no Unicode mapping.
+func PDF(lines []string) []byte {
+	return renderPDF(lines, nil)
+}
+
+// renderPDF is PDF with an optional branded header band on every page.
+func renderPDF(lines []string, branding *Branding) []byte {
+	var wrapped []string
+	for _, line := range lines {
+		wrapped = append(wrapped, wrapLine(asciiOnly(line), pdfWrapColumn)...)
+	}
+
+	var pages [][]string
+	for len(wrapped) > pdfLinesPerPage {
+		pages = append(pages, wrapped[:pdfLinesPerPage])
+		wrapped = wrapped[pdfLinesPerPage:]
+	}
+	pages = append(pages, wrapped)
+
+	// Object layout: 1 catalog, 2 page tree, 3 font, then for branded
+	// documents a header font and optionally a logo image, then a page and
+	// a content stream object per page.
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+	objects = append(objects, "") // page tree, filled in below
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>")
+	resources := "/Font << /F1 3 0 R >>"
+
+	var header string
+	if branding != nil {
+		objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold >>")
+		resources = fmt.Sprintf("/Font << /F1 3 0 R /F2 %d 0 R >>", len(objects))
+		band := parseColor(branding.Color)
+		var logo *pdfImage
+		if img := decodeLogo(branding.Logo, band); img != nil {
+			objects = append(objects, img.object())
+			resources += fmt.Sprintf(" /XObject << /Im1 %d 0 R >>", len(objects))
+			logo = img
+		}
+		header = brandHeader(branding.Name, band, logo)
+	}
+
+	first := len(objects) + 1
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", first+2*i)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	for i, page := range pages {
+		objects = append(objects, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << %s >> /Contents %d 0 R >>",
+			resources, first+2*i+1))
+
+		var stream strings.Builder
+		stream.WriteString(header)
+		fmt.Fprintf(&stream, "BT /F1 %d Tf %d TL %d %d Td\n", pdfFontSize, pdfLeading, pdfMarginLeft, pdfMarginTop)
+		for _, line := range page {
+			fmt.Fprintf(&stream, "(%s) Tj T*\n", escapePDF(line))
+		}
+		stream.WriteString("ET")
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", stream.Len(), stream.String()))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func wrapLine(line string, width int) []string {
+	if len(line) <= width {
+		return []string{line}
+	}
+	var out []string
+	for len(line) > width {
+		cut := strings.LastIndex(line[:width], " ")
+		if cut <= 0 {
+			cut = width
+		}
+		out = append(out, line[:cut])
+		line = strings.TrimLeft(line[cut:], " ")
+	}
+	return append(out, line)
+}
+
+func asciiOnly(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '—' || r == '–':
+			return '-'
+		case r == '\t':
+			return ' '
+		case r < 0x20 || r > 0x7e:
+			return '?'
+		}
+		return r
+	}, s)
+}
+
+func escapePDF(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(s)
+}
@@ -0,0 +1,638 @@
+package gates
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/andres20980/aurea-orchestrator/internal/risk"
+)
+
+// maxExprLength bounds the source of an expression.
+const maxExprLength = 2000
+
+// Expr is a compiled gate expression. The language is a small subset of
+// CEL: string, number, boolean and list literals, dotted field paths,
+// comparisons (== != < <= > >=), membership (in), !, && and ||,
+// parentheses and a few functions.
+type Expr struct {
+	src  string
+	root node
+}
+
+// String returns the expression's source.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Paths returns the field paths the expression reads, in order of first
+// use.
+func (e *Expr) Paths() []string {
+	var out []string
+	walk(e.root, func(n node) {
+		if p, ok := n.(pathNode); ok {
+			name := strings.Join(p, ".")
+			for _, seen := range out {
+				if seen == name {
+					return
+				}
+			}
+			out = append(out, name)
+		}
+	})
+	return out
+}
+
+// Eval evaluates the expression against env, which must hold a boolean
+// result.
+func (e *Expr) Eval(env map[string]interface{}) (bool, error) {
+	v, err := e.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression is %s, not a boolean", typeName(v))
+	}
+	return b, nil
+}
+
+// compile parses an expression, checking that every field path it reads
+// is one valid accepts.
+func compile(src string, valid func(path string) bool) (*Expr, error) {
+	if len(src) > maxExprLength {
+		return nil, fmt.Errorf("expression is longer than %d characters", maxExprLength)
+	}
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at offset %d", t, t.pos)
+	}
+	e := &Expr{src: src, root: root}
+	for _, path := range e.Paths() {
+		if !valid(path) {
+			return nil, fmt.Errorf("unknown field %s", path)
+		}
+	}
+	return e, nil
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+var comparisons = map[string]bool{"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ",", "."}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '_' || unicode.IsLetter(c):
+			j := i
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			toks = append(toks, token{tokIdent, src[i:j], i})
+			i = j
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(src) && unicode.IsDigit(rune(src[i+1]))):
+			j := i + 1
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.') {
+				j++
+			}
+			toks = append(toks, token{tokNumber, src[i:j], i})
+			i = j
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != src[i] {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			body := src[i+1 : j]
+			if c == '\'' {
+				body = strings.ReplaceAll(strings.ReplaceAll(body, `\'`, `'`), `"`, `\"`)
+			}
+			s, err := strconv.Unquote(`"` + body + `"`)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at offset %d", i)
+			}
+			toks = append(toks, token{tokString, s, i})
+			i = j + 1
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+			}
+			toks = append(toks, token{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(toks, token{tokEOF, "", len(src)}), nil
+}
+
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) accept(kind tokKind, text string) bool {
+	if t := p.peek(); t.kind == kind && t.text == text {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(tokOp, text) {
+		t := p.peek()
+		return fmt.Errorf("expected %q, found %s at offset %d", text, t, t.pos)
+	}
+	return nil
+}
+
+// expr parses ors of ands of comparisons of unary expressions.
+func (p *parser) expr() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokOp, "||") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{"||", left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokOp, "&&") {
+		right, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{"&&", left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) comparison() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tokOp && comparisons[t.text]:
+	case t.kind == tokIdent && t.text == "in":
+	default:
+		return left, nil
+	}
+	p.next()
+	right, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return binaryNode{t.text, left, right}, nil
+}
+
+func (p *parser) unary() (node, error) {
+	if p.accept(tokOp, "!") {
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at offset %d", t, t.pos)
+		}
+		return literalNode{f}, nil
+	case tokString:
+		return literalNode{t.text}, nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			x, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		case "[":
+			var items listNode
+			for !p.accept(tokOp, "]") {
+				if len(items) > 0 {
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+				x, err := p.expr()
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, x)
+			}
+			return items, nil
+		}
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return literalNode{true}, nil
+		case "false":
+			return literalNode{false}, nil
+		case "null":
+			return literalNode{nil}, nil
+		case "in":
+			return nil, fmt.Errorf("unexpected %s at offset %d", t, t.pos)
+		}
+		if p.accept(tokOp, "(") {
+			fn, ok := functions[t.text]
+			if !ok {
+				return nil, fmt.Errorf("unknown function %s", t.text)
+			}
+			call := callNode{name: t.text, fn: fn}
+			for !p.accept(tokOp, ")") {
+				if len(call.args) > 0 {
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+				x, err := p.expr()
+				if err != nil {
+					return nil, err
+				}
+				call.args = append(call.args, x)
+			}
+			if len(call.args) != fn.arity {
+				return nil, fmt.Errorf("%s takes %d arguments, got %d", t.text, fn.arity, len(call.args))
+			}
+			return call, nil
+		}
+		path := pathNode{t.text}
+		for p.accept(tokOp, ".") {
+			f := p.next()
+			if f.kind != tokIdent {
+				return nil, fmt.Errorf("expected a field name, found %s at offset %d", f, f.pos)
+			}
+			path = append(path, f.text)
+		}
+		return path, nil
+	}
+	return nil, fmt.Errorf("unexpected %s at offset %d", t, t.pos)
+}
+
+type node interface {
+	eval(env map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct{ v interface{} }
+
+func (n literalNode) eval(map[string]interface{}) (interface{}, error) { return n.v, nil }
+
+// pathNode reads a field; missing fields are null.
+type pathNode []string
+
+func (n pathNode) eval(env map[string]interface{}) (interface{}, error) {
+	var v interface{} = env
+	for _, name := range n {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		v = m[name]
+	}
+	return normalize(v), nil
+}
+
+type listNode []node
+
+func (n listNode) eval(env map[string]interface{}) (interface{}, error) {
+	out := make([]interface{}, len(n))
+	for i, x := range n {
+		v, err := x.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+type notNode struct{ x node }
+
+func (n notNode) eval(env map[string]interface{}) (interface{}, error) {
+	v, err := n.x.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("! needs a boolean, not %s", typeName(v))
+	}
+	return !b, nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n binaryNode) eval(env map[string]interface{}) (interface{}, error) {
+	l, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "&&" || n.op == "||" {
+		lb, ok := l.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s needs booleans, not %s", n.op, typeName(l))
+		}
+		if lb == (n.op == "||") {
+			return lb, nil
+		}
+		r, err := n.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		rb, ok := r.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s needs booleans, not %s", n.op, typeName(r))
+		}
+		return rb, nil
+	}
+	r, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equal(l, r), nil
+	case "!=":
+		return !equal(l, r), nil
+	case "in":
+		switch r := r.(type) {
+		case []interface{}:
+			for _, item := range r {
+				if equal(l, item) {
+					return true, nil
+				}
+			}
+			return false, nil
+		case map[string]interface{}:
+			key, ok := l.(string)
+			_, found := r[key]
+			return ok && found, nil
+		case nil:
+			return false, nil
+		}
+		return nil, fmt.Errorf("in needs a list, not %s", typeName(r))
+	}
+	switch l := l.(type) {
+	case float64:
+		if r, ok := r.(float64); ok {
+			return compare(n.op, l < r, l == r), nil
+		}
+	case string:
+		if r, ok := r.(string); ok {
+			return compare(n.op, l < r, l == r), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot compare %s %s %s", typeName(l), n.op, typeName(r))
+}
+
+func compare(op string, less, eq bool) bool {
+	switch op {
+	case "<":
+		return less
+	case "<=":
+		return less || eq
+	case ">":
+		return !less && !eq
+	}
+	return !less
+}
+
+type function struct {
+	arity int
+	call  func(args []interface{}) (interface{}, error)
+}
+
+type callNode struct {
+	name string
+	fn   function
+	args []node
+}
+
+func (n callNode) eval(env map[string]interface{}) (interface{}, error) {
+	args := make([]interface{}, len(n.args))
+	for i, x := range n.args {
+		v, err := x.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	v, err := n.fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return v, nil
+}
+
+// walk calls fn on n and every node below it.
+func walk(n node, fn func(node)) {
+	fn(n)
+	switch n := n.(type) {
+	case listNode:
+		for _, x := range n {
+			walk(x, fn)
+		}
+	case notNode:
+		walk(n.x, fn)
+	case binaryNode:
+		walk(n.left, fn)
+		walk(n.right, fn)
+	case callNode:
+		for _, x := range n.args {
+			walk(x, fn)
+		}
+	}
+}
+
+var errArgs = errors.New("invalid arguments")
+
+var functions = map[string]function{
+	// size(x) is the length of a list or string.
+	"size": {1, func(args []interface{}) (interface{}, error) {
+		switch v := args[0].(type) {
+		case []interface{}:
+			return float64(len(v)), nil
+		case string:
+			return float64(len(v)), nil
+		case nil:
+			return float64(0), nil
+		}
+		return nil, errArgs
+	}},
+	// contains(s, sub), starts_with(s, prefix) and ends_with(s, suffix)
+	// test strings.
+	"contains":    stringTest(strings.Contains),
+	"starts_with": stringTest(strings.HasPrefix),
+	"ends_with":   stringTest(strings.HasSuffix),
+	// matches_path(paths, pattern) reports whether any of a list of file
+	// paths matches a sensitive path pattern.
+	"matches_path": {2, func(args []interface{}) (interface{}, error) {
+		paths, ok := args[0].([]interface{})
+		pattern, ok2 := args[1].(string)
+		if !ok || !ok2 {
+			return nil, errArgs
+		}
+		for _, p := range paths {
+			if s, ok := p.(string); ok && risk.MatchPath(pattern, s) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}},
+}
+
+func stringTest(test func(s, t string) bool) function {
+	return function{2, func(args []interface{}) (interface{}, error) {
+		s, ok := args[0].(string)
+		t, ok2 := args[1].(string)
+		if !ok || !ok2 {
+			return nil, errArgs
+		}
+		return test(s, t), nil
+	}}
+}
+
+// normalize turns the values of an environment into the expression
+// language's: numbers become float64 and string slices lists.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case []string:
+		out := make([]interface{}, len(v))
+		for i, s := range v {
+			out[i] = s
+		}
+		return out
+	}
+	return v
+}
+
+func equal(a, b interface{}) bool {
+	switch a := a.(type) {
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		return false
+	}
+	if _, ok := b.(map[string]interface{}); ok {
+		return false
+	}
+	if _, ok := b.([]interface{}); ok {
+		return false
+	}
+	return a == b
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case string:
+		return "a string"
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,167 @@
+// Package gates evaluates organizations' approval gates: declarative rules,
+// written in a small CEL-like expression language, that an approval must
+// satisfy, such as "reviews labeled security need an approver from the
+// SecEng team".
+package gates
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/risk"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Fields are the field paths gate expressions may read, with what they
+// hold. Fields under review.custom_fields are the review's custom field
+// values by key.
+var Fields = map[string]string{
+	"review.id":            "the review's ID",
+	"review.title":         "the review's title",
+	"review.status":        "the review's status",
+	"review.author_id":     "the ID of the review's author",
+	"review.labels":        "the review's labels",
+	"review.priority":      "the review's priority: low, normal, high or critical",
+	"review.files":         "the file paths the review's diff touches",
+	"review.lines_changed": "the number of lines the review's diff adds or removes",
+	"review.risk.score":    "the review's risk score, 0 to 100",
+	"review.risk.level":    "the review's risk level: low, medium or high",
+	"review.custom_fields": "the review's custom field values",
+	"approver.id":          "the approver's user ID",
+	"approver.email":       "the approver's email address",
+	"approver.role":        "the approver's role",
+	"approver.teams":       "the names of the approver's teams",
+	"approver.is_author":   "whether the approver wrote the review",
+}
+
+// ValidField reports whether gate expressions may read path.
+func ValidField(path string) bool {
+	if _, ok := Fields[path]; ok {
+		return true
+	}
+	key, ok := strings.CutPrefix(path, "review.custom_fields.")
+	return ok && key != "" && !strings.Contains(key, ".")
+}
+
+// Compile compiles a gate expression.
+func Compile(src string) (*Expr, error) {
+	return compile(src, ValidField)
+}
+
+// Validate checks that a gate's expressions compile.
+func Validate(g models.ApprovalGate) error {
+	if strings.TrimSpace(g.Require) == "" {
+		return fmt.Errorf("require is empty")
+	}
+	if strings.TrimSpace(g.When) != "" {
+		if _, err := Compile(g.When); err != nil {
+			return fmt.Errorf("when: %w", err)
+		}
+	}
+	if _, err := Compile(g.Require); err != nil {
+		return fmt.Errorf("require: %w", err)
+	}
+	return nil
+}
+
+// Env returns what gate expressions see when approver approves review.
+func Env(st *store.Memory, review *models.Review, approver *models.User) map[string]interface{} {
+	diff := risk.ParseDiff(review.Content)
+	score := risk.Review(st, review)
+	custom := make(map[string]interface{})
+	for k, v := range st.GetFieldValues(review.ID) {
+		custom[k] = v
+	}
+	files := diff.Files
+	if files == nil {
+		files = []string{}
+	}
+	teams := []string{}
+	for _, t := range st.UserTeams(review.OrgID, approver.ID) {
+		teams = append(teams, t.Name)
+	}
+	return map[string]interface{}{
+		"review": map[string]interface{}{
+			"id":            review.ID,
+			"title":         review.Title,
+			"status":        review.Status,
+			"author_id":     review.AuthorID,
+			"labels":        st.GetLabels(review.ID),
+			"priority":      st.GetPriority(review.ID),
+			"files":         files,
+			"lines_changed": diff.Lines(),
+			"risk":          map[string]interface{}{"score": score.Score, "level": score.Level},
+			"custom_fields": custom,
+		},
+		"approver": map[string]interface{}{
+			"id":        approver.ID,
+			"email":     approver.Email,
+			"role":      approver.Role,
+			"teams":     teams,
+			"is_author": approver.ID == review.AuthorID,
+		},
+	}
+}
+
+// Evaluate checks an approval against the enabled gates, returning a
+// result for each. A gate whose expressions fail to evaluate fails, so a
+// broken gate never lets an approval through.
+func Evaluate(gates []models.ApprovalGate, env map[string]interface{}) []models.GateResult {
+	results := []models.GateResult{}
+	for _, g := range gates {
+		if !g.Enabled {
+			continue
+		}
+		res := models.GateResult{GateID: g.ID, Name: g.Name, Require: g.Require, Passed: true}
+		applies, err := true, error(nil)
+		if strings.TrimSpace(g.When) != "" {
+			applies, err = eval(g.When, env)
+		}
+		if err == nil && applies {
+			res.Applies = true
+			res.Passed, err = eval(g.Require, env)
+		}
+		if err != nil {
+			res.Applies, res.Passed, res.Error = true, false, err.Error()
+		}
+		if !res.Passed {
+			res.Message = g.Message
+			if res.Message == "" {
+				res.Message = fmt.Sprintf("Approval gate %q requires %s", g.Name, g.Require)
+			}
+			res.Values = values(g.Require, env)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// Failed returns the results of the gates that stop an approval.
+func Failed(results []models.GateResult) []models.GateResult {
+	return slices.DeleteFunc(slices.Clone(results), func(r models.GateResult) bool { return r.Passed })
+}
+
+func eval(src string, env map[string]interface{}) (bool, error) {
+	e, err := Compile(src)
+	if err != nil {
+		return false, err
+	}
+	return e.Eval(env)
+}
+
+// values returns the values of the fields an expression reads, to explain
+// why it does not hold.
+func values(src string, env map[string]interface{}) map[string]interface{} {
+	e, err := Compile(src)
+	if err != nil {
+		return nil
+	}
+	out := make(map[string]interface{})
+	for _, path := range e.Paths() {
+		v, _ := pathNode(strings.Split(path, ".")).eval(env)
+		out[path] = v
+	}
+	return out
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/accessreview"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// accessCampaign is a campaign with its progress and, when requested, its
+// items.
+type accessCampaign struct {
+	models.AccessCampaign
+	Progress models.AccessProgress `json:"progress"`
+	Items    []models.AccessItem   `json:"items,omitempty"`
+}
+
+// CreateAccessCampaign starts a recertification campaign. It reads
+// {"name", "deadline" (RFC 3339), "fallback_role" (default member),
+// "repeat_days"}.
+func CreateAccessCampaign(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			Name         string `json:"name"`
+			Deadline     string `json:"deadline"`
+			FallbackRole string `json:"fallback_role"`
+			RepeatDays   int    `json:"repeat_days"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Name == "" {
+			respondError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		deadline, err := time.Parse(time.RFC3339, req.Deadline)
+		if err != nil || !deadline.After(time.Now()) {
+			respondError(w, http.StatusBadRequest, "deadline must be a future RFC 3339 time")
+			return
+		}
+		if req.FallbackRole == "" {
+			req.FallbackRole = "member"
+		}
+		if !memberRoles[req.FallbackRole] || req.FallbackRole == "admin" {
+			respondErrorf(w, http.StatusBadRequest, "invalid role %q", req.FallbackRole)
+			return
+		}
+		if req.RepeatDays < 0 {
+			respondError(w, http.StatusBadRequest, "repeat_days cannot be negative")
+			return
+		}
+		c, items := accessreview.Start(st, models.AccessCampaign{
+			OrgID:        claims.OrgID,
+			Name:         req.Name,
+			FallbackRole: req.FallbackRole,
+			Deadline:     deadline,
+			RepeatDays:   req.RepeatDays,
+			CreatedBy:    claims.UserID,
+			CreatedAt:    time.Now(),
+		})
+		respondJSON(w, http.StatusCreated, accessCampaign{AccessCampaign: c, Progress: accessreview.Progress(st, c.ID), Items: items})
+	}
+}
+
+// ListAccessCampaigns returns the organization's campaigns with their
+// progress.
+func ListAccessCampaigns(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		out := []accessCampaign{}
+		for _, c := range st.ListAccessCampaigns(claims.OrgID) {
+			out = append(out, accessCampaign{AccessCampaign: c, Progress: accessreview.Progress(st, c.ID)})
+		}
+		respondJSON(w, http.StatusOK, out)
+	}
+}
+
+// GetAccessCampaign returns a campaign with its progress and every
+// member's item.
+func GetAccessCampaign(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		c, err := st.GetAccessCampaign(claims.OrgID, mux.Vars(r)["campaignId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, accessCampaign{AccessCampaign: c, Progress: accessreview.Progress(st, c.ID), Items: st.ListAccessItems(c.ID)})
+	}
+}
+
+// CertifyAccess confirms that a member keeps their role. It reads an
+// optional {"reason": "..."}. Admins cannot certify themselves.
+func CertifyAccess(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decideAccess(st, w, r, false)
+	}
+}
+
+// ChangeAccess lowers a member's role in a campaign. It reads
+// {"role": "...", "reason": "..."}; the role must grant less access than
+// the member's current one.
+func ChangeAccess(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decideAccess(st, w, r, true)
+	}
+}
+
+func decideAccess(st *store.Memory, w http.ResponseWriter, r *http.Request, change bool) {
+	claims, ok := requireOwnOrg(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		Role   string `json:"role"`
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	vars := mux.Vars(r)
+	c, err := st.GetAccessCampaign(claims.OrgID, vars["campaignId"])
+	if err != nil {
+		respondStoreError(w, err)
+		return
+	}
+	userID := vars["userId"]
+	if userID == claims.UserID {
+		respondError(w, http.StatusForbidden, "Forbidden: you cannot certify your own access")
+		return
+	}
+	now := time.Now()
+	decided := models.AccessItem{Decision: models.AccessCertified, Reason: req.Reason, DecidedBy: claims.UserID, DecidedAt: &now}
+	if change {
+		if req.Reason == "" {
+			respondError(w, http.StatusBadRequest, "reason is required")
+			return
+		}
+		user, err := st.GetUser(userID)
+		if err != nil || user.OrgID != claims.OrgID {
+			respondError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		if !memberRoles[req.Role] || !accessreview.Outranks(user.Role, req.Role) {
+			respondErrorf(w, http.StatusBadRequest, "role must grant less access than %q", user.Role)
+			return
+		}
+		decided.Decision, decided.NewRole = models.AccessChanged, req.Role
+	}
+	// A downgrade ends the member's sessions, since their tokens carry the
+	// role they were issued with.
+	var item models.AccessItem
+	err = st.Update(func(tx *store.Tx) error {
+		var err error
+		if item, err = tx.DecideAccessItem(c.ID, userID, decided); err != nil {
+			return err
+		}
+		event := &models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "access_review.certified",
+			TargetType: "user",
+			TargetID:   userID,
+			Reason:     req.Reason,
+			Metadata:   map[string]string{"campaign_id": c.ID, "from": item.Role, "to": item.Role},
+		}
+		if change {
+			if _, err := tx.SetUserRole(claims.OrgID, userID, req.Role); err != nil {
+				return err
+			}
+			tx.EndUserSessions(userID, "role downgraded", now)
+			event.Action, event.Metadata["to"] = "role.changed", req.Role
+		}
+		tx.AppendAudit(event)
+		return nil
+	})
+	if errors.Is(err, store.ErrDecided) {
+		respondError(w, http.StatusConflict, "This member has already been reviewed in this campaign")
+		return
+	}
+	if err != nil {
+		respondStoreError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, item)
+}
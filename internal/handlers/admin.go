@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/httpclient"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/replication"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// AdminListOrgs returns every organization on the instance, ordered by
+// ?sort=.
+func AdminListOrgs(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		order, ok := parseSort(w, r)
+		if !ok {
+			return
+		}
+		orgs := st.ListOrgSummaries()
+		store.SortOrgSummaries(orgs, order)
+		respondJSON(w, http.StatusOK, orgs)
+	}
+}
+
+// AdminSuspendOrg suspends an organization.
+func AdminSuspendOrg(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Reason == "" {
+			respondError(w, http.StatusBadRequest, "reason is required")
+			return
+		}
+
+		suspension := &models.OrgSuspension{
+			OrgID:       mux.Vars(r)["id"],
+			Reason:      req.Reason,
+			SuspendedBy: claims.UserID,
+			SuspendedAt: time.Now(),
+		}
+		if err := st.SuspendOrg(suspension); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, suspension)
+	}
+}
+
+// AdminUnsuspendOrg lifts an organization's suspension.
+func AdminUnsuspendOrg(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := st.UnsuspendOrg(mux.Vars(r)["id"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// AdminStats returns instance-wide statistics.
+func AdminStats(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, st.Stats())
+	}
+}
+
+// AdminIntegrationStats returns request, retry and circuit breaker counters
+// for each outbound integration.
+func AdminIntegrationStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, httpclient.Snapshot())
+	}
+}
+
+// AdminRotateEncryption re-encrypts stored fields with the active master
+// key. Run it after adding a new key so retired keys can be removed.
+func AdminRotateEncryption(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n, err := st.RotateEncryption()
+		if err != nil {
+			log.Printf("Encryption rotation stopped after %d fields: %v", n, err)
+			respondError(w, http.StatusInternalServerError, "Failed to rotate encryption keys")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]int{"rewrapped": n})
+	}
+}
+
+// AdminReplicationStatus returns the instance's replication mode, position
+// and detected conflicts.
+func AdminReplicationStatus(status func() replication.Status) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, status())
+	}
+}
+
+// AdminGetSettings returns the global instance settings.
+func AdminGetSettings(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, st.Settings())
+	}
+}
+
+// AdminUpdateSettings merges the request body into the global instance settings.
+func AdminUpdateSettings(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+
+		var req struct {
+			Values map[string]string `json:"values"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		respondJSON(w, http.StatusOK, st.UpdateSettings(req.Values, claims.UserID))
+	}
+}
+
+// AdminGetMaintenance returns the instance maintenance mode state.
+func AdminGetMaintenance(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, st.Maintenance())
+	}
+}
+
+// AdminSetMaintenance toggles instance-wide read-only maintenance mode.
+func AdminSetMaintenance(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+
+		var req struct {
+			Enabled bool   `json:"enabled"`
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		mode := models.MaintenanceMode{
+			Enabled:   req.Enabled,
+			Message:   req.Message,
+			UpdatedBy: claims.UserID,
+			UpdatedAt: time.Now(),
+		}
+		st.SetMaintenance(mode)
+		respondJSON(w, http.StatusOK, mode)
+	}
+}
+
+// respondStoreError maps store errors to HTTP responses.
+func respondStoreError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		respondError(w, http.StatusNotFound, "Not found")
+		return
+	case errors.Is(err, store.ErrDuplicate):
+		respondError(w, http.StatusConflict, "Already exists")
+		return
+	case errors.Is(err, store.ErrLegalHold):
+		respondError(w, http.StatusConflict, "Blocked by a legal hold")
+		return
+	case errors.Is(err, store.ErrFieldCipher):
+		// The cause may name keys; it belongs in the server log.
+		log.Printf("Store encryption failed: %v", err)
+		respondError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	respondError(w, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/ai"
+	"github.com/andres20980/aurea-orchestrator/internal/llm"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/operations"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// llmChoice reads the optional {"provider": "...", "model": "..."} body of
+// an AI request and returns the organization's provider to use.
+func llmChoice(w http.ResponseWriter, r *http.Request, providers *ai.Providers, orgID string) (ai.Choice, string, bool) {
+	var req struct {
+		Provider string `json:"provider"`
+		Model    string `json:"model"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return ai.Choice{}, "", false
+	}
+	c, ok := chooseLLM(w, providers, orgID, req.Provider)
+	return c, req.Model, ok
+}
+
+// chooseLLM returns the organization's provider called name, or its
+// preferred one. Requests are refused once the organization's monthly
+// budget is spent.
+func chooseLLM(w http.ResponseWriter, providers *ai.Providers, orgID, name string) (ai.Choice, bool) {
+	if err := providers.CheckBudget(orgID); err != nil {
+		respondError(w, http.StatusTooManyRequests, "AI budget for this month is exhausted")
+		return ai.Choice{}, false
+	}
+	c, err := providers.For(orgID, name)
+	switch {
+	case errors.Is(err, llm.ErrNoProvider) && name == "":
+		respondError(w, http.StatusServiceUnavailable, "No AI provider is configured")
+		return ai.Choice{}, false
+	case errors.Is(err, llm.ErrNoProvider):
+		respondErrorf(w, http.StatusBadRequest, "AI provider %q is not configured", name)
+		return ai.Choice{}, false
+	case err != nil:
+		respondErrorf(w, http.StatusServiceUnavailable, "AI provider is misconfigured: %s", err.Error())
+		return ai.Choice{}, false
+	}
+	return c, true
+}
+
+// SummarizeReview queues a language model summary of the review with its
+// risk highlights. The body may pick a configured provider and model; the
+// summary is stored on the review when the operation succeeds.
+func SummarizeReview(st *store.Memory, runner *operations.Runner, providers *ai.Providers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		c, model, ok := llmChoice(w, r, providers, review.OrgID)
+		if !ok {
+			return
+		}
+		usage := models.LLMUsage{
+			OrgID:    review.OrgID,
+			Feature:  models.AIFeatureSummary,
+			ReviewID: review.ID,
+			UserID:   claims.UserID,
+		}
+		op := runner.Start(models.Operation{
+			OrgID:     review.OrgID,
+			Kind:      "review.summarize",
+			CreatedBy: claims.UserID,
+		}, func(ctx context.Context, t *operations.Task) (interface{}, error) {
+			current, err := st.GetReview(review.ID)
+			if err != nil {
+				return nil, err
+			}
+			prompt := ai.ActivePrompt(st, review.OrgID, models.AIFeatureSummary)
+			summary, resp, err := ai.Summarize(ctx, c.Provider, model, prompt, current, st.ListComments(review.ID))
+			providers.Record(usage, c, resp)
+			if err != nil {
+				return nil, err
+			}
+			summary.RequestedBy = claims.UserID
+			st.SetReviewSummary(summary)
+			return summary, nil
+		})
+		respondOperation(w, op)
+	}
+}
+
+// GetReviewSummary returns the latest AI summary of the review.
+func GetReviewSummary(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		summary, err := st.GetReviewSummary(review.ID)
+		if err != nil {
+			respondError(w, http.StatusNotFound, "Review has not been summarized")
+			return
+		}
+		respondJSON(w, http.StatusOK, summary)
+	}
+}
+
+// llmUsageTotal adds up language model requests.
+type llmUsageTotal struct {
+	Provider     string  `json:"provider,omitempty"`
+	Model        string  `json:"model,omitempty"`
+	Feature      string  `json:"feature,omitempty"`
+	Requests     int     `json:"requests"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+func (t *llmUsageTotal) add(u models.LLMUsage) {
+	t.Requests++
+	t.InputTokens += u.InputTokens
+	t.OutputTokens += u.OutputTokens
+	t.CostUSD += u.CostUSD
+}
+
+// GetLLMUsage returns the organization's language model token usage and
+// cost in the calendar month ?month= (YYYY-MM, UTC, default the current
+// one), in total and by provider, model and feature. ?detail=true adds every
+// request.
+func GetLLMUsage(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		from := ai.MonthStart(time.Now())
+		if v := r.URL.Query().Get("month"); v != "" {
+			t, err := time.Parse("2006-01", v)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "month must be YYYY-MM")
+				return
+			}
+			from = t
+		}
+		records := st.ListLLMUsage(claims.OrgID, from, from.AddDate(0, 1, 0))
+
+		var total llmUsageTotal
+		groups := make(map[[3]string]*llmUsageTotal)
+		for _, u := range records {
+			total.add(u)
+			key := [3]string{u.Provider, u.Model, u.Feature}
+			g, ok := groups[key]
+			if !ok {
+				g = &llmUsageTotal{Provider: u.Provider, Model: u.Model, Feature: u.Feature}
+				groups[key] = g
+			}
+			g.add(u)
+		}
+		breakdown := make([]llmUsageTotal, 0, len(groups))
+		for _, g := range groups {
+			breakdown = append(breakdown, *g)
+		}
+		sort.Slice(breakdown, func(i, j int) bool {
+			a, b := breakdown[i], breakdown[j]
+			if a.Provider != b.Provider {
+				return a.Provider < b.Provider
+			}
+			if a.Model != b.Model {
+				return a.Model < b.Model
+			}
+			return a.Feature < b.Feature
+		})
+		resp := map[string]interface{}{
+			"month":     from.Format("2006-01"),
+			"total":     total,
+			"breakdown": breakdown,
+		}
+		if r.URL.Query().Get("detail") == "true" {
+			resp["requests"] = records
+		}
+		respondJSON(w, http.StatusOK, resp)
+	}
+}
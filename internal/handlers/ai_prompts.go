@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/ai"
+	"github.com/andres20980/aurea-orchestrator/internal/llm"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/operations"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// promptStatus is the prompt an AI feature uses in an organization.
+type promptStatus struct {
+	Feature string                `json:"feature"`
+	Active  models.PromptTemplate `json:"active"`
+	// Custom is false when the built-in default is in use.
+	Custom    bool                `json:"custom"`
+	Variables []ai.PromptVariable `json:"variables"`
+}
+
+func currentPrompt(st *store.Memory, orgID, feature string) promptStatus {
+	t, custom := st.ActivePrompt(orgID, feature)
+	if !custom {
+		t, _ = ai.DefaultPrompt(feature)
+	}
+	return promptStatus{Feature: feature, Active: t, Custom: custom, Variables: ai.PromptVariables(feature)}
+}
+
+// promptFeature reads the AI feature in the URL.
+func promptFeature(w http.ResponseWriter, r *http.Request) (string, bool) {
+	feature := mux.Vars(r)["feature"]
+	if !slices.Contains(models.AIFeatures, feature) {
+		respondErrorf(w, http.StatusNotFound, "Unknown AI feature %q", feature)
+		return "", false
+	}
+	return feature, true
+}
+
+// ListPrompts returns the prompt each AI feature uses in the organization
+// with the variables it can use.
+func ListPrompts(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		out := make([]promptStatus, 0, len(models.AIFeatures))
+		for _, feature := range models.AIFeatures {
+			out = append(out, currentPrompt(st, claims.OrgID, feature))
+		}
+		respondJSON(w, http.StatusOK, out)
+	}
+}
+
+// GetPrompt returns the prompt an AI feature uses in the organization, or
+// with ?version= one of its versions (0 is the built-in default).
+func GetPrompt(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		feature, ok := promptFeature(w, r)
+		if !ok {
+			return
+		}
+		v := r.URL.Query().Get("version")
+		if v == "" {
+			respondJSON(w, http.StatusOK, currentPrompt(st, claims.OrgID, feature))
+			return
+		}
+		version, err := strconv.Atoi(v)
+		if err != nil || version < 0 {
+			respondError(w, http.StatusBadRequest, "version must be a non-negative integer")
+			return
+		}
+		if version == 0 {
+			t, _ := ai.DefaultPrompt(feature)
+			respondJSON(w, http.StatusOK, t)
+			return
+		}
+		t, err := st.GetPromptVersion(claims.OrgID, feature, version)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, t)
+	}
+}
+
+// ListPromptVersions returns every version of the organization's prompt
+// for an AI feature, oldest first.
+func ListPromptVersions(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		feature, ok := promptFeature(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.PromptVersions(claims.OrgID, feature))
+	}
+}
+
+// readPrompt reads {"system": "...", "prompt": "...", "note": "..."} and
+// checks that it renders with the feature's variables.
+func readPrompt(w http.ResponseWriter, body io.Reader, feature string) (models.PromptTemplate, bool) {
+	var t models.PromptTemplate
+	if err := json.NewDecoder(body).Decode(&t); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return t, false
+	}
+	t.Feature = feature
+	if err := ai.ValidatePrompt(t); err != nil {
+		respondErrorf(w, http.StatusUnprocessableEntity, "Invalid prompt: %s", err.Error())
+		return t, false
+	}
+	return t, true
+}
+
+// SetPrompt stores a new version of the organization's prompt for an AI
+// feature and puts it in use.
+func SetPrompt(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		feature, ok := promptFeature(w, r)
+		if !ok {
+			return
+		}
+		t, ok := readPrompt(w, r.Body, feature)
+		if !ok {
+			return
+		}
+		t.OrgID = claims.OrgID
+		t.CreatedBy = claims.UserID
+		t.CreatedAt = time.Now()
+		t = st.AddPromptVersion(t)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "ai_prompt.updated",
+			TargetType: "ai_prompt",
+			TargetID:   feature,
+			Metadata:   map[string]string{"version": strconv.Itoa(t.Version)},
+		})
+		respondJSON(w, http.StatusOK, currentPrompt(st, claims.OrgID, feature))
+	}
+}
+
+// RollbackPrompt reads {"version": n} and puts that version of the
+// organization's prompt for an AI feature back in use; 0 or no version
+// rolls back to the built-in default.
+func RollbackPrompt(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		feature, ok := promptFeature(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			Version int `json:"version"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if err := st.SetActivePrompt(claims.OrgID, feature, req.Version); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "ai_prompt.rolled_back",
+			TargetType: "ai_prompt",
+			TargetID:   feature,
+			Metadata:   map[string]string{"version": strconv.Itoa(req.Version)},
+		})
+		respondJSON(w, http.StatusOK, currentPrompt(st, claims.OrgID, feature))
+	}
+}
+
+// promptTestResult is the outcome of a prompt test run. Error explains
+// why an answer could not be read.
+type promptTestResult struct {
+	Prompt      ai.RenderedPrompt     `json:"prompt"`
+	Provider    string                `json:"provider,omitempty"`
+	Model       string                `json:"model,omitempty"`
+	Answer      string                `json:"answer,omitempty"`
+	Summary     *models.ReviewSummary `json:"summary,omitempty"`
+	Suggestions []models.Suggestion   `json:"suggestions,omitempty"`
+	Error       string                `json:"error,omitempty"`
+}
+
+// TestPrompt tries a prompt for an AI feature on one of the
+// organization's reviews without storing anything. The body names the
+// review_id and either a draft {"system", "prompt"}, a stored "version"
+// (0 is the built-in default) or neither for the prompt in use; it may
+// pick a provider and model. With "render_only" the rendered prompt is
+// returned at once; otherwise the model is asked in an operation whose
+// result holds its answer.
+func TestPrompt(st *store.Memory, runner *operations.Runner, providers *ai.Providers, suggester *ai.Suggester) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		feature, ok := promptFeature(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			ReviewID   string `json:"review_id"`
+			System     string `json:"system"`
+			Prompt     string `json:"prompt"`
+			Version    *int   `json:"version"`
+			Provider   string `json:"provider"`
+			Model      string `json:"model"`
+			RenderOnly bool   `json:"render_only"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		review, err := st.GetReview(req.ReviewID)
+		if err != nil || review.OrgID != claims.OrgID {
+			respondError(w, http.StatusNotFound, "Review not found")
+			return
+		}
+
+		var t models.PromptTemplate
+		switch {
+		case req.System != "" || req.Prompt != "":
+			t = models.PromptTemplate{Feature: feature, System: req.System, Prompt: req.Prompt}
+			if err := ai.ValidatePrompt(t); err != nil {
+				respondErrorf(w, http.StatusUnprocessableEntity, "Invalid prompt: %s", err.Error())
+				return
+			}
+		case req.Version != nil && *req.Version == 0:
+			t, _ = ai.DefaultPrompt(feature)
+		case req.Version != nil:
+			if t, err = st.GetPromptVersion(claims.OrgID, feature, *req.Version); err != nil {
+				respondStoreError(w, err)
+				return
+			}
+		default:
+			t = ai.ActivePrompt(st, claims.OrgID, feature)
+		}
+
+		var rendered ai.RenderedPrompt
+		var input ai.SuggestInput
+		var comments []*models.Comment
+		if feature == models.AIFeatureSuggestions {
+			input = suggester.Input(review)
+			rendered, err = ai.SuggestPrompt(t, input)
+		} else {
+			comments = st.ListComments(review.ID)
+			rendered, err = ai.SummaryPrompt(t, review, comments)
+		}
+		if err != nil {
+			respondErrorf(w, http.StatusUnprocessableEntity, "Invalid prompt: %s", err.Error())
+			return
+		}
+		if req.RenderOnly {
+			respondJSON(w, http.StatusOK, promptTestResult{Prompt: rendered})
+			return
+		}
+
+		c, ok := chooseLLM(w, providers, claims.OrgID, req.Provider)
+		if !ok {
+			return
+		}
+		usage := models.LLMUsage{
+			OrgID:    claims.OrgID,
+			Feature:  feature,
+			ReviewID: review.ID,
+			UserID:   claims.UserID,
+		}
+		op := runner.Start(models.Operation{
+			OrgID:     claims.OrgID,
+			Kind:      "ai.prompt_test",
+			CreatedBy: claims.UserID,
+		}, func(ctx context.Context, _ *operations.Task) (interface{}, error) {
+			result := promptTestResult{Prompt: rendered, Provider: c.Provider.Name()}
+			var resp llm.Response
+			var err error
+			if feature == models.AIFeatureSuggestions {
+				result.Suggestions, resp, err = ai.Suggest(ctx, c.Provider, req.Model, t, input)
+			} else {
+				var summary models.ReviewSummary
+				summary, resp, err = ai.Summarize(ctx, c.Provider, req.Model, t, review, comments)
+				if err == nil {
+					result.Summary = &summary
+				}
+			}
+			providers.Record(usage, c, resp)
+			result.Model, result.Answer = resp.Model, resp.Text
+			if err != nil {
+				if result.Answer == "" {
+					return nil, err
+				}
+				result.Error = err.Error()
+			}
+			return result, nil
+		})
+		respondOperation(w, op)
+	}
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/ai"
+	"github.com/andres20980/aurea-orchestrator/internal/egress"
+	"github.com/andres20980/aurea-orchestrator/internal/llm"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+func maskLLMProvider(cfg models.LLMProviderConfig) models.LLMProviderConfig {
+	if cfg.APIKey != "" {
+		cfg.APIKey = maskedSecret
+	}
+	return cfg
+}
+
+// ListLLMProviders returns the language model providers the organization
+// configured, in order of preference, with their API keys masked, and
+// the names and health of every provider its AI requests may use.
+func ListLLMProviders(st *store.Memory, providers *ai.Providers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		configs := st.ListLLMProviders(claims.OrgID)
+		for i := range configs {
+			configs[i] = maskLLMProvider(configs[i])
+		}
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"providers": configs,
+			"available": providers.Names(claims.OrgID),
+			"health":    providers.Health(claims.OrgID),
+		})
+	}
+}
+
+// SetLLMProvider creates or replaces the organization's provider named in
+// the URL. An omitted or masked api_key keeps the current one, but only
+// while the type and base_url stay the same, so a stored key is never sent
+// to a new endpoint. A base_url must be a destination policy lets the
+// organization's providers reach.
+func SetLLMProvider(st *store.Memory, policy egress.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var cfg models.LLMProviderConfig
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&cfg); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		cfg.OrgID = claims.OrgID
+		cfg.Name = mux.Vars(r)["name"]
+		if !slices.Contains(models.LLMTypes, cfg.Type) {
+			respondErrorf(w, http.StatusBadRequest, "Unknown provider type %q", cfg.Type)
+			return
+		}
+		if cfg.BaseURL != "" {
+			u, err := url.Parse(cfg.BaseURL)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				respondError(w, http.StatusBadRequest, "base_url must be an absolute http(s) URL")
+				return
+			}
+			if err := policy.CheckHost(r.Context(), u.Hostname()); errors.Is(err, egress.ErrDenied) {
+				respondError(w, http.StatusBadRequest, "base_url is not an allowed destination")
+				return
+			} else if err != nil {
+				respondError(w, http.StatusBadRequest, "base_url host could not be resolved")
+				return
+			}
+		}
+		if cfg.InputPrice < 0 || cfg.OutputPrice < 0 {
+			respondError(w, http.StatusBadRequest, "Prices must not be negative")
+			return
+		}
+		if cfg.APIKey == "" || cfg.APIKey == maskedSecret {
+			cfg.APIKey = ""
+			for _, current := range st.ListLLMProviders(claims.OrgID) {
+				if current.Name == cfg.Name && current.Type == cfg.Type && current.BaseURL == cfg.BaseURL {
+					cfg.APIKey = current.APIKey
+				}
+			}
+		}
+		if cfg.APIKey == "" && cfg.Type != models.LLMOllama && cfg.Type != models.LLMLocal {
+			respondError(w, http.StatusBadRequest, "api_key is required")
+			return
+		}
+		if _, err := llm.FromConfig(nil, cfg); err != nil {
+			respondErrorf(w, http.StatusBadRequest, "Invalid provider: %s", err.Error())
+			return
+		}
+		cfg.UpdatedBy = claims.UserID
+		cfg.UpdatedAt = time.Now()
+		st.SetLLMProvider(cfg)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "llm_provider.updated",
+			TargetType: "llm_provider",
+			TargetID:   cfg.Name,
+			Metadata:   map[string]string{"type": cfg.Type, "model": cfg.Model, "disabled": strconv.FormatBool(cfg.Disabled)},
+		})
+		respondJSON(w, http.StatusOK, maskLLMProvider(cfg))
+	}
+}
+
+// DeleteLLMProvider removes one of the organization's providers.
+func DeleteLLMProvider(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		name := mux.Vars(r)["name"]
+		if err := st.DeleteLLMProvider(claims.OrgID, name); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "llm_provider.deleted",
+			TargetType: "llm_provider",
+			TargetID:   name,
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// CheckLLMProvider checks the health of a provider available to the
+// organization now. Only the status and latency are returned; why a
+// check failed is in the server log.
+func CheckLLMProvider(providers *ai.Providers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		name := mux.Vars(r)["name"]
+		h, err := providers.Check(r.Context(), claims.OrgID, name)
+		if errors.Is(err, llm.ErrNoProvider) {
+			respondErrorf(w, http.StatusNotFound, "AI provider %q is not configured", name)
+			return
+		}
+		if err != nil {
+			respondErrorf(w, http.StatusBadRequest, "Invalid provider: %s", err.Error())
+			return
+		}
+		respondJSON(w, http.StatusOK, h)
+	}
+}
+
+// aiBudgetStatus is an organization's AI budget and what it spent of it
+// this month.
+type aiBudgetStatus struct {
+	Month      string     `json:"month"`
+	MonthlyUSD float64    `json:"monthly_usd"`
+	SpentUSD   float64    `json:"spent_usd"`
+	Paused     bool       `json:"paused"`
+	UpdatedBy  string     `json:"updated_by,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+func budgetStatus(st *store.Memory, providers *ai.Providers, orgID string) aiBudgetStatus {
+	now := time.Now()
+	s := aiBudgetStatus{
+		Month:    ai.MonthStart(now).Format("2006-01"),
+		SpentUSD: providers.MonthCost(orgID, now),
+		Paused:   providers.CheckBudget(orgID) != nil,
+	}
+	if b, ok := st.GetAIBudget(orgID); ok {
+		s.MonthlyUSD, s.UpdatedBy, s.UpdatedAt = b.MonthlyUSD, b.UpdatedBy, &b.UpdatedAt
+	}
+	return s
+}
+
+// GetAIBudget returns the organization's monthly AI budget, what it spent
+// this month and whether AI features are paused until the next one.
+func GetAIBudget(st *store.Memory, providers *ai.Providers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, budgetStatus(st, providers, claims.OrgID))
+	}
+}
+
+// SetAIBudget reads {"monthly_usd": n} and caps what the organization's
+// AI features may cost each month; zero removes the cap.
+func SetAIBudget(st *store.Memory, providers *ai.Providers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			MonthlyUSD float64 `json:"monthly_usd"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.MonthlyUSD < 0 {
+			respondError(w, http.StatusBadRequest, "monthly_usd must not be negative")
+			return
+		}
+		st.SetAIBudget(models.AIBudget{
+			OrgID:      claims.OrgID,
+			MonthlyUSD: req.MonthlyUSD,
+			UpdatedBy:  claims.UserID,
+			UpdatedAt:  time.Now(),
+		})
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "ai_budget.updated",
+			TargetType: "org",
+			TargetID:   claims.OrgID,
+			Metadata:   map[string]string{"monthly_usd": strconv.FormatFloat(req.MonthlyUSD, 'f', 2, 64)},
+		})
+		respondJSON(w, http.StatusOK, budgetStatus(st, providers, claims.OrgID))
+	}
+}
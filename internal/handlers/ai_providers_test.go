@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/andres20980/aurea-orchestrator/internal/ai"
+	"github.com/andres20980/aurea-orchestrator/internal/egress"
+	"github.com/andres20980/aurea-orchestrator/internal/llm"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+func TestSetLLMProviderBaseURL(t *testing.T) {
+	st := store.NewMemory()
+	admin := newUser(t, st, "acme", "admin@acme.test", "admin", "")
+	policy := egress.Policy{AllowNetworks: []netip.Prefix{netip.MustParsePrefix("10.20.0.0/16")}}
+
+	tests := []struct {
+		name    string
+		baseURL string
+		want    int
+	}{
+		{"public", "https://93.184.216.34/v1", http.StatusOK},
+		{"allowlisted network", "http://10.20.0.7:8000/v1", http.StatusOK},
+		{"loopback", "http://127.0.0.1:6379/v1", http.StatusBadRequest},
+		{"localhost", "http://localhost:11434/v1", http.StatusBadRequest},
+		{"cloud metadata", "http://169.254.169.254/latest", http.StatusBadRequest},
+		{"private", "http://192.168.1.10/v1", http.StatusBadRequest},
+		{"not a URL", "ftp://example.com", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"type":"local","model":"llama","base_url":"` + tt.baseURL + `"}`
+			req := asUser(httptest.NewRequest("PUT", "/", strings.NewReader(body)), admin)
+			req = mux.SetURLVars(req, map[string]string{"id": "acme", "name": "own"})
+			rec := httptest.NewRecorder()
+			SetLLMProvider(st, policy)(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestSetLLMProviderKeepsKey(t *testing.T) {
+	const oldURL, newURL = "https://93.184.216.34/v1", "https://93.184.216.35/v1"
+
+	tests := []struct {
+		name    string
+		body    string
+		want    int
+		wantKey string
+	}{
+		{"same endpoint, masked key", `{"type":"openai","model":"gpt","base_url":"` + oldURL + `","api_key":"` + maskedSecret + `"}`, http.StatusOK, "sk-old"},
+		{"same endpoint, no key", `{"type":"openai","model":"gpt","base_url":"` + oldURL + `"}`, http.StatusOK, "sk-old"},
+		{"new base_url, no key", `{"type":"openai","model":"gpt","base_url":"` + newURL + `"}`, http.StatusBadRequest, "sk-old"},
+		{"base_url removed, masked key", `{"type":"openai","model":"gpt","api_key":"` + maskedSecret + `"}`, http.StatusBadRequest, "sk-old"},
+		{"new type, no key", `{"type":"azure","model":"gpt","base_url":"` + oldURL + `"}`, http.StatusBadRequest, "sk-old"},
+		{"new base_url, new key", `{"type":"openai","model":"gpt","base_url":"` + newURL + `","api_key":"sk-new"}`, http.StatusOK, "sk-new"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := store.NewMemory()
+			admin := newUser(t, st, "acme", "admin@acme.test", "admin", "")
+			st.SetLLMProvider(models.LLMProviderConfig{OrgID: "acme", Name: "main", Type: models.LLMOpenAI, BaseURL: oldURL, Model: "gpt", APIKey: "sk-old"})
+
+			req := asUser(httptest.NewRequest("PUT", "/", strings.NewReader(tt.body)), admin)
+			req = mux.SetURLVars(req, map[string]string{"id": "acme", "name": "main"})
+			rec := httptest.NewRecorder()
+			SetLLMProvider(st, egress.Policy{})(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusBadRequest && !strings.Contains(rec.Body.String(), "api_key is required") {
+				t.Errorf("body = %s, want api_key is required", rec.Body)
+			}
+			for _, cfg := range st.ListLLMProviders("acme") {
+				if cfg.APIKey != tt.wantKey {
+					t.Errorf("stored key = %q, want %q", cfg.APIKey, tt.wantKey)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckLLMProvider(t *testing.T) {
+	const leak = "redis_version:7.2.4"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/down") {
+			http.Error(w, leak, http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+	st := store.NewMemory()
+	admin := newUser(t, st, "acme", "admin@acme.test", "admin", "")
+	st.SetLLMProvider(models.LLMProviderConfig{OrgID: "acme", Name: "up", Type: models.LLMLocal, BaseURL: srv.URL + "/up", Model: "m"})
+	st.SetLLMProvider(models.LLMProviderConfig{OrgID: "acme", Name: "down", Type: models.LLMLocal, BaseURL: srv.URL + "/down", Model: "m"})
+	providers := ai.NewProviders(st, srv.Client(), &llm.Registry{})
+
+	tests := []struct {
+		name   string
+		status string
+		want   int
+	}{
+		{"up", ai.HealthHealthy, http.StatusOK},
+		{"down", ai.HealthUnhealthy, http.StatusOK},
+		{"missing", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := asUser(httptest.NewRequest("POST", "/", nil), admin)
+			req = mux.SetURLVars(req, map[string]string{"id": "acme", "name": tt.name})
+			rec := httptest.NewRecorder()
+			CheckLLMProvider(providers)(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if strings.Contains(rec.Body.String(), leak) || strings.Contains(rec.Body.String(), "127.0.0.1") {
+				t.Errorf("response reveals provider details: %s", rec.Body)
+			}
+			if tt.status == "" {
+				return
+			}
+			var h map[string]interface{}
+			json.Unmarshal(rec.Body.Bytes(), &h)
+			if h["status"] != tt.status {
+				t.Errorf("health = %v, want status %s", h, tt.status)
+			}
+			for k := range h {
+				if k != "provider" && k != "status" && k != "latency_ms" && k != "checked_at" {
+					t.Errorf("health has field %q", k)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/ai"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/secrets"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// maxRedactionRecords bounds the redaction records listed at once.
+const maxRedactionRecords = 200
+
+// GetAIRedactionPolicy returns what the organization redacts from prompts
+// before they reach hosted language models.
+func GetAIRedactionPolicy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.GetAIRedactionPolicy(claims.OrgID))
+	}
+}
+
+// SetAIRedactionPolicy replaces the organization's AI redaction policy.
+func SetAIRedactionPolicy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var policy models.AIRedactionPolicy
+		if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		for _, kind := range policy.PII {
+			if !slices.Contains(models.PIIKinds, kind) {
+				respondErrorf(w, http.StatusBadRequest, "Unknown kind of personal data %q", kind)
+				return
+			}
+		}
+		if len(policy.Rules) > maxSecretRules {
+			respondErrorf(w, http.StatusBadRequest, "At most %d redaction rules are allowed", maxSecretRules)
+			return
+		}
+		ids := make(map[string]bool)
+		for _, rule := range policy.Rules {
+			if rule.ID == "" || ids[rule.ID] {
+				respondErrorf(w, http.StatusBadRequest, "Redaction rule IDs must be set and unique: %q", rule.ID)
+				return
+			}
+			ids[rule.ID] = true
+			if _, err := secrets.Compile(rule); err != nil || rule.MinEntropy < 0 {
+				respondErrorf(w, http.StatusBadRequest, "Redaction rule %q needs a valid regular expression and a non-negative min_entropy", rule.ID)
+				return
+			}
+		}
+		if policy.PII == nil {
+			policy.PII = []string{}
+		}
+		if policy.Rules == nil {
+			policy.Rules = []models.SecretRule{}
+		}
+		policy.OrgID = claims.OrgID
+		policy.UpdatedBy = claims.UserID
+		policy.UpdatedAt = time.Now()
+		st.SetAIRedactionPolicy(policy)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "ai_redaction_policy.updated",
+			TargetType: "org",
+			TargetID:   claims.OrgID,
+			Metadata: map[string]string{
+				"enabled": strconv.FormatBool(policy.Enabled),
+				"secrets": strconv.FormatBool(policy.Secrets),
+				"rules":   strconv.Itoa(len(policy.Rules)),
+			},
+		})
+		respondJSON(w, http.StatusOK, policy)
+	}
+}
+
+// TestAIRedaction reads {"text": "..."} and returns it as the
+// organization's policy would send it to a hosted model, with what was
+// redacted.
+func TestAIRedaction(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		policy := st.GetAIRedactionPolicy(claims.OrgID)
+		if !policy.Enabled {
+			respondJSON(w, http.StatusOK, map[string]interface{}{"text": req.Text, "entries": []models.AIRedactionEntry{}})
+			return
+		}
+		redactor, err := ai.NewRedactor(policy, st.GetSecretPolicy(claims.OrgID))
+		if err != nil {
+			respondErrorf(w, http.StatusUnprocessableEntity, "Invalid redaction policy: %s", err.Error())
+			return
+		}
+		x := redactor.Start()
+		text := x.Redact(req.Text)
+		respondJSON(w, http.StatusOK, map[string]interface{}{"text": text, "entries": x.Entries()})
+	}
+}
+
+// ListAIRedactions returns what was redacted from the organization's
+// latest language model requests, newest first; ?limit= caps how many
+// (default and maximum 200). Values are only shown masked.
+func ListAIRedactions(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		limit := maxRedactionRecords
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				respondError(w, http.StatusBadRequest, "limit must be a positive integer")
+				return
+			}
+			limit = min(n, maxRedactionRecords)
+		}
+		respondJSON(w, http.StatusOK, st.ListAIRedactions(claims.OrgID, limit))
+	}
+}
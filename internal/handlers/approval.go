@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/signing"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// GetApprovalProof returns the signed approval record of a review.
+func GetApprovalProof(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+
+		approval, ok := st.GetApproval(review.ID)
+		if !ok || approval.Proof == nil {
+			respondError(w, http.StatusNotFound, "Review has no approval proof")
+			return
+		}
+		respondJSON(w, http.StatusOK, approval.Proof)
+	}
+}
+
+// GetApprovalKey publishes the public key used to sign approval proofs so
+// they can be verified offline.
+func GetApprovalKey(signer *signing.Signer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, map[string]string{
+			"algorithm":  signing.Algorithm,
+			"key_id":     signer.KeyID(),
+			"public_key": signer.PublicKey(),
+		})
+	}
+}
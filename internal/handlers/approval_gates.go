@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/gates"
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+type approvalGateRequest struct {
+	Name    string `json:"name"`
+	When    string `json:"when"`
+	Require string `json:"require"`
+	Message string `json:"message"`
+	Enabled *bool  `json:"enabled"`
+}
+
+func (req approvalGateRequest) validate(w http.ResponseWriter) bool {
+	if strings.TrimSpace(req.Name) == "" {
+		respondError(w, http.StatusBadRequest, "name is required")
+		return false
+	}
+	if err := gates.Validate(models.ApprovalGate{When: req.When, Require: req.Require}); err != nil {
+		respondErrorf(w, http.StatusUnprocessableEntity, "Invalid gate expression: %s", err.Error())
+		return false
+	}
+	return true
+}
+
+func (req approvalGateRequest) apply(g *models.ApprovalGate) {
+	g.Name, g.When, g.Require, g.Message = strings.TrimSpace(req.Name), req.When, req.Require, req.Message
+	if req.Enabled != nil {
+		g.Enabled = *req.Enabled
+	}
+}
+
+// ListApprovalGates returns the organization's approval gates.
+func ListApprovalGates(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListApprovalGates(claims.OrgID))
+	}
+}
+
+// ListApprovalGateFields returns the fields gate expressions may read.
+func ListApprovalGateFields(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, gates.Fields)
+}
+
+// CreateApprovalGate adds a gate approvals must pass: when "when" holds,
+// or always when it is empty, "require" must. Gates are enabled unless
+// "enabled" is false.
+func CreateApprovalGate(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req approvalGateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !req.validate(w) {
+			return
+		}
+		now := time.Now()
+		g := models.ApprovalGate{OrgID: claims.OrgID, Enabled: true, CreatedBy: claims.UserID, CreatedAt: now, UpdatedAt: now}
+		req.apply(&g)
+		g = st.CreateApprovalGate(g)
+		auditApprovalGate(st, claims.UserID, "approval_gate.created", g)
+		respondJSON(w, http.StatusCreated, g)
+	}
+}
+
+// GetApprovalGate returns one of the organization's approval gates.
+func GetApprovalGate(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		g, err := st.GetApprovalGate(claims.OrgID, mux.Vars(r)["gateId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, g)
+	}
+}
+
+// UpdateApprovalGate replaces one of the organization's approval gates. An
+// omitted "enabled" keeps the current state.
+func UpdateApprovalGate(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		g, err := st.GetApprovalGate(claims.OrgID, mux.Vars(r)["gateId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		var req approvalGateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !req.validate(w) {
+			return
+		}
+		req.apply(&g)
+		g.UpdatedAt = time.Now()
+		if err := st.UpdateApprovalGate(g); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		auditApprovalGate(st, claims.UserID, "approval_gate.updated", g)
+		respondJSON(w, http.StatusOK, g)
+	}
+}
+
+// DeleteApprovalGate removes one of the organization's approval gates.
+func DeleteApprovalGate(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		g, err := st.GetApprovalGate(claims.OrgID, mux.Vars(r)["gateId"])
+		if err == nil {
+			err = st.DeleteApprovalGate(claims.OrgID, g.ID)
+		}
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		auditApprovalGate(st, claims.UserID, "approval_gate.deleted", g)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func auditApprovalGate(st *store.Memory, actorID, action string, g models.ApprovalGate) {
+	st.AppendAudit(&models.AuditEvent{
+		OrgID:      g.OrgID,
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: "approval_gate",
+		TargetID:   g.ID,
+		Metadata: map[string]string{
+			"name":    g.Name,
+			"when":    g.When,
+			"require": g.Require,
+			"enabled": strconv.FormatBool(g.Enabled),
+		},
+	})
+}
+
+// EvaluateApprovalGates reads {"review_id": "...", "user_id": "..."} and
+// returns how the organization's gates would judge that member approving
+// the review now, without approving it. user_id defaults to the caller.
+func EvaluateApprovalGates(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			ReviewID string `json:"review_id"`
+			UserID   string `json:"user_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		review, err := st.GetReview(req.ReviewID)
+		if err != nil || review.OrgID != claims.OrgID {
+			respondError(w, http.StatusNotFound, "Review not found")
+			return
+		}
+		if req.UserID == "" {
+			req.UserID = claims.UserID
+		}
+		approver, err := st.GetUser(req.UserID)
+		if err != nil || approver.OrgID != claims.OrgID {
+			respondErrorf(w, http.StatusBadRequest, "User %q is not a member of the organization", req.UserID)
+			return
+		}
+		results := gates.Evaluate(st.ListApprovalGates(claims.OrgID), gates.Env(st, review, approver))
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"allowed": len(gates.Failed(results)) == 0,
+			"gates":   results,
+		})
+	}
+}
+
+// RequireApprovalGates refuses approvals that fail any of the
+// organization's enabled approval gates with 403 Forbidden, explaining
+// each failure.
+func RequireApprovalGates(st *store.Memory) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			review, ok := loadReview(st, w, r)
+			if !ok {
+				return
+			}
+			list := st.ListApprovalGates(review.OrgID)
+			if len(list) == 0 {
+				next(w, r)
+				return
+			}
+			claims, _ := middleware.GetClaims(r.Context())
+			approver, err := st.GetUser(claims.UserID)
+			if err != nil {
+				approver = &models.User{ID: claims.UserID, Email: claims.Email, OrgID: claims.OrgID, Role: claims.Role}
+			}
+			failed := gates.Failed(gates.Evaluate(list, gates.Env(st, review, approver)))
+			names := make([]string, len(failed))
+			messages := make([]string, len(failed))
+			for i, f := range failed {
+				names[i], messages[i] = f.Name, f.Message
+			}
+			middleware.TraceDecision(r.Context(), "policy.approval_gates", len(failed) == 0, "failed: "+strings.Join(names, ", "))
+			if len(failed) > 0 {
+				respondJSON(w, http.StatusForbidden, map[string]interface{}{
+					"error": i18n.T(i18n.LangOf(w), "Approval blocked by %d gate(s): %s", len(failed), strings.Join(messages, "; ")),
+					"gates": failed,
+				})
+				return
+			}
+			next(w, r)
+		}
+	}
+}
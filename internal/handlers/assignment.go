@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/assign"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// AssignmentResult describes an assignment and any out-of-office delegation
+// applied to it.
+type AssignmentResult struct {
+	*models.Assignment
+	RoutedFrom string `json:"routed_from,omitempty"`
+	CC         string `json:"cc,omitempty"`
+}
+
+// AssignReviewer sets the reviewer responsible for a review. If the
+// reviewer is out of office with a delegate, the assignment is routed to
+// the delegate or the delegate is cc'd, depending on the reviewer's mode.
+func AssignReviewer(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+
+		var req struct {
+			ReviewerID string `json:"reviewer_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		reviewer, err := st.GetUser(req.ReviewerID)
+		if err != nil || reviewer.OrgID != review.OrgID {
+			respondError(w, http.StatusBadRequest, "reviewer_id must be a member of the review's organization")
+			return
+		}
+		if _, inactive := st.GetDeactivation(reviewer.ID); inactive {
+			respondError(w, http.StatusBadRequest, "reviewer is deactivated")
+			return
+		}
+
+		now := time.Now()
+		result := AssignmentResult{Assignment: &models.Assignment{
+			ReviewID:   review.ID,
+			ReviewerID: reviewer.ID,
+			AssignedBy: claims.UserID,
+			AssignedAt: now,
+		}}
+
+		// The delegate's subscription and the assignment are recorded
+		// together, so a failed assignment leaves no subscription behind
+		ooo, away := st.GetOutOfOffice(reviewer.ID)
+		err = st.Update(func(tx *store.Tx) error {
+			if away && ooo.ActiveAt(now) && ooo.DelegateID != "" {
+				if ooo.Mode == models.DelegateCC {
+					tx.AddSubscription(&models.Subscription{UserID: ooo.DelegateID, OrgID: review.OrgID, ReviewID: review.ID, CreatedAt: now})
+					result.CC = ooo.DelegateID
+				} else {
+					result.ReviewerID = ooo.DelegateID
+					result.RoutedFrom = reviewer.ID
+				}
+			}
+			return tx.Assign(result.Assignment)
+		})
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, result)
+	}
+}
+
+// AutoAssignReview picks a reviewer with the organization's assignment
+// strategy, skipping deactivated and out-of-office reviewers.
+func AutoAssignReview(st *store.Memory, engine *assign.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+
+		now := time.Now()
+		reviewer, strategy, ok := engine.Pick(review, now)
+		if !ok {
+			respondError(w, http.StatusConflict, "No available reviewer")
+			return
+		}
+
+		a := &models.Assignment{
+			ReviewID:   review.ID,
+			ReviewerID: reviewer.ID,
+			AssignedBy: claims.UserID,
+			AssignedAt: now,
+		}
+		if err := st.Assign(a); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.Header().Set("X-Assignment-Strategy", strategy)
+		respondJSON(w, http.StatusOK, a)
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/scan"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+const maxAttachmentBytes = 25 << 20
+
+// loadAttachment resolves the review and attachment in the URL, checking
+// the caller can see the review.
+func loadAttachment(st *store.Memory, w http.ResponseWriter, r *http.Request) (models.Attachment, bool) {
+	review, ok := loadReview(st, w, r)
+	if !ok {
+		return models.Attachment{}, false
+	}
+	a, err := st.GetAttachment(review.ID, mux.Vars(r)["attachmentId"])
+	if err != nil {
+		respondStoreError(w, err)
+		return models.Attachment{}, false
+	}
+	return a, true
+}
+
+// UploadAttachment stores the request body as an attachment of the review,
+// named by ?name=. Text attachments are checked for secrets per the
+// organization's policy. When a scanner is configured the attachment is
+// scanned for malware in the background and cannot be downloaded until it
+// is found clean.
+func UploadAttachment(st *store.Memory, blobs blob.Store, scans *scan.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		claims, _ := middleware.GetClaims(r.Context())
+		name := path.Base(strings.ReplaceAll(r.URL.Query().Get("name"), `\`, "/"))
+		if name == "" || name == "." || name == "/" || len(name) > 255 {
+			respondError(w, http.StatusBadRequest, "name must be a file name of at most 255 characters")
+			return
+		}
+
+		data, err := io.ReadAll(io.LimitReader(r.Body, maxAttachmentBytes+1))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Failed to read attachment")
+			return
+		}
+		if len(data) > maxAttachmentBytes {
+			respondError(w, http.StatusRequestEntityTooLarge, "Attachment must be at most 25 MB")
+			return
+		}
+		contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || contentType == "application/octet-stream" {
+			contentType = http.DetectContentType(data)
+		}
+		findings, allowed := scanAttachmentSecrets(st, review.OrgID, contentType, data)
+		if !allowed {
+			respondSecretsBlocked(w, findings)
+			return
+		}
+
+		token, err := newToken()
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to store attachment")
+			return
+		}
+		key := "attachments/" + review.OrgID + "/" + token[:32]
+		if err := blobs.Put(key, contentType, bytes.NewReader(data)); err != nil {
+			log.Printf("Failed to store attachment for review %s: %v", review.ID, err)
+			respondError(w, http.StatusInternalServerError, "Failed to store attachment")
+			return
+		}
+
+		sum := sha256.Sum256(data)
+		status := models.ScanUnscanned
+		if scans.Enabled() {
+			status = models.ScanPending
+		}
+		a, err := st.AddAttachment(models.Attachment{
+			OrgID:       review.OrgID,
+			ReviewID:    review.ID,
+			Name:        name,
+			ContentType: contentType,
+			Size:        int64(len(data)),
+			SHA256:      hex.EncodeToString(sum[:]),
+			BlobKey:     key,
+			UploadedBy:  claims.UserID,
+			UploadedAt:  time.Now(),
+			Scan:        models.AttachmentScan{Status: status},
+		})
+		if err != nil {
+			log.Printf("Failed to record attachment for review %s: %v", review.ID, err)
+			blobs.Delete(key)
+			respondError(w, http.StatusInternalServerError, "Failed to store attachment")
+			return
+		}
+		if len(findings) > 0 {
+			source := "attachment:" + a.ID
+			for i := range findings {
+				findings[i].Source = source
+			}
+			st.RecordSecretFindings(review.ID, []string{source}, findings)
+			w.Header().Set("X-Secret-Findings", strconv.Itoa(len(findings)))
+		}
+		scans.Submit()
+		respondJSON(w, http.StatusCreated, a)
+	}
+}
+
+// ListAttachments returns the review's attachments with their scan status.
+func ListAttachments(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListAttachments(review.ID))
+	}
+}
+
+// GetAttachment returns an attachment with its scan status.
+func GetAttachment(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a, ok := loadAttachment(st, w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, a)
+	}
+}
+
+// DownloadAttachment serves an attachment's content. Attachments still
+// being scanned, infected or that could not be scanned are refused.
+func DownloadAttachment(st *store.Memory, blobs blob.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a, ok := loadAttachment(st, w, r)
+		if !ok {
+			return
+		}
+		switch a.Scan.Status {
+		case models.ScanPending:
+			w.Header().Set("Retry-After", "30")
+			respondError(w, http.StatusConflict, "Attachment is still being scanned")
+			return
+		case models.ScanInfected:
+			respondError(w, http.StatusForbidden, "Attachment is quarantined because malware was found")
+			return
+		case models.ScanFailed:
+			respondError(w, http.StatusConflict, "Attachment could not be scanned")
+			return
+		}
+		rc, contentType, err := blobs.Get(a.BlobKey)
+		if err != nil {
+			if !errors.Is(err, blob.ErrNotFound) {
+				log.Printf("Failed to read attachment %s: %v", a.ID, err)
+			}
+			respondError(w, http.StatusInternalServerError, "Failed to read attachment")
+			return
+		}
+		defer rc.Close()
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Name}))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		io.Copy(w, rc)
+	}
+}
+
+// RescanAttachment queues an attachment that could not be scanned, or was
+// uploaded before a scanner was configured, for another scan.
+func RescanAttachment(st *store.Memory, scans *scan.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a, ok := loadAttachment(st, w, r)
+		if !ok {
+			return
+		}
+		if !scans.Enabled() {
+			respondError(w, http.StatusServiceUnavailable, "No attachment scanner is configured")
+			return
+		}
+		if a.Scan.Status != models.ScanFailed && a.Scan.Status != models.ScanUnscanned {
+			respondErrorf(w, http.StatusConflict, "Attachment scan is %s; only failed or unscanned attachments can be rescanned", a.Scan.Status)
+			return
+		}
+		a, err := st.SetAttachmentScan(a.ID, models.AttachmentScan{Status: models.ScanPending}, "")
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		scans.Submit()
+		respondJSON(w, http.StatusAccepted, a)
+	}
+}
+
+// DeleteAttachment removes an attachment. Only its uploader and admins may
+// delete it.
+func DeleteAttachment(st *store.Memory, blobs blob.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a, ok := loadAttachment(st, w, r)
+		if !ok {
+			return
+		}
+		claims, _ := middleware.GetClaims(r.Context())
+		if a.UploadedBy != claims.UserID && claims.Role != "admin" {
+			respondError(w, http.StatusForbidden, "Only the uploader or an admin can delete an attachment")
+			return
+		}
+		err := st.Update(func(tx *store.Tx) error {
+			if _, err := tx.DeleteAttachment(a.ReviewID, a.ID); err != nil {
+				return err
+			}
+			tx.RecordSecretFindings(a.ReviewID, []string{"attachment:" + a.ID}, nil)
+			return nil
+		})
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if err := blobs.Delete(a.BlobKey); err != nil {
+			log.Printf("Failed to delete content of attachment %s: %v", a.ID, err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
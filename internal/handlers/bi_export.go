@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/biexport"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+const maskedSecret = "********"
+
+// GetBIExportConfig returns the organization's BI export configuration and
+// the outcome of its last run. Credentials are masked.
+func GetBIExportConfig(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		cfg, ok := st.GetBIExportConfig(claims.OrgID)
+		if !ok {
+			respondError(w, http.StatusNotFound, "BI export is not configured")
+			return
+		}
+		respondJSON(w, http.StatusOK, maskBIExport(cfg))
+	}
+}
+
+// UpdateBIExportConfig replaces the organization's BI export configuration.
+// Omitted credentials keep the current ones.
+func UpdateBIExportConfig(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+
+		var cfg models.BIExportConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		cfg.OrgID = claims.OrgID
+		current, _ := st.GetBIExportConfig(claims.OrgID)
+		if cfg.Secret == "" || cfg.Secret == maskedSecret {
+			cfg.Secret = current.Secret
+		}
+		if cfg.SecretAccessKey == "" || cfg.SecretAccessKey == maskedSecret {
+			cfg.SecretAccessKey = current.SecretAccessKey
+		}
+		cfg.LastDay, cfg.LastError, cfg.LastRunAt = current.LastDay, current.LastError, current.LastRunAt
+
+		switch cfg.Destination {
+		case models.BIDestinationHTTPS:
+			if u, err := url.Parse(cfg.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+				respondError(w, http.StatusBadRequest, "url must be an absolute https URL")
+				return
+			}
+		case models.BIDestinationS3:
+			if cfg.Bucket == "" || cfg.Region == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+				respondError(w, http.StatusBadRequest, "bucket, region, access_key_id and secret_access_key are required for s3")
+				return
+			}
+			if cfg.Endpoint != "" {
+				if u, err := url.Parse(cfg.Endpoint); err != nil || u.Scheme != "https" || u.Host == "" {
+					respondError(w, http.StatusBadRequest, "endpoint must be an absolute https URL")
+					return
+				}
+			}
+		default:
+			respondError(w, http.StatusBadRequest, "destination must be https or s3")
+			return
+		}
+
+		st.SetBIExportConfig(cfg)
+		respondJSON(w, http.StatusOK, maskBIExport(cfg))
+	}
+}
+
+// RunBIExport exports one complete UTC day now, by default yesterday, e.g.
+// to backfill or to test the destination. ?date=2026-10-15 selects the day.
+func RunBIExport(st *store.Memory, exporter *biexport.Exporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		cfg, ok := st.GetBIExportConfig(claims.OrgID)
+		if !ok {
+			respondError(w, http.StatusNotFound, "BI export is not configured")
+			return
+		}
+
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		day := today.AddDate(0, 0, -1)
+		if v := r.URL.Query().Get("date"); v != "" {
+			parsed, err := time.Parse("2006-01-02", v)
+			if err != nil || !parsed.Before(today) {
+				respondError(w, http.StatusBadRequest, "date must be a past day such as 2026-10-15")
+				return
+			}
+			day = parsed
+		}
+
+		if err := exporter.Export(r.Context(), cfg, day); err != nil {
+			respondErrorf(w, http.StatusBadGateway, "Export failed: %v", err)
+			return
+		}
+		cfg, _ = st.GetBIExportConfig(claims.OrgID)
+		respondJSON(w, http.StatusOK, maskBIExport(cfg))
+	}
+}
+
+func maskBIExport(cfg models.BIExportConfig) models.BIExportConfig {
+	if cfg.Secret != "" {
+		cfg.Secret = maskedSecret
+	}
+	if cfg.SecretAccessKey != "" {
+		cfg.SecretAccessKey = maskedSecret
+	}
+	return cfg
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+const maxLogoBytes = 1 << 20
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// GetOrgBranding returns an organization's name, colors and logo URL.
+func GetOrgBranding(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.GetBranding(claims.OrgID))
+	}
+}
+
+// UpdateOrgBranding sets an organization's display name and colors. The
+// logo is managed separately with UploadOrgLogo.
+func UpdateOrgBranding(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Name         string `json:"name"`
+			PrimaryColor string `json:"primary_color"`
+			AccentColor  string `json:"accent_color"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if len(req.Name) > 100 {
+			respondError(w, http.StatusBadRequest, "name must be at most 100 characters")
+			return
+		}
+		for _, c := range []string{req.PrimaryColor, req.AccentColor} {
+			if c != "" && !colorPattern.MatchString(c) {
+				respondError(w, http.StatusBadRequest, "colors must be hex values such as #1f883d")
+				return
+			}
+		}
+
+		b := st.GetBranding(claims.OrgID)
+		b.Name = strings.TrimSpace(req.Name)
+		b.PrimaryColor = strings.ToLower(req.PrimaryColor)
+		b.AccentColor = strings.ToLower(req.AccentColor)
+		b.UpdatedAt = time.Now()
+		st.SetBranding(b)
+		respondJSON(w, http.StatusOK, b)
+	}
+}
+
+// UploadOrgLogo stores the request body as the organization's logo. Like
+// avatars, the image type is sniffed and each upload gets a fresh key.
+func UploadOrgLogo(st *store.Memory, blobs blob.Store, baseURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+
+		data, err := io.ReadAll(io.LimitReader(r.Body, maxLogoBytes+1))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Failed to read logo")
+			return
+		}
+		if len(data) > maxLogoBytes {
+			respondError(w, http.StatusRequestEntityTooLarge, "Logo must be at most 1 MB")
+			return
+		}
+		contentType := http.DetectContentType(data)
+		ext, ok := avatarTypes[contentType]
+		if !ok {
+			respondError(w, http.StatusUnsupportedMediaType, "Logo must be a PNG, JPEG, GIF or WebP image")
+			return
+		}
+
+		token, err := newToken()
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to store logo")
+			return
+		}
+		key := "logos/" + token[:32] + "." + ext
+		if err := blobs.Put(key, contentType, bytes.NewReader(data)); err != nil {
+			log.Printf("Failed to store logo for org %s: %v", claims.OrgID, err)
+			respondError(w, http.StatusInternalServerError, "Failed to store logo")
+			return
+		}
+
+		b := st.GetBranding(claims.OrgID)
+		if b.LogoKey != "" {
+			blobs.Delete(b.LogoKey)
+		}
+		b.LogoKey = key
+		b.LogoURL = strings.TrimRight(baseURL, "/") + "/" + key
+		b.UpdatedAt = time.Now()
+		st.SetBranding(b)
+		respondJSON(w, http.StatusOK, b)
+	}
+}
+
+// DeleteOrgLogo removes the organization's logo.
+func DeleteOrgLogo(st *store.Memory, blobs blob.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		b := st.GetBranding(claims.OrgID)
+		if b.LogoKey != "" {
+			blobs.Delete(b.LogoKey)
+		}
+		b.LogoKey, b.LogoURL = "", ""
+		b.UpdatedAt = time.Now()
+		st.SetBranding(b)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ServeLogo serves a stored organization logo. Logos are public so that
+// email clients can load them.
+func ServeLogo(blobs blob.Store) http.HandlerFunc {
+	return serveImage(blobs, "logos/", "Failed to read logo")
+}
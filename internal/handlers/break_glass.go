@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	mailer "github.com/andres20980/aurea-orchestrator/internal/mail"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// minJustification is the shortest justification accepted for a
+// break-glass elevation.
+const minJustification = 20
+
+// Notification types sent to the organization's admins and to instance
+// operators when an admin asks to break glass and when the request is
+// approved.
+const (
+	NotifyBreakGlassRequested = "break_glass.requested"
+	NotifyBreakGlassApproved  = "break_glass.approved"
+)
+
+// RequestBreakGlass asks for an emergency elevation of the calling admin
+// within their own organization for {"minutes": n} (default 30, at most
+// maxDuration) on a written {"justification": "..."}. Nothing is granted
+// until another admin of the organization or an operator approves it.
+// The organization's other admins and the instance operators are notified
+// in their inbox and by email, and the request is raised as a security
+// event.
+func RequestBreakGlass(st *store.Memory, m mailer.Mailer, operators []string, maxDuration time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		var req struct {
+			Justification string `json:"justification"`
+			Minutes       int    `json:"minutes"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if len(req.Justification) < minJustification {
+			respondErrorf(w, http.StatusBadRequest, "justification must be at least %d characters", minJustification)
+			return
+		}
+		if req.Minutes <= 0 {
+			req.Minutes = 30
+		}
+		if time.Duration(req.Minutes)*time.Minute > maxDuration {
+			respondErrorf(w, http.StatusBadRequest, "minutes cannot exceed %d", int(maxDuration.Minutes()))
+			return
+		}
+		now := time.Now()
+		b, err := st.AddBreakGlass(models.BreakGlass{
+			OrgID:         claims.OrgID,
+			UserID:        claims.UserID,
+			Justification: req.Justification,
+			Minutes:       req.Minutes,
+			RequestedAt:   now,
+		})
+		if errors.Is(err, store.ErrDuplicate) {
+			respondError(w, http.StatusConflict, "You already have a break-glass elevation pending or in effect")
+			return
+		}
+		log.Printf("BREAK GLASS: user %s of organization %s requested %d minutes of elevated access: %s", claims.UserID, claims.OrgID, b.Minutes, b.Justification)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "break_glass.requested",
+			TargetType: "break_glass",
+			TargetID:   b.ID,
+			Reason:     b.Justification,
+			Metadata:   map[string]string{"minutes": strconv.Itoa(b.Minutes)},
+		})
+		st.AddSecurityEvent(&models.SecurityEvent{
+			OrgID:     claims.OrgID,
+			UserID:    claims.UserID,
+			Type:      models.SecurityBreakGlassRequested,
+			Device:    security.Fingerprint(r),
+			Detail:    b.Justification,
+			CreatedAt: now,
+		})
+		for _, u := range breakGlassRecipients(st, operators, b.OrgID, claims.UserID) {
+			lang := i18n.Negotiate(st.GetProfile(u.ID).Locale, "")
+			notifyBreakGlass(st, m, u, b, NotifyBreakGlassRequested, claims.UserID, mailer.Message{
+				Subject: i18n.T(lang, "Break-glass access requested in Aurea Orchestrator"),
+				Body:    i18n.T(lang, "%s requested %d minutes of emergency access to organization %s. Justification: %s", claims.Email, b.Minutes, b.OrgID, b.Justification),
+			})
+		}
+		respondJSON(w, http.StatusCreated, b)
+	}
+}
+
+// ApproveBreakGlass approves a pending break-glass request of another
+// admin of the caller's organization.
+func ApproveBreakGlass(st *store.Memory, m mailer.Mailer, operators []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		b, err := st.GetBreakGlass(mux.Vars(r)["grantId"])
+		if err == nil && b.OrgID != claims.OrgID {
+			err = store.ErrNotFound
+		}
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		approveBreakGlass(w, r, st, m, operators, claims, b)
+	}
+}
+
+// AdminApproveBreakGlass approves a pending break-glass request of any
+// organization.
+func AdminApproveBreakGlass(st *store.Memory, m mailer.Mailer, operators []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		b, err := st.GetBreakGlass(mux.Vars(r)["grantId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		approveBreakGlass(w, r, st, m, operators, claims, b)
+	}
+}
+
+// approveBreakGlass grants b on the approval of claims, who must not be
+// the requester, and tells the requester, the organization's other admins
+// and the operators.
+func approveBreakGlass(w http.ResponseWriter, r *http.Request, st *store.Memory, m mailer.Mailer, operators []string, claims *auth.Claims, b models.BreakGlass) {
+	if b.UserID == claims.UserID {
+		respondError(w, http.StatusForbidden, "You cannot approve your own break-glass request")
+		return
+	}
+	now := time.Now()
+	b, err := st.ApproveBreakGlass(b.ID, claims.UserID, now)
+	if errors.Is(err, store.ErrDecided) {
+		respondError(w, http.StatusConflict, "This break-glass request is no longer pending")
+		return
+	}
+	if err != nil {
+		respondStoreError(w, err)
+		return
+	}
+	log.Printf("BREAK GLASS: user %s approved elevated access of user %s in organization %s until %s", claims.UserID, b.UserID, b.OrgID, b.ExpiresAt.Format(time.RFC3339))
+	st.AppendAudit(&models.AuditEvent{
+		OrgID:      b.OrgID,
+		ActorID:    claims.UserID,
+		Action:     "break_glass.approved",
+		TargetType: "break_glass",
+		TargetID:   b.ID,
+		Reason:     b.Justification,
+		Metadata:   map[string]string{"requested_by": b.UserID, "expires_at": b.ExpiresAt.Format(time.RFC3339)},
+	})
+	st.AddSecurityEvent(&models.SecurityEvent{
+		OrgID:     b.OrgID,
+		UserID:    b.UserID,
+		Type:      models.SecurityBreakGlassApproved,
+		Device:    security.Fingerprint(r),
+		Detail:    "approved by " + claims.UserID + ": " + b.Justification,
+		CreatedAt: now,
+	})
+	recipients := breakGlassRecipients(st, operators, b.OrgID, claims.UserID, b.UserID)
+	if requester, err := st.GetUser(b.UserID); err == nil {
+		recipients = append(recipients, requester)
+	}
+	for _, u := range recipients {
+		lang := i18n.Negotiate(st.GetProfile(u.ID).Locale, "")
+		notifyBreakGlass(st, m, u, b, NotifyBreakGlassApproved, claims.UserID, mailer.Message{
+			Subject: i18n.T(lang, "Break-glass access approved in Aurea Orchestrator"),
+			Body:    i18n.T(lang, "%s approved emergency access for user %s in organization %s until %s. Justification: %s", claims.Email, b.UserID, b.OrgID, b.ExpiresAt.Format(time.RFC1123), b.Justification),
+		})
+	}
+	respondJSON(w, http.StatusOK, b)
+}
+
+// breakGlassRecipients returns the admins of orgID and the instance
+// operators, once each, leaving out the users in skip.
+func breakGlassRecipients(st *store.Memory, operators []string, orgID string, skip ...string) []*models.User {
+	seen := make(map[string]bool, len(skip))
+	for _, id := range skip {
+		seen[id] = true
+	}
+	var out []*models.User
+	for _, u := range append(st.ListOrgUsers(orgID), st.ListOperators(operators)...) {
+		if seen[u.ID] || (u.OrgID == orgID && u.Role != "admin" && u.Role != middleware.OperatorRole) {
+			continue
+		}
+		seen[u.ID] = true
+		out = append(out, u)
+	}
+	return out
+}
+
+// notifyBreakGlass puts a notification of kind about b in u's inbox and
+// emails msg to them.
+func notifyBreakGlass(st *store.Memory, m mailer.Mailer, u *models.User, b models.BreakGlass, kind, actorID string, msg mailer.Message) {
+	st.AddNotification(&models.Notification{
+		UserID:    u.ID,
+		OrgID:     b.OrgID,
+		Type:      kind,
+		ActorID:   actorID,
+		CreatedAt: time.Now(),
+	})
+	msg.To = u.Email
+	if err := m.Send(msg); err != nil {
+		log.Printf("Failed to notify %s of break-glass %s: %v", u.ID, b.ID, err)
+	}
+}
+
+// GetBreakGlass returns the caller's elevation pending or in effect.
+func GetBreakGlass(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		b, current := st.CurrentBreakGlass(claims.UserID, time.Now())
+		if !current {
+			respondError(w, http.StatusNotFound, "Not found")
+			return
+		}
+		respondJSON(w, http.StatusOK, b)
+	}
+}
+
+// EndBreakGlass ends the caller's elevation before it expires, or
+// withdraws their request before it is approved.
+func EndBreakGlass(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		b, err := st.EndBreakGlass(claims.UserID, time.Now())
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      b.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "break_glass.ended",
+			TargetType: "break_glass",
+			TargetID:   b.ID,
+		})
+		respondJSON(w, http.StatusOK, b)
+	}
+}
+
+// UnlessElevated applies gates to a review handler except for callers
+// with an approved break-glass elevation in the review's organization,
+// who go straight to the handler. The BreakGlass middleware audits what
+// they do with it.
+func UnlessElevated(st *store.Memory, gates ...func(http.HandlerFunc) http.HandlerFunc) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		gated := next
+		for i := len(gates) - 1; i >= 0; i-- {
+			gated = gates[i](gated)
+		}
+		return func(w http.ResponseWriter, r *http.Request) {
+			if b, ok := middleware.Elevated(r.Context()); ok {
+				review, err := st.GetReview(mux.Vars(r)["id"])
+				elevated := err == nil && review.OrgID == b.OrgID
+				middleware.TraceDecision(r.Context(), "break_glass", elevated, "elevation "+b.ID+" in organization "+b.OrgID)
+				if elevated {
+					next(w, r)
+					return
+				}
+			}
+			gated(w, r)
+		}
+	}
+}
+
+// ListOrgBreakGlass returns the organization's break-glass history.
+func ListOrgBreakGlass(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListBreakGlass(claims.OrgID))
+	}
+}
+
+// AdminListBreakGlass returns break-glass history across the instance, or
+// with ?org_id= of one organization.
+func AdminListBreakGlass(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, st.ListBreakGlass(r.URL.Query().Get("org_id")))
+	}
+}
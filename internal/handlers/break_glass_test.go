@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	mailer "github.com/andres20980/aurea-orchestrator/internal/mail"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+	"github.com/gorilla/mux"
+)
+
+// sentMail records the messages sent through it.
+type sentMail struct{ msgs []mailer.Message }
+
+func (s *sentMail) Send(msg mailer.Message) error {
+	s.msgs = append(s.msgs, msg)
+	return nil
+}
+
+func (s *sentMail) to() []string {
+	var out []string
+	for _, m := range s.msgs {
+		out = append(out, m.To)
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestBreakGlass(t *testing.T) {
+	st := store.NewMemory()
+	alice := newUser(t, st, "acme", "alice@acme.test", "admin", "")
+	bob := newUser(t, st, "acme", "bob@acme.test", "admin", "")
+	newUser(t, st, "acme", "carol@acme.test", "member", "")
+	op := newUser(t, st, "ops", "op@ops.test", "operator", "")
+	listed := newUser(t, st, "ops", "listed@ops.test", "member", "")
+	outsider := newUser(t, st, "globex", "admin@globex.test", "admin", "")
+	operators := []string{listed.Email}
+
+	var grantID string
+	t.Run("request", func(t *testing.T) {
+		mail := &sentMail{}
+		request := RequestBreakGlass(st, mail, operators, time.Hour)
+		tests := []struct {
+			name string
+			body string
+			want int
+		}{
+			{"short justification", `{"justification":"prod down"}`, http.StatusBadRequest},
+			{"too long", `{"justification":"production database is unreachable","minutes":120}`, http.StatusBadRequest},
+			{"valid", `{"justification":"production database is unreachable","minutes":15}`, http.StatusCreated},
+			{"already pending", `{"justification":"production database is unreachable"}`, http.StatusConflict},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				request(rec, asUser(httptest.NewRequest("POST", "/", strings.NewReader(tt.body)), alice))
+				if rec.Code != tt.want {
+					t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+				}
+			})
+		}
+
+		b, ok := st.CurrentBreakGlass(alice.ID, time.Now())
+		if !ok || b.GrantedAt != nil {
+			t.Fatalf("request = %+v, %v; want pending", b, ok)
+		}
+		grantID = b.ID
+		if _, active := st.ActiveBreakGlass(alice.ID, time.Now()); active {
+			t.Error("request took effect before approval")
+		}
+		if got, want := mail.to(), []string{bob.Email, listed.Email, op.Email}; !slices.Equal(got, want) {
+			t.Errorf("mailed %v, want %v", got, want)
+		}
+		if events := st.ListSecurityEvents("acme", 10); len(events) != 1 || events[0].Type != models.SecurityBreakGlassRequested {
+			t.Errorf("security events = %+v", events)
+		}
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		mail := &sentMail{}
+		tests := []struct {
+			name    string
+			handler http.HandlerFunc
+			caller  *models.User
+			org     string
+			want    int
+		}{
+			{"by the requester", ApproveBreakGlass(st, mail, operators), alice, "acme", http.StatusForbidden},
+			{"by the requester as operator", AdminApproveBreakGlass(st, mail, operators), alice, "", http.StatusForbidden},
+			{"from another org", ApproveBreakGlass(st, mail, operators), outsider, "globex", http.StatusNotFound},
+			{"by another admin", ApproveBreakGlass(st, mail, operators), bob, "acme", http.StatusOK},
+			{"twice", AdminApproveBreakGlass(st, mail, operators), op, "", http.StatusConflict},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := asUser(httptest.NewRequest("POST", "/", nil), tt.caller)
+				req = mux.SetURLVars(req, map[string]string{"id": tt.org, "grantId": grantID})
+				rec := httptest.NewRecorder()
+				tt.handler(rec, req)
+				if rec.Code != tt.want {
+					t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+				}
+			})
+		}
+
+		b, active := st.ActiveBreakGlass(alice.ID, time.Now())
+		if !active || b.ApprovedBy != bob.ID {
+			t.Fatalf("grant = %+v, %v; want approved by bob", b, active)
+		}
+		if d := b.ExpiresAt.Sub(*b.GrantedAt); d != 15*time.Minute {
+			t.Errorf("granted for %v, want 15m", d)
+		}
+		if got, want := mail.to(), []string{alice.Email, listed.Email, op.Email}; !slices.Equal(got, want) {
+			t.Errorf("mailed %v, want %v", got, want)
+		}
+	})
+
+	t.Run("elevation", func(t *testing.T) {
+		svc := aurea.NewWithStore(st, notify.NewDispatcher(st))
+		st.SetPolicy(models.OrgPolicy{OrgID: "acme", RequireResolvedThreads: true})
+		review, _ := svc.Reviews().Create(context.Background(), aurea.Actor{UserID: bob.ID, OrgID: "acme"}, "Hotfix", "...")
+		st.AddComment(&models.Comment{ReviewID: review.ID, AuthorID: bob.ID, Body: "?"})
+
+		approve := middleware.BreakGlass(st)(UnlessElevated(st, RequireResolvedThreads(st))(ApproveReview(svc.Reviews())))
+		operatorOnly := middleware.BreakGlass(st)(middleware.RequireOperator(operators)(AdminListBreakGlass(st)))
+		tests := []struct {
+			name    string
+			handler http.Handler
+			caller  *models.User
+			want    int
+		}{
+			{"not elevated", approve, bob, http.StatusConflict},
+			{"operator endpoint", operatorOnly, alice, http.StatusForbidden},
+			{"elevated", approve, alice, http.StatusOK},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := asUser(httptest.NewRequest("POST", "/", nil), tt.caller)
+				req = mux.SetURLVars(req, map[string]string{"id": review.ID})
+				rec := httptest.NewRecorder()
+				tt.handler.ServeHTTP(rec, req)
+				if rec.Code != tt.want {
+					t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+				}
+			})
+		}
+	})
+}
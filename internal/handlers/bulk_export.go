@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/streaming"
+)
+
+// auditPageSize is how many audit events an export reads from the store
+// at a time.
+const auditPageSize = 500
+
+// auditSnapshot reads ?until=, the audit log position an export stops at,
+// defaulting to the current end of the log. Resumed downloads pass the
+// position of the first attempt, which the Content-Location of every
+// audit export carries, so they get the same bytes.
+func auditSnapshot(w http.ResponseWriter, r *http.Request, st *store.Memory) (int, bool) {
+	seq := st.AuditSeq()
+	v := r.URL.Query().Get("until")
+	if v == "" {
+		return seq, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 || n > seq {
+		respondError(w, http.StatusBadRequest, "until must be a position in the audit log")
+		return 0, false
+	}
+	return n, true
+}
+
+// pinUntil sets a Content-Location that repeats the request with ?until=.
+func pinUntil(w http.ResponseWriter, r *http.Request, until int) {
+	q := r.URL.Query()
+	q.Set("until", strconv.Itoa(until))
+	w.Header().Set("Content-Location", (&url.URL{Path: r.URL.Path, RawQuery: q.Encode()}).String())
+}
+
+// writeAudit writes an organization's audit events up to until as NDJSON.
+func writeAudit(w io.Writer, st *store.Memory, orgID string, until int) error {
+	enc := json.NewEncoder(w)
+	for pos := 0; pos < until; {
+		var events []models.AuditEvent
+		events, pos = st.AuditPage(orgID, pos, until, auditPageSize)
+		for i := range events {
+			if err := enc.Encode(&events[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ExportAuditLog streams the organization's audit log as NDJSON, one event
+// per line, oldest first. Downloads can be resumed with a Range request
+// against the Content-Location of the first response.
+func ExportAuditLog(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		until, ok := auditSnapshot(w, r, st)
+		if !ok {
+			return
+		}
+		pinUntil(w, r, until)
+		streaming.Serve(w, r, streaming.Export{
+			Name:        fmt.Sprintf("audit-%s.ndjson", claims.OrgID),
+			ContentType: streaming.NDJSON,
+			ETag:        fmt.Sprintf(`"audit-%s-%d-%d"`, claims.OrgID, until, st.AuditVersion(claims.OrgID)),
+			Write: func(w io.Writer) error {
+				return writeAudit(w, st, claims.OrgID, until)
+			},
+		})
+	}
+}
+
+// exportedReview is a review as written to an organization export.
+type exportedReview struct {
+	*models.Review
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+// orgExport is what an organization export covers, collected when the
+// request arrives; records are read again as the export is written.
+type orgExport struct {
+	orgID   string
+	reviews []string
+	until   int
+	// progress, if set, is told how many of the export's files are
+	// written.
+	progress func(done, total int)
+}
+
+// name is the file name of the export.
+func (e *orgExport) name() string {
+	return fmt.Sprintf("org-%s.zip", e.orgID)
+}
+
+func newOrgExport(st *store.Memory, orgID string, until int) *orgExport {
+	reviews := st.ListOrgReviews(orgID)
+	sort.Slice(reviews, func(i, j int) bool {
+		if !reviews[i].CreatedAt.Equal(reviews[j].CreatedAt) {
+			return reviews[i].CreatedAt.Before(reviews[j].CreatedAt)
+		}
+		return reviews[i].ID < reviews[j].ID
+	})
+	e := &orgExport{orgID: orgID, until: until}
+	for _, rv := range reviews {
+		e.reviews = append(e.reviews, rv.ID)
+	}
+	return e
+}
+
+// etag fingerprints the records the export covers, so a changed record
+// changes the tag and a resumed download with the old one starts over.
+func (e *orgExport) etag(st *store.Memory) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %d %d\n", e.orgID, e.until, st.AuditVersion(e.orgID))
+	for _, u := range st.ListOrgUsers(e.orgID) {
+		fmt.Fprintf(h, "u %s %s %s %s\n", u.ID, u.Email, u.Name, u.Role)
+	}
+	for _, id := range e.reviews {
+		rv, err := st.GetReview(id)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(h, "r %s %s %d\n", rv.ID, rv.Status, rv.UpdatedAt.UnixNano())
+		hashString(h, rv.Title)
+		hashString(h, rv.Content)
+		fields, _ := json.Marshal(st.GetFieldValues(id))
+		h.Write(fields)
+		for _, c := range st.ListComments(id) {
+			fmt.Fprintf(h, "c %s\n", c.ID)
+			hashString(h, c.Body)
+		}
+		for _, a := range st.ListAttachments(id) {
+			fmt.Fprintf(h, "a %s %s\n", a.ID, a.Scan.Status)
+		}
+	}
+	return `"org-` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+func hashString(h hash.Hash, s string) {
+	fmt.Fprintf(h, "%d:%s\n", len(s), s)
+}
+
+// write produces the export: a zip of NDJSON files.
+func (e *orgExport) write(w io.Writer, st *store.Memory) error {
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"members.ndjson", func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			for _, u := range st.ListOrgUsers(e.orgID) {
+				if err := enc.Encode(u); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{"reviews.ndjson", e.eachReview(st, func(enc *json.Encoder, rv *models.Review) error {
+			return enc.Encode(exportedReview{Review: rv, Fields: st.GetFieldValues(rv.ID)})
+		})},
+		{"comments.ndjson", e.eachReview(st, func(enc *json.Encoder, rv *models.Review) error {
+			for _, c := range st.ListComments(rv.ID) {
+				if err := enc.Encode(c); err != nil {
+					return err
+				}
+			}
+			return nil
+		})},
+		{"attachments.ndjson", e.eachReview(st, func(enc *json.Encoder, rv *models.Review) error {
+			for _, a := range st.ListAttachments(rv.ID) {
+				if err := enc.Encode(a); err != nil {
+					return err
+				}
+			}
+			return nil
+		})},
+		{"audit.ndjson", func(w io.Writer) error {
+			return writeAudit(w, st, e.orgID, e.until)
+		}},
+	}
+	for i, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate})
+		if err != nil {
+			return err
+		}
+		if err := f.write(fw); err != nil {
+			return err
+		}
+		if e.progress != nil {
+			e.progress(i+1, len(files))
+		}
+	}
+	return zw.Close()
+}
+
+// eachReview returns a writer that calls fn for every review the export
+// covers that still exists.
+func (e *orgExport) eachReview(st *store.Memory, fn func(*json.Encoder, *models.Review) error) func(io.Writer) error {
+	return func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		for _, id := range e.reviews {
+			rv, err := st.GetReview(id)
+			if err != nil {
+				continue
+			}
+			if err := fn(enc, rv); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ExportOrg streams the organization's data as a zip of NDJSON files:
+// members, reviews with their custom field values, comments, attachment
+// records and the audit log. Downloads can be resumed with a Range request
+// against the Content-Location of the first response while nothing in the
+// export changes.
+func ExportOrg(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		until, ok := auditSnapshot(w, r, st)
+		if !ok {
+			return
+		}
+		e := newOrgExport(st, claims.OrgID, until)
+		pinUntil(w, r, until)
+		streaming.Serve(w, r, streaming.Export{
+			Name:        e.name(),
+			ContentType: streaming.Zip,
+			ETag:        e.etag(st),
+			Write: func(w io.Writer) error {
+				return e.write(w, st)
+			},
+		})
+	}
+}
+
+// bundleNames gives each attachment a distinct file name inside a bundle.
+func bundleNames(attachments []models.Attachment) []string {
+	names := make([]string, len(attachments))
+	seen := make(map[string]bool)
+	for i, a := range attachments {
+		name := strings.NewReplacer("/", "_", "\\", "_").Replace(a.Name)
+		if name == "" || name == "." || name == ".." {
+			name = a.ID
+		}
+		ext := path.Ext(name)
+		base := strings.TrimSuffix(name, ext)
+		for n := 2; seen[name]; n++ {
+			name = fmt.Sprintf("%s (%d)%s", base, n, ext)
+		}
+		seen[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// ExportAttachments streams a zip of the review's attachments that can be
+// downloaded: those found clean or uploaded with no scanner configured.
+// Downloads can be resumed with a Range request while the set of such
+// attachments stays the same.
+func ExportAttachments(st *store.Memory, blobs blob.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		var bundle []models.Attachment
+		var modtime time.Time
+		h := sha256.New()
+		for _, a := range st.ListAttachments(review.ID) {
+			if a.Scan.Status != models.ScanClean && a.Scan.Status != models.ScanUnscanned {
+				continue
+			}
+			bundle = append(bundle, a)
+			fmt.Fprintf(h, "%s %s\n", a.ID, a.SHA256)
+			if a.UploadedAt.After(modtime) {
+				modtime = a.UploadedAt
+			}
+		}
+		if len(bundle) == 0 {
+			respondError(w, http.StatusNotFound, "Review has no attachments that can be downloaded")
+			return
+		}
+		names := bundleNames(bundle)
+		streaming.Serve(w, r, streaming.Export{
+			Name:        fmt.Sprintf("review-%s-attachments.zip", review.ID),
+			ContentType: streaming.Zip,
+			ETag:        `"attachments-` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`,
+			ModTime:     modtime,
+			Write: func(w io.Writer) error {
+				zw := zip.NewWriter(w)
+				for i, a := range bundle {
+					if err := writeBundled(zw, blobs, a, names[i]); err != nil {
+						return err
+					}
+				}
+				return zw.Close()
+			},
+		})
+	}
+}
+
+func writeBundled(zw *zip.Writer, blobs blob.Store, a models.Attachment, name string) error {
+	rc, _, err := blobs.Get(a.BlobKey)
+	if err != nil {
+		return fmt.Errorf("read attachment %s: %w", a.ID, err)
+	}
+	defer rc.Close()
+	fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: a.UploadedAt.UTC()})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, rc)
+	return err
+}
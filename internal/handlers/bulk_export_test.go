@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+func TestExportAuditLogETag(t *testing.T) {
+	st := store.NewMemory()
+	alice := newUser(t, st, "acme", "alice@acme.test", "admin", "")
+	old := time.Now().AddDate(-1, 0, 0)
+	st.AppendAudit(&models.AuditEvent{OrgID: "acme", Action: "review.created", CreatedAt: old})
+	st.AppendAudit(&models.AuditEvent{OrgID: "acme", Action: "review.approved"})
+	export := ExportAuditLog(st)
+
+	get := func(location, ifRange string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", location, nil)
+		if ifRange != "" {
+			req.Header.Set("Range", "bytes=10-")
+			req.Header.Set("If-Range", ifRange)
+		}
+		req = mux.SetURLVars(asUser(req, alice), map[string]string{"id": "acme"})
+		rec := httptest.NewRecorder()
+		export(rec, req)
+		return rec
+	}
+	first := get("/api/orgs/acme/audit/export", "")
+	etag, location := first.Header().Get("ETag"), first.Header().Get("Content-Location")
+
+	tests := []struct {
+		name   string
+		before func()
+		want   int
+	}{
+		{"unchanged", func() {}, http.StatusPartialContent},
+		{"newer events", func() { st.AppendAudit(&models.AuditEvent{OrgID: "acme", Action: "review.closed"}) }, http.StatusPartialContent},
+		{"truncated by retention", func() {
+			st.ApplyRetention(models.RetentionPolicy{OrgID: "acme", AuditMonths: 6}, &models.RetentionRun{At: time.Now()})
+		}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.before()
+			rec := get(location, etag)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if rec.Code == http.StatusOK && strings.Contains(rec.Body.String(), "review.created") {
+				t.Errorf("export still has the truncated event: %s", rec.Body)
+			}
+		})
+	}
+}
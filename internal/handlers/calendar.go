@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/calendar"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// orgCalendar returns the organization's work calendar, or the default one.
+func orgCalendar(st *store.Memory, orgID string) models.WorkCalendar {
+	if wc, ok := st.GetCalendar(orgID); ok {
+		return wc
+	}
+	return calendar.Default(orgID)
+}
+
+// GetOrgCalendar returns an organization's business hours and holidays.
+func GetOrgCalendar(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, orgCalendar(st, claims.OrgID))
+	}
+}
+
+// UpdateOrgCalendar replaces an organization's business hours and holidays.
+func UpdateOrgCalendar(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+
+		var wc models.WorkCalendar
+		if err := json.NewDecoder(r.Body).Decode(&wc); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		wc.OrgID = claims.OrgID
+		if wc.Holidays == nil {
+			wc.Holidays = []string{}
+		}
+		if _, err := calendar.New(wc); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		st.SetCalendar(wc)
+		respondJSON(w, http.StatusOK, wc)
+	}
+}
+
+// GetReviewSLA reports a review's SLA deadline in the organization's
+// working time, as configured by the review_sla_hours policy.
+func GetReviewSLA(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+
+		target := st.GetPolicy(review.OrgID).ReviewSLAHours
+		if target <= 0 {
+			respondError(w, http.StatusNotFound, "No review SLA is configured for this organization")
+			return
+		}
+		cal, err := calendar.New(orgCalendar(st, review.OrgID))
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		sla, err := cal.SLA(review, target, time.Now())
+		if err != nil {
+			respondError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusOK, sla)
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+	"github.com/gorilla/mux"
+)
+
+// ListComments returns the comment threads of a review. Pass
+// ?unresolved=true to list only unresolved threads.
+func ListComments(reviews aurea.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := actorFrom(w, r)
+		if !ok {
+			return
+		}
+		unresolvedOnly := r.URL.Query().Get("unresolved") == "true"
+		threads, err := reviews.ListThreads(r.Context(), actor, mux.Vars(r)["id"], unresolvedOnly)
+		if err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, threads)
+	}
+}
+
+// AddComment starts a new comment thread on a review.
+func AddComment(reviews aurea.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := actorFrom(w, r)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Body string `json:"body"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		comment, err := reviews.AddComment(r.Context(), actor, mux.Vars(r)["id"], req.Body)
+		if err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, comment)
+	}
+}
+
+// ResolveComment marks a comment thread as resolved.
+func ResolveComment(reviews aurea.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := actorFrom(w, r)
+		if !ok {
+			return
+		}
+		vars := mux.Vars(r)
+		res, err := reviews.ResolveThread(r.Context(), actor, vars["id"], vars["commentId"])
+		if err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, res)
+	}
+}
+
+// UnresolveComment reopens a resolved comment thread.
+func UnresolveComment(reviews aurea.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := actorFrom(w, r)
+		if !ok {
+			return
+		}
+		vars := mux.Vars(r)
+		if err := reviews.UnresolveThread(r.Context(), actor, vars["id"], vars["commentId"]); err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// RequireResolvedThreads blocks approval while the organization's policy
+// requires resolved threads and some remain open.
+func RequireResolvedThreads(st *store.Memory) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			review, ok := loadReview(st, w, r)
+			if !ok {
+				return
+			}
+			if st.GetPolicy(review.OrgID).RequireResolvedThreads {
+				n := st.CountUnresolved(review.ID)
+				middleware.TraceDecision(r.Context(), "policy.require_resolved_threads", n == 0, fmt.Sprintf("%d unresolved", n))
+				if n > 0 {
+					respondErrorf(w, http.StatusConflict, "%d comment thread(s) must be resolved before approval", n)
+					return
+				}
+			}
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+	"github.com/andres20980/aurea-orchestrator/internal/compliance"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/streaming"
+	"github.com/gorilla/mux"
+)
+
+// ListComplianceReports returns the organization's compliance evidence
+// bundles, latest quarter first.
+func ListComplianceReports(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListComplianceReports(claims.OrgID))
+	}
+}
+
+// GenerateComplianceReport reads {"quarter": "2026-Q3"} and generates the
+// organization's evidence bundle for it now, replacing any earlier one.
+// The quarter defaults to the current one, whose bundle is partial.
+func GenerateComplianceReport(gen *compliance.Generator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			Quarter string `json:"quarter"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		now := time.Now()
+		if req.Quarter == "" {
+			req.Quarter, _, _ = compliance.Quarter(now)
+		}
+		report, err := gen.Generate(claims.OrgID, req.Quarter, claims.UserID, now)
+		if errors.Is(err, compliance.ErrQuarter) {
+			respondError(w, http.StatusBadRequest, "quarter must name a quarter that has begun, like 2026-Q3")
+			return
+		}
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to generate compliance report")
+			return
+		}
+		respondJSON(w, http.StatusCreated, report)
+	}
+}
+
+// GetComplianceReport returns what the organization's bundle for a
+// quarter holds.
+func GetComplianceReport(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		report, err := st.GetComplianceReport(claims.OrgID, mux.Vars(r)["quarter"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, report)
+	}
+}
+
+// DownloadComplianceReport sends the organization's bundle for a quarter
+// as a ZIP file.
+func DownloadComplianceReport(st *store.Memory, blobs blob.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		report, err := st.GetComplianceReport(claims.OrgID, mux.Vars(r)["quarter"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		key := compliance.BlobKey(report.OrgID, report.Quarter)
+		streaming.Serve(w, r, streaming.Export{
+			Name:        "compliance-" + report.OrgID + "-" + report.Quarter + ".zip",
+			ContentType: streaming.Zip,
+			ETag:        `"` + report.SHA256 + `"`,
+			ModTime:     report.GeneratedAt,
+			Write: func(w io.Writer) error {
+				rc, _, err := blobs.Get(key)
+				if err != nil {
+					return err
+				}
+				defer rc.Close()
+				_, err = io.Copy(w, rc)
+				return err
+			},
+		})
+	}
+}
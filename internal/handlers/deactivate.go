@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/assign"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// Reassignment strategies for a deactivated user's open reviews.
+const (
+	reassignNone       = "unassign"
+	reassignToUser     = "user"
+	reassignRoundRobin = "round_robin"
+	reassignAuto       = "auto"
+)
+
+// DeactivationResult reports what happened to a deactivated user's work.
+type DeactivationResult struct {
+	Deactivation *models.Deactivation `json:"deactivation"`
+	Reassigned   []*models.Assignment `json:"reassigned"`
+	Unassigned   []string             `json:"unassigned"`
+}
+
+// DeactivateMember marks a member inactive, revoking their tokens, and
+// hands their open reviews over according to the requested strategy:
+// "unassign" (default), "user" (to reassign_to), "round_robin" or "auto"
+// (the organization's assignment strategy).
+func DeactivateMember(st *store.Memory, engine *assign.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Reason     string `json:"reason"`
+			Strategy   string `json:"strategy"`
+			ReassignTo string `json:"reassign_to"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+		}
+		if req.Strategy == "" {
+			req.Strategy = reassignNone
+		}
+
+		userID := mux.Vars(r)["userId"]
+		if userID == claims.UserID {
+			respondError(w, http.StatusBadRequest, "You cannot deactivate yourself")
+			return
+		}
+		user, err := st.GetUser(userID)
+		if err != nil || user.OrgID != claims.OrgID {
+			respondError(w, http.StatusNotFound, "User not found")
+			return
+		}
+
+		var target *models.User
+		switch req.Strategy {
+		case reassignNone, reassignRoundRobin, reassignAuto:
+		case reassignToUser:
+			target, err = st.GetUser(req.ReassignTo)
+			if err != nil || target.OrgID != claims.OrgID || target.ID == userID {
+				respondError(w, http.StatusBadRequest, "reassign_to must be another member of the organization")
+				return
+			}
+			if _, inactive := st.GetDeactivation(target.ID); inactive {
+				respondError(w, http.StatusBadRequest, "reassign_to user is deactivated")
+				return
+			}
+		default:
+			respondError(w, http.StatusBadRequest, "strategy must be one of unassign, user, round_robin, auto")
+			return
+		}
+
+		now := time.Now()
+		deactivation := &models.Deactivation{
+			UserID:        userID,
+			Reason:        req.Reason,
+			DeactivatedBy: claims.UserID,
+			DeactivatedAt: now,
+		}
+		if err := st.Deactivate(deactivation); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+
+		result := DeactivationResult{Deactivation: deactivation, Reassigned: []*models.Assignment{}, Unassigned: []string{}}
+		for _, review := range st.ListOpenAssigned(userID) {
+			next := target
+			switch req.Strategy {
+			case reassignRoundRobin:
+				next, _ = engine.PickWith(models.AssignRoundRobin, review, now)
+			case reassignAuto:
+				next, _, _ = engine.Pick(review, now)
+			}
+			if next == nil {
+				st.Unassign(review.ID)
+				result.Unassigned = append(result.Unassigned, review.ID)
+				continue
+			}
+			a := &models.Assignment{ReviewID: review.ID, ReviewerID: next.ID, AssignedBy: claims.UserID, AssignedAt: now}
+			st.Assign(a)
+			result.Reassigned = append(result.Reassigned, a)
+		}
+
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "user.deactivated",
+			TargetType: "user",
+			TargetID:   userID,
+			Reason:     req.Reason,
+			Metadata:   map[string]string{"strategy": req.Strategy},
+		})
+		respondJSON(w, http.StatusOK, result)
+	}
+}
+
+// ReactivateMember restores a deactivated member's access.
+func ReactivateMember(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+
+		userID := mux.Vars(r)["userId"]
+		user, err := st.GetUser(userID)
+		if err != nil || user.OrgID != claims.OrgID {
+			respondError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		st.Reactivate(userID)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "user.reactivated",
+			TargetType: "user",
+			TargetID:   userID,
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
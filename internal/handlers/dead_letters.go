@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/deadletter"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// deadLetterFilter reads ?kind= and ?before= (RFC 3339) into a filter for
+// orgID.
+func deadLetterFilter(w http.ResponseWriter, r *http.Request, orgID string) (models.DeadLetterFilter, bool) {
+	f := models.DeadLetterFilter{OrgID: orgID, Kind: r.URL.Query().Get("kind")}
+	if v := r.URL.Query().Get("before"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "before must be an RFC 3339 time")
+			return f, false
+		}
+		f.Before = t
+	}
+	return f, true
+}
+
+// retryDeadLetter retries a dead letter and responds with its outcome: 200
+// when the job succeeded, 502 with the kept letter when it failed again.
+func retryDeadLetter(w http.ResponseWriter, r *http.Request, q *deadletter.Queue, orgID string) {
+	letter, err := q.Retry(r.Context(), orgID, mux.Vars(r)["letterId"])
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		respondStoreError(w, err)
+	case err != nil:
+		respondJSON(w, http.StatusBadGateway, map[string]interface{}{"error": err.Error(), "dead_letter": letter})
+	default:
+		respondJSON(w, http.StatusOK, map[string]interface{}{"retried": true, "dead_letter": letter})
+	}
+}
+
+// ListDeadLetters returns the organization's failed jobs, filtered by
+// ?kind= (webhook, email, push) and ?before=.
+func ListDeadLetters(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		f, ok := deadLetterFilter(w, r, claims.OrgID)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListDeadLetters(f))
+	}
+}
+
+// GetDeadLetter returns one of the organization's failed jobs.
+func GetDeadLetter(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		letter, err := st.GetDeadLetter(claims.OrgID, mux.Vars(r)["letterId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, letter)
+	}
+}
+
+// RetryDeadLetter runs one of the organization's failed jobs again.
+func RetryDeadLetter(q *deadletter.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		retryDeadLetter(w, r, q, claims.OrgID)
+	}
+}
+
+// DeleteDeadLetter discards one of the organization's failed jobs.
+func DeleteDeadLetter(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		if err := st.DeleteDeadLetter(claims.OrgID, mux.Vars(r)["letterId"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// PurgeDeadLetters discards the organization's failed jobs, optionally only
+// those of ?kind= or failed ?before=.
+func PurgeDeadLetters(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		f, ok := deadLetterFilter(w, r, claims.OrgID)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]int{"purged": st.PurgeDeadLetters(f)})
+	}
+}
+
+// AdminListDeadLetters returns failed jobs across the instance, filtered by
+// ?org_id=, ?kind= and ?before=.
+func AdminListDeadLetters(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, ok := deadLetterFilter(w, r, r.URL.Query().Get("org_id"))
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListDeadLetters(f))
+	}
+}
+
+// AdminGetDeadLetter returns any failed job.
+func AdminGetDeadLetter(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		letter, err := st.GetDeadLetter("", mux.Vars(r)["letterId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, letter)
+	}
+}
+
+// AdminRetryDeadLetter runs any failed job again.
+func AdminRetryDeadLetter(q *deadletter.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		retryDeadLetter(w, r, q, "")
+	}
+}
+
+// AdminDeleteDeadLetter discards any failed job.
+func AdminDeleteDeadLetter(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := st.DeleteDeadLetter("", mux.Vars(r)["letterId"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// AdminPurgeDeadLetters discards failed jobs across the instance, narrowed
+// by ?org_id=, ?kind= and ?before=.
+func AdminPurgeDeadLetters(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, ok := deadLetterFilter(w, r, r.URL.Query().Get("org_id"))
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]int{"purged": st.PurgeDeadLetters(f)})
+	}
+}
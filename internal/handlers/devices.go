@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/push"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// ListDevices returns the caller's push devices.
+func ListDevices(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		respondJSON(w, http.StatusOK, st.ListDevices(claims.UserID))
+	}
+}
+
+// RegisterDevice registers a device token for push notifications. Without
+// events, the device receives push.DefaultEvents.
+func RegisterDevice(st *store.Memory, gateway *push.Gateway) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+
+		var req struct {
+			Platform string   `json:"platform"`
+			Token    string   `json:"token"`
+			Name     string   `json:"name"`
+			Events   []string `json:"events"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !slices.Contains(gateway.Platforms(), req.Platform) {
+			respondError(w, http.StatusBadRequest, "platform is not enabled on this server")
+			return
+		}
+		if req.Token == "" || len(req.Token) > 4096 {
+			respondError(w, http.StatusBadRequest, "token is required")
+			return
+		}
+		if req.Events == nil {
+			req.Events = push.DefaultEvents
+		}
+		if !validEventTypes(req.Events) {
+			respondError(w, http.StatusBadRequest, "Unknown event type")
+			return
+		}
+
+		device := &models.Device{
+			UserID:    claims.UserID,
+			Platform:  req.Platform,
+			Token:     req.Token,
+			Name:      req.Name,
+			Events:    req.Events,
+			CreatedAt: time.Now(),
+		}
+		st.RegisterDevice(device)
+		respondJSON(w, http.StatusCreated, device)
+	}
+}
+
+// UpdateDevice changes a device's name and notification preferences.
+// Omitted fields are left unchanged.
+func UpdateDevice(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+
+		var req struct {
+			Name   *string  `json:"name"`
+			Events []string `json:"events"`
+			Muted  *bool    `json:"muted"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Events != nil && !validEventTypes(req.Events) {
+			respondError(w, http.StatusBadRequest, "Unknown event type")
+			return
+		}
+
+		device, err := st.UpdateDevice(claims.UserID, mux.Vars(r)["deviceId"], func(d *models.Device) {
+			if req.Name != nil {
+				d.Name = *req.Name
+			}
+			if req.Events != nil {
+				d.Events = req.Events
+			}
+			if req.Muted != nil {
+				d.Muted = *req.Muted
+			}
+		})
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, device)
+	}
+}
+
+// UnregisterDevice stops pushing to a device.
+func UnregisterDevice(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		if err := st.RemoveDevice(claims.UserID, mux.Vars(r)["deviceId"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func validEventTypes(events []string) bool {
+	for _, e := range events {
+		if !knownEvent(e) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/duplicates"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// FollowMerged wraps a review handler and redirects requests for a review
+// that was merged into another to the same path on the surviving review.
+func FollowMerged(st *store.Memory) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			id := mux.Vars(r)["id"]
+			merge, merged := st.MergedInto(id)
+			claims, ok := middleware.GetClaims(r.Context())
+			if !merged || !ok || merge.OrgID != claims.OrgID {
+				next(w, r)
+				return
+			}
+			u := *r.URL
+			u.Path = strings.Replace(u.Path, "/reviews/"+id, "/reviews/"+merge.TargetID, 1)
+			w.Header().Set("X-Merged-Into", merge.TargetID)
+			http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
+		}
+	}
+}
+
+// FindDuplicates lists the organization's reviews most similar to a review
+// by title and content. ?threshold= (0 to 1, default 0.5) sets the
+// minimum score and ?limit= (default 10) the number of results.
+func FindDuplicates(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		q := r.URL.Query()
+		threshold := duplicates.DefaultThreshold
+		if v := q.Get("threshold"); v != "" {
+			t, err := strconv.ParseFloat(v, 64)
+			if err != nil || t < 0 || t > 1 {
+				respondError(w, http.StatusBadRequest, "threshold must be a number between 0 and 1")
+				return
+			}
+			threshold = t
+		}
+		limit := 10
+		if v := q.Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				respondError(w, http.StatusBadRequest, "limit must be a positive integer")
+				return
+			}
+			limit = n
+		}
+		respondJSON(w, http.StatusOK, duplicates.Find(review, st.ListOrgReviews(review.OrgID), threshold, limit))
+	}
+}
+
+// MergeReview reads {"into": "<review id>"} and folds the review in the URL
+// into that one: comments, labels, watchers, attachments and escalation
+// history move over, and the merged ID redirects to the target from then
+// on. Approved reviews cannot be merged away.
+func MergeReview(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		source, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		claims, _ := middleware.GetClaims(r.Context())
+		var req struct {
+			Into string `json:"into"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		target, err := st.GetReview(req.Into)
+		if err != nil || target.OrgID != source.OrgID || target.ID == source.ID {
+			respondError(w, http.StatusBadRequest, "into must be another review of the organization")
+			return
+		}
+		if isLocked(st, source) {
+			respondError(w, http.StatusConflict, "Review is approved and cannot be modified; reopen it first")
+			return
+		}
+		merge := &models.ReviewMerge{SourceID: source.ID, TargetID: target.ID, MergedBy: claims.UserID, MergedAt: time.Now()}
+		err = st.Update(func(tx *store.Tx) error {
+			if err := tx.MergeReview(merge); err != nil {
+				return err
+			}
+			tx.AppendAudit(&models.AuditEvent{
+				OrgID:      source.OrgID,
+				ActorID:    claims.UserID,
+				Action:     "review.merged",
+				TargetType: "review",
+				TargetID:   source.ID,
+				Metadata:   map[string]string{"into": target.ID, "comments": strconv.Itoa(merge.Comments)},
+			})
+			return nil
+		})
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, merge)
+	}
+}
@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/emailapproval"
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/mail"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+	"github.com/gorilla/mux"
+)
+
+// InboundSecretHeader carries the shared secret on inbound email webhooks.
+const InboundSecretHeader = "X-Inbound-Secret"
+
+// EmailApprovals sends approval-request emails and carries out the
+// decisions made from them, either through the signed links or by reply.
+type EmailApprovals struct {
+	Store   *store.Memory
+	Mailer  mail.Mailer
+	Links   *emailapproval.Links
+	Issuer  middleware.TokenIssuer
+	Reviews aurea.ReviewService
+	// Approve is the authenticated approve endpoint. Approvals made by
+	// email go through it so they are checked, recorded and announced
+	// like any other approval.
+	Approve http.Handler
+	// Checks run before an approver acts on a link or reply, with the
+	// approver's token in the Authorization header: the same region,
+	// account, read-only and network checks as the API. Nil runs none.
+	Checks  mux.MiddlewareFunc
+	BaseURL string
+	// ReplyDomain receives replies for the inbound webhook. Without it
+	// emails carry links only.
+	ReplyDomain string
+	TTL         time.Duration
+}
+
+// RequestApproval emails the review's approvers a request to approve it.
+// The body may name approvers by user ID; it defaults to the org admins.
+func (e *EmailApprovals) RequestApproval(w http.ResponseWriter, r *http.Request) {
+	review, ok := loadReview(e.Store, w, r)
+	if !ok {
+		return
+	}
+	claims, _ := middleware.GetClaims(r.Context())
+	var req struct {
+		Approvers []string `json:"approvers"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	var approvers []*models.User
+	for _, u := range e.Store.ListOrgReviewers(review.OrgID) {
+		if u.Role == "admin" && (len(req.Approvers) == 0 || slices.Contains(req.Approvers, u.ID)) {
+			approvers = append(approvers, u)
+		}
+	}
+	if len(approvers) == 0 {
+		respondError(w, http.StatusBadRequest, "No active admins to request approval from")
+		return
+	}
+
+	now := time.Now()
+	sent := []*models.EmailApproval{}
+	for _, u := range approvers {
+		id, err := newRequestID()
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to generate link")
+			return
+		}
+		a := &models.EmailApproval{
+			ID:          id,
+			ReviewID:    review.ID,
+			OrgID:       review.OrgID,
+			UserID:      u.ID,
+			Email:       u.Email,
+			RequestedBy: claims.UserID,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(e.TTL),
+		}
+		e.Store.SaveEmailApproval(a)
+		if err := e.Mailer.Send(e.message(a, review)); err != nil {
+			log.Printf("Failed to email approval request for review %s to %s: %v", review.ID, u.ID, err)
+			continue
+		}
+		sent = append(sent, a)
+	}
+	respondJSON(w, http.StatusCreated, sent)
+}
+
+func (e *EmailApprovals) message(a *models.EmailApproval, review *models.Review) mail.Message {
+	lang := i18n.Negotiate(e.Store.GetProfile(a.UserID).Locale, "")
+	link := strings.TrimRight(e.BaseURL, "/") + "/email-approval/"
+	var body strings.Builder
+	body.WriteString(i18n.T(lang, "Your approval is requested for review %s: %s", review.ID, review.Title))
+	body.WriteString("\n\n")
+	body.WriteString(i18n.T(lang, "Approve: %s", link+e.Links.Sign(a.ID, emailapproval.Approve)))
+	body.WriteString("\n")
+	body.WriteString(i18n.T(lang, "Reject: %s", link+e.Links.Sign(a.ID, emailapproval.Reject)))
+	body.WriteString("\n\n")
+	if e.ReplyDomain != "" {
+		body.WriteString(i18n.T(lang, "Or reply to this email with \"approve\" or \"reject\" on the first line, followed by an optional note."))
+		body.WriteString("\n")
+	}
+	body.WriteString(i18n.T(lang, "These links can be used once and expire on %s.", a.ExpiresAt.UTC().Format(time.RFC1123)))
+
+	msg := mail.Message{
+		To:      a.Email,
+		Subject: i18n.T(lang, "Approval requested: %s", review.Title),
+		Body:    body.String(),
+	}
+	if e.ReplyDomain != "" {
+		msg.ReplyTo = emailapproval.ReplyAddress(e.ReplyDomain, a.ID)
+	}
+	return msg
+}
+
+// emailApprovalPage asks for confirmation before acting, so mail scanners
+// that prefetch links cannot use them up.
+var emailApprovalPage = template.Must(template.New("approval").Parse(`<!doctype html>
+<html><head><meta charset="utf-8"><title>{{.Heading}}</title></head>
+<body>
+<h1>{{.Heading}}</h1>
+<p>{{.Title}}</p>
+<form method="post">
+{{if .Reject}}<p><label>{{.NoteLabel}}<br><textarea name="note" rows="4" cols="60"></textarea></label></p>{{end}}
+<button type="submit">{{.Button}}</button>
+</form>
+</body></html>
+`))
+
+// ConfirmPage shows the decision behind a signed link for the approver
+// to confirm.
+func (e *EmailApprovals) ConfirmPage(w http.ResponseWriter, r *http.Request) {
+	a, action, ok := e.resolveLink(w, r)
+	if !ok {
+		return
+	}
+	e.asApprover(w, r, a, func(w http.ResponseWriter, r *http.Request) {
+		e.confirmPage(w, r, a, action)
+	})
+}
+
+func (e *EmailApprovals) confirmPage(w http.ResponseWriter, r *http.Request, a *models.EmailApproval, action string) {
+	lang := i18n.FromContext(r.Context())
+	review, err := e.Store.GetReview(a.ReviewID)
+	if err != nil {
+		respondStoreError(w, err)
+		return
+	}
+	heading, button := i18n.T(lang, "Approve review %s", review.ID), i18n.T(lang, "Approve")
+	if action == emailapproval.Reject {
+		heading, button = i18n.T(lang, "Reject review %s", review.ID), i18n.T(lang, "Reject")
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	if err := emailApprovalPage.Execute(w, map[string]interface{}{
+		"Heading":   heading,
+		"Title":     review.Title,
+		"Reject":    action == emailapproval.Reject,
+		"NoteLabel": i18n.T(lang, "Note for the author (optional)"),
+		"Button":    button,
+	}); err != nil {
+		log.Printf("Failed to render approval page: %v", err)
+	}
+}
+
+// Confirm carries out the decision behind a signed link.
+func (e *EmailApprovals) Confirm(w http.ResponseWriter, r *http.Request) {
+	a, action, ok := e.resolveLink(w, r)
+	if !ok {
+		return
+	}
+	rewind, err := keepBody(w, r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	note := r.FormValue("note")
+	rewind()
+	e.asApprover(w, r, a, func(w http.ResponseWriter, r *http.Request) {
+		e.act(w, r, a.ID, action, note)
+	})
+}
+
+// InboundReply receives replies to approval-request emails from an inbound
+// email provider's webhook. The provider must send the shared secret in
+// InboundSecretHeader and a JSON body with the reply's from, to and text.
+func (e *EmailApprovals) InboundReply(secret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if secret == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get(InboundSecretHeader)), []byte(secret)) != 1 {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		var req struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+			Text string `json:"text"`
+		}
+		rewind, err := keepBody(w, r)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		rewind()
+		id := emailapproval.RequestFromAddress(req.To)
+		action, note := emailapproval.ParseReply(req.Text)
+		if id == "" || action == "" {
+			respondError(w, http.StatusUnprocessableEntity, "Reply is not an approval decision")
+			return
+		}
+		a, err := e.Store.GetEmailApproval(id, time.Now())
+		if err != nil {
+			respondError(w, http.StatusNotFound, "Approval link is invalid or has expired")
+			return
+		}
+		if !strings.EqualFold(addressOf(req.From), a.Email) {
+			respondError(w, http.StatusForbidden, "Reply must come from the approver's address")
+			return
+		}
+		e.asApprover(w, r, a, func(w http.ResponseWriter, r *http.Request) {
+			e.act(w, r, id, action, note)
+		})
+	}
+}
+
+// resolveLink verifies the signed link in the request and looks up its
+// approval request.
+func (e *EmailApprovals) resolveLink(w http.ResponseWriter, r *http.Request) (*models.EmailApproval, string, bool) {
+	id, action, err := e.Links.Verify(mux.Vars(r)["token"])
+	if err != nil {
+		respondError(w, http.StatusNotFound, "Approval link is invalid or has expired")
+		return nil, "", false
+	}
+	a, err := e.Store.GetEmailApproval(id, time.Now())
+	if err != nil {
+		respondError(w, http.StatusNotFound, "Approval link is invalid or has expired")
+		return nil, "", false
+	}
+	return a, action, true
+}
+
+// asApprover runs next as the approver of a, who must still be an active
+// admin of the review's org, behind Checks. Links and replies reach the
+// server outside the API, so without this an approver could act while the
+// org is suspended or the instance read-only, or from a network the org
+// does not allow.
+func (e *EmailApprovals) asApprover(w http.ResponseWriter, r *http.Request, a *models.EmailApproval, next http.HandlerFunc) {
+	user, err := e.Store.GetUser(a.UserID)
+	_, inactive := e.Store.GetDeactivation(a.UserID)
+	if err != nil || inactive || user.OrgID != a.OrgID || user.Role != "admin" {
+		respondError(w, http.StatusForbidden, "Forbidden: insufficient permissions")
+		return
+	}
+	token, err := e.Issuer.GenerateToken(user.ID, user.Email, user.OrgID, user.Role)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+	if e.Checks != nil {
+		e.Checks(next).ServeHTTP(w, r)
+		return
+	}
+	next(w, r)
+}
+
+// keepBody reads r's body so it can be read again after rewind, in case
+// Checks forwards the request to the region that holds the org's data.
+func keepBody(w http.ResponseWriter, r *http.Request) (rewind func(), err error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	rewind = func() { r.Body = io.NopCloser(bytes.NewReader(body)) }
+	rewind()
+	return rewind, nil
+}
+
+// act uses up the approval request and applies the decision as its
+// approver. It runs from asApprover, so r carries the approver's token.
+func (e *EmailApprovals) act(w http.ResponseWriter, r *http.Request, id, action, note string) {
+	a, err := e.Store.UseEmailApproval(id, action, time.Now())
+	if err != nil {
+		respondError(w, http.StatusNotFound, "Approval link is invalid or has expired")
+		return
+	}
+	user, err := e.Store.GetUser(a.UserID)
+	if err != nil {
+		respondStoreError(w, err)
+		return
+	}
+
+	if action == emailapproval.Reject {
+		body := i18n.T(i18n.Negotiate(e.Store.GetProfile(user.ID).Locale, ""), "Rejected by email.")
+		if note != "" {
+			body += "\n\n" + note
+		}
+		if _, err := e.Reviews.AddComment(r.Context(), aurea.Actor{UserID: user.ID, OrgID: user.OrgID}, a.ReviewID, body); err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, a)
+		return
+	}
+
+	approve, err := http.NewRequestWithContext(r.Context(), http.MethodPost, "/api/reviews/"+a.ReviewID+"/approve", http.NoBody)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	approve.Header.Set("Authorization", r.Header.Get("Authorization"))
+	approve.RemoteAddr = r.RemoteAddr
+	e.Approve.ServeHTTP(w, mux.SetURLVars(approve, map[string]string{"id": a.ReviewID}))
+}
+
+// addressOf returns the bare address of a From header value.
+func addressOf(from string) string {
+	if i := strings.LastIndex(from, "<"); i >= 0 {
+		from = strings.TrimSuffix(from[i+1:], ">")
+	}
+	return strings.TrimSpace(from)
+}
+
+// newRequestID returns a random ID that is safe to put in a reply address.
+func newRequestID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/emailapproval"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/signing"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+	"github.com/gorilla/mux"
+)
+
+func TestEmailApprovalChecks(t *testing.T) {
+	st := store.NewMemory()
+	svc := aurea.NewWithStore(st, notify.NewDispatcher(st))
+	signer, err := signing.NewEphemeralSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.UseApprovalSigner(signer)
+	author := newUser(t, st, "acme", "author@acme.test", "member", "")
+	admin := newUser(t, st, "acme", "admin@acme.test", "admin", "")
+	review, _ := svc.Reviews().Create(context.Background(), aurea.Actor{UserID: author.ID, OrgID: "acme"}, "Release", "...")
+
+	links := emailapproval.NewLinks("secret")
+	e := &EmailApprovals{
+		Store:   st,
+		Links:   links,
+		Issuer:  auth.NewService("secret", time.Hour),
+		Reviews: svc.Reviews(),
+		Approve: middleware.JWTAuth("secret")(ApproveReview(svc.Reviews())),
+		Checks: middleware.Chain(
+			middleware.JWTAuth("secret"),
+			middleware.RestrictNetwork(st, nil, nil),
+			middleware.ReadOnly(st, nil),
+		),
+	}
+	now := time.Now()
+	st.SaveEmailApproval(&models.EmailApproval{ID: "req-1", ReviewID: review.ID, OrgID: "acme", UserID: admin.ID, Email: admin.Email, CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+	token := links.Sign("req-1", emailapproval.Approve)
+
+	confirm := func(t *testing.T, remote string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/email-approval/"+token, strings.NewReader(""))
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		e.Confirm(rec, mux.SetURLVars(req, map[string]string{"token": token}))
+		return rec
+	}
+	reply := func(t *testing.T) *httptest.ResponseRecorder {
+		t.Helper()
+		body := `{"from":"` + admin.Email + `","to":"` + emailapproval.ReplyAddress("reply.acme.test", "req-1") + `","text":"approve"}`
+		req := httptest.NewRequest("POST", "/inbound/email", strings.NewReader(body))
+		req.Header.Set(InboundSecretHeader, "inbound")
+		rec := httptest.NewRecorder()
+		e.InboundReply("inbound")(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name   string
+		setup  func()
+		act    func(t *testing.T) *httptest.ResponseRecorder
+		want   int
+		status string
+	}{
+		{
+			name:   "link during maintenance",
+			setup:  func() { st.SetMaintenance(models.MaintenanceMode{Enabled: true}) },
+			act:    func(t *testing.T) *httptest.ResponseRecorder { return confirm(t, "203.0.113.7:4000") },
+			want:   http.StatusServiceUnavailable,
+			status: aurea.StatusPending,
+		},
+		{
+			name:   "reply during maintenance",
+			act:    reply,
+			want:   http.StatusServiceUnavailable,
+			status: aurea.StatusPending,
+		},
+		{
+			name: "link from outside the org's networks",
+			setup: func() {
+				st.SetMaintenance(models.MaintenanceMode{})
+				st.SetNetworkPolicy(models.NetworkPolicy{OrgID: "acme", CIDRs: []string{"203.0.113.0/24"}})
+			},
+			act:    func(t *testing.T) *httptest.ResponseRecorder { return confirm(t, "198.51.100.9:4000") },
+			want:   http.StatusForbidden,
+			status: aurea.StatusPending,
+		},
+		{
+			name:   "link from the org's networks",
+			act:    func(t *testing.T) *httptest.ResponseRecorder { return confirm(t, "203.0.113.7:4000") },
+			want:   http.StatusOK,
+			status: aurea.StatusApproved,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+			if rec := tt.act(t); rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if got, _ := st.GetReview(review.ID); got.Status != tt.status {
+				t.Errorf("review status = %q, want %q", got.Status, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	mailer "github.com/andres20980/aurea-orchestrator/internal/mail"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// emailVerificationPurpose is the token purpose of verification links.
+const emailVerificationPurpose = "email_verification"
+
+// EmailVerifier confirms email addresses with signed, single-use links:
+// a new account stays inactive, and a changed address does not take
+// effect, until the link sent to the address is followed.
+type EmailVerifier struct {
+	Store   *store.Memory
+	Mailer  mailer.Mailer
+	Signer  *security.TokenSigner
+	Issuer  middleware.TokenIssuer
+	Limiter *security.RateLimiter
+	BaseURL string
+	TTL     time.Duration
+	// Proxies are believed about the client IP of sessions started by
+	// verification.
+	Proxies security.TrustedProxies
+}
+
+// Send emails a verification link for email to the user.
+func (e *EmailVerifier) Send(r *http.Request, user *models.User, email, purpose string) error {
+	expires := time.Now().Add(e.TTL)
+	token, err := e.Signer.Sign(emailVerificationPurpose, user.ID, expires)
+	if err != nil {
+		return err
+	}
+	e.Store.SaveEmailVerification(middleware.HashAPIKey(token), models.EmailVerification{
+		UserID:    user.ID,
+		Email:     email,
+		Purpose:   purpose,
+		ExpiresAt: expires,
+	})
+	lang := i18n.Negotiate(e.Store.GetProfile(user.ID).Locale, r.Header.Get("Accept-Language"))
+	subject := i18n.T(lang, "Confirm your email address for Aurea Orchestrator")
+	if purpose == models.VerifyEmailChange {
+		subject = i18n.T(lang, "Confirm your new email address for Aurea Orchestrator")
+	}
+	return e.Mailer.Send(mailer.Message{
+		To:      email,
+		Subject: subject,
+		Body:    i18n.T(lang, "Confirm this address by opening %s before %s.", e.BaseURL+"/verify-email?token="+token, expires.Format(time.RFC1123)),
+	})
+}
+
+// Verify reads {"token": "..."} from a verification link. For a new
+// account it activates the account, adds it to the organization it signed
+// up to, and returns an access token; for an
+// email change it switches the user to the new address.
+func (e *EmailVerifier) Verify(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	now := time.Now()
+	userID, err := e.Signer.Verify(emailVerificationPurpose, req.Token, now)
+	if err != nil {
+		respondError(w, http.StatusUnauthorized, "Invalid or expired verification link")
+		return
+	}
+	v, err := e.Store.TakeEmailVerification(middleware.HashAPIKey(req.Token), now)
+	if err != nil || v.UserID != userID {
+		respondError(w, http.StatusUnauthorized, "Invalid or expired verification link")
+		return
+	}
+
+	switch v.Purpose {
+	case models.VerifySignup:
+		user, err := e.Store.ConfirmSignup(userID)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		token, err := e.Issuer.GenerateToken(user.ID, user.Email, user.OrgID, user.Role)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+		startSession(e.Store, r, token, user.ID, user.OrgID, now.Add(24*time.Hour), e.Proxies)
+		respondJSON(w, http.StatusOK, map[string]interface{}{"user": user, "token": token})
+	case models.VerifyEmailChange:
+		previous, err := e.Store.GetUser(userID)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		oldEmail := previous.Email
+		user, err := e.Store.ChangeUserEmail(userID, v.Email)
+		if errors.Is(err, store.ErrDuplicate) {
+			respondError(w, http.StatusConflict, "email already registered")
+			return
+		}
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		e.Store.AppendAudit(&models.AuditEvent{
+			OrgID:      user.OrgID,
+			ActorID:    user.ID,
+			Action:     "email.changed",
+			TargetType: "user",
+			TargetID:   user.ID,
+			Metadata:   map[string]string{"from": oldEmail, "to": user.Email},
+		})
+		respondJSON(w, http.StatusOK, map[string]interface{}{"user": user})
+	}
+}
+
+// Resend reads {"email": "..."} and sends a new verification link to an
+// account that has not confirmed its address yet. Like ForgotPassword it
+// answers 202 either way and limits requests per address.
+func (e *EmailVerifier) Resend(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil || req.Email == "" {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if !e.allow(w, email) {
+		return
+	}
+	if user, err := e.Store.GetUserByEmail(email); err == nil && !e.Store.EmailVerified(user.ID) {
+		if err := e.Send(r, user, user.Email, models.VerifySignup); err != nil {
+			log.Printf("Failed to resend verification to %s: %v", user.ID, err)
+		}
+	}
+	respondJSON(w, http.StatusAccepted, map[string]string{"status": i18n.T(i18n.LangOf(w), "If the account awaits verification, a new link has been sent")})
+}
+
+// ChangeEmail reads {"email": "...", "password": "..."} and sends a
+// verification link to the new address. The caller keeps their current
+// address until the link is followed. Users with a password must confirm
+// it.
+func (e *EmailVerifier) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	var req struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	email := strings.TrimSpace(req.Email)
+	if _, err := mail.ParseAddress(email); err != nil || !strings.Contains(email, "@") {
+		respondError(w, http.StatusBadRequest, "Invalid email address")
+		return
+	}
+	if hash, ok := e.Store.PasswordHash(claims.UserID); ok && !security.CheckPassword(hash, req.Password) {
+		respondError(w, http.StatusForbidden, "Current password is incorrect")
+		return
+	}
+	if existing, err := e.Store.GetUserByEmail(email); err == nil && existing.ID != claims.UserID {
+		respondError(w, http.StatusConflict, "email already registered")
+		return
+	}
+	user, err := e.Store.GetUser(claims.UserID)
+	if err != nil {
+		respondStoreError(w, err)
+		return
+	}
+	if !e.allow(w, strings.ToLower(user.Email)) {
+		return
+	}
+	if err := e.Send(r, user, email, models.VerifyEmailChange); err != nil {
+		log.Printf("Failed to send email change verification to %s: %v", user.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to send verification email")
+		return
+	}
+	respondJSON(w, http.StatusAccepted, map[string]string{"pending_email": email})
+}
+
+// ResendEmailChange sends a new link for the caller's pending email
+// change.
+func (e *EmailVerifier) ResendEmailChange(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	pending, ok := e.Store.PendingEmailVerification(claims.UserID, models.VerifyEmailChange, time.Now())
+	if !ok {
+		respondError(w, http.StatusNotFound, "No email change is pending")
+		return
+	}
+	user, err := e.Store.GetUser(claims.UserID)
+	if err != nil {
+		respondStoreError(w, err)
+		return
+	}
+	if !e.allow(w, strings.ToLower(user.Email)) {
+		return
+	}
+	if err := e.Send(r, user, pending.Email, models.VerifyEmailChange); err != nil {
+		log.Printf("Failed to resend email change verification to %s: %v", user.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to send verification email")
+		return
+	}
+	respondJSON(w, http.StatusAccepted, map[string]string{"pending_email": pending.Email})
+}
+
+func (e *EmailVerifier) allow(w http.ResponseWriter, key string) bool {
+	if ok, until := e.Limiter.Allow(key); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(until).Seconds())+1))
+		respondError(w, http.StatusTooManyRequests, "Too many verification emails; try again later")
+		return false
+	}
+	return true
+}
+
+// RequireVerified wraps the login handler and refuses accounts whose email
+// address has not been confirmed yet.
+func (e *EmailVerifier) RequireVerified(jwtSecret string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			held := holdResponse(w)
+			next(held, r)
+			if held.status != 0 && (held.status < 200 || held.status >= 300) {
+				held.flush(w)
+				return
+			}
+
+			var issued struct {
+				Token string `json:"token"`
+			}
+			claims := &auth.Claims{}
+			if err := json.Unmarshal(held.body.Bytes(), &issued); err != nil || issued.Token == "" {
+				held.flush(w)
+				return
+			}
+			if _, err := jwt.ParseWithClaims(issued.Token, claims, func(*jwt.Token) (interface{}, error) {
+				return []byte(jwtSecret), nil
+			}); err != nil || e.Store.EmailVerified(claims.UserID) {
+				held.flush(w)
+				return
+			}
+			respondJSON(w, http.StatusForbidden, map[string]interface{}{
+				"error":                 i18n.T(i18n.LangOf(w), "Confirm your email address before signing in; check your inbox or ask for a new link"),
+				"verification_required": true,
+			})
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/escalation"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+	"github.com/gorilla/mux"
+)
+
+// SetReviewPriority sets the priority of a review.
+func SetReviewPriority(reviews aurea.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := actorFrom(w, r)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Priority string `json:"priority"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		id := mux.Vars(r)["id"]
+		if err := reviews.SetPriority(r.Context(), actor, id, req.Priority); err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]string{"review_id": id, "priority": req.Priority})
+	}
+}
+
+// GetEscalationConfig returns the organization's paging configuration with
+// the routing key redacted.
+func GetEscalationConfig(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		cfg, ok := st.GetEscalationConfig(claims.OrgID)
+		if !ok {
+			respondError(w, http.StatusNotFound, "Escalation is not configured")
+			return
+		}
+		if cfg.RoutingKey != "" {
+			cfg.RoutingKey = "********"
+		}
+		respondJSON(w, http.StatusOK, cfg)
+	}
+}
+
+// UpdateEscalationConfig replaces the organization's paging configuration.
+// An omitted routing key keeps the current one.
+func UpdateEscalationConfig(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+
+		var cfg models.EscalationConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		cfg.OrgID = claims.OrgID
+		if cfg.RoutingKey == "" {
+			if current, ok := st.GetEscalationConfig(claims.OrgID); ok {
+				cfg.RoutingKey = current.RoutingKey
+			}
+		}
+		if _, err := escalation.PagerFor(cfg.Provider, nil); err != nil {
+			respondError(w, http.StatusBadRequest, "provider must be pagerduty or opsgenie")
+			return
+		}
+		if cfg.Enabled && cfg.RoutingKey == "" {
+			respondError(w, http.StatusBadRequest, "routing_key is required to enable escalation")
+			return
+		}
+
+		st.SetEscalationConfig(cfg)
+		cfg.RoutingKey = "********"
+		respondJSON(w, http.StatusOK, cfg)
+	}
+}
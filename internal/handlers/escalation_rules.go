@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+type escalationRuleRequest struct {
+	Name       string `json:"name"`
+	AfterHours int    `json:"after_hours"`
+	Action     string `json:"action"`
+	Priority   string `json:"priority"`
+	Risk       string `json:"risk"`
+	Enabled    *bool  `json:"enabled"`
+}
+
+func (req escalationRuleRequest) validate(w http.ResponseWriter) bool {
+	if req.Name == "" {
+		respondError(w, http.StatusBadRequest, "name is required")
+		return false
+	}
+	if req.AfterHours < 1 {
+		respondError(w, http.StatusBadRequest, "after_hours must be at least 1")
+		return false
+	}
+	switch req.Action {
+	case models.EscalateNotifyAssignee, models.EscalateNotifyManager, models.EscalateReassign:
+	default:
+		respondError(w, http.StatusBadRequest, "action must be one of notify_assignee, notify_manager, reassign")
+		return false
+	}
+	switch req.Priority {
+	case "", models.PriorityLow, models.PriorityNormal, models.PriorityHigh, models.PriorityCritical:
+	default:
+		respondError(w, http.StatusBadRequest, "priority must be one of low, normal, high, critical")
+		return false
+	}
+	if req.Risk != "" && !slices.Contains(models.RiskLevels, req.Risk) {
+		respondError(w, http.StatusBadRequest, "risk must be one of low, medium, high")
+		return false
+	}
+	return true
+}
+
+func (req escalationRuleRequest) apply(rule *models.EscalationRule) {
+	rule.Name, rule.AfterHours, rule.Action, rule.Priority = req.Name, req.AfterHours, req.Action, req.Priority
+	rule.Risk = req.Risk
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+}
+
+// ListEscalationRules returns the organization's stall escalation rules.
+func ListEscalationRules(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListEscalationRules(claims.OrgID))
+	}
+}
+
+// CreateEscalationRule adds a rule acting on reviews without activity for
+// after_hours: notify_assignee, notify_manager or reassign. Rules are
+// enabled unless "enabled" is false.
+func CreateEscalationRule(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req escalationRuleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !req.validate(w) {
+			return
+		}
+		now := time.Now()
+		rule := models.EscalationRule{OrgID: claims.OrgID, Enabled: true, CreatedBy: claims.UserID, CreatedAt: now, UpdatedAt: now}
+		req.apply(&rule)
+		respondJSON(w, http.StatusCreated, st.CreateEscalationRule(rule))
+	}
+}
+
+// GetEscalationRule returns one of the organization's rules.
+func GetEscalationRule(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		rule, err := st.GetEscalationRule(claims.OrgID, mux.Vars(r)["ruleId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, rule)
+	}
+}
+
+// UpdateEscalationRule replaces one of the organization's rules. An
+// omitted "enabled" keeps the current state.
+func UpdateEscalationRule(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		rule, err := st.GetEscalationRule(claims.OrgID, mux.Vars(r)["ruleId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		var req escalationRuleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !req.validate(w) {
+			return
+		}
+		req.apply(&rule)
+		rule.UpdatedAt = time.Now()
+		if err := st.UpdateEscalationRule(rule); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, rule)
+	}
+}
+
+// DeleteEscalationRule removes one of the organization's rules.
+func DeleteEscalationRule(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		if err := st.DeleteEscalationRule(claims.OrgID, mux.Vars(r)["ruleId"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetEscalationHistory returns the escalation steps taken on a review.
+func GetEscalationHistory(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.EscalationHistory(review.ID))
+	}
+}
+
+// SetMemberManager reads {"manager_id": "..."} and records who a member
+// reports to, for notify_manager rules; an empty ID clears it.
+func SetMemberManager(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := orgMember(w, r, st)
+		if !ok {
+			return
+		}
+		claims, _ := middleware.GetClaims(r.Context())
+		var req struct {
+			ManagerID string `json:"manager_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.ManagerID != "" {
+			manager, err := st.GetUser(req.ManagerID)
+			if err != nil || manager.OrgID != claims.OrgID || manager.ID == userID {
+				respondError(w, http.StatusBadRequest, "manager_id must be another member of the organization")
+				return
+			}
+		}
+		st.SetManager(userID, req.ManagerID)
+		respondJSON(w, http.StatusOK, map[string]string{"user_id": userID, "manager_id": req.ManagerID})
+	}
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/assign"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+const (
+	maxExpertiseAreas  = 20
+	maxExpertiseLength = 50
+)
+
+// expertiseResponse is the body of the expertise endpoints.
+type expertiseResponse struct {
+	UserID string   `json:"user_id"`
+	Areas  []string `json:"areas"`
+}
+
+// suggestedReviewer is one ranked reviewer suggestion.
+type suggestedReviewer struct {
+	UserID string   `json:"user_id"`
+	Name   string   `json:"name"`
+	Skills []string `json:"skills"`
+	// Approvals counts the user's approvals sharing labels with the review.
+	Approvals int `json:"approvals"`
+	Open      int `json:"open"`
+}
+
+// setExpertise reads {"areas": [...]} and replaces userID's expertise
+// areas, lowercased and deduplicated.
+func setExpertise(w http.ResponseWriter, r *http.Request, st *store.Memory, userID string) {
+	var req struct {
+		Areas []string `json:"areas"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	areas := []string{}
+	for _, a := range req.Areas {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == "" || len(a) > maxExpertiseLength {
+			respondErrorf(w, http.StatusBadRequest, "expertise areas must be 1 to %d characters", maxExpertiseLength)
+			return
+		}
+		if !slices.Contains(areas, a) {
+			areas = append(areas, a)
+		}
+	}
+	if len(areas) > maxExpertiseAreas {
+		respondErrorf(w, http.StatusBadRequest, "at most %d expertise areas are allowed", maxExpertiseAreas)
+		return
+	}
+	slices.Sort(areas)
+	st.SetExpertise(userID, areas)
+	respondJSON(w, http.StatusOK, expertiseResponse{UserID: userID, Areas: areas})
+}
+
+// orgMember returns the member in the URL if they belong to the caller's
+// organization.
+func orgMember(w http.ResponseWriter, r *http.Request, st *store.Memory) (string, bool) {
+	claims, ok := requireOwnOrg(w, r)
+	if !ok {
+		return "", false
+	}
+	user, err := st.GetUser(mux.Vars(r)["userId"])
+	if err != nil || user.OrgID != claims.OrgID {
+		respondError(w, http.StatusNotFound, "User not found")
+		return "", false
+	}
+	return user.ID, true
+}
+
+// GetMyExpertise returns the caller's expertise areas.
+func GetMyExpertise(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		respondJSON(w, http.StatusOK, expertiseResponse{UserID: claims.UserID, Areas: st.GetExpertise(claims.UserID)})
+	}
+}
+
+// SetMyExpertise replaces the caller's expertise areas.
+func SetMyExpertise(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		setExpertise(w, r, st, claims.UserID)
+	}
+}
+
+// GetMemberExpertise returns a member's expertise areas.
+func GetMemberExpertise(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := orgMember(w, r, st)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, expertiseResponse{UserID: userID, Areas: st.GetExpertise(userID)})
+	}
+}
+
+// SetMemberExpertise replaces a member's expertise areas.
+func SetMemberExpertise(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := orgMember(w, r, st)
+		if !ok {
+			return
+		}
+		setExpertise(w, r, st, userID)
+	}
+}
+
+// SuggestReviewers ranks the available reviewers for a review: members
+// tagged with expertise matching its labels first, then those who approved
+// similarly labeled reviews, then the least loaded. ?limit= caps the list
+// (default 5).
+func SuggestReviewers(st *store.Memory, engine *assign.Engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		limit := 5
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				respondError(w, http.StatusBadRequest, "limit must be a positive integer")
+				return
+			}
+			limit = n
+		}
+		suggestions := []suggestedReviewer{}
+		for _, c := range engine.Suggest(review, time.Now()) {
+			if len(suggestions) == limit {
+				break
+			}
+			suggestions = append(suggestions, suggestedReviewer{
+				UserID:    c.User.ID,
+				Name:      c.User.Name,
+				Skills:    append([]string{}, c.Skills...),
+				Approvals: c.Expertise,
+				Open:      c.Open,
+			})
+		}
+		respondJSON(w, http.StatusOK, suggestions)
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+	"github.com/andres20980/aurea-orchestrator/internal/export"
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// ExportReview renders a review with its checklist, comments and approval
+// record as a Markdown or PDF document for external archiving. PDFs carry
+// the organization's branding, if any.
+func ExportReview(st *store.Memory, blobs blob.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format, err := export.ParseFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+
+		doc := reviewDocument(st, blobs, review, i18n.FromContext(r.Context()), format)
+
+		body := export.Render(doc, format)
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="review-%s.%s"`, review.ID, format))
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}
+}
+
+// reviewDocument gathers what an exported review document shows.
+func reviewDocument(st *store.Memory, blobs blob.Store, review *models.Review, lang string, format export.Format) export.ReviewDocument {
+	doc := export.ReviewDocument{
+		Review:    review,
+		Checklist: st.ListChecklist(review.ID),
+		Comments:  st.ListComments(review.ID),
+		Lang:      lang,
+	}
+	values := st.GetFieldValues(review.ID)
+	for _, f := range st.ListCustomFields(review.OrgID) {
+		if v, ok := values[f.Key]; ok {
+			doc.CustomFields = append(doc.CustomFields, export.Field{Name: f.Name, Value: fmt.Sprint(v)})
+		}
+	}
+	if approval, ok := st.GetApproval(review.ID); ok {
+		doc.Approval = approval
+	}
+	if format == export.FormatPDF {
+		doc.Branding = exportBranding(st, blobs, review.OrgID)
+	}
+	return doc
+}
+
+// exportBranding loads an organization's branding for documents, or nil
+// if it has none. A logo that cannot be read is left out.
+func exportBranding(st *store.Memory, blobs blob.Store, orgID string) *export.Branding {
+	b := st.GetBranding(orgID)
+	if b.Name == "" && b.PrimaryColor == "" && b.LogoKey == "" {
+		return nil
+	}
+	out := &export.Branding{Name: b.Name, Color: b.PrimaryColor}
+	if b.LogoKey != "" {
+		if rc, _, err := blobs.Get(b.LogoKey); err == nil {
+			out.Logo, _ = io.ReadAll(io.LimitReader(rc, maxLogoBytes))
+			rc.Close()
+		}
+	}
+	return out
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+var fieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+var fieldTypes = map[string]bool{
+	models.FieldString: true,
+	models.FieldEnum:   true,
+	models.FieldNumber: true,
+	models.FieldUser:   true,
+}
+
+type fieldRequest struct {
+	Key      string   `json:"key"`
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Options  []string `json:"options"`
+	Required bool     `json:"required"`
+}
+
+func (req fieldRequest) validate(w http.ResponseWriter) bool {
+	if req.Name == "" {
+		respondError(w, http.StatusBadRequest, "name is required")
+		return false
+	}
+	if !fieldTypes[req.Type] {
+		respondError(w, http.StatusBadRequest, "type must be one of string, enum, number, user")
+		return false
+	}
+	if req.Type == models.FieldEnum && len(req.Options) == 0 {
+		respondError(w, http.StatusBadRequest, "enum fields need options")
+		return false
+	}
+	return true
+}
+
+// ListCustomFields returns the organization's custom review fields.
+func ListCustomFields(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListCustomFields(claims.OrgID))
+	}
+}
+
+// CreateCustomField defines a new custom review field.
+func CreateCustomField(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req fieldRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !fieldKeyPattern.MatchString(req.Key) {
+			respondError(w, http.StatusBadRequest, "key must be lowercase letters, digits and underscores")
+			return
+		}
+		if !req.validate(w) {
+			return
+		}
+
+		now := time.Now()
+		field := &models.CustomField{
+			OrgID:     claims.OrgID,
+			Key:       req.Key,
+			Name:      req.Name,
+			Type:      req.Type,
+			Options:   req.Options,
+			Required:  req.Required,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := st.CreateCustomField(field); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, field)
+	}
+}
+
+// UpdateCustomField replaces a custom field definition. Its key is fixed.
+// Existing values are not revalidated.
+func UpdateCustomField(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req fieldRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !req.validate(w) {
+			return
+		}
+
+		field := &models.CustomField{
+			ID:        mux.Vars(r)["fieldId"],
+			OrgID:     claims.OrgID,
+			Name:      req.Name,
+			Type:      req.Type,
+			Options:   req.Options,
+			Required:  req.Required,
+			UpdatedAt: time.Now(),
+		}
+		if err := st.UpdateCustomField(field); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, field)
+	}
+}
+
+// DeleteCustomField removes a custom field and its values from every review.
+func DeleteCustomField(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		if err := st.DeleteCustomField(claims.OrgID, mux.Vars(r)["fieldId"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetReviewFields returns the custom field values of a review.
+func GetReviewFields(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.GetFieldValues(review.ID))
+	}
+}
+
+// SetReviewFields replaces the custom field values of a review.
+func SetReviewFields(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		var values map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		values, msg := checkFieldValues(st, i18n.LangOf(w), review.OrgID, nil, values)
+		if msg != "" {
+			respondJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+		st.SetFieldValues(review.ID, values)
+		respondJSON(w, http.StatusOK, values)
+	}
+}
+
+// ApplyCustomFields wraps the create and update review handlers. It
+// validates the "custom_fields" object of the request against the
+// organization's definitions and stores the values once the wrapped handler
+// succeeds. On update, the given fields are merged into the stored ones and
+// a null value clears a field.
+func ApplyCustomFields(st *store.Memory) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			claims, _ := middleware.GetClaims(r.Context())
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			var req struct {
+				CustomFields map[string]interface{} `json:"custom_fields"`
+			}
+			if err := json.Unmarshal(body, &req); err != nil {
+				next(w, r)
+				return
+			}
+			reviewID := mux.Vars(r)["id"]
+			if reviewID != "" && req.CustomFields == nil {
+				next(w, r)
+				return
+			}
+			var current map[string]interface{}
+			if reviewID != "" {
+				current = st.GetFieldValues(reviewID)
+			}
+			values, msg := checkFieldValues(st, i18n.LangOf(w), claims.OrgID, current, req.CustomFields)
+			if msg != "" {
+				respondJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+				return
+			}
+
+			rec := &bodyRecorder{statusRecorder: statusRecorder{ResponseWriter: w}}
+			next(rec, r)
+			if !rec.succeeded() {
+				return
+			}
+			if reviewID == "" {
+				var created struct {
+					ID string `json:"id"`
+				}
+				if err := json.Unmarshal(rec.body.Bytes(), &created); err != nil || created.ID == "" {
+					return
+				}
+				reviewID = created.ID
+			}
+			st.SetFieldValues(reviewID, values)
+		}
+	}
+}
+
+// checkFieldValues merges updates into current and validates the result.
+// It returns the normalized values, or a translated error message.
+func checkFieldValues(st *store.Memory, lang, orgID string, current, updates map[string]interface{}) (map[string]interface{}, string) {
+	defs := make(map[string]*models.CustomField)
+	for _, f := range st.ListCustomFields(orgID) {
+		defs[f.Key] = f
+	}
+
+	values := make(map[string]interface{}, len(current)+len(updates))
+	for k, v := range current {
+		values[k] = v
+	}
+	for key, v := range updates {
+		f, ok := defs[key]
+		if !ok {
+			return nil, i18n.T(lang, "unknown custom field %q", key)
+		}
+		if v == nil {
+			delete(values, key)
+			continue
+		}
+		if !fieldValueValid(st, orgID, f, v) {
+			return nil, i18n.T(lang, "invalid value for custom field %q", key)
+		}
+		values[key] = v
+	}
+	for key, f := range defs {
+		if _, set := values[key]; f.Required && !set {
+			return nil, i18n.T(lang, "custom field %q is required", key)
+		}
+	}
+	return values, ""
+}
+
+func fieldValueValid(st *store.Memory, orgID string, f *models.CustomField, v interface{}) bool {
+	switch f.Type {
+	case models.FieldNumber:
+		_, ok := v.(float64)
+		return ok
+	case models.FieldString:
+		s, ok := v.(string)
+		return ok && s != ""
+	case models.FieldEnum:
+		s, ok := v.(string)
+		return ok && slices.Contains(f.Options, s)
+	case models.FieldUser:
+		id, ok := v.(string)
+		if !ok {
+			return false
+		}
+		u, err := st.GetUser(id)
+		return err == nil && u.OrgID == orgID
+	}
+	return false
+}
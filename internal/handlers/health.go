@@ -0,0 +1,9 @@
+package handlers
+
+import "net/http"
+
+// Health reports that the server is up. Load balancers poll it, so it is
+// never load-shed and does no work.
+func Health(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+	"github.com/gorilla/mux"
+)
+
+const (
+	maxIntakeTitle   = 200
+	maxIntakeContent = 20000
+)
+
+// ListIntakeForms returns the organization's intake forms.
+func ListIntakeForms(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListIntakeForms(claims.OrgID))
+	}
+}
+
+// CreateIntakeForm creates a public intake form. The link is only returned here.
+func CreateIntakeForm(st *store.Memory, baseURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Name == "" {
+			respondError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		token, err := newToken()
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to generate link")
+			return
+		}
+		form := &models.IntakeForm{
+			OrgID:       claims.OrgID,
+			Name:        req.Name,
+			Description: req.Description,
+			TokenHash:   middleware.HashAPIKey(token),
+			Active:      true,
+			CreatedBy:   claims.UserID,
+			CreatedAt:   time.Now(),
+		}
+		st.CreateIntakeForm(form)
+		respondJSON(w, http.StatusCreated, struct {
+			*models.IntakeForm
+			URL string `json:"url"`
+		}{form, strings.TrimRight(baseURL, "/") + "/intake/" + token})
+	}
+}
+
+// DisableIntakeForm stops a form from accepting submissions.
+func DisableIntakeForm(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		if err := st.DisableIntakeForm(claims.OrgID, mux.Vars(r)["formId"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetIntakeForm describes a public intake form to its submitters.
+func GetIntakeForm(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		form, err := st.FindIntakeForm(middleware.HashAPIKey(mux.Vars(r)["token"]))
+		if err != nil {
+			respondError(w, http.StatusNotFound, "Not found")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]string{"name": form.Name, "description": form.Description})
+	}
+}
+
+// SubmitIntakeForm files a review request through a public intake form.
+// Submissions are rate limited per client IP. A honeypot "website" field,
+// invisible to people, catches naive bots, and when a challenge provider is
+// configured every submission must carry a solved challenge.
+func SubmitIntakeForm(st *store.Memory, limiter *security.RateLimiter, challenger security.Challenger, proxies security.TrustedProxies) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		form, err := st.FindIntakeForm(middleware.HashAPIKey(mux.Vars(r)["token"]))
+		if err != nil {
+			respondError(w, http.StatusNotFound, "Not found")
+			return
+		}
+		ip := security.ClientIP(r, proxies)
+		if ok, until := limiter.Allow(form.ID + "|" + ip); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(until).Seconds())+1))
+			respondError(w, http.StatusTooManyRequests, "Too many submissions; try again later")
+			return
+		}
+
+		var req struct {
+			Name    string `json:"name"`
+			Email   string `json:"email"`
+			Title   string `json:"title"`
+			Content string `json:"content"`
+			Website string `json:"website"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Website != "" {
+			// Pretend success so bots learn nothing.
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		if challenger != nil {
+			passed, err := challenger.Verify(r.Context(), r.Header.Get(CaptchaTokenHeader), ip)
+			if err != nil {
+				log.Printf("Intake challenge verification failed: %v", err)
+			}
+			if !passed {
+				respondError(w, http.StatusForbidden, "Challenge verification failed")
+				return
+			}
+		}
+		if _, err := mail.ParseAddress(req.Email); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid email")
+			return
+		}
+		if req.Title == "" || len(req.Title) > maxIntakeTitle || len(req.Content) > maxIntakeContent {
+			respondError(w, http.StatusBadRequest, "title is required and title and content must not be too long")
+			return
+		}
+
+		st.AddIntakeSubmission(&models.IntakeSubmission{
+			FormID:    form.ID,
+			OrgID:     form.OrgID,
+			Name:      req.Name,
+			Email:     req.Email,
+			Title:     req.Title,
+			Content:   req.Content,
+			IP:        ip,
+			Status:    models.IntakePending,
+			CreatedAt: time.Now(),
+		})
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// ListIntakeSubmissions returns the triage queue, filtered by ?status=
+// (default pending; "all" for every submission).
+func ListIntakeSubmissions(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		status := r.URL.Query().Get("status")
+		switch status {
+		case "":
+			status = models.IntakePending
+		case "all":
+			status = ""
+		}
+		respondJSON(w, http.StatusOK, st.ListIntakeSubmissions(claims.OrgID, status))
+	}
+}
+
+// AcceptIntakeSubmission turns a submission into a review authored by the
+// admin who accepted it, crediting the submitter in the content.
+func AcceptIntakeSubmission(st *store.Memory, reviews aurea.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		sub, err := st.GetIntakeSubmission(claims.OrgID, mux.Vars(r)["submissionId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if sub.Status != models.IntakePending {
+			respondError(w, http.StatusConflict, "Submission was already triaged")
+			return
+		}
+
+		content := fmt.Sprintf("Submitted by %s <%s> via intake form.\n\n%s", sub.Name, sub.Email, sub.Content)
+		review, err := reviews.Create(r.Context(), aurea.Actor{UserID: claims.UserID, OrgID: claims.OrgID}, sub.Title, content)
+		if err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		sub, err = st.TriageIntakeSubmission(claims.OrgID, sub.ID, models.IntakeAccepted, review.ID, "", claims.UserID, time.Now())
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, sub)
+	}
+}
+
+// RejectIntakeSubmission discards a submission, e.g. as spam.
+func RejectIntakeSubmission(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+		}
+		sub, err := st.TriageIntakeSubmission(claims.OrgID, mux.Vars(r)["submissionId"], models.IntakeRejected, "", req.Reason, claims.UserID, time.Now())
+		if err != nil {
+			if err == store.ErrDuplicate {
+				respondError(w, http.StatusConflict, "Submission was already triaged")
+				return
+			}
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, sub)
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/integrations/jira"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+const maxWebhookBytes = 1 << 20
+
+// GetJiraConfig returns the organization's Jira connector configuration
+// with secrets redacted.
+func GetJiraConfig(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		cfg, ok := st.GetJiraConfig(claims.OrgID)
+		if !ok {
+			respondError(w, http.StatusNotFound, "Jira integration is not configured")
+			return
+		}
+		respondJSON(w, http.StatusOK, cfg.Redacted())
+	}
+}
+
+// UpdateJiraConfig replaces the organization's Jira connector
+// configuration. Omitted secrets keep their current values.
+func UpdateJiraConfig(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+
+		var cfg models.JiraConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		cfg.OrgID = claims.OrgID
+
+		if current, ok := st.GetJiraConfig(claims.OrgID); ok {
+			if cfg.APIToken == "" {
+				cfg.APIToken = current.APIToken
+			}
+			if cfg.WebhookSecret == "" {
+				cfg.WebhookSecret = current.WebhookSecret
+			}
+		}
+		if cfg.Enabled && (cfg.BaseURL == "" || cfg.Email == "" || cfg.APIToken == "" || cfg.ProjectKey == "") {
+			respondError(w, http.StatusBadRequest, "base_url, email, api_token and project_key are required to enable Jira sync")
+			return
+		}
+
+		st.SetJiraConfig(cfg)
+		respondJSON(w, http.StatusOK, cfg.Redacted())
+	}
+}
+
+// JiraWebhook receives issue events from Jira and mirrors status changes
+// onto linked reviews. Requests must carry a valid X-Hub-Signature.
+func JiraWebhook(st *store.Memory, connector *jira.Connector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orgID := mux.Vars(r)["orgId"]
+		cfg, ok := st.GetJiraConfig(orgID)
+		if !ok {
+			respondError(w, http.StatusNotFound, "Not found")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBytes))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Failed to read body")
+			return
+		}
+		if err := jira.VerifySignature(cfg.WebhookSecret, body, r.Header.Get("X-Hub-Signature")); err != nil {
+			respondError(w, http.StatusUnauthorized, "Invalid signature")
+			return
+		}
+
+		var ev jira.WebhookEvent
+		if err := json.Unmarshal(body, &ev); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if err := connector.ApplyWebhook(orgID, ev); err != nil {
+			log.Printf("Failed to apply Jira webhook for org %s: %v", orgID, err)
+			respondError(w, http.StatusInternalServerError, "Failed to apply webhook")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// engageKillSwitch reads {"reason": "..."} and disables the capability in
+// the URL for orgID (instance-wide when empty).
+func engageKillSwitch(w http.ResponseWriter, r *http.Request, st *store.Memory, orgID string) {
+	claims, _ := middleware.GetClaims(r.Context())
+	capability := mux.Vars(r)["capability"]
+	if !slices.Contains(models.Capabilities, capability) {
+		respondErrorf(w, http.StatusBadRequest, "Unknown capability %q", capability)
+		return
+	}
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	k := models.KillSwitch{
+		Capability: capability,
+		OrgID:      orgID,
+		Reason:     req.Reason,
+		DisabledBy: claims.UserID,
+		DisabledAt: time.Now(),
+	}
+	st.SetKillSwitch(k)
+	respondJSON(w, http.StatusOK, k)
+}
+
+// ListOrgKillSwitches returns the kill switches affecting the organization,
+// including instance-wide ones.
+func ListOrgKillSwitches(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListKillSwitches(claims.OrgID))
+	}
+}
+
+// SetOrgKillSwitch disables a capability for the organization.
+func SetOrgKillSwitch(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		engageKillSwitch(w, r, st, claims.OrgID)
+	}
+}
+
+// DeleteOrgKillSwitch re-enables a capability for the organization.
+// Instance-wide switches can only be lifted by operators.
+func DeleteOrgKillSwitch(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		if err := st.DeleteKillSwitch(mux.Vars(r)["capability"], claims.OrgID); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// AdminListKillSwitches returns every engaged kill switch, or with
+// ?org_id= those affecting one organization.
+func AdminListKillSwitches(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, st.ListKillSwitches(r.URL.Query().Get("org_id")))
+	}
+}
+
+// AdminSetKillSwitch disables a capability instance-wide, or for the
+// organization in ?org_id=.
+func AdminSetKillSwitch(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		engageKillSwitch(w, r, st, r.URL.Query().Get("org_id"))
+	}
+}
+
+// AdminDeleteKillSwitch re-enables a capability instance-wide, or for the
+// organization in ?org_id=.
+func AdminDeleteKillSwitch(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := st.DeleteKillSwitch(mux.Vars(r)["capability"], r.URL.Query().Get("org_id")); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// AdminListLegalHolds returns the legal holds, released ones included,
+// newest first, optionally filtered by ?org_id=.
+func AdminListLegalHolds(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, st.ListLegalHolds(r.URL.Query().Get("org_id")))
+	}
+}
+
+// AdminGetLegalHold returns a legal hold.
+func AdminGetLegalHold(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h, err := st.GetLegalHold(mux.Vars(r)["holdId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, h)
+	}
+}
+
+// AdminPlaceLegalHold reads {"org_id": "...", "review_id": "...", "reason":
+// "..."} and places the organization, or only the review when review_id is
+// given, under a legal hold.
+func AdminPlaceLegalHold(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		var req struct {
+			OrgID    string `json:"org_id"`
+			ReviewID string `json:"review_id"`
+			Reason   string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.OrgID == "" {
+			respondError(w, http.StatusBadRequest, "org_id is required")
+			return
+		}
+		if strings.TrimSpace(req.Reason) == "" {
+			respondError(w, http.StatusBadRequest, "reason is required")
+			return
+		}
+		h, err := st.PlaceLegalHold(models.LegalHold{
+			OrgID:    req.OrgID,
+			ReviewID: req.ReviewID,
+			Reason:   strings.TrimSpace(req.Reason),
+			PlacedBy: claims.UserID,
+			PlacedAt: time.Now(),
+		})
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		auditLegalHold(st, claims.UserID, "legal_hold.placed", h.Reason, h)
+		respondJSON(w, http.StatusCreated, h)
+	}
+}
+
+// AdminReleaseLegalHold reads {"reason": "..."} and releases a legal hold
+// in effect.
+func AdminReleaseLegalHold(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if strings.TrimSpace(req.Reason) == "" {
+			respondError(w, http.StatusBadRequest, "reason is required")
+			return
+		}
+		h, err := st.ReleaseLegalHold(mux.Vars(r)["holdId"], claims.UserID, strings.TrimSpace(req.Reason), time.Now())
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		auditLegalHold(st, claims.UserID, "legal_hold.released", h.ReleaseReason, h)
+		respondJSON(w, http.StatusOK, h)
+	}
+}
+
+func auditLegalHold(st *store.Memory, actorID, action, reason string, h models.LegalHold) {
+	targetType, targetID := "org", h.OrgID
+	if h.ReviewID != "" {
+		targetType, targetID = "review", h.ReviewID
+	}
+	st.AppendAudit(&models.AuditEvent{
+		OrgID:      h.OrgID,
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     reason,
+		Metadata:   map[string]string{"hold_id": h.ID},
+	})
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/integrations/jira"
+	"github.com/andres20980/aurea-orchestrator/internal/links"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// ListReviewLinks returns the external links of a review.
+func ListReviewLinks(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListLinks(review.ID))
+	}
+}
+
+// AddReviewLink links a review to an external issue. The provider and issue
+// key are detected from the URL; Jira statuses are fetched immediately when
+// a Jira client is configured.
+func AddReviewLink(st *store.Memory, jc *jira.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+
+		var req struct {
+			URL string `json:"url"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		provider, key, err := links.Detect(req.URL)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+
+		link := &models.ExternalLink{
+			ReviewID:  review.ID,
+			OrgID:     review.OrgID,
+			URL:       req.URL,
+			Provider:  provider,
+			Key:       key,
+			CreatedBy: claims.UserID,
+			CreatedAt: time.Now(),
+		}
+		st.AddLink(link)
+
+		if provider == models.ProviderJira && jc != nil {
+			if status, err := jc.IssueStatus(r.Context(), key); err != nil {
+				log.Printf("Failed to fetch Jira status for %s: %v", key, err)
+			} else {
+				st.UpdateLinkStatus(link.ID, status, time.Now())
+			}
+		}
+		respondJSON(w, http.StatusCreated, link)
+	}
+}
+
+// RemoveReviewLink deletes an external link from a review.
+func RemoveReviewLink(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		link, err := st.GetLink(review.ID, mux.Vars(r)["linkId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.RemoveLink(link.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// SyncReviewLink refreshes the status of a Jira link from the Jira API.
+func SyncReviewLink(st *store.Memory, jc *jira.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		link, err := st.GetLink(review.ID, mux.Vars(r)["linkId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if link.Provider != models.ProviderJira {
+			respondError(w, http.StatusBadRequest, "Status sync is only supported for Jira links")
+			return
+		}
+		if jc == nil {
+			respondError(w, http.StatusServiceUnavailable, "Jira integration is not configured")
+			return
+		}
+
+		status, err := jc.IssueStatus(r.Context(), link.Key)
+		if err != nil {
+			log.Printf("Failed to fetch Jira status for %s: %v", link.Key, err)
+			respondError(w, http.StatusBadGateway, "Failed to fetch status from Jira")
+			return
+		}
+		st.UpdateLinkStatus(link.ID, status, time.Now())
+		respondJSON(w, http.StatusOK, link)
+	}
+}
+
+// LinkedReview pairs an external link with the review it belongs to.
+type LinkedReview struct {
+	Link   *models.ExternalLink `json:"link"`
+	Review *models.Review       `json:"review"`
+}
+
+// FindLinkedReviews returns the caller's organization's reviews linked to
+// the external key given in ?key=.
+func FindLinkedReviews(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+
+		key := links.NormalizeKey(r.URL.Query().Get("key"))
+		if key == "" {
+			respondError(w, http.StatusBadRequest, "key query parameter is required")
+			return
+		}
+
+		results := []LinkedReview{}
+		for _, link := range st.FindLinksByKey(claims.OrgID, key) {
+			if review, err := st.GetReview(link.ReviewID); err == nil {
+				results = append(results, LinkedReview{Link: link, Review: review})
+			}
+		}
+		respondJSON(w, http.StatusOK, results)
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/logging"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/gorilla/mux"
+)
+
+// AdminGetLogging returns the log level and active debug overrides.
+func AdminGetLogging(c *logging.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, c.Status())
+	}
+}
+
+// AdminSetLogLevel changes the log level, optionally for a duration after
+// which it reverts to the startup level.
+func AdminSetLogLevel(c *logging.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Level    string `json:"level"`
+			Duration string `json:"duration"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		level, err := logging.ParseLevel(req.Level)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "level must be debug, info, warn or error")
+			return
+		}
+		var d time.Duration
+		if req.Duration != "" {
+			if d, err = time.ParseDuration(req.Duration); err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid duration")
+				return
+			}
+		}
+		if err := c.SetLevel(level, d); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusOK, c.Status())
+	}
+}
+
+// AdminAddLogOverride enables debug (or another level) logging for the
+// requests of a user, org and/or path prefix until the override expires.
+func AdminAddLogOverride(c *logging.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		var req struct {
+			UserID     string `json:"user_id"`
+			OrgID      string `json:"org_id"`
+			PathPrefix string `json:"path_prefix"`
+			Level      string `json:"level"`
+			Duration   string `json:"duration"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Level == "" {
+			req.Level = "debug"
+		}
+		level, err := logging.ParseLevel(req.Level)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "level must be debug, info, warn or error")
+			return
+		}
+		d := 15 * time.Minute
+		if req.Duration != "" {
+			if d, err = time.ParseDuration(req.Duration); err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid duration")
+				return
+			}
+		}
+		o := &logging.Override{
+			UserID:     req.UserID,
+			OrgID:      req.OrgID,
+			PathPrefix: req.PathPrefix,
+			Level:      level,
+			CreatedBy:  claims.UserID,
+		}
+		if err := c.AddOverride(o, d); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusCreated, o)
+	}
+}
+
+// AdminRemoveLogOverride ends an override early.
+func AdminRemoveLogOverride(c *logging.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !c.RemoveOverride(mux.Vars(r)["overrideId"]) {
+			respondError(w, http.StatusNotFound, "Not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
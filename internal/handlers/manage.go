@@ -0,0 +1,596 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/ulid"
+	"github.com/andres20980/aurea-orchestrator/pkg/reqsign"
+	"github.com/gorilla/mux"
+)
+
+// The management endpoints are designed for declarative tools such as a
+// Terraform provider: every resource has a stable server-assigned ID and an
+// optional caller-chosen external_id, PUT fully replaces a resource, DELETE
+// of a missing resource returns 404, and list endpoints accept
+// ?external_id= so existing resources can be imported.
+
+// ManageCreateOrg creates an organization. An optional region pins its
+// data to one of regions; an empty list means no regions are configured.
+func ManageCreateOrg(st *store.Memory, regions []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		var req models.ManagedOrg
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Name == "" {
+			respondError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		if req.ID == "" {
+			id, err := ulid.New()
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, "Failed to generate ID")
+				return
+			}
+			req.ID = "org-" + id
+		}
+
+		if req.Region != "" && !slices.Contains(regions, req.Region) {
+			respondError(w, http.StatusBadRequest, "region is not configured on this instance")
+			return
+		}
+		if req.ParentID != "" {
+			if _, err := st.GetOrg(req.ParentID); err != nil {
+				respondError(w, http.StatusBadRequest, "parent_id does not name an organization")
+				return
+			}
+		}
+
+		org := &models.Organization{ID: req.ID, Name: req.Name, CreatedBy: claims.UserID, CreatedAt: time.Now()}
+		if err := st.CreateOrg(org, req.ExternalID, req.Region); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if req.ParentID != "" {
+			if _, err := st.SetOrgParent(org.ID, req.ParentID); err != nil {
+				respondStoreError(w, err)
+				return
+			}
+		}
+		managed, _ := st.GetManagedOrg(org.ID)
+		respondJSON(w, http.StatusCreated, managed)
+	}
+}
+
+// ManageListOrgs returns every organization ordered by ?sort=, or only
+// the one matching ?external_id=.
+func ManageListOrgs(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ext := r.URL.Query().Get("external_id"); ext != "" {
+			org, err := st.FindOrgByExternalID(ext)
+			if err != nil {
+				respondJSON(w, http.StatusOK, []*models.ManagedOrg{})
+				return
+			}
+			respondJSON(w, http.StatusOK, []*models.ManagedOrg{org})
+			return
+		}
+		order, ok := parseSort(w, r)
+		if !ok {
+			return
+		}
+		summaries := st.ListOrgSummaries()
+		store.SortOrgSummaries(summaries, order)
+		orgs := []*models.ManagedOrg{}
+		for _, summary := range summaries {
+			if org, err := st.GetManagedOrg(summary.ID); err == nil {
+				orgs = append(orgs, org)
+			}
+		}
+		respondJSON(w, http.StatusOK, orgs)
+	}
+}
+
+// ManageGetOrg returns an organization.
+func ManageGetOrg(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		org, err := st.GetManagedOrg(mux.Vars(r)["id"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, org)
+	}
+}
+
+// ManageUpdateOrg replaces an organization's name and external ID.
+func ManageUpdateOrg(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.ManagedOrg
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Name == "" {
+			respondError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		current, err := st.GetManagedOrg(mux.Vars(r)["id"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if req.Region != "" && req.Region != current.Region {
+			respondError(w, http.StatusBadRequest, "region cannot be changed after creation")
+			return
+		}
+		org, err := st.UpdateOrg(current.ID, req.Name, req.ExternalID)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, org)
+	}
+}
+
+// ManageDeleteOrg deletes an organization and its managed resources.
+func ManageDeleteOrg(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := st.DeleteOrg(mux.Vars(r)["id"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+type roleRequest struct {
+	ExternalID  string `json:"external_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	BaseRole    string `json:"base_role"`
+}
+
+func (req roleRequest) validate(w http.ResponseWriter) bool {
+	if req.Name == "" {
+		respondError(w, http.StatusBadRequest, "name is required")
+		return false
+	}
+	if !memberRoles[req.BaseRole] {
+		respondErrorf(w, http.StatusBadRequest, "invalid role %q", req.BaseRole)
+		return false
+	}
+	return true
+}
+
+// ListRoles returns the organization's roles, optionally filtered by ?external_id=.
+func ListRoles(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		ext := r.URL.Query().Get("external_id")
+		roles := []*models.Role{}
+		for _, role := range st.ListRoles(claims.OrgID) {
+			if ext == "" || role.ExternalID == ext {
+				roles = append(roles, role)
+			}
+		}
+		respondJSON(w, http.StatusOK, roles)
+	}
+}
+
+// CreateRole defines a new role in the organization.
+func CreateRole(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req roleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !req.validate(w) {
+			return
+		}
+
+		now := time.Now()
+		role := &models.Role{
+			OrgID:       claims.OrgID,
+			ExternalID:  req.ExternalID,
+			Name:        req.Name,
+			Description: req.Description,
+			BaseRole:    req.BaseRole,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := st.CreateRole(role); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, role)
+	}
+}
+
+// GetRole returns a role.
+func GetRole(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		role, err := st.GetRole(claims.OrgID, mux.Vars(r)["roleId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, role)
+	}
+}
+
+// UpdateRole replaces a role.
+func UpdateRole(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req roleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !req.validate(w) {
+			return
+		}
+
+		role := &models.Role{
+			ID:          mux.Vars(r)["roleId"],
+			OrgID:       claims.OrgID,
+			ExternalID:  req.ExternalID,
+			Name:        req.Name,
+			Description: req.Description,
+			BaseRole:    req.BaseRole,
+			UpdatedAt:   time.Now(),
+		}
+		if err := st.UpdateRole(role); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, role)
+	}
+}
+
+// DeleteRole removes a role.
+func DeleteRole(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		if err := st.DeleteRole(claims.OrgID, mux.Vars(r)["roleId"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+type webhookRequest struct {
+	ExternalID string   `json:"external_id"`
+	URL        string   `json:"url"`
+	Events     []string `json:"events"`
+	Secret     string   `json:"secret"`
+	Active     *bool    `json:"active"`
+}
+
+func (req webhookRequest) validate(w http.ResponseWriter) bool {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		respondError(w, http.StatusBadRequest, "url must be an absolute http(s) URL")
+		return false
+	}
+	for _, e := range req.Events {
+		if !knownEvent(e) {
+			respondErrorf(w, http.StatusBadRequest, "unknown event type %q", e)
+			return false
+		}
+	}
+	return true
+}
+
+func (req webhookRequest) webhook(orgID string) *models.Webhook {
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	events := req.Events
+	if events == nil {
+		events = []string{}
+	}
+	return &models.Webhook{
+		OrgID:      orgID,
+		ExternalID: req.ExternalID,
+		URL:        req.URL,
+		Events:     events,
+		Secret:     req.Secret,
+		Active:     active,
+		UpdatedAt:  time.Now(),
+	}
+}
+
+func knownEvent(eventType string) bool {
+	for _, t := range notify.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// ListWebhooks returns the organization's webhooks, optionally filtered by ?external_id=.
+func ListWebhooks(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		ext := r.URL.Query().Get("external_id")
+		hooks := []models.Webhook{}
+		for _, hook := range st.ListWebhooks(claims.OrgID) {
+			if ext == "" || hook.ExternalID == ext {
+				hooks = append(hooks, hook.Redacted())
+			}
+		}
+		respondJSON(w, http.StatusOK, hooks)
+	}
+}
+
+// CreateWebhook registers a webhook. The signing secret is generated if
+// not supplied and is returned only in this response.
+func CreateWebhook(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req webhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !req.validate(w) {
+			return
+		}
+
+		hook := req.webhook(claims.OrgID)
+		if hook.Secret == "" {
+			secret, err := newToken()
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, "Failed to generate secret")
+				return
+			}
+			hook.Secret = secret
+		}
+		hook.CreatedAt = hook.UpdatedAt
+		if err := st.CreateWebhook(hook); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, hook)
+	}
+}
+
+// GetWebhook returns a webhook without its secret.
+func GetWebhook(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		hook, err := st.GetWebhook(claims.OrgID, mux.Vars(r)["webhookId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, hook.Redacted())
+	}
+}
+
+// UpdateWebhook replaces a webhook. An omitted secret keeps the current one.
+func UpdateWebhook(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req webhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !req.validate(w) {
+			return
+		}
+
+		hook := req.webhook(claims.OrgID)
+		hook.ID = mux.Vars(r)["webhookId"]
+		if err := st.UpdateWebhook(hook); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, hook.Redacted())
+	}
+}
+
+// DeleteWebhook removes a webhook.
+func DeleteWebhook(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		if err := st.DeleteWebhook(claims.OrgID, mux.Vars(r)["webhookId"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ListAPIKeys returns the organization's API keys, optionally filtered by ?external_id=.
+func ListAPIKeys(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		ext := r.URL.Query().Get("external_id")
+		keys := []*models.APIKey{}
+		for _, key := range st.ListAPIKeys(claims.OrgID) {
+			if ext == "" || key.ExternalID == ext {
+				keys = append(keys, key)
+			}
+		}
+		respondJSON(w, http.StatusOK, keys)
+	}
+}
+
+// CreateAPIKey issues an API key. The key and the secret that signs
+// requests with it are returned only in this response.
+func CreateAPIKey(st *store.Memory, serverSecret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			ExternalID string `json:"external_id"`
+			Name       string `json:"name"`
+			Role       string `json:"role"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Name == "" {
+			respondError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		if !memberRoles[req.Role] {
+			respondErrorf(w, http.StatusBadRequest, "invalid role %q", req.Role)
+			return
+		}
+
+		token, err := newToken()
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to generate key")
+			return
+		}
+		secret := middleware.APIKeyPrefix + token
+		key := &models.APIKey{
+			OrgID:      claims.OrgID,
+			ExternalID: req.ExternalID,
+			Name:       req.Name,
+			Role:       req.Role,
+			Prefix:     secret[:len(middleware.APIKeyPrefix)+8],
+			Hash:       middleware.HashAPIKey(secret),
+			CreatedBy:  claims.UserID,
+			CreatedAt:  time.Now(),
+		}
+		if err := st.CreateAPIKey(key); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, struct {
+			*models.APIKey
+			Key           string `json:"key"`
+			SigningSecret string `json:"signing_secret"`
+		}{key, secret, reqsign.SigningSecret(serverSecret, key.Hash)})
+	}
+}
+
+// GetAPIKey returns an API key's metadata.
+func GetAPIKey(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		key, err := st.GetAPIKey(claims.OrgID, mux.Vars(r)["keyId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, key)
+	}
+}
+
+// UpdateAPIKey renames an API key. Role and key material are immutable.
+func UpdateAPIKey(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			Name string `json:"name"`
+			Role string `json:"role"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Name == "" {
+			respondError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+
+		id := mux.Vars(r)["keyId"]
+		if current, err := st.GetAPIKey(claims.OrgID, id); err == nil && req.Role != "" && req.Role != current.Role {
+			respondError(w, http.StatusConflict, "API key role cannot be changed; create a new key")
+			return
+		}
+		key, err := st.RenameAPIKey(claims.OrgID, id, req.Name)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, key)
+	}
+}
+
+// DeleteAPIKey revokes an API key.
+func DeleteAPIKey(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		if err := st.DeleteAPIKey(claims.OrgID, mux.Vars(r)["keyId"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "api_key.revoked",
+			TargetType: "api_key",
+			TargetID:   mux.Vars(r)["keyId"],
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/markdown"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// maxMarkdownPreview bounds the text the preview endpoint renders.
+const maxMarkdownPreview = 1 << 20
+
+// markdownFields are the JSON keys holding Markdown, rendered into the
+// same key with an _html suffix.
+var markdownFields = []string{"content", "body"}
+
+// markdownOptions links @mentions to members of the organization.
+func markdownOptions(st *store.Memory, orgID, baseURL string) markdown.Options {
+	return markdown.Options{Mention: markdown.MentionUsers(st.ListOrgUsers(orgID), baseURL)}
+}
+
+// addRenderedHTML adds the rendered HTML of every Markdown field in v, at
+// any depth.
+func addRenderedHTML(v interface{}, opts markdown.Options) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, key := range markdownFields {
+			if s, ok := v[key].(string); ok {
+				v[key+"_html"] = markdown.Render(s, opts)
+			}
+		}
+		for _, child := range v {
+			addRenderedHTML(child, opts)
+		}
+	case []interface{}:
+		for _, child := range v {
+			addRenderedHTML(child, opts)
+		}
+	}
+}
+
+// RenderMarkdown adds sanitized HTML renderings of review contents and
+// comment bodies to the wrapped handler's response when ?render=html is
+// set, as content_html and body_html next to the Markdown.
+func RenderMarkdown(st *store.Memory, baseURL string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("render") {
+			case "":
+				next(w, r)
+				return
+			case "html":
+			default:
+				respondError(w, http.StatusBadRequest, "render must be html")
+				return
+			}
+			claims, _ := middleware.GetClaims(r.Context())
+			buf := &bufferedResponse{ResponseWriter: w}
+			next(buf, r)
+			if buf.status >= 300 {
+				buf.flush(buf.body.Bytes())
+				return
+			}
+			var v interface{}
+			dec := json.NewDecoder(bytes.NewReader(buf.body.Bytes()))
+			dec.UseNumber()
+			if err := dec.Decode(&v); err != nil {
+				buf.flush(buf.body.Bytes())
+				return
+			}
+			addRenderedHTML(v, markdownOptions(st, claims.OrgID, baseURL))
+			body, err := json.Marshal(v)
+			if err != nil {
+				buf.flush(buf.body.Bytes())
+				return
+			}
+			buf.flush(append(body, '\n'))
+		}
+	}
+}
+
+// PreviewMarkdown renders {"text": "..."} as it would appear once saved,
+// for editors to preview.
+func PreviewMarkdown(st *store.Memory, baseURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		var req struct {
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxMarkdownPreview)).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]string{"html": markdown.Render(req.Text, markdownOptions(st, claims.OrgID, baseURL))})
+	}
+}
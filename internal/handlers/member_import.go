@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	mailer "github.com/andres20980/aurea-orchestrator/internal/mail"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/operations"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+const (
+	maxImportRows = 1000
+	inviteTTL     = 7 * 24 * time.Hour
+)
+
+// memberRoles are the org-scoped roles that can be assigned on import.
+var memberRoles = map[string]bool{"admin": true, "reviewer": true, "member": true, middleware.GuestRole: true}
+
+type importRow struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+}
+
+// ImportRowResult reports the outcome of a single import row.
+type ImportRowResult struct {
+	Row    int    `json:"row"`
+	Email  string `json:"email"`
+	Status string `json:"status"`
+	UserID string `json:"user_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportReport summarizes a batch member import.
+type ImportReport struct {
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// ImportMembers creates accounts in bulk from a CSV (email,name,role) or
+// JSON array body, sends each new user an invite email, and returns a
+// per-row report. Invalid rows are reported and skipped; they do not abort
+// the batch. With Prefer: respond-async the import runs as an operation
+// and the request is answered with 202 right away; the report is the
+// operation's result.
+func ImportMembers(st *store.Memory, m mailer.Mailer, baseURL string, runner *operations.Runner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+
+		rows, err := parseImport(r)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if len(rows) > maxImportRows {
+			respondErrorf(w, http.StatusRequestEntityTooLarge, "at most %d rows per import", maxImportRows)
+			return
+		}
+
+		lang := i18n.FromContext(r.Context())
+		if prefersAsync(r) {
+			op := runner.Start(models.Operation{
+				OrgID:     claims.OrgID,
+				Kind:      "members.import",
+				CreatedBy: claims.UserID,
+			}, func(ctx context.Context, t *operations.Task) (interface{}, error) {
+				return importMembers(ctx, st, m, baseURL, claims, lang, rows, t.Progress)
+			})
+			respondOperation(w, op)
+			return
+		}
+		report, _ := importMembers(context.Background(), st, m, baseURL, claims, lang, rows, nil)
+		respondJSON(w, http.StatusOK, report)
+	}
+}
+
+// importMembers imports rows in order, telling progress, if set, how many
+// are done. It stops between rows once ctx is cancelled.
+func importMembers(ctx context.Context, st *store.Memory, m mailer.Mailer, baseURL string, claims *auth.Claims, lang string, rows []importRow, progress func(done, total int)) (ImportReport, error) {
+	report := ImportReport{Rows: make([]ImportRowResult, 0, len(rows))}
+	for i, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		result := importMember(st, m, baseURL, claims, lang, row)
+		result.Row = i + 1
+		if result.Status == "created" {
+			report.Created++
+		} else {
+			report.Failed++
+		}
+		report.Rows = append(report.Rows, result)
+		if progress != nil {
+			progress(i+1, len(rows))
+		}
+	}
+	return report, nil
+}
+
+func importMember(st *store.Memory, m mailer.Mailer, baseURL string, claims *auth.Claims, lang string, row importRow) ImportRowResult {
+	email := strings.TrimSpace(row.Email)
+	result := ImportRowResult{Email: email, Status: "error"}
+
+	role := strings.ToLower(strings.TrimSpace(row.Role))
+	if role == "" {
+		role = "member"
+	}
+	if _, err := mail.ParseAddress(email); err != nil || !strings.Contains(email, "@") {
+		result.Error = i18n.T(lang, "invalid email")
+		return result
+	}
+	if !memberRoles[role] {
+		result.Error = i18n.T(lang, "invalid role %q", row.Role)
+		return result
+	}
+
+	user := &models.User{
+		Email:     email,
+		Name:      strings.TrimSpace(row.Name),
+		Role:      role,
+		OrgID:     claims.OrgID,
+		CreatedAt: time.Now(),
+	}
+	if err := st.CreateUser(user); err != nil {
+		if errors.Is(err, store.ErrDuplicate) {
+			result.Error = i18n.T(lang, "email already registered")
+		} else {
+			result.Error = err.Error()
+		}
+		return result
+	}
+	result.UserID = user.ID
+
+	token, err := newToken()
+	if err != nil {
+		result.Error = i18n.T(lang, "account created but invite could not be generated")
+		return result
+	}
+	st.SaveInvite(&models.Invite{
+		Token:     token,
+		UserID:    user.ID,
+		OrgID:     claims.OrgID,
+		Email:     email,
+		InvitedBy: claims.UserID,
+		ExpiresAt: time.Now().Add(inviteTTL),
+	})
+
+	err = m.Send(mailer.Message{
+		To:      email,
+		Subject: i18n.T(lang, "You have been invited to Aurea Orchestrator"),
+		Body:    i18n.T(lang, "You have been added as %s. Accept your invite: %s/invites/%s", role, strings.TrimRight(baseURL, "/"), token),
+	})
+	if err != nil {
+		result.Error = i18n.T(lang, "account created but invite email failed: %v", err)
+		return result
+	}
+
+	result.Status = "created"
+	return result
+}
+
+func parseImport(r *http.Request) ([]importRow, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var rows []importRow
+		if err := json.NewDecoder(r.Body).Decode(&rows); err != nil {
+			return nil, errors.New("invalid JSON body: expected an array of {email, name, role}")
+		}
+		return rows, nil
+	}
+	return parseImportCSV(r.Body)
+}
+
+func parseImportCSV(body io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("CSV body must start with a header row")
+	}
+	cols := make(map[string]int)
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := cols["email"]; !ok {
+		return nil, errors.New(`CSV header must include an "email" column`)
+	}
+
+	field := func(rec []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(rec) {
+			return rec[i]
+		}
+		return ""
+	}
+
+	var rows []importRow
+	for {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		rows = append(rows, importRow{
+			Email: field(rec, "email"),
+			Name:  field(rec, "name"),
+			Role:  field(rec, "role"),
+		})
+	}
+	return rows, nil
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/moderation"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+const (
+	maxBannedTerms   = 500
+	maxBannedTermLen = 100
+)
+
+// moderatedKeys are the request body keys whose text the banned-terms
+// filter checks, besides the string values of custom_fields.
+var moderatedKeys = []string{"title", "content", "body"}
+
+// GetModerationPolicy returns the organization's banned terms.
+func GetModerationPolicy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.GetModerationPolicy(claims.OrgID))
+	}
+}
+
+// SetModerationPolicy replaces the organization's banned terms and what
+// happens to content containing them: reject (the default) or mask.
+func SetModerationPolicy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			BannedTerms []string `json:"banned_terms"`
+			Action      string   `json:"action"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Action == "" {
+			req.Action = models.ModerationReject
+		}
+		if req.Action != models.ModerationReject && req.Action != models.ModerationMask {
+			respondError(w, http.StatusBadRequest, "action must be reject or mask")
+			return
+		}
+		if len(req.BannedTerms) > maxBannedTerms {
+			respondErrorf(w, http.StatusBadRequest, "At most %d banned terms are allowed", maxBannedTerms)
+			return
+		}
+		terms := make([]string, 0, len(req.BannedTerms))
+		for _, t := range req.BannedTerms {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if t == "" || len(t) > maxBannedTermLen {
+				respondErrorf(w, http.StatusBadRequest, "Banned terms must be 1 to %d characters", maxBannedTermLen)
+				return
+			}
+			if !slices.Contains(terms, t) {
+				terms = append(terms, t)
+			}
+		}
+		policy := models.ModerationPolicy{
+			OrgID:       claims.OrgID,
+			BannedTerms: terms,
+			Action:      req.Action,
+			UpdatedBy:   claims.UserID,
+			UpdatedAt:   time.Now(),
+		}
+		st.SetModerationPolicy(policy)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "moderation.updated",
+			TargetType: "org",
+			TargetID:   claims.OrgID,
+		})
+		respondJSON(w, http.StatusOK, policy)
+	}
+}
+
+// FilterBannedTerms applies the organization's banned-terms filter to the
+// title, content, body and custom field values of a request: content with a
+// banned term is rejected with 422, or has the terms masked, depending on
+// the organization's policy.
+func FilterBannedTerms(st *store.Memory) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			claims, _ := middleware.GetClaims(r.Context())
+			policy := st.GetModerationPolicy(claims.OrgID)
+			if len(policy.BannedTerms) == 0 {
+				next(w, r)
+				return
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			var req map[string]interface{}
+			dec := json.NewDecoder(bytes.NewReader(body))
+			dec.UseNumber()
+			if err := dec.Decode(&req); err != nil {
+				next(w, r)
+				return
+			}
+
+			filter := moderation.New(policy)
+			var found []string
+			masked := false
+			check := func(values map[string]interface{}, key string) {
+				s, ok := values[key].(string)
+				if !ok {
+					return
+				}
+				for _, t := range filter.Find(s) {
+					if !slices.Contains(found, t) {
+						found = append(found, t)
+					}
+				}
+				if policy.Action == models.ModerationMask {
+					if m := filter.Mask(s); m != s {
+						values[key], masked = m, true
+					}
+				}
+			}
+			for _, key := range moderatedKeys {
+				check(req, key)
+			}
+			if fields, ok := req["custom_fields"].(map[string]interface{}); ok {
+				for key := range fields {
+					check(fields, key)
+				}
+			}
+
+			if len(found) > 0 && policy.Action == models.ModerationReject {
+				respondErrorf(w, http.StatusUnprocessableEntity, "Content contains banned terms: %s", strings.Join(found, ", "))
+				return
+			}
+			if masked {
+				body, _ = json.Marshal(req)
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				r.ContentLength = int64(len(body))
+			}
+			next(w, r)
+		}
+	}
+}
+
+// RedactContent reads {"comment_id": "...", "text": "...", "reason": "..."}
+// or {"field": "title|content|fields.<key>", ...} and replaces the text in
+// the comment or review field with a redaction marker, or all of it when
+// text is empty. A tombstone records who redacted what and why, but not the
+// redacted text.
+func RedactContent(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		claims, _ := middleware.GetClaims(r.Context())
+		var req struct {
+			CommentID string `json:"comment_id"`
+			Field     string `json:"field"`
+			Text      string `json:"text"`
+			Reason    string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if (req.CommentID == "") == (req.Field == "") {
+			respondError(w, http.StatusBadRequest, "Set exactly one of comment_id and field")
+			return
+		}
+		if strings.TrimSpace(req.Reason) == "" {
+			respondError(w, http.StatusBadRequest, "reason is required")
+			return
+		}
+
+		red := &models.Redaction{
+			OrgID:      review.OrgID,
+			ReviewID:   review.ID,
+			CommentID:  req.CommentID,
+			Field:      req.Field,
+			Reason:     strings.TrimSpace(req.Reason),
+			RedactedBy: claims.UserID,
+			RedactedAt: time.Now(),
+		}
+		action, target := "review.redacted", review.ID
+		var err error
+		if req.CommentID != "" {
+			action, target = "comment.redacted", req.CommentID
+			_, err = st.RedactComment(req.Text, red)
+		} else {
+			_, err = st.RedactReview(req.Text, red)
+		}
+		if errors.Is(err, store.ErrNothingToRedact) {
+			respondError(w, http.StatusUnprocessableEntity, "The text to redact was not found")
+			return
+		}
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		metadata := map[string]string{"review_id": review.ID, "reason": red.Reason}
+		if red.Field != "" {
+			metadata["field"] = red.Field
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      review.OrgID,
+			ActorID:    claims.UserID,
+			Action:     action,
+			TargetType: strings.TrimSuffix(action, ".redacted"),
+			TargetID:   target,
+			Metadata:   metadata,
+		})
+		respondJSON(w, http.StatusCreated, red)
+	}
+}
+
+// ListRedactions returns the tombstones of the review and its comments.
+func ListRedactions(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListRedactions(review.ID))
+	}
+}
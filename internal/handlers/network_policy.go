@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// GetNetworkPolicy returns the CIDR ranges the organization's API access
+// is restricted to; none means unrestricted.
+func GetNetworkPolicy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.NetworkPolicy(claims.OrgID))
+	}
+}
+
+// SetNetworkPolicy reads {"cidrs": ["203.0.113.0/24", ...]} and restricts
+// the organization's API access to those ranges; an empty list lifts the
+// restriction. A policy that would block the admin's own address is
+// refused unless ?force=true, since only an operator could undo it.
+func SetNetworkPolicy(st *store.Memory, proxies security.TrustedProxies) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			CIDRs []string `json:"cidrs"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		policy := models.NetworkPolicy{OrgID: claims.OrgID, CIDRs: []string{}, UpdatedBy: claims.UserID, UpdatedAt: time.Now()}
+		for _, cidr := range req.CIDRs {
+			prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+			if err != nil {
+				respondErrorf(w, http.StatusBadRequest, "Invalid CIDR range %q", cidr)
+				return
+			}
+			policy.CIDRs = append(policy.CIDRs, prefix.Masked().String())
+		}
+		if ip := security.ClientIP(r, proxies); !policy.Allows(ip) && r.URL.Query().Get("force") != "true" {
+			respondErrorf(w, http.StatusConflict, "The policy would block your own address %s; pass ?force=true to apply it anyway", ip)
+			return
+		}
+		st.SetNetworkPolicy(policy)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "network_policy.updated",
+			TargetType: "organization",
+			TargetID:   claims.OrgID,
+			Metadata:   map[string]string{"cidrs": strings.Join(policy.CIDRs, ",")},
+		})
+		respondJSON(w, http.StatusOK, policy)
+	}
+}
+
+// ListOrgSecurityEvents returns the organization's recent security events,
+// such as requests refused by its network policy, newest first. Cap with
+// ?limit= (default 100).
+func ListOrgSecurityEvents(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		limit := 100
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > 1000 {
+				respondError(w, http.StatusBadRequest, "limit must be between 1 and 1000")
+				return
+			}
+			limit = n
+		}
+		respondJSON(w, http.StatusOK, st.ListSecurityEvents(claims.OrgID, limit))
+	}
+}
+
+// AdminGetNetworkPolicy returns any organization's network policy.
+func AdminGetNetworkPolicy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, st.NetworkPolicy(mux.Vars(r)["id"]))
+	}
+}
+
+// AdminDeleteNetworkPolicy lifts an organization's network policy, the
+// override for organizations that locked themselves out.
+func AdminDeleteNetworkPolicy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		orgID := mux.Vars(r)["id"]
+		if _, err := st.GetOrg(orgID); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.SetNetworkPolicy(models.NetworkPolicy{OrgID: orgID})
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      orgID,
+			ActorID:    claims.UserID,
+			Action:     "network_policy.overridden",
+			TargetType: "organization",
+			TargetID:   orgID,
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,418 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// The orchestrator acts as an OAuth 2.0 authorization server for clients
+// registered by an organization: the authorization code grant (with
+// optional PKCE), refresh tokens and token introspection (RFC 7662).
+const (
+	oauthCodeTTL    = 10 * time.Minute
+	oauthAccessTTL  = time.Hour
+	oauthRefreshTTL = 30 * 24 * time.Hour
+)
+
+// ListOAuthClients returns the OAuth clients registered by the organization.
+func ListOAuthClients(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListOAuthClients(claims.OrgID))
+	}
+}
+
+// CreateOAuthClient registers a client. The secret is only returned here.
+func CreateOAuthClient(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			Name         string   `json:"name"`
+			RedirectURIs []string `json:"redirect_uris"`
+			Scopes       []string `json:"scopes"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Name == "" {
+			respondError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		if len(req.RedirectURIs) == 0 {
+			respondError(w, http.StatusBadRequest, "redirect_uris is required")
+			return
+		}
+		for _, uri := range req.RedirectURIs {
+			if !validRedirectURI(uri) {
+				respondErrorf(w, http.StatusBadRequest, "invalid redirect URI %q", uri)
+				return
+			}
+		}
+		if len(req.Scopes) == 0 {
+			respondError(w, http.StatusBadRequest, "scopes is required")
+			return
+		}
+		for _, scope := range req.Scopes {
+			if !slices.Contains(models.OAuthScopes, scope) {
+				respondErrorf(w, http.StatusBadRequest, "invalid scope %q", scope)
+				return
+			}
+		}
+
+		id, err := newToken()
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to generate client")
+			return
+		}
+		secret, err := newToken()
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to generate client")
+			return
+		}
+		client := &models.OAuthClient{
+			ID:           "client-" + id[:16],
+			OrgID:        claims.OrgID,
+			Name:         req.Name,
+			RedirectURIs: req.RedirectURIs,
+			Scopes:       req.Scopes,
+			SecretHash:   middleware.HashAPIKey(secret),
+			CreatedBy:    claims.UserID,
+			CreatedAt:    time.Now(),
+		}
+		if err := st.CreateOAuthClient(client); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, struct {
+			*models.OAuthClient
+			Secret string `json:"client_secret"`
+		}{client, secret})
+	}
+}
+
+// DeleteOAuthClient removes a client and revokes its tokens.
+func DeleteOAuthClient(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		if err := st.DeleteOAuthClient(claims.OrgID, mux.Vars(r)["clientId"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// validRedirectURI accepts absolute HTTPS URIs, and HTTP on loopback for
+// local development.
+func validRedirectURI(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.Fragment != "" {
+		return false
+	}
+	switch u.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	}
+	return false
+}
+
+type authorizeRequest struct {
+	ClientID            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+	Approve             bool   `json:"approve"`
+}
+
+// resolve checks the request against the client registration and returns
+// the client and the requested scopes. Clients can only be authorized by
+// members of the organization that registered them.
+func (req authorizeRequest) resolve(w http.ResponseWriter, r *http.Request, st *store.Memory) (*models.OAuthClient, []string, bool) {
+	claims, _ := middleware.GetClaims(r.Context())
+	client, err := st.GetOAuthClient(req.ClientID)
+	if err != nil || client.OrgID != claims.OrgID {
+		respondError(w, http.StatusBadRequest, "Unknown client_id")
+		return nil, nil, false
+	}
+	if !slices.Contains(client.RedirectURIs, req.RedirectURI) {
+		respondError(w, http.StatusBadRequest, "redirect_uri is not registered for this client")
+		return nil, nil, false
+	}
+	scopes := strings.Fields(req.Scope)
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(client.Scopes, scope) {
+			respondErrorf(w, http.StatusBadRequest, "invalid scope %q", scope)
+			return nil, nil, false
+		}
+	}
+	if req.CodeChallenge != "" && req.CodeChallengeMethod != "S256" {
+		respondError(w, http.StatusBadRequest, "code_challenge_method must be S256")
+		return nil, nil, false
+	}
+	return client, scopes, true
+}
+
+// GetOAuthConsent describes an authorization request so a consent screen
+// can show the user which application is asking for which scopes. It takes
+// the standard authorize query parameters.
+func GetOAuthConsent(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		req := authorizeRequest{
+			ClientID:            q.Get("client_id"),
+			RedirectURI:         q.Get("redirect_uri"),
+			Scope:               q.Get("scope"),
+			State:               q.Get("state"),
+			CodeChallenge:       q.Get("code_challenge"),
+			CodeChallengeMethod: q.Get("code_challenge_method"),
+		}
+		if q.Get("response_type") != "code" {
+			respondError(w, http.StatusBadRequest, "response_type must be code")
+			return
+		}
+		client, scopes, ok := req.resolve(w, r, st)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"client_id":    client.ID,
+			"client_name":  client.Name,
+			"scopes":       scopes,
+			"redirect_uri": req.RedirectURI,
+			"state":        req.State,
+		})
+	}
+}
+
+// AuthorizeOAuthClient records the user's consent decision and returns the
+// URI to redirect the browser to, carrying either a code or access_denied.
+func AuthorizeOAuthClient(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req authorizeRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		_, scopes, ok := req.resolve(w, r, st)
+		if !ok {
+			return
+		}
+
+		params := url.Values{}
+		if req.State != "" {
+			params.Set("state", req.State)
+		}
+		if !req.Approve {
+			params.Set("error", "access_denied")
+			respondJSON(w, http.StatusOK, map[string]string{"redirect_to": withQuery(req.RedirectURI, params)})
+			return
+		}
+
+		token, err := newToken()
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to generate code")
+			return
+		}
+		code := middleware.OAuthCodePrefix + token
+		claims, _ := middleware.GetClaims(r.Context())
+		st.SaveOAuthCode(middleware.HashAPIKey(code), &models.OAuthCode{
+			Grant: models.OAuthGrant{
+				ClientID: req.ClientID,
+				UserID:   claims.UserID,
+				Email:    claims.Email,
+				OrgID:    claims.OrgID,
+				Role:     claims.Role,
+				Scopes:   scopes,
+			},
+			RedirectURI:   req.RedirectURI,
+			CodeChallenge: req.CodeChallenge,
+			ExpiresAt:     time.Now().Add(oauthCodeTTL),
+		})
+		params.Set("code", code)
+		respondJSON(w, http.StatusOK, map[string]string{"redirect_to": withQuery(req.RedirectURI, params)})
+	}
+}
+
+func withQuery(uri string, params url.Values) string {
+	u, _ := url.Parse(uri)
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// OAuthToken is the token endpoint. It exchanges authorization codes and
+// refresh tokens for access tokens. Clients authenticate with HTTP Basic or
+// client_id and client_secret form fields. A refresh token is spent and
+// replaced in one unit of work, so concurrent refreshes cannot both
+// succeed, and the new tokens carry the user's current role.
+func OAuthToken(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, ok := authenticateOAuthClient(w, r, st)
+		if !ok {
+			return
+		}
+		now := time.Now()
+		access, err := newToken()
+		if err != nil {
+			oauthError(w, http.StatusInternalServerError, "server_error")
+			return
+		}
+		refresh, err := newToken()
+		if err != nil {
+			oauthError(w, http.StatusInternalServerError, "server_error")
+			return
+		}
+		access = middleware.OAuthAccessPrefix + access
+		refresh = middleware.OAuthRefreshPrefix + refresh
+		var grant models.OAuthGrant
+		issue := func(tx *store.Tx) {
+			tx.SaveOAuthToken(middleware.HashAPIKey(access), &models.OAuthToken{Kind: models.OAuthAccessToken, Grant: grant, ExpiresAt: now.Add(oauthAccessTTL)})
+			tx.SaveOAuthToken(middleware.HashAPIKey(refresh), &models.OAuthToken{Kind: models.OAuthRefreshToken, Grant: grant, ExpiresAt: now.Add(oauthRefreshTTL)})
+		}
+
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			code, err := st.TakeOAuthCode(middleware.HashAPIKey(r.PostForm.Get("code")), now)
+			if err != nil || code.Grant.ClientID != client.ID || code.RedirectURI != r.PostForm.Get("redirect_uri") {
+				oauthError(w, http.StatusBadRequest, "invalid_grant")
+				return
+			}
+			if code.CodeChallenge != "" {
+				sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+				verifier := base64.RawURLEncoding.EncodeToString(sum[:])
+				if subtle.ConstantTimeCompare([]byte(verifier), []byte(code.CodeChallenge)) != 1 {
+					oauthError(w, http.StatusBadRequest, "invalid_grant")
+					return
+				}
+			}
+			grant = code.Grant
+			st.Update(func(tx *store.Tx) error {
+				issue(tx)
+				return nil
+			})
+		case "refresh_token":
+			hash := middleware.HashAPIKey(r.PostForm.Get("refresh_token"))
+			err := st.Update(func(tx *store.Tx) error {
+				// Refresh tokens rotate: each can be used once.
+				t, err := tx.TakeOAuthToken(hash, models.OAuthRefreshToken, client.ID, now)
+				if err != nil {
+					return err
+				}
+				user, err := tx.GetUser(t.Grant.UserID)
+				if err != nil || user.OrgID != t.Grant.OrgID || tx.Deactivated(user.ID) {
+					return store.ErrNotFound
+				}
+				grant = t.Grant
+				grant.Email, grant.Role = user.Email, user.Role
+				issue(tx)
+				return nil
+			})
+			if err != nil {
+				oauthError(w, http.StatusBadRequest, "invalid_grant")
+				return
+			}
+		default:
+			oauthError(w, http.StatusBadRequest, "unsupported_grant_type")
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"access_token":  access,
+			"token_type":    "Bearer",
+			"expires_in":    int(oauthAccessTTL.Seconds()),
+			"refresh_token": refresh,
+			"scope":         strings.Join(grant.Scopes, " "),
+		})
+	}
+}
+
+// OAuthIntrospect reports whether a token is active and what it grants
+// (RFC 7662). Clients can only introspect their own tokens.
+func OAuthIntrospect(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, ok := authenticateOAuthClient(w, r, st)
+		if !ok {
+			return
+		}
+		t, err := st.GetOAuthToken(middleware.HashAPIKey(r.PostForm.Get("token")), time.Now())
+		if err != nil || t.Grant.ClientID != client.ID {
+			respondJSON(w, http.StatusOK, map[string]bool{"active": false})
+			return
+		}
+		tokenType := "access_token"
+		if t.Kind == models.OAuthRefreshToken {
+			tokenType = "refresh_token"
+		}
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"active":     true,
+			"scope":      strings.Join(t.Grant.Scopes, " "),
+			"client_id":  t.Grant.ClientID,
+			"sub":        t.Grant.UserID,
+			"username":   t.Grant.Email,
+			"org_id":     t.Grant.OrgID,
+			"token_type": tokenType,
+			"exp":        t.ExpiresAt.Unix(),
+		})
+	}
+}
+
+// authenticateOAuthClient parses the form body and checks the client
+// credentials, answering invalid_client if they do not match.
+func authenticateOAuthClient(w http.ResponseWriter, r *http.Request, st *store.Memory) (*models.OAuthClient, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, 16<<10)
+	if err := r.ParseForm(); err != nil {
+		oauthError(w, http.StatusBadRequest, "invalid_request")
+		return nil, false
+	}
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	client, err := st.GetOAuthClient(id)
+	if err != nil || subtle.ConstantTimeCompare([]byte(middleware.HashAPIKey(secret)), []byte(client.SecretHash)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+		oauthError(w, http.StatusUnauthorized, "invalid_client")
+		return nil, false
+	}
+	return client, true
+}
+
+// oauthError writes an RFC 6749 error response.
+func oauthError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, status, map[string]string{"error": code})
+}
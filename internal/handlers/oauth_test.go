@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// refreshWith posts a refresh_token grant for the client with the given
+// ID and secret.
+func refreshWith(st *store.Memory, clientID, secret, token string) *httptest.ResponseRecorder {
+	form := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {token}, "client_id": {clientID}, "client_secret": {secret}}
+	req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	OAuthToken(st)(rec, req)
+	return rec
+}
+
+func TestOAuthRefresh(t *testing.T) {
+	st := store.NewMemory()
+	for _, id := range []string{"app", "other"} {
+		st.CreateOAuthClient(&models.OAuthClient{ID: id, OrgID: "acme", SecretHash: middleware.HashAPIKey(id + "-secret")})
+	}
+	admin := newUser(t, st, "acme", "admin@acme.test", "admin", "")
+	demoted := newUser(t, st, "acme", "demoted@acme.test", "admin", "")
+	gone := newUser(t, st, "acme", "gone@acme.test", "member", "")
+	refreshToken := func(u *models.User, clientID string) string {
+		token := middleware.OAuthRefreshPrefix + u.ID + clientID
+		st.SaveOAuthToken(middleware.HashAPIKey(token), &models.OAuthToken{
+			Kind:      models.OAuthRefreshToken,
+			Grant:     models.OAuthGrant{ClientID: clientID, UserID: u.ID, Email: u.Email, OrgID: u.OrgID, Role: "admin"},
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		return token
+	}
+	demotedToken := refreshToken(demoted, "app")
+	if _, err := st.SetUserRole("acme", demoted.ID, "member"); err != nil {
+		t.Fatal(err)
+	}
+	goneToken := refreshToken(gone, "app")
+	st.Deactivate(&models.Deactivation{UserID: gone.ID})
+	adminToken := refreshToken(admin, "app")
+
+	tests := []struct {
+		name     string
+		client   string
+		token    string
+		want     int
+		wantRole string
+	}{
+		{"other client", "other", adminToken, http.StatusBadRequest, ""},
+		{"own client", "app", adminToken, http.StatusOK, "admin"},
+		{"spent", "app", adminToken, http.StatusBadRequest, ""},
+		{"role changed since", "app", demotedToken, http.StatusOK, "member"},
+		{"deactivated user", "app", goneToken, http.StatusBadRequest, ""},
+		{"unknown token", "app", middleware.OAuthRefreshPrefix + "nope", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := refreshWith(st, tt.client, tt.client+"-secret", tt.token)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var resp struct {
+				AccessToken string `json:"access_token"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &resp)
+			access, err := st.GetOAuthToken(middleware.HashAPIKey(resp.AccessToken), time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if access.Grant.Role != tt.wantRole {
+				t.Errorf("access token role = %q, want %q", access.Grant.Role, tt.wantRole)
+			}
+		})
+	}
+
+	t.Run("concurrent", func(t *testing.T) {
+		token := refreshToken(admin, "app")
+		var wg sync.WaitGroup
+		codes := make(chan int, 8)
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				codes <- refreshWith(st, "app", "app-secret", token).Code
+			}()
+		}
+		wg.Wait()
+		close(codes)
+		ok := 0
+		for code := range codes {
+			if code == http.StatusOK {
+				ok++
+			}
+		}
+		if ok != 1 {
+			t.Errorf("%d refreshes succeeded, want 1", ok)
+		}
+	})
+}
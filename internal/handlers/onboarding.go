@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/onboarding"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// GetOnboarding returns the caller's welcome checklist: whether they have
+// completed their profile, created a first review and configured
+// notifications.
+func GetOnboarding(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		respondJSON(w, http.StatusOK, onboarding.State(st, claims.UserID, time.Now()))
+	}
+}
+
+// UpdateOnboarding reads {"dismissed": true|false} to hide the caller's
+// checklist or bring it back.
+func UpdateOnboarding(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		var req struct {
+			Dismissed bool `json:"dismissed"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		now := time.Now()
+		st.DismissOnboarding(claims.UserID, req.Dismissed, now)
+		respondJSON(w, http.StatusOK, onboarding.State(st, claims.UserID, now))
+	}
+}
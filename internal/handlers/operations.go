@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/operations"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/streaming"
+	"github.com/gorilla/mux"
+)
+
+// prefersAsync reports whether the client asked, with Prefer:
+// respond-async, for a long request to be answered before it finishes.
+func prefersAsync(r *http.Request) bool {
+	for _, v := range r.Header.Values("Prefer") {
+		for _, pref := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(pref), "respond-async") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// withOperationLinks adds the URLs a client follows from an operation.
+func withOperationLinks(op models.Operation) models.Operation {
+	self := "/api/operations/" + op.ID
+	op.Links = map[string]string{"self": self}
+	if !op.Finished() {
+		op.Links["cancel"] = self + "/cancel"
+	}
+	if op.File != nil && op.Status == models.OperationSucceeded {
+		op.Links["result"] = self + "/result"
+	}
+	return op
+}
+
+// respondOperation answers a request whose work continues in op.
+func respondOperation(w http.ResponseWriter, op models.Operation) {
+	op = withOperationLinks(op)
+	w.Header().Set("Location", op.Links["self"])
+	respondJSON(w, http.StatusAccepted, op)
+}
+
+// loadOperation returns the operation in the URL. Operations are visible to
+// the member who started them and to admins of their organization.
+func loadOperation(st *store.Memory, w http.ResponseWriter, r *http.Request) (*auth.Claims, models.Operation, bool) {
+	claims, ok := middleware.GetClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, models.Operation{}, false
+	}
+	op, err := st.GetOperation(claims.OrgID, mux.Vars(r)["id"])
+	if err == nil && op.CreatedBy != claims.UserID && claims.Role != "admin" {
+		err = store.ErrNotFound
+	}
+	if err != nil {
+		respondStoreError(w, err)
+		return nil, models.Operation{}, false
+	}
+	return claims, op, true
+}
+
+// ListOperations returns the operations the caller started, or for admins
+// every operation of the organization, newest first.
+func ListOperations(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		createdBy := claims.UserID
+		if claims.Role == "admin" {
+			createdBy = r.URL.Query().Get("created_by")
+		}
+		ops := st.ListOperations(claims.OrgID, createdBy)
+		for i := range ops {
+			ops[i] = withOperationLinks(ops[i])
+		}
+		respondJSON(w, http.StatusOK, ops)
+	}
+}
+
+// GetOperation returns an operation with its progress and, once it
+// finished, its result or error.
+func GetOperation(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, op, ok := loadOperation(st, w, r)
+		if !ok {
+			return
+		}
+		if !op.Finished() {
+			w.Header().Set("Retry-After", "5")
+		}
+		respondJSON(w, http.StatusOK, withOperationLinks(op))
+	}
+}
+
+// CancelOperation asks a running operation to stop. It is marked canceled
+// once its work has stopped.
+func CancelOperation(st *store.Memory, runner *operations.Runner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, op, ok := loadOperation(st, w, r)
+		if !ok {
+			return
+		}
+		op, err := runner.Cancel(claims.OrgID, op.ID)
+		if errors.Is(err, store.ErrOperationFinished) {
+			respondError(w, http.StatusConflict, "Operation already finished")
+			return
+		}
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondOperation(w, op)
+	}
+}
+
+// DownloadOperationResult serves the file a finished operation produced.
+// Range requests are supported, so interrupted downloads can resume.
+func DownloadOperationResult(st *store.Memory, runner *operations.Runner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, op, ok := loadOperation(st, w, r)
+		if !ok {
+			return
+		}
+		if !op.Finished() {
+			w.Header().Set("Retry-After", "5")
+			respondError(w, http.StatusConflict, "Operation is still running")
+			return
+		}
+		rc, err := runner.Open(op)
+		if err != nil {
+			if !errors.Is(err, blob.ErrNotFound) {
+				log.Printf("Failed to read the result of operation %s: %v", op.ID, err)
+			}
+			respondError(w, http.StatusNotFound, "Operation has no result file")
+			return
+		}
+		defer rc.Close()
+		w.Header().Set("Content-Type", op.File.ContentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": op.File.Name}))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if rs, ok := rc.(io.ReadSeeker); ok {
+			w.Header().Set("ETag", `"`+op.ID+`"`)
+			http.ServeContent(w, r, op.File.Name, *op.FinishedAt, rs)
+			return
+		}
+		io.Copy(w, rc)
+	}
+}
+
+// StartOrgExport exports the organization's data, as GET
+// /orgs/{id}/export does, in the background. The zip is downloaded from
+// the operation's result link once it is ready.
+func StartOrgExport(st *store.Memory, runner *operations.Runner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		e := newOrgExport(st, claims.OrgID, st.AuditSeq())
+		op := runner.Start(models.Operation{
+			OrgID:     claims.OrgID,
+			Kind:      "org.export",
+			CreatedBy: claims.UserID,
+		}, func(ctx context.Context, t *operations.Task) (interface{}, error) {
+			e.progress = t.Progress
+			return nil, t.SaveFile(e.name(), streaming.Zip, func(w io.Writer) error {
+				return e.write(w, st)
+			})
+		})
+		respondOperation(w, op)
+	}
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/gorilla/mux"
+)
+
+// requireOwnOrg checks that the {id} route variable names the caller's
+// organization. On failure it writes the error response and returns false.
+func requireOwnOrg(w http.ResponseWriter, r *http.Request) (*auth.Claims, bool) {
+	claims, ok := middleware.GetClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+	member := mux.Vars(r)["id"] == claims.OrgID
+	middleware.TraceDecision(r.Context(), "org", member, "organization "+mux.Vars(r)["id"])
+	if !member {
+		respondError(w, http.StatusForbidden, "Forbidden: not a member of this organization")
+		return nil, false
+	}
+	return claims, true
+}
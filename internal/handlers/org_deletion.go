@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	mailer "github.com/andres20980/aurea-orchestrator/internal/mail"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/orgdeletion"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/streaming"
+)
+
+// Notification types sent to an organization's admins about its deletion.
+const (
+	NotifyOrgDeletionScheduled = "org.deletion_scheduled"
+	NotifyOrgDeletionCanceled  = "org.deletion_canceled"
+)
+
+// RequestOrgDeletion schedules the organization's deletion at the end of a
+// grace period, during which any admin can cancel it. The organization's
+// data is exported right away for admins to download, and every admin is
+// notified in their inbox and by email.
+func RequestOrgDeletion(st *store.Memory, blobs blob.Store, m mailer.Mailer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		now := time.Now()
+		d := models.OrgDeletion{OrgID: claims.OrgID, RequestedBy: claims.UserID, RequestedAt: now, PurgeAt: now.Add(orgdeletion.Grace)}
+		err := st.ScheduleOrgDeletion(d)
+		if errors.Is(err, store.ErrDuplicate) {
+			respondError(w, http.StatusConflict, "The organization's deletion is already pending")
+			return
+		}
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		var buf bytes.Buffer
+		if err := newOrgExport(st, claims.OrgID, st.AuditSeq()).write(&buf, st); err == nil {
+			d.ExportSize = int64(buf.Len())
+			err = blobs.Put(orgdeletion.ExportKey(claims.OrgID), "application/zip", &buf)
+		}
+		if err == nil {
+			err = st.SetOrgDeletionExport(claims.OrgID, d.ExportSize)
+		}
+		if err != nil {
+			log.Printf("Failed to export organization %s before deletion: %v", claims.OrgID, err)
+			st.CancelOrgDeletion(claims.OrgID)
+			respondError(w, http.StatusInternalServerError, "Failed to export the organization's data")
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "org.deletion_scheduled",
+			TargetType: "org",
+			TargetID:   claims.OrgID,
+			Metadata:   map[string]string{"purge_at": d.PurgeAt.Format(time.RFC3339)},
+		})
+		notifyOrgDeletion(st, m, claims.OrgID, claims.UserID, NotifyOrgDeletionScheduled, func(lang string) mailer.Message {
+			return mailer.Message{
+				Subject: i18n.T(lang, "Your Aurea Orchestrator organization is scheduled for deletion"),
+				Body: i18n.T(lang, "%s requested the deletion of your organization. It and all its data will be deleted on %s unless an admin cancels it before then. Download the export of your data from /api/orgs/%s/deletion/export.",
+					claims.Email, d.PurgeAt.Format(time.RFC1123), claims.OrgID),
+			}
+		})
+		respondJSON(w, http.StatusAccepted, d)
+	}
+}
+
+// GetOrgDeletion returns the organization's pending deletion.
+func GetOrgDeletion(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		d, err := st.GetOrgDeletion(claims.OrgID)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, d)
+	}
+}
+
+// CancelOrgDeletion cancels the organization's pending deletion and
+// discards the export taken for it.
+func CancelOrgDeletion(st *store.Memory, blobs blob.Store, m mailer.Mailer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		if _, err := st.CancelOrgDeletion(claims.OrgID); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if err := blobs.Delete(orgdeletion.ExportKey(claims.OrgID)); err != nil {
+			log.Printf("Failed to delete the deletion export of organization %s: %v", claims.OrgID, err)
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "org.deletion_canceled",
+			TargetType: "org",
+			TargetID:   claims.OrgID,
+		})
+		notifyOrgDeletion(st, m, claims.OrgID, claims.UserID, NotifyOrgDeletionCanceled, func(lang string) mailer.Message {
+			return mailer.Message{
+				Subject: i18n.T(lang, "The deletion of your Aurea Orchestrator organization was canceled"),
+				Body:    i18n.T(lang, "%s canceled the pending deletion of your organization. Nothing will be deleted.", claims.Email),
+			}
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// DownloadOrgDeletionExport sends the export of the organization's data
+// taken when its deletion was requested, in the format of the
+// organization export.
+func DownloadOrgDeletionExport(st *store.Memory, blobs blob.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		d, err := st.GetOrgDeletion(claims.OrgID)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		key := orgdeletion.ExportKey(claims.OrgID)
+		streaming.Serve(w, r, streaming.Export{
+			Name:        "org-" + claims.OrgID + ".zip",
+			ContentType: streaming.Zip,
+			ETag:        fmt.Sprintf(`"org-deletion-%s-%d"`, claims.OrgID, d.RequestedAt.UnixNano()),
+			ModTime:     d.RequestedAt,
+			Write: func(w io.Writer) error {
+				rc, _, err := blobs.Get(key)
+				if err != nil {
+					return err
+				}
+				defer rc.Close()
+				_, err = io.Copy(w, rc)
+				return err
+			},
+		})
+	}
+}
+
+// notifyOrgDeletion tells every admin of the organization about its
+// deletion, in their inbox and by email.
+func notifyOrgDeletion(st *store.Memory, m mailer.Mailer, orgID, actorID, kind string, message func(lang string) mailer.Message) {
+	now := time.Now()
+	for _, u := range st.ListOrgUsers(orgID) {
+		if u.Role != "admin" {
+			continue
+		}
+		st.AddNotification(&models.Notification{
+			UserID:    u.ID,
+			OrgID:     orgID,
+			Type:      kind,
+			ActorID:   actorID,
+			CreatedAt: now,
+		})
+		msg := message(i18n.Negotiate(st.GetProfile(u.ID).Locale, ""))
+		msg.To = u.Email
+		if err := m.Send(msg); err != nil {
+			log.Printf("Failed to notify %s of the deletion of organization %s: %v", u.ID, orgID, err)
+		}
+	}
+}
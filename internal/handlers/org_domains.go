@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// TXTLookup resolves the TXT records of a DNS name.
+type TXTLookup func(ctx context.Context, name string) ([]string, error)
+
+// publicMailDomains cannot be claimed: their users are not one
+// organization's staff.
+var publicMailDomains = []string{
+	"gmail.com", "googlemail.com", "outlook.com", "hotmail.com", "live.com",
+	"yahoo.com", "icloud.com", "me.com", "aol.com", "proton.me",
+	"protonmail.com", "gmx.com", "mail.com", "yandex.com",
+}
+
+// joinRoles are the roles users can be given when joining by domain;
+// admins are always appointed explicitly.
+var joinRoles = []string{"member", "reviewer"}
+
+// normalizeDomain lowercases a domain and checks it looks like one.
+func normalizeDomain(s string) (string, bool) {
+	d := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), ".")
+	if d == "" || !strings.Contains(d, ".") || strings.ContainsAny(d, "@/: ") || len(d) > 253 {
+		return "", false
+	}
+	return d, true
+}
+
+// domainJoinRequest is the body of domain create and update requests.
+type domainJoinRequest struct {
+	Domain      string `json:"domain"`
+	JoinMode    string `json:"join_mode"`
+	DefaultRole string `json:"default_role"`
+}
+
+// validate fills in defaults and checks the join settings.
+func (req *domainJoinRequest) validate(w http.ResponseWriter) bool {
+	if req.JoinMode == "" {
+		req.JoinMode = models.JoinSuggest
+	}
+	if req.DefaultRole == "" {
+		req.DefaultRole = "member"
+	}
+	if req.JoinMode != models.JoinSuggest && req.JoinMode != models.JoinAuto {
+		respondError(w, http.StatusBadRequest, "join_mode must be suggest or auto")
+		return false
+	}
+	if !slices.Contains(joinRoles, req.DefaultRole) {
+		respondError(w, http.StatusBadRequest, "default_role must be member or reviewer")
+		return false
+	}
+	return true
+}
+
+// ListOrgDomains returns the organization's claimed email domains.
+func ListOrgDomains(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListOrgDomains(claims.OrgID))
+	}
+}
+
+// AddOrgDomain claims an email domain. The response names the TXT record
+// to publish before calling verify.
+func AddOrgDomain(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req domainJoinRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		domain, valid := normalizeDomain(req.Domain)
+		if !valid {
+			respondError(w, http.StatusBadRequest, "Invalid domain")
+			return
+		}
+		if slices.Contains(publicMailDomains, domain) {
+			respondError(w, http.StatusBadRequest, "Public email domains cannot be claimed")
+			return
+		}
+		if !req.validate(w) {
+			return
+		}
+		token, err := newToken()
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to generate secret")
+			return
+		}
+		d := models.OrgDomain{
+			OrgID:       claims.OrgID,
+			Domain:      domain,
+			JoinMode:    req.JoinMode,
+			DefaultRole: req.DefaultRole,
+			TXTName:     "_aurea-verification." + domain,
+			TXTValue:    "aurea-verification=" + token,
+			CreatedBy:   claims.UserID,
+			CreatedAt:   time.Now(),
+		}
+		if err := st.AddOrgDomain(d); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, d)
+	}
+}
+
+// UpdateOrgDomain changes how users with a matching email join.
+func UpdateOrgDomain(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req domainJoinRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !req.validate(w) {
+			return
+		}
+		d, err := st.UpdateOrgDomainJoin(claims.OrgID, mux.Vars(r)["domain"], req.JoinMode, req.DefaultRole)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, d)
+	}
+}
+
+// VerifyOrgDomain looks up the domain's verification TXT record and marks
+// the domain verified when it holds the expected value.
+func VerifyOrgDomain(st *store.Memory, lookup TXTLookup) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		d, err := st.GetOrgDomain(claims.OrgID, mux.Vars(r)["domain"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if !d.Verified {
+			ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+			records, err := lookup(ctx, d.TXTName)
+			cancel()
+			if err != nil || !slices.Contains(records, d.TXTValue) {
+				middleware.TraceDecision(r.Context(), "domain", false, "TXT record "+d.TXTName+" not found")
+				respondErrorf(w, http.StatusUnprocessableEntity, "TXT record %s with value %s not found; DNS changes can take a while to propagate", d.TXTName, d.TXTValue)
+				return
+			}
+		}
+		d, err = st.VerifyOrgDomain(claims.OrgID, d.Domain, time.Now())
+		if errors.Is(err, store.ErrDuplicate) {
+			respondError(w, http.StatusConflict, "Another organization has already verified this domain")
+			return
+		}
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, d)
+	}
+}
+
+// DeleteOrgDomain releases a domain. Existing members stay.
+func DeleteOrgDomain(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		if err := st.DeleteOrgDomain(claims.OrgID, mux.Vars(r)["domain"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// Parent organization admins can read, but not change, the reviews of
+// every organization below them. Settings follow the tree too: an org
+// without its own policy or work calendar inherits its nearest ancestor's,
+// and an enforced policy overrides the whole subtree.
+
+// ManageSetOrgParent moves an organization under another one, or to the
+// top level when parent_id is empty.
+func ManageSetOrgParent(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ParentID string `json:"parent_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		org, err := st.SetOrgParent(mux.Vars(r)["id"], req.ParentID)
+		if errors.Is(err, store.ErrCycle) {
+			respondError(w, http.StatusConflict, "An organization cannot be placed under itself or its descendants")
+			return
+		}
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, org)
+	}
+}
+
+// GetOrgHierarchy returns the organization's parent, ancestors and
+// children.
+func GetOrgHierarchy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		h, err := st.OrgHierarchy(claims.OrgID)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, h)
+	}
+}
+
+// ListTreeReviews returns the reviews of the organization and its
+// descendants, or with ?org_id= those of one organization in the tree.
+func ListTreeReviews(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		root := claims.OrgID
+		if orgID := r.URL.Query().Get("org_id"); orgID != "" {
+			if !st.InOrgTree(claims.OrgID, orgID) {
+				respondError(w, http.StatusForbidden, "Forbidden: organization is not below yours")
+				return
+			}
+			root = orgID
+		}
+		respondJSON(w, http.StatusOK, st.ListTreeReviews(root))
+	}
+}
+
+// GetTreeReview returns a review of the organization or one of its
+// descendants, read-only, with its comments, checklist and approval.
+func GetTreeReview(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		review, err := st.GetReview(mux.Vars(r)["reviewId"])
+		if err != nil || !st.InOrgTree(claims.OrgID, review.OrgID) {
+			respondError(w, http.StatusNotFound, "Review not found")
+			return
+		}
+		approval, _ := st.GetApproval(review.ID)
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"review":    review,
+			"comments":  st.ListComments(review.ID),
+			"checklist": st.ListChecklist(review.ID),
+			"approval":  approval,
+		})
+	}
+}
+
+// GetOrgRollup returns member and review counts for the organization and
+// each of its descendants, with totals.
+func GetOrgRollup(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		rollup, err := st.OrgRollup(claims.OrgID)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, rollup)
+	}
+}
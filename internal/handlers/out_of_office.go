@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// GetOutOfOffice returns the caller's out-of-office window.
+func GetOutOfOffice(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		ooo, ok := st.GetOutOfOffice(claims.UserID)
+		if !ok {
+			respondError(w, http.StatusNotFound, "No out-of-office window set")
+			return
+		}
+		respondJSON(w, http.StatusOK, ooo)
+	}
+}
+
+// SetOutOfOffice sets the caller's out-of-office window and delegate.
+func SetOutOfOffice(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+
+		var req models.OutOfOffice
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		req.UserID = claims.UserID
+		if req.Mode == "" {
+			req.Mode = models.DelegateRoute
+		}
+
+		switch {
+		case req.Start.IsZero() || req.End.IsZero():
+			respondError(w, http.StatusBadRequest, "start and end are required")
+			return
+		case !req.End.After(req.Start):
+			respondError(w, http.StatusBadRequest, "end must be after start")
+			return
+		case req.End.Before(time.Now()):
+			respondError(w, http.StatusBadRequest, "end must be in the future")
+			return
+		case req.Mode != models.DelegateRoute && req.Mode != models.DelegateCC:
+			respondError(w, http.StatusBadRequest, "mode must be route or cc")
+			return
+		}
+
+		if req.DelegateID != "" {
+			delegate, err := st.GetUser(req.DelegateID)
+			if err != nil || delegate.OrgID != claims.OrgID || delegate.ID == claims.UserID {
+				respondError(w, http.StatusBadRequest, "delegate_id must be another member of your organization")
+				return
+			}
+			if _, inactive := st.GetDeactivation(delegate.ID); inactive {
+				respondError(w, http.StatusBadRequest, "delegate is deactivated")
+				return
+			}
+		}
+
+		st.SetOutOfOffice(&req)
+		respondJSON(w, http.StatusOK, req)
+	}
+}
+
+// ClearOutOfOffice ends the caller's out-of-office window.
+func ClearOutOfOffice(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		st.ClearOutOfOffice(claims.UserID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	mailer "github.com/andres20980/aurea-orchestrator/internal/mail"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// passwordResetTTL is how long an emailed password reset link is valid.
+const passwordResetTTL = time.Hour
+
+// ForgotPassword reads {"email": "..."} and emails the account a signed,
+// single-use link to reset its password. It answers 202 whether or not
+// the account exists, so it cannot be used to probe for accounts; m
+// should not block on the relay (see mail.Async), or the time to answer
+// would tell. Each email address may only ask a few times per hour, and
+// so may each client IP, with ipLimiter.
+func ForgotPassword(st *store.Memory, m mailer.Mailer, signer *security.TokenSigner, limiter, ipLimiter *security.RateLimiter, proxies security.TrustedProxies, baseURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ok, until := ipLimiter.Allow(security.ClientIP(r, proxies)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(until).Seconds())+1))
+			respondError(w, http.StatusTooManyRequests, "Too many password reset requests; try again later")
+			return
+		}
+		var req struct {
+			Email string `json:"email"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil || req.Email == "" {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		email := strings.ToLower(strings.TrimSpace(req.Email))
+		if ok, until := limiter.Allow(email); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(until).Seconds())+1))
+			respondError(w, http.StatusTooManyRequests, "Too many password reset requests; try again later")
+			return
+		}
+		accepted := map[string]string{"status": i18n.T(i18n.LangOf(w), "If the account exists, a reset link has been sent")}
+		user, err := st.GetUserByEmail(email)
+		if err != nil {
+			respondJSON(w, http.StatusAccepted, accepted)
+			return
+		}
+		if _, inactive := st.GetDeactivation(user.ID); inactive {
+			respondJSON(w, http.StatusAccepted, accepted)
+			return
+		}
+		expires := time.Now().Add(passwordResetTTL)
+		token, err := signer.Sign(models.PasswordTokenReset, user.ID, expires)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+		st.SavePasswordToken(middleware.HashAPIKey(token), models.PasswordToken{
+			UserID:    user.ID,
+			Purpose:   models.PasswordTokenReset,
+			ExpiresAt: expires,
+		})
+		lang := i18n.Negotiate(st.GetProfile(user.ID).Locale, r.Header.Get("Accept-Language"))
+		if err := m.Send(mailer.Message{
+			To:      user.Email,
+			Subject: i18n.T(lang, "Reset your Aurea Orchestrator password"),
+			Body:    i18n.T(lang, "Use this link within an hour to choose a new password: %s\n\nIf you did not ask for it, ignore this email; your password is unchanged.", baseURL+"/reset-password?token="+token),
+		}); err != nil {
+			log.Printf("Failed to send password reset to %s: %v", user.ID, err)
+		}
+		respondJSON(w, http.StatusAccepted, accepted)
+	}
+}
+
+// ResetPassword reads {"token": "...", "new_password": "..."}, checks the
+// emailed reset token and the new password against the organization's
+// password policy, sets it and signs the user out everywhere. Each token
+// works once.
+func ResetPassword(st *store.Memory, signer *security.TokenSigner, breaches security.BreachChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Token       string `json:"token"`
+			NewPassword string `json:"new_password"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		now := time.Now()
+		userID, err := signer.Verify(models.PasswordTokenReset, req.Token, now)
+		if err != nil {
+			respondError(w, http.StatusUnauthorized, "Invalid or expired password reset token")
+			return
+		}
+		hash := middleware.HashAPIKey(req.Token)
+		pending, err := st.TakePasswordToken(hash, models.PasswordTokenReset, now)
+		if err != nil || pending.UserID != userID {
+			respondError(w, http.StatusUnauthorized, "Invalid or expired password reset token")
+			return
+		}
+		user, err := st.GetUser(userID)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if !validPassword(w, r, st, breaches, user.OrgID, req.NewPassword) {
+			// Let the user try another password with the same link.
+			st.SavePasswordToken(hash, pending)
+			return
+		}
+		if !setPassword(w, st, user, req.NewPassword, "password.reset") {
+			return
+		}
+		st.EndUserSessions(user.ID, models.SessionPasswordReset, now)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
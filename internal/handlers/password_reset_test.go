@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+func TestPasswordReset(t *testing.T) {
+	st := store.NewMemory()
+	signer := security.NewTokenSigner("secret")
+	alice := newUser(t, st, "acme", "alice@acme.test", "member", "old password")
+	gone := newUser(t, st, "acme", "gone@acme.test", "member", "old password")
+	st.Deactivate(&models.Deactivation{UserID: gone.ID})
+	now := time.Now()
+	st.AddSession(models.Session{UserID: alice.ID, OrgID: "acme", CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)})
+
+	mail := &sentMail{}
+	forgot := ForgotPassword(st, mail, signer, security.NewRateLimiter(3, time.Hour), security.NewRateLimiter(7, time.Hour), nil, "https://aurea.example")
+	// request asks for a reset link for email and returns the token it
+	// carries, if one was mailed.
+	request := func(t *testing.T, email string) string {
+		t.Helper()
+		sent := len(mail.msgs)
+		rec := httptest.NewRecorder()
+		forgot(rec, httptest.NewRequest("POST", "/auth/forgot-password", strings.NewReader(`{"email":"`+email+`"}`)))
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("forgot-password status = %d: %s", rec.Code, rec.Body)
+		}
+		if len(mail.msgs) == sent {
+			return ""
+		}
+		_, token, _ := strings.Cut(mail.msgs[len(mail.msgs)-1].Body, "token=")
+		return strings.Fields(token)[0]
+	}
+
+	t.Run("forgot", func(t *testing.T) {
+		tests := []struct {
+			email    string
+			wantMail bool
+		}{
+			{"nobody@acme.test", false},
+			{"gone@acme.test", false},
+			{" Alice@ACME.test ", true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.email, func(t *testing.T) {
+				if mailed := request(t, tt.email) != ""; mailed != tt.wantMail {
+					t.Errorf("mailed = %v, want %v", mailed, tt.wantMail)
+				}
+			})
+		}
+	})
+
+	first, second := request(t, "alice@acme.test"), request(t, "alice@acme.test")
+	t.Run("rate limited", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		forgot(rec, httptest.NewRequest("POST", "/auth/forgot-password", strings.NewReader(`{"email":"ALICE@acme.test"}`)))
+		if rec.Code != http.StatusTooManyRequests {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+		}
+	})
+
+	t.Run("rate limited per client", func(t *testing.T) {
+		// Six requests came from the test client so far.
+		tests := []struct {
+			email  string
+			remote string
+			want   int
+		}{
+			{"carol@acme.test", "192.0.2.1:1234", http.StatusAccepted},
+			{"dave@acme.test", "192.0.2.1:1234", http.StatusTooManyRequests},
+			{"dave@acme.test", "198.51.100.9:1234", http.StatusAccepted},
+		}
+		for _, tt := range tests {
+			req := httptest.NewRequest("POST", "/auth/forgot-password", strings.NewReader(`{"email":"`+tt.email+`"}`))
+			req.RemoteAddr = tt.remote
+			rec := httptest.NewRecorder()
+			forgot(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("%s from %s: status = %d, want %d", tt.email, tt.remote, rec.Code, tt.want)
+			}
+		}
+	})
+
+	wrongPurpose, _ := signer.Sign(models.PasswordTokenChange, alice.ID, now.Add(time.Hour))
+	unrecorded, _ := signer.Sign(models.PasswordTokenReset, alice.ID, now.Add(time.Hour))
+
+	t.Run("reset", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			token    string
+			password string
+			want     int
+		}{
+			{"not a token", "nope", "new password", http.StatusUnauthorized},
+			{"signed for another purpose", wrongPurpose, "new password", http.StatusUnauthorized},
+			{"signed but never sent", unrecorded, "new password", http.StatusUnauthorized},
+			{"weak password", first, "short", http.StatusBadRequest},
+			{"retry after a weak password", first, "new password", http.StatusNoContent},
+			{"used twice", first, "newer password", http.StatusUnauthorized},
+			{"another link sent before the reset", second, "newer password", http.StatusUnauthorized},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				body := `{"token":"` + tt.token + `","new_password":"` + tt.password + `"}`
+				ResetPassword(st, signer, nil)(rec, httptest.NewRequest("POST", "/auth/reset-password", strings.NewReader(body)))
+				if rec.Code != tt.want {
+					t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+				}
+			})
+		}
+	})
+
+	hash, _ := st.PasswordHash(alice.ID)
+	if !security.CheckPassword(hash, "new password") {
+		t.Error("password was not reset")
+	}
+	if sessions := st.ListSessions(alice.ID, time.Now()); len(sessions) != 0 {
+		t.Errorf("%d sessions survived the reset", len(sessions))
+	}
+}
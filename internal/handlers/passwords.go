@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+)
+
+// passwordChangeTTL is how long a forced password change may be completed
+// after the login that required it.
+const passwordChangeTTL = 15 * time.Minute
+
+// validPassword checks password against the organization's password policy
+// and writes the violation as a 400 if it fails.
+func validPassword(w http.ResponseWriter, r *http.Request, st *store.Memory, breaches security.BreachChecker, orgID, password string) bool {
+	if v := security.CheckPasswordPolicy(r.Context(), st.GetPolicy(orgID).Password, password, breaches); v != nil {
+		respondErrorf(w, http.StatusBadRequest, v.Format, v.Args...)
+		return false
+	}
+	return true
+}
+
+// setPassword hashes and stores a new password and records the change.
+func setPassword(w http.ResponseWriter, st *store.Memory, user *models.User, password, action string) bool {
+	hash, err := security.HashPassword(password)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Internal server error")
+		return false
+	}
+	st.SetPasswordHash(user.ID, hash)
+	st.AppendAudit(&models.AuditEvent{
+		OrgID:      user.OrgID,
+		ActorID:    user.ID,
+		Action:     action,
+		TargetType: "user",
+		TargetID:   user.ID,
+	})
+	return true
+}
+
+// RequirePasswordChange wraps the login handler and holds back the token
+// of users who must change their password first: because an admin forced
+// a reset, or because their password is older than the organization's
+// max_age_days. The client gets a password_change_token to complete with
+// CompletePasswordChange and then signs in again.
+func RequirePasswordChange(st *store.Memory, jwtSecret string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			held := holdResponse(w)
+			next(held, r)
+			if held.status != 0 && (held.status < 200 || held.status >= 300) {
+				held.flush(w)
+				return
+			}
+
+			var issued struct {
+				Token string `json:"token"`
+			}
+			claims := &auth.Claims{}
+			if err := json.Unmarshal(held.body.Bytes(), &issued); err != nil || issued.Token == "" {
+				held.flush(w)
+				return
+			}
+			if _, err := jwt.ParseWithClaims(issued.Token, claims, func(*jwt.Token) (interface{}, error) {
+				return []byte(jwtSecret), nil
+			}); err != nil {
+				held.flush(w)
+				return
+			}
+
+			reason := ""
+			if st.PasswordResetRequired(claims.UserID) {
+				reason = "reset_required"
+			} else if maxAge := st.GetPolicy(claims.OrgID).Password.MaxAgeDays; maxAge > 0 {
+				if setAt, ok := st.PasswordSetAt(claims.UserID); ok && time.Since(setAt) > time.Duration(maxAge)*24*time.Hour {
+					reason = "expired"
+				}
+			}
+			if reason == "" {
+				held.flush(w)
+				return
+			}
+			token, err := newToken()
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, "Internal server error")
+				return
+			}
+			expires := time.Now().Add(passwordChangeTTL)
+			st.SavePasswordToken(middleware.HashAPIKey(token), models.PasswordToken{
+				UserID:    claims.UserID,
+				Purpose:   models.PasswordTokenChange,
+				ExpiresAt: expires,
+			})
+			respondJSON(w, http.StatusForbidden, map[string]interface{}{
+				"error":                 i18n.T(i18n.LangOf(w), "Your password must be changed before you can sign in"),
+				"reason":                reason,
+				"password_change_token": token,
+				"expires_at":            expires,
+			})
+		}
+	}
+}
+
+// CompletePasswordChange reads {"password_change_token": "...",
+// "new_password": "..."} and sets the new password of a user whose login
+// required a change. The token is single-use.
+func CompletePasswordChange(st *store.Memory, breaches security.BreachChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Token       string `json:"password_change_token"`
+			NewPassword string `json:"new_password"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		hash := middleware.HashAPIKey(req.Token)
+		pending, err := st.TakePasswordToken(hash, models.PasswordTokenChange, time.Now())
+		if err != nil {
+			respondError(w, http.StatusUnauthorized, "Invalid or expired password change token")
+			return
+		}
+		user, err := st.GetUser(pending.UserID)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if !validPassword(w, r, st, breaches, user.OrgID, req.NewPassword) {
+			// Let the user try another password with the same token.
+			st.SavePasswordToken(hash, pending)
+			return
+		}
+		if setPassword(w, st, user, req.NewPassword, "password.changed") {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
+
+// ChangePassword reads {"current_password": "...", "new_password": "..."}
+// and changes the caller's password.
+func ChangePassword(st *store.Memory, breaches security.BreachChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		var req struct {
+			CurrentPassword string `json:"current_password"`
+			NewPassword     string `json:"new_password"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		current, ok := st.PasswordHash(claims.UserID)
+		if !ok || !security.CheckPassword(current, req.CurrentPassword) {
+			respondError(w, http.StatusForbidden, "Current password is incorrect")
+			return
+		}
+		if req.NewPassword == req.CurrentPassword {
+			respondError(w, http.StatusBadRequest, "The new password must differ from the current one")
+			return
+		}
+		user, err := st.GetUser(claims.UserID)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if !validPassword(w, r, st, breaches, user.OrgID, req.NewPassword) {
+			return
+		}
+		if setPassword(w, st, user, req.NewPassword, "password.changed") {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
+
+// ForcePasswordReset makes a member change their password at their next
+// login.
+func ForcePasswordReset(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		user, err := st.GetUser(mux.Vars(r)["userId"])
+		if err != nil || user.OrgID != claims.OrgID {
+			respondStoreError(w, store.ErrNotFound)
+			return
+		}
+		st.RequirePasswordReset(user.ID)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "password.reset_forced",
+			TargetType: "user",
+			TargetID:   user.ID,
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
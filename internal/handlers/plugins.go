@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/plugins"
+	"github.com/gorilla/mux"
+)
+
+// AdminListPlugins returns every available plugin with its hooks, routes
+// and failure counts.
+func AdminListPlugins(pm *plugins.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, pm.List())
+	}
+}
+
+// AdminSetPlugin enables or disables a plugin.
+func AdminSetPlugin(pm *plugins.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Enabled *bool `json:"enabled"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Enabled == nil {
+			respondError(w, http.StatusBadRequest, "enabled is required")
+			return
+		}
+		name := mux.Vars(r)["name"]
+		if err := pm.SetEnabled(name, *req.Enabled); err != nil {
+			if errors.Is(err, plugins.ErrUnknown) {
+				respondError(w, http.StatusNotFound, "Not found")
+				return
+			}
+			respondError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+		for _, s := range pm.List() {
+			if s.Name == name {
+				respondJSON(w, http.StatusOK, s)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+)
+
+// GetOrgPolicy returns the review policy of an organization.
+func GetOrgPolicy(orgs aurea.OrgService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		policy, err := orgs.GetPolicy(r.Context(), aurea.Actor{UserID: claims.UserID, OrgID: claims.OrgID})
+		if err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, policy)
+	}
+}
+
+// UpdateOrgPolicy replaces the review policy of an organization.
+func UpdateOrgPolicy(orgs aurea.OrgService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+
+		var policy aurea.OrgPolicy
+		if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		policy, err := orgs.SetPolicy(r.Context(), aurea.Actor{UserID: claims.UserID, OrgID: claims.OrgID}, policy)
+		if err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, policy)
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+const maxAvatarBytes = 2 << 20
+
+var (
+	localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+	avatarTypes = map[string]string{
+		"image/png":  "png",
+		"image/jpeg": "jpg",
+		"image/gif":  "gif",
+		"image/webp": "webp",
+	}
+)
+
+// GetProfile returns the caller's profile.
+func GetProfile(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		respondJSON(w, http.StatusOK, st.GetProfile(claims.UserID))
+	}
+}
+
+// UpdateProfile updates the caller's display name, timezone and locale.
+func UpdateProfile(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+
+		var req struct {
+			DisplayName string `json:"display_name"`
+			Timezone    string `json:"timezone"`
+			Locale      string `json:"locale"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if len(req.DisplayName) > 100 {
+			respondError(w, http.StatusBadRequest, "display_name must be at most 100 characters")
+			return
+		}
+		if req.Timezone != "" {
+			if _, err := time.LoadLocation(req.Timezone); err != nil {
+				respondError(w, http.StatusBadRequest, "timezone must be an IANA zone name such as Europe/Madrid")
+				return
+			}
+		}
+		if req.Locale != "" && !localePattern.MatchString(req.Locale) {
+			respondError(w, http.StatusBadRequest, "locale must be a language tag such as es or en-US")
+			return
+		}
+
+		profile := st.GetProfile(claims.UserID)
+		profile.DisplayName = strings.TrimSpace(req.DisplayName)
+		profile.Timezone = req.Timezone
+		profile.Locale = req.Locale
+		profile.UpdatedAt = time.Now()
+		st.SaveProfile(profile)
+
+		respondJSON(w, http.StatusOK, profile)
+	}
+}
+
+// UploadAvatar stores the request body as the caller's avatar. The image
+// type is sniffed from the content rather than trusted from the client.
+func UploadAvatar(st *store.Memory, blobs blob.Store, baseURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+
+		data, err := io.ReadAll(io.LimitReader(r.Body, maxAvatarBytes+1))
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Failed to read avatar")
+			return
+		}
+		if len(data) > maxAvatarBytes {
+			respondError(w, http.StatusRequestEntityTooLarge, "Avatar must be at most 2 MB")
+			return
+		}
+		contentType := http.DetectContentType(data)
+		ext, ok := avatarTypes[contentType]
+		if !ok {
+			respondError(w, http.StatusUnsupportedMediaType, "Avatar must be a PNG, JPEG, GIF or WebP image")
+			return
+		}
+
+		// A fresh random key per upload keeps avatar URLs unguessable and cache-safe.
+		token, err := newToken()
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to store avatar")
+			return
+		}
+		key := "avatars/" + token[:32] + "." + ext
+		if err := blobs.Put(key, contentType, bytes.NewReader(data)); err != nil {
+			log.Printf("Failed to store avatar for %s: %v", claims.UserID, err)
+			respondError(w, http.StatusInternalServerError, "Failed to store avatar")
+			return
+		}
+
+		profile := st.GetProfile(claims.UserID)
+		if profile.AvatarKey != "" {
+			blobs.Delete(profile.AvatarKey)
+		}
+		profile.AvatarKey = key
+		profile.AvatarURL = strings.TrimRight(baseURL, "/") + "/" + key
+		profile.UpdatedAt = time.Now()
+		st.SaveProfile(profile)
+
+		respondJSON(w, http.StatusOK, profile)
+	}
+}
+
+// ServeAvatar serves a stored avatar image.
+func ServeAvatar(blobs blob.Store) http.HandlerFunc {
+	return serveImage(blobs, "avatars/", "Failed to read avatar")
+}
+
+// serveImage serves an image blob under prefix. Keys are random per
+// upload, so responses can be cached forever.
+func serveImage(blobs blob.Store, prefix, failure string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rc, contentType, err := blobs.Get(prefix + mux.Vars(r)["name"])
+		if errors.Is(err, blob.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, failure)
+			return
+		}
+		defer rc.Close()
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		io.Copy(w, rc)
+	}
+}
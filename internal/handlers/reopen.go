@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+	"github.com/gorilla/mux"
+)
+
+const reviewStatusApproved = aurea.StatusApproved
+
+// isLocked reports whether a review has been approved and may no longer be edited.
+func isLocked(st *store.Memory, review *models.Review) bool {
+	if review.Status == reviewStatusApproved {
+		return true
+	}
+	_, approved := st.GetApproval(review.ID)
+	return approved
+}
+
+// RequireEditable rejects changes to approved reviews with 409 Conflict.
+// Approved reviews must be reopened before they can be edited.
+func RequireEditable(st *store.Memory) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			review, ok := loadReview(st, w, r)
+			if !ok {
+				return
+			}
+			locked := isLocked(st, review)
+			middleware.TraceDecision(r.Context(), "editable", !locked, "review status "+review.Status)
+			if locked {
+				respondError(w, http.StatusConflict, "Review is approved and cannot be modified; reopen it first")
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// ReopenReview resets the approval of a review so it can be edited again.
+// A reason is mandatory and the action is written to the audit log.
+func ReopenReview(reviews aurea.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := actorFrom(w, r)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		review, err := reviews.Reopen(r.Context(), actor, mux.Vars(r)["id"], req.Reason)
+		if err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		log.Printf("Review %s reopened by %s: %s", review.ID, actor.UserID, req.Reason)
+		respondJSON(w, http.StatusOK, review)
+	}
+}
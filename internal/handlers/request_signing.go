@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// GetRequestSigning returns whether the organization requires signed API
+// key requests.
+func GetRequestSigning(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]bool{"required": st.RequiresSignedRequests(claims.OrgID)})
+	}
+}
+
+// SetRequestSigning reads {"required": true|false}. While required, the
+// organization's API keys are refused as "ApiKey" credentials and only
+// accepted as request signatures.
+func SetRequestSigning(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			Required bool `json:"required"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		st.SetRequireSignedRequests(claims.OrgID, req.Required)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "request_signing.updated",
+			TargetType: "organization",
+			TargetID:   claims.OrgID,
+			Metadata:   map[string]string{"required": strconv.FormatBool(req.Required)},
+		})
+		respondJSON(w, http.StatusOK, map[string]bool{"required": req.Required})
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+)
+
+// respondJSON writes v as a JSON body with the given status code.
+func respondJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// respondError writes a JSON error body with the given status code,
+// translated into the response language.
+func respondError(w http.ResponseWriter, status int, message string) {
+	respondJSON(w, status, map[string]string{"error": i18n.T(i18n.LangOf(w), message)})
+}
+
+// respondErrorf is like respondError but translates format before applying args.
+func respondErrorf(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	respondJSON(w, status, map[string]string{"error": i18n.T(i18n.LangOf(w), format, args...)})
+}
+
+// statusRecorder captures the status code written by a wrapped handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+func (s *statusRecorder) succeeded() bool {
+	return s.status == 0 || (s.status >= 200 && s.status < 300)
+}
+
+// bodyRecorder captures the status and body written by a wrapped handler
+// while still passing them through to the client.
+type bodyRecorder struct {
+	statusRecorder
+	body bytes.Buffer
+}
+
+func (b *bodyRecorder) Write(p []byte) (int, error) {
+	b.body.Write(p)
+	return b.ResponseWriter.Write(p)
+}
+
+// bufferedResponse holds back the status and body written by a wrapped
+// handler so they can be rewritten before reaching the client. Headers go
+// to the underlying writer.
+type bufferedResponse struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) WriteHeader(code int) {
+	if b.status == 0 {
+		b.status = code
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+// flush sends body with the held back status.
+func (b *bufferedResponse) flush(body []byte) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	b.ResponseWriter.Header().Del("Content-Length")
+	b.ResponseWriter.WriteHeader(b.status)
+	b.ResponseWriter.Write(body)
+}
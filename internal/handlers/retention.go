@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/retention"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Bounds of retention policies.
+const (
+	maxRetentionDays   = 3650
+	maxRetentionMonths = 120
+)
+
+// GetRetentionPolicy returns how long the organization's data is kept.
+func GetRetentionPolicy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.GetRetentionPolicy(claims.OrgID))
+	}
+}
+
+// SetRetentionPolicy replaces the organization's retention policy. It
+// takes effect at the next scheduled run; preview it first.
+func SetRetentionPolicy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		p, ok := decodeRetentionPolicy(w, r)
+		if !ok {
+			return
+		}
+		p.OrgID = claims.OrgID
+		p.UpdatedBy = claims.UserID
+		p.UpdatedAt = time.Now()
+		st.SetRetentionPolicy(p)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "retention_policy.updated",
+			TargetType: "org",
+			TargetID:   claims.OrgID,
+			Metadata: map[string]string{
+				"close_idle_days":    strconv.Itoa(p.CloseIdleDays),
+				"purge_deleted_days": strconv.Itoa(p.PurgeDeletedDays),
+				"audit_months":       strconv.Itoa(p.AuditMonths),
+			},
+		})
+		respondJSON(w, http.StatusOK, p)
+	}
+}
+
+// PreviewRetention returns what applying the organization's retention
+// policy now would do, without doing it. A POST previews the policy in
+// its body instead of the current one.
+func PreviewRetention(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		p := st.GetRetentionPolicy(claims.OrgID)
+		if r.Method == http.MethodPost {
+			if p, ok = decodeRetentionPolicy(w, r); !ok {
+				return
+			}
+			p.OrgID = claims.OrgID
+		}
+		respondJSON(w, http.StatusOK, retention.Preview(st, p, time.Now()))
+	}
+}
+
+// ListRetentionRuns returns what the organization's latest scheduled
+// retention runs did, newest first.
+func ListRetentionRuns(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListRetentionRuns(claims.OrgID))
+	}
+}
+
+func decodeRetentionPolicy(w http.ResponseWriter, r *http.Request) (models.RetentionPolicy, bool) {
+	var p models.RetentionPolicy
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return p, false
+	}
+	if p.CloseIdleDays < 0 || p.CloseIdleDays > maxRetentionDays || p.PurgeDeletedDays < 0 || p.PurgeDeletedDays > maxRetentionDays {
+		respondErrorf(w, http.StatusBadRequest, "Retention days must be between 0 and %d", maxRetentionDays)
+		return p, false
+	}
+	if p.AuditMonths < 0 || p.AuditMonths > maxRetentionMonths {
+		respondErrorf(w, http.StatusBadRequest, "audit_months must be between 0 and %d", maxRetentionMonths)
+		return p, false
+	}
+	return p, true
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// loadReview fetches the review named by the {id} route variable and checks
+// that it belongs to the caller's organization. On failure it writes the
+// error response and returns false.
+func loadReview(st *store.Memory, w http.ResponseWriter, r *http.Request) (*models.Review, bool) {
+	claims, ok := middleware.GetClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	review, err := st.GetReview(mux.Vars(r)["id"])
+	if err != nil {
+		respondStoreError(w, err)
+		return nil, false
+	}
+	middleware.TraceDecision(r.Context(), "org", review.OrgID == claims.OrgID, "review "+review.ID+" in organization "+review.OrgID)
+	if review.OrgID != claims.OrgID {
+		// Do not reveal reviews from other organizations.
+		respondError(w, http.StatusNotFound, "Not found")
+		return nil, false
+	}
+	return review, true
+}
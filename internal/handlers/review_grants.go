@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// A review grant lets the users of another organization read a single
+// review, and with comment access also comment on it, through the regular
+// /reviews/{id}/comments endpoints. They never become members. Guest grants
+// do the same for one guest of the owning organization.
+
+// ListReviewGrants returns the organizations a review is shared with.
+func ListReviewGrants(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListReviewGrants(review.ID))
+	}
+}
+
+// SetReviewGrant shares a review with the organization in {orgId}. It reads
+// {"access": "read"|"comment", "note": "..."}.
+func SetReviewGrant(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		claims, _ := middleware.GetClaims(r.Context())
+		var req struct {
+			Access string `json:"access"`
+			Note   string `json:"note"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Access != models.GrantRead && req.Access != models.GrantComment {
+			respondError(w, http.StatusBadRequest, "access must be read or comment")
+			return
+		}
+		grantee := mux.Vars(r)["orgId"]
+		if grantee == review.OrgID {
+			respondError(w, http.StatusBadRequest, "A review cannot be shared with its own organization")
+			return
+		}
+		if _, err := st.GetOrg(grantee); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		g := models.ReviewGrant{
+			ReviewID:     review.ID,
+			OrgID:        review.OrgID,
+			GranteeOrgID: grantee,
+			Access:       req.Access,
+			Note:         req.Note,
+			GrantedBy:    claims.UserID,
+			GrantedAt:    time.Now(),
+		}
+		st.SetReviewGrant(g)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      review.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "review.shared",
+			TargetType: "review",
+			TargetID:   review.ID,
+			Metadata:   map[string]string{"grantee_org_id": grantee, "access": req.Access},
+		})
+		respondJSON(w, http.StatusOK, g)
+	}
+}
+
+// DeleteReviewGrant stops sharing a review with the organization in
+// {orgId}.
+func DeleteReviewGrant(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		claims, _ := middleware.GetClaims(r.Context())
+		grantee := mux.Vars(r)["orgId"]
+		if err := st.DeleteReviewGrant(review.ID, grantee); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      review.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "review.unshared",
+			TargetType: "review",
+			TargetID:   review.ID,
+			Metadata:   map[string]string{"grantee_org_id": grantee},
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// sharedReview is a review another organization shared with the caller's.
+type sharedReview struct {
+	Review    *models.Review `json:"review"`
+	OwnerOrg  string         `json:"owner_org_id"`
+	Access    string         `json:"access"`
+	GrantedAt time.Time      `json:"granted_at"`
+}
+
+// ListSharedReviews returns the reviews other organizations have shared
+// with the caller's organization, or for guests those shared with them.
+func ListSharedReviews(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		out := []sharedReview{}
+		if claims.Role == middleware.GuestRole {
+			for _, g := range st.ListGuestGrantsTo(claims.UserID) {
+				if review, err := st.GetReview(g.ReviewID); err == nil && g.OrgID == claims.OrgID {
+					out = append(out, sharedReview{Review: review, OwnerOrg: g.OrgID, Access: g.Access, GrantedAt: g.GrantedAt})
+				}
+			}
+			respondJSON(w, http.StatusOK, out)
+			return
+		}
+		for _, g := range st.ListGrantsTo(claims.OrgID) {
+			if review, err := st.GetReview(g.ReviewID); err == nil {
+				out = append(out, sharedReview{Review: review, OwnerOrg: g.OrgID, Access: g.Access, GrantedAt: g.GrantedAt})
+			}
+		}
+		respondJSON(w, http.StatusOK, out)
+	}
+}
+
+// GetSharedReview returns one review shared with the caller's organization,
+// or for guests with them.
+func GetSharedReview(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		id := mux.Vars(r)["id"]
+		var shared sharedReview
+		var granted bool
+		if claims.Role == middleware.GuestRole {
+			var g models.GuestGrant
+			g, granted = st.GuestGrant(id, claims.UserID)
+			granted = granted && g.OrgID == claims.OrgID
+			shared = sharedReview{OwnerOrg: g.OrgID, Access: g.Access, GrantedAt: g.GrantedAt}
+			middleware.TraceDecision(r.Context(), "guest_grant", granted, "review "+id+" shared with guest "+claims.UserID)
+		} else {
+			var g models.ReviewGrant
+			g, granted = st.ReviewGrant(id, claims.OrgID)
+			shared = sharedReview{OwnerOrg: g.OrgID, Access: g.Access, GrantedAt: g.GrantedAt}
+			middleware.TraceDecision(r.Context(), "review_grant", granted, "review "+id+" shared with organization "+claims.OrgID)
+		}
+		review, err := st.GetReview(id)
+		if !granted || err != nil {
+			respondError(w, http.StatusNotFound, "Not found")
+			return
+		}
+		shared.Review = review
+		respondJSON(w, http.StatusOK, shared)
+	}
+}
+
+// ListGuestGrants returns the guests a review is shared with.
+func ListGuestGrants(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListGuestGrants(review.ID))
+	}
+}
+
+// SetGuestGrant shares a review with the guest in {userId}, who must be a
+// guest of the review's organization. It reads {"access": "read"|"comment"}.
+func SetGuestGrant(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		claims, _ := middleware.GetClaims(r.Context())
+		var req struct {
+			Access string `json:"access"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Access != models.GrantRead && req.Access != models.GrantComment {
+			respondError(w, http.StatusBadRequest, "access must be read or comment")
+			return
+		}
+		user, err := st.GetUser(mux.Vars(r)["userId"])
+		if err != nil || user.OrgID != review.OrgID {
+			respondError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		if user.Role != middleware.GuestRole {
+			respondError(w, http.StatusBadRequest, "Only guests need reviews shared with them")
+			return
+		}
+		g := models.GuestGrant{
+			ReviewID:  review.ID,
+			OrgID:     review.OrgID,
+			UserID:    user.ID,
+			Access:    req.Access,
+			GrantedBy: claims.UserID,
+			GrantedAt: time.Now(),
+		}
+		st.SetGuestGrant(g)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      review.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "review.shared",
+			TargetType: "review",
+			TargetID:   review.ID,
+			Metadata:   map[string]string{"guest_id": user.ID, "access": req.Access},
+		})
+		respondJSON(w, http.StatusOK, g)
+	}
+}
+
+// DeleteGuestGrant stops sharing a review with the guest in {userId}.
+func DeleteGuestGrant(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		claims, _ := middleware.GetClaims(r.Context())
+		guest := mux.Vars(r)["userId"]
+		if err := st.DeleteGuestGrant(review.ID, guest); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      review.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "review.unshared",
+			TargetType: "review",
+			TargetID:   review.ID,
+			Metadata:   map[string]string{"guest_id": guest},
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
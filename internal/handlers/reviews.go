@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+	"github.com/gorilla/mux"
+)
+
+// ListReviews returns the reviews of the caller's organization, ordered
+// by ?sort= as ParseSort reads it. ?per_page= (at most 100) pages the
+// list and ?page= picks a page, starting at 1; without per_page every
+// review is returned. ?field.<key>=<value> keeps only the reviews whose
+// custom field key has that value.
+func ListReviews(reviews aurea.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := actorFrom(w, r)
+		if !ok {
+			return
+		}
+		opts := aurea.ListOptions{Sort: r.URL.Query().Get("sort")}
+		for param, vals := range r.URL.Query() {
+			if key, ok := strings.CutPrefix(param, "field."); ok && len(vals) > 0 {
+				if opts.Fields == nil {
+					opts.Fields = make(map[string]string)
+				}
+				opts.Fields[key] = vals[0]
+			}
+		}
+		for _, p := range []struct {
+			name string
+			n    *int
+		}{{"page", &opts.Page}, {"per_page", &opts.PerPage}} {
+			v := r.URL.Query().Get(p.name)
+			if v == "" {
+				continue
+			}
+			var err error
+			if *p.n, err = strconv.Atoi(v); err != nil || *p.n < 1 {
+				respondError(w, http.StatusBadRequest, p.name+" must be a positive number")
+				return
+			}
+		}
+		list, err := reviews.List(r.Context(), actor, opts)
+		if err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, list)
+	}
+}
+
+// CreateReview creates a pending review authored by the caller. The
+// service runs the plugins' create hooks and records the review, its audit
+// event and its review.created event together.
+func CreateReview(reviews aurea.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := actorFrom(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			Title   string `json:"title"`
+			Content string `json:"content"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		review, err := reviews.Create(r.Context(), actor, req.Title, req.Content)
+		if err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, review)
+	}
+}
+
+// GetReview returns a review of the caller's organization, or one shared
+// with it.
+func GetReview(reviews aurea.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := actorFrom(w, r)
+		if !ok {
+			return
+		}
+		review, err := reviews.Get(r.Context(), actor, mux.Vars(r)["id"])
+		if err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, review)
+	}
+}
+
+// UpdateReview replaces the title and content of a review of the caller's
+// organization.
+func UpdateReview(reviews aurea.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := actorFrom(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			Title   string `json:"title"`
+			Content string `json:"content"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		review, err := reviews.Update(r.Context(), actor, mux.Vars(r)["id"], req.Title, req.Content)
+		if err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, review)
+	}
+}
+
+// ApproveReview approves a review of the caller's organization. The
+// wrappers in main.go check the organization's approval requirements
+// first.
+func ApproveReview(reviews aurea.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := actorFrom(w, r)
+		if !ok {
+			return
+		}
+		review, err := reviews.Approve(r.Context(), actor, mux.Vars(r)["id"])
+		if err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, review)
+	}
+}
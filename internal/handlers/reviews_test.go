@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/signing"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+	"github.com/gorilla/mux"
+)
+
+func TestApproveReview(t *testing.T) {
+	st := store.NewMemory()
+	svc := aurea.NewWithStore(st, notify.NewDispatcher(st))
+	signer, err := signing.NewEphemeralSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.UseApprovalSigner(signer)
+	admin := newUser(t, st, "acme", "admin@acme.test", "admin", "")
+	outsider := newUser(t, st, "globex", "admin@globex.test", "admin", "")
+	st.SetPolicy(models.OrgPolicy{OrgID: "acme", RequireResolvedThreads: true})
+
+	actor := aurea.Actor{UserID: admin.ID, OrgID: "acme"}
+	open, _ := svc.Reviews().Create(context.Background(), actor, "Open thread", "...")
+	st.AddComment(&models.Comment{ReviewID: open.ID, AuthorID: admin.ID, Body: "?"})
+	ready, _ := svc.Reviews().Create(context.Background(), actor, "Ready", "...")
+
+	approve := RequireResolvedThreads(st)(ApproveReview(svc.Reviews()))
+	tests := []struct {
+		name   string
+		caller *models.User
+		id     string
+		want   int
+	}{
+		{"missing review", admin, "review-404", http.StatusNotFound},
+		{"other org", outsider, ready.ID, http.StatusNotFound},
+		{"unresolved thread", admin, open.ID, http.StatusConflict},
+		{"ready", admin, ready.ID, http.StatusOK},
+		{"already approved", admin, ready.ID, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := asUser(httptest.NewRequest("POST", "/", nil), tt.caller)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			rec := httptest.NewRecorder()
+			approve(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+
+	approval, ok := st.GetApproval(ready.ID)
+	if !ok || approval.Proof == nil {
+		t.Fatal("approval was not recorded with a proof")
+	}
+	if _, ok := st.GetApproval(open.ID); ok {
+		t.Error("gated approval was recorded")
+	}
+}
+
+// BenchmarkReviewCRUD measures the review handlers against an
+// organization that already has reviews.
+func BenchmarkReviewCRUD(b *testing.B) {
+	st := store.NewMemory()
+	svc := aurea.NewWithStore(st, notify.NewDispatcher(st))
+	admin := newUser(b, st, "acme", "admin@acme.test", "admin", "")
+	actor := aurea.Actor{UserID: admin.ID, OrgID: "acme"}
+	var ids []string
+	for i := 0; i < 200; i++ {
+		rv, err := svc.Reviews().Create(context.Background(), actor, fmt.Sprintf("Review %d", i), "...")
+		if err != nil {
+			b.Fatal(err)
+		}
+		ids = append(ids, rv.ID)
+	}
+
+	benchmarks := []struct {
+		name    string
+		handler http.HandlerFunc
+		method  string
+		body    string
+		want    int
+	}{
+		{"create", CreateReview(svc.Reviews()), "POST", `{"title":"New","content":"..."}`, http.StatusCreated},
+		{"get", GetReview(svc.Reviews()), "GET", "", http.StatusOK},
+		{"update", UpdateReview(svc.Reviews()), "PUT", `{"title":"Renamed","content":"..."}`, http.StatusOK},
+		{"list", ListReviews(svc.Reviews()), "GET", "", http.StatusOK},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				req := asUser(httptest.NewRequest(bm.method, "/", strings.NewReader(bm.body)), admin)
+				req = mux.SetURLVars(req, map[string]string{"id": ids[i%len(ids)]})
+				rec := httptest.NewRecorder()
+				bm.handler(rec, req)
+				if rec.Code != bm.want {
+					b.Fatalf("status = %d, want %d: %s", rec.Code, bm.want, rec.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/risk"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Bounds of risk policies.
+const (
+	maxSensitivePaths = 100
+	maxRiskApprovals  = 10
+)
+
+// GetRiskPolicy returns how the organization's reviews are scored for risk.
+func GetRiskPolicy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.GetRiskPolicy(claims.OrgID))
+	}
+}
+
+// SetRiskPolicy replaces the organization's risk policy.
+func SetRiskPolicy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var p models.RiskPolicy
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !validateRiskPolicy(w, p) {
+			return
+		}
+		if p.SensitivePaths == nil {
+			p.SensitivePaths = []models.RiskPath{}
+		}
+		if p.LabelPoints == nil {
+			p.LabelPoints = map[string]int{}
+		}
+		if p.RequiredApprovals == nil {
+			p.RequiredApprovals = map[string]int{}
+		}
+		p.OrgID = claims.OrgID
+		p.UpdatedBy = claims.UserID
+		p.UpdatedAt = time.Now()
+		st.SetRiskPolicy(p)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "risk_policy.updated",
+			TargetType: "org",
+			TargetID:   claims.OrgID,
+			Metadata: map[string]string{
+				"medium_score": strconv.Itoa(p.MediumScore),
+				"high_score":   strconv.Itoa(p.HighScore),
+			},
+		})
+		respondJSON(w, http.StatusOK, p)
+	}
+}
+
+func validateRiskPolicy(w http.ResponseWriter, p models.RiskPolicy) bool {
+	if p.LargeChangeLines < 0 || p.SizePoints < 0 || p.NewAuthorApprovals < 0 || p.NewAuthorPoints < 0 ||
+		p.ReopenedPoints < 0 || p.MaxReopenedPoints < 0 {
+		respondError(w, http.StatusBadRequest, "Risk weights must not be negative")
+		return false
+	}
+	if p.MediumScore < 0 || p.HighScore < p.MediumScore || p.HighScore > 100 {
+		respondError(w, http.StatusBadRequest, "Scores must satisfy 0 <= medium_score <= high_score <= 100")
+		return false
+	}
+	if len(p.SensitivePaths) > maxSensitivePaths {
+		respondErrorf(w, http.StatusBadRequest, "At most %d sensitive paths are allowed", maxSensitivePaths)
+		return false
+	}
+	for _, sp := range p.SensitivePaths {
+		if sp.Pattern == "" || sp.Points < 0 || !validPathPattern(sp.Pattern) {
+			respondErrorf(w, http.StatusBadRequest, "Invalid sensitive path %q", sp.Pattern)
+			return false
+		}
+	}
+	for label, points := range p.LabelPoints {
+		if points < 0 {
+			respondErrorf(w, http.StatusBadRequest, "Points of label %q must not be negative", label)
+			return false
+		}
+	}
+	for level, n := range p.RequiredApprovals {
+		if !slices.Contains(models.RiskLevels, level) || n < 1 || n > maxRiskApprovals {
+			respondErrorf(w, http.StatusBadRequest, "required_approvals must map low, medium or high to 1-%d approvals", maxRiskApprovals)
+			return false
+		}
+	}
+	return true
+}
+
+func validPathPattern(pattern string) bool {
+	_, err := path.Match(strings.TrimSuffix(pattern, "/"), "")
+	return err == nil
+}
+
+// reviewRisk is a review's risk score with the approvals it has so far.
+type reviewRisk struct {
+	models.RiskScore
+	Signoffs []models.RiskSignoff `json:"signoffs"`
+}
+
+// GetReviewRisk returns how risky a review is, why, and how many admins
+// must approve it.
+func GetReviewRisk(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, reviewRisk{RiskScore: risk.Review(st, review), Signoffs: currentSignoffs(st, review)})
+	}
+}
+
+// currentSignoffs returns the sign-offs given since the review last changed.
+func currentSignoffs(st *store.Memory, review *models.Review) []models.RiskSignoff {
+	return slices.DeleteFunc(st.RiskSignoffs(review.ID), func(s models.RiskSignoff) bool {
+		return s.At.Before(review.UpdatedAt)
+	})
+}
+
+// AddRisk adds each review's risk score to the wrapped handler's response,
+// as "risk". With ?risk=<level>, lists only keep reviews of that level.
+func AddRisk(st *store.Memory) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			level := r.URL.Query().Get("risk")
+			if level != "" && !slices.Contains(models.RiskLevels, level) {
+				respondError(w, http.StatusBadRequest, "risk must be one of low, medium, high")
+				return
+			}
+			held := holdResponse(w)
+			next(held, r)
+			var v interface{}
+			if held.status >= 300 || json.Unmarshal(held.body.Bytes(), &v) != nil {
+				held.flush(w)
+				return
+			}
+			scored := func(item map[string]interface{}) (models.RiskScore, bool) {
+				id, _ := item["id"].(string)
+				review, err := st.GetReview(id)
+				if err != nil {
+					return models.RiskScore{}, false
+				}
+				s := risk.Review(st, review)
+				item["risk"] = s
+				return s, true
+			}
+			out := v
+			switch v := v.(type) {
+			case map[string]interface{}:
+				scored(v)
+			case []interface{}:
+				kept := []interface{}{}
+				for _, child := range v {
+					item, ok := child.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					if s, ok := scored(item); ok && (level == "" || s.Level == level) {
+						kept = append(kept, item)
+					}
+				}
+				out = kept
+			}
+			held.header.Del("Content-Length")
+			for k, vals := range held.header {
+				w.Header()[k] = vals
+			}
+			status := held.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			respondJSON(w, status, out)
+		}
+	}
+}
+
+// RequireRiskApprovals holds back the approval of reviews whose risk level
+// needs several admins to approve. Each admin's approval is recorded as a
+// sign-off answered with 202 Accepted until the last one needed, which
+// goes through. Editing the review voids earlier sign-offs.
+func RequireRiskApprovals(st *store.Memory) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			review, ok := loadReview(st, w, r)
+			if !ok {
+				return
+			}
+			score := risk.Review(st, review)
+			if score.RequiredApprovals <= 1 {
+				next(w, r)
+				return
+			}
+			claims, _ := middleware.GetClaims(r.Context())
+			signoffs := currentSignoffs(st, review)
+			if !slices.ContainsFunc(signoffs, func(s models.RiskSignoff) bool { return s.UserID == claims.UserID }) {
+				st.AddRiskSignoff(models.RiskSignoff{ReviewID: review.ID, UserID: claims.UserID, Level: score.Level, At: time.Now()})
+				signoffs = currentSignoffs(st, review)
+			}
+			n := len(signoffs)
+			enough := n >= score.RequiredApprovals
+			middleware.TraceDecision(r.Context(), "policy.risk_approvals", enough,
+				fmt.Sprintf("%s risk, %d of %d approvals", score.Level, n, score.RequiredApprovals))
+			if !enough {
+				st.AppendAudit(&models.AuditEvent{
+					OrgID:      review.OrgID,
+					ActorID:    claims.UserID,
+					Action:     "review.risk_signoff",
+					TargetType: "review",
+					TargetID:   review.ID,
+					Metadata: map[string]string{
+						"level":     score.Level,
+						"approvals": strconv.Itoa(n),
+						"required":  strconv.Itoa(score.RequiredApprovals),
+					},
+				})
+				respondJSON(w, http.StatusAccepted, reviewRisk{RiskScore: score, Signoffs: signoffs})
+				return
+			}
+			next(w, r)
+		}
+	}
+}
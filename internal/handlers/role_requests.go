@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// Role changes go through requests: a member asks for a role with a
+// reason, and an admin of the organization approves or rejects it. Every
+// step is audit-logged. The new role applies from the member's next login.
+
+// roleChange reads {"role": "...", "reason": "..."}, requiring both.
+func roleChange(w http.ResponseWriter, r *http.Request) (role, reason string, ok bool) {
+	var req struct {
+		Role   string `json:"role"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return "", "", false
+	}
+	if !memberRoles[req.Role] {
+		respondErrorf(w, http.StatusBadRequest, "invalid role %q", req.Role)
+		return "", "", false
+	}
+	if req.Reason == "" {
+		respondError(w, http.StatusBadRequest, "reason is required")
+		return "", "", false
+	}
+	return req.Role, req.Reason, true
+}
+
+// RequestRole asks the caller's organization admins for another role.
+func RequestRole(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		role, reason, ok := roleChange(w, r)
+		if !ok {
+			return
+		}
+		user, err := st.GetUser(claims.UserID)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if user.Role == role {
+			respondError(w, http.StatusBadRequest, "You already have this role")
+			return
+		}
+		req, err := st.AddRoleRequest(models.RoleRequest{
+			OrgID:     user.OrgID,
+			UserID:    user.ID,
+			FromRole:  user.Role,
+			Role:      role,
+			Reason:    reason,
+			CreatedAt: time.Now(),
+		})
+		if errors.Is(err, store.ErrDuplicate) {
+			respondError(w, http.StatusConflict, "You already have a pending role request")
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      user.OrgID,
+			ActorID:    user.ID,
+			Action:     "role.requested",
+			TargetType: "user",
+			TargetID:   user.ID,
+			Reason:     reason,
+			Metadata:   map[string]string{"request_id": req.ID, "from": user.Role, "to": role},
+		})
+		respondJSON(w, http.StatusCreated, req)
+	}
+}
+
+// ListMyRoleRequests returns the caller's role requests.
+func ListMyRoleRequests(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListRoleRequests(claims.OrgID, claims.UserID, r.URL.Query().Get("status")))
+	}
+}
+
+// CancelRoleRequest withdraws one of the caller's pending role requests.
+func CancelRoleRequest(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		id := mux.Vars(r)["requestId"]
+		if req, err := st.GetRoleRequest(claims.OrgID, id); err != nil || req.UserID != claims.UserID {
+			respondError(w, http.StatusNotFound, "Not found")
+			return
+		}
+		req, err := st.DecideRoleRequest(claims.OrgID, id, models.RoleRequestCancelled, claims.UserID, "")
+		if err != nil {
+			respondRoleRequestError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, req)
+	}
+}
+
+// ListRoleRequests returns the organization's role requests, filtered by
+// ?status= (e.g. pending) and ?user_id=.
+func ListRoleRequests(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		q := r.URL.Query()
+		respondJSON(w, http.StatusOK, st.ListRoleRequests(claims.OrgID, q.Get("user_id"), q.Get("status")))
+	}
+}
+
+// ApproveRoleRequest grants the requested role. It reads an optional
+// {"reason": "..."}.
+func ApproveRoleRequest(st *store.Memory) http.HandlerFunc {
+	return decideRoleRequest(st, models.RoleRequestApproved)
+}
+
+// RejectRoleRequest turns a role request down. It reads
+// {"reason": "..."}, which is required.
+func RejectRoleRequest(st *store.Memory) http.HandlerFunc {
+	return decideRoleRequest(st, models.RoleRequestRejected)
+}
+
+func decideRoleRequest(st *store.Memory, status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var body struct {
+			Reason string `json:"reason"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+		}
+		if status == models.RoleRequestRejected && body.Reason == "" {
+			respondError(w, http.StatusBadRequest, "reason is required")
+			return
+		}
+		id := mux.Vars(r)["requestId"]
+		pending, err := st.GetRoleRequest(claims.OrgID, id)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if pending.UserID == claims.UserID {
+			respondError(w, http.StatusForbidden, "Forbidden: you cannot decide your own role request")
+			return
+		}
+		req, err := st.DecideRoleRequest(claims.OrgID, id, status, claims.UserID, body.Reason)
+		if err != nil {
+			respondRoleRequestError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "role." + status,
+			TargetType: "user",
+			TargetID:   req.UserID,
+			Reason:     body.Reason,
+			Metadata:   map[string]string{"request_id": req.ID, "from": req.FromRole, "to": req.Role},
+		})
+		respondJSON(w, http.StatusOK, req)
+	}
+}
+
+func respondRoleRequestError(w http.ResponseWriter, err error) {
+	if errors.Is(err, store.ErrDecided) {
+		respondError(w, http.StatusConflict, "Role request is no longer pending or the member's role has changed")
+		return
+	}
+	respondStoreError(w, err)
+}
+
+// SetMemberRole changes a member's role directly. It reads
+// {"role": "...", "reason": "..."}, records the change in the audit log
+// and ends the member's sessions.
+func SetMemberRole(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		role, reason, ok := roleChange(w, r)
+		if !ok {
+			return
+		}
+		userID := mux.Vars(r)["userId"]
+		if userID == claims.UserID {
+			respondError(w, http.StatusForbidden, "Forbidden: you cannot change your own role")
+			return
+		}
+		err := st.Update(func(tx *store.Tx) error {
+			previous, err := tx.SetUserRole(claims.OrgID, userID, role)
+			if err != nil {
+				return err
+			}
+			// Tokens carry the role they were issued with.
+			if previous != role {
+				tx.EndUserSessions(userID, "role changed", time.Now())
+			}
+			tx.AppendAudit(&models.AuditEvent{
+				OrgID:      claims.OrgID,
+				ActorID:    claims.UserID,
+				Action:     "role.changed",
+				TargetType: "user",
+				TargetID:   userID,
+				Reason:     reason,
+				Metadata:   map[string]string{"from": previous, "to": role},
+			})
+			return nil
+		})
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/secrets"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+const maxSecretRules = 50
+
+// textContentTypes are the attachment types checked for secrets besides
+// text/*.
+var textContentTypes = []string{"application/json", "application/xml", "application/x-yaml", "application/yaml", "application/x-pem-file"}
+
+// secretPolicyResponse is an organization's policy with the built-in rules
+// it builds on.
+type secretPolicyResponse struct {
+	models.SecretPolicy
+	BuiltinRules []models.SecretRule `json:"builtin_rules"`
+}
+
+// GetSecretPolicy returns the organization's secret detection policy.
+func GetSecretPolicy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, secretPolicyResponse{st.GetSecretPolicy(claims.OrgID), secrets.BuiltinRules})
+	}
+}
+
+// SetSecretPolicy replaces the organization's secret detection policy:
+// the mode (off, warn or block), its own rules and the built-in rules it
+// turns off.
+func SetSecretPolicy(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			Mode          string              `json:"mode"`
+			Rules         []models.SecretRule `json:"rules"`
+			DisabledRules []string            `json:"disabled_rules"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Mode != models.SecretsOff && req.Mode != models.SecretsWarn && req.Mode != models.SecretsBlock {
+			respondError(w, http.StatusBadRequest, "mode must be off, warn or block")
+			return
+		}
+		if len(req.Rules) > maxSecretRules {
+			respondErrorf(w, http.StatusBadRequest, "At most %d secret rules are allowed", maxSecretRules)
+			return
+		}
+		ids := make(map[string]bool)
+		for _, b := range secrets.BuiltinRules {
+			ids[b.ID] = true
+		}
+		for _, id := range req.DisabledRules {
+			if !ids[id] {
+				respondErrorf(w, http.StatusBadRequest, "Unknown built-in secret rule %q", id)
+				return
+			}
+		}
+		for _, rule := range req.Rules {
+			if rule.ID == "" || ids[rule.ID] {
+				respondErrorf(w, http.StatusBadRequest, "Secret rule IDs must be set and unique, including among built-in rules: %q", rule.ID)
+				return
+			}
+			ids[rule.ID] = true
+			if _, err := secrets.Compile(rule); err != nil || rule.MinEntropy < 0 {
+				respondErrorf(w, http.StatusBadRequest, "Secret rule %q needs a valid regular expression and a non-negative min_entropy", rule.ID)
+				return
+			}
+		}
+		if req.Rules == nil {
+			req.Rules = []models.SecretRule{}
+		}
+		if req.DisabledRules == nil {
+			req.DisabledRules = []string{}
+		}
+		policy := models.SecretPolicy{
+			OrgID:         claims.OrgID,
+			Mode:          req.Mode,
+			Rules:         req.Rules,
+			DisabledRules: req.DisabledRules,
+			UpdatedBy:     claims.UserID,
+			UpdatedAt:     time.Now(),
+		}
+		st.SetSecretPolicy(policy)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "secret_policy.updated",
+			TargetType: "org",
+			TargetID:   claims.OrgID,
+			Metadata:   map[string]string{"mode": policy.Mode, "rules": strconv.Itoa(len(policy.Rules))},
+		})
+		respondJSON(w, http.StatusOK, secretPolicyResponse{policy, secrets.BuiltinRules})
+	}
+}
+
+// secretDetector returns the organization's detector, or nil when
+// detection is off.
+func secretDetector(st *store.Memory, orgID string) (*secrets.Detector, models.SecretPolicy) {
+	policy := st.GetSecretPolicy(orgID)
+	if policy.Mode == models.SecretsOff {
+		return nil, policy
+	}
+	d, err := secrets.New(policy)
+	if err != nil {
+		// Rules are validated when the policy is set
+		return nil, policy
+	}
+	return d, policy
+}
+
+// respondSecretsBlocked rejects content with secrets, listing the findings.
+func respondSecretsBlocked(w http.ResponseWriter, findings []models.SecretFinding) {
+	respondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+		"error":    i18n.T(i18n.LangOf(w), "Content contains secrets; remove them and try again"),
+		"findings": findings,
+	})
+}
+
+// DetectSecrets checks the title, content and custom field values of a
+// review being created or updated for credentials. Per the organization's
+// policy, content with secrets is rejected with 422 or accepted with the
+// findings recorded on the review and counted in X-Secret-Findings.
+func DetectSecrets(st *store.Memory) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			claims, _ := middleware.GetClaims(r.Context())
+			detector, policy := secretDetector(st, claims.OrgID)
+			if detector == nil {
+				next(w, r)
+				return
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			var req struct {
+				Title        *string                `json:"title"`
+				Content      *string                `json:"content"`
+				CustomFields map[string]interface{} `json:"custom_fields"`
+			}
+			if err := json.Unmarshal(body, &req); err != nil {
+				next(w, r)
+				return
+			}
+
+			var sources []string
+			findings := []models.SecretFinding{}
+			scan := func(source, text string) {
+				sources = append(sources, source)
+				findings = append(findings, detector.Scan(source, text)...)
+			}
+			if req.Title != nil {
+				scan("title", *req.Title)
+			}
+			if req.Content != nil {
+				scan("content", *req.Content)
+			}
+			for key, v := range req.CustomFields {
+				if s, ok := v.(string); ok {
+					scan("fields."+key, s)
+				}
+			}
+			if len(findings) > 0 && policy.Mode == models.SecretsBlock {
+				respondSecretsBlocked(w, findings)
+				return
+			}
+
+			w.Header().Set("X-Secret-Findings", strconv.Itoa(len(findings)))
+			rec := &bodyRecorder{statusRecorder: statusRecorder{ResponseWriter: w}}
+			next(rec, r)
+			if !rec.succeeded() {
+				return
+			}
+			reviewID := mux.Vars(r)["id"]
+			if reviewID == "" {
+				var created struct {
+					ID string `json:"id"`
+				}
+				if err := json.Unmarshal(rec.body.Bytes(), &created); err != nil || created.ID == "" {
+					return
+				}
+				reviewID = created.ID
+			}
+			st.RecordSecretFindings(reviewID, sources, findings)
+		}
+	}
+}
+
+// scanAttachmentSecrets checks a text attachment for credentials. It
+// returns the findings and false if the upload must be rejected.
+func scanAttachmentSecrets(st *store.Memory, orgID, contentType string, data []byte) ([]models.SecretFinding, bool) {
+	if !strings.HasPrefix(contentType, "text/") && !slices.Contains(textContentTypes, contentType) {
+		return nil, true
+	}
+	detector, policy := secretDetector(st, orgID)
+	if detector == nil {
+		return nil, true
+	}
+	findings := detector.Scan("attachment", string(data))
+	return findings, len(findings) == 0 || policy.Mode != models.SecretsBlock
+}
+
+// ListSecretFindings returns the credentials found in the review's fields
+// and attachments.
+func ListSecretFindings(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListSecretFindings(review.ID))
+	}
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ObserveLogin wraps the login handler and runs anomaly detection on each
+// successful login. When the detector requires it, the issued token is held
+// back and the client gets a verification_id to complete with VerifyLogin.
+func ObserveLogin(d *security.Detector, st *store.Memory, jwtSecret string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			held := holdResponse(w)
+			next(held, r)
+			if held.status != 0 && (held.status < 200 || held.status >= 300) {
+				held.flush(w)
+				return
+			}
+
+			var issued struct {
+				Token string `json:"token"`
+			}
+			claims := &auth.Claims{}
+			if err := json.Unmarshal(held.body.Bytes(), &issued); err != nil || issued.Token == "" {
+				held.flush(w)
+				return
+			}
+			_, err := jwt.ParseWithClaims(issued.Token, claims, func(*jwt.Token) (interface{}, error) {
+				return []byte(jwtSecret), nil
+			})
+			if err != nil {
+				held.flush(w)
+				return
+			}
+
+			events := d.Observe(r, claims.UserID, claims.OrgID)
+			if !d.RequiresVerification(events) {
+				held.flush(w)
+				return
+			}
+			lang := i18n.Negotiate(st.GetProfile(claims.UserID).Locale, r.Header.Get("Accept-Language"))
+			id, err := d.Challenge(claims.Email, lang, issued.Token)
+			if err != nil {
+				log.Printf("Failed to send login verification to %s: %v", claims.UserID, err)
+				respondError(w, http.StatusInternalServerError, "Failed to send verification code")
+				return
+			}
+			respondJSON(w, http.StatusForbidden, map[string]string{
+				"error":           i18n.T(lang, "Sign-in from a new device or location must be verified; enter the code sent by email"),
+				"verification_id": id,
+			})
+		}
+	}
+}
+
+// CaptchaTokenHeader carries the challenge token on a login retry.
+const CaptchaTokenHeader = "X-Captcha-Token"
+
+// GuardLogin wraps the login handler with brute-force protection. After
+// repeated failures from an IP, logins from it must carry a solved
+// challenge in X-Captcha-Token; without one they get 428 and the widget to
+// render. If no challenge provider is configured the IP is refused with
+// 429 until its failures expire. A successful login only clears the IP's
+// failures against the account it signed in to.
+func GuardLogin(guard *security.LoginGuard, proxies security.TrustedProxies) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ip := security.ClientIP(r, proxies)
+			if blocked, until := guard.Blocked(ip); blocked {
+				challenger, siteKey := guard.Challenger()
+				if challenger == nil {
+					w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(until).Seconds())+1))
+					respondError(w, http.StatusTooManyRequests, "Too many failed logins; try again later")
+					return
+				}
+				token := r.Header.Get(CaptchaTokenHeader)
+				passed := false
+				if token != "" {
+					var err error
+					passed, err = challenger.Verify(r.Context(), token, ip)
+					if err != nil {
+						log.Printf("Login challenge verification failed: %v", err)
+					}
+				}
+				if !passed {
+					respondJSON(w, http.StatusPreconditionRequired, map[string]string{
+						"error":     i18n.T(i18n.Negotiate("", r.Header.Get("Accept-Language")), "Too many failed logins; complete the challenge and retry"),
+						"challenge": challenger.Provider(),
+						"site_key":  siteKey,
+					})
+					return
+				}
+			}
+
+			account, ok := loginAccount(w, r)
+			if !ok {
+				respondError(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+			rec := &statusRecorder{ResponseWriter: w}
+			next(rec, r)
+			switch {
+			case rec.status == http.StatusUnauthorized:
+				guard.Failed(ip, account)
+			case rec.succeeded():
+				guard.Succeeded(ip, account)
+			}
+		}
+	}
+}
+
+// loginAccount returns the normalized email a login request names, leaving
+// the body for the login handler to read.
+func loginAccount(w http.ResponseWriter, r *http.Request) (string, bool) {
+	rewind, err := keepBody(w, r)
+	if err != nil {
+		return "", false
+	}
+	defer rewind()
+	var req struct {
+		Email string `json:"email"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	return strings.ToLower(strings.TrimSpace(req.Email)), true
+}
+
+// VerifyLogin completes a login held back by ObserveLogin.
+func VerifyLogin(d *security.Detector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			VerificationID string `json:"verification_id"`
+			Code           string `json:"code"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		token, err := d.Verify(req.VerificationID, req.Code)
+		if err != nil {
+			respondError(w, http.StatusUnauthorized, "Invalid or expired verification code")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]string{"token": token})
+	}
+}
+
+// AdminListSecurityEvents returns recent suspicious logins, newest first.
+// Filter with ?org_id= and cap with ?limit= (default 100).
+func AdminListSecurityEvents(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := 100
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > 1000 {
+				respondError(w, http.StatusBadRequest, "limit must be between 1 and 1000")
+				return
+			}
+			limit = n
+		}
+		respondJSON(w, http.StatusOK, st.ListSecurityEvents(r.URL.Query().Get("org_id"), limit))
+	}
+}
+
+// heldResponse buffers a response so a wrapper can inspect it before
+// deciding whether to send it.
+type heldResponse struct {
+	// w is the writer the response is held back from. It is only written
+	// to by flush, but is unwrapped to reach what it carries, such as the
+	// response language.
+	w      http.ResponseWriter
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// holdResponse returns a heldResponse holding back a response from w.
+func holdResponse(w http.ResponseWriter) *heldResponse {
+	return &heldResponse{w: w, header: make(http.Header)}
+}
+
+func (h *heldResponse) Header() http.Header { return h.header }
+
+func (h *heldResponse) Unwrap() http.ResponseWriter { return h.w }
+
+func (h *heldResponse) WriteHeader(code int) {
+	if h.status == 0 {
+		h.status = code
+	}
+}
+
+func (h *heldResponse) Write(p []byte) (int, error) {
+	return h.body.Write(p)
+}
+
+func (h *heldResponse) flush(w http.ResponseWriter) {
+	for k, v := range h.header {
+		w.Header()[k] = v
+	}
+	if h.status == 0 {
+		h.status = http.StatusOK
+	}
+	w.WriteHeader(h.status)
+	w.Write(h.body.Bytes())
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+)
+
+func TestHeldResponseKeepsLanguage(t *testing.T) {
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{"en", "Review not found"},
+		{"es", "Revisión no encontrada"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			held := holdResponse(&i18n.Writer{ResponseWriter: rec, Lang: tt.lang})
+			respondError(held, http.StatusNotFound, "Review not found")
+			held.flush(rec)
+
+			if rec.Code != http.StatusNotFound {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body["error"] != tt.want {
+				t.Errorf("error = %q, want %q", body["error"], tt.want)
+			}
+		})
+	}
+}
+
+func TestGuardLoginCountsPerAccount(t *testing.T) {
+	guard := security.NewLoginGuard(nil, "", 4, time.Minute)
+	login := GuardLogin(guard, nil)(func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Email, Password string }
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Password != "right" {
+			respondError(w, http.StatusUnauthorized, "Invalid email or password")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]string{"token": "t"})
+	})
+
+	// The attacker holds mallory's account and guesses alice's password,
+	// signing in as mallory between guesses.
+	steps := []struct {
+		email, password string
+		want            int
+	}{
+		{"alice@acme.test", "guess-1", http.StatusUnauthorized},
+		{"mallory@acme.test", "right", http.StatusOK},
+		{"alice@acme.test", "guess-2", http.StatusUnauthorized},
+		{"Mallory@acme.test", "wrong", http.StatusUnauthorized},
+		{"mallory@acme.test", "right", http.StatusOK},
+		{"alice@acme.test", "guess-3", http.StatusUnauthorized},
+		{"alice@acme.test", "guess-4", http.StatusUnauthorized},
+		{"alice@acme.test", "guess-5", http.StatusTooManyRequests},
+		{"mallory@acme.test", "right", http.StatusTooManyRequests},
+	}
+	for i, step := range steps {
+		body := `{"email":"` + step.email + `","password":"` + step.password + `"}`
+		req := httptest.NewRequest("POST", "/login", strings.NewReader(body))
+		req.RemoteAddr = "198.51.100.9:4000"
+		rec := httptest.NewRecorder()
+		login(rec, req)
+		if rec.Code != step.want {
+			t.Fatalf("step %d (%s, %s): status = %d, want %d", i, step.email, step.password, rec.Code, step.want)
+		}
+	}
+}
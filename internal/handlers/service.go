@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+)
+
+// actorFrom builds the service actor from the request's JWT claims. On
+// failure it writes a 401 response and returns false.
+func actorFrom(w http.ResponseWriter, r *http.Request) (aurea.Actor, bool) {
+	claims, ok := middleware.GetClaims(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return aurea.Actor{}, false
+	}
+	return aurea.Actor{UserID: claims.UserID, OrgID: claims.OrgID, Guest: claims.Role == middleware.GuestRole}, true
+}
+
+// respondServiceError maps errors from the service layer to HTTP responses.
+func respondServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, aurea.ErrInvalid):
+		respondError(w, http.StatusBadRequest, strings.TrimPrefix(err.Error(), aurea.ErrInvalid.Error()+": "))
+	case errors.Is(err, aurea.ErrRejected):
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": strings.TrimPrefix(err.Error(), aurea.ErrRejected.Error()+": ")})
+	case errors.Is(err, aurea.ErrLocked):
+		respondError(w, http.StatusConflict, "Review is approved and cannot be modified; reopen it first")
+	case errors.Is(err, aurea.ErrNotApproved):
+		respondError(w, http.StatusConflict, "Review is not approved")
+	default:
+		respondStoreError(w, err)
+	}
+}
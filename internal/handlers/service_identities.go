@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// serviceIdentityRequest is the body of service identity writes. Scopes
+// are "METHOD /api/path-prefix" or "/api/path-prefix".
+type serviceIdentityRequest struct {
+	Name    string   `json:"name"`
+	Subject string   `json:"subject"`
+	OrgID   string   `json:"org_id"`
+	Role    string   `json:"role"`
+	Scopes  []string `json:"scopes"`
+}
+
+func (req serviceIdentityRequest) valid(w http.ResponseWriter) bool {
+	if req.Name == "" {
+		respondError(w, http.StatusBadRequest, "name is required")
+		return false
+	}
+	if !memberRoles[req.Role] || req.Role == middleware.GuestRole {
+		respondErrorf(w, http.StatusBadRequest, "invalid role %q", req.Role)
+		return false
+	}
+	for _, scope := range req.Scopes {
+		_, prefix, ok := strings.Cut(scope, " ")
+		if !ok {
+			prefix = scope
+		}
+		if !strings.HasPrefix(prefix, "/") {
+			respondErrorf(w, http.StatusBadRequest, "Invalid scope %q", scope)
+			return false
+		}
+	}
+	return true
+}
+
+// AdminListServiceIdentities returns the service identities allowed on the
+// mTLS listener, optionally only those of ?org_id=.
+func AdminListServiceIdentities(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, st.ListServiceIdentities(r.URL.Query().Get("org_id")))
+	}
+}
+
+// AdminCreateServiceIdentity maps a client certificate subject (CN or SAN)
+// to a service acting in an organization with a role and optional scopes.
+func AdminCreateServiceIdentity(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		var req serviceIdentityRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Subject == "" || req.OrgID == "" {
+			respondError(w, http.StatusBadRequest, "subject and org_id are required")
+			return
+		}
+		if !req.valid(w) {
+			return
+		}
+		if _, err := st.GetOrg(req.OrgID); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		svc, err := st.CreateServiceIdentity(models.ServiceIdentity{
+			Name:      req.Name,
+			Subject:   req.Subject,
+			OrgID:     req.OrgID,
+			Role:      req.Role,
+			Scopes:    req.Scopes,
+			CreatedBy: claims.UserID,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      svc.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "service_identity.created",
+			TargetType: "service_identity",
+			TargetID:   svc.ID,
+			Metadata:   map[string]string{"subject": svc.Subject, "role": svc.Role},
+		})
+		respondJSON(w, http.StatusCreated, svc)
+	}
+}
+
+// AdminGetServiceIdentity returns a service identity.
+func AdminGetServiceIdentity(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		svc, err := st.GetServiceIdentity(mux.Vars(r)["serviceId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, svc)
+	}
+}
+
+// AdminUpdateServiceIdentity changes a service identity's name, role and
+// scopes. Subject and organization cannot change; create a new identity.
+func AdminUpdateServiceIdentity(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		var req serviceIdentityRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !req.valid(w) {
+			return
+		}
+		id := mux.Vars(r)["serviceId"]
+		if current, err := st.GetServiceIdentity(id); err == nil &&
+			(req.Subject != "" && req.Subject != current.Subject || req.OrgID != "" && req.OrgID != current.OrgID) {
+			respondError(w, http.StatusConflict, "Service identity subject and organization cannot be changed")
+			return
+		}
+		svc, err := st.UpdateServiceIdentity(id, req.Name, req.Role, req.Scopes)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      svc.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "service_identity.updated",
+			TargetType: "service_identity",
+			TargetID:   svc.ID,
+			Metadata:   map[string]string{"role": svc.Role, "scopes": strings.Join(svc.Scopes, ",")},
+		})
+		respondJSON(w, http.StatusOK, svc)
+	}
+}
+
+// AdminDeleteServiceIdentity revokes a service identity; its certificate
+// is refused from then on.
+func AdminDeleteServiceIdentity(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		svc, err := st.GetServiceIdentity(mux.Vars(r)["serviceId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if err := st.DeleteServiceIdentity(svc.ID); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      svc.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "service_identity.deleted",
+			TargetType: "service_identity",
+			TargetID:   svc.ID,
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/tokenbind"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+)
+
+// startSession records the device a login token was issued to.
+func startSession(st *store.Memory, r *http.Request, token, userID, orgID string, expires time.Time, proxies security.TrustedProxies) {
+	now := time.Now()
+	st.AddSession(models.Session{
+		UserID:     userID,
+		OrgID:      orgID,
+		TokenHash:  tokenbind.TokenHash(token),
+		Device:     security.Fingerprint(r),
+		UserAgent:  r.UserAgent(),
+		IP:         security.ClientIP(r, proxies),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  expires,
+	})
+}
+
+// TrackSession wraps the login handler and starts a session for each
+// issued token, so the user can see and revoke their signed-in devices.
+func TrackSession(st *store.Memory, jwtSecret string, proxies security.TrustedProxies) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			held := holdResponse(w)
+			next(held, r)
+			if held.status != 0 && (held.status < 200 || held.status >= 300) {
+				held.flush(w)
+				return
+			}
+
+			var issued struct {
+				Token string `json:"token"`
+			}
+			claims := &auth.Claims{}
+			if err := json.Unmarshal(held.body.Bytes(), &issued); err == nil && issued.Token != "" {
+				if _, err := jwt.ParseWithClaims(issued.Token, claims, func(*jwt.Token) (interface{}, error) {
+					return []byte(jwtSecret), nil
+				}); err == nil {
+					expires := time.Now().Add(24 * time.Hour)
+					if claims.ExpiresAt != nil {
+						expires = claims.ExpiresAt.Time
+					}
+					startSession(st, r, issued.Token, claims.UserID, claims.OrgID, expires, proxies)
+				}
+			}
+			held.flush(w)
+		}
+	}
+}
+
+// ListMySessions returns the caller's signed-in devices, most recently
+// used first, marking the one the request was made with.
+func ListMySessions(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		current, _ := st.SessionByToken(tokenbind.TokenHash(token))
+		sessions := st.ListSessions(claims.UserID, time.Now())
+		for i := range sessions {
+			sessions[i].Current = sessions[i].ID == current.ID
+		}
+		respondJSON(w, http.StatusOK, sessions)
+	}
+}
+
+// RevokeMySession signs one of the caller's devices out; its token is
+// refused from then on.
+func RevokeMySession(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		s, err := st.EndSession(claims.UserID, mux.Vars(r)["sessionId"], models.SessionRevoked, time.Now())
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "session.revoked",
+			TargetType: "session",
+			TargetID:   s.ID,
+			Metadata:   map[string]string{"device": s.Device, "ip": s.IP},
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
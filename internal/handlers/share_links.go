@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+	"github.com/andres20980/aurea-orchestrator/internal/export"
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/sharelink"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultShareTTL = 7 * 24 * time.Hour
+	maxShareTTL     = 30 * 24 * time.Hour
+)
+
+// CreateShareLink issues a read-only link to the review for someone
+// without an account. The link expires after expires_in (default 7 days,
+// at most 30) and its URL is returned only in this response.
+func CreateShareLink(st *store.Memory, signer *sharelink.Signer, baseURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		claims, _ := middleware.GetClaims(r.Context())
+		var req struct {
+			ExpiresIn string `json:"expires_in"`
+			Note      string `json:"note"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		ttl := defaultShareTTL
+		if req.ExpiresIn != "" {
+			d, err := time.ParseDuration(req.ExpiresIn)
+			if err != nil || d <= 0 || d > maxShareTTL {
+				respondError(w, http.StatusBadRequest, "expires_in must be a duration of at most 720h")
+				return
+			}
+			ttl = d
+		}
+
+		now := time.Now()
+		link := st.CreateShareLink(models.ShareLink{
+			OrgID:     review.OrgID,
+			ReviewID:  review.ID,
+			Note:      req.Note,
+			CreatedBy: claims.UserID,
+			CreatedAt: now,
+			ExpiresAt: now.Add(ttl).Truncate(time.Second),
+		})
+		link.URL = strings.TrimRight(baseURL, "/") + "/shared/" + signer.Sign(link.ID, link.ExpiresAt)
+		respondJSON(w, http.StatusCreated, link)
+	}
+}
+
+// ListShareLinks returns the review's share links, including expired and
+// revoked ones, without their URLs.
+func ListShareLinks(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListShareLinks(review.ID))
+	}
+}
+
+// RevokeShareLink stops a share link from working before it expires.
+func RevokeShareLink(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		claims, _ := middleware.GetClaims(r.Context())
+		link, err := st.RevokeShareLink(review.ID, mux.Vars(r)["linkId"], claims.UserID, time.Now())
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, link)
+	}
+}
+
+// ListShareLinkAccesses returns when, and from where, a share link was used.
+func ListShareLinkAccesses(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		link, err := st.GetShareLink(mux.Vars(r)["linkId"])
+		if err != nil || link.ReviewID != review.ID {
+			respondError(w, http.StatusNotFound, "Not found")
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListShareAccesses(link.ID))
+	}
+}
+
+// SharedReview serves a review to the holder of a share link: as JSON by
+// default, or as a Markdown or PDF document with ?format=. Every use is
+// logged. Invalid, expired and revoked links all answer 404.
+func SharedReview(st *store.Memory, blobs blob.Store, signer *sharelink.Signer, proxies security.TrustedProxies) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("X-Robots-Tag", "noindex")
+
+		now := time.Now()
+		linkID, err := signer.Verify(mux.Vars(r)["token"], now)
+		if err != nil {
+			respondError(w, http.StatusNotFound, "Not found")
+			return
+		}
+		link, err := st.GetShareLink(linkID)
+		if err != nil || !link.Active(now) {
+			respondError(w, http.StatusNotFound, "Not found")
+			return
+		}
+		review, err := st.GetReview(link.ReviewID)
+		if err != nil {
+			respondError(w, http.StatusNotFound, "Not found")
+			return
+		}
+
+		formatParam := r.URL.Query().Get("format")
+		format := export.Format("json")
+		if formatParam != "" {
+			if format, err = export.ParseFormat(formatParam); err != nil {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		st.RecordShareAccess(models.ShareAccess{
+			LinkID:    link.ID,
+			At:        now,
+			IP:        security.ClientIP(r, proxies),
+			UserAgent: r.UserAgent(),
+			Format:    string(format),
+		})
+
+		lang := i18n.Negotiate("", r.Header.Get("Accept-Language"))
+		doc := reviewDocument(st, blobs, review, lang, format)
+		if formatParam == "" {
+			fields := make(map[string]string, len(doc.CustomFields))
+			for _, f := range doc.CustomFields {
+				fields[f.Name] = f.Value
+			}
+			respondJSON(w, http.StatusOK, map[string]interface{}{
+				"review":        review,
+				"checklist":     doc.Checklist,
+				"comments":      doc.Comments,
+				"approval":      doc.Approval,
+				"custom_fields": fields,
+				"expires_at":    link.ExpiresAt,
+			})
+			return
+		}
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="review-%s.%s"`, review.ID, format))
+		w.WriteHeader(http.StatusOK)
+		w.Write(export.Render(doc, format))
+	}
+}
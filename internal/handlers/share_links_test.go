@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/sharelink"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+func TestShareLinks(t *testing.T) {
+	st := store.NewMemory()
+	signer := sharelink.NewSigner("secret")
+	alice := newUser(t, st, "acme", "alice@acme.test", "reviewer", "")
+	outsider := newUser(t, st, "globex", "erin@globex.test", "admin", "")
+	now := time.Now()
+	st.SaveReview(&models.Review{ID: "r1", OrgID: "acme", Title: "Budget", Status: "pending", CreatedAt: now})
+	st.SaveReview(&models.Review{ID: "r2", OrgID: "acme", Title: "Other", Status: "pending", CreatedAt: now})
+
+	create := func(caller *models.User, reviewID, body string) (*httptest.ResponseRecorder, models.ShareLink) {
+		req := asUser(httptest.NewRequest("POST", "/", strings.NewReader(body)), caller)
+		req = mux.SetURLVars(req, map[string]string{"id": reviewID})
+		rec := httptest.NewRecorder()
+		CreateShareLink(st, signer, "https://aurea.example/")(rec, req)
+		var link models.ShareLink
+		json.Unmarshal(rec.Body.Bytes(), &link)
+		return rec, link
+	}
+	open := func(url string) int {
+		token := url[strings.LastIndex(url, "/")+1:]
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/shared/"+token, nil), map[string]string{"token": token})
+		rec := httptest.NewRecorder()
+		SharedReview(st, nil, signer, nil)(rec, req)
+		return rec.Code
+	}
+
+	t.Run("create", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			caller *models.User
+			body   string
+			want   int
+		}{
+			{"default expiry", alice, `{}`, http.StatusCreated},
+			{"short expiry", alice, `{"expires_in":"1h"}`, http.StatusCreated},
+			{"beyond the maximum", alice, `{"expires_in":"721h"}`, http.StatusBadRequest},
+			{"negative", alice, `{"expires_in":"-1h"}`, http.StatusBadRequest},
+			{"another org's review", outsider, `{}`, http.StatusNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec, link := create(tt.caller, "r1", tt.body)
+				if rec.Code != tt.want {
+					t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+				}
+				if tt.want == http.StatusCreated && !strings.HasPrefix(link.URL, "https://aurea.example/shared/") {
+					t.Errorf("URL = %q", link.URL)
+				}
+			})
+		}
+	})
+
+	_, kept := create(alice, "r1", `{}`)
+	_, revoked := create(alice, "r1", `{}`)
+	tampered := kept.URL[:strings.LastIndex(kept.URL, ".")] + ".forged"
+
+	t.Run("revoke", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			reviewID string
+			want     int
+		}{
+			{"through another review", "r2", http.StatusNotFound},
+			{"through its review", "r1", http.StatusOK},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := asUser(httptest.NewRequest("DELETE", "/", nil), alice)
+				req = mux.SetURLVars(req, map[string]string{"id": tt.reviewID, "linkId": revoked.ID})
+				rec := httptest.NewRecorder()
+				RevokeShareLink(st)(rec, req)
+				if rec.Code != tt.want {
+					t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+				}
+			})
+		}
+	})
+
+	t.Run("open", func(t *testing.T) {
+		tests := []struct {
+			name string
+			url  string
+			want int
+		}{
+			{"active", kept.URL, http.StatusOK},
+			{"revoked", revoked.URL, http.StatusNotFound},
+			{"tampered", tampered, http.StatusNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if got := open(tt.url); got != tt.want {
+					t.Errorf("status = %d, want %d", got, tt.want)
+				}
+			})
+		}
+		if n := len(st.ListShareAccesses(kept.ID)); n != 1 {
+			t.Errorf("%d accesses logged, want 1", n)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// joinOffer is an organization a new user may join by email domain.
+type joinOffer struct {
+	OrgID   string `json:"org_id"`
+	OrgName string `json:"org_name"`
+	Role    string `json:"role"`
+}
+
+// Signup creates an account for someone whose email domain an organization
+// has verified. With the auto join mode the account is created in that
+// organization at once; with suggest, the first call returns the offer and
+// the user accepts by calling again with its org_id. Anyone else needs an
+// invite from an admin. The password must meet the organization's password
+// policy. The account stays inactive, and outside the organization, until
+// the user follows the verification link emailed to them, which adds them
+// to it and returns the access token.
+func Signup(st *store.Memory, verifier *EmailVerifier, limiter *security.RateLimiter, breaches security.BreachChecker, proxies security.TrustedProxies) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ok, until := limiter.Allow(security.ClientIP(r, proxies)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(until).Seconds())+1))
+			respondError(w, http.StatusTooManyRequests, "Too many sign-ups; try again later")
+			return
+		}
+		var req struct {
+			Email    string `json:"email"`
+			Name     string `json:"name"`
+			Password string `json:"password"`
+			OrgID    string `json:"org_id"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		email := strings.TrimSpace(req.Email)
+		_, domain, found := strings.Cut(email, "@")
+		if _, err := mail.ParseAddress(email); err != nil || !found {
+			respondError(w, http.StatusBadRequest, "Invalid email address")
+			return
+		}
+
+		claim, ok := st.VerifiedDomain(strings.ToLower(domain))
+		if !ok {
+			respondError(w, http.StatusForbidden, "No organization accepts sign-ups from this email domain; ask an administrator for an invite")
+			return
+		}
+		org, err := st.GetOrg(claim.OrgID)
+		_, suspended := st.GetSuspension(claim.OrgID)
+		if err != nil || suspended {
+			respondError(w, http.StatusForbidden, "No organization accepts sign-ups from this email domain; ask an administrator for an invite")
+			return
+		}
+		if claim.JoinMode == models.JoinSuggest && req.OrgID != claim.OrgID {
+			respondJSON(w, http.StatusOK, map[string]interface{}{
+				"offers": []joinOffer{{OrgID: org.ID, OrgName: org.Name, Role: claim.DefaultRole}},
+			})
+			return
+		}
+
+		if !validPassword(w, r, st, breaches, claim.OrgID, req.Password) {
+			return
+		}
+		hash, err := security.HashPassword(req.Password)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+		user := &models.User{
+			Email:     email,
+			Name:      strings.TrimSpace(req.Name),
+			CreatedAt: time.Now(),
+		}
+		if err := st.CreateUser(user); err != nil {
+			if errors.Is(err, store.ErrDuplicate) {
+				respondError(w, http.StatusConflict, "email already registered")
+				return
+			}
+			respondStoreError(w, err)
+			return
+		}
+		st.SetPasswordHash(user.ID, hash)
+		st.SetEmailVerified(user.ID, false)
+		st.SetPendingMembership(user.ID, models.PendingMembership{OrgID: claim.OrgID, Role: claim.DefaultRole})
+
+		if err := verifier.Send(r, user, user.Email, models.VerifySignup); err != nil {
+			log.Printf("Failed to send sign-up verification to %s: %v", user.ID, err)
+		}
+		respondJSON(w, http.StatusCreated, map[string]interface{}{"user": user, "verification_required": true})
+	}
+}
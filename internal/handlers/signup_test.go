@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+func TestSignupJoinsOrgOnVerification(t *testing.T) {
+	st := store.NewMemory()
+	if err := st.CreateOrg(&models.Organization{ID: "acme", Name: "Acme"}, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.AddOrgDomain(models.OrgDomain{OrgID: "acme", Domain: "acme.test", JoinMode: models.JoinAuto, DefaultRole: "member"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.VerifyOrgDomain("acme", "acme.test", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	mail := &sentMail{}
+	verifier := &EmailVerifier{
+		Store:  st,
+		Mailer: mail,
+		Signer: security.NewTokenSigner("secret"),
+		Issuer: auth.NewService("secret", time.Hour),
+		TTL:    time.Hour,
+	}
+	signup := Signup(st, verifier, security.NewRateLimiter(10, time.Hour), nil, nil)
+
+	rec := httptest.NewRecorder()
+	signup(rec, httptest.NewRequest("POST", "/signup", strings.NewReader(`{"email":"alice@acme.test","name":"Alice","password":"correct horse battery"}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("signup status = %d: %s", rec.Code, rec.Body)
+	}
+	members := func() int { return len(st.ListOrgUsers("acme")) }
+	if n := members(); n != 0 {
+		t.Fatalf("org has %d members before verification", n)
+	}
+
+	_, token, _ := strings.Cut(mail.msgs[len(mail.msgs)-1].Body, "token=")
+	verify := func(token string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		verifier.Verify(rec, httptest.NewRequest("POST", "/auth/verify-email", strings.NewReader(`{"token":"`+token+`"}`)))
+		return rec
+	}
+	tests := []struct {
+		name    string
+		token   string
+		want    int
+		members int
+	}{
+		{"forged link", "nope", http.StatusUnauthorized, 0},
+		{"emailed link", strings.Fields(token)[0], http.StatusOK, 1},
+		{"link used again", strings.Fields(token)[0], http.StatusUnauthorized, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := verify(tt.token)
+			if rec.Code != tt.want {
+				t.Fatalf("verify status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if n := members(); n != tt.members {
+				t.Errorf("org has %d members, want %d", n, tt.members)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var resp struct{ User models.User }
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.User.OrgID != "acme" || resp.User.Role != "member" {
+				t.Errorf("user joined %q as %q", resp.User.OrgID, resp.User.Role)
+			}
+		})
+	}
+}
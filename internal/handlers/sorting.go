@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// parseSort reads ?sort=created_at or ?sort=updated_at, oldest first, or
+// newest first with a leading "-". On failure it writes a 400 response and
+// returns false.
+func parseSort(w http.ResponseWriter, r *http.Request) (store.Sort, bool) {
+	order, err := store.ParseSort(r.URL.Query().Get("sort"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "sort must be one of created_at, updated_at, optionally prefixed with -")
+		return store.Sort{}, false
+	}
+	return order, true
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+	"github.com/gorilla/mux"
+)
+
+// ListSubscriptions returns the caller's subscriptions.
+func ListSubscriptions(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		respondJSON(w, http.StatusOK, st.ListSubscriptions(claims.UserID))
+	}
+}
+
+// Subscribe watches a review, or all reviews matching a label and/or author.
+func Subscribe(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+
+		var req struct {
+			ReviewID string `json:"review_id"`
+			Label    string `json:"label"`
+			AuthorID string `json:"author_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		switch {
+		case req.ReviewID != "" && (req.Label != "" || req.AuthorID != ""):
+			respondError(w, http.StatusBadRequest, "review_id cannot be combined with label or author_id")
+			return
+		case req.ReviewID == "" && req.Label == "" && req.AuthorID == "":
+			respondError(w, http.StatusBadRequest, "review_id, label or author_id is required")
+			return
+		case req.ReviewID != "":
+			review, err := st.GetReview(req.ReviewID)
+			if err != nil || review.OrgID != claims.OrgID {
+				respondError(w, http.StatusNotFound, "Review not found")
+				return
+			}
+		}
+
+		sub := &models.Subscription{
+			UserID:    claims.UserID,
+			OrgID:     claims.OrgID,
+			ReviewID:  req.ReviewID,
+			Label:     req.Label,
+			AuthorID:  req.AuthorID,
+			CreatedAt: time.Now(),
+		}
+		st.AddSubscription(sub)
+		respondJSON(w, http.StatusCreated, sub)
+	}
+}
+
+// Unsubscribe removes one of the caller's subscriptions.
+func Unsubscribe(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		if err := st.RemoveSubscription(claims.UserID, mux.Vars(r)["subId"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ListNotifications returns the caller's notification inbox.
+func ListNotifications(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		respondJSON(w, http.StatusOK, st.ListNotifications(claims.UserID))
+	}
+}
+
+// SetReviewLabels replaces the labels of a review.
+func SetReviewLabels(reviews aurea.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := actorFrom(w, r)
+		if !ok {
+			return
+		}
+
+		var req struct {
+			Labels []string `json:"labels"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		if err := reviews.SetLabels(r.Context(), actor, mux.Vars(r)["id"], req.Labels); err != nil {
+			respondServiceError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string][]string{"labels": req.Labels})
+	}
+}
+
+// PublishOnSuccess wraps a review handler and publishes eventType to the
+// review's watchers once the wrapped handler reports success.
+func PublishOnSuccess(d *notify.Dispatcher, eventType string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w}
+			next(rec, r)
+			if !rec.succeeded() {
+				return
+			}
+			claims, _ := middleware.GetClaims(r.Context())
+			d.Publish(notify.Event{Type: eventType, ReviewID: mux.Vars(r)["id"], ActorID: claims.UserID})
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/ai"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+	"github.com/gorilla/mux"
+)
+
+// RequestSuggestions queues fresh AI suggestions of reviewers and labels
+// for the review, replacing its pending ones. New reviews get them
+// without asking. The body may pick a configured provider and model.
+func RequestSuggestions(st *store.Memory, suggester *ai.Suggester, providers *ai.Providers) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		c, model, ok := llmChoice(w, r, providers, review.OrgID)
+		if !ok {
+			return
+		}
+		respondOperation(w, suggester.Start(review, claims.UserID, c, model))
+	}
+}
+
+// ListSuggestions returns the review's AI suggestions, best first,
+// optionally only those with ?status= (pending, accepted or rejected).
+func ListSuggestions(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListSuggestions(review.ID, r.URL.Query().Get("status")))
+	}
+}
+
+// applySuggestion adds a suggested label to the review or assigns it to
+// a suggested reviewer.
+func applySuggestion(w http.ResponseWriter, r *http.Request, st *store.Memory, reviews aurea.ReviewService, s models.Suggestion) bool {
+	actor, ok := actorFrom(w, r)
+	if !ok {
+		return false
+	}
+	switch s.Kind {
+	case models.SuggestLabel:
+		labels := st.GetLabels(s.ReviewID)
+		if slices.Contains(labels, s.Value) {
+			return true
+		}
+		if err := reviews.SetLabels(r.Context(), actor, s.ReviewID, append(labels, s.Value)); err != nil {
+			respondServiceError(w, err)
+			return false
+		}
+	case models.SuggestReviewer:
+		reviewer, err := st.GetUser(s.Value)
+		if err != nil || reviewer.OrgID != s.OrgID {
+			respondError(w, http.StatusConflict, "Suggested reviewer is no longer a member of the organization")
+			return false
+		}
+		if _, inactive := st.GetDeactivation(reviewer.ID); inactive {
+			respondError(w, http.StatusConflict, "reviewer is deactivated")
+			return false
+		}
+		err = st.Assign(&models.Assignment{
+			ReviewID:   s.ReviewID,
+			ReviewerID: reviewer.ID,
+			AssignedBy: actor.UserID,
+			AssignedAt: time.Now(),
+		})
+		if err != nil {
+			respondStoreError(w, err)
+			return false
+		}
+	}
+	return true
+}
+
+// DecideSuggestion accepts or rejects one of the review's pending AI
+// suggestions. Accepting applies it: the label is added or the reviewer
+// assigned. Either way the decision tunes the organization's suggestion
+// weights.
+func DecideSuggestion(st *store.Memory, reviews aurea.ReviewService, status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := middleware.GetClaims(r.Context())
+		review, ok := loadReview(st, w, r)
+		if !ok {
+			return
+		}
+		s, err := st.GetSuggestion(review.ID, mux.Vars(r)["suggestionId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		if s.Status != models.SuggestionPending {
+			respondError(w, http.StatusConflict, "Suggestion already decided")
+			return
+		}
+		if status == models.SuggestionAccepted && !applySuggestion(w, r, st, reviews, s) {
+			return
+		}
+		s, err = st.DecideSuggestion(review.ID, s.ID, status, claims.UserID)
+		if errors.Is(err, store.ErrDecided) {
+			respondError(w, http.StatusConflict, "Suggestion already decided")
+			return
+		}
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, s)
+	}
+}
+
+// suggestionWeight is an organization's feedback on suggestions of one
+// value, with the weight it gives them.
+type suggestionWeight struct {
+	models.SuggestionFeedback
+	Weight float64 `json:"weight"`
+}
+
+// GetSuggestionWeights returns the weights the organization's feedback
+// gives AI suggestions, by reviewer and label. Values without feedback
+// weigh 1.
+func GetSuggestionWeights(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		weights := []suggestionWeight{}
+		for _, fb := range st.SuggestionFeedback(claims.OrgID) {
+			weights = append(weights, suggestionWeight{SuggestionFeedback: fb, Weight: ai.Weight(fb)})
+		}
+		respondJSON(w, http.StatusOK, weights)
+	}
+}
+
+// ResetSuggestionWeights forgets the organization's feedback on AI
+// suggestions.
+func ResetSuggestionWeights(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		st.ResetSuggestionFeedback(claims.OrgID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// maxTeamMembers bounds the members of a team.
+const maxTeamMembers = 1000
+
+type teamRequest struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
+
+// read decodes a team and checks that its members belong to the
+// organization.
+func (req *teamRequest) read(w http.ResponseWriter, r *http.Request, st *store.Memory, orgID string) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		respondError(w, http.StatusBadRequest, "name is required")
+		return false
+	}
+	if len(req.Members) > maxTeamMembers {
+		respondErrorf(w, http.StatusBadRequest, "A team can have at most %d members", maxTeamMembers)
+		return false
+	}
+	members := []string{}
+	for _, id := range req.Members {
+		if u, err := st.GetUser(id); err != nil || u.OrgID != orgID {
+			respondErrorf(w, http.StatusBadRequest, "User %q is not a member of the organization", id)
+			return false
+		}
+		if !slices.Contains(members, id) {
+			members = append(members, id)
+		}
+	}
+	req.Members = members
+	return true
+}
+
+// ListTeams returns the organization's teams.
+func ListTeams(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListTeams(claims.OrgID))
+	}
+}
+
+// CreateTeam reads {"name": "...", "members": [user IDs]} and adds a team
+// to the organization.
+func CreateTeam(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req teamRequest
+		if !req.read(w, r, st, claims.OrgID) {
+			return
+		}
+		now := time.Now()
+		team, err := st.CreateTeam(models.Team{OrgID: claims.OrgID, Name: req.Name, Members: req.Members, CreatedAt: now, UpdatedAt: now})
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "team.created",
+			TargetType: "team",
+			TargetID:   team.ID,
+			Metadata:   map[string]string{"name": team.Name},
+		})
+		respondJSON(w, http.StatusCreated, team)
+	}
+}
+
+// GetTeam returns one of the organization's teams.
+func GetTeam(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		team, err := st.GetTeam(claims.OrgID, mux.Vars(r)["teamId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, team)
+	}
+}
+
+// UpdateTeam renames one of the organization's teams and replaces its
+// members.
+func UpdateTeam(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		team, err := st.GetTeam(claims.OrgID, mux.Vars(r)["teamId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		var req teamRequest
+		if !req.read(w, r, st, claims.OrgID) {
+			return
+		}
+		team.Name, team.Members, team.UpdatedAt = req.Name, req.Members, time.Now()
+		if err := st.UpdateTeam(team); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "team.updated",
+			TargetType: "team",
+			TargetID:   team.ID,
+			Metadata:   map[string]string{"name": team.Name},
+		})
+		respondJSON(w, http.StatusOK, team)
+	}
+}
+
+// DeleteTeam removes one of the organization's teams.
+func DeleteTeam(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		id := mux.Vars(r)["teamId"]
+		if err := st.DeleteTeam(claims.OrgID, id); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "team.deleted",
+			TargetType: "team",
+			TargetID:   id,
+		})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/templates"
+	"github.com/gorilla/mux"
+)
+
+// importedTemplate reports what an import did to one template.
+type importedTemplate struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	Status  string `json:"status"`
+}
+
+// checkTemplateFields validates the custom field values review templates
+// prefill against the organization's field definitions.
+func checkTemplateFields(st *store.Memory, orgID string, b templates.Bundle) *templates.Invalid {
+	defs := make(map[string]*models.CustomField)
+	for _, f := range st.ListCustomFields(orgID) {
+		defs[f.Key] = f
+	}
+	for _, t := range b.Templates {
+		if t.Review == nil {
+			continue
+		}
+		for key, v := range t.Review.Fields {
+			f, ok := defs[key]
+			if !ok {
+				return &templates.Invalid{Format: "template %q sets unknown custom field %q", Args: []interface{}{t.Name, key}}
+			}
+			if !fieldValueValid(st, orgID, f, v) {
+				return &templates.Invalid{Format: "template %q has an invalid value for custom field %q", Args: []interface{}{t.Name, key}}
+			}
+		}
+	}
+	return nil
+}
+
+// respondImportError maps an importer error to a response.
+func respondImportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, templates.ErrNoCatalog):
+		respondError(w, http.StatusNotFound, "No template catalog is configured")
+	case errors.Is(err, templates.ErrNotInCatalog):
+		respondError(w, http.StatusNotFound, "Bundle not found in the catalog")
+	case errors.Is(err, templates.ErrUnsigned):
+		respondError(w, http.StatusUnprocessableEntity, "Bundle is not signed; set allow_unsigned to import it anyway")
+	case errors.Is(err, templates.ErrUntrusted), errors.Is(err, templates.ErrBadSignature):
+		respondError(w, http.StatusUnprocessableEntity, "Bundle signature is not from a trusted key")
+	default:
+		respondErrorf(w, http.StatusBadGateway, "Could not fetch bundle: %s", err.Error())
+	}
+}
+
+// ListTemplateCatalog returns the bundles of the official catalog.
+func ListTemplateCatalog(im *templates.Importer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entries, err := im.Catalog(r.Context())
+		if err != nil {
+			respondImportError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, entries)
+	}
+}
+
+// ImportTemplates reads {"url": "..."} or {"catalog": "name", "version":
+// "..."} and imports the bundle's templates into the organization. The
+// whole bundle is validated before anything is stored; each template
+// whose content changed gets a new version. Bundles from a URL must be
+// signed by a trusted key unless allow_unsigned is set.
+func ImportTemplates(st *store.Memory, im *templates.Importer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			URL           string `json:"url"`
+			Catalog       string `json:"catalog"`
+			Version       string `json:"version"`
+			AllowUnsigned bool   `json:"allow_unsigned"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if (req.URL == "") == (req.Catalog == "") {
+			respondError(w, http.StatusBadRequest, "Set exactly one of url and catalog")
+			return
+		}
+
+		var fetched *templates.Fetched
+		var err error
+		if req.Catalog != "" {
+			fetched, err = im.FromCatalog(r.Context(), req.Catalog, req.Version)
+		} else {
+			fetched, err = im.FromURL(r.Context(), req.URL, !req.AllowUnsigned)
+		}
+		if err != nil {
+			respondImportError(w, err)
+			return
+		}
+		invalid := templates.Validate(fetched.Bundle)
+		if invalid == nil {
+			invalid = checkTemplateFields(st, claims.OrgID, fetched.Bundle)
+		}
+		if invalid != nil {
+			respondErrorf(w, http.StatusUnprocessableEntity, invalid.Format, invalid.Args...)
+			return
+		}
+
+		now := time.Now()
+		results := make([]importedTemplate, 0, len(fetched.Bundle.Templates))
+		for _, spec := range fetched.Bundle.Templates {
+			t, added := st.AddTemplateVersion(models.Template{
+				TemplateSpec: spec,
+				OrgID:        claims.OrgID,
+				Digest:       templates.Digest(spec),
+				Source:       fetched.Source(),
+				ImportedBy:   claims.UserID,
+				ImportedAt:   now,
+			})
+			status := "unchanged"
+			switch {
+			case added && t.Version == 1:
+				status = "created"
+			case added:
+				status = "updated"
+			}
+			results = append(results, importedTemplate{Kind: t.Kind, Name: t.Name, Version: t.Version, Status: status})
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "templates.imported",
+			TargetType: "template_bundle",
+			TargetID:   fetched.Bundle.Name,
+			Metadata: map[string]string{
+				"version":  fetched.Bundle.Version,
+				"url":      fetched.URL,
+				"verified": strconv.FormatBool(fetched.Verified),
+			},
+		})
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"bundle":    fetched.Bundle.Name,
+			"version":   fetched.Bundle.Version,
+			"verified":  fetched.Verified,
+			"templates": results,
+		})
+	}
+}
+
+// ListTemplates returns the latest version of each of the organization's
+// templates, optionally only those of ?kind= (review or workflow).
+func ListTemplates(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListTemplates(claims.OrgID, r.URL.Query().Get("kind")))
+	}
+}
+
+// GetTemplate returns the latest version of a template, or the one in
+// ?version=.
+func GetTemplate(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		version := 0
+		if v := r.URL.Query().Get("version"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				respondError(w, http.StatusBadRequest, "version must be a positive integer")
+				return
+			}
+			version = n
+		}
+		vars := mux.Vars(r)
+		t, err := st.GetTemplate(claims.OrgID, vars["kind"], vars["name"], version)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, t)
+	}
+}
+
+// ListTemplateVersions returns every version of a template, oldest first.
+func ListTemplateVersions(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		vars := mux.Vars(r)
+		versions, err := st.TemplateVersions(claims.OrgID, vars["kind"], vars["name"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, versions)
+	}
+}
+
+// DeleteTemplate removes a template with all its versions.
+func DeleteTemplate(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		vars := mux.Vars(r)
+		if err := st.DeleteTemplate(claims.OrgID, vars["kind"], vars["name"]); err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
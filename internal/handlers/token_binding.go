@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/tokenbind"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// BindLogin wraps the login handler and binds the issued token to the
+// client when the user's organization requires it: to the TLS client
+// certificate, or to the key of the DPoP proof sent with the login
+// request. Logins without the required certificate or proof are refused.
+func BindLogin(st *store.Memory, proofs *tokenbind.Verifier, jwtSecret string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			held := holdResponse(w)
+			next(held, r)
+			if held.status != 0 && (held.status < 200 || held.status >= 300) {
+				held.flush(w)
+				return
+			}
+
+			var issued struct {
+				Token string `json:"token"`
+			}
+			claims := &auth.Claims{}
+			if err := json.Unmarshal(held.body.Bytes(), &issued); err != nil || issued.Token == "" {
+				held.flush(w)
+				return
+			}
+			if _, err := jwt.ParseWithClaims(issued.Token, claims, func(*jwt.Token) (interface{}, error) {
+				return []byte(jwtSecret), nil
+			}); err != nil {
+				held.flush(w)
+				return
+			}
+			mode := st.TokenBindingMode(claims.OrgID)
+			if mode == "" {
+				held.flush(w)
+				return
+			}
+
+			var thumbprint string
+			switch mode {
+			case tokenbind.ModeMTLS:
+				if thumbprint = tokenbind.RequestCertThumbprint(r); thumbprint == "" {
+					respondError(w, http.StatusUnauthorized, "This organization requires signing in with a client certificate")
+					return
+				}
+			case tokenbind.ModeDPoP:
+				var err error
+				if thumbprint, err = proofs.Verify(r, "", time.Now()); err != nil {
+					respondErrorf(w, http.StatusUnauthorized, "This organization requires a valid DPoP proof: %v", err)
+					return
+				}
+			}
+			expires := time.Now().Add(24 * time.Hour)
+			if claims.ExpiresAt != nil {
+				expires = claims.ExpiresAt.Time
+			}
+			st.BindToken(models.TokenBinding{
+				TokenHash:  tokenbind.TokenHash(issued.Token),
+				OrgID:      claims.OrgID,
+				UserID:     claims.UserID,
+				Mode:       mode,
+				Thumbprint: thumbprint,
+				ExpiresAt:  expires,
+			})
+			held.flush(w)
+		}
+	}
+}
+
+// GetTokenBinding returns how the organization's login tokens are bound.
+func GetTokenBinding(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]string{"mode": st.TokenBindingMode(claims.OrgID)})
+	}
+}
+
+// SetTokenBinding reads {"mode": "mtls"|"dpop"|""} and applies it to
+// tokens issued from then on.
+func SetTokenBinding(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req struct {
+			Mode string `json:"mode"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		switch req.Mode {
+		case "", tokenbind.ModeMTLS, tokenbind.ModeDPoP:
+		default:
+			respondError(w, http.StatusBadRequest, "mode must be mtls, dpop or empty")
+			return
+		}
+		st.SetTokenBindingMode(claims.OrgID, req.Mode)
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "token_binding.updated",
+			TargetType: "organization",
+			TargetID:   claims.OrgID,
+			Metadata:   map[string]string{"mode": req.Mode},
+		})
+		respondJSON(w, http.StatusOK, map[string]string{"mode": req.Mode})
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/tokenbind"
+)
+
+func TestBindLogin(t *testing.T) {
+	st := store.NewMemory()
+	tokens := auth.NewService("secret", time.Hour)
+	newUser(t, st, "acme", "alice@acme.test", "admin", "correct horse")
+	newUser(t, st, "globex", "erin@globex.test", "admin", "correct horse")
+	newUser(t, st, "initech", "peter@initech.test", "admin", "correct horse")
+	st.SetTokenBindingMode("acme", tokenbind.ModeMTLS)
+	st.SetTokenBindingMode("globex", tokenbind.ModeDPoP)
+	login := BindLogin(st, tokenbind.NewVerifier(), "secret")(Login(st, tokens))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	tests := []struct {
+		name      string
+		email     string
+		cert      *x509.Certificate
+		want      int
+		wantBound string
+	}{
+		{"not required", "peter@initech.test", nil, http.StatusOK, ""},
+		{"mTLS without a certificate", "alice@acme.test", nil, http.StatusUnauthorized, ""},
+		{"mTLS with a certificate", "alice@acme.test", cert, http.StatusOK, tokenbind.CertThumbprint(cert)},
+		{"DPoP without a proof", "erin@globex.test", nil, http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/login", strings.NewReader(`{"email":"`+tt.email+`","password":"correct horse"}`))
+			if tt.cert != nil {
+				req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{tt.cert}}
+			}
+			rec := httptest.NewRecorder()
+			login(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			var resp struct {
+				Token string `json:"token"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &resp)
+			if tt.want != http.StatusOK {
+				if resp.Token != "" {
+					t.Error("refused login returned a token")
+				}
+				return
+			}
+			b, bound := st.TokenBinding(tokenbind.TokenHash(resp.Token))
+			if bound != (tt.wantBound != "") || b.Thumbprint != tt.wantBound {
+				t.Errorf("binding = %+v, %v; want thumbprint %q", b, bound, tt.wantBound)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newToken returns a random 256-bit hex token for invites and one-time links.
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// GetUserStats returns a member's review activity: reviews authored and
+// approved, average turnaround and current workload. Users may read their
+// own; admins may read any member of their organization.
+func GetUserStats(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		userID := mux.Vars(r)["id"]
+		if userID != claims.UserID && claims.Role != "admin" {
+			respondError(w, http.StatusForbidden, "Forbidden: insufficient permissions")
+			return
+		}
+		stats, err := st.UserStats(userID)
+		if err == nil && stats.OrgID != claims.OrgID {
+			err = store.ErrNotFound
+		}
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, stats)
+	}
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	mailer "github.com/andres20980/aurea-orchestrator/internal/mail"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// decoyHash is checked against when the email is unknown, so a failed
+// login takes as long whether or not the account exists.
+var decoyHash = sync.OnceValue(func() string {
+	hash, _ := security.HashPassword("decoy")
+	return hash
+})
+
+// Login exchanges an email and password for an access token. Deactivated
+// accounts cannot sign in. The wrappers in main.go may hold the token back,
+// e.g. until a forced password change or an email verification.
+func Login(st *store.Memory, tokens *auth.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Email    string `json:"email"`
+			Password string `json:"password"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		hash, known := decoyHash(), false
+		user, err := st.GetUserByEmail(strings.TrimSpace(req.Email))
+		if err == nil {
+			if h, ok := st.PasswordHash(user.ID); ok {
+				hash, known = h, true
+			}
+		}
+		if !security.CheckPassword(hash, req.Password) || !known {
+			respondError(w, http.StatusUnauthorized, "Invalid email or password")
+			return
+		}
+		if _, inactive := st.GetDeactivation(user.ID); inactive {
+			respondError(w, http.StatusUnauthorized, "Unauthorized: account is deactivated")
+			return
+		}
+
+		token, err := tokens.GenerateToken(user.ID, user.Email, user.OrgID, user.Role)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]interface{}{"token": token, "user": user})
+	}
+}
+
+// GetCurrentUser returns the signed-in user.
+func GetCurrentUser(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetClaims(r.Context())
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		user, err := st.GetUser(claims.UserID)
+		if err != nil {
+			respondError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		respondJSON(w, http.StatusOK, user)
+	}
+}
+
+// GetOrgMembers lists the members of the caller's organization, ordered
+// by ?sort=.
+func GetOrgMembers(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		order, ok := parseSort(w, r)
+		if !ok {
+			return
+		}
+		users := st.ListOrgUsers(claims.OrgID)
+		if users == nil {
+			users = []*models.User{}
+		}
+		store.SortUsers(users, order)
+		respondJSON(w, http.StatusOK, users)
+	}
+}
+
+// AddOrgMember adds one member to the caller's organization the way a row
+// of an import is added: the account is created without a password and
+// the member is emailed an invite to choose one.
+func AddOrgMember(st *store.Memory, m mailer.Mailer, baseURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var row importRow
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&row); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if _, err := st.GetUserByEmail(strings.TrimSpace(row.Email)); err == nil {
+			respondError(w, http.StatusConflict, "email already registered")
+			return
+		}
+
+		result := importMember(st, m, baseURL, claims, i18n.LangOf(w), row)
+		if result.UserID == "" {
+			respondJSON(w, http.StatusBadRequest, map[string]string{"error": result.Error})
+			return
+		}
+		user, err := st.GetUser(result.UserID)
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      claims.OrgID,
+			ActorID:    claims.UserID,
+			Action:     "member.added",
+			TargetType: "user",
+			TargetID:   user.ID,
+			Metadata:   map[string]string{"role": user.Role},
+		})
+		resp := map[string]interface{}{"user": user}
+		if result.Error != "" {
+			// The account was created but the invite was not sent.
+			resp["invite_error"] = result.Error
+		}
+		respondJSON(w, http.StatusCreated, resp)
+	}
+}
+
+// RemoveOrgMember removes a member from the caller's organization and ends
+// their sessions. Admins cannot remove themselves.
+func RemoveOrgMember(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		userID := mux.Vars(r)["userId"]
+		if userID == claims.UserID {
+			respondError(w, http.StatusBadRequest, "You cannot remove yourself")
+			return
+		}
+		err := st.Update(func(tx *store.Tx) error {
+			if err := tx.DeleteUser(claims.OrgID, userID); err != nil {
+				return err
+			}
+			tx.EndUserSessions(userID, "member removed", time.Now())
+			tx.AppendAudit(&models.AuditEvent{
+				OrgID:      claims.OrgID,
+				ActorID:    claims.UserID,
+				Action:     "member.removed",
+				TargetType: "user",
+				TargetID:   userID,
+			})
+			return nil
+		})
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// newUser stores a user of orgID, with password if it is not empty.
+func newUser(t testing.TB, st *store.Memory, orgID, email, role, password string) *models.User {
+	t.Helper()
+	u := &models.User{Email: email, Role: role, OrgID: orgID, CreatedAt: time.Now()}
+	if err := st.CreateUser(u); err != nil {
+		t.Fatal(err)
+	}
+	if password != "" {
+		hash, err := security.HashPassword(password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		st.SetPasswordHash(u.ID, hash)
+	}
+	return u
+}
+
+// asUser returns r carrying the claims of u.
+func asUser(r *http.Request, u *models.User) *http.Request {
+	return r.WithContext(middleware.WithClaims(r.Context(), &auth.Claims{UserID: u.ID, Email: u.Email, OrgID: u.OrgID, Role: u.Role}))
+}
+
+func TestLogin(t *testing.T) {
+	st := store.NewMemory()
+	tokens := auth.NewService("secret", time.Hour)
+	alice := newUser(t, st, "acme", "alice@acme.test", "admin", "correct horse")
+	newUser(t, st, "acme", "invited@acme.test", "member", "")
+	gone := newUser(t, st, "acme", "gone@acme.test", "member", "correct horse")
+	st.Deactivate(&models.Deactivation{UserID: gone.ID})
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"valid", `{"email":"alice@acme.test","password":"correct horse"}`, http.StatusOK},
+		{"email case and spaces", `{"email":" Alice@ACME.test ","password":"correct horse"}`, http.StatusOK},
+		{"wrong password", `{"email":"alice@acme.test","password":"wrong"}`, http.StatusUnauthorized},
+		{"unknown email", `{"email":"bob@acme.test","password":"correct horse"}`, http.StatusUnauthorized},
+		{"no password set", `{"email":"invited@acme.test","password":""}`, http.StatusUnauthorized},
+		{"deactivated", `{"email":"gone@acme.test","password":"correct horse"}`, http.StatusUnauthorized},
+		{"bad body", `{`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Login(st, tokens)(rec, httptest.NewRequest("POST", "/login", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var resp struct {
+				Token string `json:"token"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &resp)
+			claims, err := tokens.ValidateToken(resp.Token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.UserID != alice.ID || claims.OrgID != "acme" || claims.Role != "admin" {
+				t.Errorf("claims = %+v", claims)
+			}
+		})
+	}
+}
+
+func TestOrgMembers(t *testing.T) {
+	st := store.NewMemory()
+	admin := newUser(t, st, "acme", "admin@acme.test", "admin", "")
+	member := newUser(t, st, "acme", "member@acme.test", "member", "")
+	outsider := newUser(t, st, "globex", "admin@globex.test", "admin", "")
+
+	t.Run("list", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			caller *models.User
+			want   int
+		}{
+			{"own org", member, http.StatusOK},
+			{"other org", outsider, http.StatusForbidden},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := mux.SetURLVars(asUser(httptest.NewRequest("GET", "/api/orgs/acme/members", nil), tt.caller), map[string]string{"id": "acme"})
+				rec := httptest.NewRecorder()
+				GetOrgMembers(st)(rec, req)
+				if rec.Code != tt.want {
+					t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+				}
+				if tt.want != http.StatusOK {
+					return
+				}
+				var users []models.User
+				json.Unmarshal(rec.Body.Bytes(), &users)
+				if len(users) != 2 {
+					t.Errorf("got %d members, want 2", len(users))
+				}
+			})
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			caller *models.User
+			org    string
+			userID string
+			want   int
+		}{
+			{"self", admin, "acme", admin.ID, http.StatusBadRequest},
+			{"from another org", outsider, "globex", member.ID, http.StatusNotFound},
+			{"member", admin, "acme", member.ID, http.StatusNoContent},
+			{"already removed", admin, "acme", member.ID, http.StatusNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := asUser(httptest.NewRequest("DELETE", "/", nil), tt.caller)
+				req = mux.SetURLVars(req, map[string]string{"id": tt.org, "userId": tt.userID})
+				rec := httptest.NewRecorder()
+				RemoveOrgMember(st)(rec, req)
+				if rec.Code != tt.want {
+					t.Errorf("status = %d, want %d", rec.Code, tt.want)
+				}
+			})
+		}
+		if _, err := st.GetUser(member.ID); err == nil {
+			t.Error("removed member is still stored")
+		}
+	})
+}
+
+// BenchmarkLogin measures a successful login, which is dominated by the
+// password hash comparison.
+func BenchmarkLogin(b *testing.B) {
+	st := store.NewMemory()
+	tokens := auth.NewService("secret", time.Hour)
+	newUser(b, st, "acme", "alice@acme.test", "admin", "correct horse")
+	login := Login(st, tokens)
+	body := `{"email":"alice@acme.test","password":"correct horse"}`
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rec := httptest.NewRecorder()
+		login(rec, httptest.NewRequest("POST", "/login", strings.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			b.Fatalf("status = %d: %s", rec.Code, rec.Body)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/webhooks"
+	"github.com/gorilla/mux"
+)
+
+// deliveryFilterRequest selects deliveries by webhook, status and creation
+// time range (RFC 3339, since inclusive, until exclusive).
+type deliveryFilterRequest struct {
+	WebhookID string `json:"webhook_id"`
+	Status    string `json:"status"`
+	Since     string `json:"since"`
+	Until     string `json:"until"`
+}
+
+func (req deliveryFilterRequest) filter(w http.ResponseWriter) (models.DeliveryFilter, bool) {
+	f := models.DeliveryFilter{WebhookID: req.WebhookID, Status: req.Status}
+	switch req.Status {
+	case "", models.DeliveryPending, models.DeliverySucceeded, models.DeliveryFailed:
+	default:
+		respondErrorf(w, http.StatusBadRequest, "Unknown delivery status %q", req.Status)
+		return f, false
+	}
+	var err error
+	if req.Since != "" {
+		if f.Since, err = time.Parse(time.RFC3339, req.Since); err != nil {
+			respondError(w, http.StatusBadRequest, "since must be an RFC 3339 time")
+			return f, false
+		}
+	}
+	if req.Until != "" {
+		if f.Until, err = time.Parse(time.RFC3339, req.Until); err != nil {
+			respondError(w, http.StatusBadRequest, "until must be an RFC 3339 time")
+			return f, false
+		}
+	}
+	return f, true
+}
+
+// ListWebhookDeliveries returns the organization's recorded deliveries,
+// filtered by ?webhook_id=, ?status= (e.g. failed), ?since= and ?until=.
+func ListWebhookDeliveries(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		q := r.URL.Query()
+		f, ok := deliveryFilterRequest{
+			WebhookID: q.Get("webhook_id"),
+			Status:    q.Get("status"),
+			Since:     q.Get("since"),
+			Until:     q.Get("until"),
+		}.filter(w)
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusOK, st.ListWebhookDeliveries(claims.OrgID, f))
+	}
+}
+
+// GetWebhookDelivery returns one delivery with its payload.
+func GetWebhookDelivery(st *store.Memory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		delivery, err := st.GetWebhookDelivery(claims.OrgID, mux.Vars(r)["deliveryId"])
+		if err != nil {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, delivery)
+	}
+}
+
+// RedeliverWebhook sends one delivery again and returns its outcome. A
+// failed attempt is still reported with 200; the record's status tells.
+func RedeliverWebhook(deliverer *webhooks.Deliverer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		delivery, err := deliverer.Redeliver(r.Context(), claims.OrgID, mux.Vars(r)["deliveryId"])
+		if errors.Is(err, store.ErrNotFound) {
+			respondStoreError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, delivery)
+	}
+}
+
+// RedeliverWebhooks queues redelivery of every delivery in a time range,
+// optionally narrowed to one webhook and a status, e.g. everything that
+// failed during a consumer outage.
+func RedeliverWebhooks(deliverer *webhooks.Deliverer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := requireOwnOrg(w, r)
+		if !ok {
+			return
+		}
+		var req deliveryFilterRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Since == "" || req.Until == "" {
+			respondError(w, http.StatusBadRequest, "since and until are required")
+			return
+		}
+		f, ok := req.filter(w)
+		if !ok {
+			return
+		}
+		if !f.Since.Before(f.Until) {
+			respondError(w, http.StatusBadRequest, "since must be before until")
+			return
+		}
+		respondJSON(w, http.StatusAccepted, map[string]int{"queued": deliverer.RedeliverAll(claims.OrgID, f)})
+	}
+}
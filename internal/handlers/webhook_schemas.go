@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/webhooks"
+	"github.com/gorilla/mux"
+)
+
+// webhookSchemaRef is one entry of the schema index.
+type webhookSchemaRef struct {
+	Event   string `json:"event"`
+	Version string `json:"version"`
+	URL     string `json:"url"`
+	Current bool   `json:"current"`
+}
+
+// ListWebhookSchemas lists the payload schema of every event and version,
+// so consumers can generate types for their language.
+func ListWebhookSchemas(baseURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var refs []webhookSchemaRef
+		for _, event := range notify.EventTypes {
+			for _, version := range webhooks.SchemaVersions {
+				refs = append(refs, webhookSchemaRef{
+					Event:   event,
+					Version: version,
+					URL:     webhooks.SchemaURL(baseURL, event, version),
+					Current: version == webhooks.SchemaVersion,
+				})
+			}
+		}
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		respondJSON(w, http.StatusOK, refs)
+	}
+}
+
+// GetWebhookSchema serves the JSON Schema of one event payload version.
+// Descriptions follow Accept-Language; published versions never change,
+// so responses are cacheable.
+func GetWebhookSchema(baseURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		lang := i18n.Negotiate("", r.Header.Get("Accept-Language"))
+		schema, ok := webhooks.Schema(baseURL, vars["event"], vars["version"], lang)
+		if !ok {
+			respondError(w, http.StatusNotFound, "Schema not found")
+			return
+		}
+		w.Header().Set("Content-Language", lang)
+		w.Header().Set("Vary", "Accept-Language")
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/schema+json")
+		w.WriteHeader(http.StatusOK)
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(schema)
+	}
+}
@@ -0,0 +1,59 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+// Breaker is a consecutive-failure circuit breaker. After Threshold failures
+// in a row it rejects calls for Cooldown, then lets a single probe through;
+// a successful probe closes it again.
+type Breaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// NewBreaker creates a breaker with the given threshold and cooldown.
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{Threshold: threshold, Cooldown: cooldown}
+}
+
+// Allow reports whether a call may proceed at now.
+func (b *Breaker) Allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.Threshold {
+		return true
+	}
+	if now.Before(b.openUntil) {
+		return false
+	}
+	// Half-open: push the window forward so only this call probes.
+	b.openUntil = now.Add(b.Cooldown)
+	return true
+}
+
+// Record reports the outcome of a call that Allow let through.
+func (b *Breaker) Record(ok bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ok {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.Threshold {
+		b.openUntil = now.Add(b.Cooldown)
+	}
+}
+
+// Open reports whether the breaker is currently rejecting calls.
+func (b *Breaker) Open(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures >= b.Threshold && now.Before(b.openUntil)
+}
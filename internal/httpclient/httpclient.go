@@ -0,0 +1,220 @@
+// Package httpclient provides the HTTP client used for third-party
+// integrations. Each integration gets its own retry policy, circuit breaker,
+// concurrency limit and metrics, so one slow provider cannot tie up the
+// goroutines serving API requests.
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCircuitOpen is returned while an integration's breaker is open.
+	ErrCircuitOpen = errors.New("httpclient: circuit open")
+	// ErrSaturated is returned when an integration has too many calls in flight.
+	ErrSaturated = errors.New("httpclient: too many requests in flight")
+)
+
+// Options configure an integration client. Zero values take the defaults.
+type Options struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// BaseDelay and MaxDelay bound the jittered exponential backoff.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// RetryUnsafe also retries non-idempotent methods. Only enable it for
+	// APIs that deduplicate, such as PagerDuty events.
+	RetryUnsafe bool
+	// BreakerThreshold consecutive failures open the breaker for BreakerCooldown.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// MaxInFlight caps concurrent requests; further calls fail fast.
+	MaxInFlight int
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxRetries == 0 {
+		o.MaxRetries = 2
+	}
+	if o.BaseDelay == 0 {
+		o.BaseDelay = 200 * time.Millisecond
+	}
+	if o.MaxDelay == 0 {
+		o.MaxDelay = 5 * time.Second
+	}
+	if o.BreakerThreshold == 0 {
+		o.BreakerThreshold = 5
+	}
+	if o.BreakerCooldown == 0 {
+		o.BreakerCooldown = 30 * time.Second
+	}
+	if o.MaxInFlight == 0 {
+		o.MaxInFlight = 32
+	}
+	return o
+}
+
+// Client wraps an *http.Client for one named integration.
+type Client struct {
+	name    string
+	base    *http.Client
+	opts    Options
+	breaker *Breaker
+	slots   chan struct{}
+
+	mu    sync.Mutex
+	stats Stats
+}
+
+// New creates the client for integration name on top of base and registers
+// it for Snapshot.
+func New(name string, base *http.Client, opts Options) *Client {
+	opts = opts.withDefaults()
+	c := &Client{
+		name:    name,
+		base:    base,
+		opts:    opts,
+		breaker: NewBreaker(opts.BreakerThreshold, opts.BreakerCooldown),
+		slots:   make(chan struct{}, opts.MaxInFlight),
+		stats:   Stats{Integration: name},
+	}
+	register(c)
+	return c
+}
+
+// HTTP returns an *http.Client whose requests go through c, for code that
+// expects a plain client.
+func (c *Client) HTTP() *http.Client {
+	return &http.Client{Transport: roundTripper{c}, Timeout: c.base.Timeout}
+}
+
+// Do sends req, retrying transport errors, 429 and 5xx responses with
+// jittered exponential backoff. Requests with a body are retried only if
+// the body can be replayed.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	select {
+	case c.slots <- struct{}{}:
+		defer func() { <-c.slots }()
+	default:
+		c.count(func(s *Stats) { s.Rejected++ })
+		return nil, fmt.Errorf("%w: %s", ErrSaturated, c.name)
+	}
+
+	retryable := c.opts.RetryUnsafe || isIdempotent(req.Method)
+	if req.Body != nil && req.GetBody == nil {
+		retryable = false
+	}
+
+	for attempt := 0; ; attempt++ {
+		if !c.breaker.Allow(time.Now()) {
+			c.count(func(s *Stats) { s.Rejected++ })
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, c.name)
+		}
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp, err := c.attempt(req)
+		failed := err != nil || shouldRetry(resp.StatusCode)
+		if !failed || !retryable || attempt >= c.opts.MaxRetries {
+			return resp, err
+		}
+
+		delay := c.backoff(attempt, resp)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		c.count(func(s *Stats) { s.Retries++ })
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) attempt(req *http.Request) (*http.Response, error) {
+	c.count(func(s *Stats) { s.Requests++; s.InFlight++ })
+	start := time.Now()
+	resp, err := c.base.Do(req)
+	elapsed := time.Since(start)
+
+	ok := err == nil && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests
+	c.breaker.Record(ok, time.Now())
+	c.count(func(s *Stats) {
+		s.InFlight--
+		s.totalLatency += elapsed
+		if !ok {
+			s.Failures++
+		}
+	})
+	return resp, err
+}
+
+// backoff returns the delay before retry attempt+1, honoring Retry-After.
+func (c *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			if d := time.Duration(secs) * time.Second; d <= c.opts.MaxDelay {
+				return d
+			}
+			return c.opts.MaxDelay
+		}
+	}
+	d := c.opts.BaseDelay << attempt
+	if d <= 0 || d > c.opts.MaxDelay {
+		d = c.opts.MaxDelay
+	}
+	// Full jitter keeps synchronized callers from retrying in lockstep.
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// Stats returns a copy of the client's counters.
+func (c *Client) Stats() Stats {
+	c.mu.Lock()
+	s := c.stats
+	c.mu.Unlock()
+	if s.Requests > 0 {
+		s.AvgLatency = float64(s.totalLatency.Milliseconds()) / float64(s.Requests)
+	}
+	s.CircuitOpen = c.breaker.Open(time.Now())
+	return s
+}
+
+func (c *Client) count(update func(*Stats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	update(&c.stats)
+}
+
+type roundTripper struct{ c *Client }
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt.c.Do(req)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func shouldRetry(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
@@ -0,0 +1,49 @@
+package httpclient
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Stats are the counters kept for one integration.
+type Stats struct {
+	Integration string  `json:"integration"`
+	Requests    int64   `json:"requests"`
+	Failures    int64   `json:"failures"`
+	Retries     int64   `json:"retries"`
+	Rejected    int64   `json:"rejected"`
+	InFlight    int64   `json:"in_flight"`
+	AvgLatency  float64 `json:"avg_latency_ms"`
+	CircuitOpen bool    `json:"circuit_open"`
+
+	totalLatency time.Duration
+}
+
+var registry = struct {
+	sync.Mutex
+	clients map[string]*Client
+}{clients: make(map[string]*Client)}
+
+func register(c *Client) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.clients[c.name] = c
+}
+
+// Snapshot returns the current stats of every integration client, sorted by name.
+func Snapshot() []Stats {
+	registry.Lock()
+	clients := make([]*Client, 0, len(registry.clients))
+	for _, c := range registry.clients {
+		clients = append(clients, c)
+	}
+	registry.Unlock()
+
+	out := make([]Stats, 0, len(clients))
+	for _, c := range clients {
+		out = append(out, c.Stats())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Integration < out[j].Integration })
+	return out
+}
@@ -0,0 +1,211 @@
+// Package i18n translates user-facing text. Messages are keyed by their
+// English source text, so untranslated messages fall back to English.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLang is used when no supported language is requested.
+const DefaultLang = "en"
+
+// Catalog supplies translations. Implement it to load messages from a
+// format other than the bundled JSON files.
+type Catalog interface {
+	Languages() []string
+	Lookup(lang, key string) (string, bool)
+}
+
+// MapCatalog is an in-memory catalog of language -> key -> message.
+type MapCatalog map[string]map[string]string
+
+// Languages returns the languages in the catalog, sorted.
+func (c MapCatalog) Languages() []string {
+	langs := make([]string, 0, len(c))
+	for lang := range c {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Lookup returns the translation of key in lang.
+func (c MapCatalog) Lookup(lang, key string) (string, bool) {
+	msg, ok := c[lang][key]
+	return msg, ok
+}
+
+// LoadJSON reads every <lang>.json file in fsys. Each file is a flat
+// object mapping English source text to its translation.
+func LoadJSON(fsys fs.FS) (MapCatalog, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	catalog := make(MapCatalog, len(files))
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", file, err)
+		}
+		catalog[strings.TrimSuffix(path.Base(file), ".json")] = messages
+	}
+	return catalog, nil
+}
+
+//go:embed locales/*.json
+var bundled embed.FS
+
+var (
+	mu      sync.RWMutex
+	current Catalog = mustLoadBundled()
+)
+
+func mustLoadBundled() Catalog {
+	sub, err := fs.Sub(bundled, "locales")
+	if err != nil {
+		panic(err)
+	}
+	catalog, err := LoadJSON(sub)
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+// SetCatalog replaces the active catalog.
+func SetCatalog(c Catalog) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = c
+}
+
+// T translates key into lang and formats it with args, if any.
+func T(lang, key string, args ...interface{}) string {
+	mu.RLock()
+	msg, ok := current.Lookup(lang, key)
+	mu.RUnlock()
+	if !ok || msg == "" {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Supported reports the language of the catalog matching tag, matching
+// on the base language when there is no exact match (es-MX -> es).
+func Supported(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", false
+	}
+	mu.RLock()
+	langs := current.Languages()
+	mu.RUnlock()
+
+	base := strings.SplitN(tag, "-", 2)[0]
+	for _, lang := range langs {
+		if strings.ToLower(lang) == tag {
+			return lang, true
+		}
+	}
+	for _, lang := range langs {
+		if strings.ToLower(lang) == base {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// Negotiate picks the response language: the user's saved locale if it is
+// supported, otherwise the best supported Accept-Language entry, otherwise
+// DefaultLang.
+func Negotiate(userLocale, acceptLanguage string) string {
+	if lang, ok := Supported(userLocale); ok {
+		return lang
+	}
+
+	type candidate struct {
+		tag string
+		q   float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		c := candidate{tag: fields[0], q: 1}
+		for _, param := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if q, err := strconv.ParseFloat(v, 64); err == nil {
+					c.q = q
+				}
+			}
+		}
+		if c.q > 0 {
+			candidates = append(candidates, c)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	for _, c := range candidates {
+		if lang, ok := Supported(c.tag); ok {
+			return lang
+		}
+	}
+	return DefaultLang
+}
+
+type ctxKey struct{}
+
+// WithLang returns a context carrying the response language.
+func WithLang(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, lang)
+}
+
+// FromContext returns the response language stored in ctx, or DefaultLang.
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(ctxKey{}).(string); ok {
+		return lang
+	}
+	return DefaultLang
+}
+
+// Writer tags a ResponseWriter with the response language so helpers that
+// only see the writer can localize their output.
+type Writer struct {
+	http.ResponseWriter
+	Lang string
+}
+
+// Unwrap returns the underlying ResponseWriter.
+func (w *Writer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// LangOf returns the language attached to w or any writer it wraps.
+func LangOf(w http.ResponseWriter) string {
+	for {
+		if lw, ok := w.(*Writer); ok {
+			return lw.Lang
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return DefaultLang
+		}
+		w = u.Unwrap()
+	}
+}
@@ -0,0 +1,167 @@
+// Package jira talks to the Jira REST API.
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client is a Jira REST API v2 client authenticated with an API token.
+type Client struct {
+	baseURL string
+	email   string
+	token   string
+	http    *http.Client
+}
+
+// NewClient creates a client for the Jira site at baseURL that sends its
+// requests through httpClient.
+func NewClient(baseURL, email, token string, httpClient *http.Client) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		email:   email,
+		token:   token,
+		http:    httpClient,
+	}
+}
+
+// IssueStatus returns the name of the issue's current workflow status.
+func (c *Client) IssueStatus(ctx context.Context, key string) (string, error) {
+	var issue struct {
+		Fields struct {
+			Status struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(key)+"?fields=status", nil, &issue); err != nil {
+		return "", err
+	}
+	return issue.Fields.Status.Name, nil
+}
+
+// IssueFields are the fields set when creating an issue.
+type IssueFields struct {
+	ProjectKey  string
+	IssueType   string
+	Summary     string
+	Description string
+	Components  []string
+}
+
+// CreateIssue creates an issue and returns its key.
+func (c *Client) CreateIssue(ctx context.Context, f IssueFields) (string, error) {
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": f.ProjectKey},
+		"issuetype":   map[string]string{"name": f.IssueType},
+		"summary":     f.Summary,
+		"description": f.Description,
+	}
+	if len(f.Components) > 0 {
+		fields["components"] = componentRefs(f.Components)
+	}
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/rest/api/2/issue", map[string]interface{}{"fields": fields}, &created); err != nil {
+		return "", err
+	}
+	return created.Key, nil
+}
+
+// SetComponents replaces the components of an issue.
+func (c *Client) SetComponents(ctx context.Context, key string, components []string) error {
+	body := map[string]interface{}{"fields": map[string]interface{}{"components": componentRefs(components)}}
+	return c.do(ctx, http.MethodPut, "/rest/api/2/issue/"+url.PathEscape(key), body, nil)
+}
+
+// TransitionTo moves an issue to the named status using whichever
+// available transition leads there. It is a no-op if the issue is already
+// in that status.
+func (c *Client) TransitionTo(ctx context.Context, key, status string) error {
+	current, err := c.IssueStatus(ctx, key)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(current, status) {
+		return nil
+	}
+
+	var list struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/transitions"
+	if err := c.do(ctx, http.MethodGet, path, nil, &list); err != nil {
+		return err
+	}
+	for _, t := range list.Transitions {
+		if strings.EqualFold(t.To.Name, status) || strings.EqualFold(t.Name, status) {
+			body := map[string]interface{}{"transition": map[string]string{"id": t.ID}}
+			return c.do(ctx, http.MethodPost, path, body, nil)
+		}
+	}
+	return fmt.Errorf("jira: no transition from %q to %q on %s", current, status, key)
+}
+
+func componentRefs(names []string) []map[string]string {
+	refs := make([]map[string]string, len(names))
+	for i, n := range names {
+		refs[i] = map[string]string{"name": n}
+	}
+	return refs
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	req, err := newRequest(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.email, c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("jira %s %s: unexpected status %d", method, path, resp.StatusCode)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func newRequest(ctx context.Context, method, target string, body interface{}) (*http.Request, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
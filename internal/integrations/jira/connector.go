@@ -0,0 +1,205 @@
+package jira
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// SyncActor is the actor ID recorded for changes mirrored from Jira. Events
+// from this actor are not sent back to Jira, which prevents sync loops.
+const SyncActor = "jira-sync"
+
+// ErrBadSignature is returned for webhooks that fail signature verification.
+var ErrBadSignature = errors.New("jira: invalid webhook signature")
+
+// Connector keeps reviews and Jira issues in sync for organizations that
+// have configured it: it opens an issue per new review, pushes status and
+// label changes to Jira, and applies Jira status changes received by webhook.
+type Connector struct {
+	st         *store.Memory
+	dispatcher *notify.Dispatcher
+	http       *http.Client
+	timeout    time.Duration
+}
+
+// NewConnector creates a connector that calls Jira through httpClient and
+// subscribes it to review events.
+func NewConnector(st *store.Memory, d *notify.Dispatcher, httpClient *http.Client) *Connector {
+	c := &Connector{st: st, dispatcher: d, http: httpClient, timeout: 15 * time.Second}
+	d.Listen(c.handleEvent)
+	return c
+}
+
+func (c *Connector) handleEvent(e notify.Event) {
+	if e.ActorID == SyncActor {
+		return
+	}
+	switch e.Type {
+	case notify.ReviewCreated, notify.ReviewUpdated, notify.ReviewApproved, notify.ReviewReopened, notify.ReviewLabeled:
+	default:
+		return
+	}
+
+	review, err := c.st.GetReview(e.ReviewID)
+	if err != nil {
+		return
+	}
+	cfg, ok := c.st.GetJiraConfig(review.OrgID)
+	if !ok || !cfg.Enabled {
+		return
+	}
+
+	// Jira calls can be slow; never hold up the API request that caused the event.
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		defer cancel()
+		if err := c.push(ctx, cfg, review, e.Type); err != nil {
+			log.Printf("Jira sync for review %s (%s) failed: %v", review.ID, e.Type, err)
+		}
+	}()
+}
+
+func (c *Connector) push(ctx context.Context, cfg models.JiraConfig, review *models.Review, eventType string) error {
+	client := NewClient(cfg.BaseURL, cfg.Email, cfg.APIToken, c.http)
+	components := c.components(cfg, review.ID)
+
+	key := c.issueKey(review.ID)
+	if key == "" {
+		if eventType != notify.ReviewCreated {
+			return nil
+		}
+		issueType := cfg.IssueType
+		if issueType == "" {
+			issueType = "Task"
+		}
+		created, err := client.CreateIssue(ctx, IssueFields{
+			ProjectKey:  cfg.ProjectKey,
+			IssueType:   issueType,
+			Summary:     review.Title,
+			Description: review.Content,
+			Components:  components,
+		})
+		if err != nil {
+			return err
+		}
+		c.st.AddLink(&models.ExternalLink{
+			ReviewID:  review.ID,
+			OrgID:     review.OrgID,
+			URL:       strings.TrimRight(cfg.BaseURL, "/") + "/browse/" + created,
+			Provider:  models.ProviderJira,
+			Key:       created,
+			CreatedBy: SyncActor,
+			CreatedAt: time.Now(),
+		})
+		return nil
+	}
+
+	if eventType == notify.ReviewLabeled {
+		return client.SetComponents(ctx, key, components)
+	}
+	if status, ok := cfg.StatusToJira[review.Status]; ok {
+		return client.TransitionTo(ctx, key, status)
+	}
+	return nil
+}
+
+// issueKey returns the key of the Jira issue created for a review.
+func (c *Connector) issueKey(reviewID string) string {
+	for _, l := range c.st.ListLinks(reviewID) {
+		if l.Provider == models.ProviderJira && l.CreatedBy == SyncActor {
+			return l.Key
+		}
+	}
+	return ""
+}
+
+func (c *Connector) components(cfg models.JiraConfig, reviewID string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, label := range c.st.GetLabels(reviewID) {
+		if name, ok := cfg.LabelComponents[label]; ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// VerifySignature checks the X-Hub-Signature header ("sha256=<hex>") that
+// Jira sends with webhooks configured with a secret.
+func VerifySignature(secret string, body []byte, header string) error {
+	if secret == "" {
+		return fmt.Errorf("%w: no webhook secret configured", ErrBadSignature)
+	}
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return ErrBadSignature
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return ErrBadSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// WebhookEvent is the subset of a Jira issue webhook payload the connector uses.
+type WebhookEvent struct {
+	WebhookEvent string `json:"webhookEvent"`
+	Issue        struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Status struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	} `json:"issue"`
+}
+
+// ApplyWebhook mirrors a Jira status change onto the linked review. Changes
+// that would approve a review, or that target an approved review, are
+// ignored: approval must go through the orchestrator so it is signed.
+func (c *Connector) ApplyWebhook(orgID string, ev WebhookEvent) error {
+	cfg, ok := c.st.GetJiraConfig(orgID)
+	if !ok || !cfg.Enabled || ev.WebhookEvent != "jira:issue_updated" {
+		return nil
+	}
+	status, ok := cfg.StatusFromJira[ev.Issue.Fields.Status.Name]
+	if !ok || status == "approved" {
+		return nil
+	}
+
+	for _, link := range c.st.FindLinksByKey(orgID, ev.Issue.Key) {
+		review, err := c.st.GetReview(link.ReviewID)
+		if err != nil || review.Status == status || review.Status == "approved" {
+			continue
+		}
+		if _, approved := c.st.GetApproval(review.ID); approved {
+			continue
+		}
+		if err := c.st.SetReviewStatus(review.ID, status); err != nil {
+			return err
+		}
+		c.st.UpdateLinkStatus(link.ID, ev.Issue.Fields.Status.Name, time.Now())
+		c.dispatcher.Publish(notify.Event{Type: notify.ReviewUpdated, ReviewID: review.ID, ActorID: SyncActor})
+	}
+	return nil
+}
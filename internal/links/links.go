@@ -0,0 +1,50 @@
+// Package links recognizes external issue tracker URLs.
+package links
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+var (
+	jiraKey   = regexp.MustCompile(`^/browse/([A-Z][A-Z0-9_]+-\d+)/?$`)
+	linearKey = regexp.MustCompile(`^/[^/]+/issue/([A-Z][A-Z0-9]*-\d+)(/|$)`)
+	githubKey = regexp.MustCompile(`^/([^/]+)/([^/]+)/(issues|pull)/(\d+)/?$`)
+)
+
+// Detect parses an issue URL and returns its provider and issue key.
+// Unrecognized http(s) URLs are accepted with ProviderOther and no key.
+func Detect(raw string) (provider, key string, err error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", "", errors.New("url must be an absolute http(s) URL")
+	}
+
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "github.com":
+		if m := githubKey.FindStringSubmatch(u.Path); m != nil {
+			return models.ProviderGitHub, m[1] + "/" + m[2] + "#" + m[4], nil
+		}
+	case host == "linear.app":
+		if m := linearKey.FindStringSubmatch(u.Path); m != nil {
+			return models.ProviderLinear, m[1], nil
+		}
+	default:
+		// Jira Cloud lives on *.atlassian.net; self-hosted Jira is
+		// recognized by its /browse/KEY-123 path.
+		if m := jiraKey.FindStringSubmatch(u.Path); m != nil {
+			return models.ProviderJira, m[1], nil
+		}
+	}
+	return models.ProviderOther, "", nil
+}
+
+// NormalizeKey makes issue keys comparable for filtering.
+func NormalizeKey(key string) string {
+	return strings.ToUpper(strings.TrimSpace(key))
+}
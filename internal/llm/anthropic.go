@@ -0,0 +1,97 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// anthropicVersion is the Messages API version requests are written for.
+const anthropicVersion = "2023-06-01"
+
+// defaultMaxTokens is sent when a request sets no limit, which the
+// Anthropic API requires.
+const defaultMaxTokens = 1024
+
+// Anthropic completes prompts with the Anthropic Messages API.
+type Anthropic struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	model   string
+}
+
+// NewAnthropic creates a provider for the API at baseURL (default
+// https://api.anthropic.com) that uses model (default claude-3-5-haiku-latest) unless a
+// request names another.
+func NewAnthropic(client *http.Client, baseURL, apiKey, model string) *Anthropic {
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com"
+	}
+	if model == "" {
+		model = "claude-3-5-haiku-latest"
+	}
+	return &Anthropic{client: client, baseURL: strings.TrimRight(baseURL, "/"), apiKey: apiKey, model: model}
+}
+
+// Name implements Provider.
+func (a *Anthropic) Name() string {
+	return "anthropic"
+}
+
+// Complete implements Provider. The API has no JSON mode; prompts that
+// want JSON must ask for it.
+func (a *Anthropic) Complete(ctx context.Context, req Request) (Response, error) {
+	model := req.Model
+	if model == "" {
+		model = a.model
+	}
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = defaultMaxTokens
+	}
+	body := map[string]interface{}{
+		"model":      model,
+		"max_tokens": maxTokens,
+		"system":     req.System,
+		"messages":   []map[string]string{{"role": "user", "content": req.Prompt}},
+	}
+	var out struct {
+		Model   string `json:"model"`
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := postJSON(ctx, a.client, a.baseURL+"/v1/messages", a.header(), body, &out); err != nil {
+		return Response{}, err
+	}
+	if out.Model == "" {
+		out.Model = model
+	}
+	var text strings.Builder
+	for _, c := range out.Content {
+		if c.Type == "text" {
+			text.WriteString(c.Text)
+		}
+	}
+	return Response{
+		Text:         text.String(),
+		Model:        out.Model,
+		InputTokens:  out.Usage.InputTokens,
+		OutputTokens: out.Usage.OutputTokens,
+	}, nil
+}
+
+// Health checks that the API accepts the key by listing its models.
+func (a *Anthropic) Health(ctx context.Context) error {
+	return getOK(ctx, a.client, a.baseURL+"/v1/models", a.header())
+}
+
+func (a *Anthropic) header() http.Header {
+	return http.Header{"X-Api-Key": {a.apiKey}, "Anthropic-Version": {anthropicVersion}}
+}
@@ -0,0 +1,45 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// defaultAzureVersion is the Azure OpenAI API version used when none is
+// configured.
+const defaultAzureVersion = "2024-06-01"
+
+// Azure completes prompts with a model deployed to an Azure OpenAI
+// resource.
+type Azure struct {
+	client     *http.Client
+	endpoint   string
+	apiKey     string
+	deployment string
+	version    string
+}
+
+// NewAzure creates a provider for the deployment of the resource at
+// endpoint (e.g. https://example.openai.azure.com), calling API version
+// version (default 2024-06-01). Azure routes on the deployment, so
+// requests cannot choose another model.
+func NewAzure(client *http.Client, endpoint, apiKey, deployment, version string) *Azure {
+	if version == "" {
+		version = defaultAzureVersion
+	}
+	return &Azure{client: client, endpoint: strings.TrimRight(endpoint, "/"), apiKey: apiKey, deployment: deployment, version: version}
+}
+
+// Name implements Provider.
+func (a *Azure) Name() string {
+	return "azure"
+}
+
+// Complete implements Provider.
+func (a *Azure) Complete(ctx context.Context, req Request) (Response, error) {
+	u := a.endpoint + "/openai/deployments/" + url.PathEscape(a.deployment) +
+		"/chat/completions?api-version=" + url.QueryEscape(a.version)
+	return chatCompletion(ctx, a.client, u, http.Header{"Api-Key": {a.apiKey}}, a.deployment, req)
+}
@@ -0,0 +1,61 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// FromConfig creates the provider an organization configured, sending
+// requests through client.
+func FromConfig(client *http.Client, cfg models.LLMProviderConfig) (Provider, error) {
+	var p Provider
+	switch cfg.Type {
+	case models.LLMOpenAI:
+		p = NewOpenAI(client, cfg.BaseURL, cfg.APIKey, cfg.Model)
+	case models.LLMAnthropic:
+		p = NewAnthropic(client, cfg.BaseURL, cfg.APIKey, cfg.Model)
+	case models.LLMAzure:
+		if cfg.BaseURL == "" || cfg.Model == "" {
+			return nil, fmt.Errorf("azure provider %q needs base_url and model (the deployment)", cfg.Name)
+		}
+		p = NewAzure(client, cfg.BaseURL, cfg.APIKey, cfg.Model, cfg.APIVersion)
+	case models.LLMOllama:
+		p = NewOllama(client, cfg.BaseURL, cfg.Model)
+	case models.LLMLocal:
+		if cfg.BaseURL == "" || cfg.Model == "" {
+			return nil, fmt.Errorf("local provider %q needs base_url and model", cfg.Name)
+		}
+		p = NewLocal(client, cfg.BaseURL, cfg.APIKey, cfg.Model)
+	default:
+		return nil, fmt.Errorf("unknown provider type %q", cfg.Type)
+	}
+	return WithName(p, cfg.Name), nil
+}
+
+// WithName returns p under another name.
+func WithName(p Provider, name string) Provider {
+	if name == "" || name == p.Name() {
+		return p
+	}
+	return named{Provider: p, name: name}
+}
+
+type named struct {
+	Provider
+	name string
+}
+
+func (n named) Name() string {
+	return n.name
+}
+
+func (n named) SelfHosted() bool {
+	return SelfHosted(n.Provider)
+}
+
+func (n named) Health(ctx context.Context) error {
+	return CheckHealth(ctx, n.Provider)
+}
@@ -0,0 +1,201 @@
+// Package llm sends prompts to large language model providers behind a
+// common interface, so AI features do not depend on one vendor.
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// maxResponseSize bounds the provider responses read.
+const maxResponseSize = 4 << 20
+
+// ErrNoProvider is returned when no provider is configured, or the one
+// asked for is not.
+var ErrNoProvider = errors.New("llm: no such provider configured")
+
+// ErrUnavailable is returned when a provider cannot be reached or its
+// answer cannot be read. The cause is logged rather than returned, as it
+// may describe the provider's network.
+var ErrUnavailable = errors.New("llm: provider unavailable")
+
+// StatusError is returned when a provider answers with an HTTP error. The
+// body is logged rather than returned, as it may echo internal details.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("llm: provider answered %d %s", e.Code, http.StatusText(e.Code))
+}
+
+// ErrNoHealthCheck is returned by CheckHealth for providers that cannot
+// be checked.
+var ErrNoHealthCheck = errors.New("llm: provider has no health check")
+
+// Request is a single-turn prompt.
+type Request struct {
+	// Model overrides the provider's default model when set.
+	Model     string
+	System    string
+	Prompt    string
+	MaxTokens int
+	// JSON asks for a JSON object as the answer, where the provider
+	// supports it.
+	JSON bool
+}
+
+// Response is a provider's answer with the tokens it used.
+type Response struct {
+	Text         string
+	Model        string
+	InputTokens  int
+	OutputTokens int
+	// Provider names the provider that answered, when it is not the one
+	// asked, such as when a request failed over to another.
+	Provider string
+}
+
+// Provider completes prompts.
+type Provider interface {
+	// Name identifies the provider, e.g. in usage records.
+	Name() string
+	Complete(ctx context.Context, req Request) (Response, error)
+}
+
+// SelfHosted reports whether p serves models on infrastructure the
+// operator runs, rather than a vendor's, which providers tell by
+// implementing SelfHosted() bool.
+func SelfHosted(p Provider) bool {
+	h, ok := p.(interface{ SelfHosted() bool })
+	return ok && h.SelfHosted()
+}
+
+// CheckHealth asks p whether it can serve requests, for providers that
+// can tell by implementing Health(ctx) error.
+func CheckHealth(ctx context.Context, p Provider) error {
+	c, ok := p.(interface{ Health(context.Context) error })
+	if !ok {
+		return ErrNoHealthCheck
+	}
+	return c.Health(ctx)
+}
+
+// Registry holds the configured providers in order of preference.
+type Registry struct {
+	providers []Provider
+}
+
+// Add registers p after the providers already registered.
+func (r *Registry) Add(p Provider) {
+	r.providers = append(r.providers, p)
+}
+
+// Names lists the registered providers in order of preference.
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.providers))
+	for i, p := range r.providers {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// Get returns the provider called name, or the preferred one when name is
+// empty.
+func (r *Registry) Get(name string) (Provider, error) {
+	for _, p := range r.providers {
+		if name == "" || p.Name() == name {
+			return p, nil
+		}
+	}
+	return nil, ErrNoProvider
+}
+
+// getOK sends a GET to url and checks that it succeeds.
+func getOK(ctx context.Context, client *http.Client, url string, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+	_, err = send(client, req)
+	return err
+}
+
+// postJSON sends body to url and decodes the JSON answer into out.
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	raw, err := send(client, req)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		slog.WarnContext(ctx, "llm provider response not understood", "host", req.URL.Host, "error", err)
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// send sends req and returns the body of a successful answer. Failures
+// are logged in full and returned as ErrUnavailable or a StatusError,
+// which do not carry what the provider or its network said.
+func send(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		slog.WarnContext(req.Context(), "llm provider unreachable", "host", req.URL.Host, "error", err)
+		return nil, ErrUnavailable
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if resp.StatusCode/100 != 2 {
+		slog.WarnContext(req.Context(), "llm provider error", "host", req.URL.Host, "status", resp.StatusCode, "message", errorMessage(raw))
+		return nil, &StatusError{Code: resp.StatusCode}
+	}
+	if err != nil {
+		slog.WarnContext(req.Context(), "llm provider response unreadable", "host", req.URL.Host, "error", err)
+		return nil, ErrUnavailable
+	}
+	return raw, nil
+}
+
+// errorMessage extracts the message of a provider error body, which both
+// OpenAI and Anthropic shape as {"error": {"message": "..."}}.
+func errorMessage(body []byte) string {
+	var e struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &e) == nil && e.Error.Message != "" {
+		return e.Error.Message
+	}
+	if len(body) > 200 {
+		body = body[:200]
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,65 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrorsHideProviderDetails(t *testing.T) {
+	const leak = "redis_version:7.2.4 internal-db.corp:5432"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/denied/models", "/denied/chat/completions":
+			http.Error(w, `{"error":{"message":"`+leak+`"}}`, http.StatusForbidden)
+		case "/garbled/chat/completions":
+			w.Write([]byte(leak))
+		}
+	}))
+	defer srv.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantCode int
+		wantErr  error
+	}{
+		{"health error", func() error {
+			return NewLocal(srv.Client(), srv.URL+"/denied", "", "m").Health(context.Background())
+		}, http.StatusForbidden, nil},
+		{"completion error", func() error {
+			_, err := NewLocal(srv.Client(), srv.URL+"/denied", "", "m").Complete(context.Background(), Request{Prompt: "hi"})
+			return err
+		}, http.StatusForbidden, nil},
+		{"unreadable answer", func() error {
+			_, err := NewLocal(srv.Client(), srv.URL+"/garbled", "", "m").Complete(context.Background(), Request{Prompt: "hi"})
+			return err
+		}, 0, ErrUnavailable},
+		{"unreachable", func() error {
+			return NewLocal(http.DefaultClient, closed.URL, "", "m").Health(context.Background())
+		}, 0, ErrUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if strings.Contains(err.Error(), leak) || strings.Contains(err.Error(), "127.0.0.1") {
+				t.Errorf("error %q reveals provider details", err)
+			}
+			var status *StatusError
+			if errors.As(err, &status) != (tt.wantCode != 0) || (status != nil && status.Code != tt.wantCode) {
+				t.Errorf("error = %v, want status %d", err, tt.wantCode)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Ollama completes prompts with a model served by Ollama, usually on the
+// same network, so no content leaves it.
+type Ollama struct {
+	client  *http.Client
+	baseURL string
+	model   string
+}
+
+// NewOllama creates a provider for the Ollama server at baseURL (default
+// http://localhost:11434) that uses model (default llama3.1) unless a
+// request names another.
+func NewOllama(client *http.Client, baseURL, model string) *Ollama {
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	if model == "" {
+		model = "llama3.1"
+	}
+	return &Ollama{client: client, baseURL: strings.TrimRight(baseURL, "/"), model: model}
+}
+
+// Name implements Provider.
+func (o *Ollama) Name() string {
+	return "ollama"
+}
+
+// SelfHosted reports that Ollama serves models on the operator's own
+// infrastructure.
+func (o *Ollama) SelfHosted() bool {
+	return true
+}
+
+// Health checks that the server answers by listing its models.
+func (o *Ollama) Health(ctx context.Context) error {
+	return getOK(ctx, o.client, o.baseURL+"/api/tags", nil)
+}
+
+// Complete implements Provider.
+func (o *Ollama) Complete(ctx context.Context, req Request) (Response, error) {
+	model := req.Model
+	if model == "" {
+		model = o.model
+	}
+	body := map[string]interface{}{
+		"model": model,
+		"messages": []map[string]string{
+			{"role": "system", "content": req.System},
+			{"role": "user", "content": req.Prompt},
+		},
+		"stream": false,
+	}
+	if req.MaxTokens > 0 {
+		body["options"] = map[string]int{"num_predict": req.MaxTokens}
+	}
+	if req.JSON {
+		body["format"] = "json"
+	}
+	var out struct {
+		Model   string `json:"model"`
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		PromptEvalCount int `json:"prompt_eval_count"`
+		EvalCount       int `json:"eval_count"`
+	}
+	if err := postJSON(ctx, o.client, o.baseURL+"/api/chat", nil, body, &out); err != nil {
+		return Response{}, err
+	}
+	if out.Model == "" {
+		out.Model = model
+	}
+	return Response{
+		Text:         out.Message.Content,
+		Model:        out.Model,
+		InputTokens:  out.PromptEvalCount,
+		OutputTokens: out.EvalCount,
+	}, nil
+}
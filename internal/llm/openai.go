@@ -0,0 +1,114 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// OpenAI completes prompts with the OpenAI chat completions API, or with
+// a self-hosted server offering the same API.
+type OpenAI struct {
+	client  *http.Client
+	name    string
+	baseURL string
+	apiKey  string
+	model   string
+	local   bool
+}
+
+// NewOpenAI creates a provider for the API at baseURL (default
+// https://api.openai.com/v1) that uses model (default gpt-4o-mini) unless a
+// request names another.
+func NewOpenAI(client *http.Client, baseURL, apiKey, model string) *OpenAI {
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	return &OpenAI{client: client, name: "openai", baseURL: strings.TrimRight(baseURL, "/"), apiKey: apiKey, model: model}
+}
+
+// NewLocal creates a provider for a self-hosted inference server with an
+// OpenAI-compatible API at baseURL, such as vLLM or Ollama (at
+// http://host:11434/v1), that uses model unless a request names another.
+// The API key is only sent when set.
+func NewLocal(client *http.Client, baseURL, apiKey, model string) *OpenAI {
+	return &OpenAI{client: client, name: "local", baseURL: strings.TrimRight(baseURL, "/"), apiKey: apiKey, model: model, local: true}
+}
+
+// Name implements Provider.
+func (o *OpenAI) Name() string {
+	return o.name
+}
+
+// SelfHosted reports whether the server is a self-hosted one.
+func (o *OpenAI) SelfHosted() bool {
+	return o.local
+}
+
+// Health checks that the API answers by listing its models.
+func (o *OpenAI) Health(ctx context.Context) error {
+	return getOK(ctx, o.client, o.baseURL+"/models", o.header())
+}
+
+func (o *OpenAI) header() http.Header {
+	if o.apiKey == "" {
+		return nil
+	}
+	return http.Header{"Authorization": {"Bearer " + o.apiKey}}
+}
+
+// Complete implements Provider.
+func (o *OpenAI) Complete(ctx context.Context, req Request) (Response, error) {
+	model := req.Model
+	if model == "" {
+		model = o.model
+	}
+	return chatCompletion(ctx, o.client, o.baseURL+"/chat/completions", o.header(), model, req)
+}
+
+// chatCompletion sends req to an OpenAI-style chat completions endpoint.
+func chatCompletion(ctx context.Context, client *http.Client, url string, header http.Header, model string, req Request) (Response, error) {
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	body := map[string]interface{}{
+		"model":    model,
+		"messages": []message{{Role: "system", Content: req.System}, {Role: "user", Content: req.Prompt}},
+	}
+	if req.MaxTokens > 0 {
+		body["max_tokens"] = req.MaxTokens
+	}
+	if req.JSON {
+		body["response_format"] = map[string]string{"type": "json_object"}
+	}
+	var out struct {
+		Model   string `json:"model"`
+		Choices []struct {
+			Message message `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := postJSON(ctx, client, url, header, body, &out); err != nil {
+		return Response{}, err
+	}
+	if len(out.Choices) == 0 {
+		return Response{}, errors.New("response has no choices")
+	}
+	if out.Model == "" {
+		out.Model = model
+	}
+	return Response{
+		Text:         out.Choices[0].Message.Content,
+		Model:        out.Model,
+		InputTokens:  out.Usage.PromptTokens,
+		OutputTokens: out.Usage.CompletionTokens,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"sort"
+	"strings"
+)
+
+// Price is what a model's tokens cost, in USD per million tokens.
+type Price struct {
+	Input  float64
+	Output float64
+}
+
+// Cost returns what resp cost at p.
+func (p Price) Cost(resp Response) float64 {
+	return (float64(resp.InputTokens)*p.Input + float64(resp.OutputTokens)*p.Output) / 1e6
+}
+
+// knownPrices are the list prices of common hosted models, by model name
+// prefix. Providers configured with their own prices override them.
+var knownPrices = map[string]Price{
+	"gpt-4o-mini":       {Input: 0.15, Output: 0.60},
+	"gpt-4o":            {Input: 2.50, Output: 10},
+	"gpt-4.1-nano":      {Input: 0.10, Output: 0.40},
+	"gpt-4.1-mini":      {Input: 0.40, Output: 1.60},
+	"gpt-4.1":           {Input: 2, Output: 8},
+	"claude-3-haiku":    {Input: 0.25, Output: 1.25},
+	"claude-3-5-haiku":  {Input: 0.80, Output: 4},
+	"claude-3-5-sonnet": {Input: 3, Output: 15},
+	"claude-3-7-sonnet": {Input: 3, Output: 15},
+	"claude-sonnet-4":   {Input: 3, Output: 15},
+	"claude-opus-4":     {Input: 15, Output: 75},
+}
+
+// knownPrefixes orders knownPrices longest first, so gpt-4o-mini is not
+// priced as gpt-4o.
+var knownPrefixes = func() []string {
+	prefixes := make([]string, 0, len(knownPrices))
+	for p := range knownPrices {
+		prefixes = append(prefixes, p)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+	return prefixes
+}()
+
+// PriceOf returns the list price of a hosted model, if known.
+func PriceOf(model string) (Price, bool) {
+	for _, prefix := range knownPrefixes {
+		if strings.HasPrefix(model, prefix) {
+			return knownPrices[prefix], true
+		}
+	}
+	return Price{}, false
+}
@@ -0,0 +1,275 @@
+// Package logging is the application logger. Its level can be changed at
+// runtime, and debug logging can be switched on for the requests of one
+// user, organization or path for a limited time. Standard library log
+// output goes through it at info level.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+)
+
+// MaxOverride bounds how long a runtime override may last.
+const MaxOverride = 24 * time.Hour
+
+// ErrInvalidOverride is returned for overrides without a target or with a
+// duration out of range.
+var ErrInvalidOverride = errors.New("override needs a user, org or path prefix and a duration up to 24h")
+
+// Override raises the level for matching requests until it expires.
+type Override struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id,omitempty"`
+	OrgID      string     `json:"org_id,omitempty"`
+	PathPrefix string     `json:"path_prefix,omitempty"`
+	Level      slog.Level `json:"level"`
+	CreatedBy  string     `json:"created_by"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+}
+
+// Status describes the logger configuration.
+type Status struct {
+	Level     slog.Level  `json:"level"`
+	BaseLevel slog.Level  `json:"base_level"`
+	RevertsAt *time.Time  `json:"reverts_at,omitempty"`
+	Overrides []*Override `json:"overrides"`
+}
+
+// Controller owns the logger's level and overrides.
+type Controller struct {
+	base  slog.Level
+	level slog.LevelVar
+
+	mu        sync.Mutex
+	revert    *time.Timer
+	revertsAt *time.Time
+	overrides []*Override
+	seq       int
+}
+
+// Setup installs a logger writing to out in format ("text" or "json") at
+// level as the default for slog and the standard log package.
+func Setup(out io.Writer, format string, level slog.Level) (*Controller, error) {
+	c := &Controller{base: level}
+	c.level.Set(level)
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug - 4}
+	var h slog.Handler
+	switch format {
+	case "", "text":
+		h = slog.NewTextHandler(out, opts)
+	case "json":
+		h = slog.NewJSONHandler(out, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+	slog.SetDefault(slog.New(&handler{Handler: h, c: c}))
+	return c, nil
+}
+
+// ParseLevel parses debug, info, warn or error.
+func ParseLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	err := l.UnmarshalText([]byte(s))
+	return l, err
+}
+
+// SetLevel changes the level. With a positive duration the level reverts
+// to the startup level afterwards.
+func (c *Controller) SetLevel(level slog.Level, d time.Duration) error {
+	if d < 0 || d > MaxOverride {
+		return ErrInvalidOverride
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.revert != nil {
+		c.revert.Stop()
+		c.revert, c.revertsAt = nil, nil
+	}
+	c.level.Set(level)
+	if d > 0 {
+		at := time.Now().Add(d)
+		c.revertsAt = &at
+		c.revert = time.AfterFunc(d, c.reset)
+	}
+	return nil
+}
+
+func (c *Controller) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.level.Set(c.base)
+	c.revert, c.revertsAt = nil, nil
+}
+
+// AddOverride enables o.Level for matching requests for d.
+func (c *Controller) AddOverride(o *Override, d time.Duration) error {
+	if (o.UserID == "" && o.OrgID == "" && o.PathPrefix == "") || d <= 0 || d > MaxOverride {
+		return ErrInvalidOverride
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seq++
+	o.ID = fmt.Sprintf("override-%d", c.seq)
+	o.ExpiresAt = time.Now().Add(d)
+	c.overrides = append(c.pruned(), o)
+	return nil
+}
+
+// RemoveOverride deletes an override before it expires.
+func (c *Controller) RemoveOverride(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, o := range c.overrides {
+		if o.ID == id {
+			c.overrides = append(c.overrides[:i:i], c.overrides[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Status returns the current level and unexpired overrides.
+func (c *Controller) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overrides = c.pruned()
+	return Status{
+		Level:     c.level.Level(),
+		BaseLevel: c.base,
+		RevertsAt: c.revertsAt,
+		Overrides: append([]*Override{}, c.overrides...),
+	}
+}
+
+// pruned drops expired overrides. c.mu must be held.
+func (c *Controller) pruned() []*Override {
+	now := time.Now()
+	kept := c.overrides[:0]
+	for _, o := range c.overrides {
+		if now.Before(o.ExpiresAt) {
+			kept = append(kept, o)
+		}
+	}
+	return kept
+}
+
+// enabled reports whether a record at level is logged in ctx.
+func (c *Controller) enabled(ctx context.Context, level slog.Level) bool {
+	if level >= c.level.Level() {
+		return true
+	}
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for _, o := range c.overrides {
+		if level >= o.Level && now.Before(o.ExpiresAt) && s.matches(o) {
+			return true
+		}
+	}
+	return false
+}
+
+// handler filters records through the controller.
+type handler struct {
+	slog.Handler
+	c *Controller
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.c.enabled(ctx, level)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &handler{Handler: h.Handler.WithAttrs(attrs), c: h.c}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{Handler: h.Handler.WithGroup(name), c: h.c}
+}
+
+type scopeKey struct{}
+
+// scope identifies the request a record is logged for.
+type scope struct {
+	mu            sync.Mutex
+	path          string
+	userID, orgID string
+}
+
+func (s *scope) matches(o *Override) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return (o.UserID == "" || o.UserID == s.userID) &&
+		(o.OrgID == "" || o.OrgID == s.orgID) &&
+		(o.PathPrefix == "" || strings.HasPrefix(s.path, o.PathPrefix))
+}
+
+// Middleware scopes records logged with the request context to the
+// request, so overrides can match them, and logs each request at debug.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), scopeKey{}, &scope{path: r.URL.Path})
+		start := time.Now()
+		rec := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+		slog.DebugContext(ctx, "request", "method", r.Method, "path", r.URL.Path, "status", rec.status, "duration", time.Since(start))
+	})
+}
+
+// Identify adds the authenticated user and org to the request's scope.
+// Mount it after JWTAuth.
+func Identify(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s, ok := r.Context().Value(scopeKey{}).(*scope); ok {
+			if claims, ok := middleware.GetClaims(r.Context()); ok {
+				s.mu.Lock()
+				s.userID, s.orgID = claims.UserID, claims.OrgID
+				s.mu.Unlock()
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status, w.wroteHeader = code, true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush supports streaming responses.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,52 @@
+// Package mail sends transactional email.
+package mail
+
+import (
+	"log"
+)
+
+// Message is an email with a plain-text body and an optional HTML
+// alternative.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+	// HTML, when set, is sent alongside Body for clients that render it.
+	HTML string
+	// ReplyTo, when set, directs replies somewhere other than the sender.
+	ReplyTo string
+}
+
+// Mailer sends email messages.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// LogMailer writes messages to the server log instead of sending them.
+// It is the default when no SMTP relay is configured.
+type LogMailer struct{}
+
+// Send logs the message.
+func (LogMailer) Send(msg Message) error {
+	log.Printf("mail to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// Async returns a Mailer that hands each message to m in the background,
+// so callers do not wait on the relay, and logs the messages m fails to
+// send.
+func Async(m Mailer) Mailer {
+	return asyncMailer{m}
+}
+
+type asyncMailer struct{ m Mailer }
+
+// Send starts sending msg and returns at once.
+func (a asyncMailer) Send(msg Message) error {
+	go func() {
+		if err := a.m.Send(msg); err != nil {
+			log.Printf("Failed to send mail %q: %v", msg.Subject, err)
+		}
+	}()
+	return nil
+}
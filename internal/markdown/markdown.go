@@ -0,0 +1,525 @@
+// Package markdown renders the Markdown of review contents and comments to
+// HTML. It supports the common subset of GitHub Flavored Markdown:
+// headings, paragraphs, emphasis, strikethrough, code spans and fenced
+// blocks, block quotes, lists, rules, links and images (rendered as
+// links). Raw HTML in the source is escaped rather than passed through and
+// only http, https, mailto and relative URLs are linked, so the output is
+// safe to embed without further sanitizing. Bare URLs and @mentions are
+// linked automatically.
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// Options customizes rendering.
+type Options struct {
+	// Mention resolves an @mention to the URL it links to. Mentions it
+	// does not resolve, or all of them when nil, stay plain text.
+	Mention func(name string) (href string, ok bool)
+}
+
+var (
+	headingLine = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	ruleLine    = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	fenceLine   = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ \t]*([^`\\s]*)")
+	quoteLine   = regexp.MustCompile(`^ {0,3}> ?`)
+	itemLine    = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])([ \t]+|$)`)
+	langName    = regexp.MustCompile(`^[A-Za-z0-9_+#.-]+$`)
+	mentionName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*`)
+)
+
+// Render converts Markdown to HTML.
+func Render(src string, opts Options) string {
+	src = strings.ReplaceAll(strings.ReplaceAll(src, "\r\n", "\n"), "\r", "\n")
+	r := &renderer{opts: opts}
+	r.blocks(strings.Split(src, "\n"), false)
+	return r.out.String()
+}
+
+type renderer struct {
+	opts Options
+	out  strings.Builder
+}
+
+// blocks renders lines as block elements. In a tight list item paragraphs
+// are not wrapped in <p>.
+func (r *renderer) blocks(lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case fenceLine.MatchString(line):
+			i = r.fence(lines, i)
+		case headingLine.MatchString(line):
+			m := headingLine.FindStringSubmatch(line)
+			fmt.Fprintf(&r.out, "<h%d>", len(m[1]))
+			r.inline(m[2])
+			fmt.Fprintf(&r.out, "</h%d>\n", len(m[1]))
+			i++
+		case ruleLine.MatchString(line):
+			r.out.WriteString("<hr>\n")
+			i++
+		case quoteLine.MatchString(line):
+			var quoted []string
+			for ; i < len(lines) && quoteLine.MatchString(lines[i]); i++ {
+				quoted = append(quoted, quoteLine.ReplaceAllString(lines[i], ""))
+			}
+			r.out.WriteString("<blockquote>\n")
+			r.blocks(quoted, false)
+			r.out.WriteString("</blockquote>\n")
+		case itemLine.MatchString(line):
+			i = r.list(lines, i)
+		default:
+			var para []string
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(para) == 0 || !startsBlock(lines[i])); i++ {
+				para = append(para, strings.TrimSpace(lines[i]))
+			}
+			if !tight {
+				r.out.WriteString("<p>")
+			}
+			r.inline(strings.Join(para, "\n"))
+			if !tight {
+				r.out.WriteString("</p>")
+			}
+			r.out.WriteString("\n")
+		}
+	}
+}
+
+// startsBlock reports whether a line interrupts a paragraph.
+func startsBlock(line string) bool {
+	return fenceLine.MatchString(line) || headingLine.MatchString(line) || ruleLine.MatchString(line) ||
+		quoteLine.MatchString(line) || itemLine.MatchString(line)
+}
+
+// fence renders the fenced code block starting at lines[i] and returns the
+// index of the line after it. An unclosed fence runs to the end.
+func (r *renderer) fence(lines []string, i int) int {
+	m := fenceLine.FindStringSubmatch(lines[i])
+	marker := m[1]
+	var code []string
+	for i++; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, marker) && strings.Trim(trimmed, marker[:1]) == "" {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+	if langName.MatchString(m[2]) {
+		fmt.Fprintf(&r.out, `<pre><code class="language-%s">`, html.EscapeString(m[2]))
+	} else {
+		r.out.WriteString("<pre><code>")
+	}
+	for _, l := range code {
+		r.out.WriteString(html.EscapeString(l))
+		r.out.WriteString("\n")
+	}
+	r.out.WriteString("</code></pre>\n")
+	return i
+}
+
+// list renders the list starting at lines[i] and returns the index of the
+// line after it. Items continue over indented and lazy lines; a list is
+// tight when no blank line separates its items.
+func (r *renderer) list(lines []string, i int) int {
+	first := itemLine.FindStringSubmatch(lines[i])
+	ordered := first[2][0] >= '0' && first[2][0] <= '9'
+	delim := first[2][len(first[2])-1:]
+
+	sameList := func(line string) bool {
+		m := itemLine.FindStringSubmatch(line)
+		return m != nil && (m[2][0] >= '0' && m[2][0] <= '9') == ordered && m[2][len(m[2])-1:] == delim
+	}
+
+	var items [][]string
+	tight := true
+	for i < len(lines) && sameList(lines[i]) {
+		m := itemLine.FindStringSubmatch(lines[i])
+		indent := len(m[0])
+		if m[3] == "" || len(m[3]) > 4 {
+			indent = len(m[1]) + len(m[2]) + 1
+		}
+		item := []string{strings.TrimLeft(lines[i][len(m[0]):], " \t")}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// A blank line continues the item only if indented
+				// content follows it.
+				if i+1 < len(lines) && leadingSpaces(lines[i+1]) >= indent && strings.TrimSpace(lines[i+1]) != "" {
+					item = append(item, "")
+					tight = false
+					continue
+				}
+				break
+			}
+			if leadingSpaces(line) >= indent {
+				item = append(item, line[indent:])
+				continue
+			}
+			if itemLine.MatchString(line) || startsBlock(line) || item[len(item)-1] == "" {
+				break
+			}
+			item = append(item, line)
+		}
+		items = append(items, item)
+		// Items separated by blank lines make the list loose
+		next := i
+		for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+			next++
+		}
+		if next > i && next < len(lines) && sameList(lines[next]) {
+			tight = false
+			i = next
+		}
+	}
+
+	if ordered {
+		start, _ := strconv.Atoi(first[2][:len(first[2])-1])
+		if start != 1 {
+			fmt.Fprintf(&r.out, "<ol start=\"%d\">\n", start)
+		} else {
+			r.out.WriteString("<ol>\n")
+		}
+	} else {
+		r.out.WriteString("<ul>\n")
+	}
+	for _, item := range items {
+		r.out.WriteString("<li>")
+		r.blocks(item, tight)
+		r.out.WriteString("</li>\n")
+	}
+	if ordered {
+		r.out.WriteString("</ol>\n")
+	} else {
+		r.out.WriteString("</ul>\n")
+	}
+	return i
+}
+
+func leadingSpaces(s string) int {
+	n := 0
+	for _, c := range s {
+		switch c {
+		case ' ':
+			n++
+		case '\t':
+			n += 4 - n%4
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// delimiters are the emphasis markers, longest first, with their tags.
+var delimiters = []struct{ marker, tag string }{
+	{"**", "strong"}, {"__", "strong"}, {"~~", "del"}, {"*", "em"}, {"_", "em"},
+}
+
+// inline renders the inline content of a block.
+func (r *renderer) inline(s string) {
+	var text strings.Builder
+	flush := func() {
+		r.out.WriteString(html.EscapeString(text.String()))
+		text.Reset()
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && (s[i+1] == '\n' || isPunct(s[i+1])):
+			if s[i+1] == '\n' {
+				flush()
+				r.out.WriteString("<br>\n")
+			} else {
+				text.WriteByte(s[i+1])
+			}
+			i += 2
+			continue
+		case c == '\n':
+			flush()
+			r.out.WriteString("<br>\n")
+			i++
+			continue
+		case c == '`':
+			if n := r.codeSpan(s[i:], flush); n > 0 {
+				i += n
+				continue
+			}
+		case c == '[' || (c == '!' && strings.HasPrefix(s[i+1:], "[")):
+			if n := r.link(s[i:], flush); n > 0 {
+				i += n
+				continue
+			}
+		case c == '<':
+			if end := strings.IndexByte(s[i:], '>'); end > 0 {
+				if u := s[i+1 : i+end]; !strings.ContainsAny(u, " \t\n") && safeURL(u) && strings.Contains(u, ":") {
+					flush()
+					r.anchor(u, func() { r.out.WriteString(html.EscapeString(u)) })
+					i += end + 1
+					continue
+				}
+			}
+		case c == 'h' && boundary(s, i) && (strings.HasPrefix(s[i:], "http://") || strings.HasPrefix(s[i:], "https://")):
+			if n := autolinkLength(s[i:]); n > len("https://") {
+				u := s[i : i+n]
+				flush()
+				r.anchor(u, func() { r.out.WriteString(html.EscapeString(u)) })
+				i += n
+				continue
+			}
+		case c == '@' && boundary(s, i) && r.opts.Mention != nil:
+			if name := strings.TrimRight(mentionName.FindString(s[i+1:]), "._-"); name != "" {
+				if href, ok := r.opts.Mention(name); ok {
+					flush()
+					fmt.Fprintf(&r.out, `<a class="mention" href="%s">@%s</a>`, html.EscapeString(href), html.EscapeString(name))
+					i += 1 + len(name)
+					continue
+				}
+			}
+		case c == '*' || c == '_' || c == '~':
+			if n := r.emphasis(s, i, flush); n > 0 {
+				i += n
+				continue
+			}
+			// An unclosed run stays literal as a whole, so ** cannot
+			// open a single *
+			run := len(s[i:]) - len(strings.TrimLeft(s[i:], string(c)))
+			text.WriteString(s[i : i+run])
+			i += run
+			continue
+		}
+		text.WriteByte(c)
+		i++
+	}
+	flush()
+}
+
+// codeSpan renders the code span at the start of s and returns its
+// length, or 0 if the backticks are not closed.
+func (r *renderer) codeSpan(s string, flush func()) int {
+	n := len(s) - len(strings.TrimLeft(s, "`"))
+	marker := s[:n]
+	for j := n; j < len(s); {
+		k := strings.Index(s[j:], marker)
+		if k < 0 {
+			return 0
+		}
+		k += j
+		if k+n < len(s) && s[k+n] == '`' {
+			j = k + n + len(strings.TrimLeft(s[k+n:], "`"))
+			continue
+		}
+		code := strings.ReplaceAll(s[n:k], "\n", " ")
+		if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+			code = code[1 : len(code)-1]
+		}
+		flush()
+		r.out.WriteString("<code>" + html.EscapeString(code) + "</code>")
+		return k + n
+	}
+	return 0
+}
+
+// link renders the link or image at the start of s and returns its
+// length, or 0 if s does not start with one. Images become links so
+// rendered content never loads remote resources.
+func (r *renderer) link(s string, flush func()) int {
+	start := 1
+	if s[0] == '!' {
+		start = 2
+	}
+	depth, end := 0, -1
+	for j := start; j < len(s) && end < 0; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			if depth == 0 {
+				end = j
+			}
+			depth--
+		}
+	}
+	if end < 0 || end+1 >= len(s) || s[end+1] != '(' {
+		return 0
+	}
+	closing := strings.IndexByte(s[end+2:], ')')
+	if closing < 0 {
+		return 0
+	}
+	target := strings.TrimSpace(s[end+2 : end+2+closing])
+	if k := strings.IndexAny(target, " \t"); k >= 0 {
+		// Drop a title such as [text](url "title")
+		target = target[:k]
+	}
+	target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+	if !safeURL(target) {
+		return 0
+	}
+	label := s[start:end]
+	flush()
+	r.anchor(target, func() {
+		if label == "" {
+			r.out.WriteString(html.EscapeString(target))
+			return
+		}
+		r.inline(label)
+	})
+	return end + 3 + closing
+}
+
+// emphasis renders the emphasis starting at s[i] and returns its length,
+// or 0 if the delimiter is not closed.
+func (r *renderer) emphasis(s string, i int, flush func()) int {
+	for _, d := range delimiters {
+		if !strings.HasPrefix(s[i:], d.marker) {
+			continue
+		}
+		rest := s[i+len(d.marker):]
+		if rest == "" || unicode.IsSpace(firstRune(rest)) {
+			return 0
+		}
+		// Underscores inside words are not emphasis, e.g. snake_case.
+		if d.marker[0] == '_' && i > 0 && isWordByte(s[i-1]) {
+			return 0
+		}
+		for j := 1; j < len(rest); j++ {
+			// A closing marker follows text and starts its run
+			if !strings.HasPrefix(rest[j:], d.marker) || unicode.IsSpace(lastRune(rest[:j])) || rest[j-1] == d.marker[0] {
+				continue
+			}
+			after := j + len(d.marker)
+			if len(d.marker) == 1 && after < len(rest) && rest[after] == d.marker[0] {
+				// Part of a longer run, e.g. the ** of a nested strong
+				j++
+				continue
+			}
+			if d.marker[0] == '_' && after < len(rest) && isWordByte(rest[after]) {
+				continue
+			}
+			flush()
+			r.out.WriteString("<" + d.tag + ">")
+			r.inline(rest[:j])
+			r.out.WriteString("</" + d.tag + ">")
+			return len(d.marker) + after
+		}
+		return 0
+	}
+	return 0
+}
+
+// anchor writes a link to href around the content written by label.
+func (r *renderer) anchor(href string, label func()) {
+	fmt.Fprintf(&r.out, `<a href="%s" rel="nofollow noopener noreferrer">`, html.EscapeString(href))
+	label()
+	r.out.WriteString("</a>")
+}
+
+// autolinkLength returns the length of the bare URL at the start of s,
+// leaving out trailing punctuation and unbalanced closing parentheses.
+func autolinkLength(s string) int {
+	n := strings.IndexAny(s, " \t\n<>\"")
+	if n < 0 {
+		n = len(s)
+	}
+	for n > 0 {
+		last := s[n-1]
+		if strings.IndexByte(".,:;!?'*_~", last) >= 0 {
+			n--
+			continue
+		}
+		if last == ')' && strings.Count(s[:n], "(") < strings.Count(s[:n], ")") {
+			n--
+			continue
+		}
+		break
+	}
+	return n
+}
+
+// safeURL reports whether u may be linked: an http, https or mailto URL,
+// or a relative one.
+func safeURL(u string) bool {
+	if u == "" || strings.ContainsAny(u, "\x00\n") {
+		return false
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https":
+		return parsed.Host != ""
+	case "mailto":
+		return true
+	case "":
+		// A colon before any slash would be read as a scheme by browsers
+		return !strings.Contains(strings.SplitN(u, "/", 2)[0], ":")
+	}
+	return false
+}
+
+// boundary reports whether s[i] starts a word.
+func boundary(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(s[:i])
+	return !unicode.IsLetter(prev) && !unicode.IsDigit(prev) && prev != '_' && prev != '/'
+}
+
+func isPunct(c byte) bool {
+	return c < utf8.RuneSelf && unicode.IsPunct(rune(c)) || strings.IndexByte("`*_~<>[]()#+-.!|\\{}", c) >= 0
+}
+
+func isWordByte(c byte) bool {
+	return c >= utf8.RuneSelf || c == '_' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+func firstRune(s string) rune {
+	c, _ := utf8.DecodeRuneInString(s)
+	return c
+}
+
+func lastRune(s string) rune {
+	c, _ := utf8.DecodeLastRuneInString(s)
+	return c
+}
+
+// MentionUsers resolves @mentions of the local part of users' email
+// addresses, ignoring case, to baseURL/users/<id>. Names shared by several
+// users are left unresolved.
+func MentionUsers(users []*models.User, baseURL string) func(name string) (string, bool) {
+	ids := make(map[string]string, len(users))
+	for _, u := range users {
+		local, _, _ := strings.Cut(u.Email, "@")
+		local = strings.ToLower(local)
+		if _, taken := ids[local]; taken {
+			ids[local] = ""
+			continue
+		}
+		ids[local] = u.ID
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+	return func(name string) (string, bool) {
+		id := ids[strings.ToLower(name)]
+		if id == "" {
+			return "", false
+		}
+		return baseURL + "/users/" + url.PathEscape(id), true
+	}
+}
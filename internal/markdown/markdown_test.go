@@ -0,0 +1,48 @@
+package markdown
+
+import "testing"
+
+func TestRenderSanitizes(t *testing.T) {
+	mention := func(name string) (string, bool) { return "/users/" + name, name == "alice" }
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		// Links only go to http, https, mailto and relative URLs.
+		{"javascript link", "[x](javascript:alert(1))", "<p>[x](javascript:alert(1))</p>\n"},
+		{"javascript link in mixed case", "[x](JaVaScRiPt:alert(1))", "<p>[x](JaVaScRiPt:alert(1))</p>\n"},
+		{"javascript link after a space", "[x]( javascript:alert(1))", "<p>[x]( javascript:alert(1))</p>\n"},
+		{"javascript link as an entity", "[x](&#106;avascript:alert(1))", "<p>[x](&amp;#106;avascript:alert(1))</p>\n"},
+		{"data image", "![img](data:text/html;base64,PHNjcmlwdD4=)", "<p>![img](data:text/html;base64,PHNjcmlwdD4=)</p>\n"},
+		{"javascript autolink", "<javascript:alert(1)>", "<p>&lt;javascript:alert(1)&gt;</p>\n"},
+		{"data autolink", "<data:text/html,<script>alert(1)</script>>", "<p>&lt;data:text/html,&lt;script&gt;alert(1)&lt;/script&gt;&gt;</p>\n"},
+		{"https link", `[ok](https://example.com/a?b=1&c="2")`, `<p><a href="https://example.com/a?b=1&amp;c=&#34;2&#34;" rel="nofollow noopener noreferrer">ok</a></p>` + "\n"},
+		{"relative link", "[rel](/reviews/1)", `<p><a href="/reviews/1" rel="nofollow noopener noreferrer">rel</a></p>` + "\n"},
+		{"mailto link", "[mail](mailto:a@b.test)", `<p><a href="mailto:a@b.test" rel="nofollow noopener noreferrer">mail</a></p>` + "\n"},
+
+		// Raw HTML is escaped, wherever it appears.
+		{"script", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"event handler", "<img src=x onerror=alert(1)>", "<p>&lt;img src=x onerror=alert(1)&gt;</p>\n"},
+		{"inline tag", "hello <b>bold</b>", "<p>hello &lt;b&gt;bold&lt;/b&gt;</p>\n"},
+		{"heading", "# <i>h</i>", "<h1>&lt;i&gt;h&lt;/i&gt;</h1>\n"},
+		{"code span", "`<b>`", "<p><code>&lt;b&gt;</code></p>\n"},
+		{"fenced block", "```html\n<script>x</script>\n```", `<pre><code class="language-html">&lt;script&gt;x&lt;/script&gt;` + "\n</code></pre>\n"},
+		{"fence info string", "```\"><script>\nx\n```", "<pre><code>x\n</code></pre>\n"},
+
+		// Bare URLs end before characters that would break out of them.
+		{"autolink before a tag", "https://example.com/?q=<script>", `<p><a href="https://example.com/?q=" rel="nofollow noopener noreferrer">https://example.com/?q=</a>&lt;script&gt;</p>` + "\n"},
+		{"autolink before a quote", `see https://example.com/a"onmouseover="x`, `<p>see <a href="https://example.com/a" rel="nofollow noopener noreferrer">https://example.com/a</a>&#34;onmouseover=&#34;x</p>` + "\n"},
+		{"bracketed autolink with a quote", `<https://example.com/"><script>>`, `<p><a href="https://example.com/&#34;" rel="nofollow noopener noreferrer">https://example.com/&#34;</a>&lt;script&gt;&gt;</p>` + "\n"},
+
+		{"mention", "@alice hi", `<p><a class="mention" href="/users/alice">@alice</a> hi</p>` + "\n"},
+		{"unknown mention", "@mallory hi", "<p>@mallory hi</p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.src, Options{Mention: mention}); got != tt.want {
+				t.Errorf("Render(%q)\n got %q\nwant %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Package metrics pushes the server's metrics to a collector, for
+// deployments without a Prometheus scraper: a StatsD daemon (optionally
+// with DogStatsD tags, as the Datadog agent expects) or a Prometheus
+// remote-write endpoint.
+package metrics
+
+import (
+	"context"
+	"log"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/httpclient"
+)
+
+// Kind distinguishes point-in-time values from monotonically increasing
+// totals. StatsD sinks send counters as deltas; remote write sends totals.
+type Kind int
+
+const (
+	Gauge Kind = iota
+	Counter
+)
+
+// Sample is one value of a metric series.
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+	Kind   Kind
+}
+
+// Source produces the current samples of some part of the server.
+type Source func() []Sample
+
+// Sink delivers gathered samples to a collector.
+type Sink interface {
+	Push(ctx context.Context, samples []Sample, at time.Time) error
+}
+
+// Pusher gathers samples from its sources and pushes them to every sink
+// on an interval.
+type Pusher struct {
+	interval time.Duration
+	labels   map[string]string
+	sinks    []Sink
+	sources  []Source
+}
+
+// NewPusher creates a pusher. labels are added to every sample, e.g. the
+// instance and environment.
+func NewPusher(interval time.Duration, labels map[string]string, sinks ...Sink) *Pusher {
+	return &Pusher{interval: interval, labels: labels, sinks: sinks, sources: []Source{Runtime, HTTPClients}}
+}
+
+// Add registers a source. It must be called before Run.
+func (p *Pusher) Add(s Source) {
+	p.sources = append(p.sources, s)
+}
+
+// Run pushes every interval until ctx is cancelled.
+func (p *Pusher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			samples := p.Gather()
+			for _, sink := range p.sinks {
+				if err := sink.Push(ctx, samples, now); err != nil {
+					log.Printf("Failed to push metrics: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// Gather collects the samples of every source with the pusher's labels.
+func (p *Pusher) Gather() []Sample {
+	var out []Sample
+	for _, source := range p.sources {
+		for _, s := range source() {
+			if len(p.labels) > 0 {
+				labels := make(map[string]string, len(s.Labels)+len(p.labels))
+				for k, v := range p.labels {
+					labels[k] = v
+				}
+				for k, v := range s.Labels {
+					labels[k] = v
+				}
+				s.Labels = labels
+			}
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ParseLabels parses "key=value,key=value".
+func ParseLabels(s string) map[string]string {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(pair), "="); ok && k != "" {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+var started = time.Now()
+
+// Runtime reports Go runtime metrics.
+func Runtime() []Sample {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return []Sample{
+		{Name: "go_goroutines", Value: float64(runtime.NumGoroutine())},
+		{Name: "go_memstats_heap_alloc_bytes", Value: float64(ms.HeapAlloc)},
+		{Name: "go_memstats_sys_bytes", Value: float64(ms.Sys)},
+		{Name: "go_gc_cycles_total", Value: float64(ms.NumGC), Kind: Counter},
+		{Name: "process_uptime_seconds", Value: time.Since(started).Seconds()},
+	}
+}
+
+// HTTPClients reports the counters of every third-party integration client.
+func HTTPClients() []Sample {
+	var out []Sample
+	for _, st := range httpclient.Snapshot() {
+		labels := map[string]string{"integration": st.Integration}
+		open := 0.0
+		if st.CircuitOpen {
+			open = 1
+		}
+		out = append(out,
+			Sample{Name: "integration_requests_total", Labels: labels, Value: float64(st.Requests), Kind: Counter},
+			Sample{Name: "integration_failures_total", Labels: labels, Value: float64(st.Failures), Kind: Counter},
+			Sample{Name: "integration_retries_total", Labels: labels, Value: float64(st.Retries), Kind: Counter},
+			Sample{Name: "integration_rejected_total", Labels: labels, Value: float64(st.Rejected), Kind: Counter},
+			Sample{Name: "integration_in_flight", Labels: labels, Value: float64(st.InFlight)},
+			Sample{Name: "integration_circuit_open", Labels: labels, Value: open},
+		)
+	}
+	return out
+}
+
+// sortedKeys returns the label names in order, for stable series keys.
+func sortedKeys(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
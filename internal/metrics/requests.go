@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Requests counts served HTTP requests by method and status class.
+type Requests struct {
+	mu     sync.Mutex
+	series map[requestKey]*requestTotals
+}
+
+type requestKey struct {
+	method string
+	class  string
+}
+
+type requestTotals struct {
+	count   int64
+	seconds float64
+}
+
+// NewRequests creates an empty request counter.
+func NewRequests() *Requests {
+	return &Requests{series: make(map[requestKey]*requestTotals)}
+}
+
+// Middleware counts each request once it completes.
+func (q *Requests) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		q.observe(r.Method, rec.status, time.Since(start))
+	})
+}
+
+func (q *Requests) observe(method string, status int, d time.Duration) {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+	default:
+		// Arbitrary methods would let clients create unbounded series.
+		method = "OTHER"
+	}
+	key := requestKey{method: method, class: strconv.Itoa(status/100) + "xx"}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	t, ok := q.series[key]
+	if !ok {
+		t = &requestTotals{}
+		q.series[key] = t
+	}
+	t.count++
+	t.seconds += d.Seconds()
+}
+
+// Samples reports request counts and total duration per series.
+func (q *Requests) Samples() []Sample {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make([]Sample, 0, 2*len(q.series))
+	for key, t := range q.series {
+		labels := map[string]string{"method": key.method, "code": key.class}
+		out = append(out,
+			Sample{Name: "http_requests_total", Labels: labels, Value: float64(t.count), Kind: Counter},
+			Sample{Name: "http_request_duration_seconds_sum", Labels: labels, Value: t.seconds, Kind: Counter},
+		)
+	}
+	return out
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush keeps streaming responses working through the wrapper.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
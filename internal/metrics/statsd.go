@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPacket keeps StatsD datagrams within a typical Ethernet MTU.
+const maxPacket = 1432
+
+// StatsD pushes samples over UDP in the StatsD line protocol. Gauges are
+// sent as gauges and counters as the increase since the previous push.
+type StatsD struct {
+	conn   net.Conn
+	prefix string
+	// tags selects DogStatsD tags (|#k:v); otherwise label values are
+	// appended to the metric name.
+	tags bool
+
+	mu   sync.Mutex
+	last map[string]float64
+}
+
+// NewStatsD creates a sink for the daemon at addr ("127.0.0.1:8125").
+// prefix is prepended to every metric name.
+func NewStatsD(addr, prefix string, tags bool) (*StatsD, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsD{conn: conn, prefix: prefix, tags: tags, last: make(map[string]float64)}, nil
+}
+
+// Push sends the samples.
+func (s *StatsD) Push(ctx context.Context, samples []Sample, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var packet bytes.Buffer
+	var firstErr error
+	flush := func() {
+		if packet.Len() == 0 {
+			return
+		}
+		if _, err := s.conn.Write(packet.Bytes()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		packet.Reset()
+	}
+	for _, sample := range samples {
+		line := s.line(sample)
+		if line == "" {
+			continue
+		}
+		if packet.Len() > 0 && packet.Len()+1+len(line) > maxPacket {
+			flush()
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	flush()
+	return firstErr
+}
+
+// line formats one sample, or returns "" for a counter that has not moved.
+func (s *StatsD) line(sample Sample) string {
+	keys := sortedKeys(sample.Labels)
+	name := sample.Name
+	if s.prefix != "" {
+		name = s.prefix + "." + name
+	}
+	var tags string
+	if s.tags {
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = k + ":" + sanitize(sample.Labels[k])
+		}
+		if len(parts) > 0 {
+			tags = "|#" + strings.Join(parts, ",")
+		}
+	} else {
+		// Dots would add levels to the metric hierarchy.
+		for _, k := range keys {
+			name += "." + strings.ReplaceAll(sanitize(sample.Labels[k]), ".", "_")
+		}
+	}
+
+	value, kind := sample.Value, "g"
+	if sample.Kind == Counter {
+		series := name + tags
+		prev, seen := s.last[series]
+		s.last[series] = sample.Value
+		value = sample.Value - prev
+		if !seen || value < 0 {
+			// First push or counter reset: report the total since start.
+			value = sample.Value
+		}
+		if value == 0 {
+			return ""
+		}
+		kind = "c"
+	}
+	return fmt.Sprintf("%s:%s|%s%s", name, strconv.FormatFloat(value, 'f', -1, 64), kind, tags)
+}
+
+// sanitize replaces characters with meaning in the StatsD protocol.
+func sanitize(v string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '#', ',', '\n', ' ':
+			return '_'
+		}
+		return r
+	}, v)
+}
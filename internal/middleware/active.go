@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// DeactivationLookup reports whether a user has been deactivated.
+type DeactivationLookup interface {
+	GetDeactivation(userID string) (*models.Deactivation, bool)
+}
+
+// RejectInactive refuses requests from deactivated users, which revokes
+// every token they hold as soon as they are deactivated.
+func RejectInactive(lookup DeactivationLookup) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if claims, ok := GetClaims(r.Context()); ok {
+				if _, inactive := lookup.GetDeactivation(claims.UserID); inactive {
+					http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Unauthorized: account is deactivated"), http.StatusUnauthorized)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
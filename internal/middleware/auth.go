@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/gorilla/mux"
+)
+
+type claimsKey struct{}
+
+// JWTAuth requires "Authorization: Bearer <token>" with a token signed
+// with secret, and attaches its claims to the request context.
+func JWTAuth(secret string) mux.MiddlewareFunc {
+	key := []byte(secret)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Unauthorized"), http.StatusUnauthorized)
+				return
+			}
+			claims, err := auth.ParseToken(strings.TrimSpace(token), key)
+			if err != nil {
+				http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Unauthorized: invalid or expired token"), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// WithClaims returns a copy of ctx carrying claims.
+func WithClaims(ctx context.Context, claims *auth.Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// GetClaims returns the claims JWTAuth attached to ctx.
+func GetClaims(ctx context.Context) (*auth.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*auth.Claims)
+	return claims, ok
+}
+
+// RequireRole restricts a handler to users whose token carries one of the
+// given org-scoped roles.
+func RequireRole(roles ...string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetClaims(r.Context())
+			if !ok {
+				http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Unauthorized"), http.StatusUnauthorized)
+				return
+			}
+			if !slices.Contains(roles, claims.Role) {
+				http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Forbidden: insufficient role"), http.StatusForbidden)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+)
+
+func TestJWTAuth(t *testing.T) {
+	tokens := auth.NewService("secret", time.Hour)
+	valid, _ := tokens.GenerateToken("u1", "u1@acme.test", "acme", "reviewer")
+	forged, _ := auth.NewService("other", time.Hour).GenerateToken("u1", "", "acme", "admin")
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"valid", "Bearer " + valid, http.StatusOK},
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong scheme", "Basic " + valid, http.StatusUnauthorized},
+		{"forged", "Bearer " + forged, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *auth.Claims
+			h := JWTAuth("secret")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = GetClaims(r.Context())
+			}))
+			req := httptest.NewRequest("GET", "/api/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && (got == nil || got.UserID != "u1") {
+				t.Errorf("claims = %+v", got)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims *auth.Claims
+		want   int
+	}{
+		{"allowed", &auth.Claims{Role: "admin"}, http.StatusOK},
+		{"other role", &auth.Claims{Role: "member"}, http.StatusForbidden},
+		{"operator is not an org role", &auth.Claims{Role: OperatorRole}, http.StatusForbidden},
+		{"no claims", nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := RequireRole("reviewer", "admin")(func(w http.ResponseWriter, r *http.Request) {})
+			req := httptest.NewRequest("POST", "/api/reviews", nil)
+			if tt.claims != nil {
+				req = req.WithContext(WithClaims(req.Context(), tt.claims))
+			}
+			rec := httptest.NewRecorder()
+			h(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
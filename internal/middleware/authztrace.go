@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+// AuthzTraceHeader is sent by a client to ask for the authorization trace
+// of its request, and carries the trace in the response.
+const AuthzTraceHeader = "X-Authz-Trace"
+
+type authzTraceKey struct{}
+
+type guardKey struct{}
+
+// authzTrace collects the authorization decisions taken for one request.
+type authzTrace struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (t *authzTrace) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return strings.Join(t.steps, "; ")
+}
+
+// TraceDecision records an authorization decision for the request, such as
+// a role, organization or policy check, in the request's trace if it asked
+// for one and in the debug log.
+func TraceDecision(ctx context.Context, check string, allowed bool, detail string) {
+	slog.DebugContext(ctx, "authorization decision", "check", check, "allowed", allowed, "detail", detail)
+	t, ok := ctx.Value(authzTraceKey{}).(*authzTrace)
+	if !ok {
+		return
+	}
+	outcome := "allow"
+	if !allowed {
+		outcome = "deny"
+	}
+	step := check + "=" + outcome
+	if detail != "" {
+		step += " (" + detail + ")"
+	}
+	t.mu.Lock()
+	t.steps = append(t.steps, step)
+	t.mu.Unlock()
+}
+
+// AuthzTrace returns the chain of authorization decisions in the
+// X-Authz-Trace response header when the request sends X-Authz-Trace.
+// Traces reveal policy details, so only org admins and operators get them.
+func AuthzTrace(operators []string) mux.MiddlewareFunc {
+	allowed := newOperatorSet(operators)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(AuthzTraceHeader) == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			claims, ok := GetClaims(r.Context())
+			if !ok || (claims.Role != "admin" && !allowed.contains(claims)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			trace := &authzTrace{}
+			ctx := context.WithValue(r.Context(), authzTraceKey{}, trace)
+			trace.steps = append(trace.steps, fmt.Sprintf("identity=allow (user %s, org %s, role %s)", claims.UserID, claims.OrgID, claims.Role))
+			next.ServeHTTP(&traceWriter{ResponseWriter: w, trace: trace}, r.WithContext(ctx))
+		})
+	}
+}
+
+// Traced records whether check let the request through. It instruments
+// guards such as RequireRole without changing them.
+func Traced(name string, check func(http.HandlerFunc) http.HandlerFunc) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		guarded := check(func(w http.ResponseWriter, r *http.Request) {
+			if g, ok := r.Context().Value(guardKey{}).(*guardWriter); ok {
+				g.passed = true
+			}
+			TraceDecision(r.Context(), name, true, "")
+			next(w, r)
+		})
+		return func(w http.ResponseWriter, r *http.Request) {
+			if _, tracing := r.Context().Value(authzTraceKey{}).(*authzTrace); !tracing {
+				guarded(w, r)
+				return
+			}
+			g := &guardWriter{ResponseWriter: w, ctx: r.Context(), name: name}
+			guarded(g, r.WithContext(context.WithValue(r.Context(), guardKey{}, g)))
+		}
+	}
+}
+
+// guardWriter records a denial when a guard responds without passing the
+// request on, before the response headers (and so the trace) are sent.
+type guardWriter struct {
+	http.ResponseWriter
+	ctx    context.Context
+	name   string
+	passed bool
+	denied bool
+}
+
+func (g *guardWriter) WriteHeader(code int) {
+	if !g.passed && !g.denied {
+		g.denied = true
+		TraceDecision(g.ctx, g.name, false, fmt.Sprintf("%d %s", code, http.StatusText(code)))
+	}
+	g.ResponseWriter.WriteHeader(code)
+}
+
+func (g *guardWriter) Write(p []byte) (int, error) {
+	if !g.passed && !g.denied {
+		g.WriteHeader(http.StatusOK)
+	}
+	return g.ResponseWriter.Write(p)
+}
+
+func (g *guardWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// traceWriter adds the trace header when the response headers are written.
+type traceWriter struct {
+	http.ResponseWriter
+	trace       *authzTrace
+	wroteHeader bool
+}
+
+func (t *traceWriter) WriteHeader(code int) {
+	if !t.wroteHeader {
+		t.wroteHeader = true
+		t.Header().Set(AuthzTraceHeader, t.trace.String())
+	}
+	t.ResponseWriter.WriteHeader(code)
+}
+
+func (t *traceWriter) Write(p []byte) (int, error) {
+	if !t.wroteHeader {
+		t.WriteHeader(http.StatusOK)
+	}
+	return t.ResponseWriter.Write(p)
+}
+
+func (t *traceWriter) Unwrap() http.ResponseWriter {
+	return t.ResponseWriter
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/gorilla/mux"
+)
+
+type breakGlassKey struct{}
+
+// BreakGlassLookup finds a user's emergency elevation and records what
+// they do with it.
+type BreakGlassLookup interface {
+	ActiveBreakGlass(userID string, t time.Time) (models.BreakGlass, bool)
+	AppendAudit(e *models.AuditEvent)
+}
+
+// BreakGlass marks requests from users with an approved emergency
+// elevation in effect, so org-scoped handlers can widen what they allow
+// within the elevation's organization. Every write request made under it
+// is audit-logged.
+func BreakGlass(lookup BreakGlassLookup) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetClaims(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			b, active := lookup.ActiveBreakGlass(claims.UserID, time.Now())
+			if !active || b.OrgID != claims.OrgID {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if isWrite(r.Method) {
+				lookup.AppendAudit(&models.AuditEvent{
+					OrgID:      b.OrgID,
+					ActorID:    claims.UserID,
+					Action:     "break_glass.request",
+					TargetType: "break_glass",
+					TargetID:   b.ID,
+					Reason:     b.Justification,
+					Metadata:   map[string]string{"method": r.Method, "path": r.URL.Path},
+				})
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), breakGlassKey{}, b)))
+		})
+	}
+}
+
+// Elevated returns the emergency elevation the request runs under, if
+// any. It is scoped to b.OrgID and never grants operator access.
+func Elevated(ctx context.Context) (b models.BreakGlass, ok bool) {
+	b, ok = ctx.Value(breakGlassKey{}).(models.BreakGlass)
+	return b, ok
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+type fakeBreakGlass struct {
+	grants map[string]models.BreakGlass
+	audit  []*models.AuditEvent
+}
+
+func (f *fakeBreakGlass) ActiveBreakGlass(userID string, t time.Time) (models.BreakGlass, bool) {
+	b, ok := f.grants[userID]
+	return b, ok && b.Active(t)
+}
+
+func (f *fakeBreakGlass) AppendAudit(e *models.AuditEvent) { f.audit = append(f.audit, e) }
+
+func TestBreakGlass(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	granted := func(orgID string) models.BreakGlass {
+		return models.BreakGlass{ID: "bg-" + orgID, OrgID: orgID, GrantedAt: &now, ExpiresAt: &later}
+	}
+	lookup := &fakeBreakGlass{grants: map[string]models.BreakGlass{
+		"elevated": granted("acme"),
+		"moved":    granted("globex"),
+		"pending":  {ID: "bg-pending", OrgID: "acme", RequestedAt: now},
+	}}
+
+	tests := []struct {
+		name         string
+		userID       string
+		wantElevated bool
+	}{
+		{"no elevation", "plain", false},
+		{"approved in own org", "elevated", true},
+		{"approved in another org", "moved", false},
+		{"not approved yet", "pending", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookup.audit = nil
+			var elevated bool
+			operatorOnly := RequireOperator(nil)(func(w http.ResponseWriter, r *http.Request) {})
+			h := BreakGlass(lookup)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, elevated = Elevated(r.Context())
+				operatorOnly(w, r)
+			}))
+			claims := &auth.Claims{UserID: tt.userID, OrgID: "acme", Role: "admin"}
+			req := httptest.NewRequest("POST", "/api/admin/orgs", nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req.WithContext(WithClaims(req.Context(), claims)))
+
+			if elevated != tt.wantElevated {
+				t.Errorf("elevated = %v, want %v", elevated, tt.wantElevated)
+			}
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("RequireOperator status = %d, want %d", rec.Code, http.StatusForbidden)
+			}
+			if audited := len(lookup.audit) == 1; audited != tt.wantElevated {
+				t.Errorf("write audited = %v, want %v", audited, tt.wantElevated)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// SparseFields trims successful JSON responses to the fields named in
+// ?fields=, a comma-separated list such as "title,status,assignee", so
+// that clients on slow links receive only what they need. It applies to
+// the top-level object, or to each object of a top-level array; dotted
+// names such as "risk.level" select within nested objects. "id" is always
+// kept. Unknown names are ignored, and other responses, such as errors and
+// downloads, pass through unchanged.
+func SparseFields(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields := parseFields(r.URL.Query().Get("fields"))
+		if fields == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		sw := &shapingWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r)
+		if sw.buf == nil {
+			return
+		}
+		body := sw.buf.Bytes()
+		if v, ok := decodeJSON(body); ok {
+			if shaped, err := json.Marshal(selectFields(v, fields)); err == nil {
+				body = append(shaped, '\n')
+			}
+		}
+		h := w.Header()
+		h.Set("Content-Length", strconv.Itoa(len(body)))
+		// The trimmed body is not the representation the tag was made for.
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+		w.WriteHeader(sw.status)
+		w.Write(body)
+	})
+}
+
+// decodeJSON decodes a response body holding a single JSON value. Numbers
+// are kept as written, so IDs and amounts beyond float64's precision come
+// out of the trimmed response unchanged.
+func decodeJSON(body []byte) (interface{}, bool) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if dec.Decode(&v) != nil {
+		return nil, false
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, false
+	}
+	return v, true
+}
+
+// fieldTree holds the selected field names, each with the names selected
+// within it, or nil to keep it whole.
+type fieldTree map[string]fieldTree
+
+// parseFields parses the value of ?fields=, returning nil if it names no
+// field.
+func parseFields(param string) fieldTree {
+	var tree fieldTree
+	for _, name := range strings.Split(param, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if tree == nil {
+			tree = fieldTree{"id": nil}
+		}
+		node := tree
+		parts := strings.Split(name, ".")
+		for i, part := range parts {
+			child, seen := node[part]
+			if i == len(parts)-1 || (seen && child == nil) {
+				// Selecting a field whole wins over selecting within it.
+				node[part] = nil
+				break
+			}
+			if child == nil {
+				child = fieldTree{}
+				node[part] = child
+			}
+			node = child
+		}
+	}
+	return tree
+}
+
+func selectFields(v interface{}, fields fieldTree) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(fields))
+		for name, sub := range fields {
+			val, ok := v[name]
+			if !ok {
+				continue
+			}
+			if sub != nil {
+				val = selectFields(val, sub)
+			}
+			out[name] = val
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = selectFields(item, fields)
+		}
+		return out
+	}
+	return v
+}
+
+// shapingWriter holds back a successful JSON response for SparseFields to
+// trim, and passes any other response straight through.
+type shapingWriter struct {
+	http.ResponseWriter
+	status int
+	// buf holds the body of a response being held back.
+	buf *bytes.Buffer
+}
+
+func (s *shapingWriter) WriteHeader(code int) {
+	if s.status != 0 {
+		return
+	}
+	s.status = code
+	mediaType, _, _ := mime.ParseMediaType(s.Header().Get("Content-Type"))
+	if code >= 200 && code < 300 && code != http.StatusNoContent && mediaType == "application/json" {
+		s.buf = new(bytes.Buffer)
+		return
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *shapingWriter) Write(p []byte) (int, error) {
+	if s.status == 0 {
+		s.WriteHeader(http.StatusOK)
+	}
+	if s.buf != nil {
+		return s.buf.Write(p)
+	}
+	return s.ResponseWriter.Write(p)
+}
+
+// Flush flushes responses passed through, so streams keep streaming.
+func (s *shapingWriter) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok && s.buf == nil {
+		f.Flush()
+	}
+}
+
+func (s *shapingWriter) Unwrap() http.ResponseWriter { return s.ResponseWriter }
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSparseFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		fields      string
+		contentType string
+		status      int
+		body        string
+		want        string
+	}{
+		{
+			name:   "top-level fields",
+			fields: "title,status",
+			body:   `{"id":"r1","title":"Q3","status":"pending","content":"..."}`,
+			want:   `{"id":"r1","status":"pending","title":"Q3"}`,
+		},
+		{
+			name:   "each object of an array",
+			fields: "title",
+			body:   `[{"id":"r1","title":"Q3","content":"..."},{"id":"r2","title":"Q4"}]`,
+			want:   `[{"id":"r1","title":"Q3"},{"id":"r2","title":"Q4"}]`,
+		},
+		{
+			name:   "nested fields",
+			fields: "risk.level,assignee",
+			body:   `{"id":"r1","risk":{"level":"high","score":91},"assignee":{"id":"u1","name":"Alice"}}`,
+			want:   `{"assignee":{"id":"u1","name":"Alice"},"id":"r1","risk":{"level":"high"}}`,
+		},
+		{
+			name:   "whole field wins over nested",
+			fields: "risk.level,risk",
+			body:   `{"id":"r1","risk":{"level":"high","score":91}}`,
+			want:   `{"id":"r1","risk":{"level":"high","score":91}}`,
+		},
+		{
+			name:   "unknown fields",
+			fields: "nope,risk.nope",
+			body:   `{"id":"r1","title":"Q3","risk":{"level":"high"}}`,
+			want:   `{"id":"r1","risk":{}}`,
+		},
+		{
+			name:   "large numbers",
+			fields: "amount",
+			body:   `{"id":9007199254740993,"amount":12345678901234567890.25,"title":"Q3"}`,
+			want:   `{"amount":12345678901234567890.25,"id":9007199254740993}`,
+		},
+		{
+			name:        "non-JSON response",
+			fields:      "title",
+			contentType: "text/csv",
+			body:        "id,title\nr1,Q3\n",
+			want:        "id,title\nr1,Q3\n",
+		},
+		{
+			name:   "malformed JSON",
+			fields: "title",
+			body:   `{"id":"r1","title":`,
+			want:   `{"id":"r1","title":`,
+		},
+		{
+			name:   "trailing data",
+			fields: "title",
+			body:   `{"id":"r1","title":"Q3"} {"id":"r2"}`,
+			want:   `{"id":"r1","title":"Q3"} {"id":"r2"}`,
+		},
+		{
+			name:   "error response",
+			fields: "title",
+			status: http.StatusNotFound,
+			body:   `{"error":"Review not found"}`,
+			want:   `{"error":"Review not found"}`,
+		},
+		{
+			name: "no fields",
+			body: `{"id":"r1","title":"Q3"}`,
+			want: `{"id":"r1","title":"Q3"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := SparseFields(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentType := tt.contentType
+				if contentType == "" {
+					contentType = "application/json; charset=utf-8"
+				}
+				w.Header().Set("Content-Type", contentType)
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				w.Write([]byte(tt.body))
+			}))
+			req := httptest.NewRequest("GET", "/api/reviews?fields="+tt.fields, nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if got := strings.TrimSuffix(rec.Body.String(), "\n"); got != strings.TrimSuffix(tt.want, "\n") {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
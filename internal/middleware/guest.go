@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/gorilla/mux"
+)
+
+// GuestRole is the org-scoped role of external collaborators such as
+// contractors. Guests see only the reviews shared with them individually.
+const GuestRole = "guest"
+
+// Limiter counts events per key, like security.RateLimiter.
+type Limiter interface {
+	Allow(key string) (bool, time.Time)
+}
+
+// RestrictGuests confines guests to the given routes, written as "METHOD
+// /path/template" (e.g. "GET /api/shared-reviews/{id}"), and counts their
+// requests against their own per-user limit. Everyone else passes.
+func RestrictGuests(limiter Limiter, routes ...string) mux.MiddlewareFunc {
+	allowed := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		allowed[route] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetClaims(r.Context())
+			if !ok || claims.Role != GuestRole {
+				next.ServeHTTP(w, r)
+				return
+			}
+			lang := i18n.FromContext(r.Context())
+			if ok, until := limiter.Allow(claims.UserID); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(until).Seconds())+1))
+				http.Error(w, i18n.T(lang, "Too many requests"), http.StatusTooManyRequests)
+				return
+			}
+			var template string
+			if route := mux.CurrentRoute(r); route != nil {
+				template, _ = route.GetPathTemplate()
+			}
+			permitted := allowed[r.Method+" "+template]
+			TraceDecision(r.Context(), "guest", permitted, r.Method+" "+template)
+			if !permitted {
+				http.Error(w, i18n.T(lang, "Forbidden: guests can only access reviews shared with them"), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// KillSwitches reports whether a capability is disabled.
+type KillSwitches interface {
+	KillSwitch(capability, orgID string) (models.KillSwitch, bool)
+}
+
+// Capability rejects requests with 503 while capability is disabled
+// instance-wide or for the request's organization. orgOf finds the
+// organization of public requests; when nil, the caller's is used.
+// Operators are not exempt: a switch stops the capability for everyone.
+func Capability(switches KillSwitches, capability string, orgOf func(*http.Request) string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var orgID string
+			if orgOf != nil {
+				orgID = orgOf(r)
+			} else if claims, ok := GetClaims(r.Context()); ok {
+				orgID = claims.OrgID
+			}
+			if k, disabled := switches.KillSwitch(capability, orgID); disabled {
+				TraceDecision(r.Context(), "kill_switch", false, k.Error())
+				lang := i18n.FromContext(r.Context())
+				msg := i18n.T(lang, "Service Unavailable: %s is temporarily disabled", capability)
+				if k.Reason != "" {
+					msg += " (" + k.Reason + ")"
+				}
+				w.Header().Set("X-Aurea-Disabled", capability)
+				http.Error(w, msg, http.StatusServiceUnavailable)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
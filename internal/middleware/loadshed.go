@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+)
+
+// ShedOptions configure the load shedder. Zero values take the defaults.
+type ShedOptions struct {
+	// TargetLatency is the request latency above which the concurrency
+	// limit is cut. Default 500ms.
+	TargetLatency time.Duration
+	// MaxGoroutines sheds all requests while more goroutines are running.
+	// Default 10000.
+	MaxGoroutines int
+	// MinLimit and MaxLimit bound the concurrency limit. Defaults 10 and 1000.
+	MinLimit int
+	MaxLimit int
+	// Exempt path prefixes are never shed, so health checks and operators
+	// can still reach an overloaded server.
+	Exempt []string
+	// Streams picks out long downloads, such as exports and the ranged
+	// requests that resume them. They are held to their own fixed limit,
+	// StreamLimit (default 20), and their duration does not cut the limit
+	// of other requests, so a burst of short requests does not shed a
+	// download that is midway through.
+	Streams     func(r *http.Request) bool
+	StreamLimit int
+}
+
+func (o ShedOptions) withDefaults() ShedOptions {
+	if o.TargetLatency == 0 {
+		o.TargetLatency = 500 * time.Millisecond
+	}
+	if o.MaxGoroutines == 0 {
+		o.MaxGoroutines = 10000
+	}
+	if o.MinLimit == 0 {
+		o.MinLimit = 10
+	}
+	if o.MaxLimit == 0 {
+		o.MaxLimit = 1000
+	}
+	if o.StreamLimit == 0 {
+		o.StreamLimit = 20
+	}
+	return o
+}
+
+// ShedStats describe the load shedder's state.
+type ShedStats struct {
+	Limit    int   `json:"limit"`
+	InFlight int   `json:"in_flight"`
+	Streams  int   `json:"streams"`
+	Shed     int64 `json:"shed"`
+}
+
+// LoadShedder limits concurrent requests with an AIMD limit: every request
+// that finishes within the target latency raises the limit slightly, and a
+// slow one cuts it by a quarter, at most once per target latency. Requests
+// over the limit, or arriving while too many goroutines run, get 503 with
+// Retry-After instead of queueing behind work the server cannot finish.
+type LoadShedder struct {
+	opts ShedOptions
+
+	mu           sync.Mutex
+	limit        float64
+	inFlight     int
+	streams      int
+	shed         int64
+	lastDecrease time.Time
+}
+
+// NewLoadShedder creates a load shedder starting at the middle of its range.
+func NewLoadShedder(opts ShedOptions) *LoadShedder {
+	opts = opts.withDefaults()
+	return &LoadShedder{opts: opts, limit: float64(opts.MinLimit+opts.MaxLimit) / 2}
+}
+
+// Handler sheds excess requests to next.
+func (s *LoadShedder) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, prefix := range s.opts.Exempt {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		if s.opts.Streams != nil && s.opts.Streams(r) {
+			if !s.acquireStream() {
+				shedResponse(w, r)
+				return
+			}
+			defer s.releaseStream()
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !s.acquire() {
+			shedResponse(w, r)
+			return
+		}
+		start := time.Now()
+		defer func() { s.release(time.Since(start)) }()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *LoadShedder) acquire() bool {
+	overloaded := runtime.NumGoroutine() > s.opts.MaxGoroutines
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if overloaded || s.inFlight >= int(s.limit) {
+		s.shed++
+		return false
+	}
+	s.inFlight++
+	return true
+}
+
+func shedResponse(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Retry-After", "2")
+	http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Service Unavailable: server is overloaded, retry shortly"), http.StatusServiceUnavailable)
+}
+
+func (s *LoadShedder) acquireStream() bool {
+	overloaded := runtime.NumGoroutine() > s.opts.MaxGoroutines
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if overloaded || s.streams >= s.opts.StreamLimit {
+		s.shed++
+		return false
+	}
+	s.streams++
+	return true
+}
+
+func (s *LoadShedder) releaseStream() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.streams--
+}
+
+func (s *LoadShedder) release(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight--
+	now := time.Now()
+	if latency > s.opts.TargetLatency {
+		if now.Sub(s.lastDecrease) >= s.opts.TargetLatency {
+			s.limit = math.Max(float64(s.opts.MinLimit), s.limit*0.75)
+			s.lastDecrease = now
+		}
+		return
+	}
+	s.limit = math.Min(float64(s.opts.MaxLimit), s.limit+1/s.limit)
+}
+
+// Stats returns the current limit, in-flight requests and streams, and
+// requests shed since start.
+func (s *LoadShedder) Stats() ShedStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return ShedStats{Limit: int(s.limit), InFlight: s.inFlight, Streams: s.streams, Shed: s.shed}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoadShedderStreams(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	shedder := NewLoadShedder(ShedOptions{
+		MinLimit:    1,
+		MaxLimit:    1,
+		StreamLimit: 1,
+		Streams:     func(r *http.Request) bool { return strings.HasSuffix(r.URL.Path, "/export") },
+	})
+	h := shedder.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("block") != "" {
+			started <- struct{}{}
+			<-release
+		}
+	}))
+	serve := func(target string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", target, nil))
+		return rec.Code
+	}
+
+	// A download midway through holds the only stream slot.
+	done := make(chan int)
+	go func() { done <- serve("/api/orgs/acme/export?block=1") }()
+	<-started
+
+	tests := []struct {
+		name   string
+		target string
+		want   int
+	}{
+		{"request beside a stream", "/api/reviews", http.StatusOK},
+		{"second stream", "/api/orgs/globex/export", http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serve(tt.target); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+	close(release)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("stream status = %d", code)
+	}
+	if st := shedder.Stats(); st.Streams != 0 || st.InFlight != 0 || st.Shed != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// ProfileLookup returns a user's saved profile.
+type ProfileLookup interface {
+	GetProfile(userID string) models.UserProfile
+}
+
+// Locale selects the response language from the caller's saved locale or
+// the Accept-Language header and attaches it to the request context and
+// response writer.
+func Locale(profiles ProfileLookup) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var userLocale string
+			if claims, ok := GetClaims(r.Context()); ok {
+				userLocale = profiles.GetProfile(claims.UserID).Locale
+			}
+			lang := i18n.Negotiate(userLocale, r.Header.Get("Accept-Language"))
+
+			w.Header().Set("Content-Language", lang)
+			next.ServeHTTP(&i18n.Writer{ResponseWriter: w, Lang: lang}, r.WithContext(i18n.WithLang(r.Context(), lang)))
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/gorilla/mux"
+)
+
+// NetworkPolicyLookup finds organization network policies and records the
+// requests they refuse.
+type NetworkPolicyLookup interface {
+	NetworkPolicy(orgID string) models.NetworkPolicy
+	AddSecurityEvent(e *models.SecurityEvent)
+}
+
+// RestrictNetwork refuses requests from outside the caller's organization
+// allowlist and records each refusal as a security event. Operators are
+// not bound by organization policies, so they can lift a policy that
+// locked an organization out.
+func RestrictNetwork(policies NetworkPolicyLookup, operators []string, proxies security.TrustedProxies) mux.MiddlewareFunc {
+	allowed := newOperatorSet(operators)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetClaims(r.Context())
+			if !ok || claims.OrgID == "" || allowed.contains(claims) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ip := security.ClientIP(r, proxies)
+			permitted := policies.NetworkPolicy(claims.OrgID).Allows(ip)
+			TraceDecision(r.Context(), "network_policy", permitted, "client "+ip)
+			if !permitted {
+				policies.AddSecurityEvent(&models.SecurityEvent{
+					OrgID:     claims.OrgID,
+					UserID:    claims.UserID,
+					Type:      models.SecurityIPNotAllowed,
+					IP:        ip,
+					Device:    security.Fingerprint(r),
+					Detail:    r.Method + " " + r.URL.Path,
+					CreatedAt: time.Now(),
+				})
+				http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Forbidden: your network is not allowed by this organization"), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+)
+
+type networkPolicies struct {
+	policy models.NetworkPolicy
+	events []*models.SecurityEvent
+}
+
+func (p *networkPolicies) NetworkPolicy(string) models.NetworkPolicy { return p.policy }
+func (p *networkPolicies) AddSecurityEvent(e *models.SecurityEvent)  { p.events = append(p.events, e) }
+
+func TestRestrictNetwork(t *testing.T) {
+	proxies := security.TrustedProxies{netip.MustParsePrefix("10.0.0.0/8")}
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		want      int
+	}{
+		{"allowed client", "203.0.113.7:4000", nil, http.StatusOK},
+		{"other client", "198.51.100.9:4000", nil, http.StatusForbidden},
+		{"spoofed header without a proxy", "198.51.100.9:4000", []string{"203.0.113.7"}, http.StatusForbidden},
+		{"allowed client behind the proxy", "10.0.0.2:4000", []string{"203.0.113.7"}, http.StatusOK},
+		{"spoofed header behind the proxy", "10.0.0.2:4000", []string{"203.0.113.7, 198.51.100.9"}, http.StatusForbidden},
+		{"spoofed header split across lines", "10.0.0.2:4000", []string{"203.0.113.7", "198.51.100.9"}, http.StatusForbidden},
+		{"allowed client behind two proxies", "10.0.0.2:4000", []string{"198.51.100.9, 203.0.113.7, 10.0.0.3"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies := &networkPolicies{policy: models.NetworkPolicy{OrgID: "acme", CIDRs: []string{"203.0.113.0/24"}}}
+			h := RestrictNetwork(policies, nil, proxies)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+			req := httptest.NewRequest("GET", "/api/reviews", nil)
+			req.RemoteAddr = tt.remote
+			for _, v := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+			req = req.WithContext(WithClaims(req.Context(), &auth.Claims{UserID: "u1", OrgID: "acme", Role: "member"}))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if refused := tt.want == http.StatusForbidden; refused != (len(policies.events) == 1) {
+				t.Errorf("security events = %d", len(policies.events))
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+)
+
+// OperatorRole is the instance-wide role that sits above every org-scoped role.
+const OperatorRole = "operator"
+
+// operatorSet identifies instance operators: users whose token carries the
+// operator role or whose email is on the configured allowlist.
+type operatorSet map[string]bool
+
+func newOperatorSet(operators []string) operatorSet {
+	set := make(operatorSet, len(operators))
+	for _, email := range operators {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			set[email] = true
+		}
+	}
+	return set
+}
+
+func (s operatorSet) contains(claims *auth.Claims) bool {
+	return claims.Role == OperatorRole || s[strings.ToLower(claims.Email)]
+}
+
+// RequireOperator restricts a handler to instance operators. Org-scoped
+// admins are never operators, break-glass elevation included.
+func RequireOperator(operators []string) func(http.HandlerFunc) http.HandlerFunc {
+	allowed := newOperatorSet(operators)
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetClaims(r.Context())
+			if !ok {
+				http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Unauthorized"), http.StatusUnauthorized)
+				return
+			}
+			if !allowed.contains(claims) {
+				http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Forbidden: operator access required"), http.StatusForbidden)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
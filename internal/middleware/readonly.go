@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// WriteGate reports the state that can block write requests.
+type WriteGate interface {
+	GetSuspension(orgID string) (*models.OrgSuspension, bool)
+	Maintenance() models.MaintenanceMode
+}
+
+// ReadOnly rejects write requests while the instance is in maintenance mode
+// (503) or the caller's organization is suspended (403). Reads always pass so
+// members can still browse and export their data. Operators are exempt so
+// they can lift either restriction.
+func ReadOnly(gate WriteGate, operators []string) mux.MiddlewareFunc {
+	allowed := newOperatorSet(operators)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isWrite(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, ok := GetClaims(r.Context())
+			if ok && allowed.contains(claims) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if m := gate.Maintenance(); m.Enabled {
+				TraceDecision(r.Context(), "maintenance", false, "instance is read-only")
+				w.Header().Set("Retry-After", "300")
+				http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Service Unavailable: instance is in read-only maintenance mode"), http.StatusServiceUnavailable)
+				return
+			}
+			if ok {
+				if _, suspended := gate.GetSuspension(claims.OrgID); suspended {
+					TraceDecision(r.Context(), "suspension", false, "organization "+claims.OrgID+" is suspended")
+					http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Forbidden: organization is suspended"), http.StatusForbidden)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// ReplicaReadOnly rejects every write request with 503. Replicas only apply
+// changes received from the primary, so clients must write to the primary.
+func ReplicaReadOnly() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isWrite(r.Method) {
+				http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Service Unavailable: this instance is a read-only replica"), http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
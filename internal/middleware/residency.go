@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/gorilla/mux"
+)
+
+// ForwardedRegionHeader marks requests already forwarded by Residency, so a
+// misconfigured peer cannot bounce a request back and forth.
+const ForwardedRegionHeader = "X-Aurea-Forwarded-From"
+
+// RegionLookup returns the region an organization is pinned to.
+type RegionLookup interface {
+	OrgRegion(orgID string) string
+}
+
+// ParseRegions parses "region=https://host,region=https://host" into the
+// base URL of each regional deployment.
+func ParseRegions(spec string) (map[string]*url.URL, error) {
+	regions := make(map[string]*url.URL)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(entry, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("region %q is not name=url", entry)
+		}
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("region %q has an invalid URL", name)
+		}
+		regions[name] = u
+	}
+	return regions, nil
+}
+
+// Residency routes each authenticated request to the deployment in the
+// region the caller's organization is pinned to, so its data is only read
+// and written there. Requests for unpinned or local organizations, and
+// instance-wide operator endpoints, are served locally.
+func Residency(lookup RegionLookup, local string, regions map[string]*url.URL) mux.MiddlewareFunc {
+	proxies := make(map[string]*httputil.ReverseProxy, len(regions))
+	for name, u := range regions {
+		if name != local {
+			proxies[name] = httputil.NewSingleHostReverseProxy(u)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := GetClaims(r.Context())
+			if !ok || isInstanceEndpoint(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			region := lookup.OrgRegion(claims.OrgID)
+			if region == "" || region == local {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			proxy, known := proxies[region]
+			if !known || r.Header.Get(ForwardedRegionHeader) != "" {
+				http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Misdirected Request: organization data is hosted in another region"), http.StatusMisdirectedRequest)
+				return
+			}
+			r.Header.Set(ForwardedRegionHeader, local)
+			proxy.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isInstanceEndpoint(path string) bool {
+	return strings.HasPrefix(path, "/api/admin/") || strings.HasPrefix(path, "/api/manage/")
+}
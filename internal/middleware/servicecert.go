@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"crypto/x509"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// ServiceIdentityLookup resolves service identities by certificate name.
+type ServiceIdentityLookup interface {
+	UseServiceIdentity(names []string, at time.Time) (models.ServiceIdentity, error)
+}
+
+// ServiceCertAuth authenticates service callers on the mTLS listener by
+// their verified client certificate. The certificate's common name or a SAN
+// selects the service identity; any Authorization header the caller sent
+// is replaced by a bearer token carrying the identity's organization and
+// role, so the JWT middleware and role checks apply unchanged. Requests
+// outside the identity's scopes are refused. Requests without a verified
+// certificate pass untouched. It must run before APIKeyAuth and JWTAuth.
+func ServiceCertAuth(services ServiceIdentityLookup, issuer TokenIssuer) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			locale := i18n.FromContext(r.Context())
+			svc, err := services.UseServiceIdentity(certNames(r.TLS.VerifiedChains[0][0]), time.Now())
+			if err != nil {
+				http.Error(w, i18n.T(locale, "Unauthorized: unknown service certificate"), http.StatusUnauthorized)
+				return
+			}
+			permitted := svc.Permits(r.Method, r.URL.Path)
+			TraceDecision(r.Context(), "service_scope", permitted, "service "+svc.Name)
+			if !permitted {
+				http.Error(w, i18n.T(locale, "Forbidden: outside the service's scopes"), http.StatusForbidden)
+				return
+			}
+			token, err := issuer.GenerateToken("service:"+svc.ID, "", svc.OrgID, svc.Role)
+			if err != nil {
+				http.Error(w, i18n.T(locale, "Internal server error"), http.StatusInternalServerError)
+				return
+			}
+			r = r.Clone(r.Context())
+			r.Header.Set("Authorization", "Bearer "+token)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// certNames returns the names a service certificate can be matched by.
+func certNames(cert *x509.Certificate) []string {
+	names := []string{cert.Subject.CommonName}
+	names = append(names, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		names = append(names, u.String())
+	}
+	return names
+}
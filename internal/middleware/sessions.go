@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/tokenbind"
+	"github.com/gorilla/mux"
+)
+
+// sessionTouchInterval limits how often a session's last use is recorded.
+const sessionTouchInterval = time.Minute
+
+// SessionLookup finds the session of a login token and keeps it current.
+type SessionLookup interface {
+	SessionByToken(tokenHash string) (models.Session, bool)
+	TouchSession(id, ip string, at time.Time)
+	EndSession(userID, id, reason string, at time.Time) (models.Session, error)
+	GetPolicy(orgID string) models.OrgPolicy
+}
+
+// Sessions refuses tokens whose session was revoked, ends sessions idle
+// for longer than the organization's policy allows, and records where
+// each session was last used from. Tokens not issued by a login, such as
+// those minted for API keys, pass.
+func Sessions(lookup SessionLookup, proxies security.TrustedProxies) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			s, tracked := lookup.SessionByToken(tokenbind.TokenHash(token))
+			if !tracked {
+				next.ServeHTTP(w, r)
+				return
+			}
+			locale := i18n.FromContext(r.Context())
+			now := time.Now()
+			if !s.Active(now) {
+				http.Error(w, i18n.T(locale, "Unauthorized: session has been signed out"), http.StatusUnauthorized)
+				return
+			}
+			if idle := lookup.GetPolicy(s.OrgID).SessionIdleMinutes; idle > 0 && now.Sub(s.LastSeenAt) > time.Duration(idle)*time.Minute {
+				lookup.EndSession("", s.ID, models.SessionIdle, now)
+				http.Error(w, i18n.T(locale, "Unauthorized: session expired after inactivity"), http.StatusUnauthorized)
+				return
+			}
+			if ip := security.ClientIP(r, proxies); ip != s.IP || now.Sub(s.LastSeenAt) >= sessionTouchInterval {
+				lookup.TouchSession(s.ID, ip, now)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/reqsign"
+)
+
+type signedKeys map[string]*models.APIKey
+
+func (k signedKeys) UseAPIKeyByID(id string, at time.Time) (*models.APIKey, error) {
+	if key, ok := k[id]; ok {
+		return key, nil
+	}
+	return nil, store.ErrNotFound
+}
+
+func TestSignedRequestAuth(t *testing.T) {
+	const apiKey = APIKeyPrefix + "0123456789abcdef"
+	key := &models.APIKey{ID: "key-1", OrgID: "acme", Role: "reviewer", Hash: HashAPIKey(apiKey)}
+	keys := signedKeys{key.ID: key}
+	tokens := auth.NewService("jwt secret", time.Hour)
+	secret := reqsign.SigningSecret("jwt secret", key.Hash)
+
+	tests := []struct {
+		name  string
+		keyID string
+		sign  string
+		want  int
+	}{
+		{"signing secret", key.ID, secret, http.StatusOK},
+		{"stored hash", key.ID, key.Hash, http.StatusUnauthorized},
+		{"API key", key.ID, apiKey, http.StatusUnauthorized},
+		{"unknown key", "key-2", secret, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claims *auth.Claims
+			h := SignedRequestAuth(keys, tokens, "jwt secret")(JWTAuth("jwt secret")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				claims, _ = GetClaims(r.Context())
+			})))
+			body := `{"title":"x"}`
+			req := httptest.NewRequest("POST", "/api/reviews", strings.NewReader(body))
+			reqsign.SignRequest(req, tt.keyID, tt.sign, []byte(body), time.Now())
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && (claims == nil || claims.OrgID != "acme" || claims.Role != "reviewer") {
+				t.Errorf("claims = %+v", claims)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/tokenbind"
+	"github.com/gorilla/mux"
+)
+
+// TokenBindingLookup finds the client a token was bound to at login.
+type TokenBindingLookup interface {
+	TokenBinding(tokenHash string) (models.TokenBinding, bool)
+}
+
+// TokenBinding rejects bound tokens presented by another client: without
+// the client certificate they were bound to, or without a DPoP proof
+// signed by the bound key. Unbound tokens pass.
+func TokenBinding(lookup TokenBindingLookup, proofs *tokenbind.Verifier) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			b, bound := lookup.TokenBinding(tokenbind.TokenHash(token))
+			if !bound {
+				next.ServeHTTP(w, r)
+				return
+			}
+			var thumbprint string
+			switch b.Mode {
+			case tokenbind.ModeMTLS:
+				thumbprint = tokenbind.RequestCertThumbprint(r)
+			case tokenbind.ModeDPoP:
+				thumbprint, _ = proofs.Verify(r, token, time.Now())
+			}
+			matched := thumbprint != "" && thumbprint == b.Thumbprint
+			TraceDecision(r.Context(), "token_binding", matched, "token bound by "+b.Mode)
+			if !matched {
+				http.Error(w, i18n.T(i18n.FromContext(r.Context()), "Unauthorized: token is bound to another client"), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/tokenbind"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type bindings map[string]models.TokenBinding
+
+func (b bindings) TokenBinding(tokenHash string) (models.TokenBinding, bool) {
+	binding, ok := b[tokenHash]
+	return binding, ok
+}
+
+func testCert(t *testing.T, name string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: name}, NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// dpopKey is a client's DPoP key.
+type dpopKey struct {
+	priv *ecdsa.PrivateKey
+	jwk  map[string]string
+}
+
+func newDPoPKey(t *testing.T) dpopKey {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := base64.RawURLEncoding
+	return dpopKey{priv, map[string]string{
+		"kty": "EC", "crv": "P-256",
+		"x": b64.EncodeToString(priv.X.FillBytes(make([]byte, 32))),
+		"y": b64.EncodeToString(priv.Y.FillBytes(make([]byte, 32))),
+	}}
+}
+
+// thumbprint is the RFC 7638 thumbprint of the key.
+func (k dpopKey) thumbprint() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf(`{"crv":"P-256","kty":"EC","x":%q,"y":%q}`, k.jwk["x"], k.jwk["y"])))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+var proofSeq int
+
+// proof signs a DPoP proof for a GET of path with token.
+func (k dpopKey) proof(t *testing.T, path, token string) string {
+	t.Helper()
+	proofSeq++
+	ath := sha256.Sum256([]byte(token))
+	tok := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"htm": "GET", "htu": "https://aurea.example" + path, "ath": base64.RawURLEncoding.EncodeToString(ath[:]),
+		"iat": time.Now().Unix(), "jti": fmt.Sprint("proof-", proofSeq),
+	})
+	tok.Header["typ"] = "dpop+jwt"
+	tok.Header["jwk"] = k.jwk
+	s, err := tok.SignedString(k.priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestTokenBinding(t *testing.T) {
+	laptop, stolen := testCert(t, "laptop"), testCert(t, "attacker")
+	client, other := newDPoPKey(t), newDPoPKey(t)
+	lookup := bindings{
+		tokenbind.TokenHash("mtls-token"): {Mode: tokenbind.ModeMTLS, Thumbprint: tokenbind.CertThumbprint(laptop)},
+		tokenbind.TokenHash("dpop-token"): {Mode: tokenbind.ModeDPoP, Thumbprint: client.thumbprint()},
+	}
+	h := TokenBinding(lookup, tokenbind.NewVerifier())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name  string
+		token string
+		cert  *x509.Certificate
+		proof func() string
+		want  int
+	}{
+		{"unbound token", "plain-token", nil, nil, http.StatusOK},
+		{"bound certificate", "mtls-token", laptop, nil, http.StatusOK},
+		{"other certificate", "mtls-token", stolen, nil, http.StatusUnauthorized},
+		{"no certificate", "mtls-token", nil, nil, http.StatusUnauthorized},
+		{"bound DPoP key", "dpop-token", nil, func() string { return client.proof(t, "/api/reviews", "dpop-token") }, http.StatusOK},
+		{"other DPoP key", "dpop-token", nil, func() string { return other.proof(t, "/api/reviews", "dpop-token") }, http.StatusUnauthorized},
+		{"proof for another path", "dpop-token", nil, func() string { return client.proof(t, "/api/users", "dpop-token") }, http.StatusUnauthorized},
+		{"no proof", "dpop-token", nil, nil, http.StatusUnauthorized},
+		{"certificate for DPoP token", "dpop-token", laptop, nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/reviews", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			if tt.cert != nil {
+				req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{tt.cert}}
+			}
+			if tt.proof != nil {
+				req.Header.Set(tokenbind.ProofHeader, tt.proof())
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
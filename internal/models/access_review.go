@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// Access review campaign statuses.
+const (
+	CampaignOpen      = "open"
+	CampaignCompleted = "completed"
+)
+
+// Access review item decisions.
+const (
+	AccessPending    = "pending"
+	AccessCertified  = "certified"
+	AccessChanged    = "changed"
+	AccessDowngraded = "downgraded"
+)
+
+// AccessCampaign asks an organization's admins to re-certify the role of
+// every member above FallbackRole before Deadline. Members left
+// uncertified are downgraded to FallbackRole.
+type AccessCampaign struct {
+	ID           string    `json:"id"`
+	OrgID        string    `json:"org_id"`
+	Name         string    `json:"name"`
+	FallbackRole string    `json:"fallback_role"`
+	Deadline     time.Time `json:"deadline"`
+	// RepeatDays starts the next campaign that many days after this one's
+	// deadline; zero runs the campaign once.
+	RepeatDays   int        `json:"repeat_days,omitempty"`
+	Status       string     `json:"status"`
+	CreatedBy    string     `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	LastReminder *time.Time `json:"last_reminder,omitempty"`
+}
+
+// AccessItem is one member's role under review in a campaign.
+type AccessItem struct {
+	CampaignID string     `json:"campaign_id"`
+	UserID     string     `json:"user_id"`
+	Role       string     `json:"role"`
+	Decision   string     `json:"decision"`
+	NewRole    string     `json:"new_role,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+	DecidedBy  string     `json:"decided_by,omitempty"`
+	DecidedAt  *time.Time `json:"decided_at,omitempty"`
+}
+
+// AccessProgress counts a campaign's items by decision.
+type AccessProgress struct {
+	Total      int `json:"total"`
+	Pending    int `json:"pending"`
+	Certified  int `json:"certified"`
+	Changed    int `json:"changed"`
+	Downgraded int `json:"downgraded"`
+}
+
+// Add counts one item.
+func (p *AccessProgress) Add(item AccessItem) {
+	p.Total++
+	switch item.Decision {
+	case AccessPending:
+		p.Pending++
+	case AccessCertified:
+		p.Certified++
+	case AccessChanged:
+		p.Changed++
+	case AccessDowngraded:
+		p.Downgraded++
+	}
+}
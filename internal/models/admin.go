@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// OrgSuspension records why and by whom an organization was suspended.
+type OrgSuspension struct {
+	OrgID       string    `json:"org_id"`
+	Reason      string    `json:"reason"`
+	SuspendedBy string    `json:"suspended_by"`
+	SuspendedAt time.Time `json:"suspended_at"`
+}
+
+// OrgSummary is the instance-operator view of an organization.
+type OrgSummary struct {
+	Organization
+	MemberCount int            `json:"member_count"`
+	ReviewCount int            `json:"review_count"`
+	Suspension  *OrgSuspension `json:"suspension,omitempty"`
+}
+
+// InstanceStats aggregates counters across every organization.
+type InstanceStats struct {
+	Organizations   int            `json:"organizations"`
+	SuspendedOrgs   int            `json:"suspended_orgs"`
+	Users           int            `json:"users"`
+	Reviews         int            `json:"reviews"`
+	ReviewsByStatus map[string]int `json:"reviews_by_status"`
+	GeneratedAt     time.Time      `json:"generated_at"`
+}
+
+// InstanceSettings holds global, operator-managed configuration.
+type InstanceSettings struct {
+	Values    map[string]string `json:"values"`
+	UpdatedBy string            `json:"updated_by,omitempty"`
+	UpdatedAt time.Time         `json:"updated_at,omitempty"`
+}
+
+// MaintenanceMode puts the whole instance into read-only mode.
+type MaintenanceMode struct {
+	Enabled   bool      `json:"enabled"`
+	Message   string    `json:"message,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,167 @@
+package models
+
+import "time"
+
+// AI features, as recorded in LLM usage.
+const (
+	AIFeatureSummary     = "summary"
+	AIFeatureSuggestions = "suggestions"
+)
+
+// AIFeatures lists the AI features whose prompts can be customized.
+var AIFeatures = []string{AIFeatureSummary, AIFeatureSuggestions}
+
+// PromptTemplate is a version of the prompt an AI feature sends. System
+// and Prompt are text/template templates over the feature's variables;
+// the answer format the feature parses is always appended to System.
+// Version 0 is the built-in default.
+type PromptTemplate struct {
+	OrgID     string    `json:"org_id,omitempty"`
+	Feature   string    `json:"feature"`
+	Version   int       `json:"version"`
+	System    string    `json:"system"`
+	Prompt    string    `json:"prompt"`
+	Note      string    `json:"note,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReviewSummary is a language model's summary of a review.
+type ReviewSummary struct {
+	ReviewID string `json:"review_id"`
+	Summary  string `json:"summary"`
+	// Risks highlights what reviewers should look at closely.
+	Risks       []string  `json:"risks"`
+	Provider    string    `json:"provider"`
+	Model       string    `json:"model"`
+	RequestedBy string    `json:"requested_by"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// LLMUsage records the tokens one language model request used and what
+// they cost at the provider's configured or known prices.
+type LLMUsage struct {
+	ID           string    `json:"id"`
+	OrgID        string    `json:"org_id"`
+	Feature      string    `json:"feature"`
+	Provider     string    `json:"provider"`
+	Model        string    `json:"model"`
+	ReviewID     string    `json:"review_id,omitempty"`
+	UserID       string    `json:"user_id,omitempty"`
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	CostUSD      float64   `json:"cost_usd"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Language model provider types.
+const (
+	LLMOpenAI    = "openai"
+	LLMAnthropic = "anthropic"
+	LLMAzure     = "azure"
+	LLMOllama    = "ollama"
+	// LLMLocal is a self-hosted server with an OpenAI-compatible API,
+	// such as vLLM.
+	LLMLocal = "local"
+)
+
+// LLMTypes lists every provider type, for validating configurations.
+var LLMTypes = []string{LLMOpenAI, LLMAnthropic, LLMAzure, LLMOllama, LLMLocal}
+
+// LLMProviderConfig is a language model provider an organization brings
+// with its own account or runs itself. An organization's providers are
+// preferred over the instance's, lowest Priority first; requests fail
+// over down that order when a provider is unhealthy or fails.
+type LLMProviderConfig struct {
+	OrgID string `json:"org_id"`
+	// Name identifies the provider in requests and usage records.
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	BaseURL string `json:"base_url,omitempty"`
+	// Model is the default model; for Azure, the deployment.
+	Model string `json:"model,omitempty"`
+	// APIVersion is the Azure OpenAI API version.
+	APIVersion string `json:"api_version,omitempty"`
+	APIKey     string `json:"api_key,omitempty"`
+	// InputPrice and OutputPrice are in USD per million tokens. When zero,
+	// the known prices of the model are used.
+	InputPrice  float64   `json:"input_price,omitempty"`
+	OutputPrice float64   `json:"output_price,omitempty"`
+	Priority    int       `json:"priority"`
+	Disabled    bool      `json:"disabled,omitempty"`
+	UpdatedBy   string    `json:"updated_by"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AIBudget caps what an organization's AI features may cost in a calendar
+// month (UTC). Once spent, they are paused until the next month.
+type AIBudget struct {
+	OrgID      string    `json:"org_id"`
+	MonthlyUSD float64   `json:"monthly_usd"`
+	UpdatedBy  string    `json:"updated_by"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Kinds of personal data AI redaction recognizes.
+const (
+	PIIEmail      = "email"
+	PIIPhone      = "phone"
+	PIICreditCard = "credit_card"
+	PIIIBAN       = "iban"
+	PIISSN        = "ssn"
+	PIIIPAddress  = "ip_address"
+)
+
+// PIIKinds lists every kind of personal data AI redaction recognizes.
+var PIIKinds = []string{PIIEmail, PIIPhone, PIICreditCard, PIIIBAN, PIISSN, PIIIPAddress}
+
+// AIRedactionPolicy configures what is replaced with placeholders in
+// prompts before they reach a hosted language model. Answers get the
+// personal data and custom matches back; secrets come back masked.
+type AIRedactionPolicy struct {
+	OrgID   string `json:"org_id"`
+	Enabled bool   `json:"enabled"`
+	// Secrets applies the organization's secret detection rules.
+	Secrets bool `json:"secrets"`
+	// PII lists the kinds of personal data to redact.
+	PII []string `json:"pii"`
+	// Rules are further patterns to redact, such as internal host names.
+	Rules []SecretRule `json:"rules"`
+	// SelfHosted also redacts prompts sent to self-hosted models.
+	SelfHosted bool      `json:"self_hosted"`
+	UpdatedBy  string    `json:"updated_by,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
+
+// DefaultAIRedactionPolicy is the policy of organizations that did not set
+// one: secrets and the personal data other than IP addresses are redacted.
+func DefaultAIRedactionPolicy(orgID string) AIRedactionPolicy {
+	return AIRedactionPolicy{
+		OrgID:   orgID,
+		Enabled: true,
+		Secrets: true,
+		PII:     []string{PIIEmail, PIIPhone, PIICreditCard, PIIIBAN, PIISSN},
+		Rules:   []SecretRule{},
+	}
+}
+
+// AIRedaction records what was redacted from one language model request.
+// Only masked previews and fingerprints are kept, never the values.
+type AIRedaction struct {
+	ID        string             `json:"id"`
+	OrgID     string             `json:"org_id"`
+	Provider  string             `json:"provider"`
+	Entries   []AIRedactionEntry `json:"entries"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// AIRedactionEntry is one value a placeholder stood for.
+type AIRedactionEntry struct {
+	Placeholder string `json:"placeholder"`
+	// Kind is secret, a PII kind, or custom.
+	Kind        string `json:"kind"`
+	RuleID      string `json:"rule_id,omitempty"`
+	Preview     string `json:"preview"`
+	Fingerprint string `json:"fingerprint"`
+	Count       int    `json:"count"`
+}
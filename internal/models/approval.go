@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Approval is the record written when a review is approved.
+type Approval struct {
+	ReviewID   string         `json:"review_id"`
+	ApprovedBy string         `json:"approved_by"`
+	ApprovedAt time.Time      `json:"approved_at"`
+	Proof      *ApprovalProof `json:"proof,omitempty"`
+}
+
+// ApprovalProof is a tamper-evident, signed statement of an approval. The
+// signature covers Payload, so it can be verified offline with PublicKey.
+type ApprovalProof struct {
+	ReviewID    string    `json:"review_id"`
+	ContentHash string    `json:"content_hash"`
+	ApprovedBy  string    `json:"approved_by"`
+	ApprovedAt  time.Time `json:"approved_at"`
+	Algorithm   string    `json:"algorithm"`
+	KeyID       string    `json:"key_id"`
+	PublicKey   string    `json:"public_key"`
+	Payload     string    `json:"payload"`
+	Signature   string    `json:"signature"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// ApprovalGate is a rule an organization's approvals must satisfy, written
+// as expressions evaluated when a review is approved.
+type ApprovalGate struct {
+	ID    string `json:"id"`
+	OrgID string `json:"org_id"`
+	Name  string `json:"name"`
+	// When selects the approvals the gate applies to; empty applies it to
+	// every approval.
+	When string `json:"when,omitempty"`
+	// Require must hold for an approval the gate applies to.
+	Require string `json:"require"`
+	// Message explains the gate to approvers it stops.
+	Message   string    `json:"message,omitempty"`
+	Enabled   bool      `json:"enabled"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GateResult is the outcome of checking an approval against a gate.
+type GateResult struct {
+	GateID  string `json:"gate_id"`
+	Name    string `json:"name"`
+	Applies bool   `json:"applies"`
+	Passed  bool   `json:"passed"`
+	Require string `json:"require"`
+	// Message explains a failure.
+	Message string `json:"message,omitempty"`
+	// Values are the values of the fields a failed requirement reads.
+	Values map[string]interface{} `json:"values,omitempty"`
+	// Error is why the gate's expressions could not be evaluated.
+	Error string `json:"error,omitempty"`
+}
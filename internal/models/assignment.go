@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Auto-assignment strategies an organization can select in its policy.
+const (
+	AssignRoundRobin  = "round_robin"
+	AssignLeastLoaded = "least_loaded"
+	AssignExpertise   = "expertise"
+)
+
+// Assignment links a review to the reviewer responsible for it.
+type Assignment struct {
+	ReviewID   string    `json:"review_id"`
+	ReviewerID string    `json:"reviewer_id"`
+	AssignedBy string    `json:"assigned_by"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// Deactivation marks a user as inactive. The user record is kept so that
+// historical authorship stays intact.
+type Deactivation struct {
+	UserID        string    `json:"user_id"`
+	Reason        string    `json:"reason,omitempty"`
+	DeactivatedBy string    `json:"deactivated_by"`
+	DeactivatedAt time.Time `json:"deactivated_at"`
+}
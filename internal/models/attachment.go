@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Attachment scan statuses.
+const (
+	// ScanPending attachments are waiting to be scanned and cannot be
+	// downloaded yet.
+	ScanPending = "pending"
+	ScanClean   = "clean"
+	// ScanInfected attachments have been moved to quarantine.
+	ScanInfected = "infected"
+	// ScanFailed attachments could not be scanned after several attempts.
+	ScanFailed = "failed"
+	// ScanUnscanned attachments were uploaded with no scanner configured.
+	ScanUnscanned = "unscanned"
+)
+
+// Attachment is a file uploaded to a review.
+type Attachment struct {
+	ID          string         `json:"id"`
+	OrgID       string         `json:"org_id"`
+	ReviewID    string         `json:"review_id"`
+	Name        string         `json:"name"`
+	ContentType string         `json:"content_type"`
+	Size        int64          `json:"size"`
+	SHA256      string         `json:"sha256"`
+	BlobKey     string         `json:"-"`
+	UploadedBy  string         `json:"uploaded_by"`
+	UploadedAt  time.Time      `json:"uploaded_at"`
+	Scan        AttachmentScan `json:"scan"`
+}
+
+// AttachmentScan is the outcome of scanning an attachment for malware.
+type AttachmentScan struct {
+	Status    string     `json:"status"`
+	Engine    string     `json:"engine,omitempty"`
+	Signature string     `json:"signature,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	Attempts  int        `json:"attempts,omitempty"`
+	ScannedAt *time.Time `json:"scanned_at,omitempty"`
+}
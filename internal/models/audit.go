@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// AuditEvent records a security- or compliance-relevant action.
+type AuditEvent struct {
+	ID         string            `json:"id"`
+	OrgID      string            `json:"org_id"`
+	ActorID    string            `json:"actor_id"`
+	Action     string            `json:"action"`
+	TargetType string            `json:"target_type"`
+	TargetID   string            `json:"target_id"`
+	Reason     string            `json:"reason,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// BI export destinations.
+const (
+	BIDestinationHTTPS = "https"
+	BIDestinationS3    = "s3"
+)
+
+// BIExportConfig configures the daily export of an organization's review
+// lifecycle events to a data warehouse ingestion point.
+type BIExportConfig struct {
+	OrgID       string `json:"org_id"`
+	Enabled     bool   `json:"enabled"`
+	Destination string `json:"destination"`
+	// URL receives a POST of each day's NDJSON for the https destination.
+	URL string `json:"url,omitempty"`
+	// Secret signs HTTPS deliveries like webhook payloads.
+	Secret string `json:"secret,omitempty"`
+	// S3 destination. Endpoint is only needed for S3-compatible services
+	// other than AWS.
+	Bucket          string `json:"bucket,omitempty"`
+	Region          string `json:"region,omitempty"`
+	Prefix          string `json:"prefix,omitempty"`
+	Endpoint        string `json:"endpoint,omitempty"`
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+	// LastDay is the last UTC day ("2026-10-15") exported successfully.
+	LastDay   string    `json:"last_day,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+	LastRunAt time.Time `json:"last_run_at,omitempty"`
+}
+
+// LifecycleEvent is one review event as exported to BI pipelines.
+type LifecycleEvent struct {
+	Event     string    `json:"event"`
+	OrgID     string    `json:"org_id"`
+	ReviewID  string    `json:"review_id"`
+	ActorID   string    `json:"actor_id"`
+	Status    string    `json:"review_status"`
+	Priority  string    `json:"review_priority,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// OrgBranding is an organization's display name, colors and logo, used in
+// notification emails, exported documents and the web UI header.
+type OrgBranding struct {
+	OrgID        string    `json:"org_id"`
+	Name         string    `json:"name,omitempty"`
+	PrimaryColor string    `json:"primary_color,omitempty"` // "#1f883d"
+	AccentColor  string    `json:"accent_color,omitempty"`
+	LogoKey      string    `json:"-"`
+	LogoURL      string    `json:"logo_url,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// BreakGlassApprovalWindow is how long a break-glass request waits for a
+// second admin or an operator to approve it before it lapses.
+const BreakGlassApprovalWindow = time.Hour
+
+// BreakGlass is an emergency, time-boxed elevation of an organization
+// admin within their own organization. It is requested on a written
+// justification and only takes effect once another admin of the
+// organization or an instance operator approves it.
+type BreakGlass struct {
+	ID            string     `json:"id"`
+	OrgID         string     `json:"org_id"`
+	UserID        string     `json:"user_id"`
+	Justification string     `json:"justification"`
+	Minutes       int        `json:"minutes"`
+	RequestedAt   time.Time  `json:"requested_at"`
+	ApprovedBy    string     `json:"approved_by,omitempty"`
+	GrantedAt     *time.Time `json:"granted_at,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	EndedAt       *time.Time `json:"ended_at,omitempty"`
+}
+
+// Pending reports whether the request is still awaiting approval at t.
+func (b BreakGlass) Pending(t time.Time) bool {
+	return b.GrantedAt == nil && b.EndedAt == nil && t.Before(b.RequestedAt.Add(BreakGlassApprovalWindow))
+}
+
+// Active reports whether the elevation is in effect at t.
+func (b BreakGlass) Active(t time.Time) bool {
+	return b.GrantedAt != nil && b.EndedAt == nil && t.Before(*b.ExpiresAt)
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// WorkCalendar describes an organization's business hours. SLA deadlines
+// are measured in working time on this calendar.
+type WorkCalendar struct {
+	OrgID    string   `json:"org_id"`
+	Timezone string   `json:"timezone"`
+	WorkDays []string `json:"work_days"` // "mon", "tue", ...
+	DayStart string   `json:"day_start"` // "09:00"
+	DayEnd   string   `json:"day_end"`   // "17:00"
+	Holidays []string `json:"holidays"`  // "2026-12-25"
+}
+
+// ReviewSLA is the SLA state of a review on its organization's calendar.
+type ReviewSLA struct {
+	ReviewID       string    `json:"review_id"`
+	TargetHours    int       `json:"target_hours"`
+	DueAt          time.Time `json:"due_at"`
+	ElapsedHours   float64   `json:"working_hours_elapsed"`
+	RemainingHours float64   `json:"working_hours_remaining"`
+	Breached       bool      `json:"breached"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ChecklistItem is a single checkable step on a review.
+type ChecklistItem struct {
+	ID        string     `json:"id"`
+	ReviewID  string     `json:"review_id"`
+	Text      string     `json:"text"`
+	Checked   bool       `json:"checked"`
+	CheckedBy string     `json:"checked_by,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
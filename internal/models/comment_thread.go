@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// ThreadResolution marks a comment thread as resolved.
+type ThreadResolution struct {
+	CommentID  string    `json:"comment_id"`
+	ResolvedBy string    `json:"resolved_by"`
+	ResolvedAt time.Time `json:"resolved_at"`
+}
+
+// CommentThread is a top-level comment together with its resolution state.
+type CommentThread struct {
+	Comment
+	AuthorAvatarURL string            `json:"author_avatar_url,omitempty"`
+	Resolved        bool              `json:"resolved"`
+	Resolution      *ThreadResolution `json:"resolution,omitempty"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// ComplianceReport describes an organization's compliance evidence bundle
+// for a calendar quarter: approvals, role changes, access reviews and MFA
+// enrollment. The bundle itself is a ZIP file kept in the blob store.
+type ComplianceReport struct {
+	OrgID string `json:"org_id"`
+	// Quarter is the quarter covered, such as 2026-Q3.
+	Quarter string    `json:"quarter"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	// Partial is set when the bundle was generated before the quarter
+	// ended.
+	Partial     bool   `json:"partial"`
+	Approvals   int    `json:"approvals"`
+	RoleChanges int    `json:"role_changes"`
+	Campaigns   int    `json:"access_reviews"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+	// GeneratedBy is the admin who asked for the bundle, or "scheduler".
+	GeneratedBy string    `json:"generated_by"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Dead-letter kinds: the background job that failed.
+const (
+	DeadLetterWebhook = "webhook"
+	DeadLetterEmail   = "email"
+	DeadLetterPush    = "push"
+)
+
+// DeadLetter is a background job that failed and will not be attempted
+// again unless someone retries it.
+type DeadLetter struct {
+	ID    string `json:"id"`
+	OrgID string `json:"org_id"`
+	Kind  string `json:"kind"`
+	// Reference identifies the job's subject, e.g. the webhook delivery ID.
+	// A kind has at most one dead letter per reference.
+	Reference string          `json:"reference"`
+	Summary   string          `json:"summary"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	Error     string          `json:"error"`
+	Attempts  int             `json:"attempts"`
+	CreatedAt time.Time       `json:"created_at"`
+	FailedAt  time.Time       `json:"failed_at"`
+}
+
+// DeadLetterFilter selects dead letters. Zero fields match everything.
+type DeadLetterFilter struct {
+	OrgID  string
+	Kind   string
+	Before time.Time
+}
+
+// Matches reports whether l passes the filter.
+func (f DeadLetterFilter) Matches(l *DeadLetter) bool {
+	switch {
+	case f.OrgID != "" && l.OrgID != f.OrgID:
+		return false
+	case f.Kind != "" && l.Kind != f.Kind:
+		return false
+	case !f.Before.IsZero() && !l.FailedAt.Before(f.Before):
+		return false
+	}
+	return true
+}
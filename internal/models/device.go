@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Push platforms.
+const (
+	PlatformFCM  = "fcm"
+	PlatformAPNs = "apns"
+)
+
+// Device is a mobile device registered to receive push notifications.
+// Events lists the notification types pushed to it; Muted pauses pushes
+// without forgetting the device.
+type Device struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Platform   string     `json:"platform"`
+	Token      string     `json:"-"`
+	Name       string     `json:"name,omitempty"`
+	Events     []string   `json:"events"`
+	Muted      bool       `json:"muted"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastPushAt *time.Time `json:"last_push_at,omitempty"`
+}
+
+// Wants reports whether the device should receive a notification of type.
+func (d *Device) Wants(eventType string) bool {
+	if d.Muted {
+		return false
+	}
+	for _, e := range d.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
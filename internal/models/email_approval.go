@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// EmailApproval is an approval request emailed to one approver. Its links
+// and reply address act on the review as that approver, once, until it
+// expires.
+type EmailApproval struct {
+	ID          string     `json:"id"`
+	ReviewID    string     `json:"review_id"`
+	OrgID       string     `json:"org_id"`
+	UserID      string     `json:"user_id"`
+	Email       string     `json:"email"`
+	RequestedBy string     `json:"requested_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	UsedAt      *time.Time `json:"used_at,omitempty"`
+	Action      string     `json:"action,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Email verification purposes.
+const (
+	VerifySignup      = "signup"
+	VerifyEmailChange = "email_change"
+)
+
+// EmailVerification is a pending confirmation that a user controls an
+// email address: the address of a new account, or the address an existing
+// user is changing to, which only takes effect once confirmed.
+type EmailVerification struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	Purpose   string    `json:"purpose"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PendingMembership is the organization a new account joins, and the role
+// it joins with, once its email address is verified. Until then the
+// account belongs to no organization.
+type PendingMembership struct {
+	OrgID string `json:"org_id"`
+	Role  string `json:"role"`
+}
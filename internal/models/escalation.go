@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// Review priorities.
+const (
+	PriorityLow      = "low"
+	PriorityNormal   = "normal"
+	PriorityHigh     = "high"
+	PriorityCritical = "critical"
+)
+
+// Paging providers.
+const (
+	PagerPagerDuty = "pagerduty"
+	PagerOpsgenie  = "opsgenie"
+)
+
+// EscalationConfig configures on-call paging for an organization's
+// critical reviews that breach their SLA.
+type EscalationConfig struct {
+	OrgID    string `json:"org_id"`
+	Enabled  bool   `json:"enabled"`
+	Provider string `json:"provider"`
+	// RoutingKey is the PagerDuty integration key or the Opsgenie API key.
+	RoutingKey string `json:"routing_key,omitempty"`
+}
+
+// Escalation records a page sent for a review, used for deduplication.
+type Escalation struct {
+	ReviewID string    `json:"review_id"`
+	OrgID    string    `json:"org_id"`
+	Provider string    `json:"provider"`
+	DedupKey string    `json:"dedup_key"`
+	PagedAt  time.Time `json:"paged_at"`
+}
+
+// Escalation rule actions.
+const (
+	EscalateNotifyAssignee = "notify_assignee"
+	EscalateNotifyManager  = "notify_manager"
+	EscalateReassign       = "reassign"
+)
+
+// EscalationRule acts on an organization's open reviews that have seen no
+// activity for AfterHours. Each rule fires once per stall: activity on the
+// review, including a reassignment, starts the clock again.
+type EscalationRule struct {
+	ID         string `json:"id"`
+	OrgID      string `json:"org_id"`
+	Name       string `json:"name"`
+	AfterHours int    `json:"after_hours"`
+	Action     string `json:"action"`
+	// Priority limits the rule to reviews of that priority; empty matches
+	// every review.
+	Priority string `json:"priority,omitempty"`
+	// Risk limits the rule to reviews of that risk level; empty matches
+	// every review.
+	Risk      string    `json:"risk,omitempty"`
+	Enabled   bool      `json:"enabled"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// EscalationStep records a rule firing on a review.
+type EscalationStep struct {
+	ReviewID string `json:"review_id"`
+	RuleID   string `json:"rule_id"`
+	RuleName string `json:"rule_name"`
+	Action   string `json:"action"`
+	// TargetID is the user notified or the new assignee; empty when the
+	// rule found nobody to act on.
+	TargetID string `json:"target_id,omitempty"`
+	// FromID is the assignee when the rule fired.
+	FromID string    `json:"from_id,omitempty"`
+	Note   string    `json:"note,omitempty"`
+	At     time.Time `json:"at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Custom field types.
+const (
+	FieldString = "string"
+	FieldEnum   = "enum"
+	FieldNumber = "number"
+	FieldUser   = "user"
+)
+
+// CustomField is an organization-defined field set on its reviews. Key
+// names the field in API payloads; Options lists the values of an enum.
+type CustomField struct {
+	ID        string    `json:"id"`
+	OrgID     string    `json:"org_id"`
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Options   []string  `json:"options,omitempty"`
+	Required  bool      `json:"required"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// IntakeForm is a public link through which people without an account can
+// submit review requests to an organization.
+type IntakeForm struct {
+	ID          string    `json:"id"`
+	OrgID       string    `json:"org_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	TokenHash   string    `json:"-"`
+	Active      bool      `json:"active"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Intake submission statuses.
+const (
+	IntakePending  = "pending"
+	IntakeAccepted = "accepted"
+	IntakeRejected = "rejected"
+)
+
+// IntakeSubmission is a review request filed through an intake form,
+// waiting in the triage queue until an admin accepts or rejects it.
+type IntakeSubmission struct {
+	ID        string     `json:"id"`
+	FormID    string     `json:"form_id"`
+	OrgID     string     `json:"org_id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	Title     string     `json:"title"`
+	Content   string     `json:"content"`
+	IP        string     `json:"ip"`
+	Status    string     `json:"status"`
+	ReviewID  string     `json:"review_id,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+	TriagedBy string     `json:"triaged_by,omitempty"`
+	TriagedAt *time.Time `json:"triaged_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
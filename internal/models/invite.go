@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Invite lets a newly created user claim their account.
+type Invite struct {
+	Token     string    `json:"-"`
+	UserID    string    `json:"user_id"`
+	OrgID     string    `json:"org_id"`
+	Email     string    `json:"email"`
+	InvitedBy string    `json:"invited_by"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// JiraConfig configures the Jira sync connector for an organization.
+type JiraConfig struct {
+	OrgID      string `json:"org_id"`
+	Enabled    bool   `json:"enabled"`
+	BaseURL    string `json:"base_url"`
+	Email      string `json:"email"`
+	APIToken   string `json:"api_token,omitempty"`
+	ProjectKey string `json:"project_key"`
+	IssueType  string `json:"issue_type"`
+	// WebhookSecret verifies the X-Hub-Signature header of inbound Jira webhooks.
+	WebhookSecret string `json:"webhook_secret,omitempty"`
+	// StatusToJira maps review statuses to Jira status names.
+	StatusToJira map[string]string `json:"status_to_jira"`
+	// StatusFromJira maps Jira status names to review statuses.
+	StatusFromJira map[string]string `json:"status_from_jira"`
+	// LabelComponents maps review labels to Jira component names.
+	LabelComponents map[string]string `json:"label_components"`
+}
+
+// Redacted returns a copy with secrets removed, for API responses.
+func (c JiraConfig) Redacted() JiraConfig {
+	if c.APIToken != "" {
+		c.APIToken = "********"
+	}
+	if c.WebhookSecret != "" {
+		c.WebhookSecret = "********"
+	}
+	return c
+}
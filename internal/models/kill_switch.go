@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Capabilities that kill switches can disable.
+const (
+	CapabilityWebhooks      = "webhooks"
+	CapabilityIntake        = "intake"
+	CapabilityExports       = "exports"
+	CapabilityEmail         = "email"
+	CapabilityPush          = "push"
+	CapabilityBIExport      = "bi_export"
+	CapabilityEmailApproval = "email_approval"
+	CapabilityAI            = "ai"
+)
+
+// Capabilities lists every capability, for validating kill switches.
+var Capabilities = []string{
+	CapabilityWebhooks, CapabilityIntake, CapabilityExports, CapabilityEmail,
+	CapabilityPush, CapabilityBIExport, CapabilityEmailApproval, CapabilityAI,
+}
+
+// KillSwitch disables a capability instance-wide (empty OrgID) or for one
+// organization, e.g. while an incident is investigated.
+type KillSwitch struct {
+	Capability string    `json:"capability"`
+	OrgID      string    `json:"org_id,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	DisabledBy string    `json:"disabled_by"`
+	DisabledAt time.Time `json:"disabled_at"`
+}
+
+// Error describes the switch for background jobs it stops.
+func (k KillSwitch) Error() string {
+	msg := k.Capability + " is disabled by a kill switch"
+	if k.Reason != "" {
+		msg += ": " + k.Reason
+	}
+	return msg
+}
+
+// ErrDisabled matches the errors of jobs stopped by a kill switch.
+var ErrDisabled = errors.New("disabled by a kill switch")
+
+// Is makes errors.Is(k, ErrDisabled) true.
+func (k KillSwitch) Is(target error) bool {
+	return target == ErrDisabled
+}
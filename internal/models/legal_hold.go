@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// LegalHold preserves an organization's data, or one of its reviews, for
+// litigation or an investigation: while it is in effect, deletions of the
+// held data are refused and purge and retention jobs skip it.
+type LegalHold struct {
+	ID string `json:"id"`
+	// OrgID is the organization held, or whose review is.
+	OrgID string `json:"org_id"`
+	// ReviewID is the review held; empty holds the whole organization.
+	ReviewID      string     `json:"review_id,omitempty"`
+	Reason        string     `json:"reason"`
+	PlacedBy      string     `json:"placed_by"`
+	PlacedAt      time.Time  `json:"placed_at"`
+	ReleasedBy    string     `json:"released_by,omitempty"`
+	ReleasedAt    *time.Time `json:"released_at,omitempty"`
+	ReleaseReason string     `json:"release_reason,omitempty"`
+}
+
+// Active reports whether the hold is still in effect.
+func (h LegalHold) Active() bool {
+	return h.ReleasedAt == nil
+}
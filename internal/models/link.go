@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// External link providers.
+const (
+	ProviderJira   = "jira"
+	ProviderLinear = "linear"
+	ProviderGitHub = "github"
+	ProviderOther  = "other"
+)
+
+// ExternalLink connects a review to an issue in an external tracker.
+type ExternalLink struct {
+	ID             string     `json:"id"`
+	ReviewID       string     `json:"review_id"`
+	OrgID          string     `json:"org_id"`
+	URL            string     `json:"url"`
+	Provider       string     `json:"provider"`
+	Key            string     `json:"key,omitempty"`
+	Status         string     `json:"status,omitempty"`
+	StatusSyncedAt *time.Time `json:"status_synced_at,omitempty"`
+	CreatedBy      string     `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
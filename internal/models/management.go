@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// ManagedOrg is the management API view of an organization. ExternalID is a
+// caller-chosen identifier, such as a Terraform resource address, that can
+// be used to find and import the organization. Region pins the
+// organization's data to one regional deployment; it is set at creation and
+// cannot be changed. CreatedBy is empty for organizations created before it
+// was recorded.
+type ManagedOrg struct {
+	ID         string    `json:"id"`
+	ExternalID string    `json:"external_id,omitempty"`
+	Name       string    `json:"name"`
+	Region     string    `json:"region,omitempty"`
+	ParentID   string    `json:"parent_id,omitempty"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Role is an organization-defined role. Members granted it act with the
+// permissions of BaseRole.
+type Role struct {
+	ID          string    `json:"id"`
+	OrgID       string    `json:"org_id"`
+	ExternalID  string    `json:"external_id,omitempty"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	BaseRole    string    `json:"base_role"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Webhook delivers review events for an organization to an HTTP endpoint.
+// An empty Events list subscribes to every event.
+type Webhook struct {
+	ID         string    `json:"id"`
+	OrgID      string    `json:"org_id"`
+	ExternalID string    `json:"external_id,omitempty"`
+	URL        string    `json:"url"`
+	Events     []string  `json:"events"`
+	Secret     string    `json:"secret,omitempty"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Wants reports whether the webhook should receive eventType.
+func (w *Webhook) Wants(eventType string) bool {
+	if !w.Active {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns a copy with the signing secret removed, for API responses.
+func (w Webhook) Redacted() Webhook {
+	if w.Secret != "" {
+		w.Secret = "********"
+	}
+	return w
+}
+
+// APIKey authenticates automation as an organization member with Role.
+// Only a hash of the key is stored; the key itself is shown once on creation.
+type APIKey struct {
+	ID         string     `json:"id"`
+	OrgID      string     `json:"org_id"`
+	ExternalID string     `json:"external_id,omitempty"`
+	Name       string     `json:"name"`
+	Role       string     `json:"role"`
+	Prefix     string     `json:"prefix"`
+	Hash       string     `json:"-"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Banned-term actions.
+const (
+	// ModerationReject refuses content containing a banned term.
+	ModerationReject = "reject"
+	// ModerationMask replaces banned terms with asterisks.
+	ModerationMask = "mask"
+)
+
+// RedactedMarker replaces redacted text in reviews and comments.
+const RedactedMarker = "[redacted]"
+
+// ModerationPolicy lists the terms an organization bans from review titles,
+// contents, custom field values and comments.
+type ModerationPolicy struct {
+	OrgID       string    `json:"org_id"`
+	BannedTerms []string  `json:"banned_terms"`
+	Action      string    `json:"action"`
+	UpdatedBy   string    `json:"updated_by,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// Redaction is the tombstone left where an admin redacted content from a
+// review field or a comment. The redacted text itself is not kept.
+type Redaction struct {
+	ID        string `json:"id"`
+	OrgID     string `json:"org_id"`
+	ReviewID  string `json:"review_id"`
+	CommentID string `json:"comment_id,omitempty"`
+	// Field is title, content or fields.<key> for a custom field.
+	Field string `json:"field,omitempty"`
+	// Partial is set when only part of the text was redacted.
+	Partial    bool      `json:"partial"`
+	Reason     string    `json:"reason"`
+	RedactedBy string    `json:"redacted_by"`
+	RedactedAt time.Time `json:"redacted_at"`
+}
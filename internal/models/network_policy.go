@@ -0,0 +1,34 @@
+package models
+
+import (
+	"net/netip"
+	"time"
+)
+
+// NetworkPolicy restricts an organization's API access to CIDR ranges. An
+// organization without ranges is reachable from anywhere.
+type NetworkPolicy struct {
+	OrgID     string    `json:"org_id"`
+	CIDRs     []string  `json:"cidrs"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// Allows reports whether ip is inside one of the policy's ranges.
+// Unparseable addresses are refused.
+func (p NetworkPolicy) Allows(ip string) bool {
+	if len(p.CIDRs) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, cidr := range p.CIDRs {
+		if prefix, err := netip.ParsePrefix(cidr); err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// OAuth scopes a client can request.
+const (
+	// ScopeRead allows read-only access to everything the user can see.
+	ScopeRead = "read"
+	// ScopeReviewsWrite allows creating, editing and commenting on reviews.
+	ScopeReviewsWrite = "reviews:write"
+)
+
+// OAuthScopes lists every scope, for validating client registrations.
+var OAuthScopes = []string{ScopeRead, ScopeReviewsWrite}
+
+// OAuthClient is a third-party application registered by an organization
+// to act on behalf of its members.
+type OAuthClient struct {
+	ID           string    `json:"client_id"`
+	OrgID        string    `json:"org_id"`
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	SecretHash   string    `json:"-"`
+	CreatedBy    string    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// OAuthGrant is what a user authorized a client to do: act as them with
+// the listed scopes.
+type OAuthGrant struct {
+	ClientID string   `json:"client_id"`
+	UserID   string   `json:"user_id"`
+	Email    string   `json:"email"`
+	OrgID    string   `json:"org_id"`
+	Role     string   `json:"role"`
+	Scopes   []string `json:"scopes"`
+}
+
+// OAuthCode is a single-use authorization code awaiting exchange.
+type OAuthCode struct {
+	Grant         OAuthGrant
+	RedirectURI   string
+	CodeChallenge string
+	ExpiresAt     time.Time
+}
+
+// OAuth token kinds.
+const (
+	OAuthAccessToken  = "access"
+	OAuthRefreshToken = "refresh"
+)
+
+// OAuthToken is an issued access or refresh token.
+type OAuthToken struct {
+	Kind      string
+	Grant     OAuthGrant
+	ExpiresAt time.Time
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Onboarding steps, in the order clients should present them.
+const (
+	OnboardingProfile       = "profile_completed"
+	OnboardingFirstReview   = "first_review_created"
+	OnboardingNotifications = "notifications_configured"
+)
+
+// OnboardingSteps lists every onboarding step in order.
+var OnboardingSteps = []string{OnboardingProfile, OnboardingFirstReview, OnboardingNotifications}
+
+// OnboardingStep is one item of a user's welcome checklist.
+type OnboardingStep struct {
+	Key         string     `json:"key"`
+	Done        bool       `json:"done"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Onboarding is a user's welcome checklist. Clients stop showing it once
+// it is completed or dismissed.
+type Onboarding struct {
+	UserID      string           `json:"user_id"`
+	Steps       []OnboardingStep `json:"steps"`
+	Completed   bool             `json:"completed"`
+	DismissedAt *time.Time       `json:"dismissed_at,omitempty"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Operation statuses.
+const (
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+	OperationCanceled  = "canceled"
+)
+
+// Operation is a long-running task started by a request that was answered
+// before the task finished, such as an organization export or a bulk
+// import. Clients poll it for progress and its result.
+type Operation struct {
+	ID       string            `json:"id"`
+	OrgID    string            `json:"org_id"`
+	Kind     string            `json:"kind"`
+	Status   string            `json:"status"`
+	Progress OperationProgress `json:"progress"`
+	// Result is the task's outcome once it succeeded, e.g. an import
+	// report.
+	Result json.RawMessage `json:"result,omitempty"`
+	// File describes a downloadable result, kept in the blob store.
+	File            *OperationFile    `json:"file,omitempty"`
+	Error           string            `json:"error,omitempty"`
+	CancelRequested bool              `json:"cancel_requested,omitempty"`
+	Links           map[string]string `json:"links,omitempty"`
+	CreatedBy       string            `json:"created_by"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	FinishedAt      *time.Time        `json:"finished_at,omitempty"`
+}
+
+// Finished reports whether the operation stopped running.
+func (o *Operation) Finished() bool {
+	return o.Status != OperationRunning
+}
+
+// OperationProgress counts the units of work an operation has done. Total
+// is zero when it is not known.
+type OperationProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total,omitempty"`
+}
+
+// OperationFile is a file an operation produced.
+type OperationFile struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	BlobKey     string `json:"-"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// OrgDeletion is an organization's pending deletion. Until PurgeAt its
+// admins can cancel it and download the export of its data taken when it
+// was requested; then the organization and everything in it is deleted.
+type OrgDeletion struct {
+	OrgID       string    `json:"org_id"`
+	RequestedBy string    `json:"requested_by"`
+	RequestedAt time.Time `json:"requested_at"`
+	PurgeAt     time.Time `json:"purge_at"`
+	// ExportSize is the size in bytes of the export of the
+	// organization's data.
+	ExportSize int64 `json:"export_size"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Join modes of an organization's email domain.
+const (
+	// JoinSuggest offers the organization to users signing up with a
+	// matching email; they choose whether to join.
+	JoinSuggest = "suggest"
+	// JoinAuto adds users signing up with a matching email directly.
+	JoinAuto = "auto"
+)
+
+// OrgDomain is an email domain an organization has claimed. Once verified
+// through a DNS TXT record, users signing up with an address at the domain
+// are offered or added to the organization with DefaultRole.
+type OrgDomain struct {
+	OrgID       string     `json:"org_id"`
+	Domain      string     `json:"domain"`
+	JoinMode    string     `json:"join_mode"`
+	DefaultRole string     `json:"default_role"`
+	Verified    bool       `json:"verified"`
+	VerifiedAt  *time.Time `json:"verified_at,omitempty"`
+	// The organization proves control of the domain by publishing
+	// TXTValue in a TXT record at TXTName.
+	TXTName   string    `json:"txt_name"`
+	TXTValue  string    `json:"txt_value"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// OrgHierarchy is an organization's place in the parent-child tree.
+type OrgHierarchy struct {
+	OrgID    string `json:"org_id"`
+	ParentID string `json:"parent_id,omitempty"`
+	// Ancestors lists the parent first and the root last.
+	Ancestors []string `json:"ancestors"`
+	Children  []string `json:"children"`
+}
+
+// OrgRollupEntry counts the members and reviews of one organization in a
+// subtree.
+type OrgRollupEntry struct {
+	OrgID    string         `json:"org_id"`
+	Name     string         `json:"name"`
+	ParentID string         `json:"parent_id,omitempty"`
+	Members  int            `json:"members"`
+	Reviews  int            `json:"reviews"`
+	ByStatus map[string]int `json:"by_status"`
+}
+
+// OrgRollup aggregates an organization and all of its descendants.
+type OrgRollup struct {
+	OrgID    string           `json:"org_id"`
+	Orgs     []OrgRollupEntry `json:"orgs"`
+	Members  int              `json:"members"`
+	Reviews  int              `json:"reviews"`
+	ByStatus map[string]int   `json:"by_status"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Organization is a tenant. Every user and review belongs to exactly one.
+// CreatedBy is the operator who created it through the management API, and
+// is empty for organizations created otherwise.
+type Organization struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Delegation modes for assignments made while a reviewer is away.
+const (
+	DelegateRoute = "route" // assign to the delegate instead
+	DelegateCC    = "cc"    // keep the assignment and make the delegate a watcher
+)
+
+// OutOfOffice is a reviewer's absence window and their delegate.
+type OutOfOffice struct {
+	UserID     string    `json:"user_id"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	DelegateID string    `json:"delegate_id,omitempty"`
+	Mode       string    `json:"mode"`
+	Note       string    `json:"note,omitempty"`
+}
+
+// ActiveAt reports whether the absence window covers t.
+func (o *OutOfOffice) ActiveAt(t time.Time) bool {
+	return !t.Before(o.Start) && t.Before(o.End)
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// OutboxEvent is a domain event waiting to be published to notification
+// channels, webhooks and integrations.
+type OutboxEvent struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	ReviewID  string    `json:"review_id"`
+	ActorID   string    `json:"actor_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Password token purposes.
+const (
+	PasswordTokenChange = "change"
+	PasswordTokenReset  = "reset"
+)
+
+// PasswordToken is a single-use token that lets a user set a new password
+// without signing in: when their password must be rotated, or when they
+// forgot it.
+type PasswordToken struct {
+	UserID    string
+	Purpose   string
+	ExpiresAt time.Time
+}
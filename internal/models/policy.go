@@ -0,0 +1,41 @@
+package models
+
+// OrgPolicy holds organization-wide review rules.
+type OrgPolicy struct {
+	OrgID string `json:"org_id"`
+	// RequireResolvedThreads blocks approval while any comment thread is unresolved.
+	RequireResolvedThreads bool `json:"require_resolved_threads"`
+	// ReviewSLAHours is the review turnaround target in working hours; 0 disables SLAs.
+	ReviewSLAHours int `json:"review_sla_hours"`
+	// SessionIdleMinutes signs users out after that long without a
+	// request; 0 keeps sessions until their token expires.
+	SessionIdleMinutes int `json:"session_idle_minutes"`
+	// AssignmentStrategy selects how reviews are auto-assigned: round_robin
+	// (default), least_loaded or expertise.
+	AssignmentStrategy string `json:"assignment_strategy,omitempty"`
+	// Password sets the rules members' passwords must meet.
+	Password PasswordPolicy `json:"password"`
+	// Enforced applies the policy to every descendant organization,
+	// overriding their own.
+	Enforced bool `json:"enforced"`
+	// InheritedFrom names the ancestor organization the effective policy
+	// comes from; empty when it is the organization's own.
+	InheritedFrom string `json:"inherited_from,omitempty"`
+}
+
+// PasswordPolicy sets the rules passwords must meet when they are set or
+// changed, and how long they stay valid.
+type PasswordPolicy struct {
+	// MinLength is the shortest password accepted; values below the
+	// instance minimum of 8 are raised to it.
+	MinLength     int  `json:"min_length"`
+	RequireUpper  bool `json:"require_upper"`
+	RequireLower  bool `json:"require_lower"`
+	RequireDigit  bool `json:"require_digit"`
+	RequireSymbol bool `json:"require_symbol"`
+	// CheckBreached refuses passwords found in known breaches.
+	CheckBreached bool `json:"check_breached"`
+	// MaxAgeDays forces a change at the first login after a password is
+	// that old; 0 lets passwords live forever.
+	MaxAgeDays int `json:"max_age_days"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// UserProfile holds the user-editable presentation details of an account.
+type UserProfile struct {
+	UserID      string    `json:"user_id"`
+	DisplayName string    `json:"display_name,omitempty"`
+	Timezone    string    `json:"timezone,omitempty"`
+	Locale      string    `json:"locale,omitempty"`
+	AvatarKey   string    `json:"-"`
+	AvatarURL   string    `json:"avatar_url,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
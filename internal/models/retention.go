@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// ReviewClosed is the status of reviews closed for being idle.
+const ReviewClosed = "closed"
+
+// RetentionPolicy configures how long an organization's data is kept. A
+// zero period disables that rule.
+type RetentionPolicy struct {
+	OrgID string `json:"org_id"`
+	// CloseIdleDays closes open reviews with no edits or comments for
+	// that many days.
+	CloseIdleDays int `json:"close_idle_days"`
+	// PurgeDeletedDays purges soft-deleted items, the redirects left by
+	// merged reviews and revoked share links, that many days after their
+	// removal.
+	PurgeDeletedDays int `json:"purge_deleted_days"`
+	// AuditMonths truncates audit events older than that many months.
+	AuditMonths int       `json:"audit_months"`
+	UpdatedBy   string    `json:"updated_by,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// Enabled reports whether any rule of the policy is on.
+func (p RetentionPolicy) Enabled() bool {
+	return p.CloseIdleDays > 0 || p.PurgeDeletedDays > 0 || p.AuditMonths > 0
+}
+
+// RetentionRun is what applying an organization's retention policy did,
+// or with DryRun, would do.
+type RetentionRun struct {
+	OrgID  string    `json:"org_id"`
+	DryRun bool      `json:"dry_run"`
+	At     time.Time `json:"at"`
+	// ClosedReviews are the IDs of the idle reviews closed.
+	ClosedReviews []string `json:"closed_reviews"`
+	// PurgedMerges are the IDs of the merged reviews whose redirects
+	// were purged.
+	PurgedMerges []string `json:"purged_merges"`
+	// PurgedShareLinks are the IDs of the revoked share links purged.
+	PurgedShareLinks []string `json:"purged_share_links"`
+	// AuditEvents is how many audit events were truncated.
+	AuditEvents int `json:"audit_events"`
+	// Held counts the items kept because of a legal hold.
+	Held int `json:"held"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Review is a document submitted by a member of an organization for
+// review and approval. AuthorID is the user who created it; CreatedBy
+// repeats it, as the store sets it from AuthorID on every save. ApprovedBy
+// and ApprovedAt are set while the review is approved.
+type Review struct {
+	ID         string     `json:"id"`
+	OrgID      string     `json:"org_id"`
+	AuthorID   string     `json:"author_id"`
+	Title      string     `json:"title"`
+	Content    string     `json:"content"`
+	Status     string     `json:"status"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ApprovedBy string     `json:"approved_by,omitempty"`
+	ApprovedAt *time.Time `json:"approved_at,omitempty"`
+}
+
+// Comment is a comment on a review. Each top-level comment starts a thread
+// that can be resolved.
+type Comment struct {
+	ID        string    `json:"id"`
+	ReviewID  string    `json:"review_id"`
+	AuthorID  string    `json:"author_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Access levels of a review grant.
+const (
+	GrantRead    = "read"
+	GrantComment = "comment"
+)
+
+// ReviewGrant shares one review with another organization, e.g. a
+// vendor's security team, without making its users members.
+type ReviewGrant struct {
+	ReviewID     string    `json:"review_id"`
+	OrgID        string    `json:"org_id"`
+	GranteeOrgID string    `json:"grantee_org_id"`
+	Access       string    `json:"access"`
+	Note         string    `json:"note,omitempty"`
+	GrantedBy    string    `json:"granted_by"`
+	GrantedAt    time.Time `json:"granted_at"`
+}
+
+// Allows reports whether the grant covers the access level. Comment access
+// includes read.
+func (g ReviewGrant) Allows(access string) bool {
+	return g.Access == access || g.Access == GrantComment
+}
+
+// GuestGrant shares one review with a guest of the owning organization.
+type GuestGrant struct {
+	ReviewID  string    `json:"review_id"`
+	OrgID     string    `json:"org_id"`
+	UserID    string    `json:"user_id"`
+	Access    string    `json:"access"`
+	GrantedBy string    `json:"granted_by"`
+	GrantedAt time.Time `json:"granted_at"`
+}
+
+// Allows reports whether the grant covers the access level. Comment access
+// includes read.
+func (g GuestGrant) Allows(access string) bool {
+	return g.Access == access || g.Access == GrantComment
+}
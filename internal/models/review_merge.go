@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// ReviewMerge records a duplicate review folded into another. Requests for
+// the merged review are redirected to the one it was merged into.
+type ReviewMerge struct {
+	SourceID string    `json:"source_id"`
+	TargetID string    `json:"target_id"`
+	OrgID    string    `json:"org_id"`
+	Comments int       `json:"comments"`
+	MergedBy string    `json:"merged_by"`
+	MergedAt time.Time `json:"merged_at"`
+}
@@ -0,0 +1,112 @@
+package models
+
+import "time"
+
+// Review risk levels, from least to most risky.
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// RiskLevels lists the risk levels, from least to most risky.
+var RiskLevels = []string{RiskLow, RiskMedium, RiskHigh}
+
+// RiskPolicy configures how an organization's reviews are scored for risk
+// and what approval each level of risk needs. Scores add up the points of
+// every factor that applies, capped at 100.
+type RiskPolicy struct {
+	OrgID string `json:"org_id"`
+	// LargeChangeLines is the number of changed lines that earns all of
+	// SizePoints; smaller changes earn a share of them.
+	LargeChangeLines int `json:"large_change_lines"`
+	SizePoints       int `json:"size_points"`
+	// SensitivePaths earn points when a review's diff touches them.
+	SensitivePaths []RiskPath `json:"sensitive_paths"`
+	// NewAuthorApprovals is how many approved reviews an author needs to
+	// stop earning NewAuthorPoints.
+	NewAuthorApprovals int `json:"new_author_approvals"`
+	NewAuthorPoints    int `json:"new_author_points"`
+	// ReopenedPoints are earned for each of the author's reviews reopened
+	// after approval, up to MaxReopenedPoints.
+	ReopenedPoints    int `json:"reopened_points"`
+	MaxReopenedPoints int `json:"max_reopened_points"`
+	// LabelPoints are earned by reviews carrying the label.
+	LabelPoints map[string]int `json:"label_points"`
+	// MediumScore and HighScore are the lowest scores of those levels.
+	MediumScore int `json:"medium_score"`
+	HighScore   int `json:"high_score"`
+	// RequiredApprovals is how many distinct admins must approve reviews
+	// of a risk level; levels not listed need one.
+	RequiredApprovals map[string]int `json:"required_approvals"`
+	UpdatedBy         string         `json:"updated_by,omitempty"`
+	UpdatedAt         time.Time      `json:"updated_at,omitempty"`
+}
+
+// RiskPath is a path pattern worth points when a diff touches it. A pattern
+// ending in / matches everything under that directory at any depth;
+// others are globs matched against the whole path or its last element.
+type RiskPath struct {
+	Pattern string `json:"pattern"`
+	Points  int    `json:"points"`
+}
+
+// DefaultRiskPolicy is the risk policy of organizations that have not set
+// their own. It requires no extra approvals.
+func DefaultRiskPolicy(orgID string) RiskPolicy {
+	return RiskPolicy{
+		OrgID:            orgID,
+		LargeChangeLines: 500,
+		SizePoints:       30,
+		SensitivePaths: []RiskPath{
+			{Pattern: "auth/", Points: 25},
+			{Pattern: "migrations/", Points: 20},
+			{Pattern: ".github/workflows/", Points: 20},
+			{Pattern: "*.sql", Points: 15},
+			{Pattern: "Dockerfile", Points: 10},
+		},
+		NewAuthorApprovals: 3,
+		NewAuthorPoints:    15,
+		ReopenedPoints:     5,
+		MaxReopenedPoints:  15,
+		LabelPoints:        map[string]int{"security": 25, "hotfix": 15},
+		MediumScore:        30,
+		HighScore:          60,
+		RequiredApprovals:  map[string]int{},
+	}
+}
+
+// RiskScore is how risky a review is and why.
+type RiskScore struct {
+	Score   int          `json:"score"`
+	Level   string       `json:"level"`
+	Factors []RiskFactor `json:"factors"`
+	// RequiredApprovals is how many distinct admins must approve the
+	// review.
+	RequiredApprovals int `json:"required_approvals"`
+}
+
+// Risk factor kinds.
+const (
+	RiskFactorSize          = "size"
+	RiskFactorSensitivePath = "sensitive_path"
+	RiskFactorNewAuthor     = "new_author"
+	RiskFactorReopened      = "reopened"
+	RiskFactorLabel         = "label"
+)
+
+// RiskFactor is one contribution to a risk score.
+type RiskFactor struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+	Points int    `json:"points"`
+}
+
+// RiskSignoff records an admin approving a review that needs more than one
+// approval, before the approvals it needs are in.
+type RiskSignoff struct {
+	ReviewID string    `json:"review_id"`
+	UserID   string    `json:"user_id"`
+	Level    string    `json:"level"`
+	At       time.Time `json:"at"`
+}
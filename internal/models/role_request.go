@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Role request statuses.
+const (
+	RoleRequestPending   = "pending"
+	RoleRequestApproved  = "approved"
+	RoleRequestRejected  = "rejected"
+	RoleRequestCancelled = "cancelled"
+)
+
+// RoleRequest is a member's request to change their role, decided by an
+// organization admin.
+type RoleRequest struct {
+	ID       string `json:"id"`
+	OrgID    string `json:"org_id"`
+	UserID   string `json:"user_id"`
+	FromRole string `json:"from_role"`
+	Role     string `json:"role"`
+	Reason   string `json:"reason"`
+	Status   string `json:"status"`
+	// DecidedBy and DecisionReason are set once an admin approves or
+	// rejects the request.
+	DecidedBy      string     `json:"decided_by,omitempty"`
+	DecisionReason string     `json:"decision_reason,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DecidedAt      *time.Time `json:"decided_at,omitempty"`
+}
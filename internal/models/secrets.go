@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Secret detection modes.
+const (
+	SecretsOff = "off"
+	// SecretsWarn accepts content with secrets but records the findings.
+	SecretsWarn = "warn"
+	// SecretsBlock rejects content with secrets.
+	SecretsBlock = "block"
+)
+
+// SecretPolicy configures how an organization's review content and
+// attachments are checked for credentials.
+type SecretPolicy struct {
+	OrgID string `json:"org_id"`
+	Mode  string `json:"mode"`
+	// Rules are checked in addition to the built-in ones.
+	Rules []SecretRule `json:"rules"`
+	// DisabledRules lists built-in rules the organization turned off.
+	DisabledRules []string  `json:"disabled_rules"`
+	UpdatedBy     string    `json:"updated_by,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at,omitempty"`
+}
+
+// SecretRule is a pattern that matches a kind of credential. When
+// MinEntropy is set a match only counts if its first capture group, or the
+// whole match without one, is at least that random (Shannon entropy in bits
+// per character), which weeds out placeholders such as "changeme".
+type SecretRule struct {
+	ID          string  `json:"id"`
+	Description string  `json:"description"`
+	Pattern     string  `json:"pattern"`
+	MinEntropy  float64 `json:"min_entropy,omitempty"`
+}
+
+// SecretFinding is a credential found in a review. The secret itself is
+// not kept, only a masked preview and a fingerprint to tell findings apart.
+type SecretFinding struct {
+	RuleID      string `json:"rule_id"`
+	Description string `json:"description"`
+	// Source is title, content, fields.<key> or attachment:<id>.
+	Source      string    `json:"source"`
+	Line        int       `json:"line"`
+	Preview     string    `json:"preview"`
+	Fingerprint string    `json:"fingerprint"`
+	FoundAt     time.Time `json:"found_at"`
+}
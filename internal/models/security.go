@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Security event types raised by login anomaly detection, by organization
+// network policies and by break-glass elevations.
+const (
+	SecurityNewDevice           = "new_device"
+	SecurityImpossibleTravel    = "impossible_travel"
+	SecurityIPNotAllowed        = "ip_not_allowed"
+	SecurityBreakGlassRequested = "break_glass_requested"
+	SecurityBreakGlassApproved  = "break_glass_approved"
+)
+
+// LoginEvent is a successful login. Location fields are only set when the
+// edge proxy supplied geolocation.
+type LoginEvent struct {
+	UserID    string    `json:"user_id"`
+	OrgID     string    `json:"org_id"`
+	IP        string    `json:"ip"`
+	Device    string    `json:"device"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	Country   string    `json:"country,omitempty"`
+	Latitude  float64   `json:"latitude,omitempty"`
+	Longitude float64   `json:"longitude,omitempty"`
+	Located   bool      `json:"located"`
+	At        time.Time `json:"at"`
+}
+
+// SecurityEvent flags a suspicious login, a request refused by the
+// organization's network policy or a break-glass elevation.
+type SecurityEvent struct {
+	ID        string    `json:"id"`
+	OrgID     string    `json:"org_id"`
+	UserID    string    `json:"user_id"`
+	Type      string    `json:"type"`
+	IP        string    `json:"ip"`
+	Device    string    `json:"device"`
+	Country   string    `json:"country,omitempty"`
+	Detail    string    `json:"detail"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// ServiceIdentity is an internal service that authenticates with a client
+// certificate on the mTLS listener instead of logging in. Subject is
+// matched against the certificate's common name and its DNS, URI and email
+// SANs.
+type ServiceIdentity struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Subject    string     `json:"subject"`
+	OrgID      string     `json:"org_id"`
+	Role       string     `json:"role"`
+	Scopes     []string   `json:"scopes,omitempty"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// Permits reports whether a scope of the identity covers the request. A
+// scope is "METHOD /path-prefix" or just "/path-prefix" for any method;
+// an identity without scopes may call anything its role allows.
+func (s ServiceIdentity) Permits(method, path string) bool {
+	if len(s.Scopes) == 0 {
+		return true
+	}
+	for _, scope := range s.Scopes {
+		m, prefix, ok := strings.Cut(scope, " ")
+		if !ok {
+			m, prefix = "", scope
+		}
+		if m != "" && !strings.EqualFold(m, method) {
+			continue
+		}
+		if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
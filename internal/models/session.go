@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Session end reasons.
+const (
+	SessionRevoked       = "revoked"
+	SessionIdle          = "idle"
+	SessionPasswordReset = "password_reset"
+)
+
+// Session is a signed-in device of a user: the access token issued at
+// login, identified by hash, and where it was last used from.
+type Session struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	OrgID      string     `json:"org_id"`
+	TokenHash  string     `json:"-"`
+	Device     string     `json:"device"`
+	UserAgent  string     `json:"user_agent,omitempty"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	EndedAt    *time.Time `json:"ended_at,omitempty"`
+	EndReason  string     `json:"end_reason,omitempty"`
+	// Current marks the session the listing request was made with.
+	Current bool `json:"current"`
+}
+
+// Active reports whether the session can still be used at t.
+func (s Session) Active(t time.Time) bool {
+	return s.EndedAt == nil && t.Before(s.ExpiresAt)
+}
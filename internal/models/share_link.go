@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// ShareLink grants read-only access to one review, without an account,
+// until it expires or is revoked.
+type ShareLink struct {
+	ID        string     `json:"id"`
+	OrgID     string     `json:"org_id"`
+	ReviewID  string     `json:"review_id"`
+	Note      string     `json:"note,omitempty"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedBy string     `json:"revoked_by,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	// URL is set only in the response that creates the link.
+	URL         string `json:"url,omitempty"`
+	AccessCount int    `json:"access_count"`
+}
+
+// Active reports whether the link grants access at t.
+func (l *ShareLink) Active(t time.Time) bool {
+	return l.RevokedAt == nil && t.Before(l.ExpiresAt)
+}
+
+// ShareAccess records one use of a share link.
+type ShareAccess struct {
+	LinkID    string    `json:"link_id"`
+	At        time.Time `json:"at"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	Format    string    `json:"format"`
+}
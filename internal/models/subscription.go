@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Subscription registers a user's interest in review changes. It either
+// names a single review or matches reviews by label and/or author.
+type Subscription struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	OrgID     string    `json:"org_id"`
+	ReviewID  string    `json:"review_id,omitempty"`
+	Label     string    `json:"label,omitempty"`
+	AuthorID  string    `json:"author_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Matches reports whether a change to the described review concerns this subscription.
+func (s *Subscription) Matches(reviewID, authorID string, labels []string) bool {
+	if s.ReviewID != "" {
+		return s.ReviewID == reviewID
+	}
+	if s.AuthorID != "" && s.AuthorID != authorID {
+		return false
+	}
+	if s.Label == "" {
+		return s.AuthorID != ""
+	}
+	for _, l := range labels {
+		if l == s.Label {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification is a message delivered to a user's inbox.
+type Notification struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	OrgID     string    `json:"org_id"`
+	Type      string    `json:"type"`
+	ReviewID  string    `json:"review_id"`
+	ActorID   string    `json:"actor_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
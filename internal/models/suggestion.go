@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// What AI suggestions propose.
+const (
+	SuggestReviewer = "reviewer"
+	SuggestLabel    = "label"
+)
+
+// AI suggestion statuses.
+const (
+	SuggestionPending  = "pending"
+	SuggestionAccepted = "accepted"
+	SuggestionRejected = "rejected"
+)
+
+// Suggestion is a reviewer or label a language model proposed for a
+// review. Accepting or rejecting it feeds the organization's suggestion
+// weights.
+type Suggestion struct {
+	ID       string `json:"id"`
+	OrgID    string `json:"org_id"`
+	ReviewID string `json:"review_id"`
+	Kind     string `json:"kind"`
+	// Value is the user ID of a reviewer or the label.
+	Value  string `json:"value"`
+	Reason string `json:"reason,omitempty"`
+	// Confidence is the model's own, from 0 to 1; Score weighs it by the
+	// organization's feedback on earlier suggestions of the same value.
+	Confidence float64    `json:"confidence"`
+	Score      float64    `json:"score"`
+	Status     string     `json:"status"`
+	Provider   string     `json:"provider"`
+	Model      string     `json:"model"`
+	CreatedAt  time.Time  `json:"created_at"`
+	DecidedBy  string     `json:"decided_by,omitempty"`
+	DecidedAt  *time.Time `json:"decided_at,omitempty"`
+}
+
+// SuggestionFeedback counts how an organization decided on suggestions of
+// one reviewer or label.
+type SuggestionFeedback struct {
+	Kind     string `json:"kind"`
+	Value    string `json:"value"`
+	Accepted int    `json:"accepted"`
+	Rejected int    `json:"rejected"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Team is a named group of an organization's members.
+type Team struct {
+	ID        string    `json:"id"`
+	OrgID     string    `json:"org_id"`
+	Name      string    `json:"name"`
+	Members   []string  `json:"members"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
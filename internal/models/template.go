@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Template kinds.
+const (
+	TemplateReview   = "review"
+	TemplateWorkflow = "workflow"
+)
+
+// TemplateSpec is a template as published in a bundle. Review is set for
+// review templates and Workflow for workflow definitions.
+type TemplateSpec struct {
+	Kind        string              `json:"kind"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Review      *ReviewTemplate     `json:"review,omitempty"`
+	Workflow    *WorkflowDefinition `json:"workflow,omitempty"`
+}
+
+// ReviewTemplate prefills a new review.
+type ReviewTemplate struct {
+	Title     string                 `json:"title"`
+	Content   string                 `json:"content,omitempty"`
+	Checklist []string               `json:"checklist,omitempty"`
+	Labels    []string               `json:"labels,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+// WorkflowDefinition describes a review process as states and the
+// transitions allowed between them.
+type WorkflowDefinition struct {
+	Initial     string               `json:"initial"`
+	States      []string             `json:"states"`
+	Final       []string             `json:"final"`
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+// WorkflowTransition moves a review from one state to another. Roles
+// limits who may take it; empty lets anyone.
+type WorkflowTransition struct {
+	Name  string   `json:"name"`
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// Template is one version of an organization's imported template. A new
+// version is recorded each time an import changes its content.
+type Template struct {
+	TemplateSpec
+	ID         string         `json:"id"`
+	OrgID      string         `json:"org_id"`
+	Version    int            `json:"version"`
+	Digest     string         `json:"digest"`
+	Source     TemplateSource `json:"source"`
+	ImportedBy string         `json:"imported_by"`
+	ImportedAt time.Time      `json:"imported_at"`
+}
+
+// TemplateSource records where a template version was imported from.
+type TemplateSource struct {
+	URL           string `json:"url"`
+	Catalog       bool   `json:"catalog"`
+	Bundle        string `json:"bundle"`
+	BundleVersion string `json:"bundle_version"`
+	KeyID         string `json:"key_id,omitempty"`
+	Verified      bool   `json:"verified"`
+}
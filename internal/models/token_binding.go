@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// TokenBinding ties an issued access token to the client it was issued
+// to. Tokens are identified by hash only.
+type TokenBinding struct {
+	TokenHash  string    `json:"-"`
+	OrgID      string    `json:"org_id"`
+	UserID     string    `json:"user_id"`
+	Mode       string    `json:"mode"`
+	Thumbprint string    `json:"thumbprint"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
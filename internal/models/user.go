@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// User is a member of an organization. Role is the org-scoped role: admin,
+// reviewer, member or guest.
+type User struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	OrgID     string    `json:"org_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
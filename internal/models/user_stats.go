@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// UserStats summarizes a user's review activity in their organization.
+type UserStats struct {
+	UserID string `json:"user_id"`
+	OrgID  string `json:"org_id"`
+	// Authored counts the reviews the user created.
+	Authored int `json:"authored"`
+	// Reviewed counts the reviews the user approved.
+	Reviewed int `json:"reviewed"`
+	// AverageTurnaroundHours is the mean time from assignment (or
+	// creation, for unassigned reviews) to the user's approval; 0 when
+	// the user has approved nothing.
+	AverageTurnaroundHours float64 `json:"average_turnaround_hours"`
+	// Workload counts the unapproved reviews currently assigned to the user.
+	Workload    int       `json:"workload"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery records one event sent to a webhook, with the exact
+// payload, so it can be inspected and redelivered after a consumer outage.
+type WebhookDelivery struct {
+	ID        string          `json:"id"`
+	OrgID     string          `json:"org_id"`
+	WebhookID string          `json:"webhook_id"`
+	Event     string          `json:"event"`
+	ReviewID  string          `json:"review_id"`
+	Payload   json.RawMessage `json:"payload"`
+	Status    string          `json:"status"`
+	// Attempts counts the original delivery and every redelivery.
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+}
+
+// DeliveryFilter selects webhook deliveries. Zero fields match everything.
+type DeliveryFilter struct {
+	WebhookID string
+	Status    string
+	Since     time.Time
+	Until     time.Time
+}
+
+// Matches reports whether d passes the filter.
+func (f DeliveryFilter) Matches(d *WebhookDelivery) bool {
+	switch {
+	case f.WebhookID != "" && d.WebhookID != f.WebhookID:
+		return false
+	case f.Status != "" && d.Status != f.Status:
+		return false
+	case !f.Since.IsZero() && d.CreatedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !d.CreatedAt.Before(f.Until):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+// Package moderation finds and masks the terms an organization bans from
+// review and comment text.
+package moderation
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// Filter matches banned terms as whole words, ignoring case.
+type Filter struct {
+	pattern *regexp.Regexp
+}
+
+// New compiles the banned terms of a policy. A policy without terms gives a
+// filter that matches nothing.
+func New(policy models.ModerationPolicy) *Filter {
+	if len(policy.BannedTerms) == 0 {
+		return &Filter{}
+	}
+	quoted := make([]string, len(policy.BannedTerms))
+	for i, t := range policy.BannedTerms {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	return &Filter{pattern: regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)}
+}
+
+// Find returns the banned terms in text, lowercased and without
+// duplicates, in the order they appear.
+func (f *Filter) Find(text string) []string {
+	if f.pattern == nil {
+		return nil
+	}
+	var found []string
+	seen := make(map[string]bool)
+	for _, m := range f.pattern.FindAllString(text, -1) {
+		m = strings.ToLower(m)
+		if !seen[m] {
+			seen[m] = true
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// Mask replaces every banned term in text with as many asterisks as it has
+// characters.
+func (f *Filter) Mask(text string) string {
+	if f.pattern == nil {
+		return text
+	}
+	return f.pattern.ReplaceAllStringFunc(text, func(m string) string {
+		return strings.Repeat("*", utf8.RuneCountInString(m))
+	})
+}
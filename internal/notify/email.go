@@ -0,0 +1,159 @@
+package notify
+
+import (
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/mail"
+	"github.com/andres20980/aurea-orchestrator/internal/markdown"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// eventSubjects are the email subjects of each event type, in English.
+var eventSubjects = map[string]string{
+	ReviewUpdated:   "Review %s was updated",
+	ReviewApproved:  "Review %s was approved",
+	ReviewAssigned:  "Review %s was assigned to you",
+	ReviewReopened:  "Review %s was reopened",
+	ReviewLabeled:   "Labels changed on review %s",
+	CommentAdded:    "New comment on review %s",
+	CommentResolved: "A comment thread was resolved on review %s",
+}
+
+// EmailChannel emails notifications to their recipients in each
+// recipient's preferred language.
+type EmailChannel struct {
+	st      *store.Memory
+	mailer  mail.Mailer
+	baseURL string
+}
+
+// NewEmailChannel creates an email channel.
+func NewEmailChannel(st *store.Memory, mailer mail.Mailer, baseURL string) *EmailChannel {
+	return &EmailChannel{st: st, mailer: mailer, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Name identifies the channel's dead letters.
+func (c *EmailChannel) Name() string {
+	return models.DeadLetterEmail
+}
+
+// Deliver emails the notification.
+func (c *EmailChannel) Deliver(n *models.Notification) error {
+	if k, disabled := c.st.KillSwitch(models.CapabilityEmail, n.OrgID); disabled {
+		return k
+	}
+	user, err := c.st.GetUser(n.UserID)
+	if err != nil {
+		return fmt.Errorf("look up recipient: %w", err)
+	}
+	lang := i18n.Negotiate(c.st.GetProfile(n.UserID).Locale, "")
+
+	subject, ok := eventSubjects[n.Type]
+	if !ok {
+		subject = "Activity on review %s"
+	}
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: i18n.T(lang, subject, n.ReviewID),
+		Body:    i18n.T(lang, "View the review: %s/reviews/%s", c.baseURL, n.ReviewID),
+	}
+	excerpt := c.excerpt(n)
+	if excerpt != "" {
+		msg.Body = excerpt + "\n\n" + msg.Body
+	}
+	b := c.st.GetBranding(n.OrgID)
+	branded := b.Name != "" || b.PrimaryColor != "" || b.LogoURL != ""
+	if branded && b.Name != "" {
+		msg.Subject = "[" + b.Name + "] " + msg.Subject
+	}
+	if branded || excerpt != "" {
+		html := template.HTML(markdown.Render(excerpt, markdown.Options{
+			Mention: markdown.MentionUsers(c.st.ListOrgUsers(n.OrgID), c.baseURL),
+		}))
+		msg.HTML = emailHTML(b, branded, msg.Subject, i18n.T(lang, "View the review: %s/reviews/%s", c.baseURL, n.ReviewID), html)
+	}
+	return c.mailer.Send(msg)
+}
+
+// excerpt returns the Markdown the notification is about: the new comment
+// for comment notifications, the review content for updates and
+// assignments.
+func (c *EmailChannel) excerpt(n *models.Notification) string {
+	switch n.Type {
+	case CommentAdded:
+		comments := c.st.ListComments(n.ReviewID)
+		for i := len(comments) - 1; i >= 0; i-- {
+			if comments[i].AuthorID == n.ActorID {
+				return comments[i].Body
+			}
+		}
+	case ReviewUpdated, ReviewAssigned:
+		if review, err := c.st.GetReview(n.ReviewID); err == nil {
+			return review.Content
+		}
+	}
+	return ""
+}
+
+// notificationEmail lays out a notification, under the organization's
+// header when it is branded, with the rendered Markdown it is about.
+// Styles are inline because most email clients ignore style sheets.
+var notificationEmail = template.Must(template.New("email").Parse(`<!doctype html>
+<html><head><meta charset="utf-8"><title>{{.Subject}}</title></head>
+<body style="margin:0;font-family:sans-serif;color:#1f2328">
+{{if .Branded}}<div style="background:{{.Color}};padding:12px 24px;color:{{.TextColor}};font-size:18px;font-weight:bold">
+{{if .LogoURL}}<img src="{{.LogoURL}}" alt="" height="32" style="vertical-align:middle;margin-right:12px">{{end}}{{.Name}}
+</div>
+{{end}}{{if .Excerpt}}<div style="padding:0 24px">{{.Excerpt}}</div>
+{{end}}<p style="padding:0 24px;border-left:4px solid {{.Accent}}">{{.Body}}</p>
+</body></html>`))
+
+// emailHTML renders a notification email. excerpt must be sanitized HTML.
+func emailHTML(b models.OrgBranding, branded bool, subject, body string, excerpt template.HTML) string {
+	data := struct {
+		Subject, Body, Name, LogoURL string
+		Branded                      bool
+		Excerpt                      template.HTML
+		Color, Accent, TextColor     template.CSS
+	}{
+		Subject:   subject,
+		Body:      body,
+		Branded:   branded,
+		Excerpt:   excerpt,
+		Name:      b.Name,
+		LogoURL:   b.LogoURL,
+		Color:     "#24292f",
+		Accent:    "#d0d7de",
+		TextColor: "#ffffff",
+	}
+	// Colors are validated as #rrggbb when saved, so they are safe in CSS.
+	if b.PrimaryColor != "" {
+		data.Color = template.CSS(b.PrimaryColor)
+		if !darkColor(b.PrimaryColor) {
+			data.TextColor = "#000000"
+		}
+	}
+	if b.AccentColor != "" {
+		data.Accent = template.CSS(b.AccentColor)
+	}
+	var out strings.Builder
+	if err := notificationEmail.Execute(&out, data); err != nil {
+		return ""
+	}
+	return out.String()
+}
+
+// darkColor reports whether a "#rrggbb" color needs light text.
+func darkColor(hex string) bool {
+	v, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil {
+		return true
+	}
+	r, g, b := float64(v>>16&0xff), float64(v>>8&0xff), float64(v&0xff)
+	return 0.299*r+0.587*g+0.114*b < 0.6*255
+}
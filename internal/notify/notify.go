@@ -0,0 +1,224 @@
+// Package notify routes review change events to the users watching them.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/deadletter"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+const (
+	relayInterval = 5 * time.Second
+	relayBatch    = 100
+)
+
+// Event types published by the API.
+const (
+	ReviewCreated   = "review.created"
+	ReviewUpdated   = "review.updated"
+	ReviewApproved  = "review.approved"
+	ReviewAssigned  = "review.assigned"
+	ReviewReopened  = "review.reopened"
+	ReviewLabeled   = "review.labeled"
+	CommentAdded    = "comment.added"
+	CommentResolved = "comment.resolved"
+)
+
+// EventTypes lists every event type, for validating subscriptions.
+var EventTypes = []string{
+	ReviewCreated, ReviewUpdated, ReviewApproved, ReviewAssigned, ReviewReopened,
+	ReviewLabeled, CommentAdded, CommentResolved,
+}
+
+// Event describes a change to a review.
+type Event struct {
+	Type     string
+	ReviewID string
+	ActorID  string
+}
+
+// Channel delivers notifications outside the in-app inbox, e.g. email or push.
+type Channel interface {
+	Deliver(n *models.Notification) error
+}
+
+// NamedChannel is a channel whose failed deliveries can be dead-lettered
+// under its name.
+type NamedChannel interface {
+	Channel
+	Name() string
+}
+
+// Listener receives every published event, whether or not anyone watches
+// the review. Integrations use it to mirror changes to external systems.
+type Listener func(Event)
+
+// Dispatcher fans events out to watchers' inboxes, any extra channels and
+// registered listeners.
+type Dispatcher struct {
+	st        *store.Memory
+	channels  []Channel
+	listeners []Listener
+	wake      chan struct{}
+	dead      *deadletter.Queue
+}
+
+// NewDispatcher creates a dispatcher backed by the store's subscriptions and inboxes.
+func NewDispatcher(st *store.Memory, channels ...Channel) *Dispatcher {
+	return &Dispatcher{st: st, channels: channels, wake: make(chan struct{}, 1)}
+}
+
+// Listen registers a listener. It must be called before the server starts.
+func (d *Dispatcher) Listen(l Listener) {
+	d.listeners = append(d.listeners, l)
+}
+
+// UseDeadLetters records failed deliveries of named channels in q and
+// lets q retry them. It must be called before the server starts.
+func (d *Dispatcher) UseDeadLetters(q *deadletter.Queue) {
+	d.dead = q
+	for _, ch := range d.channels {
+		named, ok := ch.(NamedChannel)
+		if !ok {
+			continue
+		}
+		q.Handle(named.Name(), func(ctx context.Context, l models.DeadLetter) error {
+			var n models.Notification
+			if err := json.Unmarshal(l.Payload, &n); err != nil {
+				return err
+			}
+			return named.Deliver(&n)
+		})
+	}
+}
+
+// Publish records the event in the store's outbox. Run delivers it.
+// Recording first means an event accepted here is delivered even if the
+// process restarts before delivery, as long as the outbox is journaled.
+func (d *Dispatcher) Publish(e Event) {
+	if err := d.st.AppendOutbox(e.outbox()); err != nil {
+		log.Printf("Failed to record %s event for review %s: %v", e.Type, e.ReviewID, err)
+		return
+	}
+	d.poke()
+}
+
+// PublishTx records the event in the outbox as part of tx, so it is
+// delivered only if tx commits.
+func (d *Dispatcher) PublishTx(tx *store.Tx, e Event) {
+	tx.AppendOutbox(e.outbox())
+	tx.OnCommit(d.poke)
+}
+
+func (e Event) outbox() *models.OutboxEvent {
+	return &models.OutboxEvent{Type: e.Type, ReviewID: e.ReviewID, ActorID: e.ActorID, CreatedAt: time.Now()}
+}
+
+// poke wakes Run to relay new events.
+func (d *Dispatcher) poke() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run relays outbox events in order until ctx is cancelled. Events are
+// removed from the outbox only after delivery, so delivery is at least once.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(relayInterval)
+	defer ticker.Stop()
+	for {
+		for _, oe := range d.st.PendingOutbox(relayBatch) {
+			d.deliver(Event{Type: oe.Type, ReviewID: oe.ReviewID, ActorID: oe.ActorID})
+			if err := d.st.MarkPublished(oe.ID); err != nil {
+				log.Printf("Failed to mark event %d published: %v", oe.ID, err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver notifies every user watching the review, and for assignments the
+// new assignee, except the actor who made the change, then passes the
+// event to each listener.
+func (d *Dispatcher) deliver(e Event) {
+	review, err := d.st.GetReview(e.ReviewID)
+	if err != nil {
+		return
+	}
+	defer func() {
+		for _, l := range d.listeners {
+			d.callListener(l, e)
+		}
+	}()
+
+	recipients := d.st.Watchers(review.OrgID, review.ID, review.AuthorID, d.st.GetLabels(review.ID))
+	if a, ok := d.st.GetAssignment(review.ID); ok && e.Type == ReviewAssigned && !slices.Contains(recipients, a.ReviewerID) {
+		recipients = append(recipients, a.ReviewerID)
+	}
+
+	now := time.Now()
+	for _, userID := range recipients {
+		if userID == e.ActorID {
+			continue
+		}
+		n := &models.Notification{
+			UserID:    userID,
+			OrgID:     review.OrgID,
+			Type:      e.Type,
+			ReviewID:  review.ID,
+			ActorID:   e.ActorID,
+			CreatedAt: now,
+		}
+		d.st.AddNotification(n)
+		for _, ch := range d.channels {
+			if err := ch.Deliver(n); err != nil {
+				log.Printf("Failed to deliver %s notification to %s: %v", n.Type, n.UserID, err)
+				d.deadLetter(ch, n, err)
+			}
+		}
+	}
+}
+
+// deadLetter records a failed channel delivery for retry.
+func (d *Dispatcher) deadLetter(ch Channel, n *models.Notification, err error) {
+	named, ok := ch.(NamedChannel)
+	if d.dead == nil || !ok {
+		return
+	}
+	payload, merr := json.Marshal(n)
+	if merr != nil {
+		return
+	}
+	d.dead.Add(models.DeadLetter{
+		OrgID:     n.OrgID,
+		Kind:      named.Name(),
+		Reference: n.ID,
+		Summary:   fmt.Sprintf("%s notification to %s", n.Type, n.UserID),
+		Payload:   payload,
+		Error:     err.Error(),
+	})
+}
+
+// callListener isolates listener panics so one faulty integration cannot
+// stop the relay.
+func (d *Dispatcher) callListener(l Listener, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Listener panicked on %s for review %s: %v", e.Type, e.ReviewID, r)
+		}
+	}()
+	l(e)
+}
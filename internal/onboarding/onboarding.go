@@ -0,0 +1,50 @@
+// Package onboarding works out a user's welcome checklist from what they
+// have done so far, so clients can drive guided onboarding without
+// reporting progress themselves.
+package onboarding
+
+import (
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// done reports whether the user has completed a step.
+func done(st *store.Memory, userID, step string) bool {
+	switch step {
+	case models.OnboardingProfile:
+		p := st.GetProfile(userID)
+		return p.DisplayName != "" && p.Timezone != ""
+	case models.OnboardingFirstReview:
+		return st.HasAuthoredReview(userID)
+	case models.OnboardingNotifications:
+		return len(st.ListSubscriptions(userID)) > 0 || len(st.ListDevices(userID)) > 0
+	}
+	return false
+}
+
+// State returns the user's checklist at now. A step is detected as done
+// the first time its condition holds, and stays done afterwards even if
+// the user later undoes it, e.g. by clearing their profile.
+func State(st *store.Memory, userID string, now time.Time) models.Onboarding {
+	o := models.Onboarding{UserID: userID, Completed: true}
+	completions := st.OnboardingCompletions(userID)
+	for _, step := range models.OnboardingSteps {
+		s := models.OnboardingStep{Key: step}
+		at, ok := completions[step]
+		if !ok && done(st, userID, step) {
+			at, ok = st.CompleteOnboardingStep(userID, step, now), true
+		}
+		if ok {
+			s.Done, s.CompletedAt = true, &at
+		} else {
+			o.Completed = false
+		}
+		o.Steps = append(o.Steps, s)
+	}
+	if at, ok := st.OnboardingDismissed(userID); ok {
+		o.DismissedAt = &at
+	}
+	return o
+}
@@ -0,0 +1,202 @@
+// Package operations runs long tasks in the background on behalf of
+// requests that are answered right away with 202 Accepted. Each task is
+// recorded as an operation in the store, which clients poll for progress
+// and the result, and can be cancelled while it runs.
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+const (
+	// retention is how long finished operations and their files are kept.
+	retention = 24 * time.Hour
+	// pruneInterval is how often expired operations are removed.
+	pruneInterval = time.Hour
+	// blobPrefix is where the files operations produce are stored.
+	blobPrefix = "operations/"
+)
+
+// Func is the work of an operation. It should stop early, returning
+// ctx.Err(), once ctx is cancelled. The value it returns is recorded as
+// the operation's result.
+type Func func(ctx context.Context, t *Task) (interface{}, error)
+
+// Runner starts operations and tracks the running ones.
+type Runner struct {
+	st    *store.Memory
+	blobs blob.Store
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+// NewRunner creates a runner that records operations in st and keeps the
+// files they produce in blobs.
+func NewRunner(st *store.Memory, blobs blob.Store) *Runner {
+	return &Runner{st: st, blobs: blobs, cancels: make(map[string]context.CancelFunc)}
+}
+
+// Start records op and runs fn in the background. It returns the recorded
+// operation, still running.
+func (r *Runner) Start(op models.Operation, fn Func) models.Operation {
+	op = r.st.CreateOperation(op)
+	ctx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	r.cancels[op.ID] = cancel
+	r.mu.Unlock()
+	go r.run(ctx, op.ID, fn)
+	return op
+}
+
+func (r *Runner) run(ctx context.Context, id string, fn Func) {
+	defer func() {
+		r.mu.Lock()
+		r.cancels[id]()
+		delete(r.cancels, id)
+		r.mu.Unlock()
+	}()
+	value, err := fn(ctx, &Task{r: r, id: id, ctx: ctx})
+	var result json.RawMessage
+	if err == nil && value != nil {
+		result, err = json.Marshal(value)
+	}
+	now := time.Now()
+	_, uerr := r.st.UpdateOperation(id, func(op *models.Operation) {
+		op.FinishedAt = &now
+		switch {
+		case ctx.Err() != nil:
+			op.Status = models.OperationCanceled
+		case err != nil:
+			op.Status = models.OperationFailed
+			op.Error = err.Error()
+		default:
+			op.Status = models.OperationSucceeded
+			op.Result = result
+		}
+	})
+	if uerr != nil && !errors.Is(uerr, store.ErrNotFound) {
+		log.Printf("Failed to record the end of operation %s: %v", id, uerr)
+	}
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Operation %s failed: %v", id, err)
+	}
+}
+
+// Cancel asks one of an organization's running operations to stop. The
+// operation is marked cancelled once its work returns.
+func (r *Runner) Cancel(orgID, id string) (models.Operation, error) {
+	op, err := r.st.GetOperation(orgID, id)
+	if err != nil {
+		return op, err
+	}
+	op, err = r.st.UpdateOperation(id, func(op *models.Operation) {
+		op.CancelRequested = true
+	})
+	if err != nil {
+		return op, err
+	}
+	r.mu.Lock()
+	if cancel, ok := r.cancels[id]; ok {
+		cancel()
+	}
+	r.mu.Unlock()
+	return op, nil
+}
+
+// Open returns the file an operation produced.
+func (r *Runner) Open(op models.Operation) (io.ReadCloser, error) {
+	if op.File == nil || op.Status != models.OperationSucceeded {
+		return nil, blob.ErrNotFound
+	}
+	rc, _, err := r.blobs.Get(op.File.BlobKey)
+	return rc, err
+}
+
+// Run removes finished operations, with their files, once they are older
+// than the retention period, until ctx is cancelled.
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, op := range r.st.DeleteOperationsBefore(time.Now().Add(-retention)) {
+			if op.File == nil {
+				continue
+			}
+			if err := r.blobs.Delete(op.File.BlobKey); err != nil {
+				log.Printf("Failed to delete the file of operation %s: %v", op.ID, err)
+			}
+		}
+	}
+}
+
+// Task lets a running operation report progress and save files.
+type Task struct {
+	r   *Runner
+	id  string
+	ctx context.Context
+}
+
+// ID returns the operation's ID.
+func (t *Task) ID() string {
+	return t.id
+}
+
+// Progress records how much of the work is done. A total of zero means it
+// is not known.
+func (t *Task) Progress(done, total int) {
+	t.r.st.UpdateOperation(t.id, func(op *models.Operation) {
+		op.Progress = models.OperationProgress{Done: done, Total: total}
+	})
+}
+
+// SaveFile stores the file write produces as the operation's downloadable
+// result. Writing fails once the operation is cancelled.
+func (t *Task) SaveFile(name, contentType string, write func(w io.Writer) error) error {
+	key := fmt.Sprintf("%s%s/%s", blobPrefix, t.id, name)
+	pr, pw := io.Pipe()
+	var size int64
+	go func() {
+		pw.CloseWithError(write(&taskWriter{ctx: t.ctx, w: pw, n: &size}))
+	}()
+	if err := t.r.blobs.Put(key, contentType, pr); err != nil {
+		pr.CloseWithError(err)
+		return err
+	}
+	_, err := t.r.st.UpdateOperation(t.id, func(op *models.Operation) {
+		op.File = &models.OperationFile{Name: name, ContentType: contentType, Size: size, BlobKey: key}
+	})
+	return err
+}
+
+// taskWriter counts what is written and stops writes once ctx is done.
+type taskWriter struct {
+	ctx context.Context
+	w   io.Writer
+	n   *int64
+}
+
+func (w *taskWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := w.w.Write(p)
+	*w.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,153 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+	// Group and version of the custom resources.
+	Group   = "aurea.io"
+	Version = "v1alpha1"
+)
+
+// Object is the part of a custom resource the controller reads.
+type Object struct {
+	Metadata ObjectMeta      `json:"metadata"`
+	Spec     json.RawMessage `json:"spec"`
+}
+
+// ObjectMeta is the subset of Kubernetes object metadata used here.
+type ObjectMeta struct {
+	Name              string     `json:"name"`
+	Namespace         string     `json:"namespace"`
+	Generation        int64      `json:"generation"`
+	DeletionTimestamp *time.Time `json:"deletionTimestamp,omitempty"`
+	Finalizers        []string   `json:"finalizers,omitempty"`
+}
+
+// Kube is a minimal Kubernetes API client for custom resources and secrets.
+type Kube struct {
+	host  string
+	token string
+	http  *http.Client
+}
+
+// InCluster configures a client from the pod's service account.
+func InCluster() (*Kube, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("not running inside a Kubernetes cluster")
+	}
+	token, err := os.ReadFile(serviceAccountDir + "/token")
+	if err != nil {
+		return nil, err
+	}
+	ca, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("invalid cluster CA certificate")
+	}
+	transport := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}}
+	return &Kube{
+		host:  "https://" + host + ":" + port,
+		token: strings.TrimSpace(string(token)),
+		http:  &http.Client{Transport: transport, Timeout: 30 * time.Second},
+	}, nil
+}
+
+// List returns every resource of the given plural kind in all namespaces.
+func (k *Kube) List(ctx context.Context, plural string) ([]Object, error) {
+	var list struct {
+		Items []Object `json:"items"`
+	}
+	err := k.do(ctx, http.MethodGet, fmt.Sprintf("/apis/%s/%s/%s", Group, Version, plural), "", nil, &list)
+	return list.Items, err
+}
+
+// PatchStatus merges status into the resource's status subresource.
+func (k *Kube) PatchStatus(ctx context.Context, plural string, meta ObjectMeta, status interface{}) error {
+	return k.do(ctx, http.MethodPatch, resourcePath(plural, meta)+"/status", "application/merge-patch+json",
+		map[string]interface{}{"status": status}, nil)
+}
+
+// SetFinalizers replaces the resource's finalizers.
+func (k *Kube) SetFinalizers(ctx context.Context, plural string, meta ObjectMeta, finalizers []string) error {
+	if finalizers == nil {
+		finalizers = []string{}
+	}
+	return k.do(ctx, http.MethodPatch, resourcePath(plural, meta), "application/merge-patch+json",
+		map[string]interface{}{"metadata": map[string]interface{}{"finalizers": finalizers}}, nil)
+}
+
+// SecretValue returns one key of a secret.
+func (k *Kube) SecretValue(ctx context.Context, namespace, name, key string) (string, error) {
+	var secret struct {
+		Data map[string]string `json:"data"`
+	}
+	path := fmt.Sprintf("/api/v1/namespaces/%s/secrets/%s", url.PathEscape(namespace), url.PathEscape(name))
+	if err := k.do(ctx, http.MethodGet, path, "", nil, &secret); err != nil {
+		return "", err
+	}
+	encoded, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s has no key %q", namespace, name, key)
+	}
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	return string(value), err
+}
+
+func (k *Kube) do(ctx context.Context, method, path, contentType string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, k.host+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+k.token)
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := k.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("kubernetes %s %s: %d %s", method, path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func resourcePath(plural string, meta ObjectMeta) string {
+	return fmt.Sprintf("/apis/%s/%s/namespaces/%s/%s/%s", Group, Version,
+		url.PathEscape(meta.Namespace), plural, url.PathEscape(meta.Name))
+}
@@ -0,0 +1,265 @@
+// Package operator reconciles AureaOrg and AureaWebhook custom resources
+// into orchestrator organizations and webhooks through the management API.
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/pkg/client"
+)
+
+const (
+	orgsPlural     = "aureaorgs"
+	webhooksPlural = "aureawebhooks"
+	finalizer      = "aurea.io/cleanup"
+)
+
+// OrgSpec is the spec of an AureaOrg resource.
+type OrgSpec struct {
+	// ID optionally fixes the organization ID; otherwise the server assigns one.
+	ID          string `json:"id,omitempty"`
+	DisplayName string `json:"displayName"`
+	// Region pins the organization's data; it only applies at creation.
+	Region string `json:"region,omitempty"`
+}
+
+// SecretKeyRef names one key of a secret in the resource's namespace.
+type SecretKeyRef struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+// WebhookSpec is the spec of an AureaWebhook resource.
+type WebhookSpec struct {
+	// OrgRef is the name of an AureaOrg in the same namespace.
+	OrgRef string   `json:"orgRef"`
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"`
+	Active *bool    `json:"active,omitempty"`
+	// SigningSecretRef optionally supplies the HMAC signing secret.
+	SigningSecretRef *SecretKeyRef `json:"signingSecretRef,omitempty"`
+	// APIKeyRef supplies an org admin API key used to manage the webhook.
+	APIKeyRef SecretKeyRef `json:"apiKeyRef"`
+}
+
+// Status is written to the status subresource of both kinds.
+type Status struct {
+	ID                 string `json:"id,omitempty"`
+	Ready              bool   `json:"ready"`
+	Message            string `json:"message,omitempty"`
+	ObservedGeneration int64  `json:"observedGeneration"`
+}
+
+// Controller periodically reconciles all custom resources.
+type Controller struct {
+	kube     *Kube
+	api      *client.Client
+	baseURL  string
+	interval time.Duration
+}
+
+// New creates a controller. api must authenticate as an instance operator;
+// webhooks are managed with the per-org API key named in each resource.
+func New(kube *Kube, api *client.Client, baseURL string, interval time.Duration) *Controller {
+	return &Controller{kube: kube, api: api, baseURL: baseURL, interval: interval}
+}
+
+// Run reconciles every interval until ctx is cancelled.
+func (c *Controller) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.ReconcileAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReconcileAll reconciles every AureaOrg, then every AureaWebhook.
+func (c *Controller) ReconcileAll(ctx context.Context) {
+	orgs, err := c.kube.List(ctx, orgsPlural)
+	if err != nil {
+		log.Printf("Listing AureaOrgs failed: %v", err)
+		return
+	}
+	orgIDs := make(map[string]string)
+	for _, obj := range orgs {
+		status := c.reconcileOrg(ctx, obj)
+		if status.Ready {
+			orgIDs[obj.Metadata.Namespace+"/"+obj.Metadata.Name] = status.ID
+		}
+	}
+
+	hooks, err := c.kube.List(ctx, webhooksPlural)
+	if err != nil {
+		log.Printf("Listing AureaWebhooks failed: %v", err)
+		return
+	}
+	for _, obj := range hooks {
+		c.reconcileWebhook(ctx, obj, orgIDs)
+	}
+}
+
+func (c *Controller) reconcileOrg(ctx context.Context, obj Object) Status {
+	meta := obj.Metadata
+	status := Status{ObservedGeneration: meta.Generation}
+	external := externalID(meta)
+
+	var spec OrgSpec
+	if err := json.Unmarshal(obj.Spec, &spec); err != nil || spec.DisplayName == "" {
+		return c.finish(ctx, orgsPlural, meta, status, errors.New("spec.displayName is required"))
+	}
+
+	existing, err := c.api.FindOrgByExternalID(ctx, external)
+	if err != nil {
+		return c.finish(ctx, orgsPlural, meta, status, err)
+	}
+
+	if meta.DeletionTimestamp != nil {
+		if existing != nil {
+			if err := c.api.DeleteOrg(ctx, existing.ID); err != nil && !isNotFound(err) {
+				return c.finish(ctx, orgsPlural, meta, status, err)
+			}
+		}
+		c.removeFinalizer(ctx, orgsPlural, meta)
+		return status
+	}
+	if err := c.ensureFinalizer(ctx, orgsPlural, meta); err != nil {
+		return c.finish(ctx, orgsPlural, meta, status, err)
+	}
+
+	switch {
+	case existing == nil:
+		existing, err = c.api.CreateOrg(ctx, client.ManagedOrg{ID: spec.ID, ExternalID: external, Name: spec.DisplayName, Region: spec.Region})
+	case existing.Name != spec.DisplayName:
+		existing, err = c.api.UpdateOrg(ctx, client.ManagedOrg{ID: existing.ID, ExternalID: external, Name: spec.DisplayName})
+	}
+	if err != nil {
+		return c.finish(ctx, orgsPlural, meta, status, err)
+	}
+	status.ID = existing.ID
+	return c.finish(ctx, orgsPlural, meta, status, nil)
+}
+
+func (c *Controller) reconcileWebhook(ctx context.Context, obj Object, orgIDs map[string]string) {
+	meta := obj.Metadata
+	status := Status{ObservedGeneration: meta.Generation}
+	external := externalID(meta)
+
+	var spec WebhookSpec
+	if err := json.Unmarshal(obj.Spec, &spec); err != nil || spec.OrgRef == "" || spec.URL == "" || spec.APIKeyRef.Name == "" {
+		c.finish(ctx, webhooksPlural, meta, status, errors.New("spec.orgRef, spec.url and spec.apiKeyRef are required"))
+		return
+	}
+	orgID, ok := orgIDs[meta.Namespace+"/"+spec.OrgRef]
+	if !ok {
+		if meta.DeletionTimestamp != nil {
+			// The organization is gone and took its webhooks with it.
+			c.removeFinalizer(ctx, webhooksPlural, meta)
+			return
+		}
+		c.finish(ctx, webhooksPlural, meta, status, fmt.Errorf("AureaOrg %q is not ready", spec.OrgRef))
+		return
+	}
+
+	key, err := c.kube.SecretValue(ctx, meta.Namespace, spec.APIKeyRef.Name, spec.APIKeyRef.Key)
+	if err != nil {
+		c.finish(ctx, webhooksPlural, meta, status, err)
+		return
+	}
+	api := client.New(c.baseURL, client.WithAPIKey(key))
+
+	existing, err := api.FindWebhookByExternalID(ctx, orgID, external)
+	if err != nil {
+		c.finish(ctx, webhooksPlural, meta, status, err)
+		return
+	}
+
+	if meta.DeletionTimestamp != nil {
+		if existing != nil {
+			if err := api.DeleteWebhook(ctx, orgID, existing.ID); err != nil && !isNotFound(err) {
+				c.finish(ctx, webhooksPlural, meta, status, err)
+				return
+			}
+		}
+		c.removeFinalizer(ctx, webhooksPlural, meta)
+		return
+	}
+	if err := c.ensureFinalizer(ctx, webhooksPlural, meta); err != nil {
+		c.finish(ctx, webhooksPlural, meta, status, err)
+		return
+	}
+
+	in := client.WebhookInput{ExternalID: external, URL: spec.URL, Events: spec.Events, Active: spec.Active}
+	if ref := spec.SigningSecretRef; ref != nil {
+		if in.Secret, err = c.kube.SecretValue(ctx, meta.Namespace, ref.Name, ref.Key); err != nil {
+			c.finish(ctx, webhooksPlural, meta, status, err)
+			return
+		}
+	}
+	if existing == nil {
+		existing, err = api.CreateWebhook(ctx, orgID, in)
+	} else {
+		// PUT is idempotent, so reapplying the spec every cycle is safe.
+		existing, err = api.UpdateWebhook(ctx, orgID, existing.ID, in)
+	}
+	if err != nil {
+		c.finish(ctx, webhooksPlural, meta, status, err)
+		return
+	}
+	status.ID = existing.ID
+	c.finish(ctx, webhooksPlural, meta, status, nil)
+}
+
+// finish records the outcome in the resource status and returns it.
+func (c *Controller) finish(ctx context.Context, plural string, meta ObjectMeta, status Status, err error) Status {
+	status.Ready = err == nil
+	if err != nil {
+		status.Message = err.Error()
+		log.Printf("Reconciling %s %s/%s failed: %v", plural, meta.Namespace, meta.Name, err)
+	}
+	if perr := c.kube.PatchStatus(ctx, plural, meta, status); perr != nil {
+		log.Printf("Updating status of %s %s/%s failed: %v", plural, meta.Namespace, meta.Name, perr)
+	}
+	return status
+}
+
+func (c *Controller) ensureFinalizer(ctx context.Context, plural string, meta ObjectMeta) error {
+	for _, f := range meta.Finalizers {
+		if f == finalizer {
+			return nil
+		}
+	}
+	return c.kube.SetFinalizers(ctx, plural, meta, append(meta.Finalizers, finalizer))
+}
+
+func (c *Controller) removeFinalizer(ctx context.Context, plural string, meta ObjectMeta) {
+	var kept []string
+	for _, f := range meta.Finalizers {
+		if f != finalizer {
+			kept = append(kept, f)
+		}
+	}
+	if err := c.kube.SetFinalizers(ctx, plural, meta, kept); err != nil {
+		log.Printf("Removing finalizer from %s %s/%s failed: %v", plural, meta.Namespace, meta.Name, err)
+	}
+}
+
+// externalID ties an API object to the custom resource that owns it.
+func externalID(meta ObjectMeta) string {
+	return "k8s:" + meta.Namespace + "/" + meta.Name
+}
+
+func isNotFound(err error) bool {
+	var apiErr *client.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
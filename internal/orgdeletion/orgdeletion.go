@@ -0,0 +1,89 @@
+// Package orgdeletion carries out organizations' staged deletions: once
+// the grace period of a pending deletion ends, the organization is
+// deleted with its members, reviews, attachments and webhooks, and the
+// files kept for it in the blob store are removed.
+package orgdeletion
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+	"github.com/andres20980/aurea-orchestrator/internal/compliance"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Grace is how long a requested deletion can be cancelled.
+const Grace = 14 * 24 * time.Hour
+
+// Actor is the ActorID of the audit events deletions record.
+const Actor = "org-deletion"
+
+// ExportKey is where the export taken when an organization's deletion was
+// requested is kept.
+func ExportKey(orgID string) string {
+	return "org-deletions/" + orgID + ".zip"
+}
+
+// Runner deletes organizations whose grace period has ended.
+type Runner struct {
+	st       *store.Memory
+	blobs    blob.Store
+	interval time.Duration
+}
+
+// New creates a runner that checks for due deletions every interval.
+func New(st *store.Memory, blobs blob.Store, interval time.Duration) *Runner {
+	return &Runner{st: st, blobs: blobs, interval: interval}
+}
+
+// Run deletes due organizations every interval until ctx is cancelled.
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.Check(now)
+		}
+	}
+}
+
+// Check deletes the organizations whose grace period has ended by now.
+// One placed under a legal hold meanwhile stays pending until the hold is
+// released.
+func (r *Runner) Check(now time.Time) {
+	for _, d := range r.st.DueOrgDeletions(now) {
+		keys := []string{ExportKey(d.OrgID)}
+		for _, report := range r.st.ListComplianceReports(d.OrgID) {
+			keys = append(keys, compliance.BlobKey(d.OrgID, report.Quarter))
+		}
+		purged, err := r.st.PurgeOrg(d.OrgID)
+		if errors.Is(err, store.ErrLegalHold) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Deleting organization %s failed: %v", d.OrgID, err)
+			continue
+		}
+		for _, key := range append(keys, purged...) {
+			if err := r.blobs.Delete(key); err != nil {
+				log.Printf("Failed to delete %s of organization %s: %v", key, d.OrgID, err)
+			}
+		}
+		log.Printf("Deleted organization %s, as requested by %s on %s", d.OrgID, d.RequestedBy, d.RequestedAt.Format(time.RFC3339))
+		r.st.AppendAudit(&models.AuditEvent{
+			OrgID:      d.OrgID,
+			ActorID:    Actor,
+			Action:     "org.deleted",
+			TargetType: "org",
+			TargetID:   d.OrgID,
+			Metadata:   map[string]string{"requested_by": d.RequestedBy},
+		})
+	}
+}
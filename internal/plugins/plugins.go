@@ -0,0 +1,309 @@
+// Package plugins lets compiled-in extensions hook into the review
+// lifecycle and serve their own routes. Plugins register themselves from an
+// init function, like database/sql drivers, and are enabled by operators at
+// runtime. Every hook runs with a timeout and recovers from panics so a
+// faulty plugin cannot take down or stall the server.
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// DefaultTimeout bounds each hook call.
+const DefaultTimeout = 5 * time.Second
+
+// ErrUnknown is returned for plugins that are not registered.
+var ErrUnknown = errors.New("plugins: unknown plugin")
+
+// Plugin is an extension. Init is called once at startup to register hooks.
+type Plugin interface {
+	Name() string
+	Description() string
+	Init(h *Host) error
+}
+
+// BeforeCreateReviewFunc inspects a review about to be created. Returning an
+// error rejects the review with that message.
+type BeforeCreateReviewFunc func(ctx context.Context, draft *models.Review) error
+
+// OnApproveFunc is told about approved reviews.
+type OnApproveFunc func(ctx context.Context, review *models.Review)
+
+// Host is what a plugin sees during Init.
+type Host struct {
+	p *registered
+}
+
+// BeforeCreateReview registers a hook run before a review is created.
+func (h *Host) BeforeCreateReview(fn BeforeCreateReviewFunc) {
+	h.p.beforeCreate = append(h.p.beforeCreate, fn)
+	h.p.hooks = append(h.p.hooks, "before-create-review")
+}
+
+// OnApprove registers a hook run after a review is approved.
+func (h *Host) OnApprove(fn OnApproveFunc) {
+	h.p.onApprove = append(h.p.onApprove, fn)
+	h.p.hooks = append(h.p.hooks, "on-approve")
+}
+
+// Route serves handler at /api/plugins/<name><path> for authenticated
+// callers. path must start with "/".
+func (h *Host) Route(method, path string, handler http.HandlerFunc) {
+	h.p.routes[method+" "+path] = handler
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]Plugin)
+)
+
+// Register makes a plugin available. It panics on duplicate names, since
+// that is a build mistake.
+func Register(p Plugin) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[p.Name()]; dup {
+		panic("plugins: duplicate plugin " + p.Name())
+	}
+	registry[p.Name()] = p
+}
+
+type registered struct {
+	plugin       Plugin
+	beforeCreate []BeforeCreateReviewFunc
+	onApprove    []OnApproveFunc
+	routes       map[string]http.HandlerFunc
+	hooks        []string
+
+	enabled   bool
+	failures  int
+	lastError string
+}
+
+// Status describes a plugin for the admin API.
+type Status struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Enabled     bool     `json:"enabled"`
+	Hooks       []string `json:"hooks"`
+	Routes      []string `json:"routes"`
+	Failures    int      `json:"failures"`
+	LastError   string   `json:"last_error,omitempty"`
+}
+
+// Manager runs the hooks of enabled plugins.
+type Manager struct {
+	st      *store.Memory
+	timeout time.Duration
+
+	mu      sync.RWMutex
+	plugins map[string]*registered
+}
+
+// NewManager initializes every registered plugin, enables those named in
+// enabled and subscribes the manager to approval events. A plugin whose
+// Init fails is left out and logged.
+func NewManager(st *store.Memory, d *notify.Dispatcher, enabled []string, timeout time.Duration) *Manager {
+	m := &Manager{st: st, timeout: timeout, plugins: make(map[string]*registered)}
+	d.Listen(m.handleEvent)
+	on := make(map[string]bool, len(enabled))
+	for _, name := range enabled {
+		on[strings.TrimSpace(name)] = true
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for name, p := range registry {
+		r := &registered{plugin: p, routes: make(map[string]http.HandlerFunc), enabled: on[name]}
+		if err := p.Init(&Host{p: r}); err != nil {
+			log.Printf("Plugin %s failed to initialize: %v", name, err)
+			continue
+		}
+		m.plugins[name] = r
+	}
+	for name := range on {
+		if name != "" && m.plugins[name] == nil {
+			log.Printf("Plugin %s is enabled but not available", name)
+		}
+	}
+	return m
+}
+
+// List returns the status of every available plugin, sorted by name.
+func (m *Manager) List() []Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	statuses := make([]Status, 0, len(m.plugins))
+	for name, r := range m.plugins {
+		s := Status{
+			Name:        name,
+			Description: r.plugin.Description(),
+			Enabled:     r.enabled,
+			Hooks:       append([]string{}, r.hooks...),
+			Routes:      []string{},
+			Failures:    r.failures,
+			LastError:   r.lastError,
+		}
+		for route := range r.routes {
+			s.Routes = append(s.Routes, route)
+		}
+		sort.Strings(s.Routes)
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// SetEnabled enables or disables a plugin.
+func (m *Manager) SetEnabled(name string, enabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.plugins[name]
+	if !ok {
+		return ErrUnknown
+	}
+	r.enabled = enabled
+	return nil
+}
+
+// active returns the enabled plugins, sorted by name so hooks run in a
+// stable order.
+func (m *Manager) active() []*registered {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*registered
+	for _, r := range m.plugins {
+		if r.enabled {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].plugin.Name() < out[j].plugin.Name() })
+	return out
+}
+
+// BeforeCreateReview runs the before-create hooks of enabled plugins. The
+// first rejection wins. Hooks that time out or panic are logged and
+// skipped, so a broken plugin does not block review creation.
+func (m *Manager) BeforeCreateReview(ctx context.Context, draft *models.Review) error {
+	for _, r := range m.active() {
+		for _, fn := range r.beforeCreate {
+			// Each hook gets its own copy: a hook that times out may still be
+			// running when the next one starts.
+			d := *draft
+			var rejection error
+			err := m.call(ctx, r, func(ctx context.Context) { rejection = fn(ctx, &d) })
+			if err != nil {
+				continue
+			}
+			if rejection != nil {
+				return fmt.Errorf("%s: %w", r.plugin.Name(), rejection)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Manager) handleEvent(e notify.Event) {
+	if e.Type != notify.ReviewApproved {
+		return
+	}
+	review, err := m.st.GetReview(e.ReviewID)
+	if err != nil {
+		return
+	}
+	// Hooks may be slow; never hold up the event relay.
+	go m.OnApprove(context.Background(), review)
+}
+
+// OnApprove runs the on-approve hooks of enabled plugins.
+func (m *Manager) OnApprove(ctx context.Context, review *models.Review) {
+	for _, r := range m.active() {
+		for _, fn := range r.onApprove {
+			m.call(ctx, r, func(ctx context.Context) { fn(ctx, review) })
+		}
+	}
+}
+
+// call runs fn with the hook timeout and recovers panics, recording any
+// failure against the plugin. fn keeps running in the background after a
+// timeout; its result is discarded.
+func (m *Manager) call(ctx context.Context, r *registered, fn func(ctx context.Context)) error {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("panic: %v", p)
+			}
+		}()
+		fn(ctx)
+		done <- nil
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", m.timeout)
+	}
+	if err != nil {
+		log.Printf("Plugin %s hook failed: %v", r.plugin.Name(), err)
+		m.mu.Lock()
+		r.failures++
+		r.lastError = err.Error()
+		m.mu.Unlock()
+	}
+	return err
+}
+
+// ServeHTTP dispatches /api/plugins/<name>/<path> to the plugin's route.
+// Routes of disabled plugins are not found. Handlers are bounded by the
+// hook timeout and isolated from panics.
+func (m *Manager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/plugins/")
+	name, path, _ := strings.Cut(rest, "/")
+
+	m.mu.RLock()
+	p, ok := m.plugins[name]
+	var handler http.HandlerFunc
+	if ok && p.enabled {
+		handler = p.routes[r.Method+" /"+path]
+	}
+	m.mu.RUnlock()
+	if handler == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	guarded := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				log.Printf("Plugin %s route panicked: %v", name, v)
+				m.mu.Lock()
+				p.failures++
+				p.lastError = fmt.Sprintf("panic: %v", v)
+				m.mu.Unlock()
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}()
+		handler(w, r)
+	})
+	http.TimeoutHandler(guarded, m.timeout, "Plugin timed out").ServeHTTP(w, r)
+}
@@ -0,0 +1,118 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	apnsProductionURL = "https://api.push.apple.com"
+	apnsSandboxURL    = "https://api.sandbox.push.apple.com"
+	// apnsTokenLifetime is how long a provider token is reused. Apple
+	// rejects tokens older than an hour and throttles refreshing more
+	// often than every 20 minutes.
+	apnsTokenLifetime = 40 * time.Minute
+)
+
+// APNs sends through the Apple Push Notification service with token-based
+// (.p8 key) authentication. The client must speak HTTP/2, which
+// net/http does by default over TLS.
+type APNs struct {
+	URL    string
+	HTTP   *http.Client
+	key    *ecdsa.PrivateKey
+	keyID  string
+	teamID string
+	topic  string
+
+	mu     sync.Mutex
+	token  string
+	signed time.Time
+}
+
+// NewAPNs creates a sender from a PEM-encoded .p8 signing key. topic is
+// the app's bundle ID; sandbox selects the development environment.
+func NewAPNs(p8 []byte, keyID, teamID, topic string, sandbox bool, client *http.Client) (*APNs, error) {
+	key, err := jwt.ParseECPrivateKeyFromPEM(p8)
+	if err != nil {
+		return nil, fmt.Errorf("parse APNs key: %w", err)
+	}
+	if keyID == "" || teamID == "" || topic == "" {
+		return nil, fmt.Errorf("APNs requires a key ID, team ID and topic")
+	}
+	url := apnsProductionURL
+	if sandbox {
+		url = apnsSandboxURL
+	}
+	return &APNs{URL: url, HTTP: client, key: key, keyID: keyID, teamID: teamID, topic: topic}, nil
+}
+
+// Send pushes msg to an APNs device token.
+func (a *APNs) Send(ctx context.Context, token string, msg Message) error {
+	auth, err := a.providerToken()
+	if err != nil {
+		return err
+	}
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{"title": msg.Title, "body": msg.Body},
+			"sound": "default",
+		},
+	}
+	for k, v := range msg.Data {
+		payload[k] = v
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.URL+"/3/device/"+token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+auth)
+	req.Header.Set("apns-topic", a.topic)
+	req.Header.Set("apns-push-type", "alert")
+	resp, err := a.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	var reason struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&reason)
+	if resp.StatusCode == http.StatusGone || reason.Reason == "BadDeviceToken" || reason.Reason == "Unregistered" {
+		return ErrUnregistered
+	}
+	return fmt.Errorf("apns returned %d: %s", resp.StatusCode, reason.Reason)
+}
+
+// providerToken returns the cached ES256 provider token, re-signing it
+// when it gets old.
+func (a *APNs) providerToken() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != "" && time.Since(a.signed) < apnsTokenLifetime {
+		return a.token, nil
+	}
+	t := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"iss": a.teamID, "iat": time.Now().Unix()})
+	t.Header["kid"] = a.keyID
+	signed, err := t.SignedString(a.key)
+	if err != nil {
+		return "", fmt.Errorf("sign APNs token: %w", err)
+	}
+	a.token, a.signed = signed, time.Now()
+	return a.token, nil
+}
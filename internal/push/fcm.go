@@ -0,0 +1,144 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	fcmSendURL = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	fcmScope   = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// serviceAccount is the part of a Google service account key file FCM
+// needs.
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// FCM sends through the Firebase Cloud Messaging HTTP v1 API, authorizing
+// with a service account.
+type FCM struct {
+	HTTP    *http.Client
+	account serviceAccount
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewFCM creates a sender from the JSON of a service account key.
+func NewFCM(credentials []byte, client *http.Client) (*FCM, error) {
+	var acct serviceAccount
+	if err := json.Unmarshal(credentials, &acct); err != nil {
+		return nil, fmt.Errorf("parse FCM credentials: %w", err)
+	}
+	if acct.ProjectID == "" || acct.ClientEmail == "" || acct.PrivateKey == "" || acct.TokenURI == "" {
+		return nil, fmt.Errorf("FCM credentials must include project_id, client_email, private_key and token_uri")
+	}
+	if _, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(acct.PrivateKey)); err != nil {
+		return nil, fmt.Errorf("parse FCM private key: %w", err)
+	}
+	return &FCM{HTTP: client, account: acct}, nil
+}
+
+// Send pushes msg to an FCM registration token.
+func (f *FCM) Send(ctx context.Context, token string, msg Message) error {
+	access, err := f.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token":        token,
+			"notification": map[string]string{"title": msg.Title, "body": msg.Body},
+			"data":         msg.Data,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(fcmSendURL, f.account.ProjectID), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+access)
+	resp, err := f.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode == http.StatusNotFound || bytes.Contains(detail, []byte("UNREGISTERED")) {
+		return ErrUnregistered
+	}
+	return fmt.Errorf("fcm returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+}
+
+// accessToken returns a cached OAuth2 access token, exchanging a signed
+// service account assertion for a new one shortly before it expires.
+func (f *FCM) accessToken(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.token != "" && time.Now().Before(f.expires.Add(-time.Minute)) {
+		return f.token, nil
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(f.account.PrivateKey))
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   f.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   f.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(key)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"}, "assertion": {assertion}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := f.HTTP.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetch FCM access token: %w", err)
+	}
+	defer resp.Body.Close()
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetch FCM access token: status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("decode FCM access token: %w", err)
+	}
+	f.token = tok.AccessToken
+	f.expires = now.Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return f.token, nil
+}
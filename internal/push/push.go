@@ -0,0 +1,127 @@
+// Package push delivers notifications to mobile devices through Firebase
+// Cloud Messaging and the Apple Push Notification service.
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// sendTimeout bounds one push so a slow provider cannot stall the
+// notification relay.
+const sendTimeout = 10 * time.Second
+
+// ErrUnregistered is returned when the provider reports that a device
+// token is no longer valid, e.g. because the app was uninstalled.
+var ErrUnregistered = errors.New("push: device token is no longer registered")
+
+// DefaultEvents are the notification types pushed to newly registered
+// devices.
+var DefaultEvents = []string{notify.ReviewAssigned, notify.ReviewApproved}
+
+// Message is a push notification.
+type Message struct {
+	Title string
+	Body  string
+	// Data is delivered to the app alongside the alert, e.g. to open the
+	// review the notification is about.
+	Data map[string]string
+}
+
+// Sender delivers messages to device tokens of one platform.
+type Sender interface {
+	Send(ctx context.Context, token string, msg Message) error
+}
+
+// pushTitles are the push titles of each event type, in English.
+var pushTitles = map[string]string{
+	notify.ReviewAssigned:  "Review %s was assigned to you",
+	notify.ReviewApproved:  "Review %s was approved",
+	notify.ReviewUpdated:   "Review %s was updated",
+	notify.ReviewReopened:  "Review %s was reopened",
+	notify.ReviewLabeled:   "Labels changed on review %s",
+	notify.CommentAdded:    "New comment on review %s",
+	notify.CommentResolved: "A comment thread was resolved on review %s",
+}
+
+// Gateway is a notification channel that pushes notifications to the
+// recipient's devices, honoring each device's preferences. Devices whose
+// tokens the provider rejects are unregistered.
+type Gateway struct {
+	st      *store.Memory
+	senders map[string]Sender
+}
+
+// NewGateway creates a gateway. senders maps platforms (models.PlatformFCM,
+// models.PlatformAPNs) to their sender; platforms without one are skipped.
+func NewGateway(st *store.Memory, senders map[string]Sender) *Gateway {
+	return &Gateway{st: st, senders: senders}
+}
+
+// Platforms returns the platforms devices can register for.
+func (g *Gateway) Platforms() []string {
+	var out []string
+	for p := range g.senders {
+		out = append(out, p)
+	}
+	return out
+}
+
+// Name identifies the channel's dead letters. A retry pushes to all of
+// the user's devices again.
+func (g *Gateway) Name() string {
+	return models.DeadLetterPush
+}
+
+// Deliver pushes the notification to the user's devices.
+func (g *Gateway) Deliver(n *models.Notification) error {
+	devices := g.st.ListDevices(n.UserID)
+	if len(devices) == 0 {
+		return nil
+	}
+	if k, disabled := g.st.KillSwitch(models.CapabilityPush, n.OrgID); disabled {
+		return k
+	}
+	lang := i18n.Negotiate(g.st.GetProfile(n.UserID).Locale, "")
+	title, ok := pushTitles[n.Type]
+	if !ok {
+		title = "Activity on review %s"
+	}
+	msg := Message{Title: i18n.T(lang, title, n.ReviewID), Data: map[string]string{"type": n.Type, "review_id": n.ReviewID}}
+	if review, err := g.st.GetReview(n.ReviewID); err == nil {
+		msg.Body = review.Title
+	}
+
+	var errs []string
+	for _, d := range devices {
+		sender, ok := g.senders[d.Platform]
+		if !ok || !d.Wants(n.Type) {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		err := sender.Send(ctx, d.Token, msg)
+		cancel()
+		switch {
+		case errors.Is(err, ErrUnregistered):
+			log.Printf("Removing push device %s of user %s: token no longer registered", d.ID, d.UserID)
+			g.st.ForgetDeviceToken(d.Platform, d.Token)
+		case err != nil:
+			errs = append(errs, fmt.Sprintf("%s: %v", d.ID, err))
+		default:
+			g.st.TouchDevice(d.ID, time.Now())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("push to %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
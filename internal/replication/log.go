@@ -0,0 +1,92 @@
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Mutation is one replicated change. Data holds the full record after the
+// change, so applying mutations in order reproduces the primary's state.
+type Mutation struct {
+	Seq       int64           `json:"seq"`
+	Origin    string          `json:"origin"`
+	Kind      string          `json:"kind"`
+	ID        string          `json:"id"`
+	Deleted   bool            `json:"deleted,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Log is a bounded in-memory sequence of mutations that readers can tail.
+type Log struct {
+	mu       sync.Mutex
+	capacity int
+	entries  []Mutation
+	next     int64
+	notify   chan struct{}
+}
+
+// NewLog creates a log that keeps the latest capacity mutations.
+func NewLog(capacity int) *Log {
+	return &Log{capacity: capacity, next: 1, notify: make(chan struct{})}
+}
+
+// Append assigns the next sequence number to m and stores it.
+func (l *Log) Append(m Mutation) Mutation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	m.Seq = l.next
+	l.next++
+	l.entries = append(l.entries, m)
+	if len(l.entries) > l.capacity {
+		l.entries = l.entries[len(l.entries)-l.capacity:]
+	}
+	close(l.notify)
+	l.notify = make(chan struct{})
+	return m
+}
+
+// Since returns up to max mutations after seq. gap is true if mutations
+// after seq have already been evicted, in which case the reader has missed
+// changes and must resynchronize.
+func (l *Log) Since(seq int64, max int) (out []Mutation, gap bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.entries) > 0 && l.entries[0].Seq > seq+1 {
+		gap = true
+	}
+	for _, m := range l.entries {
+		if m.Seq <= seq {
+			continue
+		}
+		out = append(out, m)
+		if len(out) == max {
+			break
+		}
+	}
+	return out, gap
+}
+
+// Wait blocks until a mutation after seq exists or ctx is done.
+func (l *Log) Wait(ctx context.Context, seq int64) {
+	l.mu.Lock()
+	if l.next-1 > seq {
+		l.mu.Unlock()
+		return
+	}
+	ch := l.notify
+	l.mu.Unlock()
+	select {
+	case <-ch:
+	case <-ctx.Done():
+	}
+}
+
+// LastSeq returns the sequence number of the latest mutation.
+func (l *Log) LastSeq() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.next - 1
+}
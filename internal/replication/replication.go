@@ -0,0 +1,324 @@
+// Package replication copies review, organization and policy changes from a
+// primary instance to read-only replicas in other regions (active/passive).
+//
+// The primary records every change in a log that replicas tail over HTTP.
+// Replicas apply changes in order; if a record was also modified locally
+// since it was last replicated, the conflict is recorded and the primary's
+// version wins.
+package replication
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Replication modes.
+const (
+	ModePrimary = "primary"
+	ModeReplica = "replica"
+)
+
+const (
+	logCapacity = 10000
+	batchSize   = 500
+	pollWait    = 25 * time.Second
+)
+
+// Conflict records a replicated change that overwrote a local modification.
+type Conflict struct {
+	Kind       string    `json:"kind"`
+	ID         string    `json:"id"`
+	Seq        int64     `json:"seq"`
+	Origin     string    `json:"origin"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// Status describes the replication state of this instance.
+type Status struct {
+	Mode       string     `json:"mode"`
+	InstanceID string     `json:"instance_id"`
+	LastSeq    int64      `json:"last_seq"`
+	LastSyncAt *time.Time `json:"last_sync_at,omitempty"`
+	Gap        bool       `json:"gap,omitempty"`
+	Conflicts  []Conflict `json:"conflicts"`
+}
+
+// Primary publishes local changes for replicas to tail.
+type Primary struct {
+	st         *store.Memory
+	instanceID string
+	log        *Log
+
+	mu      sync.Mutex
+	pending []store.Change
+	wake    chan struct{}
+}
+
+// NewPrimary creates a primary and subscribes it to store changes. Call Run
+// to start publishing.
+func NewPrimary(st *store.Memory, instanceID string) *Primary {
+	p := &Primary{st: st, instanceID: instanceID, log: NewLog(logCapacity), wake: make(chan struct{}, 1)}
+	st.OnChange(p.enqueue)
+	return p
+}
+
+// Run turns queued store changes into log entries until ctx is cancelled.
+func (p *Primary) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.wake:
+		}
+		p.mu.Lock()
+		changes := p.pending
+		p.pending = nil
+		p.mu.Unlock()
+
+		for _, c := range changes {
+			m, err := snapshot(p.st, c)
+			if err != nil {
+				log.Printf("Replication snapshot of %s %s failed: %v", c.Kind, c.ID, err)
+				continue
+			}
+			m.Origin = p.instanceID
+			m.CreatedAt = time.Now().UTC()
+			p.log.Append(m)
+		}
+	}
+}
+
+// enqueue runs under the store lock, so it only queues the change.
+func (p *Primary) enqueue(c store.Change) {
+	p.mu.Lock()
+	p.pending = append(p.pending, c)
+	p.mu.Unlock()
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Status returns the primary's replication state.
+func (p *Primary) Status() Status {
+	return Status{Mode: ModePrimary, InstanceID: p.instanceID, LastSeq: p.log.LastSeq(), Conflicts: []Conflict{}}
+}
+
+// StreamHandler serves GET ?after=<seq>, long-polling until mutations after
+// seq exist. Requests must carry "Authorization: Bearer <token>".
+func (p *Primary) StreamHandler(token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		given := []byte(r.Header.Get("Authorization"))
+		if token == "" || subtle.ConstantTimeCompare(given, []byte("Bearer "+token)) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		after, _ := strconv.ParseInt(r.URL.Query().Get("after"), 10, 64)
+
+		ctx, cancel := context.WithTimeout(r.Context(), pollWait)
+		defer cancel()
+		p.log.Wait(ctx, after)
+
+		batch, gap := p.log.Since(after, batchSize)
+		if batch == nil {
+			batch = []Mutation{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Mutations []Mutation `json:"mutations"`
+			Gap       bool       `json:"gap"`
+		}{batch, gap})
+	}
+}
+
+func snapshot(st *store.Memory, c store.Change) (Mutation, error) {
+	m := Mutation{Kind: c.Kind, ID: c.ID, Deleted: c.Deleted}
+	if c.Deleted {
+		return m, nil
+	}
+	var record interface{}
+	var err error
+	switch c.Kind {
+	case store.KindReview:
+		record, err = st.GetReview(c.ID)
+	case store.KindOrg:
+		record, err = st.GetManagedOrg(c.ID)
+	case store.KindPolicy:
+		record = st.OwnPolicy(c.ID)
+	default:
+		return m, fmt.Errorf("unknown kind %q", c.Kind)
+	}
+	if err != nil {
+		return m, err
+	}
+	m.Data, err = json.Marshal(record)
+	return m, err
+}
+
+// Replica tails a primary's log and applies its mutations locally.
+type Replica struct {
+	st         *store.Memory
+	instanceID string
+	primaryURL string
+	token      string
+	http       *http.Client
+
+	mu        sync.Mutex
+	lastSeq   int64
+	lastSync  *time.Time
+	gap       bool
+	dirty     map[string]bool
+	conflicts []Conflict
+}
+
+// NewReplica creates a replica of the primary at primaryURL and subscribes
+// it to local store changes for conflict detection.
+func NewReplica(st *store.Memory, instanceID, primaryURL, token string) *Replica {
+	r := &Replica{
+		st:         st,
+		instanceID: instanceID,
+		primaryURL: primaryURL,
+		token:      token,
+		http:       &http.Client{Timeout: pollWait + 10*time.Second},
+		dirty:      make(map[string]bool),
+	}
+	// Replicated writes bypass listeners, so anything seen here is local.
+	st.OnChange(func(c store.Change) {
+		r.mu.Lock()
+		r.dirty[c.Kind+"/"+c.ID] = true
+		r.mu.Unlock()
+	})
+	return r
+}
+
+// Run tails the primary until ctx is cancelled, backing off after errors.
+func (r *Replica) Run(ctx context.Context) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		if err := r.poll(ctx); err != nil {
+			log.Printf("Replication from %s failed: %v", r.primaryURL, err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(backoff):
+			}
+			if backoff < time.Minute {
+				backoff *= 2
+			}
+			continue
+		}
+		backoff = time.Second
+	}
+}
+
+func (r *Replica) poll(ctx context.Context) error {
+	r.mu.Lock()
+	after := r.lastSeq
+	r.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.primaryURL+"?after="+url.QueryEscape(strconv.FormatInt(after, 10)), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.token)
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("primary returned status %d", resp.StatusCode)
+	}
+
+	var batch struct {
+		Mutations []Mutation `json:"mutations"`
+		Gap       bool       `json:"gap"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return err
+	}
+	if batch.Gap {
+		log.Printf("Replica fell behind the primary's log after seq %d; some changes were missed", after)
+	}
+	for _, m := range batch.Mutations {
+		if err := r.apply(m); err != nil {
+			return fmt.Errorf("applying seq %d: %w", m.Seq, err)
+		}
+	}
+
+	now := time.Now().UTC()
+	r.mu.Lock()
+	r.lastSync = &now
+	r.gap = r.gap || batch.Gap
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *Replica) apply(m Mutation) error {
+	key := m.Kind + "/" + m.ID
+	r.mu.Lock()
+	if r.dirty[key] {
+		r.conflicts = append(r.conflicts, Conflict{Kind: m.Kind, ID: m.ID, Seq: m.Seq, Origin: m.Origin, DetectedAt: time.Now().UTC()})
+		delete(r.dirty, key)
+		log.Printf("Replication conflict on %s: local change overwritten by seq %d from %s", key, m.Seq, m.Origin)
+	}
+	r.mu.Unlock()
+
+	switch m.Kind {
+	case store.KindReview:
+		var review models.Review
+		if err := json.Unmarshal(m.Data, &review); err != nil {
+			return err
+		}
+		if err := r.st.PutReplicatedReview(&review); err != nil {
+			return err
+		}
+	case store.KindOrg:
+		if m.Deleted {
+			r.st.DeleteReplicatedOrg(m.ID)
+			break
+		}
+		var org models.ManagedOrg
+		if err := json.Unmarshal(m.Data, &org); err != nil {
+			return err
+		}
+		r.st.PutReplicatedOrg(&org)
+	case store.KindPolicy:
+		var policy models.OrgPolicy
+		if err := json.Unmarshal(m.Data, &policy); err != nil {
+			return err
+		}
+		r.st.PutReplicatedPolicy(policy)
+	default:
+		log.Printf("Skipping replicated mutation of unknown kind %q", m.Kind)
+	}
+
+	r.mu.Lock()
+	r.lastSeq = m.Seq
+	r.mu.Unlock()
+	return nil
+}
+
+// Status returns the replica's replication state.
+func (r *Replica) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Status{
+		Mode:       ModeReplica,
+		InstanceID: r.instanceID,
+		LastSeq:    r.lastSeq,
+		LastSyncAt: r.lastSync,
+		Gap:        r.gap,
+		Conflicts:  append([]Conflict{}, r.conflicts...),
+	}
+}
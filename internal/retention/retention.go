@@ -0,0 +1,88 @@
+// Package retention applies organizations' retention policies on
+// schedule: it closes idle reviews, purges soft-deleted items and
+// truncates old audit events, keeping whatever is under a legal hold.
+package retention
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Actor is the ActorID of the audit events retention runs record.
+const Actor = "retention"
+
+// Runner applies retention policies periodically.
+type Runner struct {
+	st       *store.Memory
+	interval time.Duration
+}
+
+// New creates a runner that applies every enabled policy each interval.
+func New(st *store.Memory, interval time.Duration) *Runner {
+	return &Runner{st: st, interval: interval}
+}
+
+// Run applies retention policies every interval until ctx is cancelled.
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.Check(now)
+		}
+	}
+}
+
+// Check applies every enabled retention policy as of now.
+func (r *Runner) Check(now time.Time) {
+	for _, p := range r.st.ListRetentionPolicies() {
+		Apply(r.st, p, now)
+	}
+}
+
+// Preview returns what applying p as of now would do, without doing it.
+func Preview(st *store.Memory, p models.RetentionPolicy, now time.Time) models.RetentionRun {
+	run := models.RetentionRun{DryRun: true, At: now}
+	st.ApplyRetention(p, &run)
+	return run
+}
+
+// Apply applies p as of now and audits what it did.
+func Apply(st *store.Memory, p models.RetentionPolicy, now time.Time) models.RetentionRun {
+	run := models.RetentionRun{At: now}
+	st.ApplyRetention(p, &run)
+	for _, id := range run.ClosedReviews {
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      p.OrgID,
+			ActorID:    Actor,
+			Action:     "review.closed",
+			TargetType: "review",
+			TargetID:   id,
+			Reason:     "idle for " + strconv.Itoa(p.CloseIdleDays) + " days",
+		})
+	}
+	if len(run.ClosedReviews)+len(run.PurgedMerges)+len(run.PurgedShareLinks)+run.AuditEvents > 0 {
+		st.AppendAudit(&models.AuditEvent{
+			OrgID:      p.OrgID,
+			ActorID:    Actor,
+			Action:     "retention.applied",
+			TargetType: "org",
+			TargetID:   p.OrgID,
+			Metadata: map[string]string{
+				"closed_reviews":     strconv.Itoa(len(run.ClosedReviews)),
+				"purged_merges":      strconv.Itoa(len(run.PurgedMerges)),
+				"purged_share_links": strconv.Itoa(len(run.PurgedShareLinks)),
+				"audit_events":       strconv.Itoa(run.AuditEvents),
+				"held":               strconv.Itoa(run.Held),
+			},
+		})
+	}
+	return run
+}
@@ -0,0 +1,160 @@
+// Package risk scores how risky a review is to approve, from the size of
+// its diff, the sensitive paths it touches, its author's history and its
+// labels, as the organization's risk policy weighs them.
+package risk
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// maxScore caps risk scores.
+const maxScore = 100
+
+// Diff is what a review's unified diff changes.
+type Diff struct {
+	Files   []string
+	Added   int
+	Removed int
+}
+
+// Lines returns the number of lines the diff adds or removes.
+func (d Diff) Lines() int {
+	return d.Added + d.Removed
+}
+
+// ParseDiff reads the unified diff in a review's content. Content that
+// holds no diff counts as that many added lines and touches no files.
+func ParseDiff(content string) Diff {
+	var d Diff
+	inDiff := false
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			inDiff = true
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			// A file header; removed lines starting with "-- " are not
+			// followed by a +++ line
+			inDiff = true
+			for _, name := range []string{diffPath(line[4:]), diffPath(lines[i+1][4:])} {
+				if name != "" && !slices.Contains(d.Files, name) {
+					d.Files = append(d.Files, name)
+				}
+			}
+			i++
+		case !inDiff:
+		case strings.HasPrefix(line, "+"):
+			d.Added++
+		case strings.HasPrefix(line, "-"):
+			d.Removed++
+		}
+	}
+	if !inDiff {
+		d.Added = 0
+		for _, line := range lines {
+			if strings.TrimSpace(line) != "" {
+				d.Added++
+			}
+		}
+	}
+	return d
+}
+
+// diffPath returns the path named by a ---/+++ header, without its a/ or
+// b/ prefix; empty for /dev/null.
+func diffPath(name string) string {
+	name, _, _ = strings.Cut(name, "\t")
+	name = strings.TrimSpace(name)
+	if name == "/dev/null" {
+		return ""
+	}
+	if rest, ok := strings.CutPrefix(name, "a/"); ok {
+		return rest
+	}
+	if rest, ok := strings.CutPrefix(name, "b/"); ok {
+		return rest
+	}
+	return name
+}
+
+// MatchPath reports whether a file path matches a sensitive path pattern.
+func MatchPath(pattern, name string) bool {
+	if dir, ok := strings.CutSuffix(pattern, "/"); ok {
+		return strings.HasPrefix(name, dir+"/") || strings.Contains(name, "/"+dir+"/")
+	}
+	if ok, _ := path.Match(pattern, name); ok {
+		return true
+	}
+	ok, _ := path.Match(pattern, path.Base(name))
+	return ok
+}
+
+// Author is what is known of a review author's track record.
+type Author struct {
+	// Approved counts their approved reviews.
+	Approved int
+	// Reopened counts the times their reviews were reopened after approval.
+	Reopened int
+}
+
+// Score scores a review with the given labels, diff and author under a
+// risk policy.
+func Score(p models.RiskPolicy, diff Diff, author Author, labels []string) models.RiskScore {
+	s := models.RiskScore{Factors: []models.RiskFactor{}}
+	add := func(kind, detail string, points int) {
+		if points > 0 {
+			s.Factors = append(s.Factors, models.RiskFactor{Kind: kind, Detail: detail, Points: points})
+			s.Score += points
+		}
+	}
+
+	if n := diff.Lines(); n > 0 && p.LargeChangeLines > 0 {
+		add(models.RiskFactorSize, fmt.Sprintf("%d changed lines", n), p.SizePoints*min(n, p.LargeChangeLines)/p.LargeChangeLines)
+	}
+	for _, sp := range p.SensitivePaths {
+		for _, f := range diff.Files {
+			if MatchPath(sp.Pattern, f) {
+				add(models.RiskFactorSensitivePath, fmt.Sprintf("%s matches %s", f, sp.Pattern), sp.Points)
+				break
+			}
+		}
+	}
+	if author.Approved < p.NewAuthorApprovals {
+		add(models.RiskFactorNewAuthor, fmt.Sprintf("author has %d approved reviews", author.Approved), p.NewAuthorPoints)
+	}
+	if author.Reopened > 0 {
+		points := author.Reopened * p.ReopenedPoints
+		if p.MaxReopenedPoints > 0 {
+			points = min(points, p.MaxReopenedPoints)
+		}
+		add(models.RiskFactorReopened, fmt.Sprintf("author's reviews were reopened %d times", author.Reopened), points)
+	}
+	for _, l := range labels {
+		add(models.RiskFactorLabel, "labeled "+l, p.LabelPoints[l])
+	}
+
+	s.Score = min(s.Score, maxScore)
+	switch {
+	case s.Score >= p.HighScore && p.HighScore > 0:
+		s.Level = models.RiskHigh
+	case s.Score >= p.MediumScore && p.MediumScore > 0:
+		s.Level = models.RiskMedium
+	default:
+		s.Level = models.RiskLow
+	}
+	s.RequiredApprovals = max(p.RequiredApprovals[s.Level], 1)
+	return s
+}
+
+// Review scores a stored review under its organization's risk policy.
+func Review(st *store.Memory, review *models.Review) models.RiskScore {
+	approved, reopened := st.AuthorHistory(review.OrgID, review.AuthorID)
+	return Score(st.GetRiskPolicy(review.OrgID), ParseDiff(review.Content), Author{Approved: approved, Reopened: reopened}, st.GetLabels(review.ID))
+}
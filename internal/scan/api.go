@@ -0,0 +1,58 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// API scans with an external scanning service. The content is POSTed as
+// the request body and the service answers with
+// {"infected": true, "signature": "..."}.
+type API struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+// NewAPI creates a scanner for the service at url, sending token as a bearer
+// token when not empty.
+func NewAPI(client *http.Client, url, token string) *API {
+	return &API{client: client, url: url, token: token}
+}
+
+// Name implements Scanner.
+func (a *API) Name() string {
+	return "api"
+}
+
+// Scan implements Scanner.
+func (a *API) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, r)
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Accept", "application/json")
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("scanning API: %s", resp.Status)
+	}
+	var verdict struct {
+		Infected  bool   `json:"infected"`
+		Signature string `json:"signature"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&verdict); err != nil {
+		return Result{}, fmt.Errorf("decode scanning API response: %w", err)
+	}
+	return Result{Infected: verdict.Infected, Signature: verdict.Signature}, nil
+}
@@ -0,0 +1,92 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// chunkSize is the size of the chunks streamed to clamd.
+const chunkSize = 32 << 10
+
+// ClamAV scans with a ClamAV daemon (clamd) over its INSTREAM command.
+type ClamAV struct {
+	network string
+	addr    string
+}
+
+// NewClamAV creates a scanner for the clamd listening at addr, either
+// host:port or the path of a Unix socket.
+func NewClamAV(addr string) *ClamAV {
+	if strings.HasPrefix(addr, "/") {
+		return &ClamAV{network: "unix", addr: addr}
+	}
+	return &ClamAV{network: "tcp", addr: addr}
+}
+
+// Name implements Scanner.
+func (c *ClamAV) Name() string {
+	return "clamav"
+}
+
+// Scan implements Scanner.
+func (c *ClamAV) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, c.network, c.addr)
+	if err != nil {
+		return Result{}, fmt.Errorf("connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// Requests are null-terminated (the z prefix); content follows as
+	// length-prefixed chunks ended by a zero length.
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return Result{}, fmt.Errorf("write to clamd: %w", err)
+	}
+	buf := make([]byte, 4+chunkSize)
+	for {
+		n, rerr := r.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf, uint32(n))
+			if _, err := conn.Write(buf[:4+n]); err != nil {
+				return Result{}, fmt.Errorf("write to clamd: %w", err)
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return Result{}, rerr
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return Result{}, fmt.Errorf("write to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return Result{}, fmt.Errorf("read from clamd: %w", err)
+	}
+	return parseClamReply(strings.TrimRight(reply, "\x00"))
+}
+
+// parseClamReply reads a clamd verdict such as "stream: OK" or
+// "stream: Win.Test.EICAR_HDB-1 FOUND".
+func parseClamReply(reply string) (Result, error) {
+	verdict := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case verdict == "OK":
+		return Result{}, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		return Result{Infected: true, Signature: strings.TrimSuffix(verdict, " FOUND")}, nil
+	default:
+		return Result{}, fmt.Errorf("clamd: %s", verdict)
+	}
+}
@@ -0,0 +1,182 @@
+// Package scan checks uploaded attachments for malware with a pluggable
+// scanner, a ClamAV daemon or an external scanning API. Attachments are
+// scanned in the background after upload; infected ones are moved to
+// quarantine and their uploader is notified.
+package scan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/blob"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+const (
+	// scanTimeout bounds one scan so a stuck scanner cannot stall the queue.
+	scanTimeout = 2 * time.Minute
+	// maxAttempts is how many times a scan is tried before the attachment
+	// is marked failed.
+	maxAttempts = 3
+	// retryInterval is how often pending attachments are picked up again,
+	// e.g. after a scanner outage or a restart.
+	retryInterval = 30 * time.Second
+	// quarantinePrefix is where the content of infected attachments is
+	// moved, out of reach of the download endpoint.
+	quarantinePrefix = "quarantine/"
+)
+
+// AttachmentQuarantined is the notification type sent to the uploader of an
+// infected attachment.
+const AttachmentQuarantined = "attachment.quarantined"
+
+// Result is the verdict of a scan.
+type Result struct {
+	Infected bool
+	// Signature names the malware found, e.g. Win.Test.EICAR_HDB-1.
+	Signature string
+}
+
+// Scanner scans content for malware.
+type Scanner interface {
+	// Name identifies the engine in scan results.
+	Name() string
+	Scan(ctx context.Context, r io.Reader) (Result, error)
+}
+
+// Service scans pending attachments in the background.
+type Service struct {
+	st      *store.Memory
+	blobs   blob.Store
+	scanner Scanner
+	wake    chan struct{}
+}
+
+// NewService creates a service that scans attachments stored in blobs with
+// scanner. A nil scanner disables scanning: attachments are then stored
+// unscanned.
+func NewService(st *store.Memory, blobs blob.Store, scanner Scanner) *Service {
+	return &Service{st: st, blobs: blobs, scanner: scanner, wake: make(chan struct{}, 1)}
+}
+
+// Enabled reports whether a scanner is configured.
+func (s *Service) Enabled() bool {
+	return s.scanner != nil
+}
+
+// Submit asks Run to scan pending attachments now rather than at the next
+// retry.
+func (s *Service) Submit() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run scans pending attachments until ctx is cancelled.
+func (s *Service) Run(ctx context.Context) {
+	if s.scanner == nil {
+		return
+	}
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+	for {
+		for _, a := range s.st.AttachmentsByScanStatus(models.ScanPending) {
+			if ctx.Err() != nil {
+				return
+			}
+			s.scan(ctx, a)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan scans one attachment and records the outcome. Errors leave the
+// attachment pending for another attempt until maxAttempts is reached.
+func (s *Service) scan(ctx context.Context, a models.Attachment) {
+	result, err := s.check(ctx, a)
+	now := time.Now()
+	state := models.AttachmentScan{Status: models.ScanClean, Engine: s.scanner.Name(), Attempts: a.Scan.Attempts + 1, ScannedAt: &now}
+	blobKey := ""
+	switch {
+	case err != nil:
+		log.Printf("Failed to scan attachment %s (attempt %d): %v", a.ID, state.Attempts, err)
+		state.Status, state.Error, state.ScannedAt = models.ScanPending, err.Error(), nil
+		if state.Attempts >= maxAttempts {
+			state.Status = models.ScanFailed
+		}
+	case result.Infected:
+		state.Status, state.Signature = models.ScanInfected, result.Signature
+		if blobKey, err = s.quarantine(a); err != nil {
+			// The infected content stays where it is, but the status
+			// already keeps it from being downloaded.
+			log.Printf("Failed to quarantine attachment %s: %v", a.ID, err)
+			blobKey = ""
+		}
+	}
+	if _, err := s.st.SetAttachmentScan(a.ID, state, blobKey); err != nil {
+		// Deleted while it was being scanned
+		return
+	}
+	if state.Status == models.ScanInfected {
+		s.report(a, state, now)
+	}
+}
+
+func (s *Service) check(ctx context.Context, a models.Attachment) (Result, error) {
+	rc, _, err := s.blobs.Get(a.BlobKey)
+	if err != nil {
+		return Result{}, fmt.Errorf("read attachment: %w", err)
+	}
+	defer rc.Close()
+	ctx, cancel := context.WithTimeout(ctx, scanTimeout)
+	defer cancel()
+	return s.scanner.Scan(ctx, rc)
+}
+
+// quarantine moves an attachment's content below quarantinePrefix and
+// returns its new key.
+func (s *Service) quarantine(a models.Attachment) (string, error) {
+	rc, contentType, err := s.blobs.Get(a.BlobKey)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	key := quarantinePrefix + a.BlobKey
+	if err := s.blobs.Put(key, contentType, rc); err != nil {
+		return "", err
+	}
+	if err := s.blobs.Delete(a.BlobKey); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// report notifies the uploader of an infected attachment and audits it.
+func (s *Service) report(a models.Attachment, state models.AttachmentScan, now time.Time) {
+	s.st.AddNotification(&models.Notification{
+		UserID:    a.UploadedBy,
+		OrgID:     a.OrgID,
+		Type:      AttachmentQuarantined,
+		ReviewID:  a.ReviewID,
+		ActorID:   "system",
+		CreatedAt: now,
+	})
+	s.st.AppendAudit(&models.AuditEvent{
+		OrgID:      a.OrgID,
+		ActorID:    "system",
+		Action:     "attachment.quarantined",
+		TargetType: "attachment",
+		TargetID:   a.ID,
+		Metadata:   map[string]string{"review_id": a.ReviewID, "engine": state.Engine, "signature": state.Signature},
+	})
+}
@@ -0,0 +1,157 @@
+// Package secrets finds credentials such as cloud access keys, API tokens
+// and private keys in text, with built-in rules and rules organizations
+// add themselves.
+package secrets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// BuiltinRules are checked for every organization unless disabled.
+var BuiltinRules = []models.SecretRule{
+	{ID: "aws-access-key", Description: "AWS access key ID", Pattern: `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+	{ID: "aws-secret-key", Description: "AWS secret access key", Pattern: `(?i)aws.{0,20}?(?:secret|key).{0,20}?[:=]\s*["']?([A-Za-z0-9/+]{40})\b`, MinEntropy: 4},
+	{ID: "github-token", Description: "GitHub token", Pattern: `\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{40,})\b`},
+	{ID: "gitlab-token", Description: "GitLab personal access token", Pattern: `\bglpat-[A-Za-z0-9_-]{20,}\b`},
+	{ID: "slack-token", Description: "Slack token", Pattern: `\bxox[abposr]-[A-Za-z0-9-]{10,}\b`},
+	{ID: "stripe-key", Description: "Stripe secret key", Pattern: `\b[sr]k_live_[A-Za-z0-9]{24,}\b`},
+	{ID: "google-api-key", Description: "Google API key", Pattern: `\bAIza[0-9A-Za-z_-]{35}\b`},
+	{ID: "private-key", Description: "Private key", Pattern: `-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----`},
+	{ID: "jwt", Description: "JSON Web Token", Pattern: `\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`},
+	{ID: "generic-secret", Description: "Secret assigned in configuration", Pattern: `(?i)(?:api[_-]?key|secret|token|passw(?:or)?d)["']?\s*[:=]\s*["']?([A-Za-z0-9/+_.=-]{16,})`, MinEntropy: 3.5},
+}
+
+type rule struct {
+	models.SecretRule
+	re *regexp.Regexp
+}
+
+// Detector checks text against a set of rules.
+type Detector struct {
+	rules []rule
+}
+
+// Compile checks that a rule's pattern is a valid regular expression.
+func Compile(r models.SecretRule) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", r.ID, err)
+	}
+	return re, nil
+}
+
+// New creates a detector for an organization's policy: the built-in rules
+// it did not disable, then its own.
+func New(policy models.SecretPolicy) (*Detector, error) {
+	d := &Detector{}
+	for _, r := range BuiltinRules {
+		if slices.Contains(policy.DisabledRules, r.ID) {
+			continue
+		}
+		d.rules = append(d.rules, rule{SecretRule: r, re: regexp.MustCompile(r.Pattern)})
+	}
+	for _, r := range policy.Rules {
+		re, err := Compile(r)
+		if err != nil {
+			return nil, err
+		}
+		d.rules = append(d.rules, rule{SecretRule: r, re: re})
+	}
+	return d, nil
+}
+
+// Match is where a rule matched in a text.
+type Match struct {
+	RuleID      string
+	Description string
+	// Start and End delimit the secret: the rule's first capture group,
+	// or the whole match without one.
+	Start, End int
+}
+
+// Matches returns every match of the rules in text, rule by rule.
+func (d *Detector) Matches(text string) []Match {
+	var matches []Match
+	for _, r := range d.rules {
+		for _, loc := range r.re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			if r.MinEntropy > 0 && Entropy(text[start:end]) < r.MinEntropy {
+				continue
+			}
+			matches = append(matches, Match{RuleID: r.ID, Description: r.Description, Start: start, End: end})
+		}
+	}
+	return matches
+}
+
+// Scan returns the secrets found in text, attributed to source.
+func (d *Detector) Scan(source, text string) []models.SecretFinding {
+	var findings []models.SecretFinding
+	seen := make(map[string]bool)
+	now := time.Now()
+	for _, m := range d.Matches(text) {
+		secret := text[m.Start:m.End]
+		fingerprint := Fingerprint(secret)
+		if seen[m.RuleID+fingerprint] {
+			continue
+		}
+		seen[m.RuleID+fingerprint] = true
+		findings = append(findings, models.SecretFinding{
+			RuleID:      m.RuleID,
+			Description: m.Description,
+			Source:      source,
+			Line:        strings.Count(text[:m.Start], "\n") + 1,
+			Preview:     Mask(secret),
+			Fingerprint: fingerprint,
+			FoundAt:     now,
+		})
+	}
+	return findings
+}
+
+// Entropy returns the Shannon entropy of s in bits per character.
+func Entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	n := 0
+	for _, c := range s {
+		counts[c]++
+		n++
+	}
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// Mask keeps the first four characters of a secret so it can be recognized
+// and hides the rest.
+func Mask(secret string) string {
+	runes := []rune(secret)
+	if len(runes) <= 8 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:4]) + strings.Repeat("*", len(runes)-4)
+}
+
+// Fingerprint identifies a secret without revealing it.
+func Fingerprint(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:8])
+}
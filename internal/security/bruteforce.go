@@ -0,0 +1,101 @@
+package security
+
+import (
+	"sync"
+	"time"
+)
+
+// LoginGuard counts failed logins per client IP. Once an IP reaches the
+// threshold within the window, further attempts must pass a challenge, or
+// are refused until the window passes if no challenger is configured.
+// Failures are kept per account the IP tried, so a successful login only
+// forgives the failures against its own account: signing in to an account
+// the attacker holds does not reset the count against the ones they guess.
+type LoginGuard struct {
+	challenger Challenger
+	siteKey    string
+	threshold  int
+	window     time.Duration
+
+	mu       sync.Mutex
+	failures map[string]*failures
+}
+
+type failures struct {
+	count    int
+	first    time.Time
+	accounts map[string]int
+}
+
+// NewLoginGuard creates a guard. challenger may be nil.
+func NewLoginGuard(challenger Challenger, siteKey string, threshold int, window time.Duration) *LoginGuard {
+	return &LoginGuard{
+		challenger: challenger,
+		siteKey:    siteKey,
+		threshold:  threshold,
+		window:     window,
+		failures:   make(map[string]*failures),
+	}
+}
+
+// Challenger returns the configured challenger and the public site key the
+// client renders it with.
+func (g *LoginGuard) Challenger() (Challenger, string) {
+	return g.challenger, g.siteKey
+}
+
+// Blocked reports whether ip has failed too often, and when its failures
+// expire.
+func (g *LoginGuard) Blocked(ip string) (bool, time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	f, ok := g.failures[ip]
+	if !ok {
+		return false, time.Time{}
+	}
+	expires := f.first.Add(g.window)
+	if time.Now().After(expires) {
+		delete(g.failures, ip)
+		return false, time.Time{}
+	}
+	return f.count >= g.threshold, expires
+}
+
+// Failed records a failed login from ip to account.
+func (g *LoginGuard) Failed(ip, account string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Now()
+	f, ok := g.failures[ip]
+	if !ok || now.After(f.first.Add(g.window)) {
+		f = &failures{first: now, accounts: make(map[string]int)}
+		g.failures[ip] = f
+	}
+	f.count++
+	f.accounts[account]++
+
+	// Keep the map from growing without bound under a spray of addresses.
+	if len(g.failures) > 100000 {
+		for k, v := range g.failures {
+			if now.After(v.first.Add(g.window)) {
+				delete(g.failures, k)
+			}
+		}
+	}
+}
+
+// Succeeded clears the failures of ip against account. Its failures
+// against other accounts still count.
+func (g *LoginGuard) Succeeded(ip, account string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	f, ok := g.failures[ip]
+	if !ok {
+		return
+	}
+	f.count -= f.accounts[account]
+	delete(f.accounts, account)
+	if f.count == 0 {
+		delete(g.failures, ip)
+	}
+}
@@ -0,0 +1,78 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Challenger verifies a token produced by a client-side challenge widget.
+type Challenger interface {
+	// Provider names the widget the client must render, e.g. "turnstile".
+	Provider() string
+	Verify(ctx context.Context, token, remoteIP string) (bool, error)
+}
+
+// Siteverify endpoints of the supported providers.
+const (
+	HCaptchaVerifyURL  = "https://api.hcaptcha.com/siteverify"
+	TurnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+)
+
+// SiteVerify checks tokens against an hCaptcha or Turnstile compatible
+// siteverify endpoint.
+type SiteVerify struct {
+	name     string
+	endpoint string
+	secret   string
+	http     *http.Client
+}
+
+// ChallengerFor returns the challenger for provider ("hcaptcha" or
+// "turnstile"), calling it through httpClient.
+func ChallengerFor(provider, secret string, httpClient *http.Client) (*SiteVerify, error) {
+	s := &SiteVerify{name: provider, secret: secret, http: httpClient}
+	switch provider {
+	case "hcaptcha":
+		s.endpoint = HCaptchaVerifyURL
+	case "turnstile":
+		s.endpoint = TurnstileVerifyURL
+	default:
+		return nil, fmt.Errorf("unknown challenge provider %q", provider)
+	}
+	return s, nil
+}
+
+// Provider implements Challenger.
+func (s *SiteVerify) Provider() string { return s.name }
+
+// Verify implements Challenger.
+func (s *SiteVerify) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	form := url.Values{"secret": {s.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("%s siteverify: unexpected status %d", s.name, resp.StatusCode)
+	}
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+	return result.Success, nil
+}
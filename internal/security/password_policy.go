@@ -0,0 +1,123 @@
+package security
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// MinPasswordLength is the shortest password any organization accepts.
+const MinPasswordLength = 8
+
+// PwnedPasswordsURL is the Have I Been Pwned range endpoint.
+const PwnedPasswordsURL = "https://api.pwnedpasswords.com/range/"
+
+// BreachChecker reports whether a password appears in known breaches.
+type BreachChecker interface {
+	Breached(ctx context.Context, password string) (bool, error)
+}
+
+// PwnedPasswords checks passwords against Have I Been Pwned using its
+// k-anonymity API: only the first five hex digits of the password's SHA-1
+// leave the server, and the match is made locally among the returned
+// suffixes.
+type PwnedPasswords struct {
+	endpoint string
+	http     *http.Client
+}
+
+// NewPwnedPasswords creates a checker calling the API through httpClient.
+func NewPwnedPasswords(httpClient *http.Client) *PwnedPasswords {
+	return &PwnedPasswords{endpoint: PwnedPasswordsURL, http: httpClient}
+}
+
+// Breached implements BreachChecker.
+func (p *PwnedPasswords) Breached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := digest[:5], digest[5:]
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+	// Padding hides the real number of suffixes from observers.
+	req.Header.Set("Add-Padding", "true")
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("pwned passwords: unexpected status %d", resp.StatusCode)
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if candidate == suffix && count != "0" {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// PasswordViolation explains why a password was refused. Format and Args
+// form a translatable message.
+type PasswordViolation struct {
+	Format string
+	Args   []interface{}
+}
+
+func (v *PasswordViolation) Error() string {
+	return fmt.Sprintf(v.Format, v.Args...)
+}
+
+// CheckPasswordPolicy returns why password does not meet policy, or nil.
+// The breach check only runs when the policy asks for it and breaches is
+// set; if the breach service cannot be reached the password is accepted
+// rather than locking users out.
+func CheckPasswordPolicy(ctx context.Context, policy models.PasswordPolicy, password string, breaches BreachChecker) *PasswordViolation {
+	minLength := max(policy.MinLength, MinPasswordLength)
+	if len([]rune(password)) < minLength {
+		return &PasswordViolation{Format: "Password must be at least %d characters", Args: []interface{}{minLength}}
+	}
+	var upper, lower, digit, symbol bool
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			upper = true
+		case unicode.IsLower(c):
+			lower = true
+		case unicode.IsDigit(c):
+			digit = true
+		case unicode.IsPunct(c) || unicode.IsSymbol(c) || unicode.IsSpace(c):
+			symbol = true
+		}
+	}
+	switch {
+	case policy.RequireUpper && !upper:
+		return &PasswordViolation{Format: "Password must contain an uppercase letter"}
+	case policy.RequireLower && !lower:
+		return &PasswordViolation{Format: "Password must contain a lowercase letter"}
+	case policy.RequireDigit && !digit:
+		return &PasswordViolation{Format: "Password must contain a digit"}
+	case policy.RequireSymbol && !symbol:
+		return &PasswordViolation{Format: "Password must contain a symbol"}
+	}
+	if policy.CheckBreached && breaches != nil {
+		breached, err := breaches.Breached(ctx, password)
+		if err != nil {
+			log.Printf("Password breach check failed: %v", err)
+		} else if breached {
+			return &PasswordViolation{Format: "This password has appeared in a data breach; choose another"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package security
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// TrustedProxies lists the networks of the reverse proxies in front of the
+// server. Only these are believed about where a request came from.
+type TrustedProxies []netip.Prefix
+
+// PrivateProxies trusts proxies on loopback and private networks, where
+// load balancers in front of the server usually run.
+var PrivateProxies = TrustedProxies{
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("::1/128"),
+	netip.MustParsePrefix("fc00::/7"),
+}
+
+// ParseTrustedProxies parses a comma-separated list of CIDRs.
+func ParseTrustedProxies(s string) (TrustedProxies, error) {
+	var out TrustedProxies
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", entry, err)
+		}
+		out = append(out, prefix.Masked())
+	}
+	return out, nil
+}
+
+// Trusts reports whether ip is the address of a trusted proxy.
+func (p TrustedProxies) Trusts(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, n := range p {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address of the client. Walking back from the
+// connection through X-Forwarded-For, each trusted proxy vouches for the
+// hop before it; the first hop that is not a trusted proxy is the client.
+// Entries further left are set by the client and are never believed.
+func ClientIP(r *http.Request, proxies TrustedProxies) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !proxies.Trusts(ip) {
+		return ip
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		ip = hop
+		if !proxies.Trusts(hop) {
+			break
+		}
+	}
+	return ip
+}
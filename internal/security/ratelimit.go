@@ -0,0 +1,45 @@
+package security
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter allows up to limit events per key in each fixed window.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu      sync.Mutex
+	windows map[string]*failures
+}
+
+// NewRateLimiter creates a limiter.
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{limit: limit, window: window, windows: make(map[string]*failures)}
+}
+
+// Allow counts an event for key and reports whether it is within the
+// limit. When it is not, it also returns when the window resets.
+func (l *RateLimiter) Allow(key string) (bool, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	w, ok := l.windows[key]
+	if !ok || now.After(w.first.Add(l.window)) {
+		if len(l.windows) > 100000 {
+			for k, v := range l.windows {
+				if now.After(v.first.Add(l.window)) {
+					delete(l.windows, k)
+				}
+			}
+		}
+		w = &failures{first: now}
+		l.windows[key] = w
+	}
+	if w.count >= l.limit {
+		return false, w.first.Add(l.window)
+	}
+	w.count++
+	return true, time.Time{}
+}
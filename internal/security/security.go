@@ -0,0 +1,245 @@
+// Package security detects suspicious logins: logins from a device the user
+// has not used before, and logins from places too far from the previous
+// login to have travelled between them.
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/mail"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+const (
+	// maxTravelSpeed is faster than any commercial flight, in km/h.
+	maxTravelSpeed = 1000
+	// minTravelDistance ignores jumps that geolocation noise can explain, in km.
+	minTravelDistance = 500
+
+	challengeTTL      = 15 * time.Minute
+	challengeAttempts = 5
+)
+
+// ErrChallengeFailed is returned for unknown, expired or wrong verification codes.
+var ErrChallengeFailed = errors.New("security: verification failed")
+
+// Locator resolves where a request comes from.
+type Locator interface {
+	Locate(r *http.Request) (country string, lat, lon float64, ok bool)
+}
+
+// HeaderLocator reads geolocation added by a trusted edge proxy.
+type HeaderLocator struct {
+	Country, Latitude, Longitude string
+}
+
+// CloudflareLocator reads the geolocation headers added by Cloudflare.
+var CloudflareLocator = HeaderLocator{Country: "CF-IPCountry", Latitude: "CF-IPLatitude", Longitude: "CF-IPLongitude"}
+
+// Locate implements Locator.
+func (h HeaderLocator) Locate(r *http.Request) (string, float64, float64, bool) {
+	country := r.Header.Get(h.Country)
+	lat, errLat := strconv.ParseFloat(r.Header.Get(h.Latitude), 64)
+	lon, errLon := strconv.ParseFloat(r.Header.Get(h.Longitude), 64)
+	return country, lat, lon, errLat == nil && errLon == nil
+}
+
+// Fingerprint identifies the client device from stable request headers.
+func Fingerprint(r *http.Request) string {
+	sum := sha256.Sum256([]byte(r.UserAgent() + "\n" + r.Header.Get("Accept-Language")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Detector checks each login against the user's history, records security
+// events in the store and audit log, and can hold back the session of a
+// suspicious login until the user confirms a code sent by email.
+type Detector struct {
+	st       *store.Memory
+	mailer   mail.Mailer
+	locator  Locator
+	proxies  TrustedProxies
+	reverify bool
+
+	mu         sync.Mutex
+	challenges map[string]*challenge
+}
+
+type challenge struct {
+	codeHash [32]byte
+	token    string
+	expires  time.Time
+	attempts int
+}
+
+// NewDetector creates a detector. With reverify set, suspicious logins must
+// be confirmed with an emailed code before their token is released.
+func NewDetector(st *store.Memory, mailer mail.Mailer, locator Locator, proxies TrustedProxies, reverify bool) *Detector {
+	return &Detector{
+		st:         st,
+		mailer:     mailer,
+		locator:    locator,
+		proxies:    proxies,
+		reverify:   reverify,
+		challenges: make(map[string]*challenge),
+	}
+}
+
+// Observe records a successful login from r and returns the security events
+// it raised.
+func (d *Detector) Observe(r *http.Request, userID, orgID string) []*models.SecurityEvent {
+	login := &models.LoginEvent{
+		UserID:    userID,
+		OrgID:     orgID,
+		IP:        ClientIP(r, d.proxies),
+		Device:    Fingerprint(r),
+		UserAgent: r.UserAgent(),
+		At:        time.Now(),
+	}
+	if d.locator != nil {
+		login.Country, login.Latitude, login.Longitude, login.Located = d.locator.Locate(r)
+	}
+
+	history := d.st.LoginHistory(userID)
+	d.st.RecordLogin(login)
+	if len(history) == 0 {
+		// Nothing to compare the first login against.
+		return nil
+	}
+
+	var events []*models.SecurityEvent
+	raise := func(eventType, detail string) {
+		e := &models.SecurityEvent{
+			OrgID:     orgID,
+			UserID:    userID,
+			Type:      eventType,
+			IP:        login.IP,
+			Device:    login.Device,
+			Country:   login.Country,
+			Detail:    detail,
+			CreatedAt: login.At,
+		}
+		d.st.AddSecurityEvent(e)
+		d.st.AppendAudit(&models.AuditEvent{
+			OrgID:      orgID,
+			ActorID:    userID,
+			Action:     "security." + eventType,
+			TargetType: "user",
+			TargetID:   userID,
+			Reason:     detail,
+			Metadata:   map[string]string{"ip": login.IP, "device": login.Device, "country": login.Country},
+			CreatedAt:  login.At,
+		})
+		events = append(events, e)
+	}
+
+	known := false
+	for _, prev := range history {
+		if prev.Device == login.Device {
+			known = true
+			break
+		}
+	}
+	if !known {
+		raise(models.SecurityNewDevice, "login from a device not seen before")
+	}
+
+	if last := history[len(history)-1]; last.Located && login.Located {
+		km := distance(last.Latitude, last.Longitude, login.Latitude, login.Longitude)
+		hours := login.At.Sub(last.At).Hours()
+		if km >= minTravelDistance && (hours <= 0 || km/hours > maxTravelSpeed) {
+			raise(models.SecurityImpossibleTravel, fmt.Sprintf("%.0f km from the previous login (%s) in %s",
+				km, last.Country, login.At.Sub(last.At).Round(time.Minute)))
+		}
+	}
+	return events
+}
+
+// RequiresVerification reports whether a login that raised events must be
+// confirmed before its session is released.
+func (d *Detector) RequiresVerification(events []*models.SecurityEvent) bool {
+	return d.reverify && len(events) > 0
+}
+
+// Challenge holds token back and emails a one-time code to the user. It
+// returns the ID the client sends with the code to Verify.
+func (d *Detector) Challenge(email, lang, token string) (string, error) {
+	id, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+
+	d.mu.Lock()
+	now := time.Now()
+	for cid, c := range d.challenges {
+		if now.After(c.expires) {
+			delete(d.challenges, cid)
+		}
+	}
+	d.challenges[id] = &challenge{codeHash: sha256.Sum256([]byte(code)), token: token, expires: now.Add(challengeTTL)}
+	d.mu.Unlock()
+
+	return id, d.mailer.Send(mail.Message{
+		To:      email,
+		Subject: i18n.T(lang, "Confirm your sign-in to Aurea Orchestrator"),
+		Body:    i18n.T(lang, "We noticed a sign-in from a new device or location. Your verification code is %s. It expires in 15 minutes.", code),
+	})
+}
+
+// Verify checks a code and returns the held token. A challenge is dropped
+// after it succeeds or has been guessed too many times.
+func (d *Detector) Verify(id, code string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c, ok := d.challenges[id]
+	if !ok || time.Now().After(c.expires) {
+		delete(d.challenges, id)
+		return "", ErrChallengeFailed
+	}
+	sum := sha256.Sum256([]byte(code))
+	if subtle.ConstantTimeCompare(sum[:], c.codeHash[:]) != 1 {
+		c.attempts++
+		if c.attempts >= challengeAttempts {
+			delete(d.challenges, id)
+		}
+		return "", ErrChallengeFailed
+	}
+	delete(d.challenges, id)
+	return c.token, nil
+}
+
+// distance returns the great-circle distance between two points in km.
+func distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,230 @@
+// Package seed populates a store with sample organizations, users and
+// reviews, so evaluators and frontend developers get a meaningful
+// environment without creating data by hand.
+package seed
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/security"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Account is a seeded user that can log in with the seed password.
+type Account struct {
+	Email string
+	Name  string
+	Role  string
+	OrgID string
+}
+
+type org struct {
+	id, name string
+	users    []user
+	reviews  []review
+}
+
+type user struct {
+	key, email, name, role string
+}
+
+// review is a sample review. Users are referred to by key, and ages are
+// relative to the time of seeding.
+type review struct {
+	title, content string
+	author         string
+	age            time.Duration
+	assignee       string
+	approver       string
+	priority       string
+	labels         []string
+	comments       []comment
+}
+
+type comment struct {
+	author   string
+	body     string
+	resolver string
+}
+
+var profiles = map[string][]org{
+	"minimal": {{
+		id: "demo", name: "Demo",
+		users: []user{
+			{"admin", "admin@demo.example", "Demo Admin", "admin"},
+			{"reviewer", "reviewer@demo.example", "Demo Reviewer", "reviewer"},
+		},
+	}},
+	"demo": {
+		{
+			id: "acme", name: "Acme Corp",
+			users: []user{
+				{"alice", "alice@acme.example", "Alice Moreno", "admin"},
+				{"bob", "bob@acme.example", "Bob Chen", "reviewer"},
+				{"carol", "carol@acme.example", "Carol Díaz", "reviewer"},
+				{"dave", "dave@acme.example", "Dave Okafor", "member"},
+			},
+			reviews: []review{
+				{
+					title: "Q3 hiring plan", author: "alice", age: 2 * time.Hour,
+					content: "Proposal to open four engineering roles in Q3, two of them senior.",
+					labels:  []string{"planning"},
+				},
+				{
+					title: "Vendor contract renewal: Initech", author: "dave", age: 26 * time.Hour,
+					content:  "Renew the support contract for another 12 months at a 5% increase.",
+					assignee: "bob", priority: "high", labels: []string{"legal", "finance"},
+					comments: []comment{
+						{author: "bob", body: "Can we get the termination clause reviewed by legal first?"},
+						{author: "dave", body: "Sent to legal this morning."},
+					},
+				},
+				{
+					title: "Incident postmortem: checkout outage", author: "carol", age: 3 * 24 * time.Hour,
+					content:  "Root cause was an expired certificate on the payments gateway.",
+					assignee: "alice", approver: "alice", priority: "critical", labels: []string{"incident"},
+					comments: []comment{
+						{author: "alice", body: "Please add the action item for certificate monitoring.", resolver: "carol"},
+					},
+				},
+				{
+					title: "Public API deprecation notice", author: "bob", age: 5 * 24 * time.Hour,
+					content:  "Announce the removal of the v1 endpoints with six months' notice.",
+					assignee: "carol", priority: "normal", labels: []string{"api", "communication"},
+					comments: []comment{
+						{author: "carol", body: "Six months seems short for enterprise customers.", resolver: "bob"},
+						{author: "carol", body: "Which customers are still on v1?"},
+					},
+				},
+				{
+					title: "Office move checklist", author: "dave", age: 9 * 24 * time.Hour,
+					content:  "Everything needed before the move to the new floor.",
+					approver: "alice", priority: "low",
+				},
+			},
+		},
+		{
+			id: "globex", name: "Globex",
+			users: []user{
+				{"erin", "erin@globex.example", "Erin Walsh", "admin"},
+				{"frank", "frank@globex.example", "Frank Rossi", "reviewer"},
+				{"grace", "grace@globex.example", "Grace Kim", "member"},
+			},
+			reviews: []review{
+				{
+					title: "Data retention policy update", author: "grace", age: 30 * time.Hour,
+					content:  "Shorten retention for support tickets from five years to two.",
+					assignee: "frank", priority: "high", labels: []string{"compliance"},
+					comments: []comment{
+						{author: "frank", body: "Does this conflict with the audit requirements in the EU?"},
+					},
+				},
+				{
+					title: "Marketing site redesign", author: "erin", age: 6 * 24 * time.Hour,
+					content:  "New landing page and pricing table.",
+					assignee: "frank", approver: "erin", labels: []string{"design"},
+				},
+			},
+		},
+	},
+}
+
+// Profiles returns the names of the available profiles.
+func Profiles() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load adds the named profile's data to st. Every seeded user gets
+// password, stored hashed. It returns the accounts that can log in.
+func Load(st *store.Memory, profile, password string) ([]Account, error) {
+	orgs, ok := profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("unknown seed profile %q", profile)
+	}
+	hash, err := security.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var accounts []Account
+	for _, o := range orgs {
+		st.SaveOrg(&models.Organization{ID: o.id, Name: o.name, CreatedAt: now.Add(-30 * 24 * time.Hour)})
+
+		ids := make(map[string]string, len(o.users))
+		for _, u := range o.users {
+			created := &models.User{Email: u.email, Name: u.name, Role: u.role, OrgID: o.id, CreatedAt: now.Add(-30 * 24 * time.Hour)}
+			if err := st.CreateUser(created); err != nil {
+				return nil, fmt.Errorf("user %s: %w", u.email, err)
+			}
+			st.SetPasswordHash(created.ID, hash)
+			ids[u.key] = created.ID
+			accounts = append(accounts, Account{Email: u.email, Name: u.name, Role: u.role, OrgID: o.id})
+		}
+
+		for i, rv := range o.reviews {
+			if err := loadReview(st, o.id, fmt.Sprintf("review-%s-%d", o.id, i+1), rv, ids, now); err != nil {
+				return nil, fmt.Errorf("review %q: %w", rv.title, err)
+			}
+		}
+	}
+	return accounts, nil
+}
+
+func loadReview(st *store.Memory, orgID, id string, rv review, ids map[string]string, now time.Time) error {
+	created := now.Add(-rv.age)
+	status := "pending"
+	if rv.approver != "" {
+		status = "approved"
+	}
+	err := st.SaveReview(&models.Review{
+		ID:        id,
+		OrgID:     orgID,
+		AuthorID:  ids[rv.author],
+		Title:     rv.title,
+		Content:   rv.content,
+		Status:    status,
+		CreatedAt: created,
+		UpdatedAt: created,
+	})
+	if err != nil {
+		return err
+	}
+	if len(rv.labels) > 0 {
+		st.SetLabels(id, rv.labels)
+	}
+	if rv.priority != "" {
+		st.SetPriority(id, rv.priority)
+	}
+	if rv.assignee != "" {
+		err = st.Assign(&models.Assignment{ReviewID: id, ReviewerID: ids[rv.assignee], AssignedBy: ids[rv.author], AssignedAt: created.Add(10 * time.Minute)})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Activity is spread evenly between creation and now.
+	step := rv.age / time.Duration(len(rv.comments)+2)
+	for i, c := range rv.comments {
+		at := created.Add(step * time.Duration(i+1))
+		comment := &models.Comment{ReviewID: id, AuthorID: ids[c.author], Body: c.body, CreatedAt: at}
+		if err := st.AddComment(comment); err != nil {
+			return err
+		}
+		if c.resolver != "" {
+			st.ResolveThread(&models.ThreadResolution{CommentID: comment.ID, ResolvedBy: ids[c.resolver], ResolvedAt: at.Add(step / 2)})
+		}
+	}
+	if rv.approver != "" {
+		st.SaveApproval(&models.Approval{ReviewID: id, ApprovedBy: ids[rv.approver], ApprovedAt: now.Add(-step)})
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package sharelink
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	s := NewSigner("secret")
+	now := time.Unix(1_700_000_000, 0)
+	token := s.Sign("share-1", now.Add(time.Hour))
+	parts := strings.Split(token, ".")
+
+	tests := []struct {
+		name  string
+		token string
+		at    time.Time
+		want  string
+	}{
+		{"valid", token, now, "share-1"},
+		{"at expiry", token, now.Add(time.Hour), ""},
+		{"expired", token, now.Add(2 * time.Hour), ""},
+		{"other link", "share-2." + parts[1] + "." + parts[2], now, ""},
+		{"extended expiry", parts[0] + "." + "9999999999" + "." + parts[2], now, ""},
+		{"other secret", NewSigner("other").Sign("share-1", now.Add(time.Hour)), now, ""},
+		{"missing signature", parts[0] + "." + parts[1], now, ""},
+		{"empty", "", now, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Verify(tt.token, tt.at)
+			if got != tt.want {
+				t.Errorf("Verify = %q, want %q", got, tt.want)
+			}
+			if tt.want == "" && !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify error = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
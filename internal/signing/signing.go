@@ -0,0 +1,149 @@
+// Package signing produces and verifies signed approval records.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// Algorithm is the signature scheme used for approval proofs.
+const Algorithm = "ed25519"
+
+// ErrInvalidProof is returned when a proof fails verification.
+var ErrInvalidProof = errors.New("invalid approval proof")
+
+// Signer signs approval records with the server key.
+type Signer struct {
+	key   ed25519.PrivateKey
+	keyID string
+}
+
+// NewSigner creates a signer from a base64-encoded 32-byte Ed25519 seed.
+func NewSigner(seed string) (*Signer, error) {
+	raw, err := base64.StdEncoding.DecodeString(seed)
+	if err != nil {
+		return nil, fmt.Errorf("decode signing key: %w", err)
+	}
+	if len(raw) != ed25519.SeedSize {
+		return nil, fmt.Errorf("signing key must be %d bytes, got %d", ed25519.SeedSize, len(raw))
+	}
+	return newSigner(ed25519.NewKeyFromSeed(raw)), nil
+}
+
+// NewEphemeralSigner creates a signer with a random key. Proofs it issues
+// remain verifiable offline, but the key does not survive a restart.
+func NewEphemeralSigner() (*Signer, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return newSigner(key), nil
+}
+
+func newSigner(key ed25519.PrivateKey) *Signer {
+	return &Signer{key: key, keyID: keyID(key.Public().(ed25519.PublicKey))}
+}
+
+// PublicKey returns the base64-encoded public key.
+func (s *Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
+
+// KeyID returns a short fingerprint of the public key.
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// payload is the signed portion of a proof. Field order is fixed so the
+// encoding is stable.
+type payload struct {
+	ReviewID    string `json:"review_id"`
+	ContentHash string `json:"content_hash"`
+	ApprovedBy  string `json:"approved_by"`
+	ApprovedAt  string `json:"approved_at"`
+	KeyID       string `json:"key_id"`
+}
+
+// Sign issues a proof binding the review content to the approval.
+func (s *Signer) Sign(review *models.Review, approval *models.Approval) (*models.ApprovalProof, error) {
+	p := payload{
+		ReviewID:    review.ID,
+		ContentHash: ContentHash(review),
+		ApprovedBy:  approval.ApprovedBy,
+		ApprovedAt:  approval.ApprovedAt.UTC().Format(time.RFC3339Nano),
+		KeyID:       s.keyID,
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ApprovalProof{
+		ReviewID:    p.ReviewID,
+		ContentHash: p.ContentHash,
+		ApprovedBy:  p.ApprovedBy,
+		ApprovedAt:  approval.ApprovedAt.UTC(),
+		Algorithm:   Algorithm,
+		KeyID:       s.keyID,
+		PublicKey:   s.PublicKey(),
+		Payload:     string(body),
+		Signature:   base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, body)),
+	}, nil
+}
+
+// ContentHash returns the hex SHA-256 of the review fields covered by an approval.
+func ContentHash(review *models.Review) string {
+	h := sha256.New()
+	for _, field := range []string{review.ID, review.OrgID, review.AuthorID, review.Title, review.Content} {
+		// Length-prefix each field so boundaries cannot be shifted.
+		fmt.Fprintf(h, "%d:%s", len(field), field)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify checks a proof's signature against publicKey (base64) and that the
+// signed payload matches the proof's fields. It needs no server access.
+// Pass the review to also confirm its current content matches the approved
+// content, or nil to skip that check.
+func Verify(proof *models.ApprovalProof, publicKey string, review *models.Review) error {
+	if proof.Algorithm != Algorithm {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidProof, proof.Algorithm)
+	}
+	pub, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: malformed public key", ErrInvalidProof)
+	}
+	sig, err := base64.StdEncoding.DecodeString(proof.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidProof)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pub), []byte(proof.Payload), sig) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidProof)
+	}
+
+	var p payload
+	if err := json.Unmarshal([]byte(proof.Payload), &p); err != nil {
+		return fmt.Errorf("%w: malformed payload", ErrInvalidProof)
+	}
+	if p.ReviewID != proof.ReviewID || p.ContentHash != proof.ContentHash || p.ApprovedBy != proof.ApprovedBy {
+		return fmt.Errorf("%w: payload does not match proof fields", ErrInvalidProof)
+	}
+	if review != nil && ContentHash(review) != p.ContentHash {
+		return fmt.Errorf("%w: review content changed since approval", ErrInvalidProof)
+	}
+	return nil
+}
+
+func keyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
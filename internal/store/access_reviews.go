@@ -0,0 +1,116 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// AddAccessCampaign stores an open campaign with its items, assigning its
+// ID.
+func (m *Memory) AddAccessCampaign(c models.AccessCampaign, items []models.AccessItem) models.AccessCampaign {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.campaignSeq++
+	c.ID = fmt.Sprintf("campaign-%d", m.campaignSeq)
+	c.Status = models.CampaignOpen
+	m.campaigns[c.ID] = &c
+	stored := make([]*models.AccessItem, len(items))
+	for i := range items {
+		item := items[i]
+		item.CampaignID = c.ID
+		item.Decision = models.AccessPending
+		stored[i] = &item
+	}
+	m.accessItems[c.ID] = stored
+	return c
+}
+
+// GetAccessCampaign returns a campaign of the organization.
+func (m *Memory) GetAccessCampaign(orgID, id string) (models.AccessCampaign, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, ok := m.campaigns[id]
+	if !ok || c.OrgID != orgID {
+		return models.AccessCampaign{}, ErrNotFound
+	}
+	return *c, nil
+}
+
+// ListAccessCampaigns returns the organization's campaigns, newest first.
+// An empty orgID lists every organization's open campaigns.
+func (m *Memory) ListAccessCampaigns(orgID string) []models.AccessCampaign {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.AccessCampaign{}
+	for _, c := range m.campaigns {
+		if c.OrgID == orgID || (orgID == "" && c.Status == models.CampaignOpen) {
+			out = append(out, *c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// ListAccessItems returns a campaign's items, ordered by user.
+func (m *Memory) ListAccessItems(campaignID string) []models.AccessItem {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]models.AccessItem, 0, len(m.accessItems[campaignID]))
+	for _, item := range m.accessItems[campaignID] {
+		out = append(out, *item)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UserID < out[j].UserID })
+	return out
+}
+
+// DecideAccessItem records the decision on a pending item of an open
+// campaign.
+func (m *Memory) DecideAccessItem(campaignID, userID string, decided models.AccessItem) (models.AccessItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.decideAccessItem(campaignID, userID, decided)
+}
+
+func (m *Memory) decideAccessItem(campaignID, userID string, decided models.AccessItem) (models.AccessItem, error) {
+	c, ok := m.campaigns[campaignID]
+	if !ok {
+		return models.AccessItem{}, ErrNotFound
+	}
+	for i, item := range m.accessItems[campaignID] {
+		if item.UserID != userID {
+			continue
+		}
+		if item.Decision != models.AccessPending || c.Status != models.CampaignOpen {
+			return *item, ErrDecided
+		}
+		decided.CampaignID, decided.UserID, decided.Role = item.CampaignID, item.UserID, item.Role
+		m.accessItems[campaignID][i] = &decided
+		return decided, nil
+	}
+	return models.AccessItem{}, ErrNotFound
+}
+
+// CompleteAccessCampaign closes a campaign.
+func (m *Memory) CompleteAccessCampaign(id string, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c, ok := m.campaigns[id]; ok {
+		completed := *c
+		completed.Status, completed.CompletedAt = models.CampaignCompleted, &at
+		m.campaigns[id] = &completed
+	}
+}
+
+// MarkAccessReminder records when a campaign's reminders were last sent.
+func (m *Memory) MarkAccessReminder(id string, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c, ok := m.campaigns[id]; ok {
+		reminded := *c
+		reminded.LastReminder = &at
+		m.campaigns[id] = &reminded
+	}
+}
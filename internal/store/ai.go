@@ -0,0 +1,256 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// SetReviewSummary stores the latest AI summary of a review.
+func (m *Memory) SetReviewSummary(s models.ReviewSummary) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.Risks = slices.Clone(s.Risks)
+	m.summaries[s.ReviewID] = s
+}
+
+// GetReviewSummary returns the latest AI summary of a review.
+func (m *Memory) GetReviewSummary(reviewID string) (models.ReviewSummary, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.summaries[reviewID]
+	if !ok {
+		return models.ReviewSummary{}, ErrNotFound
+	}
+	s.Risks = slices.Clone(s.Risks)
+	return s, nil
+}
+
+// RecordLLMUsage records a language model request, assigning its ID and
+// time.
+func (m *Memory) RecordLLMUsage(u models.LLMUsage) models.LLMUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.llmSeq++
+	u.ID = fmt.Sprintf("llm-%d", m.llmSeq)
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = time.Now()
+	}
+	m.llmUsage = append(m.llmUsage, u)
+	return u
+}
+
+// ListLLMUsage returns an organization's language model requests made in
+// [from, to), oldest first.
+func (m *Memory) ListLLMUsage(orgID string, from, to time.Time) []models.LLMUsage {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.LLMUsage{}
+	for _, u := range m.llmUsage {
+		if u.OrgID == orgID && !u.CreatedAt.Before(from) && u.CreatedAt.Before(to) {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// ListLLMProviders returns the language model providers an organization
+// configured, in order of preference.
+func (m *Memory) ListLLMProviders(orgID string) []models.LLMProviderConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.llmConfigs[orgID])
+}
+
+// SetLLMProvider creates or replaces an organization's provider by name.
+func (m *Memory) SetLLMProvider(c models.LLMProviderConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	configs := slices.DeleteFunc(m.llmConfigs[c.OrgID], func(o models.LLMProviderConfig) bool { return o.Name == c.Name })
+	configs = append(configs, c)
+	sort.SliceStable(configs, func(i, j int) bool { return configs[i].Priority < configs[j].Priority })
+	m.llmConfigs[c.OrgID] = configs
+}
+
+// DeleteLLMProvider removes an organization's provider.
+func (m *Memory) DeleteLLMProvider(orgID, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	configs := m.llmConfigs[orgID]
+	i := slices.IndexFunc(configs, func(c models.LLMProviderConfig) bool { return c.Name == name })
+	if i < 0 {
+		return ErrNotFound
+	}
+	m.llmConfigs[orgID] = slices.Delete(configs, i, i+1)
+	return nil
+}
+
+// GetAIBudget returns an organization's monthly AI budget, if it set one.
+func (m *Memory) GetAIBudget(orgID string) (models.AIBudget, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	b, ok := m.aiBudgets[orgID]
+	return b, ok
+}
+
+// SetAIBudget sets an organization's monthly AI budget; zero removes it.
+func (m *Memory) SetAIBudget(b models.AIBudget) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if b.MonthlyUSD <= 0 {
+		delete(m.aiBudgets, b.OrgID)
+		return
+	}
+	m.aiBudgets[b.OrgID] = b
+}
+
+// promptHistory holds the versions of an organization's prompt for one AI
+// feature and which is in use; active 0 means the built-in default.
+type promptHistory struct {
+	versions []models.PromptTemplate
+	active   int
+}
+
+// AddPromptVersion stores a new version of an organization's prompt for
+// a feature, assigning its version, and puts it in use.
+func (m *Memory) AddPromptVersion(t models.PromptTemplate) models.PromptTemplate {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	byFeature, ok := m.prompts[t.OrgID]
+	if !ok {
+		byFeature = make(map[string]*promptHistory)
+		m.prompts[t.OrgID] = byFeature
+	}
+	h, ok := byFeature[t.Feature]
+	if !ok {
+		h = &promptHistory{}
+		byFeature[t.Feature] = h
+	}
+	t.Version = len(h.versions) + 1
+	h.versions = append(h.versions, t)
+	h.active = t.Version
+	return t
+}
+
+// ActivePrompt returns the organization's prompt in use for a feature; ok
+// is false when the built-in default is.
+func (m *Memory) ActivePrompt(orgID, feature string) (models.PromptTemplate, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	h, ok := m.prompts[orgID][feature]
+	if !ok || h.active == 0 {
+		return models.PromptTemplate{}, false
+	}
+	return h.versions[h.active-1], true
+}
+
+// PromptVersions returns every version of the organization's prompt for
+// a feature, oldest first.
+func (m *Memory) PromptVersions(orgID, feature string) []models.PromptTemplate {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	h, ok := m.prompts[orgID][feature]
+	if !ok {
+		return []models.PromptTemplate{}
+	}
+	return slices.Clone(h.versions)
+}
+
+// GetPromptVersion returns one version of the organization's prompt for
+// a feature.
+func (m *Memory) GetPromptVersion(orgID, feature string, version int) (models.PromptTemplate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	h, ok := m.prompts[orgID][feature]
+	if !ok || version < 1 || version > len(h.versions) {
+		return models.PromptTemplate{}, ErrNotFound
+	}
+	return h.versions[version-1], nil
+}
+
+// SetActivePrompt puts a stored version of the organization's prompt for
+// a feature in use; version 0 rolls back to the built-in default.
+func (m *Memory) SetActivePrompt(orgID, feature string, version int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.prompts[orgID][feature]
+	if !ok {
+		if version == 0 {
+			return nil
+		}
+		return ErrNotFound
+	}
+	if version < 0 || version > len(h.versions) {
+		return ErrNotFound
+	}
+	h.active = version
+	return nil
+}
+
+// GetAIRedactionPolicy returns an organization's AI redaction policy, or
+// the default one.
+func (m *Memory) GetAIRedactionPolicy(orgID string) models.AIRedactionPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	p, ok := m.aiRedactPol[orgID]
+	if !ok {
+		return models.DefaultAIRedactionPolicy(orgID)
+	}
+	p.PII = slices.Clone(p.PII)
+	p.Rules = slices.Clone(p.Rules)
+	return p
+}
+
+// SetAIRedactionPolicy replaces an organization's AI redaction policy.
+func (m *Memory) SetAIRedactionPolicy(p models.AIRedactionPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p.PII = slices.Clone(p.PII)
+	p.Rules = slices.Clone(p.Rules)
+	m.aiRedactPol[p.OrgID] = p
+}
+
+// RecordAIRedaction records what was redacted from a language model
+// request, assigning its ID and time.
+func (m *Memory) RecordAIRedaction(r models.AIRedaction) models.AIRedaction {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.aiRedactSeq++
+	r.ID = fmt.Sprintf("air-%d", m.aiRedactSeq)
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+	r.Entries = slices.Clone(r.Entries)
+	m.aiRedacts = append(m.aiRedacts, r)
+	return r
+}
+
+// ListAIRedactions returns an organization's latest redaction records,
+// newest first, at most limit of them.
+func (m *Memory) ListAIRedactions(orgID string, limit int) []models.AIRedaction {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.AIRedaction{}
+	for i := len(m.aiRedacts) - 1; i >= 0 && len(out) < limit; i-- {
+		if r := m.aiRedacts[i]; r.OrgID == orgID {
+			r.Entries = slices.Clone(r.Entries)
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// AllLLMProviders returns the language model providers of every
+// organization.
+func (m *Memory) AllLLMProviders() []models.LLMProviderConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []models.LLMProviderConfig
+	for _, configs := range m.llmConfigs {
+		out = append(out, configs...)
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// CreateApprovalGate stores a new gate, assigning its ID.
+func (m *Memory) CreateApprovalGate(g models.ApprovalGate) models.ApprovalGate {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gateSeq++
+	g.ID = fmt.Sprintf("gate-%d", m.gateSeq)
+	m.gates[g.ID] = &g
+	return g
+}
+
+// GetApprovalGate returns a gate of the organization.
+func (m *Memory) GetApprovalGate(orgID, id string) (models.ApprovalGate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	g, ok := m.gates[id]
+	if !ok || g.OrgID != orgID {
+		return models.ApprovalGate{}, ErrNotFound
+	}
+	return *g, nil
+}
+
+// ListApprovalGates returns the organization's gates, oldest first.
+func (m *Memory) ListApprovalGates(orgID string) []models.ApprovalGate {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.ApprovalGate{}
+	for _, g := range m.gates {
+		if g.OrgID == orgID {
+			out = append(out, *g)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// UpdateApprovalGate replaces a gate of the organization.
+func (m *Memory) UpdateApprovalGate(g models.ApprovalGate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cur, ok := m.gates[g.ID]; !ok || cur.OrgID != g.OrgID {
+		return ErrNotFound
+	}
+	m.gates[g.ID] = &g
+	return nil
+}
+
+// DeleteApprovalGate removes a gate of the organization.
+func (m *Memory) DeleteApprovalGate(orgID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if g, ok := m.gates[id]; !ok || g.OrgID != orgID {
+		return ErrNotFound
+	}
+	delete(m.gates, id)
+	return nil
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// AddAttachment records a new attachment, assigning its ID.
+func (m *Memory) AddAttachment(a models.Attachment) (models.Attachment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, err := m.storedAttachment(a)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	m.attachSeq++
+	a.ID = fmt.Sprintf("attachment-%d", m.attachSeq)
+	stored.ID = a.ID
+	m.attachments[a.ID] = stored
+	return a, nil
+}
+
+// ListAttachments returns a review's attachments, oldest first.
+func (m *Memory) ListAttachments(reviewID string) []models.Attachment {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.Attachment{}
+	for _, a := range m.attachments {
+		if a.ReviewID != reviewID {
+			continue
+		}
+		if read, ok := m.listedAttachment(a); ok {
+			out = append(out, read)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UploadedAt.Before(out[j].UploadedAt) })
+	return out
+}
+
+// GetAttachment returns one of a review's attachments.
+func (m *Memory) GetAttachment(reviewID, id string) (models.Attachment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, ok := m.attachments[id]
+	if !ok || a.ReviewID != reviewID {
+		return models.Attachment{}, ErrNotFound
+	}
+	return m.readAttachment(a)
+}
+
+// AttachmentsByScanStatus returns the attachments with a scan status,
+// oldest first.
+func (m *Memory) AttachmentsByScanStatus(status string) []models.Attachment {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.Attachment{}
+	for _, a := range m.attachments {
+		if a.Scan.Status != status {
+			continue
+		}
+		if read, ok := m.listedAttachment(a); ok {
+			out = append(out, read)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UploadedAt.Before(out[j].UploadedAt) })
+	return out
+}
+
+// SetAttachmentScan records the scan outcome of an attachment and, when
+// blobKey is not empty, where its content now lives.
+func (m *Memory) SetAttachmentScan(id string, scan models.AttachmentScan, blobKey string) (models.Attachment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.attachments[id]
+	if !ok {
+		return models.Attachment{}, ErrNotFound
+	}
+	updated := *a
+	updated.Scan = scan
+	if blobKey != "" {
+		updated.BlobKey = blobKey
+	}
+	m.attachments[id] = &updated
+	return m.readAttachment(&updated)
+}
+
+// DeleteAttachment removes one of a review's attachments and returns it so
+// the caller can delete its content. It returns ErrLegalHold if the review
+// is under a legal hold.
+func (m *Memory) DeleteAttachment(reviewID, id string) (models.Attachment, error) {
+	var a models.Attachment
+	err := m.Update(func(tx *Tx) (err error) {
+		a, err = tx.DeleteAttachment(reviewID, id)
+		return err
+	})
+	return a, err
+}
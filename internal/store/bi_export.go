@@ -0,0 +1,65 @@
+package store
+
+import (
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// lifecycleRetention bounds how long lifecycle events are kept for export.
+const lifecycleRetention = 14 * 24 * time.Hour
+
+// GetBIExportConfig returns an organization's BI export configuration.
+func (m *Memory) GetBIExportConfig(orgID string) (models.BIExportConfig, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, ok := m.biConfigs[orgID]
+	return c, ok
+}
+
+// SetBIExportConfig replaces an organization's BI export configuration.
+func (m *Memory) SetBIExportConfig(c models.BIExportConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.biConfigs[c.OrgID] = c
+}
+
+// ListBIExportConfigs returns every enabled BI export configuration.
+func (m *Memory) ListBIExportConfigs() []models.BIExportConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []models.BIExportConfig
+	for _, c := range m.biConfigs {
+		if c.Enabled {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// AppendLifecycle records a lifecycle event and drops events older than
+// the retention period.
+func (m *Memory) AppendLifecycle(e *models.LifecycleEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cutoff := e.Timestamp.Add(-lifecycleRetention)
+	drop := 0
+	for drop < len(m.lifecycle) && m.lifecycle[drop].Timestamp.Before(cutoff) {
+		drop++
+	}
+	m.lifecycle = append(m.lifecycle[drop:], e)
+}
+
+// ListLifecycle returns an organization's lifecycle events in [from, to),
+// oldest first.
+func (m *Memory) ListLifecycle(orgID string, from, to time.Time) []*models.LifecycleEvent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []*models.LifecycleEvent{}
+	for _, e := range m.lifecycle {
+		if e.OrgID == orgID && !e.Timestamp.Before(from) && e.Timestamp.Before(to) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,21 @@
+package store
+
+import "github.com/andres20980/aurea-orchestrator/internal/models"
+
+// GetBranding returns an organization's branding. Organizations without
+// branding get an empty record.
+func (m *Memory) GetBranding(orgID string) models.OrgBranding {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if b, ok := m.branding[orgID]; ok {
+		return b
+	}
+	return models.OrgBranding{OrgID: orgID}
+}
+
+// SetBranding replaces an organization's branding.
+func (m *Memory) SetBranding(b models.OrgBranding) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.branding[b.OrgID] = b
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// AddBreakGlass records an elevation request, assigning its ID. It returns
+// ErrDuplicate if the user already has one pending or in effect.
+func (m *Memory) AddBreakGlass(b models.BreakGlass) (models.BreakGlass, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.breakGlass {
+		if existing.UserID == b.UserID && (existing.Pending(b.RequestedAt) || existing.Active(b.RequestedAt)) {
+			return models.BreakGlass{}, ErrDuplicate
+		}
+	}
+	m.glassSeq++
+	b.ID = fmt.Sprintf("break-glass-%d", m.glassSeq)
+	m.breakGlass[b.ID] = &b
+	return b, nil
+}
+
+// GetBreakGlass returns an elevation by ID.
+func (m *Memory) GetBreakGlass(id string) (models.BreakGlass, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	b, ok := m.breakGlass[id]
+	if !ok {
+		return models.BreakGlass{}, ErrNotFound
+	}
+	return *b, nil
+}
+
+// ApproveBreakGlass grants a pending elevation for the minutes requested,
+// starting at. It returns ErrDecided if the request is no longer pending.
+func (m *Memory) ApproveBreakGlass(id, approverID string, at time.Time) (models.BreakGlass, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.breakGlass[id]
+	if !ok {
+		return models.BreakGlass{}, ErrNotFound
+	}
+	if !b.Pending(at) {
+		return models.BreakGlass{}, ErrDecided
+	}
+	granted := *b
+	expires := at.Add(time.Duration(b.Minutes) * time.Minute)
+	granted.ApprovedBy = approverID
+	granted.GrantedAt = &at
+	granted.ExpiresAt = &expires
+	m.breakGlass[id] = &granted
+	return granted, nil
+}
+
+// ActiveBreakGlass returns the user's elevation in effect at t, if any.
+func (m *Memory) ActiveBreakGlass(userID string, t time.Time) (models.BreakGlass, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, b := range m.breakGlass {
+		if b.UserID == userID && b.Active(t) {
+			return *b, true
+		}
+	}
+	return models.BreakGlass{}, false
+}
+
+// CurrentBreakGlass returns the user's elevation pending or in effect at
+// t, if any.
+func (m *Memory) CurrentBreakGlass(userID string, t time.Time) (models.BreakGlass, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, b := range m.breakGlass {
+		if b.UserID == userID && (b.Pending(t) || b.Active(t)) {
+			return *b, true
+		}
+	}
+	return models.BreakGlass{}, false
+}
+
+// EndBreakGlass ends the user's elevation early, or withdraws their
+// request if it has not been approved yet.
+func (m *Memory) EndBreakGlass(userID string, at time.Time) (models.BreakGlass, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, b := range m.breakGlass {
+		if b.UserID == userID && (b.Pending(at) || b.Active(at)) {
+			ended := *b
+			ended.EndedAt = &at
+			m.breakGlass[id] = &ended
+			return ended, nil
+		}
+	}
+	return models.BreakGlass{}, ErrNotFound
+}
+
+// ListBreakGlass returns the organization's elevations, newest first. An
+// empty orgID lists every organization's.
+func (m *Memory) ListBreakGlass(orgID string) []models.BreakGlass {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.BreakGlass{}
+	for _, b := range m.breakGlass {
+		if orgID == "" || b.OrgID == orgID {
+			out = append(out, *b)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].RequestedAt.After(out[j].RequestedAt) })
+	return out
+}
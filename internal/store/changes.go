@@ -0,0 +1,84 @@
+package store
+
+import "github.com/andres20980/aurea-orchestrator/internal/models"
+
+// Kinds of records reported to change listeners.
+const (
+	KindReview = "review"
+	KindOrg    = "org"
+	KindPolicy = "policy"
+)
+
+// Change identifies a record that was created, modified or deleted.
+type Change struct {
+	Kind    string
+	ID      string
+	Deleted bool
+}
+
+// OnChange registers fn to be called after reviews, organizations or
+// policies change. fn runs while the store is locked, so it must not call
+// back into the store; queue the change and read the record later instead.
+// Listeners must be registered before the store is shared.
+func (m *Memory) OnChange(fn func(Change)) {
+	m.listeners = append(m.listeners, fn)
+}
+
+func (m *Memory) changed(c Change) {
+	for _, fn := range m.listeners {
+		fn(c)
+	}
+}
+
+// PutReplicatedReview stores a review received from a peer without
+// notifying change listeners.
+func (m *Memory) PutReplicatedReview(r *models.Review) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, err := m.storedReview(r)
+	if err != nil {
+		return err
+	}
+	m.reviews[r.ID] = stored
+	return nil
+}
+
+// PutReplicatedOrg stores an organization received from a peer without
+// notifying change listeners.
+func (m *Memory) PutReplicatedOrg(org *models.ManagedOrg) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.orgs[org.ID] = &models.Organization{ID: org.ID, Name: org.Name, CreatedBy: org.CreatedBy, CreatedAt: org.CreatedAt, UpdatedAt: org.UpdatedAt}
+	if org.ExternalID != "" {
+		m.orgExtIDs[org.ID] = org.ExternalID
+	} else {
+		delete(m.orgExtIDs, org.ID)
+	}
+	if org.Region != "" {
+		m.orgRegions[org.ID] = org.Region
+	}
+	if org.ParentID != "" {
+		m.orgParents[org.ID] = org.ParentID
+	} else {
+		delete(m.orgParents, org.ID)
+	}
+}
+
+// DeleteReplicatedOrg removes an organization deleted on a peer without
+// notifying change listeners.
+func (m *Memory) DeleteReplicatedOrg(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.orgs, id)
+	delete(m.orgExtIDs, id)
+	delete(m.orgRegions, id)
+	m.detachOrg(id)
+}
+
+// PutReplicatedPolicy stores a policy received from a peer without
+// notifying change listeners.
+func (m *Memory) PutReplicatedPolicy(p models.OrgPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policies[p.OrgID] = p
+}
@@ -0,0 +1,43 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// SaveComplianceReport records an organization's compliance bundle for a
+// quarter, replacing an earlier one.
+func (m *Memory) SaveComplianceReport(r models.ComplianceReport) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.compliance[r.OrgID] == nil {
+		m.compliance[r.OrgID] = make(map[string]models.ComplianceReport)
+	}
+	m.compliance[r.OrgID][r.Quarter] = r
+}
+
+// GetComplianceReport returns an organization's compliance bundle for a
+// quarter.
+func (m *Memory) GetComplianceReport(orgID, quarter string) (models.ComplianceReport, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.compliance[orgID][quarter]
+	if !ok {
+		return models.ComplianceReport{}, ErrNotFound
+	}
+	return r, nil
+}
+
+// ListComplianceReports returns an organization's compliance bundles,
+// latest quarter first.
+func (m *Memory) ListComplianceReports(orgID string) []models.ComplianceReport {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.ComplianceReport{}
+	for _, r := range m.compliance[orgID] {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Quarter > out[j].Quarter })
+	return out
+}
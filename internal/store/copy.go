@@ -0,0 +1,111 @@
+package store
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// The store keeps its own copies of the records written to it and hands
+// out copies when they are read, so callers may change what they hold
+// without racing with other requests or reaching the stored record. The
+// records are only ever replaced, never changed in place, under the write
+// lock.
+
+// copyOf returns a copy of *p, or nil. It suits records whose only
+// reference fields are replaced rather than changed.
+func copyOf[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	return &c
+}
+
+// copyAll copies each record of s.
+func copyAll[T any](s []*T) []*T {
+	if s == nil {
+		return nil
+	}
+	out := make([]*T, len(s))
+	for i, p := range s {
+		out[i] = copyOf(p)
+	}
+	return out
+}
+
+func copyUser(u *models.User) *models.User {
+	c := *u
+	return &c
+}
+
+func copyOrg(org *models.Organization) *models.Organization {
+	c := *org
+	return &c
+}
+
+// copyAudit copies e, including its metadata.
+func copyAudit(e *models.AuditEvent) *models.AuditEvent {
+	c := *e
+	c.Metadata = maps.Clone(e.Metadata)
+	return &c
+}
+
+func copyNotification(n *models.Notification) *models.Notification {
+	c := *n
+	return &c
+}
+
+// copyApproval copies a, including its proof.
+func copyApproval(a *models.Approval) *models.Approval {
+	if a == nil {
+		return nil
+	}
+	c := *a
+	c.Proof = copyOf(a.Proof)
+	return &c
+}
+
+// copyWebhook copies w, including its event list.
+func copyWebhook(w *models.Webhook) *models.Webhook {
+	c := *w
+	c.Events = slices.Clone(w.Events)
+	return &c
+}
+
+// copyDevice copies d, including its event list.
+func copyDevice(d *models.Device) *models.Device {
+	c := *d
+	c.Events = slices.Clone(d.Events)
+	return &c
+}
+
+// copyCustomField copies f, including its options.
+func copyCustomField(f *models.CustomField) *models.CustomField {
+	c := *f
+	c.Options = slices.Clone(f.Options)
+	return &c
+}
+
+// copyOAuthClient copies c, including its redirect URIs and scopes.
+func copyOAuthClient(c *models.OAuthClient) *models.OAuthClient {
+	out := *c
+	out.RedirectURIs = slices.Clone(c.RedirectURIs)
+	out.Scopes = slices.Clone(c.Scopes)
+	return &out
+}
+
+// copyOAuthCode copies c, including the scopes of its grant.
+func copyOAuthCode(c *models.OAuthCode) *models.OAuthCode {
+	out := *c
+	out.Grant.Scopes = slices.Clone(c.Grant.Scopes)
+	return &out
+}
+
+// copyOAuthToken copies t, including the scopes of its grant.
+func copyOAuthToken(t *models.OAuthToken) *models.OAuthToken {
+	out := *t
+	out.Grant.Scopes = slices.Clone(t.Grant.Scopes)
+	return &out
+}
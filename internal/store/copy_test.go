@@ -0,0 +1,215 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// TestRecordsAreCopied changes a record after writing it and after
+// reading it, and checks that the stored record is unchanged.
+func TestRecordsAreCopied(t *testing.T) {
+	m := NewMemory()
+	m.SaveReview(&models.Review{ID: "r1", OrgID: "acme", Title: "Plan"})
+
+	tests := []struct {
+		name  string
+		write func() *string
+		read  func() *string
+	}{
+		{"review", func() *string {
+			r := &models.Review{ID: "r2", OrgID: "acme", Title: "stored"}
+			m.SaveReview(r)
+			return &r.Title
+		}, func() *string { r, _ := m.GetReview("r2"); return &r.Title }},
+		{"approval", func() *string {
+			a := &models.Approval{ReviewID: "r1", ApprovedBy: "stored", Proof: &models.ApprovalProof{Signature: "stored"}}
+			m.SaveApproval(a)
+			return &a.Proof.Signature
+		}, func() *string { a, _ := m.GetApproval("r1"); return &a.Proof.Signature }},
+		{"assignment", func() *string {
+			a := &models.Assignment{ReviewID: "r1", ReviewerID: "stored"}
+			m.Assign(a)
+			return &a.ReviewerID
+		}, func() *string { a, _ := m.GetAssignment("r1"); return &a.ReviewerID }},
+		{"subscription", func() *string {
+			s := &models.Subscription{UserID: "u1", Label: "stored"}
+			m.AddSubscription(s)
+			return &s.Label
+		}, func() *string { return &m.ListSubscriptions("u1")[0].Label }},
+		{"link", func() *string {
+			l := &models.ExternalLink{ReviewID: "r1", Key: "stored"}
+			m.AddLink(l)
+			return &l.Key
+		}, func() *string { return &m.ListLinks("r1")[0].Key }},
+		{"checklist item", func() *string {
+			items := []*models.ChecklistItem{{ReviewID: "r1", Text: "stored"}}
+			m.SetChecklist("r1", items)
+			return &items[0].Text
+		}, func() *string { return &m.ListChecklist("r1")[0].Text }},
+		{"escalation", func() *string {
+			e := &models.Escalation{ReviewID: "r1", DedupKey: "stored"}
+			m.RecordEscalation(e)
+			return &e.DedupKey
+		}, func() *string { e, _ := m.GetEscalation("r1"); return &e.DedupKey }},
+		{"out of office", func() *string {
+			o := &models.OutOfOffice{UserID: "u1", DelegateID: "stored", End: time.Now()}
+			m.SetOutOfOffice(o)
+			return &o.DelegateID
+		}, func() *string { o, _ := m.GetOutOfOffice("u1"); return &o.DelegateID }},
+		{"role", func() *string {
+			r := &models.Role{OrgID: "acme", Name: "stored"}
+			m.CreateRole(r)
+			return &r.Name
+		}, func() *string { r, _ := m.GetRole("acme", "role-1"); return &r.Name }},
+		{"webhook events", func() *string {
+			w := &models.Webhook{OrgID: "acme", Events: []string{"stored"}}
+			m.CreateWebhook(w)
+			return &w.Events[0]
+		}, func() *string { w, _ := m.GetWebhook("acme", "webhook-1"); return &w.Events[0] }},
+		{"api key", func() *string {
+			k := &models.APIKey{OrgID: "acme", Name: "stored", Hash: "h1"}
+			m.CreateAPIKey(k)
+			return &k.Name
+		}, func() *string { k, _ := m.UseAPIKey("h1", time.Now()); return &k.Name }},
+		{"email approval", func() *string {
+			a := &models.EmailApproval{ID: "ea1", ReviewID: "stored", ExpiresAt: time.Now().Add(time.Hour)}
+			m.SaveEmailApproval(a)
+			return &a.ReviewID
+		}, func() *string { a, _ := m.GetEmailApproval("ea1", time.Now()); return &a.ReviewID }},
+		{"device events", func() *string {
+			d := &models.Device{UserID: "u1", Token: "t1", Events: []string{"stored"}}
+			m.RegisterDevice(d)
+			return &d.Events[0]
+		}, func() *string { return &m.ListDevices("u1")[0].Events[0] }},
+		{"intake form", func() *string {
+			f := &models.IntakeForm{OrgID: "acme", Name: "stored", TokenHash: "h1", Active: true}
+			m.CreateIntakeForm(f)
+			return &f.Name
+		}, func() *string { f, _ := m.FindIntakeForm("h1"); return &f.Name }},
+		{"intake submission", func() *string {
+			s := &models.IntakeSubmission{OrgID: "acme", Title: "stored"}
+			m.AddIntakeSubmission(s)
+			return &s.Title
+		}, func() *string { s, _ := m.GetIntakeSubmission("acme", "submission-1"); return &s.Title }},
+		{"custom field options", func() *string {
+			f := &models.CustomField{OrgID: "acme", Key: "team", Options: []string{"stored"}}
+			m.CreateCustomField(f)
+			return &f.Options[0]
+		}, func() *string { f, _ := m.GetCustomField("acme", "field-1"); return &f.Options[0] }},
+		{"oauth client scopes", func() *string {
+			c := &models.OAuthClient{ID: "app", OrgID: "acme", Scopes: []string{"stored"}}
+			m.CreateOAuthClient(c)
+			return &c.Scopes[0]
+		}, func() *string { c, _ := m.GetOAuthClient("app"); return &c.Scopes[0] }},
+		{"oauth token scopes", func() *string {
+			tok := &models.OAuthToken{Grant: models.OAuthGrant{Scopes: []string{"stored"}}, ExpiresAt: time.Now().Add(time.Hour)}
+			m.SaveOAuthToken("h1", tok)
+			return &tok.Grant.Scopes[0]
+		}, func() *string { tok, _ := m.GetOAuthToken("h1", time.Now()); return &tok.Grant.Scopes[0] }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*tt.write() = "changed after write"
+			if got := *tt.read(); got != "stored" {
+				t.Fatalf("after changing the written record, stored = %q", got)
+			}
+			*tt.read() = "changed after read"
+			if got := *tt.read(); got != "stored" {
+				t.Fatalf("after changing a read record, stored = %q", got)
+			}
+		})
+	}
+}
+
+func TestUpdateLinkStatusReplaces(t *testing.T) {
+	m := NewMemory()
+	l := &models.ExternalLink{ReviewID: "r1", Status: "open"}
+	m.AddLink(l)
+	held, _ := m.GetLink("r1", l.ID)
+	m.UpdateLinkStatus(l.ID, "done", time.Now())
+	if held.Status != "open" {
+		t.Errorf("a link read earlier changed to %q", held.Status)
+	}
+	if got, _ := m.GetLink("r1", l.ID); got.Status != "done" || got.StatusSyncedAt == nil {
+		t.Errorf("status = %q, synced at %v", got.Status, got.StatusSyncedAt)
+	}
+}
+
+// TestUpdatesReplace checks that recording a change replaces the stored
+// record instead of changing one that was handed out earlier.
+func TestUpdatesReplace(t *testing.T) {
+	m := NewMemory()
+	now := time.Now()
+	m.CreateAPIKey(&models.APIKey{OrgID: "acme", Name: "ci", Hash: "h1"})
+	m.SaveEmailApproval(&models.EmailApproval{ID: "ea1", ExpiresAt: now.Add(time.Hour)})
+	m.RegisterDevice(&models.Device{UserID: "u1", Token: "t1"})
+	m.CreateIntakeForm(&models.IntakeForm{OrgID: "acme", TokenHash: "h1", Active: true})
+	m.AddIntakeSubmission(&models.IntakeSubmission{OrgID: "acme", Status: models.IntakePending})
+
+	tests := []struct {
+		name    string
+		held    func() func() bool
+		update  func()
+		updated func() bool
+	}{
+		{"api key use", func() func() bool {
+			k, _ := m.GetAPIKey("acme", "key-1")
+			return func() bool { return k.LastUsedAt == nil }
+		}, func() { m.UseAPIKeyByID("key-1", now) }, func() bool {
+			k, _ := m.GetAPIKey("acme", "key-1")
+			return k.LastUsedAt != nil
+		}},
+		{"api key rename", func() func() bool {
+			k, _ := m.GetAPIKey("acme", "key-1")
+			return func() bool { return k.Name == "ci" }
+		}, func() { m.RenameAPIKey("acme", "key-1", "deploy") }, func() bool {
+			k, _ := m.GetAPIKey("acme", "key-1")
+			return k.Name == "deploy"
+		}},
+		{"email approval use", func() func() bool {
+			a, _ := m.GetEmailApproval("ea1", now)
+			return func() bool { return a.UsedAt == nil }
+		}, func() { m.UseEmailApproval("ea1", "approve", now) }, func() bool {
+			_, err := m.GetEmailApproval("ea1", now)
+			return err == ErrNotFound
+		}},
+		{"device update", func() func() bool {
+			d := m.ListDevices("u1")[0]
+			return func() bool { return !d.Muted && d.LastPushAt == nil }
+		}, func() {
+			m.UpdateDevice("u1", "dev-1", func(d *models.Device) { d.Muted = true })
+			m.TouchDevice("dev-1", now)
+		}, func() bool {
+			d := m.ListDevices("u1")[0]
+			return d.Muted && d.LastPushAt != nil
+		}},
+		{"intake form disable", func() func() bool {
+			f, _ := m.FindIntakeForm("h1")
+			return func() bool { return f.Active }
+		}, func() { m.DisableIntakeForm("acme", "form-1") }, func() bool {
+			_, err := m.FindIntakeForm("h1")
+			return err == ErrNotFound
+		}},
+		{"intake triage", func() func() bool {
+			s, _ := m.GetIntakeSubmission("acme", "submission-1")
+			return func() bool { return s.Status == models.IntakePending }
+		}, func() { m.TriageIntakeSubmission("acme", "submission-1", models.IntakeRejected, "", "spam", "u1", now) }, func() bool {
+			s, _ := m.GetIntakeSubmission("acme", "submission-1")
+			return s.Status == models.IntakeRejected
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unchanged := tt.held()
+			tt.update()
+			if !unchanged() {
+				t.Error("a record read earlier was changed in place")
+			}
+			if !tt.updated() {
+				t.Error("the stored record was not updated")
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// PutDeadLetter records a failed job, assigning its ID. A letter with the
+// same kind and reference as an existing one replaces it, keeping its ID
+// and creation time and adding up the attempts.
+func (m *Memory) PutDeadLetter(l models.DeadLetter) models.DeadLetter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.deadLetters {
+		if existing.Kind == l.Kind && existing.Reference == l.Reference {
+			l.ID, l.CreatedAt = existing.ID, existing.CreatedAt
+			l.Attempts += existing.Attempts
+			m.deadLetters[l.ID] = &l
+			return l
+		}
+	}
+	m.letterSeq++
+	l.ID = fmt.Sprintf("dead-letter-%d", m.letterSeq)
+	m.deadLetters[l.ID] = &l
+	return l
+}
+
+// GetDeadLetter returns a dead letter. An empty orgID matches any
+// organization.
+func (m *Memory) GetDeadLetter(orgID, id string) (models.DeadLetter, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	l, ok := m.deadLetters[id]
+	if !ok || (orgID != "" && l.OrgID != orgID) {
+		return models.DeadLetter{}, ErrNotFound
+	}
+	return *l, nil
+}
+
+// ListDeadLetters returns the dead letters matching f, most recently
+// failed first.
+func (m *Memory) ListDeadLetters(f models.DeadLetterFilter) []models.DeadLetter {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.DeadLetter{}
+	for _, l := range m.deadLetters {
+		if f.Matches(l) {
+			out = append(out, *l)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].FailedAt.After(out[j].FailedAt) })
+	return out
+}
+
+// DeleteDeadLetter removes a dead letter. An empty orgID matches any
+// organization.
+func (m *Memory) DeleteDeadLetter(orgID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l, ok := m.deadLetters[id]
+	if !ok || (orgID != "" && l.OrgID != orgID) {
+		return ErrNotFound
+	}
+	delete(m.deadLetters, id)
+	return nil
+}
+
+// ResolveDeadLetter removes the dead letter of a job that has since
+// succeeded, if there is one.
+func (m *Memory) ResolveDeadLetter(kind, reference string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, l := range m.deadLetters {
+		if l.Kind == kind && l.Reference == reference {
+			delete(m.deadLetters, id)
+		}
+	}
+}
+
+// PurgeDeadLetters removes the dead letters matching f and returns how
+// many were removed. Those of organizations under a legal hold are kept.
+func (m *Memory) PurgeDeadLetters(f models.DeadLetterFilter) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for id, l := range m.deadLetters {
+		if f.Matches(l) && !m.orgHeld(l.OrgID) {
+			delete(m.deadLetters, id)
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// RegisterDevice stores a push device, assigning its ID. Registering a
+// token that is already known moves it to the new user and settings, since
+// a device token identifies one app install.
+func (m *Memory) RegisterDevice(d *models.Device) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, old := range m.devices {
+		if old.Platform == d.Platform && old.Token == d.Token {
+			delete(m.devices, id)
+		}
+	}
+	m.deviceSeq++
+	d.ID = fmt.Sprintf("dev-%d", m.deviceSeq)
+	m.devices[d.ID] = copyDevice(d)
+}
+
+// ListDevices returns a user's devices, oldest first.
+func (m *Memory) ListDevices(userID string) []*models.Device {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []*models.Device{}
+	for _, d := range m.devices {
+		if d.UserID == userID {
+			out = append(out, copyDevice(d))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// UpdateDevice changes the name and preferences of a user's device.
+func (m *Memory) UpdateDevice(userID, id string, update func(*models.Device)) (*models.Device, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.devices[id]
+	if !ok || d.UserID != userID {
+		return nil, ErrNotFound
+	}
+	updated := copyDevice(d)
+	update(updated)
+	m.devices[id] = updated
+	return copyDevice(updated), nil
+}
+
+// RemoveDevice unregisters a user's device.
+func (m *Memory) RemoveDevice(userID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.devices[id]
+	if !ok || d.UserID != userID {
+		return ErrNotFound
+	}
+	delete(m.devices, id)
+	return nil
+}
+
+// ForgetDeviceToken removes the device with a token the push provider
+// reported as no longer valid.
+func (m *Memory) ForgetDeviceToken(platform, token string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, d := range m.devices {
+		if d.Platform == platform && d.Token == token {
+			delete(m.devices, id)
+		}
+	}
+}
+
+// TouchDevice records a successful push to a device.
+func (m *Memory) TouchDevice(id string, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if d, ok := m.devices[id]; ok {
+		touched := *d
+		touched.LastPushAt = &at
+		m.devices[id] = &touched
+	}
+}
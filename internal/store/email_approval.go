@@ -0,0 +1,43 @@
+package store
+
+import (
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// SaveEmailApproval stores an emailed approval request.
+func (m *Memory) SaveEmailApproval(a *models.EmailApproval) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mailAsks[a.ID] = copyOf(a)
+}
+
+// GetEmailApproval returns an emailed approval request that has neither
+// been used nor expired at now.
+func (m *Memory) GetEmailApproval(id string, now time.Time) (*models.EmailApproval, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, ok := m.mailAsks[id]
+	if !ok || a.UsedAt != nil || !now.Before(a.ExpiresAt) {
+		return nil, ErrNotFound
+	}
+	return copyOf(a), nil
+}
+
+// UseEmailApproval marks an emailed approval request as used for action.
+// A request can be used once: ErrNotFound is returned if it is unknown,
+// expired or already used.
+func (m *Memory) UseEmailApproval(id, action string, now time.Time) (*models.EmailApproval, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.mailAsks[id]
+	if !ok || a.UsedAt != nil || !now.Before(a.ExpiresAt) {
+		return nil, ErrNotFound
+	}
+	used := *a
+	used.UsedAt = &now
+	used.Action = action
+	m.mailAsks[id] = &used
+	return copyOf(&used), nil
+}
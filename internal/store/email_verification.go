@@ -0,0 +1,123 @@
+package store
+
+import (
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// SaveEmailVerification stores a pending verification by token hash. It
+// replaces the user's earlier verification of the same purpose, so only the
+// latest link works, and drops expired ones.
+func (m *Memory) SaveEmailVerification(hash string, v models.EmailVerification) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for h, existing := range m.emailVerifs {
+		if now.After(existing.ExpiresAt) || (existing.UserID == v.UserID && existing.Purpose == v.Purpose) {
+			delete(m.emailVerifs, h)
+		}
+	}
+	m.emailVerifs[hash] = v
+}
+
+// TakeEmailVerification removes and returns an unexpired verification, so
+// each link can be used once.
+func (m *Memory) TakeEmailVerification(hash string, now time.Time) (models.EmailVerification, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.emailVerifs[hash]
+	if !ok {
+		return models.EmailVerification{}, ErrNotFound
+	}
+	delete(m.emailVerifs, hash)
+	if now.After(v.ExpiresAt) {
+		return models.EmailVerification{}, ErrNotFound
+	}
+	return v, nil
+}
+
+// PendingEmailVerification returns the user's unexpired verification of a
+// purpose, if any.
+func (m *Memory) PendingEmailVerification(userID, purpose string, now time.Time) (models.EmailVerification, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, v := range m.emailVerifs {
+		if v.UserID == userID && v.Purpose == purpose && now.Before(v.ExpiresAt) {
+			return v, true
+		}
+	}
+	return models.EmailVerification{}, false
+}
+
+// SetEmailVerified records whether the user has confirmed their address.
+// Users are verified unless marked otherwise at sign-up.
+func (m *Memory) SetEmailVerified(userID string, verified bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if verified {
+		delete(m.unverified, userID)
+		return
+	}
+	m.unverified[userID] = true
+}
+
+// EmailVerified reports whether the user has confirmed their address.
+func (m *Memory) EmailVerified(userID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return !m.unverified[userID]
+}
+
+// SetPendingMembership records the organization an unverified account
+// joins once ConfirmSignup is called for it.
+func (m *Memory) SetPendingMembership(userID string, p models.PendingMembership) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pendingJoin[userID] = p
+}
+
+// ConfirmSignup marks a new account's address verified and moves it into
+// the organization it signed up to. It returns ErrNotFound if the user or
+// that organization no longer exists.
+func (m *Memory) ConfirmSignup(userID string) (*models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.users[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if p, pending := m.pendingJoin[userID]; pending {
+		if _, ok := m.orgs[p.OrgID]; !ok {
+			return nil, ErrNotFound
+		}
+		joined := *u
+		joined.OrgID, joined.Role, joined.UpdatedAt = p.OrgID, p.Role, time.Now()
+		m.users[userID] = &joined
+		u = &joined
+		delete(m.pendingJoin, userID)
+	}
+	delete(m.unverified, userID)
+	return copyUser(u), nil
+}
+
+// ChangeUserEmail sets a user's email address. It returns ErrDuplicate if
+// another user has it.
+func (m *Memory) ChangeUserEmail(userID, email string) (*models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.users[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	for id, other := range m.users {
+		if id != userID && strings.EqualFold(other.Email, email) {
+			return nil, ErrDuplicate
+		}
+	}
+	changed := *u
+	changed.Email, changed.UpdatedAt = email, time.Now()
+	m.users[userID] = &changed
+	return copyUser(&changed), nil
+}
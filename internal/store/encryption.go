@@ -0,0 +1,197 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// ErrFieldCipher is returned when a sensitive field cannot be encrypted or
+// decrypted. The write or read fails rather than storing plaintext or
+// handing out an empty value that a later update would save.
+var ErrFieldCipher = errors.New("store: field encryption failed")
+
+// FieldCipher encrypts sensitive fields at rest.
+type FieldCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(value string) (string, error)
+	// Stale reports whether value should be re-encrypted with the active key.
+	Stale(value string) bool
+}
+
+// SetFieldCipher enables encryption of review content, comment bodies and
+// attachment names and content types.
+// It must be called before the store is shared. Values stored earlier stay
+// readable and are encrypted by RotateEncryption.
+func (m *Memory) SetFieldCipher(c FieldCipher) {
+	m.cipher = c
+}
+
+// RotateEncryption re-encrypts every sensitive field that is plaintext or
+// sealed with a retired master key, and returns how many were rewritten.
+func (m *Memory) RotateEncryption() (int, error) {
+	if m.cipher == nil {
+		return 0, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	rewrap := func(value *string) error {
+		if !m.cipher.Stale(*value) {
+			return nil
+		}
+		plain, err := m.cipher.Decrypt(*value)
+		if err != nil {
+			return err
+		}
+		sealed, err := m.cipher.Encrypt(plain)
+		if err != nil {
+			return err
+		}
+		*value = sealed
+		n++
+		return nil
+	}
+	for _, r := range m.reviews {
+		if err := rewrap(&r.Content); err != nil {
+			return n, err
+		}
+	}
+	for _, comments := range m.comments {
+		for _, c := range comments {
+			if err := rewrap(&c.Body); err != nil {
+				return n, err
+			}
+		}
+	}
+	for _, a := range m.attachments {
+		if err := rewrap(&a.Name); err != nil {
+			return n, err
+		}
+		if err := rewrap(&a.ContentType); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func (m *Memory) seal(value string) (string, error) {
+	if m.cipher == nil {
+		return value, nil
+	}
+	sealed, err := m.cipher.Encrypt(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrFieldCipher, err)
+	}
+	return sealed, nil
+}
+
+func (m *Memory) open(value string) (string, error) {
+	if m.cipher == nil {
+		return value, nil
+	}
+	plain, err := m.cipher.Decrypt(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrFieldCipher, err)
+	}
+	return plain, nil
+}
+
+// storedReview returns the copy of r kept in the store, with CreatedBy set
+// from AuthorID. Callers keep r and may change it without reaching the
+// stored review.
+func (m *Memory) storedReview(r *models.Review) (*models.Review, error) {
+	stored := *r
+	stored.CreatedBy = r.AuthorID
+	var err error
+	if stored.Content, err = m.seal(r.Content); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// readReview returns a copy of r as seen by callers.
+func (m *Memory) readReview(r *models.Review) (*models.Review, error) {
+	out := *r
+	var err error
+	if out.Content, err = m.open(r.Content); err != nil {
+		return nil, fmt.Errorf("review %s: %w", r.ID, err)
+	}
+	return &out, nil
+}
+
+// listedReview is readReview for listings, which have no error to return:
+// a review that cannot be decrypted is logged and left out.
+func (m *Memory) listedReview(r *models.Review) (*models.Review, bool) {
+	out, err := m.readReview(r)
+	if err != nil {
+		log.Printf("Leaving a review out of a listing: %v", err)
+		return nil, false
+	}
+	return out, true
+}
+
+func (m *Memory) storedComment(c *models.Comment) (*models.Comment, error) {
+	stored := *c
+	var err error
+	if stored.Body, err = m.seal(c.Body); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func (m *Memory) readComment(c *models.Comment) (*models.Comment, error) {
+	out := *c
+	var err error
+	if out.Body, err = m.open(c.Body); err != nil {
+		return nil, fmt.Errorf("comment %s: %w", c.ID, err)
+	}
+	return &out, nil
+}
+
+// listedComment is readComment for listings.
+func (m *Memory) listedComment(c *models.Comment) (*models.Comment, bool) {
+	out, err := m.readComment(c)
+	if err != nil {
+		log.Printf("Leaving a comment out of a listing: %v", err)
+		return nil, false
+	}
+	return out, true
+}
+
+// storedAttachment seals the attachment's name and content type, which
+// can say as much about a review as its content.
+func (m *Memory) storedAttachment(a models.Attachment) (*models.Attachment, error) {
+	var err error
+	if a.Name, err = m.seal(a.Name); err != nil {
+		return nil, err
+	}
+	if a.ContentType, err = m.seal(a.ContentType); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (m *Memory) readAttachment(a *models.Attachment) (models.Attachment, error) {
+	out := *a
+	var err error
+	if out.Name, err = m.open(a.Name); err != nil {
+		return models.Attachment{}, fmt.Errorf("attachment %s: %w", a.ID, err)
+	}
+	if out.ContentType, err = m.open(a.ContentType); err != nil {
+		return models.Attachment{}, fmt.Errorf("attachment %s: %w", a.ID, err)
+	}
+	return out, nil
+}
+
+// listedAttachment is readAttachment for listings.
+func (m *Memory) listedAttachment(a *models.Attachment) (models.Attachment, bool) {
+	out, err := m.readAttachment(a)
+	if err != nil {
+		log.Printf("Leaving an attachment out of a listing: %v", err)
+		return models.Attachment{}, false
+	}
+	return out, true
+}
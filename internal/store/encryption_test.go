@@ -0,0 +1,116 @@
+package store
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// prefixCipher marks sealed values with a prefix and fails on demand.
+type prefixCipher struct {
+	failEncrypt, failDecrypt bool
+}
+
+func (c *prefixCipher) Encrypt(plaintext string) (string, error) {
+	if c.failEncrypt {
+		return "", errors.New("key unavailable")
+	}
+	return "sealed:" + plaintext, nil
+}
+
+func (c *prefixCipher) Decrypt(value string) (string, error) {
+	if c.failDecrypt {
+		return "", errors.New("key unavailable")
+	}
+	return strings.TrimPrefix(value, "sealed:"), nil
+}
+
+func (c *prefixCipher) Stale(value string) bool { return !strings.HasPrefix(value, "sealed:") }
+
+func TestFieldCipherFailures(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(m *Memory) error
+		read  func(m *Memory) error
+	}{
+		{
+			name: "review",
+			write: func(m *Memory) error {
+				return m.SaveReview(&models.Review{ID: "r1", OrgID: "acme", Content: "launch plan"})
+			},
+			read: func(m *Memory) error {
+				if n := len(m.ListOrgReviews("acme")); n != 0 {
+					t.Errorf("listed %d reviews", n)
+				}
+				_, err := m.GetReview("r1")
+				return err
+			},
+		},
+		{
+			name:  "comment",
+			write: func(m *Memory) error { return m.AddComment(&models.Comment{ID: "c1", ReviewID: "r1", Body: "ship it"}) },
+			read: func(m *Memory) error {
+				if n := len(m.ListComments("r1")); n != 0 {
+					t.Errorf("listed %d comments", n)
+				}
+				_, err := m.GetComment("r1", "c1")
+				return err
+			},
+		},
+		{
+			name: "attachment",
+			write: func(m *Memory) error {
+				_, err := m.AddAttachment(models.Attachment{ReviewID: "r1", Name: "layoffs.xlsx", ContentType: "application/vnd.ms-excel"})
+				return err
+			},
+			read: func(m *Memory) error {
+				if n := len(m.ListAttachments("r1")); n != 0 {
+					t.Errorf("listed %d attachments", n)
+				}
+				_, err := m.GetAttachment("r1", "attachment-1")
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" write", func(t *testing.T) {
+			m := NewMemory()
+			m.SetFieldCipher(&prefixCipher{failEncrypt: true})
+			if err := tt.write(m); !errors.Is(err, ErrFieldCipher) {
+				t.Fatalf("write error = %v, want ErrFieldCipher", err)
+			}
+			if len(m.reviews)+len(m.comments)+len(m.attachments) != 0 {
+				t.Error("a record was stored without its sensitive fields sealed")
+			}
+		})
+		t.Run(tt.name+" read", func(t *testing.T) {
+			m := NewMemory()
+			cipher := &prefixCipher{}
+			m.SetFieldCipher(cipher)
+			if err := tt.write(m); err != nil {
+				t.Fatal(err)
+			}
+			cipher.failDecrypt = true
+			if err := tt.read(m); !errors.Is(err, ErrFieldCipher) {
+				t.Fatalf("read error = %v, want ErrFieldCipher", err)
+			}
+		})
+	}
+}
+
+func TestAttachmentMetadataIsSealed(t *testing.T) {
+	m := NewMemory()
+	m.SetFieldCipher(&prefixCipher{})
+	a, err := m.AddAttachment(models.Attachment{ReviewID: "r1", Name: "layoffs.xlsx", ContentType: "application/vnd.ms-excel"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored := m.attachments[a.ID]; stored.Name != "sealed:layoffs.xlsx" || stored.ContentType != "sealed:application/vnd.ms-excel" {
+		t.Errorf("stored name %q, content type %q", stored.Name, stored.ContentType)
+	}
+	if got, _ := m.GetAttachment("r1", a.ID); got.Name != "layoffs.xlsx" || got.ContentType != "application/vnd.ms-excel" {
+		t.Errorf("read name %q, content type %q", got.Name, got.ContentType)
+	}
+}
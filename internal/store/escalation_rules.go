@@ -0,0 +1,130 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// CreateEscalationRule stores a new rule, assigning its ID.
+func (m *Memory) CreateEscalationRule(r models.EscalationRule) models.EscalationRule {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.escRuleSeq++
+	r.ID = fmt.Sprintf("escrule-%d", m.escRuleSeq)
+	m.escRules[r.ID] = &r
+	return r
+}
+
+// GetEscalationRule returns a rule of the organization.
+func (m *Memory) GetEscalationRule(orgID, id string) (models.EscalationRule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.escRules[id]
+	if !ok || r.OrgID != orgID {
+		return models.EscalationRule{}, ErrNotFound
+	}
+	return *r, nil
+}
+
+// ListEscalationRules returns the organization's rules, or every
+// organization's when orgID is empty, shortest delay first.
+func (m *Memory) ListEscalationRules(orgID string) []models.EscalationRule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.EscalationRule{}
+	for _, r := range m.escRules {
+		if orgID == "" || r.OrgID == orgID {
+			out = append(out, *r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].AfterHours != out[j].AfterHours {
+			return out[i].AfterHours < out[j].AfterHours
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// UpdateEscalationRule replaces a rule of the organization.
+func (m *Memory) UpdateEscalationRule(r models.EscalationRule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cur, ok := m.escRules[r.ID]; !ok || cur.OrgID != r.OrgID {
+		return ErrNotFound
+	}
+	m.escRules[r.ID] = &r
+	return nil
+}
+
+// DeleteEscalationRule removes a rule of the organization. Its past steps
+// stay in the reviews' history.
+func (m *Memory) DeleteEscalationRule(orgID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if r, ok := m.escRules[id]; !ok || r.OrgID != orgID {
+		return ErrNotFound
+	}
+	delete(m.escRules, id)
+	return nil
+}
+
+// AddEscalationStep appends a step to a review's escalation history.
+func (m *Memory) AddEscalationStep(s models.EscalationStep) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.escHistory[s.ReviewID] = append(m.escHistory[s.ReviewID], s)
+}
+
+// EscalationHistory returns the steps taken on a review, oldest first.
+func (m *Memory) EscalationHistory(reviewID string) []models.EscalationStep {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]models.EscalationStep{}, m.escHistory[reviewID]...)
+}
+
+// LastActivity returns when a review last saw activity: its creation or
+// last update, its assignment, or its latest comment.
+func (m *Memory) LastActivity(reviewID string) (time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.reviews[reviewID]
+	if !ok {
+		return time.Time{}, ErrNotFound
+	}
+	last := r.CreatedAt
+	if r.UpdatedAt.After(last) {
+		last = r.UpdatedAt
+	}
+	if a := m.assignments[reviewID]; a != nil && a.AssignedAt.After(last) {
+		last = a.AssignedAt
+	}
+	for _, c := range m.comments[reviewID] {
+		if c.CreatedAt.After(last) {
+			last = c.CreatedAt
+		}
+	}
+	return last, nil
+}
+
+// SetManager records who a user reports to; an empty managerID clears it.
+func (m *Memory) SetManager(userID, managerID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if managerID == "" {
+		delete(m.managers, userID)
+		return
+	}
+	m.managers[userID] = managerID
+}
+
+// GetManager returns who a user reports to.
+func (m *Memory) GetManager(userID string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	id, ok := m.managers[userID]
+	return id, ok
+}
@@ -0,0 +1,19 @@
+package store
+
+// SetExpertise replaces the expertise areas a user is tagged with.
+func (m *Memory) SetExpertise(userID string, areas []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(areas) == 0 {
+		delete(m.expertise, userID)
+		return
+	}
+	m.expertise[userID] = append([]string(nil), areas...)
+}
+
+// GetExpertise returns the expertise areas a user is tagged with.
+func (m *Memory) GetExpertise(userID string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]string{}, m.expertise[userID]...)
+}
@@ -0,0 +1,40 @@
+package store
+
+import "github.com/andres20980/aurea-orchestrator/internal/models"
+
+// AuditSeq returns how many audit events have been recorded across all
+// organizations. Audit events are only ever appended, and truncated ones
+// keep their place, so it marks a point in the log that later events do
+// not change.
+func (m *Memory) AuditSeq() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.audit)
+}
+
+// AuditVersion returns how many of an organization's audit events
+// retention has truncated. The organization's log up to a position reads
+// the same for as long as its version does.
+func (m *Memory) AuditVersion(orgID string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.auditPurged[orgID]
+}
+
+// AuditPage returns up to limit of an organization's audit events among
+// those recorded after position after and up to position until, oldest
+// first, with the position to continue from. Reading a long log in pages
+// keeps the lock free between them.
+func (m *Memory) AuditPage(orgID string, after, until, limit int) ([]models.AuditEvent, int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	until = min(until, len(m.audit))
+	var events []models.AuditEvent
+	i := after
+	for ; i < until && len(events) < limit; i++ {
+		if e := m.audit[i]; e.OrgID == orgID {
+			events = append(events, *e)
+		}
+	}
+	return events, i
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// CreateCustomField stores a new field definition, assigning its ID. Keys
+// are unique within an organization.
+func (m *Memory) CreateCustomField(f *models.CustomField) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, other := range m.fields {
+		if other.OrgID == f.OrgID && other.Key == f.Key {
+			return ErrDuplicate
+		}
+	}
+	m.fieldSeq++
+	f.ID = fmt.Sprintf("field-%d", m.fieldSeq)
+	m.fields[f.ID] = copyCustomField(f)
+	return nil
+}
+
+// GetCustomField returns a field definition of the organization.
+func (m *Memory) GetCustomField(orgID, id string) (*models.CustomField, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	f, ok := m.fields[id]
+	if !ok || f.OrgID != orgID {
+		return nil, ErrNotFound
+	}
+	return copyCustomField(f), nil
+}
+
+// ListCustomFields returns the organization's field definitions by key.
+func (m *Memory) ListCustomFields(orgID string) []*models.CustomField {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []*models.CustomField{}
+	for _, f := range m.fields {
+		if f.OrgID == orgID {
+			out = append(out, copyCustomField(f))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// UpdateCustomField replaces a field definition. The key cannot change, so
+// stored values stay attached to it.
+func (m *Memory) UpdateCustomField(f *models.CustomField) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.fields[f.ID]
+	if !ok || existing.OrgID != f.OrgID {
+		return ErrNotFound
+	}
+	f.Key = existing.Key
+	f.CreatedAt = existing.CreatedAt
+	m.fields[f.ID] = copyCustomField(f)
+	return nil
+}
+
+// DeleteCustomField removes a field definition and its values.
+func (m *Memory) DeleteCustomField(orgID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.fields[id]
+	if !ok || f.OrgID != orgID {
+		return ErrNotFound
+	}
+	delete(m.fields, id)
+	for reviewID, values := range m.fieldValues {
+		if r, ok := m.reviews[reviewID]; ok && r.OrgID == orgID {
+			delete(values, f.Key)
+		}
+	}
+	return nil
+}
+
+// GetFieldValues returns a copy of the custom field values of a review.
+func (m *Memory) GetFieldValues(reviewID string) map[string]interface{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	values := make(map[string]interface{}, len(m.fieldValues[reviewID]))
+	for k, v := range m.fieldValues[reviewID] {
+		values[k] = v
+	}
+	return values
+}
+
+// SetFieldValues replaces the custom field values of a review.
+func (m *Memory) SetFieldValues(reviewID string, values map[string]interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fieldValues[reviewID] = values
+}
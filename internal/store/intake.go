@@ -0,0 +1,116 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// CreateIntakeForm stores a new intake form, assigning its ID.
+func (m *Memory) CreateIntakeForm(f *models.IntakeForm) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.intakeSeq++
+	f.ID = fmt.Sprintf("form-%d", m.intakeSeq)
+	m.intakeForms[f.ID] = copyOf(f)
+}
+
+// ListIntakeForms returns the organization's intake forms, oldest first.
+func (m *Memory) ListIntakeForms(orgID string) []*models.IntakeForm {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []*models.IntakeForm{}
+	for _, f := range m.intakeForms {
+		if f.OrgID == orgID {
+			out = append(out, copyOf(f))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// FindIntakeForm returns the active intake form with the given token hash.
+func (m *Memory) FindIntakeForm(tokenHash string) (*models.IntakeForm, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, f := range m.intakeForms {
+		if f.TokenHash == tokenHash && f.Active {
+			return copyOf(f), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// DisableIntakeForm stops an intake form from accepting submissions.
+// Submissions already received stay in the triage queue.
+func (m *Memory) DisableIntakeForm(orgID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.intakeForms[id]
+	if !ok || f.OrgID != orgID {
+		return ErrNotFound
+	}
+	disabled := *f
+	disabled.Active = false
+	m.intakeForms[id] = &disabled
+	return nil
+}
+
+// AddIntakeSubmission stores a submission, assigning its ID.
+func (m *Memory) AddIntakeSubmission(s *models.IntakeSubmission) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.submitSeq++
+	s.ID = fmt.Sprintf("submission-%d", m.submitSeq)
+	m.submissions[s.ID] = copyOf(s)
+}
+
+// GetIntakeSubmission returns a submission to the organization.
+func (m *Memory) GetIntakeSubmission(orgID, id string) (*models.IntakeSubmission, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.submissions[id]
+	if !ok || s.OrgID != orgID {
+		return nil, ErrNotFound
+	}
+	return copyOf(s), nil
+}
+
+// ListIntakeSubmissions returns the organization's submissions, oldest
+// first, optionally only those with the given status.
+func (m *Memory) ListIntakeSubmissions(orgID, status string) []*models.IntakeSubmission {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []*models.IntakeSubmission{}
+	for _, s := range m.submissions {
+		if s.OrgID == orgID && (status == "" || s.Status == status) {
+			out = append(out, copyOf(s))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// TriageIntakeSubmission records the decision on a pending submission.
+// It returns ErrDuplicate if the submission was already triaged.
+func (m *Memory) TriageIntakeSubmission(orgID, id, status, reviewID, reason, by string, at time.Time) (*models.IntakeSubmission, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.submissions[id]
+	if !ok || s.OrgID != orgID {
+		return nil, ErrNotFound
+	}
+	if s.Status != models.IntakePending {
+		return nil, ErrDuplicate
+	}
+	triaged := *s
+	triaged.Status = status
+	triaged.ReviewID = reviewID
+	triaged.Reason = reason
+	triaged.TriagedBy = by
+	triaged.TriagedAt = &at
+	m.submissions[id] = &triaged
+	return copyOf(&triaged), nil
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+func killSwitchKey(capability, orgID string) string {
+	return capability + "|" + orgID
+}
+
+// SetKillSwitch engages a kill switch, replacing any on the same
+// capability and scope.
+func (m *Memory) SetKillSwitch(k models.KillSwitch) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.switches[killSwitchKey(k.Capability, k.OrgID)] = k
+}
+
+// DeleteKillSwitch lifts a kill switch. An empty orgID lifts the
+// instance-wide switch.
+func (m *Memory) DeleteKillSwitch(capability, orgID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := killSwitchKey(capability, orgID)
+	if _, ok := m.switches[key]; !ok {
+		return ErrNotFound
+	}
+	delete(m.switches, key)
+	return nil
+}
+
+// ListKillSwitches returns the engaged kill switches, instance-wide first.
+// A non-empty orgID lists the instance-wide switches and that
+// organization's.
+func (m *Memory) ListKillSwitches(orgID string) []models.KillSwitch {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.KillSwitch{}
+	for _, k := range m.switches {
+		if orgID == "" || k.OrgID == "" || k.OrgID == orgID {
+			out = append(out, k)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].OrgID != out[j].OrgID {
+			return out[i].OrgID < out[j].OrgID
+		}
+		return out[i].Capability < out[j].Capability
+	})
+	return out
+}
+
+// KillSwitch returns the switch disabling capability for the organization:
+// the instance-wide switch if engaged, otherwise the organization's.
+func (m *Memory) KillSwitch(capability, orgID string) (models.KillSwitch, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if k, ok := m.switches[killSwitchKey(capability, "")]; ok {
+		return k, true
+	}
+	if orgID == "" {
+		return models.KillSwitch{}, false
+	}
+	k, ok := m.switches[killSwitchKey(capability, orgID)]
+	return k, ok
+}
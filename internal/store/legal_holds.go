@@ -0,0 +1,127 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// ErrLegalHold is returned when deleting data under a legal hold.
+var ErrLegalHold = errors.New("under legal hold")
+
+// PlaceLegalHold records a hold, assigning its ID. It returns ErrNotFound
+// if the organization or the review does not exist, and ErrDuplicate if
+// the same data already has a hold in effect.
+func (m *Memory) PlaceLegalHold(h models.LegalHold) (models.LegalHold, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.orgs[h.OrgID]; !ok {
+		return models.LegalHold{}, ErrNotFound
+	}
+	if h.ReviewID != "" {
+		if r, ok := m.reviews[h.ReviewID]; !ok || r.OrgID != h.OrgID {
+			return models.LegalHold{}, ErrNotFound
+		}
+	}
+	for _, existing := range m.holds {
+		if existing.Active() && existing.OrgID == h.OrgID && existing.ReviewID == h.ReviewID {
+			return models.LegalHold{}, ErrDuplicate
+		}
+	}
+	m.holdSeq++
+	h.ID = fmt.Sprintf("hold-%d", m.holdSeq)
+	m.holds[h.ID] = &h
+	return h, nil
+}
+
+// GetLegalHold returns a hold by ID.
+func (m *Memory) GetLegalHold(id string) (models.LegalHold, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	h, ok := m.holds[id]
+	if !ok {
+		return models.LegalHold{}, ErrNotFound
+	}
+	return *h, nil
+}
+
+// ReleaseLegalHold ends a hold in effect. Released holds are kept as a
+// record.
+func (m *Memory) ReleaseLegalHold(id, releasedBy, reason string, at time.Time) (models.LegalHold, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.holds[id]
+	if !ok || !h.Active() {
+		return models.LegalHold{}, ErrNotFound
+	}
+	released := *h
+	released.ReleasedBy, released.ReleasedAt, released.ReleaseReason = releasedBy, &at, reason
+	m.holds[id] = &released
+	return released, nil
+}
+
+// ListLegalHolds returns the organization's holds, newest first. An empty
+// orgID lists every organization's.
+func (m *Memory) ListLegalHolds(orgID string) []models.LegalHold {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.LegalHold{}
+	for _, h := range m.holds {
+		if orgID == "" || h.OrgID == orgID {
+			out = append(out, *h)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PlacedAt.After(out[j].PlacedAt) })
+	return out
+}
+
+// OrgOnHold reports whether the whole organization is under a hold.
+func (m *Memory) OrgOnHold(orgID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.orgHeld(orgID)
+}
+
+// ReviewOnHold reports whether the review is under a hold, its own or its
+// organization's.
+func (m *Memory) ReviewOnHold(reviewID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.reviewHeld(reviewID)
+}
+
+func (m *Memory) orgHeld(orgID string) bool {
+	for _, h := range m.holds {
+		if h.Active() && h.OrgID == orgID && h.ReviewID == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Memory) reviewHeld(reviewID string) bool {
+	r, ok := m.reviews[reviewID]
+	if ok && m.orgHeld(r.OrgID) {
+		return true
+	}
+	for _, h := range m.holds {
+		if h.Active() && h.ReviewID == reviewID {
+			return true
+		}
+	}
+	return false
+}
+
+// anyHeld reports whether the organization or any of its reviews is under
+// a hold.
+func (m *Memory) anyHeld(orgID string) bool {
+	for _, h := range m.holds {
+		if h.Active() && h.OrgID == orgID {
+			return true
+		}
+	}
+	return false
+}
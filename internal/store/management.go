@@ -0,0 +1,477 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// CreateOrg stores a new organization with an optional external ID. It
+// returns ErrDuplicate if the ID or external ID is already in use.
+func (m *Memory) CreateOrg(org *models.Organization, externalID, region string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.orgs[org.ID]; ok {
+		return ErrDuplicate
+	}
+	if externalID != "" && m.orgByExternalID(externalID) != "" {
+		return ErrDuplicate
+	}
+	if org.UpdatedAt.IsZero() {
+		org.UpdatedAt = org.CreatedAt
+	}
+	m.orgs[org.ID] = copyOrg(org)
+	if externalID != "" {
+		m.orgExtIDs[org.ID] = externalID
+	}
+	if region != "" {
+		m.orgRegions[org.ID] = region
+	}
+	m.changed(Change{Kind: KindOrg, ID: org.ID})
+	return nil
+}
+
+// GetManagedOrg returns the management view of an organization.
+func (m *Memory) GetManagedOrg(id string) (*models.ManagedOrg, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.managedOrg(id)
+}
+
+// FindOrgByExternalID returns the organization with the given external ID.
+func (m *Memory) FindOrgByExternalID(externalID string) (*models.ManagedOrg, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	id := m.orgByExternalID(externalID)
+	if id == "" {
+		return nil, ErrNotFound
+	}
+	return m.managedOrg(id)
+}
+
+// UpdateOrg renames an organization and replaces its external ID.
+func (m *Memory) UpdateOrg(id, name, externalID string) (*models.ManagedOrg, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	org, ok := m.orgs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if externalID != "" {
+		if owner := m.orgByExternalID(externalID); owner != "" && owner != id {
+			return nil, ErrDuplicate
+		}
+		m.orgExtIDs[id] = externalID
+	} else {
+		delete(m.orgExtIDs, id)
+	}
+	renamed := *org
+	renamed.Name = name
+	m.orgs[id] = &renamed
+	m.touchOrg(id)
+	m.changed(Change{Kind: KindOrg, ID: id})
+	return m.managedOrg(id)
+}
+
+// DeleteOrg removes an organization together with its roles, webhooks, API
+// keys, templates and review grants it gave or received. Its child
+// organizations move up to its parent. It returns ErrLegalHold if the
+// organization or any of its reviews is under a legal hold.
+func (m *Memory) DeleteOrg(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteOrg(id)
+}
+
+func (m *Memory) deleteOrg(id string) error {
+	if _, ok := m.orgs[id]; !ok {
+		return ErrNotFound
+	}
+	if m.anyHeld(id) {
+		return ErrLegalHold
+	}
+	delete(m.orgs, id)
+	delete(m.orgExtIDs, id)
+	delete(m.orgRegions, id)
+	m.changed(Change{Kind: KindOrg, ID: id, Deleted: true})
+	for _, child := range m.detachOrg(id) {
+		m.changed(Change{Kind: KindOrg, ID: child})
+	}
+	for rid, r := range m.roles {
+		if r.OrgID == id {
+			delete(m.roles, rid)
+		}
+	}
+	for wid, w := range m.webhooks {
+		if w.OrgID == id {
+			delete(m.webhooks, wid)
+		}
+	}
+	for kid, k := range m.apiKeys {
+		if k.OrgID == id {
+			delete(m.apiKeys, kid)
+		}
+	}
+	for key, g := range m.grants {
+		if g.OrgID == id || g.GranteeOrgID == id {
+			delete(m.grants, key)
+		}
+	}
+	for key, g := range m.guestGrants {
+		if g.OrgID == id {
+			delete(m.guestGrants, key)
+		}
+	}
+	delete(m.sigRequired, id)
+	delete(m.netPolicies, id)
+	for sid, s := range m.services {
+		if s.OrgID == id {
+			delete(m.services, sid)
+		}
+	}
+	for tid, t := range m.templates {
+		if t.OrgID == id {
+			delete(m.templates, tid)
+		}
+	}
+	for rid, r := range m.escRules {
+		if r.OrgID == id {
+			delete(m.escRules, rid)
+		}
+	}
+	delete(m.moderation, id)
+	delete(m.secretPols, id)
+	for aid, a := range m.attachments {
+		if a.OrgID == id {
+			delete(m.attachments, aid)
+		}
+	}
+	for oid, op := range m.operations {
+		if op.OrgID == id {
+			delete(m.operations, oid)
+		}
+	}
+	delete(m.suggestFb, id)
+	delete(m.llmConfigs, id)
+	delete(m.aiBudgets, id)
+	delete(m.prompts, id)
+	delete(m.aiRedactPol, id)
+	delete(m.riskPols, id)
+	delete(m.compliance, id)
+	delete(m.retention, id)
+	delete(m.retRuns, id)
+	delete(m.deletions, id)
+	for tid, t := range m.teams {
+		if t.OrgID == id {
+			delete(m.teams, tid)
+		}
+	}
+	for gid, g := range m.gates {
+		if g.OrgID == id {
+			delete(m.gates, gid)
+		}
+	}
+	m.aiRedacts = slices.DeleteFunc(m.aiRedacts, func(r models.AIRedaction) bool { return r.OrgID == id })
+	m.llmUsage = slices.DeleteFunc(m.llmUsage, func(u models.LLMUsage) bool { return u.OrgID == id })
+	return nil
+}
+
+func (m *Memory) managedOrg(id string) (*models.ManagedOrg, error) {
+	org, ok := m.orgs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &models.ManagedOrg{ID: org.ID, ExternalID: m.orgExtIDs[id], Name: org.Name, Region: m.orgRegions[id], ParentID: m.orgParents[id],
+		CreatedBy: org.CreatedBy, CreatedAt: org.CreatedAt, UpdatedAt: org.UpdatedAt}, nil
+}
+
+// touchOrg records that an organization changed now.
+func (m *Memory) touchOrg(id string) {
+	if org, ok := m.orgs[id]; ok {
+		touched := *org
+		touched.UpdatedAt = time.Now()
+		m.orgs[id] = &touched
+	}
+}
+
+// OrgRegion returns the region an organization's data is pinned to, or ""
+// if it may be served from any region.
+func (m *Memory) OrgRegion(orgID string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.orgRegions[orgID]
+}
+
+func (m *Memory) orgByExternalID(externalID string) string {
+	for id, ext := range m.orgExtIDs {
+		if ext == externalID {
+			return id
+		}
+	}
+	return ""
+}
+
+// CreateRole stores a new role, assigning its ID. Role names and external
+// IDs are unique within an organization.
+func (m *Memory) CreateRole(r *models.Role) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.roleConflict(r) {
+		return ErrDuplicate
+	}
+	m.roleSeq++
+	r.ID = fmt.Sprintf("role-%d", m.roleSeq)
+	m.roles[r.ID] = copyOf(r)
+	return nil
+}
+
+// GetRole returns a role of the organization.
+func (m *Memory) GetRole(orgID, id string) (*models.Role, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.roles[id]
+	if !ok || r.OrgID != orgID {
+		return nil, ErrNotFound
+	}
+	return copyOf(r), nil
+}
+
+// ListRoles returns the organization's roles sorted by name.
+func (m *Memory) ListRoles(orgID string) []*models.Role {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*models.Role
+	for _, r := range m.roles {
+		if r.OrgID == orgID {
+			out = append(out, copyOf(r))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// UpdateRole replaces an existing role, keeping its creation time.
+func (m *Memory) UpdateRole(r *models.Role) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, ok := m.roles[r.ID]
+	if !ok || current.OrgID != r.OrgID {
+		return ErrNotFound
+	}
+	if m.roleConflict(r) {
+		return ErrDuplicate
+	}
+	r.CreatedAt = current.CreatedAt
+	m.roles[r.ID] = copyOf(r)
+	return nil
+}
+
+// DeleteRole removes a role of the organization.
+func (m *Memory) DeleteRole(orgID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.roles[id]
+	if !ok || r.OrgID != orgID {
+		return ErrNotFound
+	}
+	delete(m.roles, id)
+	return nil
+}
+
+func (m *Memory) roleConflict(r *models.Role) bool {
+	for _, other := range m.roles {
+		if other.OrgID != r.OrgID || other.ID == r.ID {
+			continue
+		}
+		if other.Name == r.Name || (r.ExternalID != "" && other.ExternalID == r.ExternalID) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateWebhook stores a new webhook, assigning its ID. External IDs are
+// unique within an organization.
+func (m *Memory) CreateWebhook(w *models.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.webhookConflict(w) {
+		return ErrDuplicate
+	}
+	m.webhookSeq++
+	w.ID = fmt.Sprintf("webhook-%d", m.webhookSeq)
+	m.webhooks[w.ID] = copyWebhook(w)
+	return nil
+}
+
+// GetWebhook returns a webhook of the organization.
+func (m *Memory) GetWebhook(orgID, id string) (*models.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	w, ok := m.webhooks[id]
+	if !ok || w.OrgID != orgID {
+		return nil, ErrNotFound
+	}
+	return copyWebhook(w), nil
+}
+
+// ListWebhooks returns the organization's webhooks in creation order.
+func (m *Memory) ListWebhooks(orgID string) []*models.Webhook {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*models.Webhook
+	for _, w := range m.webhooks {
+		if w.OrgID == orgID {
+			out = append(out, copyWebhook(w))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// UpdateWebhook replaces an existing webhook, keeping its creation time.
+// An empty secret keeps the current one.
+func (m *Memory) UpdateWebhook(w *models.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, ok := m.webhooks[w.ID]
+	if !ok || current.OrgID != w.OrgID {
+		return ErrNotFound
+	}
+	if m.webhookConflict(w) {
+		return ErrDuplicate
+	}
+	if w.Secret == "" {
+		w.Secret = current.Secret
+	}
+	w.CreatedAt = current.CreatedAt
+	m.webhooks[w.ID] = copyWebhook(w)
+	return nil
+}
+
+// DeleteWebhook removes a webhook of the organization.
+func (m *Memory) DeleteWebhook(orgID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.webhooks[id]
+	if !ok || w.OrgID != orgID {
+		return ErrNotFound
+	}
+	delete(m.webhooks, id)
+	return nil
+}
+
+func (m *Memory) webhookConflict(w *models.Webhook) bool {
+	if w.ExternalID == "" {
+		return false
+	}
+	for _, other := range m.webhooks {
+		if other.OrgID == w.OrgID && other.ID != w.ID && other.ExternalID == w.ExternalID {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPIKey stores a new API key, assigning its ID. External IDs are
+// unique within an organization.
+func (m *Memory) CreateAPIKey(k *models.APIKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if k.ExternalID != "" {
+		for _, other := range m.apiKeys {
+			if other.OrgID == k.OrgID && other.ExternalID == k.ExternalID {
+				return ErrDuplicate
+			}
+		}
+	}
+	m.apiKeySeq++
+	k.ID = fmt.Sprintf("key-%d", m.apiKeySeq)
+	m.apiKeys[k.ID] = copyOf(k)
+	return nil
+}
+
+// GetAPIKey returns an API key of the organization.
+func (m *Memory) GetAPIKey(orgID, id string) (*models.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	k, ok := m.apiKeys[id]
+	if !ok || k.OrgID != orgID {
+		return nil, ErrNotFound
+	}
+	return copyOf(k), nil
+}
+
+// ListAPIKeys returns the organization's API keys in creation order.
+func (m *Memory) ListAPIKeys(orgID string) []*models.APIKey {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []*models.APIKey
+	for _, k := range m.apiKeys {
+		if k.OrgID == orgID {
+			out = append(out, copyOf(k))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// RenameAPIKey changes the display name of an API key. The key material
+// and role cannot be changed; rotate by creating a new key.
+func (m *Memory) RenameAPIKey(orgID, id, name string) (*models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k, ok := m.apiKeys[id]
+	if !ok || k.OrgID != orgID {
+		return nil, ErrNotFound
+	}
+	renamed := *k
+	renamed.Name = name
+	m.apiKeys[id] = &renamed
+	return copyOf(&renamed), nil
+}
+
+// DeleteAPIKey revokes an API key of the organization.
+func (m *Memory) DeleteAPIKey(orgID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k, ok := m.apiKeys[id]
+	if !ok || k.OrgID != orgID {
+		return ErrNotFound
+	}
+	delete(m.apiKeys, id)
+	return nil
+}
+
+// UseAPIKey returns the API key with the given hash and records its use.
+func (m *Memory) UseAPIKey(hash string, at time.Time) (*models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, k := range m.apiKeys {
+		if k.Hash == hash {
+			return m.useAPIKey(id, at), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// UseAPIKeyByID returns an API key by ID and records its use.
+func (m *Memory) UseAPIKeyByID(id string, at time.Time) (*models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.apiKeys[id]; !ok {
+		return nil, ErrNotFound
+	}
+	return m.useAPIKey(id, at), nil
+}
+
+// useAPIKey records that an API key was used at and returns a copy of it.
+func (m *Memory) useAPIKey(id string, at time.Time) *models.APIKey {
+	used := *m.apiKeys[id]
+	used.LastUsedAt = &at
+	m.apiKeys[id] = &used
+	return copyOf(&used)
+}
@@ -0,0 +1,12 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/store/storetest"
+)
+
+func TestMemory(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Store { return store.NewMemory() })
+}
@@ -0,0 +1,178 @@
+package store
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// ListOrgReviews returns the reviews of an organization, newest first.
+// Reviews created at the same instant are ordered by ID, so the order is
+// the same on every call and pages of it do not overlap.
+func (m *Memory) ListOrgReviews(orgID string) []*models.Review {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []*models.Review{}
+	for _, r := range m.reviews {
+		if r.OrgID != orgID {
+			continue
+		}
+		if read, ok := m.listedReview(r); ok {
+			out = append(out, read)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.After(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// MergeReview folds a review into another of the same organization: its
+// comments, labels, watchers, attachments and escalation history move to
+// the target, and the source is removed, leaving a redirect.
+// merge.Comments is set to the number of comments moved. It returns
+// ErrNotFound if either review does not exist, and ErrLegalHold if the
+// source is under a legal hold.
+func (m *Memory) MergeReview(merge *models.ReviewMerge) error {
+	return m.Update(func(tx *Tx) error { return tx.MergeReview(merge) })
+}
+
+// mergeReview does the work of MergeReview and returns a function that
+// undoes it.
+func (m *Memory) mergeReview(merge *models.ReviewMerge) (undo func(), err error) {
+	src, ok := m.reviews[merge.SourceID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if _, ok := m.reviews[merge.TargetID]; !ok {
+		return nil, ErrNotFound
+	}
+	if m.reviewHeld(merge.SourceID) {
+		return nil, ErrLegalHold
+	}
+	source, target := merge.SourceID, merge.TargetID
+
+	var restore []func()
+	for _, id := range []string{source, target} {
+		restore = append(restore,
+			keepSlice(m.comments, id), keepSlice(m.labels, id), keepSlice(m.escHistory, id),
+			keepSlice(m.redactions, id), keepSlice(m.secrets, id),
+		)
+	}
+	restore = append(restore,
+		keep(m.reviews, source), keep(m.summaries, source), keep(m.suggestions, source),
+		keep(m.signoffs, source), keep(m.assignments, source), keep(m.priorities, source),
+		keep(m.checklists, source), keep(m.fieldValues, source), keep(m.merged, source),
+	)
+	for id, sub := range m.subs {
+		if sub.ReviewID == source {
+			restore = append(restore, keep(m.subs, id))
+		}
+	}
+	for id, a := range m.attachments {
+		if a.ReviewID == source {
+			restore = append(restore, keep(m.attachments, id))
+		}
+	}
+	undo = func() {
+		for _, f := range restore {
+			f()
+		}
+	}
+
+	for _, c := range m.comments[source] {
+		moved := *c
+		moved.ReviewID = target
+		m.comments[target] = append(m.comments[target], &moved)
+	}
+	merge.Comments = len(m.comments[source])
+	sort.SliceStable(m.comments[target], func(i, j int) bool {
+		return m.comments[target][i].CreatedAt.Before(m.comments[target][j].CreatedAt)
+	})
+	delete(m.comments, source)
+
+	labels := m.labels[target]
+	for _, l := range m.labels[source] {
+		if !slices.Contains(labels, l) {
+			labels = append(labels, l)
+		}
+	}
+	if len(labels) > 0 {
+		m.labels[target] = labels
+	}
+	for id, sub := range m.subs {
+		if sub.ReviewID == source {
+			moved := *sub
+			moved.ReviewID = target
+			m.subs[id] = &moved
+		}
+	}
+	for id, a := range m.attachments {
+		if a.ReviewID == source {
+			moved := *a
+			moved.ReviewID = target
+			m.attachments[id] = &moved
+		}
+	}
+	if steps := m.escHistory[source]; len(steps) > 0 {
+		for _, s := range steps {
+			s.ReviewID = target
+			m.escHistory[target] = append(m.escHistory[target], s)
+		}
+		sort.SliceStable(m.escHistory[target], func(i, j int) bool {
+			return m.escHistory[target][i].At.Before(m.escHistory[target][j].At)
+		})
+	}
+
+	for _, red := range m.redactions[source] {
+		red.ReviewID = target
+		m.redactions[target] = append(m.redactions[target], red)
+	}
+	// Findings in the source's own fields go with it; those in its
+	// attachments follow them
+	for _, f := range m.secrets[source] {
+		if strings.HasPrefix(f.Source, "attachment:") {
+			m.secrets[target] = append(slices.Clip(m.secrets[target]), f)
+		}
+	}
+
+	delete(m.reviews, source)
+	delete(m.redactions, source)
+	delete(m.secrets, source)
+	delete(m.summaries, source)
+	delete(m.suggestions, source)
+	delete(m.signoffs, source)
+	delete(m.labels, source)
+	delete(m.escHistory, source)
+	delete(m.assignments, source)
+	delete(m.priorities, source)
+	delete(m.checklists, source)
+	delete(m.fieldValues, source)
+	merge.OrgID = src.OrgID
+	m.merged[source] = *merge
+	return undo, nil
+}
+
+// MergedInto returns the merge that removed a review, following later
+// merges of its target so TargetID names a review that still exists.
+func (m *Memory) MergedInto(reviewID string) (models.ReviewMerge, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	merge, ok := m.merged[reviewID]
+	if !ok {
+		return merge, false
+	}
+	for seen := 0; seen < len(m.merged); seen++ {
+		next, ok := m.merged[merge.TargetID]
+		if !ok {
+			break
+		}
+		merge.TargetID = next.TargetID
+	}
+	return merge, true
+}
@@ -0,0 +1,147 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// ErrNothingToRedact is returned when the text to redact does not appear
+// in the content.
+var ErrNothingToRedact = errors.New("text to redact not found")
+
+// GetModerationPolicy returns an organization's banned terms. Organizations
+// that never set any get an empty policy that rejects nothing.
+func (m *Memory) GetModerationPolicy(orgID string) models.ModerationPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	p, ok := m.moderation[orgID]
+	if !ok {
+		return models.ModerationPolicy{OrgID: orgID, BannedTerms: []string{}, Action: models.ModerationReject}
+	}
+	return p
+}
+
+// SetModerationPolicy replaces an organization's banned terms.
+func (m *Memory) SetModerationPolicy(p models.ModerationPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.moderation[p.OrgID] = p
+}
+
+// redact replaces text in s with the redaction marker, or all of s when text
+// is empty. It reports whether anything was replaced.
+func redact(s, text string) (string, bool) {
+	if text == "" {
+		return models.RedactedMarker, s != models.RedactedMarker
+	}
+	if !strings.Contains(s, text) {
+		return s, false
+	}
+	return strings.ReplaceAll(s, text, models.RedactedMarker), true
+}
+
+// addRedaction records a tombstone, assigning its ID. The caller must hold
+// the lock.
+func (m *Memory) addRedaction(red *models.Redaction) {
+	m.redactSeq++
+	red.ID = fmt.Sprintf("redaction-%d", m.redactSeq)
+	m.redactions[red.ReviewID] = append(m.redactions[red.ReviewID], *red)
+}
+
+// RedactComment replaces text in a comment's body with the redaction
+// marker, or the whole body when text is empty, and records the tombstone
+// red. It returns ErrNotFound for unknown comments and ErrNothingToRedact
+// when text does not appear in the body.
+func (m *Memory) RedactComment(text string, red *models.Redaction) (*models.Comment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	comments := m.comments[red.ReviewID]
+	for i, c := range comments {
+		if c.ID != red.CommentID {
+			continue
+		}
+		read, err := m.readComment(c)
+		if err != nil {
+			return nil, err
+		}
+		updated := *read
+		var ok bool
+		if updated.Body, ok = redact(updated.Body, text); !ok {
+			return nil, ErrNothingToRedact
+		}
+		stored, err := m.storedComment(&updated)
+		if err != nil {
+			return nil, err
+		}
+		comments = append([]*models.Comment(nil), comments...)
+		comments[i] = stored
+		m.comments[red.ReviewID] = comments
+		red.Partial = text != ""
+		m.addRedaction(red)
+		return &updated, nil
+	}
+	return nil, ErrNotFound
+}
+
+// RedactReview replaces text in a review field (title, content or
+// fields.<key> for a string custom field) with the redaction marker, or the
+// whole value when text is empty, and records the tombstone red. It returns
+// ErrNotFound for unknown reviews or fields and ErrNothingToRedact when
+// text does not appear in the field.
+func (m *Memory) RedactReview(text string, red *models.Redaction) (*models.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.reviews[red.ReviewID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	read, err := m.readReview(stored)
+	if err != nil {
+		return nil, err
+	}
+	review := *read
+	redacted := false
+	switch key, custom := strings.CutPrefix(red.Field, "fields."); {
+	case red.Field == "title":
+		review.Title, redacted = redact(review.Title, text)
+	case red.Field == "content":
+		review.Content, redacted = redact(review.Content, text)
+	case custom:
+		value, ok := m.fieldValues[review.ID][key].(string)
+		if !ok {
+			return nil, ErrNotFound
+		}
+		if value, redacted = redact(value, text); redacted {
+			values := make(map[string]interface{}, len(m.fieldValues[review.ID]))
+			for k, v := range m.fieldValues[review.ID] {
+				values[k] = v
+			}
+			values[key] = value
+			m.fieldValues[review.ID] = values
+		}
+	default:
+		return nil, ErrNotFound
+	}
+	if !redacted {
+		return nil, ErrNothingToRedact
+	}
+	if stored, err = m.storedReview(&review); err != nil {
+		return nil, err
+	}
+	m.reviews[review.ID] = stored
+	red.Partial = text != ""
+	m.addRedaction(red)
+	m.changed(Change{Kind: KindReview, ID: review.ID})
+	return &review, nil
+}
+
+// ListRedactions returns the tombstones of a review and its comments,
+// oldest first.
+func (m *Memory) ListRedactions(reviewID string) []models.Redaction {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]models.Redaction{}, m.redactions[reviewID]...)
+}
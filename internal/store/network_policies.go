@@ -0,0 +1,26 @@
+package store
+
+import "github.com/andres20980/aurea-orchestrator/internal/models"
+
+// SetNetworkPolicy replaces the organization's network policy; a policy
+// without ranges removes it.
+func (m *Memory) SetNetworkPolicy(p models.NetworkPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(p.CIDRs) == 0 {
+		delete(m.netPolicies, p.OrgID)
+		return
+	}
+	m.netPolicies[p.OrgID] = p
+}
+
+// NetworkPolicy returns the organization's network policy, which has no
+// ranges when access is unrestricted.
+func (m *Memory) NetworkPolicy(orgID string) models.NetworkPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if p, ok := m.netPolicies[orgID]; ok {
+		return p
+	}
+	return models.NetworkPolicy{OrgID: orgID, CIDRs: []string{}}
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// CreateOAuthClient stores a new OAuth client. Its ID is chosen by the caller.
+func (m *Memory) CreateOAuthClient(c *models.OAuthClient) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.oauthApps[c.ID]; ok {
+		return ErrDuplicate
+	}
+	m.oauthApps[c.ID] = copyOAuthClient(c)
+	return nil
+}
+
+// GetOAuthClient returns an OAuth client by client ID.
+func (m *Memory) GetOAuthClient(id string) (*models.OAuthClient, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, ok := m.oauthApps[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyOAuthClient(c), nil
+}
+
+// ListOAuthClients returns the OAuth clients registered by an organization.
+func (m *Memory) ListOAuthClients(orgID string) []*models.OAuthClient {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	clients := []*models.OAuthClient{}
+	for _, c := range m.oauthApps {
+		if c.OrgID == orgID {
+			clients = append(clients, copyOAuthClient(c))
+		}
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].CreatedAt.Before(clients[j].CreatedAt) })
+	return clients
+}
+
+// DeleteOAuthClient removes an OAuth client and revokes every code and
+// token issued to it.
+func (m *Memory) DeleteOAuthClient(orgID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.oauthApps[id]
+	if !ok || c.OrgID != orgID {
+		return ErrNotFound
+	}
+	delete(m.oauthApps, id)
+	for h, code := range m.authCodes {
+		if code.Grant.ClientID == id {
+			delete(m.authCodes, h)
+		}
+	}
+	for h, t := range m.authTokens {
+		if t.Grant.ClientID == id {
+			delete(m.authTokens, h)
+		}
+	}
+	return nil
+}
+
+// SaveOAuthCode stores an authorization code by hash.
+func (m *Memory) SaveOAuthCode(hash string, c *models.OAuthCode) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.authCodes[hash] = copyOAuthCode(c)
+}
+
+// TakeOAuthCode removes and returns an unexpired authorization code, so
+// each code can be exchanged once.
+func (m *Memory) TakeOAuthCode(hash string, now time.Time) (*models.OAuthCode, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.authCodes[hash]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	delete(m.authCodes, hash)
+	if now.After(c.ExpiresAt) {
+		return nil, ErrNotFound
+	}
+	return copyOAuthCode(c), nil
+}
+
+// SaveOAuthToken stores an issued token by hash.
+func (m *Memory) SaveOAuthToken(hash string, t *models.OAuthToken) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.authTokens[hash] = copyOAuthToken(t)
+}
+
+// GetOAuthToken returns an unexpired token by hash.
+func (m *Memory) GetOAuthToken(hash string, now time.Time) (*models.OAuthToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.authTokens[hash]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if now.After(t.ExpiresAt) {
+		delete(m.authTokens, hash)
+		return nil, ErrNotFound
+	}
+	return copyOAuthToken(t), nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import "time"
+
+// CompleteOnboardingStep records when the user first completed an
+// onboarding step and returns that time. Later calls keep the first time.
+func (m *Memory) CompleteOnboardingStep(userID, step string, at time.Time) time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	steps := m.onboarding[userID]
+	if steps == nil {
+		steps = make(map[string]time.Time)
+		m.onboarding[userID] = steps
+	}
+	if first, ok := steps[step]; ok {
+		return first
+	}
+	steps[step] = at
+	return at
+}
+
+// OnboardingCompletions returns when the user completed each onboarding
+// step they have completed.
+func (m *Memory) OnboardingCompletions(userID string) map[string]time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string]time.Time, len(m.onboarding[userID]))
+	for step, at := range m.onboarding[userID] {
+		out[step] = at
+	}
+	return out
+}
+
+// DismissOnboarding hides the user's checklist, or shows it again when
+// dismissed is false.
+func (m *Memory) DismissOnboarding(userID string, dismissed bool, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !dismissed {
+		delete(m.onboardDis, userID)
+		return
+	}
+	m.onboardDis[userID] = at
+}
+
+// OnboardingDismissed returns when the user dismissed their checklist.
+func (m *Memory) OnboardingDismissed(userID string) (time.Time, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	at, ok := m.onboardDis[userID]
+	return at, ok
+}
+
+// HasAuthoredReview reports whether the user has created any review.
+func (m *Memory) HasAuthoredReview(userID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, r := range m.reviews {
+		if r.AuthorID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// ErrOperationFinished is returned when changing an operation that stopped
+// running.
+var ErrOperationFinished = errors.New("operation already finished")
+
+// copyOperation returns a copy of op that shares nothing mutable with it.
+func copyOperation(op *models.Operation) models.Operation {
+	out := *op
+	out.Links = maps.Clone(op.Links)
+	if op.File != nil {
+		f := *op.File
+		out.File = &f
+	}
+	return out
+}
+
+// CreateOperation records a running operation, assigning its ID.
+func (m *Memory) CreateOperation(op models.Operation) models.Operation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.opSeq++
+	op.ID = fmt.Sprintf("op-%d", m.opSeq)
+	op.Status = models.OperationRunning
+	op.CreatedAt = time.Now()
+	op.UpdatedAt = op.CreatedAt
+	m.operations[op.ID] = &op
+	return copyOperation(&op)
+}
+
+// GetOperation returns one of an organization's operations.
+func (m *Memory) GetOperation(orgID, id string) (models.Operation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	op, ok := m.operations[id]
+	if !ok || op.OrgID != orgID {
+		return models.Operation{}, ErrNotFound
+	}
+	return copyOperation(op), nil
+}
+
+// ListOperations returns an organization's operations, newest first,
+// optionally only those started by createdBy.
+func (m *Memory) ListOperations(orgID, createdBy string) []models.Operation {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.Operation{}
+	for _, op := range m.operations {
+		if op.OrgID == orgID && (createdBy == "" || op.CreatedBy == createdBy) {
+			out = append(out, copyOperation(op))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// UpdateOperation applies fn to a stored operation and returns the result.
+// Finished operations cannot be changed.
+func (m *Memory) UpdateOperation(id string, fn func(*models.Operation)) (models.Operation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	op, ok := m.operations[id]
+	if !ok {
+		return models.Operation{}, ErrNotFound
+	}
+	if op.Finished() {
+		return copyOperation(op), ErrOperationFinished
+	}
+	next := copyOperation(op)
+	fn(&next)
+	next.UpdatedAt = time.Now()
+	m.operations[id] = &next
+	return copyOperation(&next), nil
+}
+
+// DeleteOperationsBefore removes operations that finished before t and
+// returns them, so their files can be deleted.
+func (m *Memory) DeleteOperationsBefore(t time.Time) []models.Operation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []models.Operation
+	for id, op := range m.operations {
+		if op.FinishedAt != nil && op.FinishedAt.Before(t) {
+			out = append(out, copyOperation(op))
+			delete(m.operations, id)
+		}
+	}
+	return out
+}
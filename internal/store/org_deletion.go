@@ -0,0 +1,182 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// ScheduleOrgDeletion records an organization's pending deletion. It
+// returns ErrNotFound if the organization does not exist, ErrDuplicate if
+// its deletion is already pending and ErrLegalHold if it or any of its
+// reviews is under a legal hold.
+func (m *Memory) ScheduleOrgDeletion(d models.OrgDeletion) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.orgs[d.OrgID]; !ok {
+		return ErrNotFound
+	}
+	if _, ok := m.deletions[d.OrgID]; ok {
+		return ErrDuplicate
+	}
+	if m.anyHeld(d.OrgID) {
+		return ErrLegalHold
+	}
+	m.deletions[d.OrgID] = d
+	return nil
+}
+
+// GetOrgDeletion returns an organization's pending deletion.
+func (m *Memory) GetOrgDeletion(orgID string) (models.OrgDeletion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	d, ok := m.deletions[orgID]
+	if !ok {
+		return models.OrgDeletion{}, ErrNotFound
+	}
+	return d, nil
+}
+
+// SetOrgDeletionExport records the size of the export taken for an
+// organization's pending deletion.
+func (m *Memory) SetOrgDeletionExport(orgID string, size int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.deletions[orgID]
+	if !ok {
+		return ErrNotFound
+	}
+	d.ExportSize = size
+	m.deletions[orgID] = d
+	return nil
+}
+
+// CancelOrgDeletion cancels an organization's pending deletion.
+func (m *Memory) CancelOrgDeletion(orgID string) (models.OrgDeletion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.deletions[orgID]
+	if !ok {
+		return models.OrgDeletion{}, ErrNotFound
+	}
+	delete(m.deletions, orgID)
+	return d, nil
+}
+
+// DueOrgDeletions returns the pending deletions whose grace period has
+// ended by t, oldest first.
+func (m *Memory) DueOrgDeletions(t time.Time) []models.OrgDeletion {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []models.OrgDeletion
+	for _, d := range m.deletions {
+		if !t.Before(d.PurgeAt) {
+			out = append(out, d)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PurgeAt.Before(out[j].PurgeAt) })
+	return out
+}
+
+// PurgeOrg deletes an organization as DeleteOrg does, together with its
+// members, its reviews and everything recorded about them. It returns the keys of the blobs the deleted records
+// referenced, for the caller to delete. Audit events are kept.
+func (m *Memory) PurgeOrg(id string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var blobKeys []string
+	for _, a := range m.attachments {
+		if a.OrgID == id {
+			blobKeys = append(blobKeys, a.BlobKey)
+		}
+	}
+	if b, ok := m.branding[id]; ok && b.LogoKey != "" {
+		blobKeys = append(blobKeys, b.LogoKey)
+	}
+	if err := m.deleteOrg(id); err != nil {
+		return nil, err
+	}
+	delete(m.branding, id)
+	delete(m.policies, id)
+	delete(m.calendars, id)
+	delete(m.jira, id)
+	delete(m.escalation, id)
+
+	for rid, r := range m.reviews {
+		if r.OrgID != id {
+			continue
+		}
+		for _, c := range m.comments[rid] {
+			delete(m.resolutions, c.ID)
+		}
+		delete(m.reviews, rid)
+		delete(m.comments, rid)
+		delete(m.checklists, rid)
+		delete(m.approvals, rid)
+		delete(m.labels, rid)
+		delete(m.assignments, rid)
+		delete(m.priorities, rid)
+		delete(m.escalated, rid)
+		delete(m.escHistory, rid)
+		delete(m.fieldValues, rid)
+		delete(m.redactions, rid)
+		delete(m.secrets, rid)
+		delete(m.summaries, rid)
+		delete(m.suggestions, rid)
+		delete(m.signoffs, rid)
+		m.changed(Change{Kind: KindReview, ID: rid, Deleted: true})
+	}
+	for sid, merge := range m.merged {
+		if merge.OrgID == id {
+			delete(m.merged, sid)
+		}
+	}
+	for lid, l := range m.shareLinks {
+		if l.OrgID == id {
+			delete(m.shareLinks, lid)
+			delete(m.shareAccess, lid)
+		}
+	}
+	for lid, l := range m.links {
+		if _, ok := m.reviews[l.ReviewID]; !ok {
+			delete(m.links, lid)
+		}
+	}
+
+	for uid, u := range m.users {
+		if u.OrgID != id && m.pendingJoin[uid].OrgID != id {
+			continue
+		}
+		delete(m.users, uid)
+		delete(m.profiles, uid)
+		delete(m.inbox, uid)
+		delete(m.inactive, uid)
+		delete(m.away, uid)
+		delete(m.passwords, uid)
+		delete(m.pwSetAt, uid)
+		delete(m.pwReset, uid)
+		delete(m.unverified, uid)
+		delete(m.pendingJoin, uid)
+		delete(m.onboarding, uid)
+		delete(m.expertise, uid)
+		delete(m.managers, uid)
+	}
+	for sid, s := range m.subs {
+		if _, ok := m.users[s.UserID]; !ok {
+			delete(m.subs, sid)
+		}
+	}
+	for sid, s := range m.sessions {
+		if _, ok := m.users[s.UserID]; !ok {
+			delete(m.sessions, sid)
+			delete(m.sessionTok, s.TokenHash)
+		}
+	}
+	for did, d := range m.devices {
+		if _, ok := m.users[d.UserID]; !ok {
+			delete(m.devices, did)
+		}
+	}
+	return blobKeys, nil
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+func orgDomainKey(orgID, domain string) string {
+	return orgID + "|" + domain
+}
+
+// AddOrgDomain claims an email domain for an organization, unverified.
+func (m *Memory) AddOrgDomain(d models.OrgDomain) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := orgDomainKey(d.OrgID, d.Domain)
+	if _, ok := m.domains[key]; ok {
+		return ErrDuplicate
+	}
+	m.domains[key] = &d
+	return nil
+}
+
+// GetOrgDomain returns one of the organization's domains.
+func (m *Memory) GetOrgDomain(orgID, domain string) (models.OrgDomain, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	d, ok := m.domains[orgDomainKey(orgID, domain)]
+	if !ok {
+		return models.OrgDomain{}, ErrNotFound
+	}
+	return *d, nil
+}
+
+// ListOrgDomains returns the organization's domains in name order.
+func (m *Memory) ListOrgDomains(orgID string) []models.OrgDomain {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.OrgDomain{}
+	for _, d := range m.domains {
+		if d.OrgID == orgID {
+			out = append(out, *d)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Domain < out[j].Domain })
+	return out
+}
+
+// UpdateOrgDomainJoin sets how users with a matching email join.
+func (m *Memory) UpdateOrgDomainJoin(orgID, domain, mode, role string) (models.OrgDomain, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.domains[orgDomainKey(orgID, domain)]
+	if !ok {
+		return models.OrgDomain{}, ErrNotFound
+	}
+	d.JoinMode, d.DefaultRole = mode, role
+	return *d, nil
+}
+
+// VerifyOrgDomain marks an organization's domain verified. It returns
+// ErrDuplicate if another organization has already verified the domain.
+func (m *Memory) VerifyOrgDomain(orgID, domain string, at time.Time) (models.OrgDomain, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.domains[orgDomainKey(orgID, domain)]
+	if !ok {
+		return models.OrgDomain{}, ErrNotFound
+	}
+	for _, other := range m.domains {
+		if other.Domain == domain && other.OrgID != orgID && other.Verified {
+			return models.OrgDomain{}, ErrDuplicate
+		}
+	}
+	if !d.Verified {
+		d.Verified, d.VerifiedAt = true, &at
+	}
+	return *d, nil
+}
+
+// DeleteOrgDomain releases an organization's domain.
+func (m *Memory) DeleteOrgDomain(orgID, domain string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := orgDomainKey(orgID, domain)
+	if _, ok := m.domains[key]; !ok {
+		return ErrNotFound
+	}
+	delete(m.domains, key)
+	return nil
+}
+
+// VerifiedDomain returns the verified claim on domain, if any organization
+// has one.
+func (m *Memory) VerifiedDomain(domain string) (models.OrgDomain, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, d := range m.domains {
+		if d.Domain == domain && d.Verified {
+			return *d, true
+		}
+	}
+	return models.OrgDomain{}, false
+}
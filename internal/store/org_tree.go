@@ -0,0 +1,170 @@
+package store
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// ErrCycle is returned when a parent assignment would make an organization
+// its own ancestor.
+var ErrCycle = errors.New("organization hierarchy cycle")
+
+// SetOrgParent makes parentID the parent of an organization, or detaches it
+// from its parent when parentID is empty.
+func (m *Memory) SetOrgParent(id, parentID string) (*models.ManagedOrg, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.orgs[id]; !ok {
+		return nil, ErrNotFound
+	}
+	if parentID == "" {
+		delete(m.orgParents, id)
+	} else {
+		if _, ok := m.orgs[parentID]; !ok {
+			return nil, ErrNotFound
+		}
+		if parentID == id || m.inTree(id, parentID) {
+			return nil, ErrCycle
+		}
+		m.orgParents[id] = parentID
+	}
+	m.touchOrg(id)
+	m.changed(Change{Kind: KindOrg, ID: id})
+	return m.managedOrg(id)
+}
+
+// OrgHierarchy returns the parent, ancestors and direct children of an
+// organization.
+func (m *Memory) OrgHierarchy(id string) (models.OrgHierarchy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if _, ok := m.orgs[id]; !ok {
+		return models.OrgHierarchy{}, ErrNotFound
+	}
+	return models.OrgHierarchy{
+		OrgID:     id,
+		ParentID:  m.orgParents[id],
+		Ancestors: append([]string{}, m.ancestors(id)...),
+		Children:  m.children(id),
+	}, nil
+}
+
+// InOrgTree reports whether orgID is rootID or one of its descendants.
+func (m *Memory) InOrgTree(rootID, orgID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.inTree(rootID, orgID)
+}
+
+// ListTreeReviews returns the reviews of an organization and all of its
+// descendants, newest first.
+func (m *Memory) ListTreeReviews(rootID string) []*models.Review {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []*models.Review{}
+	for _, r := range m.reviews {
+		if !m.inTree(rootID, r.OrgID) {
+			continue
+		}
+		if read, ok := m.listedReview(r); ok {
+			out = append(out, read)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// OrgRollup counts members and reviews by status for an organization and
+// each of its descendants.
+func (m *Memory) OrgRollup(rootID string) (models.OrgRollup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if _, ok := m.orgs[rootID]; !ok {
+		return models.OrgRollup{}, ErrNotFound
+	}
+	entries := map[string]*models.OrgRollupEntry{}
+	for id, org := range m.orgs {
+		if m.inTree(rootID, id) {
+			entries[id] = &models.OrgRollupEntry{OrgID: id, Name: org.Name, ParentID: m.orgParents[id], ByStatus: map[string]int{}}
+		}
+	}
+	for _, u := range m.users {
+		if e := entries[u.OrgID]; e != nil {
+			e.Members++
+		}
+	}
+	for _, r := range m.reviews {
+		if e := entries[r.OrgID]; e != nil {
+			e.Reviews++
+			e.ByStatus[r.Status]++
+		}
+	}
+	rollup := models.OrgRollup{OrgID: rootID, Orgs: make([]models.OrgRollupEntry, 0, len(entries)), ByStatus: map[string]int{}}
+	for _, e := range entries {
+		rollup.Orgs = append(rollup.Orgs, *e)
+		rollup.Members += e.Members
+		rollup.Reviews += e.Reviews
+		for status, n := range e.ByStatus {
+			rollup.ByStatus[status] += n
+		}
+	}
+	sort.Slice(rollup.Orgs, func(i, j int) bool { return rollup.Orgs[i].OrgID < rollup.Orgs[j].OrgID })
+	return rollup, nil
+}
+
+// ancestors returns the chain of parents of an organization, nearest
+// first. Callers must hold m.mu.
+func (m *Memory) ancestors(id string) []string {
+	var out []string
+	seen := map[string]bool{id: true}
+	for p := m.orgParents[id]; p != "" && !seen[p]; p = m.orgParents[p] {
+		seen[p] = true
+		out = append(out, p)
+	}
+	return out
+}
+
+// inTree reports whether orgID is rootID or below it. Callers must hold
+// m.mu.
+func (m *Memory) inTree(rootID, orgID string) bool {
+	if orgID == rootID {
+		return true
+	}
+	for _, id := range m.ancestors(orgID) {
+		if id == rootID {
+			return true
+		}
+	}
+	return false
+}
+
+// children returns the direct children of an organization, ordered by ID.
+// Callers must hold m.mu.
+func (m *Memory) children(id string) []string {
+	out := []string{}
+	for child, parent := range m.orgParents {
+		if parent == id {
+			out = append(out, child)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// detachOrg moves the children of a removed organization up to its parent
+// and returns them. Callers must hold m.mu for writing.
+func (m *Memory) detachOrg(id string) []string {
+	parent := m.orgParents[id]
+	delete(m.orgParents, id)
+	moved := m.children(id)
+	for _, child := range moved {
+		if parent != "" {
+			m.orgParents[child] = parent
+		} else {
+			delete(m.orgParents, child)
+		}
+	}
+	return moved
+}
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// journalRecord is one line of the outbox journal: either a new event or
+// the ID of an event that has been published.
+type journalRecord struct {
+	Event     *models.OutboxEvent `json:"event,omitempty"`
+	Published int64               `json:"published,omitempty"`
+}
+
+// OpenOutboxJournal makes the outbox durable by journaling it to path.
+// Unpublished events from a previous run are loaded so they are published
+// after a crash, and the journal is compacted to just those events.
+func (m *Memory) OpenOutboxJournal(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	pending := make(map[int64]*models.OutboxEvent)
+	if f, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var rec journalRecord
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+				// A torn final line from a crash mid-write is expected; stop there.
+				break
+			}
+			if rec.Event != nil {
+				pending[rec.Event.ID] = rec.Event
+				if rec.Event.ID > m.outboxSeq {
+					m.outboxSeq = rec.Event.ID
+				}
+			} else {
+				delete(pending, rec.Published)
+			}
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("reading outbox journal: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, e := range pending {
+		m.outbox = append(m.outbox, e)
+	}
+	sort.Slice(m.outbox, func(i, j int) bool { return m.outbox[i].ID < m.outbox[j].ID })
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	for _, e := range m.outbox {
+		if err := writeJournal(f, journalRecord{Event: e}); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	f.Close()
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	m.journal, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	return err
+}
+
+// AppendOutbox records an event for publication, assigning its ID. With a
+// journal, the event is on disk before AppendOutbox returns.
+func (m *Memory) AppendOutbox(e *models.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outboxSeq++
+	e.ID = m.outboxSeq
+	if m.journal != nil {
+		if err := writeJournal(m.journal, journalRecord{Event: e}); err != nil {
+			return err
+		}
+		if err := m.journal.Sync(); err != nil {
+			return err
+		}
+	}
+	m.outbox = append(m.outbox, e)
+	return nil
+}
+
+// PendingOutbox returns up to limit unpublished events, oldest first.
+func (m *Memory) PendingOutbox(limit int) []*models.OutboxEvent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.outbox) < limit {
+		limit = len(m.outbox)
+	}
+	return append([]*models.OutboxEvent(nil), m.outbox[:limit]...)
+}
+
+// MarkPublished removes a published event from the outbox.
+func (m *Memory) MarkPublished(id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, e := range m.outbox {
+		if e.ID == id {
+			m.outbox = append(m.outbox[:i], m.outbox[i+1:]...)
+			if m.journal != nil {
+				return writeJournal(m.journal, journalRecord{Published: id})
+			}
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func writeJournal(f *os.File, rec journalRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// SetPasswordHash stores a user's password hash, replacing any previous one.
+// Hashes come from security.HashPassword; the store never sees passwords.
+// Setting a password clears a pending forced reset and drops the user's
+// outstanding reset and change tokens.
+func (m *Memory) SetPasswordHash(userID, hash string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.passwords[userID] = hash
+	m.pwSetAt[userID] = time.Now()
+	delete(m.pwReset, userID)
+	for h, t := range m.pwTokens {
+		if t.UserID == userID {
+			delete(m.pwTokens, h)
+		}
+	}
+}
+
+// PasswordHash returns a user's password hash, if one is set.
+func (m *Memory) PasswordHash(userID string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	h, ok := m.passwords[userID]
+	return h, ok
+}
+
+// PasswordSetAt returns when a user's password was last set.
+func (m *Memory) PasswordSetAt(userID string) (time.Time, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.pwSetAt[userID]
+	return t, ok
+}
+
+// RequirePasswordReset makes the user change their password at their next
+// login.
+func (m *Memory) RequirePasswordReset(userID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pwReset[userID] = true
+}
+
+// PasswordResetRequired reports whether the user must change their
+// password at their next login.
+func (m *Memory) PasswordResetRequired(userID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.pwReset[userID]
+}
+
+// SavePasswordToken stores a password token by hash, dropping expired ones.
+func (m *Memory) SavePasswordToken(hash string, t models.PasswordToken) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for h, existing := range m.pwTokens {
+		if now.After(existing.ExpiresAt) {
+			delete(m.pwTokens, h)
+		}
+	}
+	m.pwTokens[hash] = t
+}
+
+// TakePasswordToken removes and returns an unexpired password token of the
+// given purpose, so each token can be used once.
+func (m *Memory) TakePasswordToken(hash, purpose string, now time.Time) (models.PasswordToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.pwTokens[hash]
+	if !ok || t.Purpose != purpose {
+		return models.PasswordToken{}, ErrNotFound
+	}
+	delete(m.pwTokens, hash)
+	if now.After(t.ExpiresAt) {
+		return models.PasswordToken{}, ErrNotFound
+	}
+	return t, nil
+}
@@ -0,0 +1,21 @@
+package store
+
+// SetRequireSignedRequests sets whether the organization's API keys may
+// only be used to sign requests rather than be sent as credentials.
+func (m *Memory) SetRequireSignedRequests(orgID string, required bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !required {
+		delete(m.sigRequired, orgID)
+		return
+	}
+	m.sigRequired[orgID] = true
+}
+
+// RequiresSignedRequests reports whether the organization only accepts
+// signed API key requests.
+func (m *Memory) RequiresSignedRequests(orgID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.sigRequired[orgID]
+}
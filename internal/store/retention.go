@@ -0,0 +1,174 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// maxRetentionRuns is how many retention runs are kept per organization.
+const maxRetentionRuns = 20
+
+// GetRetentionPolicy returns an organization's retention policy, with
+// every rule disabled when it has not set one.
+func (m *Memory) GetRetentionPolicy(orgID string) models.RetentionPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	p, ok := m.retention[orgID]
+	if !ok {
+		return models.RetentionPolicy{OrgID: orgID}
+	}
+	return p
+}
+
+// SetRetentionPolicy replaces an organization's retention policy.
+func (m *Memory) SetRetentionPolicy(p models.RetentionPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retention[p.OrgID] = p
+	m.changed(Change{Kind: KindPolicy, ID: p.OrgID})
+}
+
+// ListRetentionPolicies returns the policies with any rule enabled.
+func (m *Memory) ListRetentionPolicies() []models.RetentionPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []models.RetentionPolicy
+	for _, p := range m.retention {
+		if p.Enabled() {
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].OrgID < out[j].OrgID })
+	return out
+}
+
+// ApplyRetention applies an organization's retention policy as of run.At:
+// it closes idle reviews, purges soft-deleted items and truncates old
+// audit events, recording what it did in run. With run.DryRun it only
+// records what it would do. Data under a legal hold is kept and counted
+// in run.Held; truncated audit events keep their place in the log, so
+// positions handed out by AuditSeq stay valid.
+func (m *Memory) ApplyRetention(p models.RetentionPolicy, run *models.RetentionRun) {
+	if run.DryRun {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+	} else {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+	}
+	run.OrgID = p.OrgID
+	run.ClosedReviews, run.PurgedMerges, run.PurgedShareLinks = []string{}, []string{}, []string{}
+
+	if p.CloseIdleDays > 0 {
+		cutoff := run.At.AddDate(0, 0, -p.CloseIdleDays)
+		for id, r := range m.reviews {
+			if r.OrgID != p.OrgID || r.Status == "approved" || r.Status == models.ReviewClosed || m.approvals[id] != nil {
+				continue
+			}
+			if !m.lastActivity(r).Before(cutoff) {
+				continue
+			}
+			if m.reviewHeld(id) {
+				run.Held++
+				continue
+			}
+			run.ClosedReviews = append(run.ClosedReviews, id)
+			if !run.DryRun {
+				closed := *r
+				closed.Status = models.ReviewClosed
+				closed.UpdatedAt = run.At
+				m.reviews[id] = &closed
+				m.changed(Change{Kind: KindReview, ID: id})
+			}
+		}
+		sort.Strings(run.ClosedReviews)
+	}
+
+	if p.PurgeDeletedDays > 0 {
+		cutoff := run.At.AddDate(0, 0, -p.PurgeDeletedDays)
+		for id, merge := range m.merged {
+			if merge.OrgID != p.OrgID || !merge.MergedAt.Before(cutoff) {
+				continue
+			}
+			if m.orgHeld(p.OrgID) || m.reviewHeld(merge.TargetID) {
+				run.Held++
+				continue
+			}
+			run.PurgedMerges = append(run.PurgedMerges, id)
+			if !run.DryRun {
+				delete(m.merged, id)
+			}
+		}
+		for id, l := range m.shareLinks {
+			if l.OrgID != p.OrgID || l.RevokedAt == nil || !l.RevokedAt.Before(cutoff) {
+				continue
+			}
+			if m.reviewHeld(l.ReviewID) {
+				run.Held++
+				continue
+			}
+			run.PurgedShareLinks = append(run.PurgedShareLinks, id)
+			if !run.DryRun {
+				delete(m.shareLinks, id)
+				delete(m.shareAccess, id)
+			}
+		}
+		sort.Strings(run.PurgedMerges)
+		sort.Strings(run.PurgedShareLinks)
+	}
+
+	if p.AuditMonths > 0 {
+		cutoff := run.At.AddDate(0, -p.AuditMonths, 0)
+		for i, e := range m.audit {
+			if e.OrgID != p.OrgID || !e.CreatedAt.Before(cutoff) {
+				continue
+			}
+			if m.orgHeld(p.OrgID) || (e.TargetType == "review" && m.reviewHeld(e.TargetID)) {
+				run.Held++
+				continue
+			}
+			run.AuditEvents++
+			if !run.DryRun {
+				m.audit[i] = &models.AuditEvent{ID: e.ID, CreatedAt: e.CreatedAt}
+				m.auditPurged[p.OrgID]++
+			}
+		}
+	}
+
+	if !run.DryRun {
+		runs := append(m.retRuns[p.OrgID], *run)
+		if len(runs) > maxRetentionRuns {
+			runs = runs[len(runs)-maxRetentionRuns:]
+		}
+		m.retRuns[p.OrgID] = runs
+	}
+}
+
+// ListRetentionRuns returns the organization's latest retention runs,
+// newest first.
+func (m *Memory) ListRetentionRuns(orgID string) []models.RetentionRun {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	runs := m.retRuns[orgID]
+	out := make([]models.RetentionRun, len(runs))
+	for i, r := range runs {
+		out[len(runs)-1-i] = r
+	}
+	return out
+}
+
+// lastActivity returns when a review was last edited or commented on.
+func (m *Memory) lastActivity(r *models.Review) time.Time {
+	last := r.UpdatedAt
+	if last.IsZero() {
+		last = r.CreatedAt
+	}
+	for _, c := range m.comments[r.ID] {
+		if c.CreatedAt.After(last) {
+			last = c.CreatedAt
+		}
+	}
+	return last
+}
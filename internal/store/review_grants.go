@@ -0,0 +1,132 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+func reviewGrantKey(reviewID, granteeOrgID string) string {
+	return reviewID + "|" + granteeOrgID
+}
+
+// SetReviewGrant shares a review with the grantee organization, replacing
+// any earlier grant to the same organization.
+func (m *Memory) SetReviewGrant(g models.ReviewGrant) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.grants[reviewGrantKey(g.ReviewID, g.GranteeOrgID)] = &g
+}
+
+// DeleteReviewGrant stops sharing a review with an organization.
+func (m *Memory) DeleteReviewGrant(reviewID, granteeOrgID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := reviewGrantKey(reviewID, granteeOrgID)
+	if _, ok := m.grants[key]; !ok {
+		return ErrNotFound
+	}
+	delete(m.grants, key)
+	return nil
+}
+
+// ReviewGrant returns the grant of a review to an organization, if any.
+func (m *Memory) ReviewGrant(reviewID, granteeOrgID string) (models.ReviewGrant, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	g, ok := m.grants[reviewGrantKey(reviewID, granteeOrgID)]
+	if !ok {
+		return models.ReviewGrant{}, false
+	}
+	return *g, true
+}
+
+// ListReviewGrants returns the organizations a review is shared with,
+// ordered by grantee.
+func (m *Memory) ListReviewGrants(reviewID string) []models.ReviewGrant {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.ReviewGrant{}
+	for _, g := range m.grants {
+		if g.ReviewID == reviewID {
+			out = append(out, *g)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].GranteeOrgID < out[j].GranteeOrgID })
+	return out
+}
+
+// ListGrantsTo returns the grants an organization has received, newest
+// first.
+func (m *Memory) ListGrantsTo(granteeOrgID string) []models.ReviewGrant {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.ReviewGrant{}
+	for _, g := range m.grants {
+		if g.GranteeOrgID == granteeOrgID {
+			out = append(out, *g)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].GrantedAt.After(out[j].GrantedAt) })
+	return out
+}
+
+// SetGuestGrant shares a review with a guest, replacing any earlier grant
+// to the same guest.
+func (m *Memory) SetGuestGrant(g models.GuestGrant) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.guestGrants[reviewGrantKey(g.ReviewID, g.UserID)] = &g
+}
+
+// DeleteGuestGrant stops sharing a review with a guest.
+func (m *Memory) DeleteGuestGrant(reviewID, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := reviewGrantKey(reviewID, userID)
+	if _, ok := m.guestGrants[key]; !ok {
+		return ErrNotFound
+	}
+	delete(m.guestGrants, key)
+	return nil
+}
+
+// GuestGrant returns the grant of a review to a guest, if any.
+func (m *Memory) GuestGrant(reviewID, userID string) (models.GuestGrant, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	g, ok := m.guestGrants[reviewGrantKey(reviewID, userID)]
+	if !ok {
+		return models.GuestGrant{}, false
+	}
+	return *g, true
+}
+
+// ListGuestGrants returns the guests a review is shared with, ordered by
+// user.
+func (m *Memory) ListGuestGrants(reviewID string) []models.GuestGrant {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.GuestGrant{}
+	for _, g := range m.guestGrants {
+		if g.ReviewID == reviewID {
+			out = append(out, *g)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UserID < out[j].UserID })
+	return out
+}
+
+// ListGuestGrantsTo returns the grants a guest has received, newest first.
+func (m *Memory) ListGuestGrantsTo(userID string) []models.GuestGrant {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.GuestGrant{}
+	for _, g := range m.guestGrants {
+		if g.UserID == userID {
+			out = append(out, *g)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].GrantedAt.After(out[j].GrantedAt) })
+	return out
+}
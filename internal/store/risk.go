@@ -0,0 +1,71 @@
+package store
+
+import (
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// GetRiskPolicy returns how an organization's reviews are scored for risk,
+// or the default policy when it has not set one.
+func (m *Memory) GetRiskPolicy(orgID string) models.RiskPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	p, ok := m.riskPols[orgID]
+	if !ok {
+		return models.DefaultRiskPolicy(orgID)
+	}
+	return p
+}
+
+// SetRiskPolicy replaces an organization's risk policy.
+func (m *Memory) SetRiskPolicy(p models.RiskPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.riskPols[p.OrgID] = p
+	m.changed(Change{Kind: KindPolicy, ID: p.OrgID})
+}
+
+// AuthorHistory counts an author's reviews in an organization that were
+// approved and the times any of them was reopened after approval.
+func (m *Memory) AuthorHistory(orgID, authorID string) (approved, reopened int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	authored := make(map[string]bool)
+	for id, r := range m.reviews {
+		if r.OrgID != orgID || r.AuthorID != authorID {
+			continue
+		}
+		authored[id] = true
+		if m.approvals[id] != nil || r.Status == "approved" {
+			approved++
+		}
+	}
+	for _, e := range m.audit {
+		if e.OrgID == orgID && e.Action == "review.reopened" && authored[e.TargetID] {
+			reopened++
+		}
+	}
+	return approved, reopened
+}
+
+// AddRiskSignoff records an admin's approval of a review that needs
+// several, replacing any earlier one of theirs, and returns the review's
+// sign-offs, oldest first.
+func (m *Memory) AddRiskSignoff(s models.RiskSignoff) []models.RiskSignoff {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var kept []models.RiskSignoff
+	for _, old := range m.signoffs[s.ReviewID] {
+		if old.UserID != s.UserID {
+			kept = append(kept, old)
+		}
+	}
+	m.signoffs[s.ReviewID] = append(kept, s)
+	return append([]models.RiskSignoff(nil), m.signoffs[s.ReviewID]...)
+}
+
+// RiskSignoffs returns the sign-offs recorded for a review, oldest first.
+func (m *Memory) RiskSignoffs(reviewID string) []models.RiskSignoff {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]models.RiskSignoff{}, m.signoffs[reviewID]...)
+}
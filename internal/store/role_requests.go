@@ -0,0 +1,102 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// ErrDecided is returned when acting on a role request or access review
+// item that is no longer pending, or approving a role request whose user
+// has changed role since.
+var ErrDecided = errors.New("already decided")
+
+// AddRoleRequest records a pending role request, assigning its ID. It
+// returns ErrDuplicate if the user already has a pending request.
+func (m *Memory) AddRoleRequest(req models.RoleRequest) (models.RoleRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.roleReqs {
+		if existing.UserID == req.UserID && existing.Status == models.RoleRequestPending {
+			return models.RoleRequest{}, ErrDuplicate
+		}
+	}
+	m.roleReqSeq++
+	req.ID = fmt.Sprintf("role-request-%d", m.roleReqSeq)
+	req.Status = models.RoleRequestPending
+	m.roleReqs[req.ID] = &req
+	return req, nil
+}
+
+// GetRoleRequest returns a role request of the organization.
+func (m *Memory) GetRoleRequest(orgID, id string) (models.RoleRequest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	req, ok := m.roleReqs[id]
+	if !ok || req.OrgID != orgID {
+		return models.RoleRequest{}, ErrNotFound
+	}
+	return *req, nil
+}
+
+// ListRoleRequests returns the organization's role requests, newest first,
+// optionally only those of one user or status.
+func (m *Memory) ListRoleRequests(orgID, userID, status string) []models.RoleRequest {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.RoleRequest{}
+	for _, req := range m.roleReqs {
+		if req.OrgID != orgID || (userID != "" && req.UserID != userID) || (status != "" && req.Status != status) {
+			continue
+		}
+		out = append(out, *req)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// DecideRoleRequest moves a pending role request to status. Approving it
+// also gives the user the requested role.
+func (m *Memory) DecideRoleRequest(orgID, id, status, decidedBy, reason string) (models.RoleRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	req, ok := m.roleReqs[id]
+	if !ok || req.OrgID != orgID {
+		return models.RoleRequest{}, ErrNotFound
+	}
+	if req.Status != models.RoleRequestPending {
+		return *req, ErrDecided
+	}
+	if status == models.RoleRequestApproved {
+		u, ok := m.users[req.UserID]
+		if !ok || u.OrgID != orgID {
+			return *req, ErrNotFound
+		}
+		if u.Role != req.FromRole {
+			return *req, ErrDecided
+		}
+		updated := *u
+		updated.Role, updated.UpdatedAt = req.Role, time.Now()
+		m.users[u.ID] = &updated
+	}
+	now := time.Now()
+	decided := *req
+	decided.Status, decided.DecidedBy, decided.DecisionReason, decided.DecidedAt = status, decidedBy, reason, &now
+	m.roleReqs[id] = &decided
+	return decided, nil
+}
+
+// SetUserRole changes the role of a member of the organization and returns
+// the previous role.
+func (m *Memory) SetUserRole(orgID, userID, role string) (string, error) {
+	var previous string
+	err := m.Update(func(tx *Tx) error {
+		var err error
+		previous, err = tx.SetUserRole(orgID, userID, role)
+		return err
+	})
+	return previous, err
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"slices"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// GetSecretPolicy returns an organization's secret detection policy.
+// Organizations that never set one warn on the built-in rules.
+func (m *Memory) GetSecretPolicy(orgID string) models.SecretPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	p, ok := m.secretPols[orgID]
+	if !ok {
+		return models.SecretPolicy{OrgID: orgID, Mode: models.SecretsWarn, Rules: []models.SecretRule{}, DisabledRules: []string{}}
+	}
+	return p
+}
+
+// SetSecretPolicy replaces an organization's secret detection policy.
+func (m *Memory) SetSecretPolicy(p models.SecretPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.secretPols[p.OrgID] = p
+}
+
+// RecordSecretFindings replaces the findings of a review from the given
+// sources with findings, so a source that no longer holds a secret is
+// cleared.
+func (m *Memory) RecordSecretFindings(reviewID string, sources []string, findings []models.SecretFinding) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recordSecretFindings(reviewID, sources, findings)
+}
+
+func (m *Memory) recordSecretFindings(reviewID string, sources []string, findings []models.SecretFinding) {
+	kept := []models.SecretFinding{}
+	for _, f := range m.secrets[reviewID] {
+		if !slices.Contains(sources, f.Source) {
+			kept = append(kept, f)
+		}
+	}
+	kept = append(kept, findings...)
+	if len(kept) == 0 {
+		delete(m.secrets, reviewID)
+		return
+	}
+	m.secrets[reviewID] = kept
+}
+
+// ListSecretFindings returns the secrets found in a review.
+func (m *Memory) ListSecretFindings(reviewID string) []models.SecretFinding {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]models.SecretFinding{}, m.secrets[reviewID]...)
+}
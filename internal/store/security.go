@@ -0,0 +1,51 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// maxLoginHistory bounds the logins kept per user for anomaly detection.
+const maxLoginHistory = 50
+
+// RecordLogin appends a login to the user's history.
+func (m *Memory) RecordLogin(e *models.LoginEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	history := append(m.logins[e.UserID], e)
+	if len(history) > maxLoginHistory {
+		history = history[len(history)-maxLoginHistory:]
+	}
+	m.logins[e.UserID] = history
+}
+
+// LoginHistory returns the user's recent logins, oldest first.
+func (m *Memory) LoginHistory(userID string) []*models.LoginEvent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]*models.LoginEvent(nil), m.logins[userID]...)
+}
+
+// AddSecurityEvent records a security event, assigning its ID.
+func (m *Memory) AddSecurityEvent(e *models.SecurityEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.securitySeq++
+	e.ID = fmt.Sprintf("sec-%d", m.securitySeq)
+	m.security = append(m.security, e)
+}
+
+// ListSecurityEvents returns up to limit security events, newest first,
+// optionally restricted to one organization.
+func (m *Memory) ListSecurityEvents(orgID string, limit int) []*models.SecurityEvent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	events := []*models.SecurityEvent{}
+	for i := len(m.security) - 1; i >= 0 && len(events) < limit; i-- {
+		if e := m.security[i]; orgID == "" || e.OrgID == orgID {
+			events = append(events, e)
+		}
+	}
+	return events
+}
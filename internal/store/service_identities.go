@@ -0,0 +1,96 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// CreateServiceIdentity stores a service identity, assigning its ID. It
+// returns ErrDuplicate if another identity already claims the subject.
+func (m *Memory) CreateServiceIdentity(s models.ServiceIdentity) (models.ServiceIdentity, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, other := range m.services {
+		if strings.EqualFold(other.Subject, s.Subject) {
+			return models.ServiceIdentity{}, ErrDuplicate
+		}
+	}
+	m.serviceSeq++
+	s.ID = fmt.Sprintf("service-%d", m.serviceSeq)
+	m.services[s.ID] = &s
+	return s, nil
+}
+
+// GetServiceIdentity returns a service identity.
+func (m *Memory) GetServiceIdentity(id string) (models.ServiceIdentity, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.services[id]
+	if !ok {
+		return models.ServiceIdentity{}, ErrNotFound
+	}
+	return *s, nil
+}
+
+// ListServiceIdentities returns the service identities of an organization,
+// or all of them when orgID is empty, in creation order.
+func (m *Memory) ListServiceIdentities(orgID string) []models.ServiceIdentity {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.ServiceIdentity{}
+	for _, s := range m.services {
+		if orgID == "" || s.OrgID == orgID {
+			out = append(out, *s)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// UpdateServiceIdentity replaces the role, scopes and name of a service
+// identity. Its subject and organization are immutable.
+func (m *Memory) UpdateServiceIdentity(id, name, role string, scopes []string) (models.ServiceIdentity, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.services[id]
+	if !ok {
+		return models.ServiceIdentity{}, ErrNotFound
+	}
+	updated := *s
+	updated.Name, updated.Role, updated.Scopes = name, role, scopes
+	m.services[id] = &updated
+	return updated, nil
+}
+
+// DeleteServiceIdentity removes a service identity.
+func (m *Memory) DeleteServiceIdentity(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.services[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.services, id)
+	return nil
+}
+
+// UseServiceIdentity returns the identity claiming one of the certificate
+// names and records its use.
+func (m *Memory) UseServiceIdentity(names []string, at time.Time) (models.ServiceIdentity, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, s := range m.services {
+		for _, name := range names {
+			if name != "" && strings.EqualFold(s.Subject, name) {
+				used := *s
+				used.LastUsedAt = &at
+				m.services[id] = &used
+				return used, nil
+			}
+		}
+	}
+	return models.ServiceIdentity{}, ErrNotFound
+}
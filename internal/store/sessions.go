@@ -0,0 +1,93 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// AddSession records a new session, assigning its ID, and drops sessions
+// that have expired or ended.
+func (m *Memory) AddSession(s models.Session) models.Session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, existing := range m.sessions {
+		if !existing.Active(s.CreatedAt) {
+			delete(m.sessionTok, existing.TokenHash)
+			delete(m.sessions, id)
+		}
+	}
+	m.sessionSeq++
+	s.ID = fmt.Sprintf("session-%d", m.sessionSeq)
+	m.sessions[s.ID] = &s
+	m.sessionTok[s.TokenHash] = s.ID
+	return s
+}
+
+// SessionByToken returns the session of an access token, if the token
+// was issued by a login.
+func (m *Memory) SessionByToken(tokenHash string) (models.Session, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.sessions[m.sessionTok[tokenHash]]
+	if !ok {
+		return models.Session{}, false
+	}
+	return *s, true
+}
+
+// TouchSession records that a session was used from ip at t.
+func (m *Memory) TouchSession(id, ip string, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return
+	}
+	touched := *s
+	touched.IP, touched.LastSeenAt = ip, at
+	m.sessions[id] = &touched
+}
+
+// ListSessions returns the user's active sessions, most recently used
+// first.
+func (m *Memory) ListSessions(userID string, at time.Time) []models.Session {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.Session{}
+	for _, s := range m.sessions {
+		if s.UserID == userID && s.Active(at) {
+			out = append(out, *s)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].LastSeenAt.After(out[j].LastSeenAt) })
+	return out
+}
+
+// EndSession ends a session of the user (any user when userID is empty)
+// for reason. It returns ErrNotFound if the session is not active.
+func (m *Memory) EndSession(userID, id, reason string, at time.Time) (models.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok || (userID != "" && s.UserID != userID) || !s.Active(at) {
+		return models.Session{}, ErrNotFound
+	}
+	ended := *s
+	ended.EndedAt, ended.EndReason = &at, reason
+	m.sessions[id] = &ended
+	return ended, nil
+}
+
+// EndUserSessions ends every active session of the user, e.g. after a
+// password reset, and returns how many were ended.
+func (m *Memory) EndUserSessions(userID, reason string, at time.Time) int {
+	n := 0
+	m.Update(func(tx *Tx) error {
+		n = tx.EndUserSessions(userID, reason, at)
+		return nil
+	})
+	return n
+}
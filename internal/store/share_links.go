@@ -0,0 +1,89 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// maxShareAccesses bounds the access log kept per share link.
+const maxShareAccesses = 1000
+
+// CreateShareLink stores a share link, assigning its ID.
+func (m *Memory) CreateShareLink(l models.ShareLink) models.ShareLink {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.shareSeq++
+	l.ID = fmt.Sprintf("share-%d", m.shareSeq)
+	m.shareLinks[l.ID] = &l
+	return l
+}
+
+// GetShareLink returns a share link.
+func (m *Memory) GetShareLink(id string) (models.ShareLink, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	l, ok := m.shareLinks[id]
+	if !ok {
+		return models.ShareLink{}, ErrNotFound
+	}
+	return *l, nil
+}
+
+// ListShareLinks returns a review's share links, newest first.
+func (m *Memory) ListShareLinks(reviewID string) []models.ShareLink {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.ShareLink{}
+	for _, l := range m.shareLinks {
+		if l.ReviewID == reviewID {
+			out = append(out, *l)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// RevokeShareLink revokes a share link of the review. Revoking twice keeps
+// the first revocation.
+func (m *Memory) RevokeShareLink(reviewID, id, by string, at time.Time) (models.ShareLink, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l, ok := m.shareLinks[id]
+	if !ok || l.ReviewID != reviewID {
+		return models.ShareLink{}, ErrNotFound
+	}
+	if l.RevokedAt == nil {
+		l.RevokedAt, l.RevokedBy = &at, by
+	}
+	return *l, nil
+}
+
+// RecordShareAccess logs a use of a share link, keeping the most recent
+// accesses.
+func (m *Memory) RecordShareAccess(a models.ShareAccess) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if l, ok := m.shareLinks[a.LinkID]; ok {
+		l.AccessCount++
+	}
+	log := append(m.shareAccess[a.LinkID], a)
+	if len(log) > maxShareAccesses {
+		log = log[len(log)-maxShareAccesses:]
+	}
+	m.shareAccess[a.LinkID] = log
+}
+
+// ListShareAccesses returns a share link's access log, newest first.
+func (m *Memory) ListShareAccesses(linkID string) []models.ShareAccess {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	log := m.shareAccess[linkID]
+	out := make([]models.ShareAccess, len(log))
+	for i, a := range log {
+		out[len(log)-1-i] = a
+	}
+	return out
+}
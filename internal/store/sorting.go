@@ -0,0 +1,80 @@
+package store
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// Sortable timestamp fields of list queries.
+const (
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+)
+
+// ErrInvalidSort is returned by ParseSort for an unknown field.
+var ErrInvalidSort = errors.New("store: sort must be one of created_at, updated_at, optionally prefixed with -")
+
+// Sort orders a list by when its items were created or last updated. The
+// zero Sort keeps the list's own order.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort reads a ?sort= value: created_at or updated_at, oldest first,
+// or newest first with a leading "-". An empty value is the zero Sort.
+func ParseSort(param string) (Sort, error) {
+	if param == "" {
+		return Sort{}, nil
+	}
+	field, desc := strings.CutPrefix(param, "-")
+	if field != SortCreatedAt && field != SortUpdatedAt {
+		return Sort{}, ErrInvalidSort
+	}
+	return Sort{Field: field, Desc: desc}, nil
+}
+
+// SortReviews orders reviews by s.
+func SortReviews(reviews []*models.Review, s Sort) {
+	sortByTime(reviews, s, func(r *models.Review) (time.Time, time.Time, string) { return r.CreatedAt, r.UpdatedAt, r.ID })
+}
+
+// SortUsers orders users by s.
+func SortUsers(users []*models.User, s Sort) {
+	sortByTime(users, s, func(u *models.User) (time.Time, time.Time, string) { return u.CreatedAt, u.UpdatedAt, u.ID })
+}
+
+// SortOrgSummaries orders organization summaries by s.
+func SortOrgSummaries(orgs []models.OrgSummary, s Sort) {
+	sortByTime(orgs, s, func(o models.OrgSummary) (time.Time, time.Time, string) { return o.CreatedAt, o.UpdatedAt, o.ID })
+}
+
+// sortByTime orders items by the time s names. Items without that time go
+// last, and ties are broken by ID, so the order is stable across requests.
+func sortByTime[T any](items []T, s Sort, key func(T) (created, updated time.Time, id string)) {
+	if s.Field == "" {
+		return
+	}
+	at := func(item T) (time.Time, string) {
+		created, updated, id := key(item)
+		if s.Field == SortUpdatedAt {
+			return updated, id
+		}
+		return created, id
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		ti, idi := at(items[i])
+		tj, idj := at(items[j])
+		if ti.IsZero() || tj.IsZero() {
+			return !ti.IsZero() && tj.IsZero()
+		}
+		if !ti.Equal(tj) {
+			return ti.Before(tj) != s.Desc
+		}
+		return idi < idj
+	})
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		param   string
+		want    Sort
+		wantErr bool
+	}{
+		{"", Sort{}, false},
+		{"created_at", Sort{Field: SortCreatedAt}, false},
+		{"-updated_at", Sort{Field: SortUpdatedAt, Desc: true}, false},
+		{"title", Sort{}, true},
+		{"--created_at", Sort{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.param, func(t *testing.T) {
+			got, err := ParseSort(tt.param)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Fatalf("ParseSort(%q) = %+v, %v", tt.param, got, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSort) {
+				t.Errorf("err = %v, want ErrInvalidSort", err)
+			}
+		})
+	}
+}
+
+func TestSortReviews(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	reviews := func() []*models.Review {
+		return []*models.Review{
+			{ID: "c", CreatedAt: t0.Add(2 * time.Hour), UpdatedAt: t0.Add(2 * time.Hour)},
+			{ID: "a", CreatedAt: t0, UpdatedAt: t0.Add(3 * time.Hour)},
+			{ID: "z", CreatedAt: t0.Add(time.Hour)},
+			{ID: "b", CreatedAt: t0, UpdatedAt: t0.Add(time.Hour)},
+		}
+	}
+	tests := []struct {
+		sort Sort
+		want []string
+	}{
+		{Sort{}, []string{"c", "a", "z", "b"}},
+		{Sort{Field: SortCreatedAt}, []string{"a", "b", "z", "c"}},
+		{Sort{Field: SortCreatedAt, Desc: true}, []string{"c", "z", "a", "b"}},
+		// Reviews never updated go last either way.
+		{Sort{Field: SortUpdatedAt}, []string{"b", "c", "a", "z"}},
+		{Sort{Field: SortUpdatedAt, Desc: true}, []string{"a", "c", "b", "z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort.Field, func(t *testing.T) {
+			list := reviews()
+			SortReviews(list, tt.sort)
+			var got []string
+			for _, r := range list {
+				got = append(got, r.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("%+v: got %v, want %v", tt.sort, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,1202 @@
+// Package store provides the in-memory persistence layer shared by the API handlers.
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/ulid"
+)
+
+var (
+	// ErrNotFound is returned when a requested record does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrDuplicate is returned when a record violates a uniqueness constraint.
+	ErrDuplicate = errors.New("already exists")
+)
+
+// Memory is an in-memory store for organizations, users, reviews and
+// instance-wide state.
+type Memory struct {
+	mu          sync.RWMutex
+	orgs        map[string]*models.Organization
+	users       map[string]*models.User
+	reviews     map[string]*models.Review
+	comments    map[string][]*models.Comment
+	checklists  map[string][]*models.ChecklistItem
+	approvals   map[string]*models.Approval
+	resolutions map[string]*models.ThreadResolution
+	policies    map[string]models.OrgPolicy
+	labels      map[string][]string
+	subs        map[string]*models.Subscription
+	inbox       map[string][]*models.Notification
+	invites     map[string]*models.Invite
+	profiles    map[string]*models.UserProfile
+	assignments map[string]*models.Assignment
+	inactive    map[string]*models.Deactivation
+	away        map[string]*models.OutOfOffice
+	calendars   map[string]models.WorkCalendar
+	links       map[string]*models.ExternalLink
+	jira        map[string]models.JiraConfig
+	priorities  map[string]string
+	escalation  map[string]models.EscalationConfig
+	escalated   map[string]*models.Escalation
+	orgExtIDs   map[string]string
+	orgRegions  map[string]string
+	roles       map[string]*models.Role
+	webhooks    map[string]*models.Webhook
+	apiKeys     map[string]*models.APIKey
+	audit       []*models.AuditEvent
+	// auditPurged counts the audit events truncated by retention, per
+	// organization.
+	auditPurged map[string]int
+	commentSeq  int
+	subSeq      int
+	notifySeq   int
+	linkSeq     int
+	roleSeq     int
+	webhookSeq  int
+	apiKeySeq   int
+	suspensions map[string]*models.OrgSuspension
+	settings    models.InstanceSettings
+	maintenance models.MaintenanceMode
+	listeners   []func(Change)
+	outbox      []*models.OutboxEvent
+	outboxSeq   int64
+	journal     *os.File
+	cipher      FieldCipher
+	logins      map[string][]*models.LoginEvent
+	security    []*models.SecurityEvent
+	securitySeq int
+	oauthApps   map[string]*models.OAuthClient
+	authCodes   map[string]*models.OAuthCode
+	authTokens  map[string]*models.OAuthToken
+	fields      map[string]*models.CustomField
+	fieldValues map[string]map[string]interface{}
+	fieldSeq    int
+	intakeForms map[string]*models.IntakeForm
+	submissions map[string]*models.IntakeSubmission
+	intakeSeq   int
+	submitSeq   int
+	mailAsks    map[string]*models.EmailApproval
+	devices     map[string]*models.Device
+	deviceSeq   int
+	passwords   map[string]string
+	branding    map[string]models.OrgBranding
+	biConfigs   map[string]models.BIExportConfig
+	lifecycle   []*models.LifecycleEvent
+	deliveries  map[string]*models.WebhookDelivery
+	deliverySeq int
+	deadLetters map[string]*models.DeadLetter
+	letterSeq   int
+	switches    map[string]models.KillSwitch
+	shareLinks  map[string]*models.ShareLink
+	shareAccess map[string][]models.ShareAccess
+	shareSeq    int
+	domains     map[string]*models.OrgDomain
+	orgParents  map[string]string
+	grants      map[string]*models.ReviewGrant
+	guestGrants map[string]*models.GuestGrant
+	roleReqs    map[string]*models.RoleRequest
+	roleReqSeq  int
+	campaigns   map[string]*models.AccessCampaign
+	campaignSeq int
+	accessItems map[string][]*models.AccessItem
+	breakGlass  map[string]*models.BreakGlass
+	glassSeq    int
+	bindModes   map[string]string
+	tokenBinds  map[string]models.TokenBinding
+	services    map[string]*models.ServiceIdentity
+	serviceSeq  int
+	sigRequired map[string]bool
+	netPolicies map[string]models.NetworkPolicy
+	sessions    map[string]*models.Session
+	sessionTok  map[string]string
+	sessionSeq  int
+	pwSetAt     map[string]time.Time
+	pwReset     map[string]bool
+	pwTokens    map[string]models.PasswordToken
+	emailVerifs map[string]models.EmailVerification
+	unverified  map[string]bool
+	pendingJoin map[string]models.PendingMembership
+	onboarding  map[string]map[string]time.Time
+	onboardDis  map[string]time.Time
+	templates   map[string]*models.Template
+	templateSeq int
+	expertise   map[string][]string
+	escRules    map[string]*models.EscalationRule
+	escRuleSeq  int
+	escHistory  map[string][]models.EscalationStep
+	managers    map[string]string
+	merged      map[string]models.ReviewMerge
+	attachments map[string]*models.Attachment
+	attachSeq   int
+	moderation  map[string]models.ModerationPolicy
+	redactions  map[string][]models.Redaction
+	redactSeq   int
+	secretPols  map[string]models.SecretPolicy
+	secrets     map[string][]models.SecretFinding
+	operations  map[string]*models.Operation
+	opSeq       int
+	summaries   map[string]models.ReviewSummary
+	llmUsage    []models.LLMUsage
+	llmSeq      int
+	llmConfigs  map[string][]models.LLMProviderConfig
+	aiBudgets   map[string]models.AIBudget
+	prompts     map[string]map[string]*promptHistory
+	aiRedactPol map[string]models.AIRedactionPolicy
+	aiRedacts   []models.AIRedaction
+	aiRedactSeq int
+	suggestions map[string][]models.Suggestion
+	suggestSeq  int
+	suggestFb   map[string]map[string]models.SuggestionFeedback
+	riskPols    map[string]models.RiskPolicy
+	signoffs    map[string][]models.RiskSignoff
+	teams       map[string]*models.Team
+	teamSeq     int
+	gates       map[string]*models.ApprovalGate
+	gateSeq     int
+	compliance  map[string]map[string]models.ComplianceReport
+	holds       map[string]*models.LegalHold
+	holdSeq     int
+	retention   map[string]models.RetentionPolicy
+	retRuns     map[string][]models.RetentionRun
+	deletions   map[string]models.OrgDeletion
+}
+
+// NewMemory creates an empty in-memory store.
+func NewMemory() *Memory {
+	return &Memory{
+		orgs:        make(map[string]*models.Organization),
+		users:       make(map[string]*models.User),
+		reviews:     make(map[string]*models.Review),
+		comments:    make(map[string][]*models.Comment),
+		checklists:  make(map[string][]*models.ChecklistItem),
+		approvals:   make(map[string]*models.Approval),
+		resolutions: make(map[string]*models.ThreadResolution),
+		policies:    make(map[string]models.OrgPolicy),
+		labels:      make(map[string][]string),
+		subs:        make(map[string]*models.Subscription),
+		inbox:       make(map[string][]*models.Notification),
+		invites:     make(map[string]*models.Invite),
+		profiles:    make(map[string]*models.UserProfile),
+		assignments: make(map[string]*models.Assignment),
+		inactive:    make(map[string]*models.Deactivation),
+		away:        make(map[string]*models.OutOfOffice),
+		calendars:   make(map[string]models.WorkCalendar),
+		links:       make(map[string]*models.ExternalLink),
+		jira:        make(map[string]models.JiraConfig),
+		priorities:  make(map[string]string),
+		escalation:  make(map[string]models.EscalationConfig),
+		escalated:   make(map[string]*models.Escalation),
+		orgExtIDs:   make(map[string]string),
+		orgRegions:  make(map[string]string),
+		roles:       make(map[string]*models.Role),
+		webhooks:    make(map[string]*models.Webhook),
+		apiKeys:     make(map[string]*models.APIKey),
+		suspensions: make(map[string]*models.OrgSuspension),
+		logins:      make(map[string][]*models.LoginEvent),
+		oauthApps:   make(map[string]*models.OAuthClient),
+		authCodes:   make(map[string]*models.OAuthCode),
+		authTokens:  make(map[string]*models.OAuthToken),
+		fields:      make(map[string]*models.CustomField),
+		fieldValues: make(map[string]map[string]interface{}),
+		intakeForms: make(map[string]*models.IntakeForm),
+		mailAsks:    make(map[string]*models.EmailApproval),
+		devices:     make(map[string]*models.Device),
+		passwords:   make(map[string]string),
+		branding:    make(map[string]models.OrgBranding),
+		biConfigs:   make(map[string]models.BIExportConfig),
+		deliveries:  make(map[string]*models.WebhookDelivery),
+		deadLetters: make(map[string]*models.DeadLetter),
+		switches:    make(map[string]models.KillSwitch),
+		shareLinks:  make(map[string]*models.ShareLink),
+		shareAccess: make(map[string][]models.ShareAccess),
+		domains:     make(map[string]*models.OrgDomain),
+		orgParents:  make(map[string]string),
+		grants:      make(map[string]*models.ReviewGrant),
+		guestGrants: make(map[string]*models.GuestGrant),
+		roleReqs:    make(map[string]*models.RoleRequest),
+		campaigns:   make(map[string]*models.AccessCampaign),
+		accessItems: make(map[string][]*models.AccessItem),
+		breakGlass:  make(map[string]*models.BreakGlass),
+		bindModes:   make(map[string]string),
+		tokenBinds:  make(map[string]models.TokenBinding),
+		services:    make(map[string]*models.ServiceIdentity),
+		sigRequired: make(map[string]bool),
+		netPolicies: make(map[string]models.NetworkPolicy),
+		sessions:    make(map[string]*models.Session),
+		sessionTok:  make(map[string]string),
+		pwSetAt:     make(map[string]time.Time),
+		pwReset:     make(map[string]bool),
+		pwTokens:    make(map[string]models.PasswordToken),
+		emailVerifs: make(map[string]models.EmailVerification),
+		unverified:  make(map[string]bool),
+		pendingJoin: make(map[string]models.PendingMembership),
+		onboarding:  make(map[string]map[string]time.Time),
+		onboardDis:  make(map[string]time.Time),
+		templates:   make(map[string]*models.Template),
+		expertise:   make(map[string][]string),
+		escRules:    make(map[string]*models.EscalationRule),
+		escHistory:  make(map[string][]models.EscalationStep),
+		managers:    make(map[string]string),
+		merged:      make(map[string]models.ReviewMerge),
+		attachments: make(map[string]*models.Attachment),
+		auditPurged: make(map[string]int),
+		moderation:  make(map[string]models.ModerationPolicy),
+		redactions:  make(map[string][]models.Redaction),
+		secretPols:  make(map[string]models.SecretPolicy),
+		secrets:     make(map[string][]models.SecretFinding),
+		operations:  make(map[string]*models.Operation),
+		summaries:   make(map[string]models.ReviewSummary),
+		llmConfigs:  make(map[string][]models.LLMProviderConfig),
+		aiBudgets:   make(map[string]models.AIBudget),
+		prompts:     make(map[string]map[string]*promptHistory),
+		aiRedactPol: make(map[string]models.AIRedactionPolicy),
+		suggestions: make(map[string][]models.Suggestion),
+		suggestFb:   make(map[string]map[string]models.SuggestionFeedback),
+		riskPols:    make(map[string]models.RiskPolicy),
+		signoffs:    make(map[string][]models.RiskSignoff),
+		teams:       make(map[string]*models.Team),
+		gates:       make(map[string]*models.ApprovalGate),
+		compliance:  make(map[string]map[string]models.ComplianceReport),
+		holds:       make(map[string]*models.LegalHold),
+		retention:   make(map[string]models.RetentionPolicy),
+		retRuns:     make(map[string][]models.RetentionRun),
+		deletions:   make(map[string]models.OrgDeletion),
+		submissions: make(map[string]*models.IntakeSubmission),
+		settings:    models.InstanceSettings{Values: make(map[string]string)},
+	}
+}
+
+// SaveOrg creates or replaces an organization.
+func (m *Memory) SaveOrg(org *models.Organization) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.orgs[org.ID] = copyOrg(org)
+	m.touchOrg(org.ID)
+	m.changed(Change{Kind: KindOrg, ID: org.ID})
+}
+
+// GetOrg returns the organization with the given ID.
+func (m *Memory) GetOrg(id string) (*models.Organization, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	org, ok := m.orgs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyOrg(org), nil
+}
+
+// ListOrgSummaries returns every organization with member and review counts,
+// ordered by creation time.
+func (m *Memory) ListOrgSummaries() []models.OrgSummary {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	summaries := make([]models.OrgSummary, 0, len(m.orgs))
+	for _, org := range m.orgs {
+		summary := models.OrgSummary{Organization: *org, Suspension: copyOf(m.suspensions[org.ID])}
+		for _, u := range m.users {
+			if u.OrgID == org.ID {
+				summary.MemberCount++
+			}
+		}
+		for _, r := range m.reviews {
+			if r.OrgID == org.ID {
+				summary.ReviewCount++
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].CreatedAt.Before(summaries[j].CreatedAt)
+	})
+	return summaries
+}
+
+// SuspendOrg marks an organization as suspended.
+func (m *Memory) SuspendOrg(s *models.OrgSuspension) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.orgs[s.OrgID]; !ok {
+		return ErrNotFound
+	}
+	m.suspensions[s.OrgID] = copyOf(s)
+	return nil
+}
+
+// UnsuspendOrg lifts a suspension. It is a no-op if the organization is not suspended.
+func (m *Memory) UnsuspendOrg(orgID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.orgs[orgID]; !ok {
+		return ErrNotFound
+	}
+	delete(m.suspensions, orgID)
+	return nil
+}
+
+// GetSuspension returns the active suspension for an organization, if any.
+func (m *Memory) GetSuspension(orgID string) (*models.OrgSuspension, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.suspensions[orgID]
+	return copyOf(s), ok
+}
+
+// SaveUser creates or replaces a user.
+func (m *Memory) SaveUser(u *models.User) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[u.ID] = copyUser(u)
+}
+
+// CreateUser stores a new user, assigning it an ID that sorts by creation
+// time. It fails with ErrDuplicate if the email is already registered.
+func (m *Memory) CreateUser(u *models.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.users {
+		if strings.EqualFold(existing.Email, u.Email) {
+			return ErrDuplicate
+		}
+	}
+	id, err := ulid.New()
+	if err != nil {
+		return err
+	}
+	u.ID = "user-" + id
+	if u.UpdatedAt.IsZero() {
+		u.UpdatedAt = u.CreatedAt
+	}
+	m.users[u.ID] = copyUser(u)
+	return nil
+}
+
+// GetUser returns the user with the given ID.
+func (m *Memory) GetUser(id string) (*models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	u, ok := m.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyUser(u), nil
+}
+
+// GetUserByEmail returns the user with the given email, compared
+// case-insensitively.
+func (m *Memory) GetUserByEmail(email string) (*models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, u := range m.users {
+		if strings.EqualFold(u.Email, email) {
+			return copyUser(u), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// DeleteUser removes a user of an organization along with their password
+// and profile. It returns ErrNotFound if the user is not in orgID.
+func (m *Memory) DeleteUser(orgID, id string) error {
+	return m.Update(func(tx *Tx) error { return tx.DeleteUser(orgID, id) })
+}
+
+// ListOrgReviewers returns the active users of an organization who can
+// review, ordered by ID.
+func (m *Memory) ListOrgReviewers(orgID string) []*models.User {
+	return m.listReviewers(orgID, time.Time{})
+}
+
+// ListAvailableReviewers is like ListOrgReviewers but also skips reviewers
+// who are out of office at t. Auto-assignment uses it.
+func (m *Memory) ListAvailableReviewers(orgID string, t time.Time) []*models.User {
+	return m.listReviewers(orgID, t)
+}
+
+// ListOrgUsers returns every user of an organization, ordered by ID.
+func (m *Memory) ListOrgUsers(orgID string) []*models.User {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var users []*models.User
+	for _, u := range m.users {
+		if u.OrgID == orgID {
+			users = append(users, copyUser(u))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users
+}
+
+// ListOperators returns the instance operators: users with the operator
+// role and users whose email is in emails.
+func (m *Memory) ListOperators(emails []string) []*models.User {
+	allow := make(map[string]bool, len(emails))
+	for _, e := range emails {
+		if e = strings.ToLower(strings.TrimSpace(e)); e != "" {
+			allow[e] = true
+		}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var users []*models.User
+	for _, u := range m.users {
+		if u.Role == "operator" || allow[strings.ToLower(u.Email)] {
+			users = append(users, copyUser(u))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users
+}
+
+func (m *Memory) listReviewers(orgID string, availableAt time.Time) []*models.User {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var users []*models.User
+	for _, u := range m.users {
+		if u.OrgID != orgID || m.inactive[u.ID] != nil {
+			continue
+		}
+		if ooo := m.away[u.ID]; !availableAt.IsZero() && ooo != nil && ooo.ActiveAt(availableAt) {
+			continue
+		}
+		if u.Role == "reviewer" || u.Role == "admin" {
+			users = append(users, copyUser(u))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users
+}
+
+// Deactivate marks a user inactive.
+func (m *Memory) Deactivate(d *models.Deactivation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[d.UserID]; !ok {
+		return ErrNotFound
+	}
+	m.inactive[d.UserID] = copyOf(d)
+	return nil
+}
+
+// Reactivate clears a user's deactivation.
+func (m *Memory) Reactivate(userID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.inactive, userID)
+}
+
+// GetDeactivation returns the deactivation record of an inactive user.
+func (m *Memory) GetDeactivation(userID string) (*models.Deactivation, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	d, ok := m.inactive[userID]
+	return copyOf(d), ok
+}
+
+// SetOutOfOffice replaces a user's out-of-office window.
+func (m *Memory) SetOutOfOffice(o *models.OutOfOffice) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.away[o.UserID] = copyOf(o)
+}
+
+// ClearOutOfOffice removes a user's out-of-office window.
+func (m *Memory) ClearOutOfOffice(userID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.away, userID)
+}
+
+// GetOutOfOffice returns a user's out-of-office window, if one is set.
+func (m *Memory) GetOutOfOffice(userID string) (*models.OutOfOffice, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	o, ok := m.away[userID]
+	return copyOf(o), ok
+}
+
+// GetProfile returns a copy of a user's profile. Users without a saved
+// profile get an empty one.
+func (m *Memory) GetProfile(userID string) models.UserProfile {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if p, ok := m.profiles[userID]; ok {
+		return *p
+	}
+	return models.UserProfile{UserID: userID}
+}
+
+// SaveProfile creates or replaces a user's profile.
+func (m *Memory) SaveProfile(p models.UserProfile) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.profiles[p.UserID] = &p
+}
+
+// SaveInvite stores an invite keyed by its token.
+func (m *Memory) SaveInvite(inv *models.Invite) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.invites[inv.Token] = inv
+}
+
+// SaveReview creates or replaces a review.
+func (m *Memory) SaveReview(r *models.Review) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, err := m.storedReview(r)
+	if err != nil {
+		return err
+	}
+	m.reviews[r.ID] = stored
+	m.changed(Change{Kind: KindReview, ID: r.ID})
+	return nil
+}
+
+// GetReview returns the review with the given ID.
+func (m *Memory) GetReview(id string) (*models.Review, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.reviews[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return m.readReview(r)
+}
+
+// AddComment appends a comment to its review, assigning an ID if it has none.
+func (m *Memory) AddComment(c *models.Comment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.addComment(c)
+}
+
+func (m *Memory) addComment(c *models.Comment) error {
+	stored, err := m.storedComment(c)
+	if err != nil {
+		return err
+	}
+	if c.ID == "" {
+		m.commentSeq++
+		c.ID = fmt.Sprintf("comment-%d", m.commentSeq)
+		stored.ID = c.ID
+	}
+	m.comments[c.ReviewID] = append(m.comments[c.ReviewID], stored)
+	return nil
+}
+
+// GetComment returns a comment on the given review.
+func (m *Memory) GetComment(reviewID, commentID string) (*models.Comment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, c := range m.comments[reviewID] {
+		if c.ID == commentID {
+			return m.readComment(c)
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// ListThreads returns the comment threads of a review with their resolution
+// state. If unresolvedOnly is set, resolved threads are omitted.
+func (m *Memory) ListThreads(reviewID string, unresolvedOnly bool) []models.CommentThread {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	threads := make([]models.CommentThread, 0, len(m.comments[reviewID]))
+	for _, c := range m.comments[reviewID] {
+		res := m.resolutions[c.ID]
+		if unresolvedOnly && res != nil {
+			continue
+		}
+		comment, ok := m.listedComment(c)
+		if !ok {
+			continue
+		}
+		thread := models.CommentThread{Comment: *comment, Resolved: res != nil, Resolution: res}
+		if p := m.profiles[c.AuthorID]; p != nil {
+			thread.AuthorAvatarURL = p.AvatarURL
+		}
+		threads = append(threads, thread)
+	}
+	return threads
+}
+
+// ResolveThread marks a comment thread as resolved.
+func (m *Memory) ResolveThread(res *models.ThreadResolution) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resolutions[res.CommentID] = res
+}
+
+// UnresolveThread reopens a comment thread.
+func (m *Memory) UnresolveThread(commentID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.resolutions, commentID)
+}
+
+// CountUnresolved returns the number of unresolved threads on a review.
+func (m *Memory) CountUnresolved(reviewID string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	n := 0
+	for _, c := range m.comments[reviewID] {
+		if m.resolutions[c.ID] == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// ListComments returns the comments on a review in the order they were added.
+func (m *Memory) ListComments(reviewID string) []*models.Comment {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	comments := make([]*models.Comment, 0, len(m.comments[reviewID]))
+	for _, c := range m.comments[reviewID] {
+		if comment, ok := m.listedComment(c); ok {
+			comments = append(comments, comment)
+		}
+	}
+	return comments
+}
+
+// SetChecklist replaces the checklist of a review.
+func (m *Memory) SetChecklist(reviewID string, items []*models.ChecklistItem) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checklists[reviewID] = copyAll(items)
+}
+
+// ListChecklist returns the checklist items of a review.
+func (m *Memory) ListChecklist(reviewID string) []*models.ChecklistItem {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return copyAll(m.checklists[reviewID])
+}
+
+// SaveApproval records the approval of a review and sets the review's
+// ApprovedBy and ApprovedAt.
+func (m *Memory) SaveApproval(a *models.Approval) {
+	m.Update(func(tx *Tx) error {
+		tx.SaveApproval(a)
+		return nil
+	})
+}
+
+// GetApproval returns the approval record of a review, if it has been approved.
+func (m *Memory) GetApproval(reviewID string) (*models.Approval, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, ok := m.approvals[reviewID]
+	return copyApproval(a), ok
+}
+
+// Assign sets the reviewer of a review, replacing any previous assignment.
+func (m *Memory) Assign(a *models.Assignment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.reviews[a.ReviewID]; !ok {
+		return ErrNotFound
+	}
+	m.assignments[a.ReviewID] = copyOf(a)
+	return nil
+}
+
+// Unassign removes the reviewer of a review.
+func (m *Memory) Unassign(reviewID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.assignments, reviewID)
+}
+
+// GetAssignment returns the current assignment of a review.
+func (m *Memory) GetAssignment(reviewID string) (*models.Assignment, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, ok := m.assignments[reviewID]
+	return copyOf(a), ok
+}
+
+// ListOpenAssigned returns the unapproved reviews assigned to a reviewer.
+func (m *Memory) ListOpenAssigned(reviewerID string) []*models.Review {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var reviews []*models.Review
+	for id, a := range m.assignments {
+		if a.ReviewerID != reviewerID || m.approvals[id] != nil {
+			continue
+		}
+		if r, ok := m.reviews[id]; ok {
+			if read, ok := m.listedReview(r); ok {
+				reviews = append(reviews, read)
+			}
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].CreatedAt.Before(reviews[j].CreatedAt) })
+	return reviews
+}
+
+// SetReviewStatus changes the status of a review.
+func (m *Memory) SetReviewStatus(id, status string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.reviews[id]
+	if !ok {
+		return ErrNotFound
+	}
+	updated := *r
+	updated.Status = status
+	updated.UpdatedAt = time.Now()
+	m.reviews[id] = &updated
+	m.changed(Change{Kind: KindReview, ID: id})
+	return nil
+}
+
+// ListOpenReviews returns every review that has not been approved, across
+// all organizations.
+func (m *Memory) ListOpenReviews() []*models.Review {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var reviews []*models.Review
+	for id, r := range m.reviews {
+		if m.approvals[id] != nil || r.Status == "approved" {
+			continue
+		}
+		if read, ok := m.listedReview(r); ok {
+			reviews = append(reviews, read)
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].CreatedAt.Before(reviews[j].CreatedAt) })
+	return reviews
+}
+
+// SetPriority sets the priority of a review.
+func (m *Memory) SetPriority(reviewID, priority string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.priorities[reviewID] = priority
+}
+
+// GetPriority returns the priority of a review, defaulting to normal.
+func (m *Memory) GetPriority(reviewID string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if p, ok := m.priorities[reviewID]; ok {
+		return p
+	}
+	return models.PriorityNormal
+}
+
+// ReopenReview clears the approval of a review and returns it to the given
+// status so it can be edited again.
+func (m *Memory) ReopenReview(id, status string) (*models.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.reviews[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	delete(m.approvals, id)
+	delete(m.signoffs, id)
+	updated := *r
+	updated.Status = status
+	updated.UpdatedAt = time.Now()
+	updated.ApprovedBy, updated.ApprovedAt = "", nil
+	m.reviews[id] = &updated
+	m.changed(Change{Kind: KindReview, ID: id})
+	return m.readReview(&updated)
+}
+
+// AppendAudit records an audit event, assigning its ID and timestamp.
+func (m *Memory) AppendAudit(e *models.AuditEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.appendAudit(e)
+}
+
+func (m *Memory) appendAudit(e *models.AuditEvent) {
+	e.ID = fmt.Sprintf("audit-%d", len(m.audit)+1)
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	m.audit = append(m.audit, copyAudit(e))
+}
+
+// ListAudit returns the audit events of an organization, oldest first.
+func (m *Memory) ListAudit(orgID string) []*models.AuditEvent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var events []*models.AuditEvent
+	for _, e := range m.audit {
+		if e.OrgID == orgID {
+			events = append(events, copyAudit(e))
+		}
+	}
+	return events
+}
+
+// GetPolicy returns the effective review policy of an organization: the
+// topmost enforced policy among its ancestors, else its own, else the
+// nearest ancestor's. Without any, it gets the zero-value defaults.
+func (m *Memory) GetPolicy(orgID string) models.OrgPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ancestors := m.ancestors(orgID)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if p, ok := m.policies[ancestors[i]]; ok && p.Enforced {
+			return inheritedPolicy(p, orgID)
+		}
+	}
+	if p, ok := m.policies[orgID]; ok {
+		return p
+	}
+	for _, id := range ancestors {
+		if p, ok := m.policies[id]; ok {
+			return inheritedPolicy(p, orgID)
+		}
+	}
+	return models.OrgPolicy{OrgID: orgID}
+}
+
+// OwnPolicy returns the policy set on the organization itself, ignoring
+// inheritance.
+func (m *Memory) OwnPolicy(orgID string) models.OrgPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	p, ok := m.policies[orgID]
+	if !ok {
+		p.OrgID = orgID
+	}
+	return p
+}
+
+func inheritedPolicy(p models.OrgPolicy, orgID string) models.OrgPolicy {
+	p.InheritedFrom = p.OrgID
+	p.OrgID = orgID
+	return p
+}
+
+// SetPolicy replaces the review policy of an organization.
+func (m *Memory) SetPolicy(p models.OrgPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policies[p.OrgID] = p
+	m.changed(Change{Kind: KindPolicy, ID: p.OrgID})
+}
+
+// SetLabels replaces the labels of a review.
+func (m *Memory) SetLabels(reviewID string, labels []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.labels[reviewID] = labels
+}
+
+// GetLabels returns the labels of a review.
+func (m *Memory) GetLabels(reviewID string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]string(nil), m.labels[reviewID]...)
+}
+
+// AddSubscription stores a subscription, assigning its ID.
+func (m *Memory) AddSubscription(sub *models.Subscription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addSubscription(sub)
+}
+
+func (m *Memory) addSubscription(sub *models.Subscription) {
+	m.subSeq++
+	sub.ID = fmt.Sprintf("sub-%d", m.subSeq)
+	m.subs[sub.ID] = copyOf(sub)
+}
+
+// RemoveSubscription deletes a subscription owned by userID.
+func (m *Memory) RemoveSubscription(userID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub, ok := m.subs[id]
+	if !ok || sub.UserID != userID {
+		return ErrNotFound
+	}
+	delete(m.subs, id)
+	return nil
+}
+
+// ListSubscriptions returns the subscriptions of a user.
+func (m *Memory) ListSubscriptions(userID string) []*models.Subscription {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var subs []*models.Subscription
+	for _, sub := range m.subs {
+		if sub.UserID == userID {
+			subs = append(subs, copyOf(sub))
+		}
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].CreatedAt.Before(subs[j].CreatedAt) })
+	return subs
+}
+
+// Watchers returns the distinct users in an organization with a
+// subscription matching the described review.
+func (m *Memory) Watchers(orgID, reviewID, authorID string, labels []string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	seen := make(map[string]bool)
+	var users []string
+	for _, sub := range m.subs {
+		if sub.OrgID != orgID || seen[sub.UserID] || !sub.Matches(reviewID, authorID, labels) {
+			continue
+		}
+		seen[sub.UserID] = true
+		users = append(users, sub.UserID)
+	}
+	sort.Strings(users)
+	return users
+}
+
+// AddNotification appends a notification to its recipient's inbox, assigning its ID.
+func (m *Memory) AddNotification(n *models.Notification) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addNotification(n)
+}
+
+func (m *Memory) addNotification(n *models.Notification) {
+	m.notifySeq++
+	n.ID = fmt.Sprintf("notification-%d", m.notifySeq)
+	m.inbox[n.UserID] = append(m.inbox[n.UserID], copyNotification(n))
+}
+
+// ListNotifications returns a user's notifications, newest first.
+func (m *Memory) ListNotifications(userID string) []*models.Notification {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	src := m.inbox[userID]
+	out := make([]*models.Notification, len(src))
+	for i, n := range src {
+		out[len(src)-1-i] = copyNotification(n)
+	}
+	return out
+}
+
+// GetCalendar returns the work calendar of an organization, if one is set,
+// or else that of its nearest ancestor with one.
+func (m *Memory) GetCalendar(orgID string) (models.WorkCalendar, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if c, ok := m.calendars[orgID]; ok {
+		return c, true
+	}
+	for _, id := range m.ancestors(orgID) {
+		if c, ok := m.calendars[id]; ok {
+			return c, true
+		}
+	}
+	return models.WorkCalendar{}, false
+}
+
+// SetCalendar replaces the work calendar of an organization.
+func (m *Memory) SetCalendar(c models.WorkCalendar) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calendars[c.OrgID] = c
+}
+
+// AddLink stores an external link, assigning its ID.
+func (m *Memory) AddLink(l *models.ExternalLink) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.linkSeq++
+	l.ID = fmt.Sprintf("link-%d", m.linkSeq)
+	m.links[l.ID] = copyOf(l)
+}
+
+// GetLink returns an external link of the given review.
+func (m *Memory) GetLink(reviewID, id string) (*models.ExternalLink, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	l, ok := m.links[id]
+	if !ok || l.ReviewID != reviewID {
+		return nil, ErrNotFound
+	}
+	return copyOf(l), nil
+}
+
+// RemoveLink deletes an external link.
+func (m *Memory) RemoveLink(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.links, id)
+}
+
+// UpdateLinkStatus records the latest status fetched from the external tracker.
+func (m *Memory) UpdateLinkStatus(id, status string, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if l, ok := m.links[id]; ok {
+		updated := *l
+		updated.Status, updated.StatusSyncedAt = status, &at
+		m.links[id] = &updated
+	}
+}
+
+// ListLinks returns the external links of a review.
+func (m *Memory) ListLinks(reviewID string) []*models.ExternalLink {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var links []*models.ExternalLink
+	for _, l := range m.links {
+		if l.ReviewID == reviewID {
+			links = append(links, copyOf(l))
+		}
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].CreatedAt.Before(links[j].CreatedAt) })
+	return links
+}
+
+// FindLinksByKey returns an organization's links to the given external key.
+func (m *Memory) FindLinksByKey(orgID, key string) []*models.ExternalLink {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var links []*models.ExternalLink
+	for _, l := range m.links {
+		if l.OrgID == orgID && strings.EqualFold(l.Key, key) {
+			links = append(links, copyOf(l))
+		}
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].CreatedAt.Before(links[j].CreatedAt) })
+	return links
+}
+
+// GetJiraConfig returns the Jira connector configuration of an organization.
+func (m *Memory) GetJiraConfig(orgID string) (models.JiraConfig, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, ok := m.jira[orgID]
+	return c, ok
+}
+
+// SetJiraConfig replaces the Jira connector configuration of an organization.
+func (m *Memory) SetJiraConfig(c models.JiraConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jira[c.OrgID] = c
+}
+
+// GetEscalationConfig returns the paging configuration of an organization.
+func (m *Memory) GetEscalationConfig(orgID string) (models.EscalationConfig, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, ok := m.escalation[orgID]
+	return c, ok
+}
+
+// SetEscalationConfig replaces the paging configuration of an organization.
+func (m *Memory) SetEscalationConfig(c models.EscalationConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.escalation[c.OrgID] = c
+}
+
+// RecordEscalation stores a page unless one was already recorded for the
+// review, and reports whether it was stored.
+func (m *Memory) RecordEscalation(e *models.Escalation) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.escalated[e.ReviewID]; ok {
+		return false
+	}
+	m.escalated[e.ReviewID] = copyOf(e)
+	return true
+}
+
+// ForgetEscalation removes a page record so the review can be paged again.
+func (m *Memory) ForgetEscalation(reviewID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.escalated, reviewID)
+}
+
+// GetEscalation returns the page recorded for a review, if any.
+func (m *Memory) GetEscalation(reviewID string) (*models.Escalation, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.escalated[reviewID]
+	return copyOf(e), ok
+}
+
+// Stats computes instance-wide counters.
+func (m *Memory) Stats() models.InstanceStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := models.InstanceStats{
+		Organizations:   len(m.orgs),
+		SuspendedOrgs:   len(m.suspensions),
+		Users:           len(m.users),
+		Reviews:         len(m.reviews),
+		ReviewsByStatus: make(map[string]int),
+		GeneratedAt:     time.Now(),
+	}
+	for _, r := range m.reviews {
+		stats.ReviewsByStatus[r.Status]++
+	}
+	return stats
+}
+
+// Settings returns a copy of the instance-wide settings.
+func (m *Memory) Settings() models.InstanceSettings {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s := m.settings
+	s.Values = make(map[string]string, len(m.settings.Values))
+	for k, v := range m.settings.Values {
+		s.Values[k] = v
+	}
+	return s
+}
+
+// UpdateSettings merges values into the instance settings. An empty value
+// removes the key.
+func (m *Memory) UpdateSettings(values map[string]string, updatedBy string) models.InstanceSettings {
+	m.mu.Lock()
+	for k, v := range values {
+		if v == "" {
+			delete(m.settings.Values, k)
+			continue
+		}
+		m.settings.Values[k] = v
+	}
+	m.settings.UpdatedBy = updatedBy
+	m.settings.UpdatedAt = time.Now()
+	m.mu.Unlock()
+
+	return m.Settings()
+}
+
+// Maintenance returns the current maintenance mode state.
+func (m *Memory) Maintenance() models.MaintenanceMode {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.maintenance
+}
+
+// SetMaintenance replaces the maintenance mode state.
+func (m *Memory) SetMaintenance(mode models.MaintenanceMode) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maintenance = mode
+}
@@ -0,0 +1,372 @@
+// Package storetest is a conformance suite for store implementations.
+// A driver's tests call Run with a constructor for empty stores:
+//
+//	func TestMemory(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T) storetest.Store { return store.NewMemory() })
+//	}
+//
+// The suite checks the behavior callers rely on: round trips, not-found
+// and duplicate errors, result ordering and paging, that units of work
+// commit or roll back as a whole, and that concurrent writers do not lose
+// updates.
+package storetest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Store is the part of the store contract the suite covers.
+type Store interface {
+	CreateUser(u *models.User) error
+	GetUser(id string) (*models.User, error)
+	SaveReview(r *models.Review) error
+	GetReview(id string) (*models.Review, error)
+	SetReviewStatus(id, status string) error
+	AddComment(c *models.Comment) error
+	GetComment(reviewID, commentID string) (*models.Comment, error)
+	ListComments(reviewID string) []*models.Comment
+	AppendAudit(e *models.AuditEvent)
+	ListAudit(orgID string) []*models.AuditEvent
+	AddNotification(n *models.Notification)
+	ListNotifications(userID string) []*models.Notification
+	AuditSeq() int
+	AuditPage(orgID string, after, until, limit int) ([]models.AuditEvent, int)
+	Update(fn func(tx *store.Tx) error) error
+}
+
+// Run runs the suite, creating a fresh store for each case.
+func Run(t *testing.T, newStore func(t *testing.T) Store) {
+	cases := []struct {
+		name string
+		fn   func(t *testing.T, s Store)
+	}{
+		{"ReviewRoundTrip", testReviewRoundTrip},
+		{"NotFound", testNotFound},
+		{"DuplicateEmail", testDuplicateEmail},
+		{"CommentOrder", testCommentOrder},
+		{"AuditOrderAndScope", testAuditOrderAndScope},
+		{"AuditPages", testAuditPages},
+		{"NotificationsNewestFirst", testNotificationsNewestFirst},
+		{"ConcurrentComments", testConcurrentComments},
+		{"ConcurrentStatusUpdates", testConcurrentStatusUpdates},
+		{"TxCommit", testTxCommit},
+		{"TxRollback", testTxRollback},
+		{"ConcurrentTx", testConcurrentTx},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			c.fn(t, newStore(t))
+		})
+	}
+}
+
+func review(id string) *models.Review {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	return &models.Review{ID: id, OrgID: "org-1", AuthorID: "user-1", Title: "Title " + id, Content: "Content " + id, Status: "pending", CreatedAt: now, UpdatedAt: now}
+}
+
+func testReviewRoundTrip(t *testing.T, s Store) {
+	want := review("review-1")
+	s.SaveReview(want)
+	got, err := s.GetReview(want.ID)
+	if err != nil {
+		t.Fatalf("GetReview: %v", err)
+	}
+	if got.Title != want.Title || got.Content != want.Content || got.OrgID != want.OrgID || got.Status != want.Status || !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Fatalf("GetReview = %+v, want %+v", got, want)
+	}
+
+	want.Title = "Renamed"
+	s.SaveReview(want)
+	if got, _ := s.GetReview(want.ID); got.Title != "Renamed" {
+		t.Fatalf("after SaveReview replace, title = %q, want %q", got.Title, "Renamed")
+	}
+}
+
+func testNotFound(t *testing.T, s Store) {
+	if _, err := s.GetReview("missing"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("GetReview(missing) error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.GetUser("missing"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("GetUser(missing) error = %v, want ErrNotFound", err)
+	}
+	if err := s.SetReviewStatus("missing", "approved"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("SetReviewStatus(missing) error = %v, want ErrNotFound", err)
+	}
+	s.SaveReview(review("review-1"))
+	if _, err := s.GetComment("review-1", "missing"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("GetComment(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func testDuplicateEmail(t *testing.T, s Store) {
+	if err := s.CreateUser(&models.User{Email: "a@example.com", OrgID: "org-1"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	err := s.CreateUser(&models.User{Email: "A@Example.com", OrgID: "org-2"})
+	if !errors.Is(err, store.ErrDuplicate) {
+		t.Fatalf("CreateUser with the same email in another case = %v, want ErrDuplicate", err)
+	}
+}
+
+func testCommentOrder(t *testing.T, s Store) {
+	s.SaveReview(review("review-1"))
+	for i := 0; i < 20; i++ {
+		s.AddComment(&models.Comment{ReviewID: "review-1", AuthorID: "user-1", Body: fmt.Sprint(i), CreatedAt: time.Now()})
+	}
+	comments := s.ListComments("review-1")
+	if len(comments) != 20 {
+		t.Fatalf("ListComments returned %d comments, want 20", len(comments))
+	}
+	seen := make(map[string]bool)
+	for i, c := range comments {
+		if c.Body != fmt.Sprint(i) {
+			t.Fatalf("comment %d has body %q, want insertion order", i, c.Body)
+		}
+		if c.ID == "" || seen[c.ID] {
+			t.Fatalf("comment %d has empty or repeated ID %q", i, c.ID)
+		}
+		seen[c.ID] = true
+	}
+}
+
+func testAuditOrderAndScope(t *testing.T, s Store) {
+	for i := 0; i < 10; i++ {
+		org := "org-1"
+		if i%2 == 1 {
+			org = "org-2"
+		}
+		s.AppendAudit(&models.AuditEvent{OrgID: org, Action: fmt.Sprint(i)})
+	}
+	events := s.ListAudit("org-1")
+	if len(events) != 5 {
+		t.Fatalf("ListAudit returned %d events, want 5", len(events))
+	}
+	for i, e := range events {
+		if e.OrgID != "org-1" || e.Action != fmt.Sprint(i*2) {
+			t.Fatalf("event %d = %s/%s, want org-1 events oldest first", i, e.OrgID, e.Action)
+		}
+		if e.ID == "" || e.CreatedAt.IsZero() {
+			t.Fatalf("event %d has no ID or timestamp", i)
+		}
+	}
+}
+
+func testNotificationsNewestFirst(t *testing.T, s Store) {
+	for i := 0; i < 5; i++ {
+		s.AddNotification(&models.Notification{UserID: "user-1", ReviewID: fmt.Sprint(i), CreatedAt: time.Now()})
+	}
+	s.AddNotification(&models.Notification{UserID: "user-2", ReviewID: "other"})
+	got := s.ListNotifications("user-1")
+	if len(got) != 5 {
+		t.Fatalf("ListNotifications returned %d, want 5", len(got))
+	}
+	for i, n := range got {
+		if n.ReviewID != fmt.Sprint(4-i) {
+			t.Fatalf("notification %d is about %q, want newest first", i, n.ReviewID)
+		}
+	}
+}
+
+func testConcurrentComments(t *testing.T, s Store) {
+	s.SaveReview(review("review-1"))
+	const writers, each = 8, 50
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				s.AddComment(&models.Comment{ReviewID: "review-1", AuthorID: fmt.Sprint(w), Body: fmt.Sprint(i)})
+			}
+		}(w)
+	}
+	wg.Wait()
+	comments := s.ListComments("review-1")
+	if len(comments) != writers*each {
+		t.Fatalf("after concurrent writes ListComments returned %d, want %d", len(comments), writers*each)
+	}
+	ids := make(map[string]bool)
+	for _, c := range comments {
+		ids[c.ID] = true
+	}
+	if len(ids) != writers*each {
+		t.Fatalf("concurrent writes produced %d distinct IDs, want %d", len(ids), writers*each)
+	}
+}
+
+func testConcurrentStatusUpdates(t *testing.T, s Store) {
+	const n = 50
+	for i := 0; i < n; i++ {
+		s.SaveReview(review(fmt.Sprintf("review-%d", i)))
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		id := fmt.Sprintf("review-%d", i)
+		go func() {
+			defer wg.Done()
+			if err := s.SetReviewStatus(id, "approved"); err != nil {
+				t.Errorf("SetReviewStatus(%s): %v", id, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := s.GetReview(id); err != nil {
+				t.Errorf("GetReview(%s) during update: %v", id, err)
+			}
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < n; i++ {
+		r, err := s.GetReview(fmt.Sprintf("review-%d", i))
+		if err != nil || r.Status != "approved" {
+			t.Fatalf("review-%d after concurrent update = %+v, %v; want approved", i, r, err)
+		}
+	}
+}
+
+func testAuditPages(t *testing.T, s Store) {
+	for i := 0; i < 25; i++ {
+		org := "org-1"
+		if i%3 == 0 {
+			org = "org-2"
+		}
+		s.AppendAudit(&models.AuditEvent{OrgID: org, Action: fmt.Sprint(i)})
+	}
+	until := s.AuditSeq()
+	s.AppendAudit(&models.AuditEvent{OrgID: "org-1", Action: "after the snapshot"})
+
+	want := s.ListAudit("org-1")
+	want = want[:len(want)-1]
+	for _, limit := range []int{1, 4, 100} {
+		var got []models.AuditEvent
+		for pos := 0; pos < until; {
+			var page []models.AuditEvent
+			page, pos = s.AuditPage("org-1", pos, until, limit)
+			if len(page) > limit {
+				t.Fatalf("limit %d: page of %d events", limit, len(page))
+			}
+			got = append(got, page...)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("limit %d: paging returned %d events, want %d", limit, len(got), len(want))
+		}
+		for i := range got {
+			if got[i].ID != want[i].ID {
+				t.Fatalf("limit %d: event %d = %s, want %s", limit, i, got[i].ID, want[i].ID)
+			}
+		}
+	}
+}
+
+func testTxCommit(t *testing.T, s Store) {
+	err := s.Update(func(tx *store.Tx) error {
+		tx.SaveReview(review("review-1"))
+		if _, err := tx.GetReview("review-1"); err != nil {
+			return fmt.Errorf("unit of work does not see its own write: %w", err)
+		}
+		tx.AddComment(&models.Comment{ReviewID: "review-1", Body: "first"})
+		tx.AppendAudit(&models.AuditEvent{OrgID: "org-1", Action: "review.created"})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := s.GetReview("review-1"); err != nil {
+		t.Errorf("GetReview after commit: %v", err)
+	}
+	if n := len(s.ListComments("review-1")); n != 1 {
+		t.Errorf("ListComments after commit returned %d, want 1", n)
+	}
+	if n := len(s.ListAudit("org-1")); n != 1 {
+		t.Errorf("ListAudit after commit returned %d, want 1", n)
+	}
+}
+
+func testTxRollback(t *testing.T, s Store) {
+	errAbort := errors.New("abort")
+	tests := []struct {
+		name string
+		end  func() error
+	}{
+		{"error", func() error { return errAbort }},
+		{"panic", func() error { panic(errAbort) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.SaveReview(review("review-1"))
+			before, _ := s.GetReview("review-1")
+			var err error
+			func() {
+				defer func() {
+					if p := recover(); p != nil {
+						err = p.(error)
+					}
+				}()
+				err = s.Update(func(tx *store.Tx) error {
+					changed := review("review-1")
+					changed.Title = "Changed"
+					tx.SaveReview(changed)
+					tx.SaveReview(review("review-2"))
+					tx.AddComment(&models.Comment{ReviewID: "review-1", Body: "dropped"})
+					tx.AppendAudit(&models.AuditEvent{OrgID: "org-1", Action: "dropped"})
+					return tt.end()
+				})
+			}()
+			if !errors.Is(err, errAbort) {
+				t.Fatalf("Update = %v, want the unit of work's error", err)
+			}
+			if got, _ := s.GetReview("review-1"); got.Title != before.Title {
+				t.Errorf("rolled back review title = %q, want %q", got.Title, before.Title)
+			}
+			if _, err := s.GetReview("review-2"); !errors.Is(err, store.ErrNotFound) {
+				t.Errorf("GetReview of a rolled back review = %v, want ErrNotFound", err)
+			}
+			if n := len(s.ListComments("review-1")); n != 0 {
+				t.Errorf("ListComments after rollback returned %d, want 0", n)
+			}
+			if n := len(s.ListAudit("org-1")); n != 0 {
+				t.Errorf("ListAudit after rollback returned %d, want 0", n)
+			}
+		})
+	}
+}
+
+func testConcurrentTx(t *testing.T, s Store) {
+	s.SaveReview(review("review-1"))
+	const n = 50
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.Update(func(tx *store.Tx) error {
+				r, err := tx.GetReview("review-1")
+				if err != nil {
+					return err
+				}
+				var count int
+				fmt.Sscan(r.Content, &count)
+				r.Content = fmt.Sprint(count + 1)
+				tx.SaveReview(r)
+				return nil
+			})
+			if err != nil {
+				t.Errorf("Update: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if r, _ := s.GetReview("review-1"); r.Content != fmt.Sprint(n) {
+		t.Fatalf("after %d concurrent read-modify-write units of work the count is %s", n, r.Content)
+	}
+}
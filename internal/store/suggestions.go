@@ -0,0 +1,144 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// OrgLabels returns the labels used on the organization's reviews, sorted.
+func (m *Memory) OrgLabels(orgID string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var labels []string
+	for id, ls := range m.labels {
+		if r, ok := m.reviews[id]; ok && r.OrgID == orgID {
+			for _, l := range ls {
+				if !slices.Contains(labels, l) {
+					labels = append(labels, l)
+				}
+			}
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// ReplaceSuggestions replaces a review's pending suggestions with fresh
+// ones, assigning their IDs. Decided suggestions are kept, and fresh ones
+// repeating them are dropped.
+func (m *Memory) ReplaceSuggestions(reviewID string, fresh []models.Suggestion) []models.Suggestion {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var kept []models.Suggestion
+	for _, s := range m.suggestions[reviewID] {
+		if s.Status != models.SuggestionPending {
+			kept = append(kept, s)
+		}
+	}
+	added := []models.Suggestion{}
+	for _, s := range fresh {
+		if slices.ContainsFunc(kept, func(k models.Suggestion) bool { return k.Kind == s.Kind && k.Value == s.Value }) {
+			continue
+		}
+		m.suggestSeq++
+		s.ID = fmt.Sprintf("suggestion-%d", m.suggestSeq)
+		s.ReviewID = reviewID
+		s.Status = models.SuggestionPending
+		added = append(added, s)
+	}
+	m.suggestions[reviewID] = append(kept, added...)
+	return added
+}
+
+// ListSuggestions returns a review's suggestions, optionally only those
+// with status, best first.
+func (m *Memory) ListSuggestions(reviewID, status string) []models.Suggestion {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.Suggestion{}
+	for _, s := range m.suggestions[reviewID] {
+		if status == "" || s.Status == status {
+			out = append(out, s)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
+
+// GetSuggestion returns one of a review's suggestions.
+func (m *Memory) GetSuggestion(reviewID, id string) (models.Suggestion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, s := range m.suggestions[reviewID] {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return models.Suggestion{}, ErrNotFound
+}
+
+// DecideSuggestion accepts or rejects a pending suggestion and counts the
+// decision in the organization's feedback. It returns ErrDecided if the
+// suggestion was already decided.
+func (m *Memory) DecideSuggestion(reviewID, id, status, decidedBy string) (models.Suggestion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, s := range m.suggestions[reviewID] {
+		if s.ID != id {
+			continue
+		}
+		if s.Status != models.SuggestionPending {
+			return s, ErrDecided
+		}
+		now := time.Now()
+		s.Status, s.DecidedBy, s.DecidedAt = status, decidedBy, &now
+		list := slices.Clone(m.suggestions[reviewID])
+		list[i] = s
+		m.suggestions[reviewID] = list
+
+		if m.suggestFb[s.OrgID] == nil {
+			m.suggestFb[s.OrgID] = make(map[string]models.SuggestionFeedback)
+		}
+		key := s.Kind + "/" + s.Value
+		fb := m.suggestFb[s.OrgID][key]
+		fb.Kind, fb.Value = s.Kind, s.Value
+		if status == models.SuggestionAccepted {
+			fb.Accepted++
+		} else {
+			fb.Rejected++
+		}
+		m.suggestFb[s.OrgID][key] = fb
+		return s, nil
+	}
+	return models.Suggestion{}, ErrNotFound
+}
+
+// SuggestionFeedback returns the organization's decisions on suggestions,
+// by kind and value.
+func (m *Memory) SuggestionFeedback(orgID string) []models.SuggestionFeedback {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.SuggestionFeedback{}
+	for _, fb := range m.suggestFb[orgID] {
+		out = append(out, fb)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
+
+// ResetSuggestionFeedback forgets the organization's decisions on
+// suggestions.
+func (m *Memory) ResetSuggestionFeedback(orgID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.suggestFb, orgID)
+}
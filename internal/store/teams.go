@@ -0,0 +1,97 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+func copyTeam(t *models.Team) models.Team {
+	out := *t
+	out.Members = append([]string{}, t.Members...)
+	return out
+}
+
+// teamConflict reports whether another team of the organization has t's
+// name, ignoring case.
+func (m *Memory) teamConflict(t models.Team) bool {
+	for _, other := range m.teams {
+		if other.ID != t.ID && other.OrgID == t.OrgID && strings.EqualFold(other.Name, t.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateTeam stores a new team, assigning its ID. Team names are unique
+// within an organization.
+func (m *Memory) CreateTeam(t models.Team) (models.Team, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.teamConflict(t) {
+		return models.Team{}, ErrDuplicate
+	}
+	m.teamSeq++
+	t.ID = fmt.Sprintf("team-%d", m.teamSeq)
+	m.teams[t.ID] = &t
+	return copyTeam(&t), nil
+}
+
+// GetTeam returns a team of the organization.
+func (m *Memory) GetTeam(orgID, id string) (models.Team, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.teams[id]
+	if !ok || t.OrgID != orgID {
+		return models.Team{}, ErrNotFound
+	}
+	return copyTeam(t), nil
+}
+
+// ListTeams returns the organization's teams by name.
+func (m *Memory) ListTeams(orgID string) []models.Team {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.Team{}
+	for _, t := range m.teams {
+		if t.OrgID == orgID {
+			out = append(out, copyTeam(t))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// UserTeams returns the organization's teams a user is a member of, by name.
+func (m *Memory) UserTeams(orgID, userID string) []models.Team {
+	teams := m.ListTeams(orgID)
+	return slices.DeleteFunc(teams, func(t models.Team) bool { return !slices.Contains(t.Members, userID) })
+}
+
+// UpdateTeam replaces a team of the organization.
+func (m *Memory) UpdateTeam(t models.Team) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cur, ok := m.teams[t.ID]; !ok || cur.OrgID != t.OrgID {
+		return ErrNotFound
+	}
+	if m.teamConflict(t) {
+		return ErrDuplicate
+	}
+	m.teams[t.ID] = &t
+	return nil
+}
+
+// DeleteTeam removes a team of the organization.
+func (m *Memory) DeleteTeam(orgID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t, ok := m.teams[id]; !ok || t.OrgID != orgID {
+		return ErrNotFound
+	}
+	delete(m.teams, id)
+	return nil
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// latestTemplate returns the newest version of a template. The caller must
+// hold the lock.
+func (m *Memory) latestTemplate(orgID, kind, name string) *models.Template {
+	var latest *models.Template
+	for _, t := range m.templates {
+		if t.OrgID == orgID && t.Kind == kind && t.Name == name && (latest == nil || t.Version > latest.Version) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// AddTemplateVersion records t as the next version of its template,
+// assigning its ID and version. If the latest version has the same digest
+// nothing is recorded and that version is returned with false.
+func (m *Memory) AddTemplateVersion(t models.Template) (models.Template, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	latest := m.latestTemplate(t.OrgID, t.Kind, t.Name)
+	t.Version = 1
+	if latest != nil {
+		if latest.Digest == t.Digest {
+			return *latest, false
+		}
+		t.Version = latest.Version + 1
+	}
+	m.templateSeq++
+	t.ID = fmt.Sprintf("template-%d", m.templateSeq)
+	m.templates[t.ID] = &t
+	return t, true
+}
+
+// ListTemplates returns the latest version of each of the organization's
+// templates, optionally only those of kind, sorted by kind and name.
+func (m *Memory) ListTemplates(orgID, kind string) []models.Template {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	latest := make(map[string]*models.Template)
+	for _, t := range m.templates {
+		if t.OrgID != orgID || (kind != "" && t.Kind != kind) {
+			continue
+		}
+		key := t.Kind + "/" + t.Name
+		if cur, ok := latest[key]; !ok || t.Version > cur.Version {
+			latest[key] = t
+		}
+	}
+	out := make([]models.Template, 0, len(latest))
+	for _, t := range latest {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// TemplateVersions returns every version of a template, oldest first, or
+// ErrNotFound if the organization has no such template.
+func (m *Memory) TemplateVersions(orgID, kind, name string) ([]models.Template, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var out []models.Template
+	for _, t := range m.templates {
+		if t.OrgID == orgID && t.Kind == kind && t.Name == name {
+			out = append(out, *t)
+		}
+	}
+	if len(out) == 0 {
+		return nil, ErrNotFound
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// GetTemplate returns one version of a template; version 0 selects the
+// latest.
+func (m *Memory) GetTemplate(orgID, kind, name string, version int) (models.Template, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if version == 0 {
+		if t := m.latestTemplate(orgID, kind, name); t != nil {
+			return *t, nil
+		}
+		return models.Template{}, ErrNotFound
+	}
+	for _, t := range m.templates {
+		if t.OrgID == orgID && t.Kind == kind && t.Name == name && t.Version == version {
+			return *t, nil
+		}
+	}
+	return models.Template{}, ErrNotFound
+}
+
+// DeleteTemplate removes every version of a template.
+func (m *Memory) DeleteTemplate(orgID, kind, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	found := false
+	for id, t := range m.templates {
+		if t.OrgID == orgID && t.Kind == kind && t.Name == name {
+			delete(m.templates, id)
+			found = true
+		}
+	}
+	if !found {
+		return ErrNotFound
+	}
+	return nil
+}
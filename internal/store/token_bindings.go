@@ -0,0 +1,49 @@
+package store
+
+import (
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// SetTokenBindingMode sets how the organization's login tokens are bound
+// to clients; an empty mode turns binding off.
+func (m *Memory) SetTokenBindingMode(orgID, mode string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if mode == "" {
+		delete(m.bindModes, orgID)
+		return
+	}
+	m.bindModes[orgID] = mode
+}
+
+// TokenBindingMode returns the organization's token binding mode, or ""
+// when binding is off.
+func (m *Memory) TokenBindingMode(orgID string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.bindModes[orgID]
+}
+
+// BindToken records a token binding and drops expired ones.
+func (m *Memory) BindToken(b models.TokenBinding) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for hash, existing := range m.tokenBinds {
+		if now.After(existing.ExpiresAt) {
+			delete(m.tokenBinds, hash)
+		}
+	}
+	m.tokenBinds[b.TokenHash] = b
+}
+
+// TokenBinding returns the binding of the token with the given hash, if
+// it has one.
+func (m *Memory) TokenBinding(tokenHash string) (models.TokenBinding, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	b, ok := m.tokenBinds[tokenHash]
+	return b, ok
+}
@@ -0,0 +1,431 @@
+package store
+
+import (
+	"slices"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// Tx is a unit of work on the store: a group of writes that other callers
+// see all at once, or not at all. Reads through the Tx see its own
+// writes.
+type Tx struct {
+	m *Memory
+	// undo reverts the writes made so far, in reverse order.
+	undo    []func()
+	changes []Change
+	// outbox holds the events to journal before committing.
+	outbox   []*models.OutboxEvent
+	onCommit []func()
+}
+
+// Update runs fn as a unit of work, holding the store's write lock until
+// it returns. If fn returns an error or panics, every write made through
+// tx is reverted; otherwise the outbox events it recorded are journaled,
+// change listeners are told what changed and the functions registered
+// with OnCommit run, after the lock is released. fn must reach the store
+// only through tx: calling the store's own methods from fn deadlocks.
+func (m *Memory) Update(fn func(tx *Tx) error) error {
+	tx := &Tx{m: m}
+	m.mu.Lock()
+	committed := false
+	defer func() {
+		if !committed {
+			tx.rollback()
+			m.mu.Unlock()
+		}
+	}()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.journal(); err != nil {
+		return err
+	}
+	committed = true
+	for _, c := range tx.changes {
+		m.changed(c)
+	}
+	m.mu.Unlock()
+	for _, f := range tx.onCommit {
+		f()
+	}
+	return nil
+}
+
+func (tx *Tx) rollback() {
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		tx.undo[i]()
+	}
+}
+
+// journal writes the outbox events recorded in the unit of work to the
+// outbox journal, if there is one, so that they survive a restart once
+// committed.
+func (tx *Tx) journal() error {
+	m := tx.m
+	if m.journal == nil || len(tx.outbox) == 0 {
+		return nil
+	}
+	for _, e := range tx.outbox {
+		if err := writeJournal(m.journal, journalRecord{Event: e}); err != nil {
+			return err
+		}
+	}
+	return m.journal.Sync()
+}
+
+// OnCommit registers fn to run once the unit of work has committed.
+func (tx *Tx) OnCommit(fn func()) {
+	tx.onCommit = append(tx.onCommit, fn)
+}
+
+// GetReview returns the review with the given ID.
+func (tx *Tx) GetReview(id string) (*models.Review, error) {
+	r, ok := tx.m.reviews[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return tx.m.readReview(r)
+}
+
+// SaveReview creates or replaces a review.
+func (tx *Tx) SaveReview(r *models.Review) error {
+	m := tx.m
+	stored, err := m.storedReview(r)
+	if err != nil {
+		return err
+	}
+	prev, had := m.reviews[r.ID]
+	m.reviews[r.ID] = stored
+	tx.undo = append(tx.undo, func() {
+		if had {
+			m.reviews[r.ID] = prev
+		} else {
+			delete(m.reviews, r.ID)
+		}
+	})
+	tx.changes = append(tx.changes, Change{Kind: KindReview, ID: r.ID})
+	return nil
+}
+
+// GetApproval returns the approval record of a review, if it has been
+// approved.
+func (tx *Tx) GetApproval(reviewID string) (*models.Approval, bool) {
+	a, ok := tx.m.approvals[reviewID]
+	return copyApproval(a), ok
+}
+
+// SaveApproval records the approval of a review and sets the review's
+// ApprovedBy and ApprovedAt.
+func (tx *Tx) SaveApproval(a *models.Approval) {
+	m := tx.m
+	prev, had := m.approvals[a.ReviewID]
+	m.approvals[a.ReviewID] = copyApproval(a)
+	tx.undo = append(tx.undo, func() {
+		if had {
+			m.approvals[a.ReviewID] = prev
+		} else {
+			delete(m.approvals, a.ReviewID)
+		}
+	})
+	if r, ok := m.reviews[a.ReviewID]; ok {
+		tx.keep(keep(m.reviews, a.ReviewID))
+		approved := *r
+		at := a.ApprovedAt
+		approved.ApprovedBy, approved.ApprovedAt = a.ApprovedBy, &at
+		m.reviews[a.ReviewID] = &approved
+		tx.changes = append(tx.changes, Change{Kind: KindReview, ID: a.ReviewID})
+	}
+}
+
+// ReopenReview clears the approval of a review and returns it to the given
+// status, as Memory.ReopenReview does.
+func (tx *Tx) ReopenReview(id, status string, at time.Time) (*models.Review, error) {
+	m := tx.m
+	prev, ok := m.reviews[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	approval, approved := m.approvals[id]
+	signoffs, signed := m.signoffs[id]
+	reopened := *prev
+	reopened.Status = status
+	reopened.UpdatedAt = at
+	reopened.ApprovedBy, reopened.ApprovedAt = "", nil
+	m.reviews[id] = &reopened
+	delete(m.approvals, id)
+	delete(m.signoffs, id)
+	tx.undo = append(tx.undo, func() {
+		m.reviews[id] = prev
+		if approved {
+			m.approvals[id] = approval
+		}
+		if signed {
+			m.signoffs[id] = signoffs
+		}
+	})
+	tx.changes = append(tx.changes, Change{Kind: KindReview, ID: id})
+	return m.readReview(&reopened)
+}
+
+// Assign sets the reviewer of a review, replacing any previous assignment.
+func (tx *Tx) Assign(a *models.Assignment) error {
+	m := tx.m
+	if _, ok := m.reviews[a.ReviewID]; !ok {
+		return ErrNotFound
+	}
+	prev, had := m.assignments[a.ReviewID]
+	m.assignments[a.ReviewID] = copyOf(a)
+	tx.undo = append(tx.undo, func() {
+		if had {
+			m.assignments[a.ReviewID] = prev
+		} else {
+			delete(m.assignments, a.ReviewID)
+		}
+	})
+	return nil
+}
+
+// AddSubscription stores a subscription, assigning its ID.
+func (tx *Tx) AddSubscription(sub *models.Subscription) {
+	m := tx.m
+	seq := m.subSeq
+	m.addSubscription(sub)
+	tx.undo = append(tx.undo, func() {
+		delete(m.subs, sub.ID)
+		m.subSeq = seq
+	})
+}
+
+// AddComment stores a comment, assigning its ID if it has none.
+func (tx *Tx) AddComment(c *models.Comment) error {
+	m := tx.m
+	seq, prev := m.commentSeq, m.comments[c.ReviewID]
+	if err := m.addComment(c); err != nil {
+		return err
+	}
+	tx.undo = append(tx.undo, func() {
+		m.comments[c.ReviewID] = prev
+		m.commentSeq = seq
+	})
+	return nil
+}
+
+// SetLabels replaces the labels of a review.
+func (tx *Tx) SetLabels(reviewID string, labels []string) {
+	m := tx.m
+	prev, had := m.labels[reviewID]
+	m.labels[reviewID] = labels
+	tx.undo = append(tx.undo, func() {
+		if had {
+			m.labels[reviewID] = prev
+		} else {
+			delete(m.labels, reviewID)
+		}
+	})
+}
+
+// AppendAudit records an audit event, assigning its ID and timestamp.
+func (tx *Tx) AppendAudit(e *models.AuditEvent) {
+	m := tx.m
+	n := len(m.audit)
+	m.appendAudit(e)
+	tx.undo = append(tx.undo, func() {
+		m.audit[n] = nil
+		m.audit = m.audit[:n]
+	})
+}
+
+// AppendOutbox records an event for publication, assigning its ID. It is
+// journaled when the unit of work commits.
+func (tx *Tx) AppendOutbox(e *models.OutboxEvent) {
+	m := tx.m
+	seq, n := m.outboxSeq, len(m.outbox)
+	m.outboxSeq++
+	e.ID = m.outboxSeq
+	m.outbox = append(m.outbox, e)
+	tx.outbox = append(tx.outbox, e)
+	tx.undo = append(tx.undo, func() {
+		m.outbox[n] = nil
+		m.outbox = m.outbox[:n]
+		m.outboxSeq = seq
+	})
+}
+
+// AddNotification stores a notification in a user's inbox, assigning its
+// ID.
+func (tx *Tx) AddNotification(n *models.Notification) {
+	m := tx.m
+	seq, prev := m.notifySeq, m.inbox[n.UserID]
+	m.addNotification(n)
+	tx.undo = append(tx.undo, func() {
+		m.inbox[n.UserID] = prev
+		m.notifySeq = seq
+	})
+}
+
+// DeleteUser removes a user of an organization, as Memory.DeleteUser does.
+func (tx *Tx) DeleteUser(orgID, id string) error {
+	m := tx.m
+	u, ok := m.users[id]
+	if !ok || u.OrgID != orgID {
+		return ErrNotFound
+	}
+	tx.keep(
+		keep(m.users, id), keep(m.passwords, id), keep(m.pwSetAt, id),
+		keep(m.pwReset, id), keep(m.profiles, id),
+	)
+	delete(m.users, id)
+	delete(m.passwords, id)
+	delete(m.pwSetAt, id)
+	delete(m.pwReset, id)
+	delete(m.profiles, id)
+	return nil
+}
+
+// EndUserSessions ends every active session of the user and returns how
+// many were ended.
+func (tx *Tx) EndUserSessions(userID, reason string, at time.Time) int {
+	m := tx.m
+	n := 0
+	for id, s := range m.sessions {
+		if s.UserID == userID && s.Active(at) {
+			tx.keep(keep(m.sessions, id))
+			ended := *s
+			ended.EndedAt, ended.EndReason = &at, reason
+			m.sessions[id] = &ended
+			n++
+		}
+	}
+	return n
+}
+
+// DeleteAttachment removes one of a review's attachments, as
+// Memory.DeleteAttachment does.
+func (tx *Tx) DeleteAttachment(reviewID, id string) (models.Attachment, error) {
+	m := tx.m
+	a, ok := m.attachments[id]
+	if !ok || a.ReviewID != reviewID {
+		return models.Attachment{}, ErrNotFound
+	}
+	if m.reviewHeld(reviewID) {
+		return models.Attachment{}, ErrLegalHold
+	}
+	read, err := m.readAttachment(a)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	tx.keep(keep(m.attachments, id))
+	delete(m.attachments, id)
+	return read, nil
+}
+
+// RecordSecretFindings replaces the findings of a review from the given
+// sources, as Memory.RecordSecretFindings does.
+func (tx *Tx) RecordSecretFindings(reviewID string, sources []string, findings []models.SecretFinding) {
+	m := tx.m
+	tx.keep(keep(m.secrets, reviewID))
+	m.recordSecretFindings(reviewID, sources, findings)
+}
+
+// MergeReview folds a review into another, as Memory.MergeReview does.
+func (tx *Tx) MergeReview(merge *models.ReviewMerge) error {
+	undo, err := tx.m.mergeReview(merge)
+	if err != nil {
+		return err
+	}
+	tx.keep(undo)
+	tx.changes = append(tx.changes,
+		Change{Kind: KindReview, ID: merge.SourceID, Deleted: true},
+		Change{Kind: KindReview, ID: merge.TargetID},
+	)
+	return nil
+}
+
+// GetUser returns a user by ID.
+func (tx *Tx) GetUser(id string) (*models.User, error) {
+	u, ok := tx.m.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyUser(u), nil
+}
+
+// SetUserRole changes the role of a member of the organization, as
+// Memory.SetUserRole does.
+func (tx *Tx) SetUserRole(orgID, userID, role string) (string, error) {
+	m := tx.m
+	u, ok := m.users[userID]
+	if !ok || u.OrgID != orgID {
+		return "", ErrNotFound
+	}
+	tx.keep(keep(m.users, userID))
+	updated := *u
+	updated.Role, updated.UpdatedAt = role, time.Now()
+	m.users[userID] = &updated
+	return u.Role, nil
+}
+
+// DecideAccessItem records the decision on a member of a campaign, as
+// Memory.DecideAccessItem does.
+func (tx *Tx) DecideAccessItem(campaignID, userID string, decided models.AccessItem) (models.AccessItem, error) {
+	tx.keep(keepSlice(tx.m.accessItems, campaignID))
+	return tx.m.decideAccessItem(campaignID, userID, decided)
+}
+
+// Deactivated reports whether the user is deactivated.
+func (tx *Tx) Deactivated(userID string) bool {
+	_, ok := tx.m.inactive[userID]
+	return ok
+}
+
+// TakeOAuthToken removes and returns an unexpired token by hash if it is
+// of the given kind and was issued to clientID. Tokens that do not match
+// are left in place, so only their own client can spend them.
+func (tx *Tx) TakeOAuthToken(hash, kind, clientID string, now time.Time) (*models.OAuthToken, error) {
+	m := tx.m
+	t, ok := m.authTokens[hash]
+	if !ok || t.Kind != kind || t.Grant.ClientID != clientID || now.After(t.ExpiresAt) {
+		return nil, ErrNotFound
+	}
+	tx.keep(keep(m.authTokens, hash))
+	delete(m.authTokens, hash)
+	return copyOAuthToken(t), nil
+}
+
+// SaveOAuthToken stores an issued token by hash.
+func (tx *Tx) SaveOAuthToken(hash string, t *models.OAuthToken) {
+	tx.keep(keep(tx.m.authTokens, hash))
+	tx.m.authTokens[hash] = copyOAuthToken(t)
+}
+
+// keep records undo functions, run in reverse order on rollback.
+func (tx *Tx) keep(undo ...func()) {
+	tx.undo = append(tx.undo, undo...)
+}
+
+// keep returns a function that puts m[k] back the way it is now.
+func keep[K comparable, V any](m map[K]V, k K) func() {
+	prev, had := m[k]
+	return func() {
+		if had {
+			m[k] = prev
+		} else {
+			delete(m, k)
+		}
+	}
+}
+
+// keepSlice is keep for slices that may be changed in place.
+func keepSlice[K comparable, E any](m map[K][]E, k K) func() {
+	prev, had := m[k]
+	prev = slices.Clone(prev)
+	return func() {
+		if had {
+			m[k] = prev
+		} else {
+			delete(m, k)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+var errAbort = errors.New("abort")
+
+// fixture returns a store with two reviews of one organization, a member
+// with a session, and comments, labels, an attachment and a secret finding
+// on the first review.
+func fixture(t *testing.T) (m *Memory, userID string) {
+	t.Helper()
+	m = NewMemory()
+	now := time.Now()
+	m.SaveReview(&models.Review{ID: "r1", OrgID: "acme", Title: "Source", CreatedAt: now})
+	m.SaveReview(&models.Review{ID: "r2", OrgID: "acme", Title: "Target", CreatedAt: now})
+	m.AddComment(&models.Comment{ReviewID: "r2", Body: "later", CreatedAt: now.Add(time.Minute)})
+	m.AddComment(&models.Comment{ReviewID: "r1", Body: "first", CreatedAt: now})
+	m.SetLabels("r1", []string{"finance"})
+	m.SetLabels("r2", []string{"q3"})
+	a, _ := m.AddAttachment(models.Attachment{OrgID: "acme", ReviewID: "r1", Name: "plan.pdf"})
+	m.RecordSecretFindings("r1", nil, []models.SecretFinding{{Source: "attachment:" + a.ID}})
+	u := &models.User{OrgID: "acme", Email: "u1@acme.test", Role: "member"}
+	if err := m.CreateUser(u); err != nil {
+		t.Fatal(err)
+	}
+	m.AddSession(models.Session{UserID: u.ID, OrgID: "acme", CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)})
+	return m, u.ID
+}
+
+// state renders what the fixture's readers return, to compare before and
+// after a unit of work.
+func state(t *testing.T, m *Memory, userID string) string {
+	t.Helper()
+	r1, _ := m.GetReview("r1")
+	u1, _ := m.GetUser(userID)
+	_, merged := m.MergedInto("r1")
+	b, err := json.Marshal([]interface{}{
+		r1, u1, merged,
+		m.ListComments("r1"), m.ListComments("r2"),
+		m.GetLabels("r1"), m.GetLabels("r2"),
+		m.ListAttachments("r1"), m.ListAttachments("r2"),
+		m.ListSecretFindings("r1"), m.ListSecretFindings("r2"),
+		m.ListSessions(userID, time.Now()),
+		m.ListAudit("acme"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestUpdateRollsBack(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(tx *Tx, userID string) error
+	}{
+		{"merge review", func(tx *Tx, _ string) error {
+			return tx.MergeReview(&models.ReviewMerge{SourceID: "r1", TargetID: "r2"})
+		}},
+		{"delete user", func(tx *Tx, userID string) error {
+			if err := tx.DeleteUser("acme", userID); err != nil {
+				return err
+			}
+			if tx.EndUserSessions(userID, "removed", time.Now()) != 1 {
+				return errors.New("session not ended")
+			}
+			return nil
+		}},
+		{"delete attachment", func(tx *Tx, _ string) error {
+			a := tx.m.attachments
+			for id := range a {
+				if _, err := tx.DeleteAttachment("r1", id); err != nil {
+					return err
+				}
+				tx.RecordSecretFindings("r1", []string{"attachment:" + id}, nil)
+			}
+			return nil
+		}},
+		{"review, audit and outbox", func(tx *Tx, _ string) error {
+			r, err := tx.GetReview("r1")
+			if err != nil {
+				return err
+			}
+			r.Title = "Renamed"
+			tx.SaveReview(r)
+			tx.AppendAudit(&models.AuditEvent{OrgID: "acme", Action: "review.updated", TargetID: "r1"})
+			tx.AppendOutbox(&models.OutboxEvent{Type: "review.updated", ReviewID: "r1"})
+			return nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, userID := fixture(t)
+			before, outbox := state(t, m, userID), len(m.PendingOutbox(100))
+			err := m.Update(func(tx *Tx) error {
+				if err := tt.fn(tx, userID); err != nil {
+					t.Fatalf("step failed: %v", err)
+				}
+				return errAbort
+			})
+			if !errors.Is(err, errAbort) {
+				t.Fatalf("err = %v, want errAbort", err)
+			}
+			if after := state(t, m, userID); after != before {
+				t.Errorf("rollback left changes:\nbefore %s\nafter  %s", before, after)
+			}
+			if n := len(m.PendingOutbox(100)); n != outbox {
+				t.Errorf("outbox has %d events, want %d", n, outbox)
+			}
+
+			// The same work commits when the function succeeds.
+			if err := m.Update(func(tx *Tx) error { return tt.fn(tx, userID) }); err != nil {
+				t.Fatal(err)
+			}
+			if state(t, m, userID) == before {
+				t.Error("committed unit of work changed nothing")
+			}
+		})
+	}
+}
+
+func TestMergeReview(t *testing.T) {
+	m, _ := fixture(t)
+	merge := &models.ReviewMerge{SourceID: "r1", TargetID: "r2"}
+	if err := m.MergeReview(merge); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.GetReview("r1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("source still exists: %v", err)
+	}
+	comments := m.ListComments("r2")
+	if merge.Comments != 1 || len(comments) != 2 || comments[0].Body != "first" {
+		t.Errorf("comments were not moved in order: %d moved, %+v", merge.Comments, comments)
+	}
+	if got := m.GetLabels("r2"); len(got) != 2 {
+		t.Errorf("labels = %v", got)
+	}
+	if got := m.ListAttachments("r2"); len(got) != 1 {
+		t.Errorf("attachments were not moved: %v", got)
+	}
+	if into, ok := m.MergedInto("r1"); !ok || into.TargetID != "r2" || into.OrgID != "acme" {
+		t.Errorf("MergedInto = %+v, %v", into, ok)
+	}
+	if err := m.MergeReview(&models.ReviewMerge{SourceID: "r1", TargetID: "r2"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second merge: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestApprovalStampsReview(t *testing.T) {
+	m, _ := fixture(t)
+	at := time.Now()
+	m.SaveApproval(&models.Approval{ReviewID: "r1", ApprovedBy: "u2", ApprovedAt: at})
+	r, _ := m.GetReview("r1")
+	if r.ApprovedBy != "u2" || r.ApprovedAt == nil || !r.ApprovedAt.Equal(at) {
+		t.Fatalf("approved review = %q at %v", r.ApprovedBy, r.ApprovedAt)
+	}
+	if _, err := m.ReopenReview("r1", "pending"); err != nil {
+		t.Fatal(err)
+	}
+	r, _ = m.GetReview("r1")
+	if r.ApprovedBy != "" || r.ApprovedAt != nil {
+		t.Errorf("reopened review = %q at %v", r.ApprovedBy, r.ApprovedAt)
+	}
+}
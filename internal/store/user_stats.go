@@ -0,0 +1,45 @@
+package store
+
+import (
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// UserStats computes the review activity of a user within their
+// organization.
+func (m *Memory) UserStats(userID string) (models.UserStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	u, ok := m.users[userID]
+	if !ok {
+		return models.UserStats{}, ErrNotFound
+	}
+	stats := models.UserStats{UserID: userID, OrgID: u.OrgID, GeneratedAt: time.Now()}
+	var turnaround time.Duration
+	for id, r := range m.reviews {
+		if r.OrgID != u.OrgID {
+			continue
+		}
+		if r.AuthorID == userID {
+			stats.Authored++
+		}
+		assignment := m.assignments[id]
+		approval := m.approvals[id]
+		switch {
+		case approval != nil && approval.ApprovedBy == userID:
+			stats.Reviewed++
+			start := r.CreatedAt
+			if assignment != nil && assignment.ReviewerID == userID {
+				start = assignment.AssignedAt
+			}
+			turnaround += approval.ApprovedAt.Sub(start)
+		case approval == nil && assignment != nil && assignment.ReviewerID == userID:
+			stats.Workload++
+		}
+	}
+	if stats.Reviewed > 0 {
+		stats.AverageTurnaroundHours = (turnaround / time.Duration(stats.Reviewed)).Hours()
+	}
+	return stats, nil
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// AddWebhookDelivery records a delivery, assigning its ID.
+func (m *Memory) AddWebhookDelivery(d models.WebhookDelivery) models.WebhookDelivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliverySeq++
+	d.ID = fmt.Sprintf("delivery-%d", m.deliverySeq)
+	m.deliveries[d.ID] = &d
+	return d
+}
+
+// UpdateWebhookDelivery replaces a recorded delivery. It returns
+// ErrNotFound if the delivery has been pruned meanwhile.
+func (m *Memory) UpdateWebhookDelivery(d models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.deliveries[d.ID]; !ok {
+		return ErrNotFound
+	}
+	m.deliveries[d.ID] = &d
+	return nil
+}
+
+// GetWebhookDelivery returns a delivery of the organization.
+func (m *Memory) GetWebhookDelivery(orgID, id string) (models.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	d, ok := m.deliveries[id]
+	if !ok || d.OrgID != orgID {
+		return models.WebhookDelivery{}, ErrNotFound
+	}
+	return *d, nil
+}
+
+// ListWebhookDeliveries returns the organization's deliveries matching f,
+// oldest first.
+func (m *Memory) ListWebhookDeliveries(orgID string, f models.DeliveryFilter) []models.WebhookDelivery {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []models.WebhookDelivery{}
+	for _, d := range m.deliveries {
+		if d.OrgID == orgID && f.Matches(d) {
+			out = append(out, *d)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// PruneWebhookDeliveries removes deliveries created before cutoff and
+// returns how many were removed. Those of organizations under a legal hold
+// are kept.
+func (m *Memory) PruneWebhookDeliveries(cutoff time.Time) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for id, d := range m.deliveries {
+		if d.CreatedAt.Before(cutoff) && !m.orgHeld(d.OrgID) {
+			delete(m.deliveries, id)
+			n++
+		}
+	}
+	return n
+}
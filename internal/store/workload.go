@@ -0,0 +1,39 @@
+package store
+
+import "slices"
+
+// OpenAssignmentCounts returns how many unapproved reviews of the
+// organization are assigned to each reviewer.
+func (m *Memory) OpenAssignmentCounts(orgID string) map[string]int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	counts := make(map[string]int)
+	for id, a := range m.assignments {
+		if r, ok := m.reviews[id]; ok && r.OrgID == orgID && m.approvals[id] == nil {
+			counts[a.ReviewerID]++
+		}
+	}
+	return counts
+}
+
+// LabelExperience scores each member of the organization by the reviews
+// they approved, one point per label shared with labels.
+func (m *Memory) LabelExperience(orgID string, labels []string) map[string]int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	scores := make(map[string]int)
+	if len(labels) == 0 {
+		return scores
+	}
+	for id, a := range m.approvals {
+		if r, ok := m.reviews[id]; !ok || r.OrgID != orgID {
+			continue
+		}
+		for _, l := range m.labels[id] {
+			if slices.Contains(labels, l) {
+				scores[a.ApprovedBy]++
+			}
+		}
+	}
+	return scores
+}
@@ -0,0 +1,182 @@
+// Package streaming serves large generated downloads, such as exports and
+// attachment bundles, without holding them in memory. Plain requests are
+// streamed chunked as the export is produced. Range requests, used to
+// resume interrupted downloads, regenerate the export and skip to the
+// requested bytes, so an export must produce the same bytes for as long as
+// its ETag stays the same.
+package streaming
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// flushEvery is how much output is buffered by the server before it is
+// pushed to the client.
+const flushEvery = 64 << 10
+
+// Content types of the formats exports use.
+const (
+	NDJSON = "application/x-ndjson"
+	Zip    = "application/zip"
+)
+
+// Export is a generated download.
+type Export struct {
+	// Name is the file name offered to the client.
+	Name        string
+	ContentType string
+	// ETag identifies the bytes Write produces, quoted. Range requests are
+	// only honored when it is set.
+	ETag    string
+	ModTime time.Time
+	// Write produces the export. It must stop when a write fails.
+	Write func(w io.Writer) error
+}
+
+// Serve sends an export. Requests without a Range header get it streamed
+// as it is produced; Range and HEAD requests are served by
+// http.ServeContent from a view of the export that is regenerated as
+// needed, which first measures its size with a pass that discards the
+// output.
+func Serve(w http.ResponseWriter, r *http.Request, e Export) {
+	h := w.Header()
+	h.Set("Content-Type", e.ContentType)
+	h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": e.Name}))
+	h.Set("X-Content-Type-Options", "nosniff")
+	if e.ETag != "" {
+		h.Set("ETag", e.ETag)
+	}
+
+	if e.ETag != "" && (r.Header.Get("Range") != "" || r.Method == http.MethodHead) {
+		content := &generated{write: e.Write, size: -1}
+		defer content.Close()
+		http.ServeContent(w, r, e.Name, e.ModTime, content)
+		return
+	}
+	if e.ETag != "" {
+		h.Set("Accept-Ranges", "bytes")
+		if match := r.Header.Get("If-None-Match"); match == e.ETag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	if !e.ModTime.IsZero() {
+		h.Set("Last-Modified", e.ModTime.UTC().Format(http.TimeFormat))
+	}
+	w.WriteHeader(http.StatusOK)
+	fw := &flushWriter{w: w, rc: http.NewResponseController(w)}
+	if err := e.Write(fw); err != nil {
+		// The status is already sent; the client sees a truncated body.
+		log.Printf("Export %s stopped after %d bytes: %v", e.Name, fw.written, err)
+		return
+	}
+	fw.rc.Flush()
+}
+
+// flushWriter pushes output to the client every flushEvery bytes.
+type flushWriter struct {
+	w       io.Writer
+	rc      *http.ResponseController
+	written int64
+	pending int
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	f.written += int64(n)
+	f.pending += n
+	if err == nil && f.pending >= flushEvery {
+		f.pending = 0
+		if ferr := f.rc.Flush(); ferr != nil && !errors.Is(ferr, http.ErrNotSupported) {
+			return n, ferr
+		}
+	}
+	return n, err
+}
+
+// generated is a seekable view of an export. Reading starts the export in
+// a goroutine and discards the bytes before the read position; seeking
+// backwards starts it over.
+type generated struct {
+	write  func(w io.Writer) error
+	size   int64
+	offset int64
+	pr     *io.PipeReader
+	pos    int64
+}
+
+func (g *generated) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += g.offset
+	case io.SeekEnd:
+		size, err := g.measure()
+		if err != nil {
+			return 0, err
+		}
+		offset += size
+	default:
+		return 0, fmt.Errorf("streaming: invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, errors.New("streaming: negative position")
+	}
+	g.offset = offset
+	return offset, nil
+}
+
+func (g *generated) measure() (int64, error) {
+	if g.size < 0 {
+		var c counter
+		if err := g.write(&c); err != nil {
+			return 0, err
+		}
+		g.size = int64(c)
+	}
+	return g.size, nil
+}
+
+func (g *generated) Read(p []byte) (int, error) {
+	if g.pr != nil && g.pos > g.offset {
+		g.Close()
+	}
+	if g.pr == nil {
+		pr, pw := io.Pipe()
+		go func() { pw.CloseWithError(g.write(pw)) }()
+		g.pr, g.pos = pr, 0
+	}
+	if g.pos < g.offset {
+		n, err := io.CopyN(io.Discard, g.pr, g.offset-g.pos)
+		g.pos += n
+		if err != nil {
+			return 0, err
+		}
+	}
+	n, err := g.pr.Read(p)
+	g.pos += int64(n)
+	g.offset += int64(n)
+	return n, err
+}
+
+// Close stops the export being read, if any.
+func (g *generated) Close() {
+	if g.pr != nil {
+		g.pr.Close()
+		g.pr = nil
+	}
+}
+
+// counter counts the bytes written to it.
+type counter int64
+
+func (c *counter) Write(p []byte) (int, error) {
+	*c += counter(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,85 @@
+package streaming
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServeRanges(t *testing.T) {
+	var content strings.Builder
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&content, "line %04d\n", i)
+	}
+	body := content.String()
+	export := Export{
+		Name:        "export.ndjson",
+		ContentType: NDJSON,
+		ETag:        `"v1"`,
+		ModTime:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Write: func(w io.Writer) error {
+			// Write in small pieces, as an export of many records does.
+			for i := 0; i < len(body); i += 7 {
+				if _, err := io.WriteString(w, body[i:min(i+7, len(body))]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		header       map[string]string
+		untagged     bool
+		want         int
+		wantBody     string
+		contentRange string
+	}{
+		{name: "whole export", want: http.StatusOK, wantBody: body},
+		{name: "middle range", header: map[string]string{"Range": "bytes=10-19"}, want: http.StatusPartialContent, wantBody: body[10:20], contentRange: "bytes 10-19/1000"},
+		{name: "resume to the end", header: map[string]string{"Range": "bytes=995-"}, want: http.StatusPartialContent, wantBody: body[995:], contentRange: "bytes 995-999/1000"},
+		{name: "suffix range", header: map[string]string{"Range": "bytes=-5"}, want: http.StatusPartialContent, wantBody: body[995:], contentRange: "bytes 995-999/1000"},
+		{name: "range past the end", header: map[string]string{"Range": "bytes=1000-"}, want: http.StatusRequestedRangeNotSatisfiable, contentRange: "bytes */1000"},
+		{name: "resume of the current export", header: map[string]string{"Range": "bytes=990-", "If-Range": `"v1"`}, want: http.StatusPartialContent, wantBody: body[990:]},
+		{name: "resume of a changed export", header: map[string]string{"Range": "bytes=990-", "If-Range": `"v0"`}, want: http.StatusOK, wantBody: body},
+		{name: "range of an untagged export", untagged: true, header: map[string]string{"Range": "bytes=10-19"}, want: http.StatusOK, wantBody: body},
+		{name: "unchanged", header: map[string]string{"If-None-Match": `"v1"`}, want: http.StatusNotModified},
+		{name: "head", method: http.MethodHead, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/api/orgs/acme/export", nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			e := export
+			if tt.untagged {
+				e.ETag = ""
+			}
+			rec := httptest.NewRecorder()
+			Serve(rec, req, e)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if got := rec.Body.String(); got != tt.wantBody && tt.want != http.StatusRequestedRangeNotSatisfiable {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+			if got := rec.Header().Get("Content-Range"); got != tt.contentRange && tt.contentRange != "" {
+				t.Errorf("Content-Range = %q, want %q", got, tt.contentRange)
+			}
+			if method == http.MethodHead && rec.Header().Get("Content-Length") != "1000" {
+				t.Errorf("Content-Length = %q", rec.Header().Get("Content-Length"))
+			}
+		})
+	}
+}
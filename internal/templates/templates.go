@@ -0,0 +1,330 @@
+// Package templates imports review templates and workflow definitions
+// from bundles published at a URL or in the official catalog, so
+// organizations can share review processes. Bundles may be signed with
+// Ed25519; bundles from the catalog must be signed by a trusted key.
+package templates
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+)
+
+// maxBundleSize bounds the bundles and catalog index read from the network.
+const maxBundleSize = 1 << 20
+
+var (
+	// ErrUnsigned is returned for unsigned bundles where a signature is
+	// required.
+	ErrUnsigned = errors.New("templates: bundle is not signed")
+	// ErrUntrusted is returned for bundles signed by a key that is not
+	// trusted.
+	ErrUntrusted = errors.New("templates: bundle signed by an untrusted key")
+	// ErrBadSignature is returned when a bundle's signature does not verify.
+	ErrBadSignature = errors.New("templates: bundle signature does not verify")
+	// ErrNoCatalog is returned when no catalog is configured.
+	ErrNoCatalog = errors.New("templates: no catalog configured")
+	// ErrNotInCatalog is returned for bundles the catalog does not list.
+	ErrNotInCatalog = errors.New("templates: bundle not in catalog")
+)
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Bundle is a named, versioned set of templates.
+type Bundle struct {
+	Name        string                `json:"name"`
+	Version     string                `json:"version"`
+	Publisher   string                `json:"publisher,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Templates   []models.TemplateSpec `json:"templates"`
+}
+
+// Signed is the envelope of a signed bundle. Signature is the base64
+// Ed25519 signature of the exact bytes of Bundle.
+type Signed struct {
+	Bundle    json.RawMessage `json:"bundle"`
+	KeyID     string          `json:"key_id"`
+	Signature string          `json:"signature"`
+}
+
+// CatalogEntry lists one bundle version in the catalog.
+type CatalogEntry struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+}
+
+// Fetched is a bundle read and checked by an Importer.
+type Fetched struct {
+	Bundle   Bundle
+	URL      string
+	Catalog  bool
+	KeyID    string
+	Verified bool
+}
+
+// Source returns the provenance recorded on templates imported from f.
+func (f *Fetched) Source() models.TemplateSource {
+	return models.TemplateSource{
+		URL:           f.URL,
+		Catalog:       f.Catalog,
+		Bundle:        f.Bundle.Name,
+		BundleVersion: f.Bundle.Version,
+		KeyID:         f.KeyID,
+		Verified:      f.Verified,
+	}
+}
+
+// Importer fetches bundles and checks their signatures.
+type Importer struct {
+	client     *http.Client
+	catalogURL string
+	keys       map[string]ed25519.PublicKey
+}
+
+// NewImporter creates an importer that fetches with client, reads the
+// catalog index from catalogURL (empty disables the catalog) and trusts
+// the base64-encoded Ed25519 public keys in trustedKeys.
+func NewImporter(client *http.Client, catalogURL string, trustedKeys []string) (*Importer, error) {
+	im := &Importer{client: client, catalogURL: catalogURL, keys: make(map[string]ed25519.PublicKey)}
+	for _, k := range trustedKeys {
+		if k = strings.TrimSpace(k); k == "" {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(k)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("trusted template key %q is not a base64 Ed25519 public key", k)
+		}
+		pub := ed25519.PublicKey(raw)
+		im.keys[KeyID(pub)] = pub
+	}
+	return im, nil
+}
+
+// KeyID returns the fingerprint bundles use to name their signing key.
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Catalog returns the bundles listed in the official catalog.
+func (im *Importer) Catalog(ctx context.Context) ([]CatalogEntry, error) {
+	if im.catalogURL == "" {
+		return nil, ErrNoCatalog
+	}
+	body, err := im.get(ctx, im.catalogURL)
+	if err != nil {
+		return nil, err
+	}
+	var index struct {
+		Bundles []CatalogEntry `json:"bundles"`
+	}
+	if err := json.Unmarshal(body, &index); err != nil {
+		return nil, fmt.Errorf("decode catalog: %w", err)
+	}
+	return index.Bundles, nil
+}
+
+// FromCatalog fetches a bundle listed in the catalog. An empty version
+// selects the last one listed. Catalog bundles must be signed by a
+// trusted key.
+func (im *Importer) FromCatalog(ctx context.Context, name, version string) (*Fetched, error) {
+	entries, err := im.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var found *CatalogEntry
+	for i := range entries {
+		if entries[i].Name == name && (version == "" || entries[i].Version == version) {
+			found = &entries[i]
+		}
+	}
+	if found == nil {
+		return nil, ErrNotInCatalog
+	}
+	f, err := im.FromURL(ctx, found.URL, true)
+	if err != nil {
+		return nil, err
+	}
+	if f.Bundle.Name != found.Name || f.Bundle.Version != found.Version {
+		return nil, fmt.Errorf("catalog lists %s %s but the bundle is %s %s",
+			found.Name, found.Version, f.Bundle.Name, f.Bundle.Version)
+	}
+	f.Catalog = true
+	return f, nil
+}
+
+// FromURL fetches a bundle. A signed bundle must verify against a trusted
+// key; an unsigned one is accepted only when requireSigned is false.
+func (im *Importer) FromURL(ctx context.Context, url string, requireSigned bool) (*Fetched, error) {
+	body, err := im.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	f, err := im.Open(body, requireSigned)
+	if err != nil {
+		return nil, err
+	}
+	f.URL = url
+	return f, nil
+}
+
+// Open decodes a bundle, signed or not, and checks its signature.
+func (im *Importer) Open(data []byte, requireSigned bool) (*Fetched, error) {
+	var signed Signed
+	if err := json.Unmarshal(data, &signed); err != nil {
+		return nil, fmt.Errorf("decode bundle: %w", err)
+	}
+	f := &Fetched{}
+	raw := data
+	if len(signed.Bundle) > 0 {
+		pub, ok := im.keys[signed.KeyID]
+		if !ok {
+			return nil, ErrUntrusted
+		}
+		sig, err := base64.StdEncoding.DecodeString(signed.Signature)
+		if err != nil || !ed25519.Verify(pub, signed.Bundle, sig) {
+			return nil, ErrBadSignature
+		}
+		raw, f.KeyID, f.Verified = signed.Bundle, signed.KeyID, true
+	} else if requireSigned {
+		return nil, ErrUnsigned
+	}
+	if err := json.Unmarshal(raw, &f.Bundle); err != nil {
+		return nil, fmt.Errorf("decode bundle: %w", err)
+	}
+	return f, nil
+}
+
+func (im *Importer) get(ctx context.Context, url string) ([]byte, error) {
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return nil, fmt.Errorf("%q is not an http(s) URL", url)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := im.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBundleSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxBundleSize {
+		return nil, fmt.Errorf("GET %s: larger than %d bytes", url, maxBundleSize)
+	}
+	return body, nil
+}
+
+// Invalid describes why a bundle was rejected. Format and Args form a
+// message key for translation.
+type Invalid struct {
+	Format string
+	Args   []interface{}
+}
+
+func (e *Invalid) Error() string {
+	return fmt.Sprintf(e.Format, e.Args...)
+}
+
+func invalid(format string, args ...interface{}) *Invalid {
+	return &Invalid{Format: format, Args: args}
+}
+
+// Validate checks that a bundle is well formed: it is named and
+// versioned, its templates have unique valid names, and every workflow's
+// transitions connect its declared states.
+func Validate(b Bundle) *Invalid {
+	if b.Name == "" || b.Version == "" {
+		return invalid("bundle name and version are required")
+	}
+	if len(b.Templates) == 0 {
+		return invalid("bundle has no templates")
+	}
+	seen := make(map[string]bool)
+	for _, t := range b.Templates {
+		if !namePattern.MatchString(t.Name) {
+			return invalid("template name %q must be lowercase letters, digits, - or _", t.Name)
+		}
+		if seen[t.Kind+"/"+t.Name] {
+			return invalid("template %q appears twice", t.Name)
+		}
+		seen[t.Kind+"/"+t.Name] = true
+		switch t.Kind {
+		case models.TemplateReview:
+			if t.Review == nil || t.Review.Title == "" || t.Workflow != nil {
+				return invalid("review template %q needs a title and no workflow", t.Name)
+			}
+		case models.TemplateWorkflow:
+			if t.Workflow == nil || t.Review != nil {
+				return invalid("workflow template %q needs a workflow and no review", t.Name)
+			}
+			if err := validateWorkflow(t.Name, t.Workflow); err != nil {
+				return err
+			}
+		default:
+			return invalid("template %q has unknown kind %q", t.Name, t.Kind)
+		}
+	}
+	return nil
+}
+
+func validateWorkflow(name string, wf *models.WorkflowDefinition) *Invalid {
+	states := make(map[string]bool, len(wf.States))
+	for _, s := range wf.States {
+		if s == "" || states[s] {
+			return invalid("workflow %q has an empty or repeated state", name)
+		}
+		states[s] = true
+	}
+	if !states[wf.Initial] {
+		return invalid("workflow %q: initial state %q is not one of its states", name, wf.Initial)
+	}
+	if len(wf.Final) == 0 {
+		return invalid("workflow %q has no final state", name)
+	}
+	for _, s := range wf.Final {
+		if !states[s] {
+			return invalid("workflow %q: final state %q is not one of its states", name, s)
+		}
+	}
+	for _, tr := range wf.Transitions {
+		for _, s := range []string{tr.From, tr.To} {
+			if !states[s] {
+				return invalid("workflow %q: transition %q uses unknown state %q", name, tr.Name, s)
+			}
+		}
+		if slices.Contains(wf.Final, tr.From) {
+			return invalid("workflow %q: transition %q leaves final state %q", name, tr.Name, tr.From)
+		}
+	}
+	return nil
+}
+
+// Digest returns a fingerprint of a template's content, used to tell
+// whether an import changes it.
+func Digest(t models.TemplateSpec) string {
+	data, _ := json.Marshal(t)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
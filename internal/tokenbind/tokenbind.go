@@ -0,0 +1,196 @@
+// Package tokenbind binds access tokens to the client they were issued
+// to, either by its TLS client certificate (RFC 8705) or by the key that
+// signs its DPoP proofs (RFC 9449), so a stolen token is useless from
+// another machine.
+package tokenbind
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Binding modes.
+const (
+	ModeMTLS = "mtls"
+	ModeDPoP = "dpop"
+)
+
+// ProofHeader carries the DPoP proof.
+const ProofHeader = "DPoP"
+
+// proofWindow is how far a proof's iat may be from the server clock.
+const proofWindow = 5 * time.Minute
+
+// CertThumbprint returns the base64url SHA-256 thumbprint of a
+// certificate (x5t#S256).
+func CertThumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// RequestCertThumbprint returns the thumbprint of the client certificate
+// presented on r, or "" without one.
+func RequestCertThumbprint(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+	return CertThumbprint(r.TLS.PeerCertificates[0])
+}
+
+// TokenHash identifies an access token without storing it.
+func TokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Verifier checks DPoP proofs and rejects replayed ones.
+type Verifier struct {
+	mu     sync.Mutex
+	seen   map[string]time.Time
+	pruned time.Time
+}
+
+// NewVerifier creates a verifier.
+func NewVerifier() *Verifier {
+	return &Verifier{seen: make(map[string]time.Time)}
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// thumbprint is the RFC 7638 thumbprint of the key.
+func (k jwk) thumbprint() string {
+	var canonical string
+	switch k.Kty {
+	case "EC":
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, k.Crv, k.X, k.Y)
+	case "RSA":
+		canonical = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, k.E, k.N)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	b64 := base64.RawURLEncoding
+	switch k.Kty {
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, errX := b64.DecodeString(k.X)
+		y, errY := b64.DecodeString(k.Y)
+		if errX != nil || errY != nil {
+			return nil, errors.New("malformed EC key")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "RSA":
+		n, errN := b64.DecodeString(k.N)
+		e, errE := b64.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			return nil, errors.New("malformed RSA key")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+type proofClaims struct {
+	Method string `json:"htm"`
+	URL    string `json:"htu"`
+	Hash   string `json:"ath,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Verify checks the DPoP proof sent with r and returns the thumbprint of
+// its key. With a non-empty accessToken the proof must also carry its
+// hash. The proof's htu is compared by path only, since proxies rewrite
+// the scheme and host.
+func (v *Verifier) Verify(r *http.Request, accessToken string, now time.Time) (string, error) {
+	proof := r.Header.Get(ProofHeader)
+	if proof == "" {
+		return "", errors.New("missing DPoP proof")
+	}
+	var key jwk
+	claims := &proofClaims{}
+	_, err := jwt.ParseWithClaims(proof, claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Header["typ"] != "dpop+jwt" {
+			return nil, errors.New("proof typ must be dpop+jwt")
+		}
+		raw, err := json.Marshal(t.Header["jwk"])
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &key); err != nil {
+			return nil, err
+		}
+		return key.publicKey()
+	}, jwt.WithValidMethods([]string{"ES256", "ES384", "RS256", "PS256"}), jwt.WithoutClaimsValidation())
+	if err != nil {
+		return "", fmt.Errorf("invalid DPoP proof: %w", err)
+	}
+	if claims.Method != r.Method {
+		return "", errors.New("DPoP proof is for another method")
+	}
+	if u, err := url.Parse(claims.URL); err != nil || u.Path != r.URL.Path {
+		return "", errors.New("DPoP proof is for another URL")
+	}
+	if claims.IssuedAt == nil || now.Sub(claims.IssuedAt.Time).Abs() > proofWindow {
+		return "", errors.New("DPoP proof is stale")
+	}
+	if accessToken != "" {
+		sum := sha256.Sum256([]byte(accessToken))
+		if claims.Hash != base64.RawURLEncoding.EncodeToString(sum[:]) {
+			return "", errors.New("DPoP proof is for another access token")
+		}
+	}
+	if claims.ID == "" || !v.firstUse(claims.ID, now) {
+		return "", errors.New("DPoP proof was already used")
+	}
+	return key.thumbprint(), nil
+}
+
+// firstUse records a proof ID and reports whether it was new.
+func (v *Verifier) firstUse(jti string, now time.Time) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if now.Sub(v.pruned) > proofWindow {
+		for id, at := range v.seen {
+			if now.Sub(at) > 2*proofWindow {
+				delete(v.seen, id)
+			}
+		}
+		v.pruned = now
+	}
+	if _, ok := v.seen[jti]; ok {
+		return false
+	}
+	v.seen[jti] = now
+	return true
+}
@@ -0,0 +1,103 @@
+package tokenbind
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testProof is a DPoP proof to sign with key.
+type testProof struct {
+	typ, method, url, token, jti string
+	iat                          time.Time
+}
+
+func (p testProof) sign(t *testing.T, key *ecdsa.PrivateKey) string {
+	t.Helper()
+	claims := &proofClaims{Method: p.method, URL: p.url, RegisteredClaims: jwt.RegisteredClaims{ID: p.jti, IssuedAt: jwt.NewNumericDate(p.iat)}}
+	if p.token != "" {
+		sum := sha256.Sum256([]byte(p.token))
+		claims.Hash = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+	tok := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	tok.Header["typ"] = p.typ
+	tok.Header["jwk"] = publicJWK(key)
+	s, err := tok.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func publicJWK(key *ecdsa.PrivateKey) jwk {
+	b64 := base64.RawURLEncoding
+	return jwk{Kty: "EC", Crv: "P-256", X: b64.EncodeToString(key.X.FillBytes(make([]byte, 32))), Y: b64.EncodeToString(key.Y.FillBytes(make([]byte, 32)))}
+}
+
+func TestVerify(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	v := NewVerifier()
+	valid := testProof{typ: "dpop+jwt", method: "GET", url: "https://aurea.example/api/reviews", token: "access", iat: now}
+	// Each case's proof gets a fresh ID unless it sets one.
+	n := 0
+	fresh := func(p testProof) testProof {
+		if p.jti == "" {
+			n++
+			p.jti = fmt.Sprint("proof-", n)
+		}
+		return p
+	}
+
+	tests := []struct {
+		name   string
+		proof  func(p testProof) testProof
+		method string
+		token  string
+		ok     bool
+	}{
+		{"valid", func(p testProof) testProof { p.jti = "once"; return p }, "GET", "access", true},
+		{"replayed", func(p testProof) testProof { p.jti = "once"; return p }, "GET", "access", false},
+		{"at login", func(p testProof) testProof { p.method, p.token = "POST", ""; return p }, "POST", "", true},
+		{"other method", func(p testProof) testProof { return p }, "DELETE", "access", false},
+		{"other URL", func(p testProof) testProof { p.url = "https://aurea.example/api/users"; return p }, "GET", "access", false},
+		{"other host, same path", func(p testProof) testProof { p.url = "http://internal:8080/api/reviews"; return p }, "GET", "access", true},
+		{"stale", func(p testProof) testProof { p.iat = now.Add(-10 * time.Minute); return p }, "GET", "access", false},
+		{"from the future", func(p testProof) testProof { p.iat = now.Add(10 * time.Minute); return p }, "GET", "access", false},
+		{"other token", func(p testProof) testProof { p.token = "stolen"; return p }, "GET", "access", false},
+		{"no token hash", func(p testProof) testProof { p.token = ""; return p }, "GET", "access", false},
+		{"wrong typ", func(p testProof) testProof { p.typ = "JWT"; return p }, "GET", "access", false},
+		{"no jti", func(p testProof) testProof { p.jti = ""; return p }, "GET", "access", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.proof(fresh(valid))
+			req := httptest.NewRequest(tt.method, "/api/reviews", nil)
+			req.Header.Set(ProofHeader, p.sign(t, key))
+			thumbprint, err := v.Verify(req, tt.token, now)
+			if (err == nil) != tt.ok {
+				t.Fatalf("Verify = %q, %v; want ok %v", thumbprint, err, tt.ok)
+			}
+			if tt.ok && thumbprint != publicJWK(key).thumbprint() {
+				t.Errorf("thumbprint = %q, want the proof key's", thumbprint)
+			}
+		})
+	}
+
+	t.Run("no proof", func(t *testing.T) {
+		if _, err := v.Verify(httptest.NewRequest("GET", "/api/reviews", nil), "access", now); err == nil {
+			t.Error("Verify accepted a request without a proof")
+		}
+	})
+}
@@ -0,0 +1,206 @@
+"use strict";
+
+// Minimal client for the orchestrator API. Routes are real paths (/ and
+// /reviews/{id}); the server answers unknown page URLs with index.html.
+
+const pageSize = 20;
+let me = null;
+let page = 1;
+
+const $ = (id) => document.getElementById(id);
+
+function token() {
+  return sessionStorage.getItem("aurea.token");
+}
+
+async function api(method, path, body) {
+  const headers = { Accept: "application/json" };
+  if (token()) headers.Authorization = "Bearer " + token();
+  if (body !== undefined) headers["Content-Type"] = "application/json";
+  const resp = await fetch(path, {
+    method,
+    headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  if (resp.status === 401 && path !== "/login") {
+    logout();
+    throw new Error("Your session has expired. Please log in again.");
+  }
+  const data = resp.headers.get("Content-Type")?.includes("application/json") ? await resp.json() : null;
+  if (!resp.ok) throw new Error((data && data.error) || resp.statusText);
+  return data;
+}
+
+function showError(err) {
+  $("error").textContent = err ? err.message || String(err) : "";
+  $("error").hidden = !err;
+}
+
+function show(view) {
+  for (const id of ["login-view", "list-view", "detail-view"]) $(id).hidden = id !== view;
+  $("logout").hidden = view === "login-view";
+}
+
+function logout() {
+  sessionStorage.removeItem("aurea.token");
+  me = null;
+  $("whoami").textContent = "";
+  applyBranding({});
+  show("login-view");
+}
+
+// applyBranding shows the organization's name, logo and color in the header.
+function applyBranding(branding) {
+  const header = document.querySelector("header");
+  $("brand-name").textContent = branding.name || "Aurea Orchestrator";
+  document.title = branding.name ? branding.name + " · Aurea Orchestrator" : "Aurea Orchestrator";
+  header.style.background = branding.primary_color || "";
+  header.style.color = branding.primary_color && !isDark(branding.primary_color) ? "#000" : "";
+  // Logos are served by this server; loading them by path keeps them
+  // same-origin under the content security policy.
+  $("brand-logo").hidden = !branding.logo_url;
+  if (branding.logo_url) $("brand-logo").src = new URL(branding.logo_url).pathname;
+  else $("brand-logo").removeAttribute("src");
+}
+
+function isDark(hex) {
+  const v = parseInt(hex.slice(1), 16);
+  return 0.299 * (v >> 16 & 255) + 0.587 * (v >> 8 & 255) + 0.114 * (v & 255) < 0.6 * 255;
+}
+
+function navigate(path) {
+  history.pushState(null, "", path);
+  route();
+}
+
+function formatTime(value) {
+  return value ? new Date(value).toLocaleString() : "";
+}
+
+async function route() {
+  showError(null);
+  if (!token()) return show("login-view");
+  try {
+    if (!me) {
+      me = await api("GET", "/api/me");
+      $("whoami").textContent = me.email;
+      applyBranding(await api("GET", "/api/orgs/" + encodeURIComponent(me.org_id) + "/branding"));
+    }
+    const match = location.pathname.match(/^\/reviews\/(.+)$/);
+    if (match) await showReview(decodeURIComponent(match[1]));
+    else await showList();
+  } catch (err) {
+    showError(err);
+  }
+}
+
+async function showList() {
+  show("list-view");
+  const reviews = await api("GET", `/api/reviews?page=${page}&per_page=${pageSize}`);
+  const rows = reviews.map((r) => {
+    const link = document.createElement("a");
+    link.href = "/reviews/" + encodeURIComponent(r.id);
+    link.textContent = r.title;
+    const status = document.createElement("span");
+    status.className = "status " + r.status;
+    status.textContent = r.status;
+    const tr = document.createElement("tr");
+    for (const content of [link, status, formatTime(r.updated_at)]) {
+      const td = document.createElement("td");
+      td.append(content);
+      tr.append(td);
+    }
+    return tr;
+  });
+  $("reviews").replaceChildren(...rows);
+  $("page").textContent = "Page " + page;
+  $("prev").disabled = page === 1;
+  $("next").disabled = reviews.length < pageSize;
+}
+
+async function showReview(id) {
+  show("detail-view");
+  const [review, threads] = await Promise.all([
+    api("GET", "/api/reviews/" + encodeURIComponent(id)),
+    api("GET", "/api/reviews/" + encodeURIComponent(id) + "/comments"),
+  ]);
+  $("detail-view").dataset.id = review.id;
+  $("review-title").textContent = review.title;
+  $("review-status").textContent = review.status;
+  $("review-status").className = "status " + review.status;
+  $("review-updated").textContent = "Updated " + formatTime(review.updated_at);
+  $("review-content").textContent = review.content;
+  $("approve").hidden = review.status !== "pending" || me.role !== "admin";
+
+  const items = threads.map((t) => {
+    const li = document.createElement("li");
+    if (t.resolved) li.className = "resolved";
+    const meta = document.createElement("div");
+    meta.className = "meta";
+    meta.textContent = `${t.author_id} · ${formatTime(t.created_at)}${t.resolved ? " · resolved" : ""}`;
+    const body = document.createElement("div");
+    body.textContent = t.body;
+    li.append(meta, body);
+    return li;
+  });
+  $("comments").replaceChildren(...items);
+}
+
+function onSubmit(id, handler) {
+  $(id).addEventListener("submit", async (event) => {
+    event.preventDefault();
+    const form = event.target;
+    const button = form.querySelector("button[type=submit]");
+    button.disabled = true;
+    showError(null);
+    try {
+      await handler(Object.fromEntries(new FormData(form)));
+      form.reset();
+    } catch (err) {
+      showError(err);
+    } finally {
+      button.disabled = false;
+    }
+  });
+}
+
+onSubmit("login-form", async ({ email, password }) => {
+  const { token } = await api("POST", "/login", { email, password });
+  sessionStorage.setItem("aurea.token", token);
+  await route();
+});
+
+onSubmit("create-form", async ({ title, content }) => {
+  const review = await api("POST", "/api/reviews", { title, content });
+  navigate("/reviews/" + encodeURIComponent(review.id));
+});
+
+onSubmit("comment-form", async ({ body }) => {
+  const id = $("detail-view").dataset.id;
+  await api("POST", "/api/reviews/" + encodeURIComponent(id) + "/comments", { body });
+  await showReview(id);
+});
+
+$("approve").addEventListener("click", async () => {
+  const id = $("detail-view").dataset.id;
+  showError(null);
+  try {
+    await api("POST", "/api/reviews/" + encodeURIComponent(id) + "/approve");
+    await showReview(id);
+  } catch (err) {
+    showError(err);
+  }
+});
+
+$("prev").addEventListener("click", () => { page--; route(); });
+$("next").addEventListener("click", () => { page++; route(); });
+$("logout").addEventListener("click", logout);
+window.addEventListener("popstate", route);
+// Same-origin links navigate without reloading the page.
+document.addEventListener("click", (event) => {
+  const link = event.target.closest("a");
+  if (!link || link.origin !== location.origin || event.ctrlKey || event.metaKey || event.shiftKey) return;
+  event.preventDefault();
+  navigate(link.pathname);
+});
+route();
@@ -0,0 +1,195 @@
+// Package ui serves the embedded web frontend, so the orchestrator is usable
+// from a browser without deploying a separate single-page app. The frontend
+// talks to the same /login and /api endpoints as any other client.
+//
+// Static assets are served under fingerprinted names (/assets/app.<hash>.js)
+// with immutable cache headers, and index.html is rewritten to reference
+// them, so browsers cache assets forever yet pick up a new release at once.
+// Compressible assets are gzipped once at startup. Any other GET for an
+// HTML page is answered with index.html, so client-side routes survive a
+// reload.
+package ui
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+//go:embed static
+var static embed.FS
+
+// contentSecurityPolicy limits the UI to its own scripts and styles and the
+// API on the same origin.
+const contentSecurityPolicy = "default-src 'self'; img-src 'self' data:; frame-ancestors 'none'; base-uri 'none'; form-action 'self'"
+
+const (
+	assetPrefix    = "/assets/"
+	immutableCache = "public, max-age=31536000, immutable"
+	revalidate     = "no-cache"
+)
+
+// noFallback lists path prefixes that never fall back to index.html, so
+// unknown API and integration URLs still answer 404.
+var noFallback = []string{"/api/", "/assets/", "/debug/", "/replication/", "/oauth/", "/schemas/", "/shared/", "/webhooks/"}
+
+// asset is a file prepared for serving.
+type asset struct {
+	body        []byte
+	gzipped     []byte
+	contentType string
+	etag        string
+	cache       string
+}
+
+// server serves the prepared assets.
+type server struct {
+	assets  map[string]*asset
+	index   *asset
+	started time.Time
+}
+
+// Handler serves the frontend. Mount it last so API routes take precedence.
+func Handler() http.Handler {
+	s, err := newServer(static)
+	if err != nil {
+		// The files are embedded at build time, so this cannot fail at run time.
+		panic(err)
+	}
+	return s
+}
+
+func newServer(embedded fs.FS) (*server, error) {
+	files, err := fs.Sub(embedded, "static")
+	if err != nil {
+		return nil, err
+	}
+	s := &server{assets: make(map[string]*asset), started: time.Now()}
+
+	// Fingerprint every file except index.html, then rewrite index.html
+	// to reference the fingerprinted names.
+	var rewrites []string
+	err = fs.WalkDir(files, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || name == "index.html" {
+			return err
+		}
+		body, err := fs.ReadFile(files, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(body)
+		hash := hex.EncodeToString(sum[:])[:12]
+		ext := path.Ext(name)
+		fingerprinted := assetPrefix + strings.TrimSuffix(name, ext) + "." + hash + ext
+
+		s.assets[fingerprinted] = prepare(name, body, hash, immutableCache)
+		// The plain name stays available for anything that links it directly.
+		s.assets["/"+name] = prepare(name, body, hash, revalidate)
+		rewrites = append(rewrites, `"/`+name+`"`, `"`+fingerprinted+`"`)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := fs.ReadFile(files, "index.html")
+	if err != nil {
+		return nil, err
+	}
+	index = []byte(strings.NewReplacer(rewrites...).Replace(string(index)))
+	sum := sha256.Sum256(index)
+	s.index = prepare("index.html", index, hex.EncodeToString(sum[:])[:12], revalidate)
+	return s, nil
+}
+
+// prepare computes an asset's headers and, when it helps, its gzipped form.
+func prepare(name string, body []byte, hash, cache string) *asset {
+	a := &asset{
+		body:        body,
+		contentType: mime.TypeByExtension(path.Ext(name)),
+		etag:        `"` + hash + `"`,
+		cache:       cache,
+	}
+	if a.contentType == "" {
+		a.contentType = http.DetectContentType(body)
+	}
+	if compressible(a.contentType) {
+		var buf bytes.Buffer
+		zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		zw.Write(body)
+		zw.Close()
+		if buf.Len() < len(body) {
+			a.gzipped = buf.Bytes()
+		}
+	}
+	return a
+}
+
+func compressible(contentType string) bool {
+	for _, prefix := range []string{"text/", "application/javascript", "application/json", "image/svg+xml"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a, ok := s.assets[r.URL.Path]
+	if !ok {
+		if r.URL.Path != "/" && r.URL.Path != "/index.html" && !s.fallback(r) {
+			http.NotFound(w, r)
+			return
+		}
+		a = s.index
+	}
+
+	h := w.Header()
+	h.Set("Content-Security-Policy", contentSecurityPolicy)
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("Content-Type", a.contentType)
+	h.Set("Cache-Control", a.cache)
+	h.Set("ETag", a.etag)
+	body := a.body
+	if a.gzipped != nil {
+		h.Add("Vary", "Accept-Encoding")
+		if acceptsGzip(r) {
+			h.Set("Content-Encoding", "gzip")
+			body = a.gzipped
+		}
+	}
+	http.ServeContent(w, r, "", s.started, bytes.NewReader(body))
+}
+
+// fallback reports whether an unknown path is a client-side route: an
+// extensionless page request outside the server's own prefixes.
+func (s *server) fallback(r *http.Request) bool {
+	for _, prefix := range noFallback {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return false
+		}
+	}
+	if path.Ext(r.URL.Path) != "" {
+		return false
+	}
+	accept := r.Header.Get("Accept")
+	return accept == "" || strings.Contains(accept, "text/html") || strings.Contains(accept, "*/*")
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		enc, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(strings.TrimSpace(enc), "gzip") && strings.ReplaceAll(params, " ", "") != "q=0" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+// Package ulid generates ULIDs: 128-bit identifiers made of a millisecond
+// timestamp and 80 random bits, written as 26 Crockford base32 characters
+// so that they sort in the order they were made.
+package ulid
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOverflow is returned when more IDs are asked for within one
+// millisecond than its random part can order.
+var ErrOverflow = errors.New("ulid: too many IDs in one millisecond")
+
+const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var (
+	mu   sync.Mutex
+	last [16]byte
+)
+
+// New returns a ULID for the current time.
+func New() (string, error) {
+	return newAt(time.Now())
+}
+
+// newAt returns a ULID for t. IDs made in the same millisecond as the last,
+// or in an earlier one should the clock step back, continue from the last
+// ID so that they still sort after it.
+func newAt(t time.Time) (string, error) {
+	var id [16]byte
+	ms := uint64(t.UnixMilli())
+	for i := 0; i < 6; i++ {
+		id[i] = byte(ms >> (40 - 8*i))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if string(id[:6]) <= string(last[:6]) {
+		id = last
+		if !increment(id[6:]) {
+			return "", ErrOverflow
+		}
+	} else if _, err := rand.Read(id[6:]); err != nil {
+		return "", err
+	}
+	last = id
+	return encode(id), nil
+}
+
+// increment adds one to the big-endian number b, reporting false if it
+// wrapped around.
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encode writes id as 26 base32 characters, five bits each, the first
+// holding only the top three bits.
+func encode(id [16]byte) string {
+	var out [26]byte
+	var acc uint32
+	bits := 0
+	j := len(out) - 1
+	for i := len(id) - 1; i >= 0; i-- {
+		acc |= uint32(id[i]) << bits
+		bits += 8
+		for bits >= 5 {
+			out[j] = alphabet[acc&31]
+			acc >>= 5
+			bits -= 5
+			j--
+		}
+	}
+	out[0] = alphabet[acc]
+	return string(out[:])
+}
@@ -0,0 +1,87 @@
+package webhooks
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/andres20980/aurea-orchestrator/internal/i18n"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+)
+
+// SchemaVersion is the payload schema version of current deliveries. A
+// new version is added, never an existing one changed, when a field is
+// removed or changes type; adding optional fields keeps the version.
+const SchemaVersion = "v1"
+
+// SchemaVersions lists every published schema version, oldest first.
+var SchemaVersions = []string{"v1"}
+
+// SchemaPath returns the path serving the schema of event at version.
+func SchemaPath(event, version string) string {
+	return "/schemas/" + event + "/" + version + ".json"
+}
+
+// SchemaURL returns the absolute schema URL included in deliveries.
+func SchemaURL(baseURL, event, version string) string {
+	return strings.TrimRight(baseURL, "/") + SchemaPath(event, version)
+}
+
+// Schema returns the JSON Schema (draft 2020-12) describing the payload
+// of event at version, with descriptions in lang. ok is false for an
+// unknown event or version.
+func Schema(baseURL, event, version, lang string) (schema map[string]interface{}, ok bool) {
+	if !slices.Contains(notify.EventTypes, event) || !slices.Contains(SchemaVersions, version) {
+		return nil, false
+	}
+	str := func(key string) map[string]interface{} {
+		return map[string]interface{}{"type": "string", "minLength": 1, "description": i18n.T(lang, key)}
+	}
+	dateTime := func(key string) map[string]interface{} {
+		return map[string]interface{}{"type": "string", "format": "date-time", "description": i18n.T(lang, key)}
+	}
+	return map[string]interface{}{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"$id":         SchemaURL(baseURL, event, version),
+		"title":       event + " webhook payload",
+		"description": i18n.T(lang, "Sent to webhook endpoints when the %s event occurs.", event),
+		"type":        "object",
+		"required":    []string{"event", "review_id", "org_id", "actor_id", "timestamp", "schema"},
+		"properties": map[string]interface{}{
+			"event": map[string]interface{}{
+				"const":       event,
+				"description": i18n.T(lang, "The event type."),
+			},
+			"review_id": str("ID of the review the event concerns."),
+			"org_id":    str("ID of the review's organization."),
+			"actor_id":  str("ID of the user who caused the event."),
+			"timestamp": map[string]interface{}{
+				"type":        "string",
+				"format":      "date-time",
+				"description": i18n.T(lang, "When the event was delivered, in RFC 3339 format."),
+			},
+			"custom_fields": map[string]interface{}{
+				"type":        "object",
+				"description": i18n.T(lang, "The review's organization-defined field values, by field key."),
+			},
+			"review": map[string]interface{}{
+				"type":        "object",
+				"description": i18n.T(lang, "When the review was created, last changed and approved, and by whom."),
+				"required":    []string{"created_at", "created_by", "updated_at"},
+				"properties": map[string]interface{}{
+					"created_at":  dateTime("When the review was created, in RFC 3339 format."),
+					"created_by":  str("ID of the user who created the review."),
+					"updated_at":  dateTime("When the review last changed, in RFC 3339 format."),
+					"approved_by": str("ID of the user who approved the review, if it is approved."),
+					"approved_at": dateTime("When the review was approved, in RFC 3339 format, if it is approved."),
+				},
+			},
+			"schema": map[string]interface{}{
+				"type":        "string",
+				"format":      "uri",
+				"description": i18n.T(lang, "URL of the JSON Schema this payload conforms to."),
+			},
+		},
+		// Optional fields may be added within a version.
+		"additionalProperties": true,
+	}, true
+}
@@ -0,0 +1,249 @@
+// Package webhooks delivers review events to organizations' webhook endpoints.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/deadletter"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Payload is the JSON body posted to webhook endpoints.
+type Payload struct {
+	Event     string    `json:"event"`
+	ReviewID  string    `json:"review_id"`
+	OrgID     string    `json:"org_id"`
+	ActorID   string    `json:"actor_id"`
+	Timestamp time.Time `json:"timestamp"`
+	// CustomFields holds the review's organization-defined field values.
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+	// Review holds when the review was created, changed and approved,
+	// and by whom.
+	Review *ReviewStamps `json:"review,omitempty"`
+	// Schema is the URL of the JSON Schema the payload conforms to.
+	Schema string `json:"schema"`
+}
+
+// ReviewStamps is the part of a review a payload carries: who created it
+// and approved it, and when it was created, last changed and approved.
+type ReviewStamps struct {
+	CreatedAt  time.Time  `json:"created_at"`
+	CreatedBy  string     `json:"created_by"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ApprovedBy string     `json:"approved_by,omitempty"`
+	ApprovedAt *time.Time `json:"approved_at,omitempty"`
+}
+
+// Sign returns the X-Aurea-Signature header value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// DefaultRetention is how long delivery payloads are kept for redelivery.
+const DefaultRetention = 7 * 24 * time.Hour
+
+// Deliverer posts events to every matching webhook of the review's
+// organization and records each delivery for inspection and redelivery.
+type Deliverer struct {
+	st        *store.Memory
+	http      *http.Client
+	timeout   time.Duration
+	baseURL   string
+	retention time.Duration
+	dead      *deadletter.Queue
+
+	mu         sync.Mutex
+	lastPruned time.Time
+}
+
+// NewDeliverer creates a deliverer and subscribes it to review events.
+// baseURL is the server's public URL, used for payload schema links, and
+// deliveries are kept for retention.
+func NewDeliverer(st *store.Memory, d *notify.Dispatcher, client *http.Client, baseURL string, retention time.Duration) *Deliverer {
+	del := &Deliverer{st: st, http: client, timeout: 15 * time.Second, baseURL: baseURL, retention: retention}
+	d.Listen(del.handleEvent)
+	return del
+}
+
+// UseDeadLetters records failed deliveries in q, resolves them when a
+// redelivery succeeds and lets q retry them.
+func (d *Deliverer) UseDeadLetters(q *deadletter.Queue) {
+	d.dead = q
+	q.Handle(models.DeadLetterWebhook, func(ctx context.Context, l models.DeadLetter) error {
+		_, err := d.redeliver(ctx, l.OrgID, l.Reference, false)
+		return err
+	})
+}
+
+func (d *Deliverer) handleEvent(e notify.Event) {
+	review, err := d.st.GetReview(e.ReviewID)
+	if err != nil {
+		return
+	}
+	d.prune()
+	payload := Payload{Event: e.Type, ReviewID: e.ReviewID, OrgID: review.OrgID, ActorID: e.ActorID, Timestamp: time.Now().UTC(), CustomFields: d.st.GetFieldValues(review.ID),
+		Review: &ReviewStamps{CreatedAt: review.CreatedAt, CreatedBy: review.CreatedBy, UpdatedAt: review.UpdatedAt, ApprovedBy: review.ApprovedBy, ApprovedAt: review.ApprovedAt},
+		Schema: SchemaURL(d.baseURL, e.Type, SchemaVersion)}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Encoding webhook payload for %s failed: %v", e.Type, err)
+		return
+	}
+	for _, hook := range d.st.ListWebhooks(review.OrgID) {
+		if !hook.Wants(e.Type) {
+			continue
+		}
+		hook := *hook
+		delivery := d.st.AddWebhookDelivery(models.WebhookDelivery{
+			OrgID:     review.OrgID,
+			WebhookID: hook.ID,
+			Event:     e.Type,
+			ReviewID:  e.ReviewID,
+			Payload:   body,
+			Status:    models.DeliveryPending,
+			CreatedAt: time.Now(),
+		})
+		// Never hold up the API request that caused the event.
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+			defer cancel()
+			if _, err := d.attempt(ctx, &hook, delivery, false, true); err != nil {
+				log.Printf("Webhook %s delivery of %s failed: %v", hook.ID, e.Type, err)
+			}
+		}()
+	}
+}
+
+// Redeliver sends a recorded delivery again with its original payload,
+// signed with the webhook's current secret, and returns the updated record.
+// The webhook must still exist; it is sent even if inactive, since
+// redelivery is an explicit request.
+func (d *Deliverer) Redeliver(ctx context.Context, orgID, deliveryID string) (models.WebhookDelivery, error) {
+	return d.redeliver(ctx, orgID, deliveryID, true)
+}
+
+// redeliver implements Redeliver. record is false when the dead-letter
+// queue is retrying, as it records the outcome itself.
+func (d *Deliverer) redeliver(ctx context.Context, orgID, deliveryID string, record bool) (models.WebhookDelivery, error) {
+	delivery, err := d.st.GetWebhookDelivery(orgID, deliveryID)
+	if err != nil {
+		return delivery, err
+	}
+	hook, err := d.st.GetWebhook(orgID, delivery.WebhookID)
+	if err != nil {
+		return delivery, err
+	}
+	hookCopy := *hook
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	return d.attempt(ctx, &hookCopy, delivery, true, record)
+}
+
+// RedeliverAll redelivers every delivery of the organization matching f in
+// the background, oldest first and one at a time so a recovering consumer
+// is not flooded. It returns the number of deliveries queued.
+func (d *Deliverer) RedeliverAll(orgID string, f models.DeliveryFilter) int {
+	deliveries := d.st.ListWebhookDeliveries(orgID, f)
+	go func() {
+		for _, delivery := range deliveries {
+			if _, err := d.Redeliver(context.Background(), orgID, delivery.ID); err != nil {
+				log.Printf("Webhook %s redelivery of %s failed: %v", delivery.WebhookID, delivery.ID, err)
+			}
+		}
+	}()
+	return len(deliveries)
+}
+
+// attempt posts the delivery's payload and records the outcome, and with
+// record set, dead-letters a failure or resolves an earlier one.
+func (d *Deliverer) attempt(ctx context.Context, hook *models.Webhook, delivery models.WebhookDelivery, redelivery, record bool) (models.WebhookDelivery, error) {
+	status, err := d.deliver(ctx, hook, delivery, redelivery)
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = status
+	delivery.Status, delivery.Error = models.DeliverySucceeded, ""
+	if err != nil {
+		delivery.Status, delivery.Error = models.DeliveryFailed, err.Error()
+	}
+	if uerr := d.st.UpdateWebhookDelivery(delivery); uerr != nil && !errors.Is(uerr, store.ErrNotFound) {
+		log.Printf("Recording webhook delivery %s failed: %v", delivery.ID, uerr)
+	}
+	if d.dead != nil && record {
+		if err != nil {
+			d.dead.Add(models.DeadLetter{
+				OrgID:     delivery.OrgID,
+				Kind:      models.DeadLetterWebhook,
+				Reference: delivery.ID,
+				Summary:   fmt.Sprintf("%s to %s", delivery.Event, hook.URL),
+				Error:     err.Error(),
+			})
+		} else {
+			d.dead.Resolve(models.DeadLetterWebhook, delivery.ID)
+		}
+	}
+	return delivery, err
+}
+
+func (d *Deliverer) deliver(ctx context.Context, hook *models.Webhook, delivery models.WebhookDelivery, redelivery bool) (int, error) {
+	if k, disabled := d.st.KillSwitch(models.CapabilityWebhooks, delivery.OrgID); disabled {
+		return 0, k
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Aurea-Event", delivery.Event)
+	req.Header.Set("X-Aurea-Webhook", hook.ID)
+	req.Header.Set("X-Aurea-Delivery", delivery.ID)
+	// Redeliveries keep the schema of the version they were produced with.
+	var meta struct {
+		Schema string `json:"schema"`
+	}
+	if json.Unmarshal(delivery.Payload, &meta) == nil && meta.Schema != "" {
+		req.Header.Set("X-Aurea-Schema", meta.Schema)
+	}
+	req.Header.Set("X-Aurea-Signature", Sign(hook.Secret, delivery.Payload))
+	if redelivery {
+		req.Header.Set("X-Aurea-Redelivery", "true")
+	}
+
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// prune drops deliveries older than the retention period, at most hourly.
+func (d *Deliverer) prune() {
+	d.mu.Lock()
+	due := time.Since(d.lastPruned) >= time.Hour
+	if due {
+		d.lastPruned = time.Now()
+	}
+	d.mu.Unlock()
+	if due {
+		d.st.PruneWebhookDeliveries(time.Now().Add(-d.retention))
+	}
+}
@@ -0,0 +1,114 @@
+// Package aurea exposes the orchestrator's review and organization logic as
+// a library, so Go programs can embed it without running the HTTP server.
+//
+//	svc := aurea.New()
+//	actor := aurea.Actor{UserID: "u1", OrgID: "acme"}
+//	review, err := svc.Reviews().Create(ctx, actor, "Q3 plan", "...")
+package aurea
+
+import (
+	"context"
+	"errors"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// Domain types shared with the HTTP API.
+type (
+	User             = models.User
+	Review           = models.Review
+	Comment          = models.Comment
+	Approval         = models.Approval
+	ApprovalProof    = models.ApprovalProof
+	CommentThread    = models.CommentThread
+	ThreadResolution = models.ThreadResolution
+	OrgPolicy        = models.OrgPolicy
+	ManagedOrg       = models.ManagedOrg
+	Webhook          = models.Webhook
+	Event            = notify.Event
+)
+
+var (
+	// ErrNotFound is returned for records that do not exist or belong to
+	// another organization.
+	ErrNotFound = store.ErrNotFound
+	// ErrInvalid is returned for invalid arguments; the error text says which.
+	ErrInvalid = errors.New("invalid argument")
+	// ErrLocked is returned when modifying a review that has been approved.
+	ErrLocked = errors.New("review is approved and locked")
+	// ErrNotApproved is returned when reopening a review that is not approved.
+	ErrNotApproved = errors.New("review is not approved")
+	// ErrRejected is returned when a create hook rejects a review; the
+	// error text carries the hook's reason.
+	ErrRejected = errors.New("rejected")
+)
+
+// Review statuses.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+)
+
+// Actor identifies who performs an operation. All operations are scoped to
+// the actor's organization; a guest actor is further limited to the reviews
+// shared with them individually.
+type Actor struct {
+	UserID string
+	OrgID  string
+	Guest  bool
+}
+
+// Service bundles the review and organization services over one store.
+type Service struct {
+	reviews *reviewService
+	orgs    *orgService
+}
+
+// ApprovalSigner issues the signed proof stored with each approval, such
+// as signing.Signer.
+type ApprovalSigner interface {
+	Sign(review *Review, approval *Approval) (*ApprovalProof, error)
+}
+
+// New creates a self-contained service with in-memory storage. Its events
+// are relayed in the background.
+func New() *Service {
+	st := store.NewMemory()
+	d := notify.NewDispatcher(st)
+	go d.Run(context.Background())
+	return NewWithStore(st, d)
+}
+
+// NewWithStore creates a service over an existing store and dispatcher.
+// The server uses it so the API and library share state and events.
+func NewWithStore(st *store.Memory, d *notify.Dispatcher) *Service {
+	return &Service{
+		reviews: &reviewService{st: st, d: d},
+		orgs:    &orgService{st: st},
+	}
+}
+
+// CreateHook inspects a review before it is created. Returning an error
+// rejects the review with ErrRejected.
+type CreateHook func(ctx context.Context, draft *Review) error
+
+// OnBeforeCreate registers a hook run by ReviewService.Create. It must be
+// called before the service is used.
+func (s *Service) OnBeforeCreate(h CreateHook) {
+	s.reviews.beforeCreate = append(s.reviews.beforeCreate, h)
+}
+
+// UseApprovalSigner makes ReviewService.Approve sign every approval with
+// signer. An approval that cannot be signed is not recorded. It must be
+// called before the service is used.
+func (s *Service) UseApprovalSigner(signer ApprovalSigner) {
+	s.reviews.signer = signer
+}
+
+// Reviews returns the review service.
+func (s *Service) Reviews() ReviewService { return s.reviews }
+
+// Orgs returns the organization service.
+func (s *Service) Orgs() OrgService { return s.orgs }
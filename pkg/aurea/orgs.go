@@ -0,0 +1,45 @@
+package aurea
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+)
+
+// OrgService manages organization-wide settings.
+type OrgService interface {
+	GetPolicy(ctx context.Context, actor Actor) (OrgPolicy, error)
+	SetPolicy(ctx context.Context, actor Actor, policy OrgPolicy) (OrgPolicy, error)
+}
+
+type orgService struct {
+	st *store.Memory
+}
+
+func (s *orgService) GetPolicy(ctx context.Context, actor Actor) (OrgPolicy, error) {
+	return s.st.GetPolicy(actor.OrgID), nil
+}
+
+func (s *orgService) SetPolicy(ctx context.Context, actor Actor, policy OrgPolicy) (OrgPolicy, error) {
+	if policy.ReviewSLAHours < 0 {
+		return OrgPolicy{}, fmt.Errorf("%w: review_sla_hours must not be negative", ErrInvalid)
+	}
+	if policy.SessionIdleMinutes < 0 {
+		return OrgPolicy{}, fmt.Errorf("%w: session_idle_minutes must not be negative", ErrInvalid)
+	}
+	if policy.Password.MinLength < 0 || policy.Password.MaxAgeDays < 0 {
+		return OrgPolicy{}, fmt.Errorf("%w: password min_length and max_age_days must not be negative", ErrInvalid)
+	}
+	switch policy.AssignmentStrategy {
+	case "", models.AssignRoundRobin, models.AssignLeastLoaded, models.AssignExpertise:
+	default:
+		return OrgPolicy{}, fmt.Errorf("%w: assignment_strategy must be one of round_robin, least_loaded, expertise", ErrInvalid)
+	}
+	policy.OrgID = actor.OrgID
+	policy.InheritedFrom = ""
+	s.st.SetPolicy(policy)
+	// An ancestor's enforced policy still takes precedence.
+	return s.st.GetPolicy(actor.OrgID), nil
+}
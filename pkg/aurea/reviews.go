@@ -0,0 +1,417 @@
+package aurea
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/internal/ulid"
+)
+
+// MaxPageSize caps ListOptions.PerPage.
+const MaxPageSize = 100
+
+// ListOptions filters and orders the reviews ReviewService.List returns
+// and selects a page of them.
+type ListOptions struct {
+	// Sort is "created_at" or "updated_at", oldest first, or newest first
+	// with a leading "-". Empty lists the newest reviews first.
+	Sort string
+	// Page is the 1-based page to return, PerPage reviews long. A zero
+	// PerPage returns every review; a zero Page is the first page.
+	Page    int
+	PerPage int
+	// Fields keeps only the reviews whose custom field with each key has
+	// the given value, compared as text.
+	Fields map[string]string
+}
+
+// ReviewService manages reviews, their comment threads and labels.
+type ReviewService interface {
+	Create(ctx context.Context, actor Actor, title, content string) (*Review, error)
+	Get(ctx context.Context, actor Actor, id string) (*Review, error)
+	List(ctx context.Context, actor Actor, opts ListOptions) ([]*Review, error)
+	Update(ctx context.Context, actor Actor, id, title, content string) (*Review, error)
+	Approve(ctx context.Context, actor Actor, id string) (*Review, error)
+	Reopen(ctx context.Context, actor Actor, id, reason string) (*Review, error)
+	SetLabels(ctx context.Context, actor Actor, id string, labels []string) error
+	SetPriority(ctx context.Context, actor Actor, id, priority string) error
+
+	ListThreads(ctx context.Context, actor Actor, id string, unresolvedOnly bool) ([]CommentThread, error)
+	AddComment(ctx context.Context, actor Actor, id, body string) (*Comment, error)
+	ResolveThread(ctx context.Context, actor Actor, id, commentID string) (*ThreadResolution, error)
+	UnresolveThread(ctx context.Context, actor Actor, id, commentID string) error
+}
+
+var priorities = map[string]bool{
+	models.PriorityLow:      true,
+	models.PriorityNormal:   true,
+	models.PriorityHigh:     true,
+	models.PriorityCritical: true,
+}
+
+type reviewService struct {
+	st           *store.Memory
+	d            *notify.Dispatcher
+	beforeCreate []CreateHook
+	signer       ApprovalSigner
+}
+
+func (s *reviewService) Create(ctx context.Context, actor Actor, title, content string) (*Review, error) {
+	if title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalid)
+	}
+	id, err := newReviewID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	review := &models.Review{
+		ID:        id,
+		OrgID:     actor.OrgID,
+		AuthorID:  actor.UserID,
+		Title:     title,
+		Content:   content,
+		Status:    StatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for _, hook := range s.beforeCreate {
+		if err := hook(ctx, review); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRejected, err)
+		}
+	}
+	// The review, its audit event and its review.created event are
+	// recorded together
+	err = s.st.Update(func(tx *store.Tx) error {
+		if err := tx.SaveReview(review); err != nil {
+			return err
+		}
+		if review, err = tx.GetReview(id); err != nil {
+			return err
+		}
+		tx.AppendAudit(&models.AuditEvent{
+			OrgID:      review.OrgID,
+			ActorID:    actor.UserID,
+			Action:     "review.created",
+			TargetType: "review",
+			TargetID:   id,
+		})
+		s.d.PublishTx(tx, notify.Event{Type: notify.ReviewCreated, ReviewID: id, ActorID: actor.UserID})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (s *reviewService) Get(ctx context.Context, actor Actor, id string) (*Review, error) {
+	return s.review(actor, id, models.GrantRead)
+}
+
+// List returns the page of the actor's organization's reviews that opts
+// asks for, in the order it asks for. Reviews are filtered before they are
+// paged, so every page but the last is full. Guests only see reviews shared with
+// them individually, so they get none here.
+func (s *reviewService) List(ctx context.Context, actor Actor, opts ListOptions) ([]*Review, error) {
+	order, err := store.ParseSort(opts.Sort)
+	if err != nil {
+		return nil, fmt.Errorf("%w: sort must be one of created_at, updated_at, optionally prefixed with -", ErrInvalid)
+	}
+	if opts.Page < 0 {
+		return nil, fmt.Errorf("%w: page must be positive", ErrInvalid)
+	}
+	if opts.PerPage < 0 || opts.PerPage > MaxPageSize {
+		return nil, fmt.Errorf("%w: per_page must be between 1 and %d", ErrInvalid, MaxPageSize)
+	}
+	if actor.Guest {
+		return []*Review{}, nil
+	}
+	reviews := s.st.ListOrgReviews(actor.OrgID)
+	if len(opts.Fields) > 0 {
+		reviews = slices.DeleteFunc(reviews, func(r *Review) bool {
+			return !fieldsMatch(s.st.GetFieldValues(r.ID), opts.Fields)
+		})
+	}
+	store.SortReviews(reviews, order)
+	if opts.PerPage == 0 {
+		return reviews, nil
+	}
+	skip := max(opts.Page, 1) - 1
+	if skip > len(reviews)/opts.PerPage {
+		return []*Review{}, nil
+	}
+	start := skip * opts.PerPage
+	return reviews[start:min(start+opts.PerPage, len(reviews))], nil
+}
+
+func fieldsMatch(values map[string]interface{}, filters map[string]string) bool {
+	for key, want := range filters {
+		v, ok := values[key]
+		if !ok || fmt.Sprint(v) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// review returns a review of the actor's organization, or of another one
+// that shared it with the actor's organization at the given access level.
+// Guests need a grant of their own. Pass an empty access to allow only
+// members of the owning organization.
+func (s *reviewService) review(actor Actor, id, access string) (*Review, error) {
+	review, err := s.st.GetReview(id)
+	if err != nil {
+		return nil, err
+	}
+	if actor.Guest {
+		if grant, ok := s.st.GuestGrant(review.ID, actor.UserID); ok && access != "" && grant.Allows(access) && grant.OrgID == actor.OrgID {
+			return review, nil
+		}
+		return nil, ErrNotFound
+	}
+	if review.OrgID == actor.OrgID {
+		return review, nil
+	}
+	if grant, ok := s.st.ReviewGrant(review.ID, actor.OrgID); ok && access != "" && grant.Allows(access) {
+		return review, nil
+	}
+	// Do not reveal reviews from other organizations.
+	return nil, ErrNotFound
+}
+
+// Update replaces the title and content of a review of the actor's
+// organization. Approved reviews are locked until reopened.
+func (s *reviewService) Update(ctx context.Context, actor Actor, id, title, content string) (*Review, error) {
+	if title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalid)
+	}
+	review, err := s.review(actor, id, "")
+	if err != nil {
+		return nil, err
+	}
+	err = s.st.Update(func(tx *store.Tx) error {
+		// Check the lock again within the unit of work, so an approval
+		// that lands after the check above is not edited over.
+		current, err := tx.GetReview(review.ID)
+		if err != nil {
+			return err
+		}
+		if _, approved := tx.GetApproval(current.ID); approved || current.Status == StatusApproved {
+			return ErrLocked
+		}
+		current.Title, current.Content, current.UpdatedAt = title, content, time.Now()
+		if err := tx.SaveReview(current); err != nil {
+			return err
+		}
+		tx.AppendAudit(&models.AuditEvent{
+			OrgID:      current.OrgID,
+			ActorID:    actor.UserID,
+			Action:     "review.updated",
+			TargetType: "review",
+			TargetID:   current.ID,
+		})
+		s.d.PublishTx(tx, notify.Event{Type: notify.ReviewUpdated, ReviewID: current.ID, ActorID: actor.UserID})
+		review = current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// Approve approves a review of the actor's organization. The approval,
+// its signed proof, the review's new status, the audit event and the
+// review.approved event are recorded together; if the approval cannot be
+// signed, none of them is.
+func (s *reviewService) Approve(ctx context.Context, actor Actor, id string) (*Review, error) {
+	review, err := s.review(actor, id, "")
+	if err != nil {
+		return nil, err
+	}
+	err = s.st.Update(func(tx *store.Tx) error {
+		current, err := tx.GetReview(review.ID)
+		if err != nil {
+			return err
+		}
+		if _, approved := tx.GetApproval(current.ID); approved || current.Status == StatusApproved {
+			return ErrLocked
+		}
+		now := time.Now()
+		approval := &models.Approval{ReviewID: current.ID, ApprovedBy: actor.UserID, ApprovedAt: now}
+		if s.signer != nil {
+			if approval.Proof, err = s.signer.Sign(current, approval); err != nil {
+				return fmt.Errorf("sign approval: %w", err)
+			}
+		}
+		current.Status, current.UpdatedAt = StatusApproved, now
+		current.ApprovedBy, current.ApprovedAt = actor.UserID, &now
+		if err := tx.SaveReview(current); err != nil {
+			return err
+		}
+		tx.SaveApproval(approval)
+		tx.AppendAudit(&models.AuditEvent{
+			OrgID:      current.OrgID,
+			ActorID:    actor.UserID,
+			Action:     "review.approved",
+			TargetType: "review",
+			TargetID:   current.ID,
+		})
+		s.d.PublishTx(tx, notify.Event{Type: notify.ReviewApproved, ReviewID: current.ID, ActorID: actor.UserID})
+		review = current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (s *reviewService) Reopen(ctx context.Context, actor Actor, id, reason string) (*Review, error) {
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalid)
+	}
+	review, err := s.review(actor, id, "")
+	if err != nil {
+		return nil, err
+	}
+	if !s.locked(review) {
+		return nil, ErrNotApproved
+	}
+
+	err = s.st.Update(func(tx *store.Tx) error {
+		review, err = tx.ReopenReview(review.ID, StatusPending, time.Now())
+		if err != nil {
+			return err
+		}
+		tx.AppendAudit(&models.AuditEvent{
+			OrgID:      review.OrgID,
+			ActorID:    actor.UserID,
+			Action:     "review.reopened",
+			TargetType: "review",
+			TargetID:   review.ID,
+			Reason:     reason,
+		})
+		s.d.PublishTx(tx, notify.Event{Type: notify.ReviewReopened, ReviewID: review.ID, ActorID: actor.UserID})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (s *reviewService) SetLabels(ctx context.Context, actor Actor, id string, labels []string) error {
+	review, err := s.review(actor, id, "")
+	if err != nil {
+		return err
+	}
+	return s.st.Update(func(tx *store.Tx) error {
+		tx.SetLabels(review.ID, labels)
+		s.d.PublishTx(tx, notify.Event{Type: notify.ReviewLabeled, ReviewID: review.ID, ActorID: actor.UserID})
+		return nil
+	})
+}
+
+func (s *reviewService) SetPriority(ctx context.Context, actor Actor, id, priority string) error {
+	if !priorities[priority] {
+		return fmt.Errorf("%w: priority must be one of low, normal, high, critical", ErrInvalid)
+	}
+	review, err := s.review(actor, id, "")
+	if err != nil {
+		return err
+	}
+	s.st.SetPriority(review.ID, priority)
+	return nil
+}
+
+func (s *reviewService) ListThreads(ctx context.Context, actor Actor, id string, unresolvedOnly bool) ([]CommentThread, error) {
+	review, err := s.Get(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.st.ListThreads(review.ID, unresolvedOnly), nil
+}
+
+func (s *reviewService) AddComment(ctx context.Context, actor Actor, id, body string) (*Comment, error) {
+	if body == "" {
+		return nil, fmt.Errorf("%w: body is required", ErrInvalid)
+	}
+	review, err := s.review(actor, id, models.GrantComment)
+	if err != nil {
+		return nil, err
+	}
+	comment := &models.Comment{
+		ReviewID:  review.ID,
+		AuthorID:  actor.UserID,
+		Body:      body,
+		CreatedAt: time.Now(),
+	}
+	err = s.st.Update(func(tx *store.Tx) error {
+		if err := tx.AddComment(comment); err != nil {
+			return err
+		}
+		s.d.PublishTx(tx, notify.Event{Type: notify.CommentAdded, ReviewID: review.ID, ActorID: actor.UserID})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *reviewService) ResolveThread(ctx context.Context, actor Actor, id, commentID string) (*ThreadResolution, error) {
+	comment, err := s.comment(ctx, actor, id, commentID)
+	if err != nil {
+		return nil, err
+	}
+	res := &models.ThreadResolution{
+		CommentID:  comment.ID,
+		ResolvedBy: actor.UserID,
+		ResolvedAt: time.Now(),
+	}
+	s.st.ResolveThread(res)
+	s.d.Publish(notify.Event{Type: notify.CommentResolved, ReviewID: comment.ReviewID, ActorID: actor.UserID})
+	return res, nil
+}
+
+func (s *reviewService) UnresolveThread(ctx context.Context, actor Actor, id, commentID string) error {
+	comment, err := s.comment(ctx, actor, id, commentID)
+	if err != nil {
+		return err
+	}
+	s.st.UnresolveThread(comment.ID)
+	return nil
+}
+
+func (s *reviewService) comment(ctx context.Context, actor Actor, id, commentID string) (*Comment, error) {
+	review, err := s.review(actor, id, "")
+	if err != nil {
+		return nil, err
+	}
+	return s.st.GetComment(review.ID, commentID)
+}
+
+// locked reports whether a review has been approved and may no longer be edited.
+func (s *reviewService) locked(review *Review) bool {
+	if review.Status == StatusApproved {
+		return true
+	}
+	_, approved := s.st.GetApproval(review.ID)
+	return approved
+}
+
+// newReviewID returns an ID for a new review that sorts by creation time.
+func newReviewID() (string, error) {
+	id, err := ulid.New()
+	if err != nil {
+		return "", err
+	}
+	return "review-" + id, nil
+}
@@ -0,0 +1,173 @@
+package aurea_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/signing"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+)
+
+func TestCreateHooks(t *testing.T) {
+	ctx := context.Background()
+	svc := aurea.New()
+	svc.OnBeforeCreate(func(ctx context.Context, draft *aurea.Review) error {
+		if draft.Title == "forbidden" {
+			return errors.New("title not allowed")
+		}
+		return nil
+	})
+	actor := aurea.Actor{UserID: "u1", OrgID: "acme"}
+
+	tests := []struct {
+		title string
+		want  error
+	}{
+		{"Q3 plan", nil},
+		{"", aurea.ErrInvalid},
+		{"forbidden", aurea.ErrRejected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			review, err := svc.Reviews().Create(ctx, actor, tt.title, "")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+			if err == nil && review.CreatedBy != actor.UserID {
+				t.Errorf("created_by = %q, want %q", review.CreatedBy, actor.UserID)
+			}
+		})
+	}
+}
+
+func TestReviewAccess(t *testing.T) {
+	ctx := context.Background()
+	svc := aurea.New()
+	author := aurea.Actor{UserID: "u1", OrgID: "acme"}
+	review, err := svc.Reviews().Create(ctx, author, "Q3 plan", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		actor aurea.Actor
+		get   error
+		list  int
+	}{
+		{"same org", aurea.Actor{UserID: "u2", OrgID: "acme"}, nil, 1},
+		{"other org", aurea.Actor{UserID: "u3", OrgID: "globex"}, aurea.ErrNotFound, 0},
+		{"guest without grant", aurea.Actor{UserID: "g1", OrgID: "acme", Guest: true}, aurea.ErrNotFound, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Reviews().Get(ctx, tt.actor, review.ID); !errors.Is(err, tt.get) {
+				t.Errorf("Get: err = %v, want %v", err, tt.get)
+			}
+			list, err := svc.Reviews().List(ctx, tt.actor, aurea.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list) != tt.list {
+				t.Errorf("List: got %d reviews, want %d", len(list), tt.list)
+			}
+		})
+	}
+}
+
+type failingSigner struct{}
+
+func (failingSigner) Sign(*aurea.Review, *aurea.Approval) (*aurea.ApprovalProof, error) {
+	return nil, errors.New("key unavailable")
+}
+
+func TestApprove(t *testing.T) {
+	ctx := context.Background()
+	admin := aurea.Actor{UserID: "admin", OrgID: "acme"}
+	signer, err := signing.NewEphemeralSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		signer  aurea.ApprovalSigner
+		actor   aurea.Actor
+		twice   bool
+		wantErr error
+	}{
+		{"signed", signer, admin, false, nil},
+		{"signing fails", failingSigner{}, admin, false, errAny},
+		{"already approved", signer, admin, true, aurea.ErrLocked},
+		{"other org", signer, aurea.Actor{UserID: "x", OrgID: "globex"}, false, aurea.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := store.NewMemory()
+			svc := aurea.NewWithStore(st, notify.NewDispatcher(st))
+			svc.UseApprovalSigner(tt.signer)
+			review, err := svc.Reviews().Create(ctx, admin, "Q3 plan", "...")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.twice {
+				if _, err := svc.Reviews().Approve(ctx, tt.actor, review.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+			_, err = svc.Reviews().Approve(ctx, tt.actor, review.ID)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr == errAny && err == nil:
+				t.Fatal("approval succeeded without a proof")
+			case tt.wantErr != nil && tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			approval, approved := st.GetApproval(review.ID)
+			stored, _ := st.GetReview(review.ID)
+			if tt.wantErr != nil && !tt.twice {
+				if approved || stored.Status != aurea.StatusPending {
+					t.Fatalf("failed approval was recorded: %+v, status %s", approval, stored.Status)
+				}
+				return
+			}
+			if !approved || stored.Status != aurea.StatusApproved {
+				t.Fatalf("approval not recorded: status %s", stored.Status)
+			}
+			if stored.CreatedBy != admin.UserID || stored.ApprovedBy != admin.UserID || stored.ApprovedAt == nil {
+				t.Errorf("review stamps = %q/%q/%v", stored.CreatedBy, stored.ApprovedBy, stored.ApprovedAt)
+			}
+			if err := signing.Verify(approval.Proof, signer.PublicKey(), stored); err != nil {
+				t.Errorf("proof does not verify: %v", err)
+			}
+		})
+	}
+}
+
+// errAny marks a case that must fail without naming the error.
+var errAny = errors.New("any error")
+
+func TestReopenClearsApproval(t *testing.T) {
+	ctx := context.Background()
+	admin := aurea.Actor{UserID: "admin", OrgID: "acme"}
+	svc := aurea.New()
+	review, err := svc.Reviews().Create(ctx, admin, "Q3 plan", "...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Reviews().Approve(ctx, admin, review.ID); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := svc.Reviews().Reopen(ctx, admin, review.ID, "numbers changed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.ApprovedBy != "" || reopened.ApprovedAt != nil {
+		t.Errorf("reopened review still approved by %q at %v", reopened.ApprovedBy, reopened.ApprovedAt)
+	}
+}
@@ -0,0 +1,215 @@
+// Package aureatest provides test fixtures for code built on the
+// orchestrator: an in-memory store and service, factories for
+// organizations, users and reviews, and a test server wired with the API's
+// middleware and service-backed handlers.
+//
+//	env := aureatest.New(t)
+//	org := env.Org("acme")
+//	alice := env.User(org.ID, "admin")
+//	review := env.Review(alice, "Q3 plan")
+//	c := env.Client(alice)
+//	threads, err := c.ListComments(ctx, review.ID, false)
+package aureatest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/handlers"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+	"github.com/andres20980/aurea-orchestrator/pkg/client"
+	"github.com/gorilla/mux"
+)
+
+// Secret signs the tokens issued by Env.Token.
+const Secret = "aureatest-secret"
+
+// Organization is an organization created by Env.Org.
+type Organization = models.Organization
+
+// Env is an isolated orchestrator instance for one test.
+type Env struct {
+	// Store holds all state. Tests may read and seed it directly.
+	Store *store.Memory
+	// Service is the library API over Store.
+	Service *aurea.Service
+	// Dispatcher delivers events published through Service.
+	Dispatcher *notify.Dispatcher
+
+	tb     testing.TB
+	tokens *auth.Service
+
+	mu     sync.Mutex
+	seq    int
+	server *httptest.Server
+}
+
+// New creates an environment whose event relay and server are stopped
+// when the test ends.
+func New(tb testing.TB) *Env {
+	tb.Helper()
+	st := store.NewMemory()
+	d := notify.NewDispatcher(st)
+	ctx, cancel := context.WithCancel(context.Background())
+	tb.Cleanup(cancel)
+	go d.Run(ctx)
+	return &Env{
+		Store:      st,
+		Service:    aurea.NewWithStore(st, d),
+		Dispatcher: d,
+		tb:         tb,
+		tokens:     auth.NewService(Secret, time.Hour),
+	}
+}
+
+func (e *Env) next() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.seq++
+	return e.seq
+}
+
+// Org creates an organization. An empty name is generated.
+func (e *Env) Org(name string) *Organization {
+	n := e.next()
+	if name == "" {
+		name = fmt.Sprintf("Org %d", n)
+	}
+	org := &Organization{ID: fmt.Sprintf("org-%d", n), Name: name, CreatedAt: time.Now()}
+	e.Store.SaveOrg(org)
+	return org
+}
+
+// User creates a user with role (admin, reviewer or member) in an org.
+func (e *Env) User(orgID, role string) *aurea.User {
+	e.tb.Helper()
+	n := e.next()
+	u := &aurea.User{
+		Email:     fmt.Sprintf("user%d@%s.test", n, strings.ToLower(orgID)),
+		Name:      fmt.Sprintf("User %d", n),
+		Role:      role,
+		OrgID:     orgID,
+		CreatedAt: time.Now(),
+	}
+	if err := e.Store.CreateUser(u); err != nil {
+		e.tb.Fatalf("aureatest: create user: %v", err)
+	}
+	return u
+}
+
+// Review creates a pending review authored by author. An empty title is
+// generated.
+func (e *Env) Review(author *aurea.User, title string) *aurea.Review {
+	e.tb.Helper()
+	if title == "" {
+		title = fmt.Sprintf("Review %d", e.next())
+	}
+	review, err := e.Service.Reviews().Create(context.Background(), Actor(author), title, "Content of "+title)
+	if err != nil {
+		e.tb.Fatalf("aureatest: create review: %v", err)
+	}
+	return review
+}
+
+// Watch subscribes u to a review so it receives the review's notifications.
+func (e *Env) Watch(u *aurea.User, reviewID string) {
+	e.Store.AddSubscription(&models.Subscription{UserID: u.ID, OrgID: u.OrgID, ReviewID: reviewID, CreatedAt: time.Now()})
+}
+
+// Actor returns the service actor for a user.
+func Actor(u *aurea.User) aurea.Actor {
+	return aurea.Actor{UserID: u.ID, OrgID: u.OrgID}
+}
+
+// Token returns a bearer token for u, accepted by the test server.
+func (e *Env) Token(u *aurea.User) string {
+	e.tb.Helper()
+	token, err := e.tokens.GenerateToken(u.ID, u.Email, u.OrgID, u.Role)
+	if err != nil {
+		e.tb.Fatalf("aureatest: issue token: %v", err)
+	}
+	return token
+}
+
+// Client returns an SDK client for the test server, authenticated as u.
+func (e *Env) Client(u *aurea.User) *client.Client {
+	return client.New(e.URL(), client.WithToken(e.Token(u)), client.WithMaxRetries(0))
+}
+
+// URL starts the test server on first use and returns its base URL.
+func (e *Env) URL() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.server == nil {
+		e.server = httptest.NewServer(e.Handler())
+		e.tb.Cleanup(e.server.Close)
+	}
+	return e.server.URL
+}
+
+// Handler returns the API routes that run on the service and store, behind
+// the same authentication, locale and role checks as the server. Review
+// approval is not mounted.
+func (e *Env) Handler() http.Handler {
+	st, reviews := e.Store, e.Service.Reviews()
+	r := mux.NewRouter()
+	api := r.PathPrefix("/api").Subrouter()
+	api.Use(middleware.JWTAuth(Secret))
+	api.Use(middleware.Locale(st))
+	api.Use(middleware.RejectInactive(st))
+
+	api.HandleFunc("/me", handlers.GetCurrentUser(st)).Methods("GET")
+	api.HandleFunc("/me/profile", handlers.GetProfile(st)).Methods("GET")
+	api.HandleFunc("/me/profile", handlers.UpdateProfile(st)).Methods("PUT")
+	api.HandleFunc("/me/subscriptions", handlers.ListSubscriptions(st)).Methods("GET")
+	api.HandleFunc("/me/subscriptions", handlers.Subscribe(st)).Methods("POST")
+	api.HandleFunc("/me/subscriptions/{subId}", handlers.Unsubscribe(st)).Methods("DELETE")
+	api.HandleFunc("/me/notifications", handlers.ListNotifications(st)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/members", handlers.GetOrgMembers(st)).Methods("GET")
+	api.HandleFunc("/orgs/{id}/policy", handlers.GetOrgPolicy(e.Service.Orgs())).Methods("GET")
+	api.HandleFunc("/orgs/{id}/policy", middleware.RequireRole("admin")(handlers.UpdateOrgPolicy(e.Service.Orgs()))).Methods("PUT")
+	api.HandleFunc("/reviews", handlers.ListReviews(reviews)).Methods("GET")
+	api.HandleFunc("/reviews", middleware.RequireRole("reviewer", "admin")(handlers.CreateReview(reviews))).Methods("POST")
+	api.HandleFunc("/reviews/{id}", handlers.GetReview(reviews)).Methods("GET")
+	api.HandleFunc("/reviews/{id}", middleware.RequireRole("reviewer", "admin")(handlers.RequireEditable(st)(handlers.UpdateReview(reviews)))).Methods("PUT")
+	api.HandleFunc("/reviews/{id}/reopen", middleware.RequireRole("admin")(handlers.ReopenReview(reviews))).Methods("POST")
+	api.HandleFunc("/reviews/{id}/priority", middleware.RequireRole("reviewer", "admin")(handlers.SetReviewPriority(reviews))).Methods("PUT")
+	api.HandleFunc("/reviews/{id}/labels", middleware.RequireRole("reviewer", "admin")(handlers.RequireEditable(st)(handlers.SetReviewLabels(reviews)))).Methods("PUT")
+	api.HandleFunc("/reviews/{id}/comments", handlers.ListComments(reviews)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/comments", handlers.AddComment(reviews)).Methods("POST")
+	api.HandleFunc("/reviews/{id}/comments/{commentId}/resolve", handlers.ResolveComment(reviews)).Methods("POST")
+	api.HandleFunc("/reviews/{id}/comments/{commentId}/resolve", handlers.UnresolveComment(reviews)).Methods("DELETE")
+	api.HandleFunc("/reviews/{id}/fields", handlers.GetReviewFields(st)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/fields", middleware.RequireRole("reviewer", "admin")(handlers.RequireEditable(st)(handlers.SetReviewFields(st)))).Methods("PUT")
+	return r
+}
+
+// WaitForNotification waits up to a few seconds for userID to receive a
+// notification of eventType about reviewID, failing the test otherwise.
+func (e *Env) WaitForNotification(userID, eventType, reviewID string) *models.Notification {
+	e.tb.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		for _, n := range e.Store.ListNotifications(userID) {
+			if n.Type == eventType && n.ReviewID == reviewID {
+				return n
+			}
+		}
+		if time.Now().After(deadline) {
+			e.tb.Fatalf("aureatest: no %s notification for %s about %s", eventType, userID, reviewID)
+			return nil
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,167 @@
+package aureatest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/andres20980/aurea-orchestrator/internal/models"
+	"github.com/andres20980/aurea-orchestrator/pkg/aureatest"
+	"github.com/andres20980/aurea-orchestrator/pkg/client"
+)
+
+func TestReviewsOverHTTP(t *testing.T) {
+	ctx := context.Background()
+	env := aureatest.New(t)
+	acme, globex := env.Org("acme"), env.Org("globex")
+	reviewer := env.User(acme.ID, "reviewer")
+	member := env.User(acme.ID, "member")
+	outsider := env.User(globex.ID, "admin")
+
+	created, err := env.Client(reviewer).CreateReview(ctx, client.ReviewInput{Title: "Q3 plan", Content: "..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := env.Store.GetReview(created.ID); err != nil || stored.AuthorID != reviewer.ID {
+		t.Fatalf("created review is not in the store: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		as   *client.User
+		call func(c *client.Client) error
+		want int
+	}{
+		{"get own org", member, func(c *client.Client) error { _, err := c.GetReview(ctx, created.ID); return err }, 0},
+		{"get other org", outsider, func(c *client.Client) error { _, err := c.GetReview(ctx, created.ID); return err }, http.StatusNotFound},
+		{"create as member", member, func(c *client.Client) error {
+			_, err := c.CreateReview(ctx, client.ReviewInput{Title: "x"})
+			return err
+		}, http.StatusForbidden},
+		{"create without title", reviewer, func(c *client.Client) error {
+			_, err := c.CreateReview(ctx, client.ReviewInput{})
+			return err
+		}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(env.Client(tt.as))
+			var apiErr *client.APIError
+			switch {
+			case tt.want == 0 && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.want != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.want):
+				t.Fatalf("err = %v, want status %d", err, tt.want)
+			}
+		})
+	}
+
+	for _, u := range []*client.User{member, outsider} {
+		list, err := env.Client(u).ListReviews(ctx, client.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := map[bool]int{true: 1, false: 0}[u.OrgID == acme.ID]; len(list) != want {
+			t.Errorf("%s sees %d reviews, want %d", u.OrgID, len(list), want)
+		}
+	}
+}
+
+func TestUpdateReviewOverHTTP(t *testing.T) {
+	ctx := context.Background()
+	env := aureatest.New(t)
+	acme, globex := env.Org("acme"), env.Org("globex")
+	reviewer := env.User(acme.ID, "reviewer")
+	outsider := env.User(globex.ID, "reviewer")
+	c := env.Client(reviewer)
+
+	draft, err := c.CreateReview(ctx, client.ReviewInput{Title: "Draft"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	approved, err := c.CreateReview(ctx, client.ReviewInput{Title: "Approved"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.Store.SaveApproval(&models.Approval{ReviewID: approved.ID, ApprovedBy: reviewer.ID})
+
+	tests := []struct {
+		name string
+		as   *client.User
+		id   string
+		in   client.ReviewInput
+		want int
+	}{
+		{"edit", reviewer, draft.ID, client.ReviewInput{Title: "Final", Content: "Body"}, 0},
+		{"no title", reviewer, draft.ID, client.ReviewInput{Content: "Body"}, http.StatusBadRequest},
+		{"approved", reviewer, approved.ID, client.ReviewInput{Title: "Changed"}, http.StatusConflict},
+		{"other org", outsider, draft.ID, client.ReviewInput{Title: "Hijacked"}, http.StatusNotFound},
+		{"missing", reviewer, "review-missing", client.ReviewInput{Title: "x"}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.Client(tt.as).UpdateReview(ctx, tt.id, tt.in)
+			var apiErr *client.APIError
+			switch {
+			case tt.want == 0 && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.want != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.want):
+				t.Fatalf("err = %v, want status %d", err, tt.want)
+			}
+		})
+	}
+
+	got, _ := env.Store.GetReview(draft.ID)
+	if got.Title != "Final" || got.Content != "Body" {
+		t.Errorf("stored review = %q/%q, want Final/Body", got.Title, got.Content)
+	}
+}
+
+func TestListReviewsSorted(t *testing.T) {
+	ctx := context.Background()
+	env := aureatest.New(t)
+	c := env.Client(env.User(env.Org("acme").ID, "reviewer"))
+
+	var ids []string
+	for _, title := range []string{"first", "second", "third"} {
+		r, err := c.CreateReview(ctx, client.ReviewInput{Title: title})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, r.ID)
+	}
+	if _, err := c.UpdateReview(ctx, ids[0], client.ReviewInput{Title: "first, edited"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"", []string{ids[2], ids[1], ids[0]}},
+		{"created_at", []string{ids[0], ids[1], ids[2]}},
+		{"-updated_at", []string{ids[0], ids[2], ids[1]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			list, err := c.ListReviews(ctx, client.ListOptions{Sort: tt.sort})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, r := range list {
+				got = append(got, r.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var apiErr *client.APIError
+	if _, err := c.ListReviews(ctx, client.ListOptions{Sort: "title"}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("sort=title: err = %v, want status 400", err)
+	}
+}
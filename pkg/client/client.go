@@ -0,0 +1,217 @@
+// Package client is the Go SDK for the orchestrator HTTP API.
+//
+//	c := client.New("https://aurea.example.com")
+//	if err := c.Login(ctx, "alice@example.com", "secret"); err != nil { ... }
+//	it := c.Reviews(client.ListOptions{PageSize: 50})
+//	for it.Next(ctx) {
+//		fmt.Println(it.Review().Title)
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/pkg/reqsign"
+)
+
+// APIError is returned for non-2xx responses.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("aurea: %d %s", e.StatusCode, e.Message)
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the underlying HTTP client.
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) { c.http = h }
+}
+
+// WithToken authenticates with an existing JWT instead of logging in.
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithAPIKey authenticates with an organization API key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithSignedAPIKey authenticates by signing each request instead of
+// sending an API key, as organizations that require signed requests
+// demand. keyID is the key's ID and signingSecret the signing_secret
+// returned with it. Each request, and each retry of one, is signed with a
+// new nonce, as the server accepts each nonce once.
+func WithSignedAPIKey(keyID, signingSecret string) Option {
+	return func(c *Client) { c.apiKeyID, c.signingSecret = keyID, signingSecret }
+}
+
+// WithMaxRetries sets how many times a rate-limited (429) request is retried.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) { c.maxRetries = n }
+}
+
+// Client calls the orchestrator API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	http       *http.Client
+	maxRetries int
+
+	mu            sync.Mutex
+	token         string
+	apiKey        string
+	apiKeyID      string
+	signingSecret string
+}
+
+// New creates a client for the server at baseURL.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		http:       &http.Client{Timeout: 30 * time.Second},
+		maxRetries: 3,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Login exchanges credentials for a token. The credentials are not kept:
+// once the token expires, requests fail with a 401 *APIError and the
+// caller logs in again.
+func (c *Client) Login(ctx context.Context, email, password string) error {
+	var resp struct {
+		Token string `json:"token"`
+	}
+	body := map[string]string{"email": email, "password": password}
+	if err := c.send(ctx, http.MethodPost, "/login", body, &resp, false); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.token = resp.Token
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Client) authorize(req *http.Request, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case c.apiKeyID != "":
+		reqsign.SignRequest(req, c.apiKeyID, c.signingSecret, body, time.Now())
+	case c.apiKey != "":
+		req.Header.Set("Authorization", "ApiKey "+c.apiKey)
+	default:
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+}
+
+// Me returns the authenticated user.
+func (c *Client) Me(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.do(ctx, http.MethodGet, "/api/me", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Token returns the current bearer token.
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// do sends an authenticated request.
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	return c.send(ctx, method, path, in, out, true)
+}
+
+// send performs one logical request, retrying 429 responses.
+func (c *Client) send(ctx context.Context, method, path string, in, out interface{}, authenticated bool) error {
+	var payload []byte
+	if in != nil {
+		var err error
+		if payload, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		if in != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json")
+		if authenticated {
+			c.authorize(req, payload)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < c.maxRetries {
+			resp.Body.Close()
+			if err := sleep(ctx, retryAfter(resp, attempt)); err != nil {
+				return err
+			}
+			continue
+		}
+		return decode(resp, out)
+	}
+}
+
+func decode(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var body struct {
+			Error string `json:"error"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &body) != nil || body.Error == "" {
+			body.Error = strings.TrimSpace(string(data))
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: body.Error}
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func retryAfter(resp *http.Response, attempt int) time.Duration {
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return time.Duration(1<<attempt) * 500 * time.Millisecond
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,233 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andres20980/aurea-orchestrator/internal/auth"
+	"github.com/andres20980/aurea-orchestrator/internal/handlers"
+	"github.com/andres20980/aurea-orchestrator/internal/middleware"
+	"github.com/andres20980/aurea-orchestrator/internal/notify"
+	"github.com/andres20980/aurea-orchestrator/internal/store"
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+	"github.com/andres20980/aurea-orchestrator/pkg/reqsign"
+)
+
+func TestReviews(t *testing.T) {
+	st := store.NewMemory()
+	svc := aurea.NewWithStore(st, notify.NewDispatcher(st))
+	actor := aurea.Actor{UserID: "u1", OrgID: "acme"}
+	for i := 0; i < 7; i++ {
+		review, err := svc.Reviews().Create(context.Background(), actor, fmt.Sprintf("Review %d", i), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Every third review, from the first to the last, is the core
+		// team's, so its matches are spread across the unfiltered pages.
+		if i%3 == 0 {
+			st.SetFieldValues(review.ID, map[string]interface{}{"team": "core"})
+		}
+	}
+	token, err := auth.NewService("secret", time.Hour).GenerateToken("u1", "alice@acme.test", "acme", "member")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests atomic.Int32
+	list := middleware.JWTAuth("secret")(handlers.ListReviews(svc.Reviews()))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		list.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	c := New(srv.URL, WithToken(token))
+
+	tests := []struct {
+		name     string
+		opts     ListOptions
+		reviews  int
+		requests int32
+	}{
+		{"full last page", ListOptions{PageSize: 7}, 7, 2},
+		{"last page short", ListOptions{PageSize: 3}, 7, 3},
+		{"from the second page", ListOptions{Page: 2, PageSize: 3}, 4, 2},
+		{"sorted", ListOptions{PageSize: 2, Sort: "created_at"}, 7, 4},
+		{"filtered by a custom field", ListOptions{PageSize: 2, Fields: map[string]string{"team": "core"}}, 3, 2},
+		{"filtered to nothing", ListOptions{PageSize: 2, Fields: map[string]string{"team": "web"}}, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			seen := map[string]bool{}
+			it := c.Reviews(tt.opts)
+			for it.Next(context.Background()) {
+				if seen[it.Review().ID] {
+					t.Errorf("review %s returned twice", it.Review().ID)
+				}
+				seen[it.Review().ID] = true
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if len(seen) != tt.reviews {
+				t.Errorf("got %d reviews, want %d", len(seen), tt.reviews)
+			}
+			if n := requests.Load(); n != tt.requests {
+				t.Errorf("sent %d requests, want %d", n, tt.requests)
+			}
+		})
+	}
+}
+
+func TestListReviewsPage(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		io.WriteString(w, `[{"id":"r1"}]`)
+	}))
+	defer srv.Close()
+	reviews, err := New(srv.URL).ListReviews(context.Background(), ListOptions{Page: 2, PageSize: 10, Sort: "-updated_at"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reviews) != 1 || reviews[0].ID != "r1" {
+		t.Errorf("reviews = %+v", reviews)
+	}
+	if want := "page=2&per_page=10&sort=-updated_at"; query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+}
+
+func TestRequests(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		login   bool
+		handler func(attempt int32, w http.ResponseWriter, r *http.Request)
+		want    error
+	}{
+		{
+			name:  "sends the token from Login",
+			login: true,
+			handler: func(attempt int32, w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/login":
+					fmt.Fprintf(w, `{"token":"token-%d"}`, attempt)
+				case r.Header.Get("Authorization") != "Bearer token-1":
+					w.WriteHeader(http.StatusUnauthorized)
+				default:
+					io.WriteString(w, `{"id":"u1"}`)
+				}
+			},
+		},
+		{
+			name:  "returns an expired token's 401",
+			login: true,
+			handler: func(attempt int32, w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/login" && attempt == 1 {
+					io.WriteString(w, `{"token":"token-1"}`)
+					return
+				}
+				w.WriteHeader(http.StatusUnauthorized)
+				io.WriteString(w, `{"error":"Unauthorized: invalid or expired token"}`)
+			},
+			want: &APIError{StatusCode: http.StatusUnauthorized, Message: "Unauthorized: invalid or expired token"},
+		},
+		{
+			name: "retries rate-limited requests",
+			handler: func(attempt int32, w http.ResponseWriter, r *http.Request) {
+				if attempt < 3 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				io.WriteString(w, `{"id":"u1"}`)
+			},
+		},
+		{
+			name: "gives up after the retries",
+			opts: []Option{WithMaxRetries(1)},
+			handler: func(attempt int32, w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			want: &APIError{StatusCode: http.StatusTooManyRequests},
+		},
+		{
+			name: "reports the API's error",
+			opts: []Option{WithAPIKey("key")},
+			handler: func(attempt int32, w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "ApiKey key" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(http.StatusForbidden)
+				io.WriteString(w, `{"error":"Forbidden"}`)
+			},
+			want: &APIError{StatusCode: http.StatusForbidden, Message: "Forbidden"},
+		},
+		{
+			name: "signs requests",
+			opts: []Option{WithSignedAPIKey("key-1", "signing-secret")},
+			handler: func(attempt int32, w http.ResponseWriter, r *http.Request) {
+				if id, _ := reqsign.KeyID(r); id != "key-1" || reqsign.Verify(r, nil, "signing-secret", time.Now()) != nil {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				io.WriteString(w, `{"id":"u1"}`)
+			},
+		},
+		{
+			name: "signs each retry with a new nonce",
+			opts: []Option{WithSignedAPIKey("key-1", "signing-secret")},
+			handler: func() func(int32, http.ResponseWriter, *http.Request) {
+				var first string
+				return func(attempt int32, w http.ResponseWriter, r *http.Request) {
+					nonce := r.Header.Get(reqsign.NonceHeader)
+					if attempt == 1 {
+						first = nonce
+						w.Header().Set("Retry-After", "0")
+						w.WriteHeader(http.StatusTooManyRequests)
+						return
+					}
+					if nonce == first || reqsign.Verify(r, nil, "signing-secret", time.Now()) != nil {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					io.WriteString(w, `{"id":"u1"}`)
+				}
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.handler(attempts.Add(1), w, r)
+			}))
+			defer srv.Close()
+			c := New(srv.URL, tt.opts...)
+			if tt.login {
+				if err := c.Login(context.Background(), "alice@acme.test", "secret"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			user, err := c.Me(context.Background())
+			var apiErr, wantErr *APIError
+			switch {
+			case tt.want == nil && err != nil:
+				t.Fatalf("Me: %v", err)
+			case tt.want == nil && user.ID != "u1":
+				t.Errorf("user = %+v", user)
+			case errors.As(tt.want, &wantErr) && (!errors.As(err, &apiErr) || apiErr.StatusCode != wantErr.StatusCode || apiErr.Message != wantErr.Message):
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package client
+
+import "context"
+
+const defaultPageSize = 50
+
+// ReviewIterator walks every review page by page.
+type ReviewIterator struct {
+	c    *Client
+	opts ListOptions
+	buf  []Review
+	cur  Review
+	seen map[string]bool
+	done bool
+	err  error
+}
+
+// Reviews returns an iterator over all reviews, starting at opts.Page.
+func (c *Client) Reviews(opts ListOptions) *ReviewIterator {
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+	if opts.PageSize < 1 {
+		opts.PageSize = defaultPageSize
+	}
+	return &ReviewIterator{c: c, opts: opts, seen: make(map[string]bool)}
+}
+
+// Next advances to the next review, fetching pages as needed. It returns
+// false when there are no more reviews or an error occurred.
+func (it *ReviewIterator) Next(ctx context.Context) bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		page, err := it.c.ListReviews(ctx, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if len(page) < it.opts.PageSize {
+			it.done = true
+		}
+		it.opts.Page++
+		for _, r := range page {
+			// Guard against servers that ignore paging and return the same rows.
+			if it.seen[r.ID] {
+				it.done = true
+				continue
+			}
+			it.seen[r.ID] = true
+			it.buf = append(it.buf, r)
+		}
+	}
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Review returns the current review.
+func (it *ReviewIterator) Review() Review { return it.cur }
+
+// Err returns the error that stopped iteration, if any.
+func (it *ReviewIterator) Err() error { return it.err }
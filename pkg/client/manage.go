@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+)
+
+// Management API resources.
+type (
+	ManagedOrg = aurea.ManagedOrg
+	Webhook    = aurea.Webhook
+)
+
+// WebhookInput is the body of webhook create and update requests. An empty
+// Secret on update keeps the current one.
+type WebhookInput struct {
+	ExternalID string   `json:"external_id,omitempty"`
+	URL        string   `json:"url"`
+	Events     []string `json:"events"`
+	Secret     string   `json:"secret,omitempty"`
+	Active     *bool    `json:"active,omitempty"`
+}
+
+// FindOrgByExternalID returns the organization with the given external ID,
+// or nil if there is none. Requires an instance operator.
+func (c *Client) FindOrgByExternalID(ctx context.Context, externalID string) (*ManagedOrg, error) {
+	var orgs []ManagedOrg
+	if err := c.do(ctx, http.MethodGet, "/api/manage/orgs?external_id="+url.QueryEscape(externalID), nil, &orgs); err != nil {
+		return nil, err
+	}
+	if len(orgs) == 0 {
+		return nil, nil
+	}
+	return &orgs[0], nil
+}
+
+// CreateOrg creates an organization. Requires an instance operator.
+func (c *Client) CreateOrg(ctx context.Context, org ManagedOrg) (*ManagedOrg, error) {
+	var out ManagedOrg
+	if err := c.do(ctx, http.MethodPost, "/api/manage/orgs", org, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateOrg replaces an organization's name and external ID.
+func (c *Client) UpdateOrg(ctx context.Context, org ManagedOrg) (*ManagedOrg, error) {
+	var out ManagedOrg
+	if err := c.do(ctx, http.MethodPut, "/api/manage/orgs/"+url.PathEscape(org.ID), org, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteOrg deletes an organization.
+func (c *Client) DeleteOrg(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/manage/orgs/"+url.PathEscape(id), nil, nil)
+}
+
+// FindWebhookByExternalID returns the organization's webhook with the given
+// external ID, or nil if there is none.
+func (c *Client) FindWebhookByExternalID(ctx context.Context, orgID, externalID string) (*Webhook, error) {
+	var hooks []Webhook
+	if err := c.do(ctx, http.MethodGet, webhooksPath(orgID)+"?external_id="+url.QueryEscape(externalID), nil, &hooks); err != nil {
+		return nil, err
+	}
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+	return &hooks[0], nil
+}
+
+// CreateWebhook registers a webhook. The response includes the signing secret.
+func (c *Client) CreateWebhook(ctx context.Context, orgID string, in WebhookInput) (*Webhook, error) {
+	var out Webhook
+	if err := c.do(ctx, http.MethodPost, webhooksPath(orgID), in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateWebhook replaces a webhook.
+func (c *Client) UpdateWebhook(ctx context.Context, orgID, id string, in WebhookInput) (*Webhook, error) {
+	var out Webhook
+	if err := c.do(ctx, http.MethodPut, webhooksPath(orgID)+"/"+url.PathEscape(id), in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWebhook removes a webhook.
+func (c *Client) DeleteWebhook(ctx context.Context, orgID, id string) error {
+	return c.do(ctx, http.MethodDelete, webhooksPath(orgID)+"/"+url.PathEscape(id), nil, nil)
+}
+
+func webhooksPath(orgID string) string {
+	return "/api/orgs/" + url.PathEscape(orgID) + "/webhooks"
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/andres20980/aurea-orchestrator/pkg/aurea"
+)
+
+// Types returned by the API.
+type (
+	User             = aurea.User
+	Review           = aurea.Review
+	Comment          = aurea.Comment
+	CommentThread    = aurea.CommentThread
+	ThreadResolution = aurea.ThreadResolution
+	OrgPolicy        = aurea.OrgPolicy
+)
+
+// ReviewInput is the body of create and update requests.
+type ReviewInput struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// ListOptions selects a page of results. Zero values use the server defaults.
+type ListOptions struct {
+	Page     int
+	PageSize int
+	// Sort is "created_at" or "updated_at", oldest first, or newest first
+	// with a leading "-".
+	Sort string
+	// Fields keeps only the reviews whose custom field with each key has
+	// the given value.
+	Fields map[string]string
+}
+
+func (o ListOptions) query() string {
+	q := url.Values{}
+	if o.Page > 0 {
+		q.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PageSize > 0 {
+		q.Set("per_page", strconv.Itoa(o.PageSize))
+	}
+	if o.Sort != "" {
+		q.Set("sort", o.Sort)
+	}
+	for key, value := range o.Fields {
+		q.Set("field."+key, value)
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
+
+// ListReviews returns one page of the caller's organization's reviews.
+func (c *Client) ListReviews(ctx context.Context, opts ListOptions) ([]Review, error) {
+	var reviews []Review
+	err := c.do(ctx, http.MethodGet, "/api/reviews"+opts.query(), nil, &reviews)
+	return reviews, err
+}
+
+// CreateReview creates a review.
+func (c *Client) CreateReview(ctx context.Context, in ReviewInput) (*Review, error) {
+	var review Review
+	if err := c.do(ctx, http.MethodPost, "/api/reviews", in, &review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetReview returns a review by ID.
+func (c *Client) GetReview(ctx context.Context, id string) (*Review, error) {
+	var review Review
+	if err := c.do(ctx, http.MethodGet, reviewPath(id), nil, &review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// UpdateReview replaces a review's title and content.
+func (c *Client) UpdateReview(ctx context.Context, id string, in ReviewInput) (*Review, error) {
+	var review Review
+	if err := c.do(ctx, http.MethodPut, reviewPath(id), in, &review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ApproveReview approves a review.
+func (c *Client) ApproveReview(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, reviewPath(id)+"/approve", nil, nil)
+}
+
+// ReopenReview reopens an approved review, recording reason in the audit log.
+func (c *Client) ReopenReview(ctx context.Context, id, reason string) (*Review, error) {
+	var review Review
+	if err := c.do(ctx, http.MethodPost, reviewPath(id)+"/reopen", map[string]string{"reason": reason}, &review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// SetLabels replaces a review's labels.
+func (c *Client) SetLabels(ctx context.Context, id string, labels []string) error {
+	return c.do(ctx, http.MethodPut, reviewPath(id)+"/labels", map[string][]string{"labels": labels}, nil)
+}
+
+// SetPriority sets a review's priority (low, normal, high or critical).
+func (c *Client) SetPriority(ctx context.Context, id, priority string) error {
+	return c.do(ctx, http.MethodPut, reviewPath(id)+"/priority", map[string]string{"priority": priority}, nil)
+}
+
+// ListComments returns a review's comment threads.
+func (c *Client) ListComments(ctx context.Context, id string, unresolvedOnly bool) ([]CommentThread, error) {
+	path := reviewPath(id) + "/comments"
+	if unresolvedOnly {
+		path += "?unresolved=true"
+	}
+	var threads []CommentThread
+	err := c.do(ctx, http.MethodGet, path, nil, &threads)
+	return threads, err
+}
+
+// AddComment starts a comment thread on a review.
+func (c *Client) AddComment(ctx context.Context, id, body string) (*Comment, error) {
+	var comment Comment
+	if err := c.do(ctx, http.MethodPost, reviewPath(id)+"/comments", map[string]string{"body": body}, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// ResolveComment marks a comment thread as resolved.
+func (c *Client) ResolveComment(ctx context.Context, id, commentID string) (*ThreadResolution, error) {
+	var res ThreadResolution
+	if err := c.do(ctx, http.MethodPost, commentPath(id, commentID)+"/resolve", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UnresolveComment reopens a resolved comment thread.
+func (c *Client) UnresolveComment(ctx context.Context, id, commentID string) error {
+	return c.do(ctx, http.MethodDelete, commentPath(id, commentID)+"/resolve", nil, nil)
+}
+
+// GetOrgPolicy returns an organization's review policy.
+func (c *Client) GetOrgPolicy(ctx context.Context, orgID string) (*OrgPolicy, error) {
+	var policy OrgPolicy
+	if err := c.do(ctx, http.MethodGet, "/api/orgs/"+url.PathEscape(orgID)+"/policy", nil, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// UpdateOrgPolicy replaces an organization's review policy.
+func (c *Client) UpdateOrgPolicy(ctx context.Context, orgID string, policy OrgPolicy) (*OrgPolicy, error) {
+	var out OrgPolicy
+	if err := c.do(ctx, http.MethodPut, "/api/orgs/"+url.PathEscape(orgID)+"/policy", policy, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func reviewPath(id string) string {
+	return "/api/reviews/" + url.PathEscape(id)
+}
+
+func commentPath(id, commentID string) string {
+	return reviewPath(id) + "/comments/" + url.PathEscape(commentID)
+}
@@ -0,0 +1,90 @@
+package reqsign
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	const keyHash = "6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b"
+	secret := SigningSecret("server secret", keyHash)
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"title":"x"}`)
+
+	tests := []struct {
+		name    string
+		signAs  string
+		signAt  time.Time
+		path    string
+		nonce   string // replaces the signed nonce; "-" removes it
+		body    []byte
+		wantErr error
+	}{
+		{"valid", secret, now, "/api/reviews", "", body, nil},
+		{"signed with the stored hash", keyHash, now, "/api/reviews", "", body, ErrMismatch},
+		{"other server secret", SigningSecret("another secret", keyHash), now, "/api/reviews", "", body, ErrMismatch},
+		{"altered path", secret, now, "/api/orgs", "", body, ErrMismatch},
+		{"altered body", secret, now, "/api/reviews", "", []byte(`{"title":"y"}`), ErrMismatch},
+		{"too old", secret, now.Add(-Window - time.Second), "/api/reviews", "", body, ErrExpired},
+		{"altered nonce", secret, now, "/api/reviews", "0123", body, ErrMismatch},
+		{"no nonce", secret, now, "/api/reviews", "-", body, ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/reviews", nil)
+			SignRequest(req, "key-1", tt.signAs, body, tt.signAt)
+			req.URL.Path = tt.path
+			switch tt.nonce {
+			case "":
+			case "-":
+				req.Header.Del(NonceHeader)
+			default:
+				req.Header.Set(NonceHeader, tt.nonce)
+			}
+			if id, ok := KeyID(req); !ok || id != "key-1" {
+				t.Fatalf("KeyID = %q, %v", id, ok)
+			}
+			if err := Verify(req, tt.body, secret, now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReplays(t *testing.T) {
+	secret := SigningSecret("server secret", "hash")
+	now := time.Unix(1_700_000_000, 0)
+	replays := NewReplays()
+	signed := func(at time.Time) func() *http.Request {
+		req := httptest.NewRequest("POST", "/api/reviews", nil)
+		SignRequest(req, "key-1", secret, nil, at)
+		// Each attempt sends a copy of the same signed request.
+		return func() *http.Request { return req.Clone(req.Context()) }
+	}
+	// A client polling or retrying sends identical requests in the same
+	// second; each is signed with its own nonce.
+	first, second := signed(now), signed(now)
+
+	tests := []struct {
+		name    string
+		req     func() *http.Request
+		at      time.Time
+		wantErr error
+	}{
+		{"first use", first, now, nil},
+		{"replayed", first, now.Add(time.Minute), ErrReplayed},
+		{"identical request in the same second", second, now.Add(time.Minute), nil},
+		{"replayed at the end of the window", second, now.Add(Window), ErrReplayed},
+		{"replayed after the window", first, now.Add(Window + time.Second), ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := replays.Verify(tt.req(), nil, secret, tt.at); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}